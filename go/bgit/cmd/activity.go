@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/heatmap"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+type activityOptions struct {
+	author  string
+	since   string
+	until   string
+	day     string
+	noPager bool
+}
+
+func newActivityCmd(d *Deps) *cobra.Command {
+	opts := &activityOptions{}
+
+	activityCmd := &cobra.Command{
+		Use:   "activity",
+		Short: "Show a heatmap of commits per day",
+		Long: `Show the commits reachable from HEAD as a contribution heatmap, the way
+GitHub draws one: a column per week, a cell per day, shaded by how many
+commits were authored that day compared with the busiest one. Under it are
+the total, the busiest day and the longest run of days with commits.
+
+The map covers the year up to today; --since and --until (YYYY-MM-DD) pick
+another span, and only as many weeks as fit in the terminal are drawn.
+--author keeps the commits whose author name or email contains the text.
+
+On a terminal the map opens full screen: the arrow keys pick a day (left and
+right by week, up and down by day), n and N jump to the next and previous
+day with commits, and the commits of the picked day are listed under the
+map. When output is piped, or with --no-pager, the map is printed instead;
+--day prints the commits of one day.`,
+		Example: `  bgit activity
+  bgit activity --author alice@example.com --since 2026-01-01
+  bgit activity --day 2026-03-04`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runActivity(d, opts)
+		},
+	}
+
+	activityCmd.Flags().StringVar(&opts.author, "author", "", "Only count commits whose author name or email contains this")
+	activityCmd.Flags().StringVar(&opts.since, "since", "", "First day to count, as YYYY-MM-DD (default: a year ago)")
+	activityCmd.Flags().StringVar(&opts.until, "until", "", "Last day to count, as YYYY-MM-DD (default: today)")
+	activityCmd.Flags().StringVar(&opts.day, "day", "", "List the commits of this day, as YYYY-MM-DD, instead of the map")
+	activityCmd.Flags().BoolVar(&opts.noPager, "no-pager", false, "Print the map instead of opening it full screen")
+
+	return activityCmd
+}
+
+// activityDay is one day of 'bgit activity --output json'.
+type activityDay struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// activityRepo is what 'bgit activity' reads from the repository.
+type activityRepo interface {
+	Commits(filter gitService.CommitFilter) ([]*object.Commit, error)
+}
+
+func runActivity(d *Deps, opts *activityOptions) error {
+	from, to, err := activitySpan(opts)
+	if err != nil {
+		return err
+	}
+	client, err := openRepo[activityRepo](d)
+	if err != nil {
+		return err
+	}
+	commits, err := client.Commits(gitService.CommitFilter{
+		Since:  from,
+		Until:  to.AddDate(0, 0, 1).Add(-time.Nanosecond),
+		Author: opts.author,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+
+	view := ui.ActivityView{From: from, To: to, Counts: map[string]int{}, Author: opts.author}
+	byDay := map[string][]ui.DayCommit{}
+	for _, c := range commits {
+		when := c.Author.When.In(time.Local)
+		key := when.Format(ui.DayLayout)
+		view.Counts[key]++
+		byDay[key] = append(byDay[key], ui.DayCommit{
+			Hash:    c.Hash.String(),
+			Subject: strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0],
+			Author:  c.Author.Name,
+			When:    when,
+		})
+	}
+
+	if opts.day != "" {
+		day, _ := time.ParseInLocation(ui.DayLayout, opts.day, time.Local)
+		list := byDay[day.Format(ui.DayLayout)]
+		if d.Output.JSON() {
+			if list == nil {
+				list = []ui.DayCommit{} // encode as [] rather than null
+			}
+			return json.NewEncoder(d.IO.Out).Encode(list)
+		}
+		fmt.Fprint(d.IO.Out, ui.RenderDayCommits(day, list, ui.TerminalWidth(d.IO.Out)))
+		return nil
+	}
+
+	if d.Output.JSON() {
+		days := []activityDay{}
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			if n := view.Counts[day.Format(ui.DayLayout)]; n > 0 {
+				days = append(days, activityDay{Date: day.Format(ui.DayLayout), Count: n})
+			}
+		}
+		return json.NewEncoder(d.IO.Out).Encode(days)
+	}
+
+	term, ok := ui.TerminalFile(d.IO.Out)
+	if opts.noPager || !ok || !ui.IsInteractive(d.IO.In, d.IO.Out) {
+		fmt.Fprint(d.IO.Out, ui.RenderActivity(view, ui.TerminalWidth(d.IO.Out)))
+		return nil
+	}
+	d.flushOut()
+	return heatmap.Run(term, d.IO.In, view, byDay)
+}
+
+// activitySpan works out the days to count from the flags: by default the
+// year up to today, or with --day just that day.
+func activitySpan(opts *activityOptions) (from, to time.Time, err error) {
+	parse := func(flag, value string) (time.Time, error) {
+		t, err := time.ParseInLocation(ui.DayLayout, value, time.Local)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --%s %q (want YYYY-MM-DD)", flag, value)
+		}
+		return t, nil
+	}
+
+	if opts.day != "" {
+		day, err := parse("day", opts.day)
+		return day, day, err
+	}
+
+	y, m, dd := time.Now().Date()
+	to = time.Date(y, m, dd, 0, 0, 0, 0, time.Local)
+	if opts.until != "" {
+		if to, err = parse("until", opts.until); err != nil {
+			return from, to, err
+		}
+	}
+	from = to.AddDate(-1, 0, 1)
+	if opts.since != "" {
+		if from, err = parse("since", opts.since); err != nil {
+			return from, to, err
+		}
+	}
+	if from.After(to) {
+		return from, to, fmt.Errorf("--since %s is after --until %s", from.Format(ui.DayLayout), to.Format(ui.DayLayout))
+	}
+	return from, to, nil
+}
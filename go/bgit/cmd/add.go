@@ -1,54 +1,341 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"io"
+	"sort"
+	"strings"
 
-	internal "github.com/endalk200/bgit/internal/services/git"
+	"github.com/charmbracelet/huh"
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/patchmode"
 	"github.com/spf13/cobra"
 )
 
-var addCmd = &cobra.Command{
-	Use:   "add [files...]",
-	Short: "Stage file contents into the index",
-	Long: `Stage file contents into the index (staging area) similar to 'git add'.
+func newAddCmd(d *Deps) *cobra.Command {
+	addCmd := &cobra.Command{
+		Use:   "add [files...]",
+		Short: "Stage file contents into the index",
+		Long: `Stage file contents into the index (staging area) similar to 'git add'.
 You can provide explicit file paths or use --all to stage all tracked modifications
-and new untracked files. Patterns (globs) within shell expansion also work.`,
-	Args: cobra.ArbitraryArgs,
-	Run: func(cmd *cobra.Command, args []string) {
-		cwd, err := os.Getwd()
+and new untracked files.
+
+A directory stages every changed file under it. Quoted glob patterns are
+expanded by bgit itself, the way git expands pathspecs: a pattern matches
+the whole path, with '*' matching any characters, slashes included, and '?'
+any one, so '*.go' matches Go files at any depth and 'internal/*.go' every
+one under internal. Ignored files are never staged this way.
+
+  bgit add internal/ui
+  bgit add 'internal/*.go'
+  bgit add '*.md'
+
+Run without paths or --all on a terminal, add lists the changed files,
+grouped into modified, deleted, intent-to-add and untracked ones, to pick
+those to stage: x or space picks a file, / filters the list by name, and
+enter goes on to the next group, staging the files picked after the last.
+
+Every staged file is listed. Paths that cannot be staged (not found, ignored,
+or with nothing new to stage) are reported without stopping the rest; the
+command only fails when nothing at all could be staged.
+
+--patch (-p) stages part of the changes, as git add -p does. Each hunk of
+the unstaged diff, of the paths given or of every file, is shown in turn to
+stage (y) or skip (n); s splits a hunk into smaller ones where unchanged
+lines separate its changes, and a and d decide the rest of the file at
+once. The hunks chosen are staged when every one is decided, or when q
+ends it early; ctrl+c stages nothing. A change of mode is staged with the
+file's first staged hunk. Binary files and files whose mode alone changed
+have no hunks, and are listed to stage whole.`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all, _ := cmd.Flags().GetBool("all")
+			intent, _ := cmd.Flags().GetBool("intent-to-add")
+			if patch, _ := cmd.Flags().GetBool("patch"); patch {
+				return runAddPatch(d, args)
+			}
+			if !all && !intent && len(args) == 0 && ui.IsInteractive(d.IO.In, d.IO.Out) {
+				d.flushOut()
+				term, _ := ui.TerminalFile(d.IO.Out)
+				return runAddPicker(d, term)
+			}
+			return runAdd(d, args, all, intent)
+		},
+	}
+
+	addCmd.Flags().BoolP("all", "A", false, "Stage all tracked and untracked changes")
+	addCmd.Flags().BoolP("intent-to-add", "N", false, "Record new files without their content, so they show in diffs")
+	addCmd.Flags().BoolP("patch", "p", false, "Choose the hunks to stage one by one")
+	addCmd.MarkFlagsMutuallyExclusive("all", "intent-to-add", "patch")
+
+	return addCmd
+}
+
+// addRepo is what 'bgit add' needs from the repository, the picker and
+// --patch included.
+type addRepo interface {
+	indexRepo
+	AddAllFiles() ([]string, error)
+	AddFiles(files []string) (gitService.AddResult, error)
+	IntentToAdd(files []string) (gitService.AddResult, error)
+	DiffStats(staged bool, paths []string) ([]gitService.FileStat, error)
+	DeletedFiles() ([]string, error)
+	IntentToAddFiles() ([]string, error)
+	UntrackedFiles() ([]string, error)
+	Diff(staged bool, paths []string) (string, error)
+	StagePatch(patch string) error
+}
+
+func runAdd(d *Deps, args []string, all, intent bool) error {
+	if !all && len(args) == 0 {
+		return errors.New("nothing specified, nothing staged; name the files to stage or use --all")
+	}
+
+	client, err := openRepo[addRepo](d)
+	if err != nil {
+		return err
+	}
+
+	done, err := protectIndex(d, client)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	if all {
+		stagedFiles, err := client.AddAllFiles()
 		if err != nil {
-			panic(fmt.Errorf("cannot determine working directory: %w", err))
+			return err
 		}
+		printPaths(d, "Staged", stagedFiles)
+		return nil
+	}
 
-		client, err := internal.NewGitClient(cwd)
-		if err != nil {
-			panic(err.Error())
+	add, verb := client.AddFiles, "Staged"
+	if intent {
+		add, verb = client.IntentToAdd, "Marked intent to add for"
+	}
+	res, err := add(args)
+	if err != nil {
+		return err
+	}
+	printPaths(d, verb, res.Staged)
+	if len(res.Skipped) > 0 {
+		d.flushOut()
+		items := make([]string, 0, len(res.Skipped))
+		for _, s := range res.Skipped {
+			items = append(items, fmt.Sprintf("%s (%s)", s.Path, s.Reason))
+		}
+		fmt.Fprint(d.IO.ErrOut, ui.RenderSkippedPaths(items, ui.TerminalWidth(d.IO.ErrOut)))
+	}
+	if len(res.Staged) == 0 {
+		return errors.New("nothing was staged")
+	}
+	return nil
+}
+
+// addGroup is one status's files in the add picker.
+type addGroup struct {
+	title   string
+	options []huh.Option[string]
+	picked  []string
+}
+
+// runAddPicker lists the files with unstaged changes on term, grouped by
+// status, and stages the ones picked.
+func runAddPicker(d *Deps, term io.Writer) error {
+	client, err := openRepo[addRepo](d)
+	if err != nil {
+		return err
+	}
+	stats, err := client.DiffStats(false, nil)
+	if err != nil {
+		return err
+	}
+	deleted, err := client.DeletedFiles()
+	if err != nil {
+		return err
+	}
+	intents, err := client.IntentToAddFiles()
+	if err != nil {
+		return err
+	}
+	untracked, err := client.UntrackedFiles()
+	if err != nil {
+		return err
+	}
+
+	modifiedGroup := &addGroup{title: "Modified"}
+	deletedGroup := &addGroup{title: "Deleted"}
+	intentGroup := &addGroup{title: "Intent to add"}
+	untrackedGroup := &addGroup{title: "Untracked"}
+	groupOf := map[string]*addGroup{}
+	for _, p := range deleted {
+		groupOf[p] = deletedGroup
+	}
+	for _, p := range intents {
+		groupOf[p] = intentGroup
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Path < stats[j].Path })
+	width := 0
+	for _, st := range stats {
+		width = max(width, ui.StringWidth(st.Path))
+	}
+	for _, st := range stats {
+		g := groupOf[st.Path]
+		if g == nil {
+			g = modifiedGroup
+		}
+		change := fmt.Sprintf("+%d -%d", st.Insertions, st.Deletions)
+		if st.Binary {
+			change = "binary"
 		}
+		label := st.Path + strings.Repeat(" ", width-ui.StringWidth(st.Path)) + "  " + change
+		g.options = append(g.options, huh.NewOption(label, st.Path))
+	}
+	sort.Strings(untracked)
+	for _, p := range untracked {
+		untrackedGroup.options = append(untrackedGroup.options, huh.NewOption(p, p))
+	}
 
-		all, _ := cmd.Flags().GetBool("all")
+	var groups []*addGroup
+	var fields []huh.Field
+	for _, g := range []*addGroup{modifiedGroup, deletedGroup, intentGroup, untrackedGroup} {
+		if len(g.options) == 0 {
+			continue
+		}
+		groups = append(groups, g)
+		fields = append(fields, huh.NewMultiSelect[string]().
+			Title(fmt.Sprintf("%s (%d)", g.title, len(g.options))).
+			Options(g.options...).
+			Filterable(true).
+			Height(min(len(g.options)+2, 12)).
+			Value(&g.picked))
+	}
+	if len(fields) == 0 {
+		d.infoln("No changes to stage.")
+		return nil
+	}
 
-		if all {
-			_, err := client.AddAllFiles()
-			if err != nil {
-				panic(err.Error())
-			}
-		} else {
-			var targets []string
-			targets = append(targets, args...)
-			stagedFiles, err := client.AddFiles(targets)
-			if err != nil {
-				panic(err.Error())
+	err = huh.NewForm(huh.NewGroup(fields...).Title("Stage which files?")).
+		WithInput(d.IO.In).WithOutput(term).Run()
+	if errors.Is(err, huh.ErrUserAborted) {
+		d.infoln("Nothing staged.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var picked []string
+	for _, g := range groups {
+		picked = append(picked, g.picked...)
+	}
+	if len(picked) == 0 {
+		d.infoln("Nothing staged.")
+		return nil
+	}
+	return runAdd(d, picked, false, false)
+}
+
+// errAddPatchNeedsTerminal is returned when the hunk stager cannot be drawn.
+var errAddPatchNeedsTerminal = errors.New("add -p needs an interactive terminal")
+
+// runAddPatch lets the user pick the hunks of the unstaged changes to
+// stage, in paths or everywhere, and stages them.
+func runAddPatch(d *Deps, paths []string) error {
+	client, err := openRepo[addRepo](d)
+	if err != nil {
+		return err
+	}
+	diff, err := client.Diff(false, paths)
+	if err != nil {
+		return err
+	}
+	files, err := patchmode.Parse(diff)
+	if err != nil {
+		return err
+	}
+	var whole []string
+	hunks := 0
+	for _, f := range files {
+		if len(f.Hunks) == 0 {
+			whole = append(whole, f.Path)
+		}
+		hunks += len(f.Hunks)
+	}
+	defer func() {
+		if len(whole) > 0 {
+			d.infoln("Binary files and changes of mode have no hunks; stage them whole with 'bgit add':")
+			for _, p := range whole {
+				d.infof("  %s %s\n", ui.Bullet(), p)
 			}
-			fmt.Printf("Staged %d files\n", len(stagedFiles))
-			for _, file := range stagedFiles {
-				fmt.Printf("  • %s\n", file)
+		}
+	}()
+	if hunks == 0 {
+		d.infoln("No unstaged changes to pick from.")
+		return nil
+	}
+
+	term, ok := ui.TerminalFile(d.IO.Out)
+	if !ok {
+		return errAddPatchNeedsTerminal
+	}
+	d.flushOut()
+	outcome, err := patchmode.Run(term, d.IO.In, files)
+	if err != nil {
+		return err
+	}
+	if outcome == patchmode.Aborted {
+		d.infoln("Nothing staged.")
+		return nil
+	}
+
+	var patch strings.Builder
+	var staged []string
+	chosen := 0
+	for _, f := range files {
+		p := f.Patch()
+		if p == "" {
+			continue
+		}
+		patch.WriteString(p)
+		staged = append(staged, f.Path)
+		for _, h := range f.Hunks {
+			if h.Choice == patchmode.Stage {
+				chosen++
 			}
 		}
-	},
+	}
+	if len(staged) == 0 {
+		d.infoln("Nothing staged.")
+		return nil
+	}
+
+	done, err := protectIndex(d, client)
+	if err != nil {
+		return err
+	}
+	defer done()
+	if err := client.StagePatch(patch.String()); err != nil {
+		return fmt.Errorf("failed to stage the hunks: %w", err)
+	}
+	d.infof("Staged %s of %s\n", plural(chosen, "hunk"), plural(len(staged), "file"))
+	for _, file := range staged {
+		d.infof("  %s %s\n", ui.Bullet(), file)
+	}
+	return nil
 }
 
-func init() {
-	rootCmd.AddCommand(addCmd)
-	addCmd.Flags().BoolP("all", "A", false, "Stage all tracked and untracked changes")
+// printPaths lists the files an add staged (or marked, with -N).
+func printPaths(d *Deps, verb string, files []string) {
+	if len(files) == 0 {
+		return
+	}
+	d.infof("%s %s\n", verb, plural(len(files), "file"))
+	for _, file := range files {
+		d.infof("  %s %s\n", ui.Bullet(), file)
+	}
 }
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+type baseOptions struct {
+	all     bool
+	octopus bool
+}
+
+// baseJSON is one merge base in --output json.
+type baseJSON struct {
+	Commit  string    `json:"commit"`
+	Subject string    `json:"subject"`
+	Date    time.Time `json:"date"`
+}
+
+func newBaseCmd(d *Deps) *cobra.Command {
+	opts := &baseOptions{}
+
+	baseCmd := &cobra.Command{
+		Use:   "base <commit> [<commit>...]",
+		Short: "Print the common ancestor of two branches or commits",
+		Long: `Print the merge base of two commits, branches or any revisions git
+understands: their nearest common ancestor, the commit a merge of them
+would start from and where the history of one branched off the other. With
+one commit the other is HEAD, so 'bgit base main' prints where the current
+branch left main.
+
+Given more commits, the first is set against the others taken together, as
+if they had been merged; --octopus instead prints the ancestor all of them
+share, the base of an octopus merge of them.
+
+Criss-cross merges can leave several commits equally near; the nearest is
+printed unless --all asks for every one. Each is printed as its full hash,
+ready for other commands:
+
+  git diff $(bgit base main)
+
+Commits with no history in common print nothing and fail.`,
+		Example: `  bgit base main
+  bgit base origin/main feature/login
+  bgit base --octopus main release/1.4 hotfix`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				args = []string{"HEAD", args[0]}
+			}
+			return runBase(d, args, opts)
+		},
+	}
+
+	baseCmd.Flags().BoolVarP(&opts.all, "all", "a", false, "Print every nearest common ancestor, not just one")
+	baseCmd.Flags().BoolVar(&opts.octopus, "octopus", false, "Print the ancestor all the commits share")
+
+	return baseCmd
+}
+
+// baseRepo is what 'bgit base' needs from the repository.
+type baseRepo interface {
+	ResolveCommit(rev string) (*object.Commit, error)
+	OctopusMergeBases(commits ...plumbing.Hash) ([]plumbing.Hash, error)
+	MergeBases(one plumbing.Hash, others ...plumbing.Hash) ([]plumbing.Hash, error)
+}
+
+func runBase(d *Deps, revs []string, opts *baseOptions) error {
+	client, err := openRepo[baseRepo](d)
+	if err != nil {
+		return err
+	}
+	hashes := make([]plumbing.Hash, 0, len(revs))
+	for _, rev := range revs {
+		c, err := client.ResolveCommit(rev)
+		if err != nil {
+			return err
+		}
+		hashes = append(hashes, c.Hash)
+	}
+
+	var bases []plumbing.Hash
+	if opts.octopus {
+		bases, err = client.OctopusMergeBases(hashes...)
+	} else {
+		bases, err = client.MergeBases(hashes[0], hashes[1:]...)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find the merge base: %w", err)
+	}
+	if len(bases) == 0 {
+		return errors.New(noBaseMessage(revs))
+	}
+	if !opts.all {
+		bases = bases[:1]
+	}
+
+	if d.Output.JSON() {
+		out := make([]baseJSON, 0, len(bases))
+		for _, h := range bases {
+			c, err := client.ResolveCommit(h.String())
+			if err != nil {
+				return err
+			}
+			out = append(out, baseJSON{
+				Commit:  h.String(),
+				Subject: strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0],
+				Date:    c.Committer.When,
+			})
+		}
+		return json.NewEncoder(d.IO.Out).Encode(out)
+	}
+	for _, h := range bases {
+		fmt.Fprintln(d.IO.Out, h)
+	}
+	return nil
+}
+
+// noBaseMessage says that revs share no history.
+func noBaseMessage(revs []string) string {
+	if len(revs) == 2 {
+		return fmt.Sprintf("%s and %s have no common ancestor", revs[0], revs[1])
+	}
+	return fmt.Sprintf("%s have no common ancestor", strings.Join(revs, ", "))
+}
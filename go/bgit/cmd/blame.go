@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/blameview"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+type blameOptions struct {
+	rev     string
+	noPager bool
+}
+
+func newBlameCmd(d *Deps) *cobra.Command {
+	opts := &blameOptions{}
+
+	blameCmd := &cobra.Command{
+		Use:   "blame <file>",
+		Short: "Show who last changed each line of a file",
+		Long: `Show every line of a file as it is in HEAD (or --rev) with the commit that
+last changed it: its short hash, author and how long ago it was made, given
+once for each run of lines the commit changed. A bar down the left is
+coloured by the age of the line, from red for this week through orange,
+yellow and teal to blue for more than a year ago, so the recent changes
+stand out.
+
+On a terminal the blame opens full screen: j and k (or the arrow keys) move
+from line to line, n and N jump to the next and previous run of lines from
+another commit, and the status bar names the commit of the current line.
+enter opens that commit with every file it touched; b blames the file as
+it was before that commit, to see who wrote the line before it, and esc
+goes back. When output is piped, or with --no-pager, it is printed instead.`,
+		Example: `  bgit blame internal/ui/status.go
+  bgit blame --rev v1.2.0 README.md`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBlame(d, args[0], opts)
+		},
+	}
+
+	blameCmd.Flags().StringVar(&opts.rev, "rev", "HEAD", "Blame the file as it is in this commit")
+	blameCmd.Flags().BoolVar(&opts.noPager, "no-pager", false, "Print the blame instead of opening it full screen")
+
+	return blameCmd
+}
+
+// blameRepo is what 'bgit blame' needs from the repository.
+type blameRepo interface {
+	patchRepo
+	ResolveCommit(rev string) (*object.Commit, error)
+	Blame(c *object.Commit, file string) ([]gitService.BlameLine, error)
+}
+
+func runBlame(d *Deps, path string, opts *blameOptions) error {
+	client, err := openRepo[blameRepo](d)
+	if err != nil {
+		return err
+	}
+	commit, err := client.ResolveCommit(opts.rev)
+	if err != nil {
+		return err
+	}
+
+	path = filepath.ToSlash(filepath.Clean(path))
+	view, err := blameView(client, commit, path, opts.rev)
+	if err != nil {
+		return err
+	}
+
+	if d.Output.JSON() {
+		if view.Lines == nil {
+			view.Lines = []ui.BlameLine{} // encode as [] rather than null
+		}
+		return json.NewEncoder(d.IO.Out).Encode(view.Lines)
+	}
+	if len(view.Lines) == 0 {
+		fmt.Fprintf(d.IO.Out, "%s is empty.\n", path)
+		return nil
+	}
+
+	width := ui.TerminalWidth(d.IO.Out)
+	term, ok := ui.TerminalFile(d.IO.Out)
+	if opts.noPager || !ok || !ui.IsInteractive(d.IO.In, d.IO.Out) {
+		fmt.Fprint(d.IO.Out, strings.Join(ui.RenderBlame(view, width), ""))
+		return nil
+	}
+
+	d.flushOut()
+	return blameview.Run(term, d.IO.In, view, blameview.Options{
+		Open: func(hash string) (string, error) {
+			c, err := client.ResolveCommit(hash)
+			if err != nil {
+				return "", err
+			}
+			return renderFullCommit(client, c, width)
+		},
+		Before: func(hash string) (ui.BlameView, error) {
+			c, err := client.ResolveCommit(hash)
+			if err != nil {
+				return ui.BlameView{}, err
+			}
+			if c.NumParents() == 0 {
+				return ui.BlameView{}, fmt.Errorf("%s is the first commit; nothing came before it", hash[:7])
+			}
+			parent, err := c.Parent(0)
+			if err != nil {
+				return ui.BlameView{}, err
+			}
+			return blameView(client, parent, path, hash[:7]+"^")
+		},
+	})
+}
+
+// blameView blames path as it is in c, which was named rev.
+func blameView(client blameRepo, c *object.Commit, path, rev string) (ui.BlameView, error) {
+	lines, err := client.Blame(c, path)
+	if err != nil {
+		return ui.BlameView{}, err
+	}
+	return ui.BlameView{Path: path, Rev: rev, Lines: uiBlameLines(lines), Now: time.Now()}, nil
+}
+
+// uiBlameLines turns the lines of a blame into those of a blame view.
+func uiBlameLines(lines []gitService.BlameLine) []ui.BlameLine {
+	out := make([]ui.BlameLine, 0, len(lines))
+	for _, l := range lines {
+		out = append(out, ui.BlameLine{
+			Hash:    l.Commit.String(),
+			Author:  l.Author,
+			Email:   l.Email,
+			When:    l.When,
+			Subject: l.Subject,
+			Text:    l.Text,
+		})
+	}
+	return out
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/spf13/cobra"
+)
+
+func newBranchCmd(d *Deps) *cobra.Command {
+	var force bool
+
+	branchCmd := &cobra.Command{
+		Use:   "branch",
+		Short: "List, create, delete and rename local branches",
+		Long: `Manage local branches. Without a subcommand the branches are listed, as
+with 'branch list'.
+
+'branches --compare' sets every branch against the default branch instead.`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBranchList(d)
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List local branches with the branch each tracks",
+		Long: `List the local branches, the current one marked with *, each with its
+last commit and the remote branch it tracks: how many commits it is ahead
+and behind that, as of the last fetch, or "gone" when it no longer exists.`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBranchList(d)
+		},
+	}
+
+	createCmd := &cobra.Command{
+		Use:     "create <name> [<start>]",
+		Aliases: []string{"new"},
+		Short:   "Create a branch at HEAD or at another branch, tag or commit",
+		Long: `Create a branch at HEAD, or at <start> when given: a branch, tag, commit
+or any revision git understands, such as origin/main or HEAD~2. The current
+branch stays checked out.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start := ""
+			if len(args) == 2 {
+				start = args[1]
+			}
+			return runBranchCreate(d, args[0], start)
+		},
+	}
+
+	deleteCmd := &cobra.Command{
+		Use:     "delete <name>...",
+		Aliases: []string{"rm"},
+		Short:   "Delete branches whose work is merged",
+		Long: `Delete local branches. A branch is only deleted when all its commits are
+on the branch it tracks, or on HEAD when it tracks none, so no work is lost;
+--force deletes it anyway. The current branch cannot be deleted.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBranchDelete(d, args, force)
+		},
+	}
+	deleteCmd.Flags().BoolVarP(&force, "force", "f", false, "Delete branches even if their commits are not merged")
+
+	renameCmd := &cobra.Command{
+		Use:     "rename [<old>] <new>",
+		Aliases: []string{"mv"},
+		Short:   "Rename a branch, by default the current one",
+		Long: `Rename a local branch, by default the current one. What it tracks and the
+issue it was started for come along.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBranchRename(d, args)
+		},
+	}
+
+	branchCmd.AddCommand(listCmd, createCmd, deleteCmd, renameCmd)
+	return branchCmd
+}
+
+// branchRepo is what the 'bgit branch' commands do to the repository.
+type branchRepo interface {
+	Branches() ([]gitService.Branch, error)
+	CreateBranch(name, start string) (plumbing.Hash, error)
+	DeleteBranch(name string, force bool) error
+	RenameBranch(oldName, newName string) error
+}
+
+func runBranchList(d *Deps) error {
+	client, err := openRepo[branchRepo](d)
+	if err != nil {
+		return err
+	}
+	branches, err := client.Branches()
+	if err != nil {
+		return fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	rows := make([]ui.BranchListRow, 0, len(branches))
+	for _, b := range branches {
+		rows = append(rows, ui.BranchListRow{
+			Name:     b.Name,
+			Current:  b.Current,
+			Hash:     b.Tip.String(),
+			Subject:  b.Subject,
+			Upstream: b.Upstream,
+			Ahead:    b.Ahead,
+			Behind:   b.Behind,
+			Gone:     b.UpstreamGone,
+		})
+	}
+	if d.Output.JSON() {
+		return json.NewEncoder(d.IO.Out).Encode(rows)
+	}
+	fmt.Fprint(d.IO.Out, ui.RenderBranchList(rows, ui.TerminalWidth(d.IO.Out)))
+	return nil
+}
+
+func runBranchCreate(d *Deps, name, start string) error {
+	client, err := openRepo[branchRepo](d)
+	if err != nil {
+		return err
+	}
+	hash, err := client.CreateBranch(name, start)
+	if err != nil {
+		return err
+	}
+	d.infof("%sCreated branch %s at %s\n", ui.Icon("✓"), name, hash.String()[:7])
+	return nil
+}
+
+func runBranchDelete(d *Deps, names []string, force bool) error {
+	client, err := openRepo[branchRepo](d)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, name := range names {
+		err := client.DeleteBranch(name, force)
+		var notMerged gitService.ErrBranchNotMerged
+		switch {
+		case errors.As(err, &notMerged):
+			fmt.Fprintf(d.IO.ErrOut, "error: %s; use --force to delete it anyway\n", err)
+			failed++
+		case err != nil:
+			fmt.Fprintf(d.IO.ErrOut, "error: %s\n", err)
+			failed++
+		default:
+			d.infof("%sDeleted branch %s\n", ui.Icon("✓"), name)
+		}
+	}
+	switch {
+	case failed == 1:
+		return errors.New("1 branch not deleted")
+	case failed > 1:
+		return fmt.Errorf("%d branches not deleted", failed)
+	}
+	return nil
+}
+
+func runBranchRename(d *Deps, args []string) error {
+	client, err := openRepo[branchRepo](d)
+	if err != nil {
+		return err
+	}
+	oldName, newName := "", args[len(args)-1]
+	if len(args) == 2 {
+		oldName = args[0]
+	} else {
+		branches, err := client.Branches()
+		if err != nil {
+			return fmt.Errorf("failed to list branches: %w", err)
+		}
+		for _, b := range branches {
+			if b.Current {
+				oldName = b.Name
+			}
+		}
+		if oldName == "" {
+			return errors.New("not on a branch; name the branch to rename")
+		}
+	}
+	if err := client.RenameBranch(oldName, newName); err != nil {
+		return err
+	}
+	d.infof("%sRenamed branch %s to %s\n", ui.Icon("✓"), oldName, newName)
+	return nil
+}
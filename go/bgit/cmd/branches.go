@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	forgeService "github.com/endalk200/bgit/internal/services/forge"
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+type branchesOptions struct {
+	compare bool
+	base    string
+	noPR    bool
+}
+
+func newBranchesCmd(d *Deps) *cobra.Command {
+	opts := &branchesOptions{}
+
+	branchesCmd := &cobra.Command{
+		Use:   "branches",
+		Short: "List local branches, or compare them with the default branch",
+		Long: `List local branches, marking the current one.
+
+With --compare every branch is set against the default branch (origin's
+HEAD, else main or master; --base picks another): how many commits it is
+ahead and behind, how long ago it was last committed to and by whom, and the
+open pull request for it on GitHub. Branches already merged and branches
+with unmerged work but no commits in 90 days are flagged, to help clear out
+stale work.
+
+Pull requests are looked up with GITHUB_TOKEN or GH_TOKEN when set (public
+repositories work without one); --no-pr skips the lookup.`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBranches(cmd.Context(), d, opts)
+		},
+	}
+
+	branchesCmd.Flags().BoolVar(&opts.compare, "compare", false, "Compare every branch with the default branch")
+	branchesCmd.Flags().StringVar(&opts.base, "base", "", "Branch to compare with (default: origin's HEAD, main or master)")
+	branchesCmd.Flags().BoolVar(&opts.noPR, "no-pr", false, "Do not look up open pull requests")
+
+	return branchesCmd
+}
+
+// branchesRepo is what 'bgit branches' reads from the repository.
+type branchesRepo interface {
+	ForgeRepo
+	LocalBranches() ([]string, error)
+	CurrentBranch() (string, error)
+	DefaultBranch() (string, error)
+	CompareBranches(base string) ([]gitService.BranchInfo, error)
+}
+
+func runBranches(ctx context.Context, d *Deps, opts *branchesOptions) error {
+	client, err := openRepo[branchesRepo](d)
+	if err != nil {
+		return err
+	}
+
+	if !opts.compare {
+		names, err := client.LocalBranches()
+		if err != nil {
+			return fmt.Errorf("failed to list branches: %w", err)
+		}
+		if d.Output.JSON() {
+			if names == nil {
+				names = []string{}
+			}
+			return json.NewEncoder(d.IO.Out).Encode(names)
+		}
+		current, _ := client.CurrentBranch()
+		for _, name := range names {
+			mark := "  "
+			if name == current {
+				mark = "* "
+			}
+			fmt.Fprintln(d.IO.Out, mark+name)
+		}
+		return nil
+	}
+
+	base := opts.base
+	if base == "" {
+		if base, err = client.DefaultBranch(); err != nil {
+			return err
+		}
+	}
+	infos, err := client.CompareBranches(base)
+	if err != nil {
+		return fmt.Errorf("failed to compare branches: %w", err)
+	}
+
+	table := ui.BranchTable{Base: base, Now: time.Now(), ShowPR: !opts.noPR}
+	for _, b := range infos {
+		table.Rows = append(table.Rows, ui.BranchRow{
+			Name:    b.Name,
+			Current: b.Current,
+			Ahead:   b.Ahead,
+			Behind:  b.Behind,
+			When:    b.TipWhen,
+			Author:  b.TipOwner,
+		})
+	}
+
+	if !opts.noPR {
+		prs, err := openPullRequests(ctx, d, client)
+		if err != nil {
+			table.ShowPR = false
+			table.PRNote = "Pull requests not shown: " + err.Error()
+		}
+		for i, row := range table.Rows {
+			if pr, ok := prs[row.Name]; ok {
+				table.Rows[i].PR = fmt.Sprintf("#%d", pr.Number)
+				if pr.Draft {
+					table.Rows[i].PR += " (draft)"
+				}
+			}
+		}
+	}
+
+	if d.Output.JSON() {
+		if table.Rows == nil {
+			table.Rows = []ui.BranchRow{}
+		}
+		return json.NewEncoder(d.IO.Out).Encode(table.Rows)
+	}
+	fmt.Fprint(d.IO.Out, ui.RenderBranchTable(table, ui.TerminalWidth(d.IO.Out)))
+	return nil
+}
+
+// openPullRequests maps branch names to their open pull request.
+func openPullRequests(ctx context.Context, d *Deps, client ForgeRepo) (map[string]forgeService.PullRequest, error) {
+	forge, err := openForge(d, client)
+	if err != nil {
+		return nil, err
+	}
+	prs, err := forge.OpenPullRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byBranch := make(map[string]forgeService.PullRequest, len(prs))
+	for _, pr := range prs {
+		byBranch[pr.Head] = pr
+	}
+	return byBranch, nil
+}
+
+// openForge connects to the forge behind origin, explaining in plain words
+// why it cannot.
+func openForge(d *Deps, client ForgeRepo) (Forge, error) {
+	forge, err := d.OpenForge(client)
+	switch {
+	case errors.Is(err, forgeService.ErrUnsupportedForge):
+		return nil, errors.New("origin is not hosted on GitHub")
+	case errors.Is(err, errOffline):
+		return nil, errors.New("offline")
+	case errors.As(err, new(gitService.ErrNoRemote)):
+		return nil, errors.New("the repository has no origin remote")
+	case err != nil:
+		return nil, err
+	}
+	return forge, nil
+}
@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newChangelistCmd(d *Deps) *cobra.Command {
+	changelistCmd := &cobra.Command{
+		Use:     "changelist",
+		Aliases: []string{"cl"},
+		Short:   "Group changed files into named changelists",
+		Long: `Group changed files into named changelists, the way Perforce and JetBrains
+IDEs do, so that unrelated work in the same working tree can be committed
+separately. 'bgit status' lists the files of each changelist under its name,
+and 'bgit commit --changelist <name>' stages and commits just those files.
+
+A file is in at most one changelist; adding it to another moves it. The
+changelists are kept in the repository's git directory and are not shared.
+
+Without a subcommand, every changelist is listed with its files.`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runChangelistList(d)
+		},
+	}
+
+	addCmd := &cobra.Command{
+		Use:   "add <name> <path>...",
+		Short: "Put files in a changelist, creating it if needed",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runChangelistAdd(d, args[0], args[1:])
+		},
+	}
+
+	removeCmd := &cobra.Command{
+		Use:   "remove <path>...",
+		Short: "Take files out of their changelist",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runChangelistRemove(d, args)
+		},
+	}
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a changelist, leaving its files as they are",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runChangelistDelete(d, args[0])
+		},
+	}
+
+	changelistCmd.AddCommand(addCmd, removeCmd, deleteCmd)
+	return changelistCmd
+}
+
+// changelistStateFile lives in the git directory, next to git's own state.
+const changelistStateFile = "bgit-changelists.json"
+
+// changelists are the named groups of files of a repository, in the order
+// they were created.
+type changelists struct {
+	Lists []changelist `json:"changelists"`
+}
+
+type changelist struct {
+	Name  string   `json:"name"`
+	Files []string `json:"files"`
+}
+
+func loadChangelists(client gitDirRepo) (*changelists, string, error) {
+	path, err := client.GitPath(changelistStateFile)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &changelists{}, path, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	var cls changelists
+	if err := json.Unmarshal(data, &cls); err != nil {
+		return nil, "", fmt.Errorf("unreadable changelists in %s (fix or remove it): %w", path, err)
+	}
+	return &cls, path, nil
+}
+
+func saveChangelists(path string, cls *changelists) error {
+	data, err := json.MarshalIndent(cls, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// find returns the changelist called name, or nil.
+func (c *changelists) find(name string) *changelist {
+	for i := range c.Lists {
+		if c.Lists[i].Name == name {
+			return &c.Lists[i]
+		}
+	}
+	return nil
+}
+
+// add puts files in the changelist called name, creating it, and takes
+// them out of any other.
+func (c *changelists) add(name string, files []string) {
+	c.remove(files)
+	cl := c.find(name)
+	if cl == nil {
+		c.Lists = append(c.Lists, changelist{Name: name})
+		cl = &c.Lists[len(c.Lists)-1]
+	}
+	for _, f := range files {
+		if !slices.Contains(cl.Files, f) {
+			cl.Files = append(cl.Files, f)
+		}
+	}
+	slices.Sort(cl.Files)
+}
+
+// remove takes files out of whichever changelist has them and reports how
+// many it found.
+func (c *changelists) remove(files []string) int {
+	n := 0
+	for i := range c.Lists {
+		before := len(c.Lists[i].Files)
+		c.Lists[i].Files = slices.DeleteFunc(c.Lists[i].Files, func(f string) bool {
+			return slices.Contains(files, f)
+		})
+		n += before - len(c.Lists[i].Files)
+	}
+	return n
+}
+
+// view lists the changelists for display, each file with how it has
+// changed according to status. Unchanged files are left out unless all is
+// set.
+func (c *changelists) view(status ui.StatusView, all bool) []ui.Changelist {
+	var out []ui.Changelist
+	for _, cl := range c.Lists {
+		v := ui.Changelist{Name: cl.Name, Files: []ui.ChangelistFile{}}
+		for _, f := range cl.Files {
+			state := fileState(status, f)
+			if state == "" && !all {
+				continue
+			}
+			if state == "" {
+				state = "unchanged"
+			}
+			v.Files = append(v.Files, ui.ChangelistFile{Path: f, State: state})
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// fileState names how path has changed, or "" when it has not.
+func fileState(v ui.StatusView, path string) string {
+	for _, s := range []struct {
+		list  []string
+		state string
+	}{
+		{v.Untracked, "untracked"},
+		{v.Intent, "intent to add"},
+		{v.Added, "added"},
+		{v.Deleted, "deleted"},
+		{v.Renamed, "renamed"},
+		{v.Staged, "staged"},
+		{v.Modified, "modified"},
+	} {
+		if slices.Contains(s.list, path) {
+			return s.state
+		}
+	}
+	return ""
+}
+
+// stagingRepo is what staging a changelist needs from the repository.
+type stagingRepo interface {
+	gitDirRepo
+	StagedFiles() ([]string, error)
+	AddFiles(files []string) (gitService.AddResult, error)
+}
+
+// stageChangelist stages the changed files of the changelist called name,
+// refusing when files outside it are staged already, since those would be
+// committed with it.
+func stageChangelist(client stagingRepo, name string) error {
+	cls, _, err := loadChangelists(client)
+	if err != nil {
+		return err
+	}
+	cl := cls.find(name)
+	if cl == nil {
+		return fmt.Errorf("no changelist named %q", name)
+	}
+	staged, err := client.StagedFiles()
+	if err != nil {
+		return fmt.Errorf("failed to get staged files: %w", err)
+	}
+	var others []string
+	for _, f := range staged {
+		if !slices.Contains(cl.Files, f) {
+			others = append(others, f)
+		}
+	}
+	if len(others) > 0 {
+		slices.Sort(others)
+		return fmt.Errorf("files outside changelist %s are staged: %s (unstage them or add them to the changelist)", name, strings.Join(others, ", "))
+	}
+	if len(cl.Files) == 0 {
+		return nil
+	}
+	// Files with nothing to stage are skipped; an empty result is reported
+	// as nothing staged.
+	if _, err := client.AddFiles(cl.Files); err != nil {
+		return fmt.Errorf("failed to stage changelist %s: %w", name, err)
+	}
+	return nil
+}
+
+// dropFromChangelists takes committed files out of their changelists.
+func dropFromChangelists(client gitDirRepo, files []string) error {
+	cls, path, err := loadChangelists(client)
+	if err != nil {
+		return err
+	}
+	if cls.remove(files) == 0 {
+		return nil
+	}
+	return saveChangelists(path, cls)
+}
+
+// changelistPaths turns paths given on the command line into the slash
+// separated, repository-relative form the changelists keep.
+func changelistPaths(paths []string) []string {
+	out := make([]string, 0, len(paths))
+	for _, p := range paths {
+		out = append(out, filepath.ToSlash(filepath.Clean(p)))
+	}
+	return out
+}
+
+// changelistRepo is what the 'bgit changelist' commands need from the
+// repository: its status, and where the changelists are kept.
+type changelistRepo interface {
+	statusRepo
+}
+
+func runChangelistList(d *Deps) error {
+	client, err := openRepo[changelistRepo](d)
+	if err != nil {
+		return err
+	}
+	cls, _, err := loadChangelists(client)
+	if err != nil {
+		return err
+	}
+	lists := cls.view(collectStatus(client), true)
+
+	if d.Output.JSON() {
+		if lists == nil {
+			lists = []ui.Changelist{} // encode as [] rather than null
+		}
+		return json.NewEncoder(d.IO.Out).Encode(lists)
+	}
+	if len(lists) == 0 {
+		fmt.Fprintln(d.IO.Out, "No changelists. Create one with 'bgit changelist add <name> <path>...'.")
+		return nil
+	}
+	fmt.Fprint(d.IO.Out, ui.RenderChangelists(lists, ui.TerminalWidth(d.IO.Out)))
+	for _, cl := range lists {
+		if len(cl.Files) == 0 {
+			fmt.Fprintf(d.IO.Out, "Changelist %s is empty.\n", cl.Name)
+		}
+	}
+	return nil
+}
+
+func runChangelistAdd(d *Deps, name string, paths []string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("a changelist needs a name")
+	}
+	client, err := openRepo[changelistRepo](d)
+	if err != nil {
+		return err
+	}
+	cls, path, err := loadChangelists(client)
+	if err != nil {
+		return err
+	}
+	files := changelistPaths(paths)
+	cls.add(name, files)
+	if err := saveChangelists(path, cls); err != nil {
+		return err
+	}
+	d.infof("%sAdded %s to changelist %s\n", ui.Icon("✓"), plural(len(files), "file"), name)
+	return nil
+}
+
+func runChangelistRemove(d *Deps, paths []string) error {
+	client, err := openRepo[changelistRepo](d)
+	if err != nil {
+		return err
+	}
+	cls, path, err := loadChangelists(client)
+	if err != nil {
+		return err
+	}
+	n := cls.remove(changelistPaths(paths))
+	if n == 0 {
+		return errors.New("none of those files is in a changelist")
+	}
+	if err := saveChangelists(path, cls); err != nil {
+		return err
+	}
+	d.infof("%sTook %s out of its changelist\n", ui.Icon("✓"), plural(n, "file"))
+	return nil
+}
+
+func runChangelistDelete(d *Deps, name string) error {
+	client, err := openRepo[changelistRepo](d)
+	if err != nil {
+		return err
+	}
+	cls, path, err := loadChangelists(client)
+	if err != nil {
+		return err
+	}
+	if cls.find(name) == nil {
+		return fmt.Errorf("no changelist named %q", name)
+	}
+	cls.Lists = slices.DeleteFunc(cls.Lists, func(cl changelist) bool { return cl.Name == name })
+	if err := saveChangelists(path, cls); err != nil {
+		return err
+	}
+	d.infof("%sDeleted changelist %s\n", ui.Icon("✓"), name)
+	return nil
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+var errCherryPickNothing = errors.New("name the commits to cherry-pick")
+
+type cherryPickOptions struct {
+	noCommit bool
+	abort    bool
+}
+
+func newCherryPickCmd(d *Deps) *cobra.Command {
+	opts := &cherryPickOptions{}
+
+	cherryPickCmd := &cobra.Command{
+		Use:   "cherry-pick <commit>...",
+		Short: "Apply the changes of commits onto the current branch",
+		Long: `Apply the changes each <commit> made onto the current branch, in the order
+given, as new commits keeping their messages and authors. --no-commit only
+applies the changes to the staging area and the working tree, to look over
+and commit as one.
+
+When a commit conflicts, the cherry-pick stops there and lists the files to
+resolve. 'bgit resolve' resolves them and carries on with the commits left;
+'bgit cherry-pick --abort' gives up, putting the branch back as it was. With
+--no-commit the commits after the one that conflicted are not picked: pick
+them again once the conflicts are resolved.`,
+		Example: `  bgit cherry-pick 9c1d2e3
+  bgit cherry-pick feature~2 feature
+  bgit cherry-pick --no-commit 9c1d2e3 32f7d25
+  bgit cherry-pick --abort`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.abort {
+				if len(args) > 0 {
+					return errors.New("--abort takes no commits")
+				}
+				return runCherryPickAbort(d)
+			}
+			return runCherryPick(d, args, opts)
+		},
+	}
+
+	cherryPickCmd.Flags().BoolVarP(&opts.noCommit, "no-commit", "n", false, "Apply the changes without committing them")
+	cherryPickCmd.Flags().BoolVar(&opts.abort, "abort", false, "Give up a cherry-pick that stopped on conflicts")
+	cherryPickCmd.MarkFlagsMutuallyExclusive("no-commit", "abort")
+
+	return cherryPickCmd
+}
+
+// cherryPickRepo is what 'bgit cherry-pick' needs from the repository.
+type cherryPickRepo interface {
+	OperationInProgress() gitService.Operation
+	ResolveCommit(rev string) (*object.Commit, error)
+	HeadBranch() (name string, detached bool, err error)
+	CherryPick(commits []plumbing.Hash, noCommit bool) error
+	Conflicts() ([]gitService.Conflict, error)
+	CommitsBetween(since, until plumbing.Hash) ([]*object.Commit, error)
+	AbortCherryPick() error
+}
+
+func runCherryPick(d *Deps, revs []string, opts *cherryPickOptions) error {
+	if len(revs) == 0 {
+		return errCherryPickNothing
+	}
+	client, err := openRepo[cherryPickRepo](d)
+	if err != nil {
+		return err
+	}
+	if op := client.OperationInProgress(); op != gitService.NoOperation {
+		return fmt.Errorf("a %s is in progress; finish it with 'bgit resolve' first", op)
+	}
+
+	result := ui.CherryPickResult{NoCommit: opts.noCommit}
+	hashes := make([]plumbing.Hash, 0, len(revs))
+	for _, rev := range revs {
+		c, err := client.ResolveCommit(rev)
+		if err != nil {
+			return err
+		}
+		hashes = append(hashes, c.Hash)
+		result.Commits = append(result.Commits, ui.CherryPickCommit{
+			Hash:    c.Hash.String(),
+			Subject: strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0],
+		})
+	}
+	head, err := client.ResolveCommit("HEAD")
+	if err != nil {
+		return err
+	}
+	var detached bool
+	if result.Branch, detached, err = client.HeadBranch(); err != nil {
+		return err
+	}
+	if detached {
+		result.Branch = "HEAD"
+	}
+
+	pickErr := client.CherryPick(hashes, opts.noCommit)
+	var conflict gitService.ErrCherryPickConflict
+	switch {
+	case errors.As(pickErr, &conflict):
+		result.Applied = conflict.Applied
+		conflicts, err := client.Conflicts()
+		if err != nil {
+			return fmt.Errorf("failed to list conflicts: %w", err)
+		}
+		for _, c := range conflicts {
+			result.Conflicts = append(result.Conflicts, c.Path)
+		}
+	case pickErr != nil:
+		return pickErr
+	default:
+		result.Applied = len(hashes)
+	}
+
+	if !opts.noCommit {
+		if err := cherryPicked(client, head.Hash, result.Commits); err != nil {
+			return err
+		}
+	}
+
+	if pickErr == nil {
+		d.infof("%s", ui.RenderCherryPick(result, ui.TerminalWidth(d.IO.Out)))
+		if opts.noCommit {
+			d.infof("Commit the changes with 'bgit commit'.\n")
+		}
+		return nil
+	}
+	fmt.Fprint(d.IO.ErrOut, ui.RenderCherryPick(result, ui.TerminalWidth(d.IO.ErrOut)))
+	hint := "Hint: run 'bgit resolve' to resolve the conflicts"
+	rest := result.Applied+1 < len(result.Commits)
+	switch {
+	case opts.noCommit && rest:
+		hint += ", then pick the commits not picked again"
+	case opts.noCommit:
+	case rest:
+		hint += " and pick the rest, or 'bgit cherry-pick --abort' to give up"
+	default:
+		hint += ", or 'bgit cherry-pick --abort' to give up"
+	}
+	fmt.Fprintln(d.IO.ErrOut, hint)
+	return pickErr
+}
+
+// cherryPicked fills in the commits the picked ones became: those made on
+// top of from, oldest first.
+func cherryPicked(client cherryPickRepo, from plumbing.Hash, commits []ui.CherryPickCommit) error {
+	head, err := client.ResolveCommit("HEAD")
+	if err != nil {
+		return err
+	}
+	made, err := client.CommitsBetween(from, head.Hash)
+	if err != nil {
+		return err
+	}
+	slices.Reverse(made)
+	for i, c := range made {
+		if i < len(commits) {
+			commits[i].Picked = c.Hash.String()
+		}
+	}
+	return nil
+}
+
+func runCherryPickAbort(d *Deps) error {
+	client, err := openRepo[cherryPickRepo](d)
+	if err != nil {
+		return err
+	}
+	if client.OperationInProgress() != gitService.CherryPick {
+		return errors.New("no cherry-pick to abort")
+	}
+	if err := client.AbortCherryPick(); err != nil {
+		return err
+	}
+	head, err := client.ResolveCommit("HEAD")
+	if err != nil {
+		return err
+	}
+	d.infof("%sCherry-pick aborted; HEAD is back at %s %s\n", ui.Icon("✓"), head.Hash.String()[:7], strings.SplitN(strings.TrimSpace(head.Message), "\n", 2)[0])
+	return nil
+}
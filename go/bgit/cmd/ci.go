@@ -0,0 +1,368 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	forgeService "github.com/endalk200/bgit/internal/services/forge"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/pager"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+type ciOptions struct {
+	commit  string
+	all     bool
+	noPager bool
+}
+
+func newCICmd(d *Deps) *cobra.Command {
+	opts := &ciOptions{}
+
+	ciCmd := &cobra.Command{
+		Use:   "ci",
+		Short: "Show CI runs for the current branch, read failed logs, re-run jobs",
+		Long: `Follow GitHub Actions for the current branch without leaving the terminal.
+
+On its own, or as 'ci status', it shows the latest run of every workflow for
+the branch (or, with --commit, for one commit) and the jobs that failed in
+them, each with the step that failed and the job ID. 'ci logs' prints the
+log of a job, by default the first failed one, and 'ci rerun' starts the
+failed jobs again.
+
+The API is called with GITHUB_TOKEN or GH_TOKEN when set; private
+repositories and 'ci rerun' need one.`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCIStatus(cmd.Context(), d, opts)
+		},
+	}
+	ciCmd.PersistentFlags().StringVar(&opts.commit, "commit", "", "Look at the runs of this commit instead of the branch")
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the latest workflow runs and failed jobs",
+		Long: `Show the latest run of every workflow for the current branch, or for
+--commit, with the jobs that failed in them. --all lists every recent run
+instead of the latest per workflow.`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCIStatus(cmd.Context(), d, opts)
+		},
+	}
+	statusCmd.Flags().BoolVar(&opts.all, "all", false, "List every recent run, not only the latest of each workflow")
+
+	logsCmd := &cobra.Command{
+		Use:   "logs [job-id]",
+		Short: "Show the log of a job, by default the first failed one",
+		Long: `Show the log of a finished job, without timestamps and with each step as
+its own section. Without a job ID the first failed job of the latest runs
+is shown, as listed by 'bgit ci status'.
+
+On a terminal the log opens in the pager, where n and N move between
+steps; otherwise, or with --no-pager, it is streamed to standard output as
+it downloads.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCILogs(cmd.Context(), d, args, opts)
+		},
+	}
+	logsCmd.Flags().BoolVar(&opts.noPager, "no-pager", false, "Print the log instead of opening the pager")
+
+	rerunCmd := &cobra.Command{
+		Use:   "rerun [run-id]",
+		Short: "Re-run the failed jobs of a run, by default of every failed run",
+		Long: `Start the failed jobs of a workflow run again, along with the jobs that
+depend on them. Without a run ID every failed run among the latest runs of
+the branch (or --commit) is re-run. It needs a token allowed to run
+workflows.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCIRerun(cmd.Context(), d, args, opts)
+		},
+	}
+
+	ciCmd.AddCommand(statusCmd, logsCmd, rerunCmd)
+	return ciCmd
+}
+
+// ciTarget is what the ci commands look at: a branch, and the commit whose
+// runs count as current.
+type ciTarget struct {
+	forge  Forge
+	branch string
+	head   string
+	// detached is set when runs are looked up by commit rather than branch.
+	detached bool
+}
+
+// ciRepo is what the 'bgit ci' commands read from the repository: the
+// commit to look up the runs of, and the remote the forge is found from.
+type ciRepo interface {
+	ForgeRepo
+	ResolveCommit(rev string) (*object.Commit, error)
+	HeadBranch() (name string, detached bool, err error)
+}
+
+func openCI(d *Deps, opts *ciOptions) (ciTarget, error) {
+	client, err := openRepo[ciRepo](d)
+	if err != nil {
+		return ciTarget{}, err
+	}
+	forge, err := openForge(d, client)
+	if err != nil {
+		return ciTarget{}, fmt.Errorf("cannot reach CI: %w", err)
+	}
+
+	rev := opts.commit
+	if rev == "" {
+		rev = "HEAD"
+	}
+	commit, err := client.ResolveCommit(rev)
+	if err != nil {
+		return ciTarget{}, err
+	}
+	t := ciTarget{forge: forge, head: commit.Hash.String()}
+	if opts.commit != "" {
+		t.branch, t.detached = rev, true
+		return t, nil
+	}
+	if t.branch, t.detached, err = client.HeadBranch(); err != nil {
+		return ciTarget{}, err
+	}
+	return t, nil
+}
+
+// ciRuns fetches the runs of the target: the latest of each workflow
+// unless all is set.
+func ciRuns(ctx context.Context, t ciTarget, all bool) ([]forgeService.WorkflowRun, error) {
+	filter := forgeService.RunFilter{Branch: t.branch, Limit: 50}
+	if t.detached {
+		filter = forgeService.RunFilter{SHA: t.head, Limit: 50}
+	}
+	runs, err := t.forge.WorkflowRuns(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+	}
+	if all {
+		return runs, nil
+	}
+	// Runs come newest first, so the first of each workflow is its latest.
+	seen := map[string]bool{}
+	latest := runs[:0]
+	for _, r := range runs {
+		if !seen[r.Workflow] {
+			seen[r.Workflow] = true
+			latest = append(latest, r)
+		}
+	}
+	return latest, nil
+}
+
+// failedJobs lists the failed jobs of the failed runs among runs.
+func failedJobs(ctx context.Context, forge Forge, runs []forgeService.WorkflowRun) ([]ui.JobRow, error) {
+	var failed []ui.JobRow
+	for _, r := range runs {
+		if !forgeService.Failed(r.State()) {
+			continue
+		}
+		jobs, err := forge.RunJobs(ctx, r.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the jobs of run %d: %w", r.ID, err)
+		}
+		for _, j := range jobs {
+			if !forgeService.Failed(j.State()) {
+				continue
+			}
+			row := ui.JobRow{ID: j.ID, RunID: r.ID, Workflow: r.Workflow, Name: j.Name, State: j.State(), URL: j.URL}
+			for _, s := range j.Steps {
+				if forgeService.Failed(s.State()) {
+					row.Step = s.Name
+					break
+				}
+			}
+			failed = append(failed, row)
+		}
+	}
+	return failed, nil
+}
+
+func runCIStatus(ctx context.Context, d *Deps, opts *ciOptions) error {
+	t, err := openCI(d, opts)
+	if err != nil {
+		return err
+	}
+	runs, err := ciRuns(ctx, t, opts.all)
+	if err != nil {
+		return err
+	}
+	view := ui.CIView{
+		Repo:   t.forge.Repo().String(),
+		Branch: t.branch,
+		Head:   t.head,
+		Runs:   []ui.RunRow{},
+		Now:    time.Now(),
+	}
+	for _, r := range runs {
+		view.Runs = append(view.Runs, ui.RunRow{
+			ID:       r.ID,
+			Workflow: r.Workflow,
+			Event:    r.Event,
+			State:    r.State(),
+			SHA:      r.SHA,
+			Attempt:  r.Attempt,
+			When:     r.Updated,
+			URL:      r.URL,
+		})
+	}
+	if view.Failed, err = failedJobs(ctx, t.forge, runs); err != nil {
+		return err
+	}
+
+	if d.Output.JSON() {
+		if view.Failed == nil {
+			view.Failed = []ui.JobRow{}
+		}
+		return json.NewEncoder(d.IO.Out).Encode(view)
+	}
+	fmt.Fprint(d.IO.Out, ui.RenderCI(view, ui.TerminalWidth(d.IO.Out)))
+	if len(view.Failed) > 0 {
+		d.infoln("\nRun 'bgit ci logs' to read the first failed log, or 'bgit ci rerun' to try again.")
+	}
+	return nil
+}
+
+func runCILogs(ctx context.Context, d *Deps, args []string, opts *ciOptions) error {
+	t, err := openCI(d, opts)
+	if err != nil {
+		return err
+	}
+
+	var job forgeService.Job
+	if len(args) == 1 {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a job ID; 'bgit ci status' lists them", args[0])
+		}
+		if job, err = t.forge.Job(ctx, id); err != nil {
+			return fmt.Errorf("failed to look up job %d: %w", id, err)
+		}
+	} else {
+		runs, err := ciRuns(ctx, t, false)
+		if err != nil {
+			return err
+		}
+		failed, err := failedJobs(ctx, t.forge, runs)
+		if err != nil {
+			return err
+		}
+		if len(failed) == 0 {
+			return fmt.Errorf("no failed jobs in the latest runs of %s; pass a job ID to read another log", t.branch)
+		}
+		job = forgeService.Job{ID: failed[0].ID, Name: failed[0].Workflow + " › " + failed[0].Name, Status: "completed"}
+	}
+	if job.Status != "completed" {
+		return fmt.Errorf("job %d is %s; GitHub serves its log once it has finished", job.ID, strings.ReplaceAll(job.Status, "_", " "))
+	}
+
+	log, err := t.forge.JobLog(ctx, job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the log of job %d: %w", job.ID, err)
+	}
+	defer log.Close()
+
+	lines := bufio.NewScanner(log)
+	lines.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	term, ok := ui.TerminalFile(d.IO.Out)
+	if opts.noPager || !ok || !ui.IsInteractive(d.IO.In, d.IO.Out) {
+		for lines.Scan() {
+			text, _ := ui.RenderLogLine(lines.Text())
+			fmt.Fprintln(d.IO.Out, text)
+		}
+		return lines.Err()
+	}
+
+	var (
+		sections []string
+		section  strings.Builder
+	)
+	for lines.Scan() {
+		text, group := ui.RenderLogLine(lines.Text())
+		if group && section.Len() > 0 {
+			sections = append(sections, section.String())
+			section.Reset()
+		}
+		section.WriteString(text + "\n")
+	}
+	if err := lines.Err(); err != nil {
+		return fmt.Errorf("failed to read the log of job %d: %w", job.ID, err)
+	}
+	if section.Len() > 0 {
+		sections = append(sections, section.String())
+	}
+	if len(sections) == 0 {
+		d.infof("The log of job %d is empty.\n", job.ID)
+		return nil
+	}
+
+	d.flushOut()
+	return pager.Run(term, d.IO.In, sections, pager.Options{
+		Title: fmt.Sprintf("ci logs %s (job %d)", job.Name, job.ID),
+		Noun:  "step",
+	})
+}
+
+func runCIRerun(ctx context.Context, d *Deps, args []string, opts *ciOptions) error {
+	t, err := openCI(d, opts)
+	if err != nil {
+		return err
+	}
+
+	var runs []forgeService.WorkflowRun
+	if len(args) == 1 {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a run ID; 'bgit ci status' lists them", args[0])
+		}
+		runs = []forgeService.WorkflowRun{{ID: id, Workflow: "run"}}
+	} else {
+		latest, err := ciRuns(ctx, t, false)
+		if err != nil {
+			return err
+		}
+		for _, r := range latest {
+			if forgeService.Failed(r.State()) {
+				runs = append(runs, r)
+			}
+		}
+		if len(runs) == 0 {
+			d.infof("No failed runs for %s.\n", t.branch)
+			return nil
+		}
+	}
+
+	for _, r := range runs {
+		if err := t.forge.RerunFailedJobs(ctx, r.ID); err != nil {
+			var callErr forgeService.ErrForgeCallFailed
+			if errors.As(err, &callErr) && (callErr.Code == 401 || callErr.Code == 403 || callErr.Code == 404) {
+				err = fmt.Errorf("%w (set GITHUB_TOKEN to a token that can run workflows)", err)
+			}
+			return fmt.Errorf("failed to re-run %s %d: %w", r.Workflow, r.ID, err)
+		}
+		d.infof("%sRe-running the failed jobs of %s %d\n", ui.Icon("🔁"), r.Workflow, r.ID)
+		if r.URL != "" {
+			d.infof("  %s\n", r.URL)
+		}
+	}
+	return nil
+}
@@ -1,13 +1,25 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
-	"github.com/endalk200/bgit/internal/config"
+	"github.com/endalk200/bgit/internal/metrics"
+	"github.com/endalk200/bgit/internal/sandbox"
 	commitgenService "github.com/endalk200/bgit/internal/services/commitgen"
 	gitService "github.com/endalk200/bgit/internal/services/git"
-
+	ownersService "github.com/endalk200/bgit/internal/services/owners"
+	testimpactService "github.com/endalk200/bgit/internal/services/testimpact"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/pipeline"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
 	"github.com/spf13/cobra"
 )
 
@@ -19,91 +31,802 @@ func (e ErrCanNotDetermineWorkingDirectory) Error() string {
 	return fmt.Sprintf("cannot determine working directory: %s", e.Message)
 }
 
-var commitCmd = &cobra.Command{
-	Use:   "commit",
-	Short: "Create a commit from staged changes (AI message fallback)",
-	Long: `Create a commit from staged changes. If -m/--message is omitted and --no-ai
+type commitOptions struct {
+	message      string
+	dryRun       bool
+	noAI         bool
+	noSpellcheck bool
+
+	forceConflicts bool
+	generated      bool
+	noVerify       bool
+	changelist     string
+	acceptRisk     bool
+	verifyTests    bool
+
+	amend         bool
+	date          string
+	committerDate string
+	allowBackdate bool
+}
+
+func newCommitCmd(d *Deps) *cobra.Command {
+	opts := &commitOptions{}
+
+	commitCmd := &cobra.Command{
+		Use:         "commit",
+		Short:       "Create a commit from staged changes (AI message fallback)",
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Long: `Create a commit from staged changes. If -m/--message is omitted and --no-ai
 is not set, an AI generated message will be requested using OpenAI. This requires
-OPENAI_API_KEY to be present in the environment.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		message, _ := cmd.Flags().GetString("message")
-		dryRun, _ := cmd.Flags().GetBool("dry-run")
-		noAI, _ := cmd.Flags().GetBool("no-ai")
+OPENAI_API_KEY to be present in the environment.
 
-		cwd, err := os.Getwd()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "error: cannot determine working directory: %v\n", err)
-			os.Exit(1)
-		}
+--changelist <name> stages the changed files of that changelist (see 'bgit
+changelist') and commits just them; it stops if files outside the changelist
+are staged. Committed files leave the changelist, and a dry run leaves them
+staged.
 
-		gitClient, err := gitService.NewGitClient(cwd)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "error: %v\n", err)
-			os.Exit(1)
-		}
+While a merge is in progress, stopped on conflicts or by 'bgit merge
+--no-commit', the commit is the merge commit, and its message the one git
+wrote to MERGE_MSG unless -m gives another.
+
+--amend replaces the last commit with one of the staged changes on top of
+it, keeping its author and author date and, without -m, its message; nothing
+need be staged to reword or redate it. --date sets the author date and
+--committer-date the committer date, to a date like 2024-05-01 14:00 or
+"yesterday 14:00", "3 days ago" or "today 9:30am". A date further back than
+commit.max_backdate (a week) is refused unless --allow-backdate is given.
+
+Staged files are scanned for leftover conflict markers (<<<<<<<, |||||||,
+>>>>>>>) and the commit is refused, listing every file and line, unless
+--force-conflicts is given.
+
+The commands under tasks.pre_commit in the config (linters, quick tests)
+run next, at the root of the repository. Each runs without the API keys and
+tokens in the environment and is killed after its timeout; if any fails, the
+commit stops and every task is listed with how it ended and the end of its
+output. --no-verify skips them.
+
+With --verify-tests the tests the staged changes affect run next (see 'bgit
+tests': the Go packages of staged Go files, and tests.mappings of the
+config), and the commit stops if any fail.
+
+The staged changes are then given a risk score out of 100, from their size,
+files that recent bug fixes keep coming back to, source changed without
+tests, and migrations or configuration they touch; with risk.ai in the
+config the AI provider rates the diff too and the two are averaged. From
+risk.warn_at (40) the progress names what makes the change risky, and from
+risk.confirm_at (70) the commit shows the score in full and asks before going
+ahead. Without a terminal it fails instead, unless --accept-risk is given.
+
+With a CODEOWNERS file (in .github/, the root or docs/) the progress names
+the owners whose review the staged files will need. When they fall into
+owners.warn_areas (3) or more ownership areas, the commit goes ahead but
+suggests splitting it.
 
-		stagedFiles, err := gitClient.StagedFiles()
+Generated files (lockfiles, minified assets, vendored code; see the generated
+section of the config) are named but not included in the diff sent to the AI
+provider, and are counted but not listed in the summary. --generated includes
+them. Large diffs are shortened to the ai.max_diff_bytes and
+ai.per_file_max_lines limits, keeping the lines around each change, and the
+prompt notes what was left out.
+
+With --offline (or BGIT_OFFLINE=1) no AI provider is asked; the message is
+written from the names of the staged files instead.
+
+Generated messages then go through the steps in message.post_process of the
+config (regex replacements, a prefix, external filter commands), in order,
+and their body is wrapped at message.body_width columns (72 by default),
+keeping lists, code and trailers in shape. On a branch made with
+'bgit issue start' a "Refs #<number>" trailer is added, unless the message
+mentions the issue already.
+
+The message is spell-checked against a list of common misspellings and the
+project terms in the spell section of the config. On a terminal the commit
+pauses to offer corrections; --no-spellcheck skips the check.
+
+With notes.environment set in the config, the Go and git versions, the
+platform and any dirty submodules are attached to the commit as a git note
+(see 'bgit show --notes').
+
+Progress is shown as a pipeline (collect staged files, check for conflict
+markers, run pre-commit tasks, run affected tests, assess risk, check
+owners, build diff, generate message, post-process, wrap body, reference
+issue, validate, check spelling, commit, record environment). On a
+terminal the stages update live; when output is piped each finished stage
+is printed on its own line.
+
+With --output json the progress and any prompts go to standard error, and
+standard output carries only the result, for editors and wrappers: the hash,
+message, author and date, the files with their insertions and deletions and
+the totals, where the message came from (ai, offline or message), the AI
+provider and model when one wrote it, the risk score with its level and
+factors, the code owners of the files with the reviewers a pull request will
+need, and how long the command took. A dry run prints the same with the
+staged changes and no hash. When there is nothing to commit, or the commit
+is aborted, nothing is printed and the command fails.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCommit(cmd.Context(), d, opts)
+		},
+	}
+
+	commitCmd.Flags().StringVarP(&opts.message, "message", "m", "", "Commit message (if omitted uses AI or heuristic)")
+	commitCmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Preview commit without creating it")
+	commitCmd.Flags().BoolVar(&opts.noAI, "no-ai", false, "Disable AI commit message generation")
+	commitCmd.Flags().BoolVar(&opts.noSpellcheck, "no-spellcheck", false, "Skip the commit message spell-check")
+	commitCmd.Flags().BoolVar(&opts.forceConflicts, "force-conflicts", false, "Commit even if staged files contain conflict markers")
+	commitCmd.Flags().BoolVar(&opts.generated, "generated", false, "Include generated files in the AI prompt and the summary")
+	commitCmd.Flags().BoolVar(&opts.noVerify, "no-verify", false, "Skip the pre-commit tasks in the config")
+	commitCmd.Flags().StringVar(&opts.changelist, "changelist", "", "Stage and commit just the files of this changelist")
+	commitCmd.Flags().BoolVar(&opts.verifyTests, "verify-tests", false, "Run the tests the staged changes affect first (see 'bgit tests')")
+	commitCmd.Flags().BoolVar(&opts.acceptRisk, "accept-risk", false, "Commit without asking however high the risk score")
+	commitCmd.Flags().BoolVar(&opts.amend, "amend", false, "Replace the last commit, keeping its message unless -m is given")
+	commitCmd.Flags().StringVar(&opts.date, "date", "", "Author date, like 2024-05-01 14:00 or \"yesterday 14:00\"")
+	commitCmd.Flags().StringVar(&opts.committerDate, "committer-date", "", "Committer date, in the form of --date")
+	commitCmd.Flags().BoolVar(&opts.allowBackdate, "allow-backdate", false, "Allow dates further back than commit.max_backdate")
+
+	return commitCmd
+}
+
+// errNothingStaged stops the commit pipeline when the index is clean.
+var errNothingStaged = errors.New("nothing staged")
+
+// errConflictMarkers stops the commit pipeline when staged content still has
+// conflict markers in it.
+var errConflictMarkers = errors.New("staged files contain conflict markers (use --force-conflicts to commit anyway)")
+
+// errBackdated stops a commit dated further back than commit.max_backdate.
+var errBackdated = errors.New("that is further back than commit.max_backdate allows (use --allow-backdate to commit anyway)")
+
+// commitOptionsFor reads --amend, --date and --committer-date, refusing
+// dates further back from now than commit.max_backdate.
+func commitOptionsFor(d *Deps, opts *commitOptions, now time.Time) (gitService.CommitOptions, error) {
+	options := gitService.CommitOptions{Amend: opts.amend}
+	limit := d.Config.Get().Commit.MaxBackdate
+	for _, f := range []struct {
+		flag, value string
+		date        *time.Time
+	}{
+		{"date", opts.date, &options.AuthorDate},
+		{"committer-date", opts.committerDate, &options.CommitterDate},
+	} {
+		if f.value == "" {
+			continue
+		}
+		t, err := gitService.ParseDate(f.value, now)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "error: failed to get staged files: %v\n", err)
-			os.Exit(1)
+			return options, fmt.Errorf("invalid --%s: %w", f.flag, err)
 		}
-
-		if len(stagedFiles) == 0 {
-			fmt.Println("No staged files to commit. Use 'bgit add' to stage files first.")
-			return
+		if limit > 0 && now.Sub(t) > limit && !opts.allowBackdate {
+			return options, fmt.Errorf("--%s %s is %s: %w", f.flag, t.Format("2006-01-02 15:04"), ui.RelativeTime(t, now), errBackdated)
 		}
+		*f.date = t
+	}
+	return options, nil
+}
 
-		fmt.Printf("Found %d staged files:\n", len(stagedFiles))
-		for _, file := range stagedFiles {
-			fmt.Printf("  • %s\n", file)
+// issueLinkRepo is what finding the issue of the current branch needs.
+type issueLinkRepo interface {
+	CurrentBranch() (string, error)
+	BranchIssue(branch string) (int, error)
+}
+
+// linkedIssue is the issue the current branch was started for with 'bgit
+// issue start', or 0.
+func linkedIssue(client issueLinkRepo) int {
+	branch, err := client.CurrentBranch()
+	if err != nil {
+		return 0
+	}
+	number, _ := client.BranchIssue(branch)
+	return number
+}
+
+// progressOut is where stage progress and prompts go: standard output,
+// unless that is reserved for a JSON result.
+func progressOut(d *Deps) io.Writer {
+	if d.Output.JSON() {
+		return d.IO.ErrOut
+	}
+	return d.IO.Out
+}
+
+// pipelineMode picks the progress display: a live board on a terminal,
+// plain lines when piped, nothing in quiet mode.
+func pipelineMode(d *Deps) pipeline.Mode {
+	switch {
+	case d.Output.Quiet:
+		return pipeline.Silent
+	case ui.IsTerminal(progressOut(d)):
+		return pipeline.Live
+	default:
+		return pipeline.Lines
+	}
+}
+
+// runPipeline shows stage progress on the terminal itself when drawing the
+// live board, and on the (buffered) command output otherwise.
+func runPipeline(ctx context.Context, d *Deps, stages []pipeline.Stage) error {
+	mode := pipelineMode(d)
+	w := progressOut(d)
+	if mode == pipeline.Live {
+		d.flushOut()
+		w, _ = ui.TerminalFile(w)
+	}
+	return pipeline.Run(ctx, w, d.IO.In, mode, stages)
+}
+
+// commitRepo is everything 'bgit commit' does to the repository, from
+// reading the staged changes to making the commit and its note.
+type commitRepo interface {
+	submoduleRepo
+	indexRepo
+	issueLinkRepo
+	promptDiffRepo
+	ownersRepo
+	riskRepo
+	stagingRepo
+	MergeMessage() (string, bool)
+	OperationInProgress() gitService.Operation
+	ResolveCommit(rev string) (*object.Commit, error)
+	StagedConflictMarkers(files []string) ([]gitService.MarkerHit, error)
+	GetStagedFilesDiff(stagedFiles []string) (string, error)
+	Commit(message string, opts gitService.CommitOptions) (*object.Commit, error)
+	AddNote(ref string, commit plumbing.Hash, text string) error
+	CommitStats(c *object.Commit) ([]gitService.FileStat, error)
+	DiffStats(staged bool, paths []string) ([]gitService.FileStat, error)
+}
+
+func runCommit(ctx context.Context, d *Deps, opts *commitOptions) error {
+	start := time.Now()
+	gitClient, err := openRepo[commitRepo](d)
+	if err != nil {
+		return err
+	}
+
+	var (
+		stagedFiles []string
+		stagedDiff  *commitgenService.Diff
+		message     = opts.message
+		provider    = d.Config.Get().AIProvider
+		commitObj   *object.Commit
+		markers     []gitService.MarkerHit
+		taskRuns    []sandbox.Result
+		testRuns    []sandbox.Result
+		risk        *commitRisk
+		owners      []ownersService.Area
+		ownersWarn  string
+
+		// providerFailed tells the error path to add a configuration hint.
+		providerFailed bool
+	)
+
+	commitOpts, err := commitOptionsFor(d, opts, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// A merge in progress is committed with the message git wrote for it,
+	// and an amended commit keeps its message; kept says which.
+	var kept string
+	mergeMessage, merging := gitClient.MergeMessage()
+	switch {
+	case opts.amend:
+		if op := gitClient.OperationInProgress(); op != gitService.NoOperation {
+			return fmt.Errorf("cannot amend while a %s is in progress; finish it with 'bgit resolve' first", op)
 		}
-		fmt.Println()
+		head, err := gitClient.ResolveCommit("HEAD")
+		if err != nil {
+			return err
+		}
+		if message == "" {
+			message, kept = strings.TrimSpace(head.Message), "message of the amended commit"
+		}
+	case merging && message == "":
+		message, kept = mergeMessage, "merge message"
+	}
 
-		// If no message provided, generate one using AI
-		if message == "" && !noAI {
-			fmt.Println("Generating commit message using AI...")
-			stagedDiff, err := gitClient.GetStagedFilesDiff(stagedFiles)
+	stages := []pipeline.Stage{
+		{Name: "Collect staged files", Run: func(ctx context.Context) (string, error) {
+			if opts.changelist != "" {
+				if err := stageChangelist(gitClient, opts.changelist); err != nil {
+					return "", err
+				}
+			}
+			files, err := gitClient.StagedFiles()
+			if err != nil {
+				return "", fmt.Errorf("failed to get staged files: %w", err)
+			}
+			if len(files) == 0 && !merging && !opts.amend {
+				return "", errNothingStaged
+			}
+			stagedFiles = files
+			return plural(len(files), "file"), nil
+		}},
+		{Name: "Check conflict markers", Run: func(ctx context.Context) (string, error) {
+			hits, err := gitClient.StagedConflictMarkers(stagedFiles)
+			if err != nil {
+				return "", fmt.Errorf("failed to scan staged files: %w", err)
+			}
+			markers = hits
+			switch {
+			case len(hits) == 0:
+				return "none", nil
+			case opts.forceConflicts:
+				return plural(len(hits), "marker") + ", forced", nil
+			default:
+				return "", errConflictMarkers
+			}
+		}},
+		{Name: "Run pre-commit tasks", Run: func(ctx context.Context) (string, error) {
+			tasks := d.Config.Get().Tasks.PreCommit
+			switch {
+			case opts.noVerify:
+				return "", pipeline.Skip("--no-verify")
+			case len(tasks) == 0:
+				return "", pipeline.Skip("none configured")
+			}
+			taskRuns = runTasks(ctx, d, "", tasks)
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			for _, r := range taskRuns {
+				if r.Failed() {
+					return "", errTasksFailed
+				}
+			}
+			return plural(len(taskRuns), "task") + " passed", nil
+		}},
+		{Name: "Run affected tests", Run: func(ctx context.Context) (string, error) {
+			if !opts.verifyTests {
+				return "", pipeline.Skip("no --verify-tests")
+			}
+			suites, _ := testimpactService.Plan(os.DirFS("."), stagedFiles, d.Config.Get().Tests.Mappings)
+			cmds := testimpactService.Commands(suites)
+			if len(cmds) == 0 {
+				return "", pipeline.Skip("no tests found for the staged files")
+			}
+			return testStage(d, cmds, &testRuns).Run(ctx)
+		}},
+		{Name: "Assess risk", Run: func(ctx context.Context) (string, error) {
+			cfg := d.Config.Get().Risk
+			if !cfg.Enabled {
+				return "", pipeline.Skip("disabled in config")
+			}
+			a, note, err := assessRisk(ctx, d, gitClient, stagedFiles)
+			if err != nil {
+				return "", err
+			}
+			risk = &commitRisk{Score: a.Score, Level: riskLevel(a.Score, cfg), Factors: a.Factors}
+			detail, err := checkRisk(ctx, d, *risk, opts)
+			if err != nil {
+				return "", err
+			}
+			if note != "" {
+				detail += " (" + note + ")"
+			}
+			return detail, nil
+		}},
+		{Name: "Check owners", Run: func(ctx context.Context) (string, error) {
+			rules, file, err := loadOwners(gitClient)
+			switch {
+			case err != nil && file != "":
+				// A broken CODEOWNERS file is for its owners to fix.
+				return "", pipeline.Skip(err.Error())
+			case err != nil:
+				return "", err
+			case rules == nil:
+				return "", pipeline.Skip("no CODEOWNERS file")
+			}
+			owners = rules.Areas(stagedFiles)
+			detail, warn := checkOwners(d, owners)
+			if warn {
+				ownersWarn = fmt.Sprintf("This commit spans %s; its pull request needs reviews from %s.",
+					plural(ownersService.Owned(owners), "ownership area"), strings.Join(ownersService.Reviewers(owners), ", "))
+			}
+			return detail, nil
+		}},
+		{Name: "Build diff", Run: func(ctx context.Context) (string, error) {
+			if message != "" || opts.noAI {
+				return "", pipeline.Skip("not needed without AI")
+			}
+			pd, err := buildPromptDiff(d, gitClient, stagedFiles, opts.generated)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "error: failed to get staged diff: %v\n", err)
-				os.Exit(1)
+				return "", err
 			}
+			stagedDiff = pd.diff
 
-			// Get configured provider
-			provider := config.GetProvider()
-			fmt.Printf("Using AI provider: %s (env: %s)\n", provider.Name, provider.EnvName)
+			detail := plural(stagedDiff.Len(), "byte")
+			if stagedDiff.Truncation().Truncated() {
+				detail = fmt.Sprintf("%s of %d, truncated", detail, stagedDiff.Size())
+			}
+			if len(pd.omitted) > 0 {
+				detail += fmt.Sprintf(", %s left out", plural(len(pd.omitted), "generated file"))
+			}
+			return detail, nil
+		}},
+		{Name: "Generate message", Run: func(ctx context.Context) (string, error) {
+			switch {
+			case opts.message != "":
+				return "", pipeline.Skip("message given with -m")
+			case kept != "":
+				return "", pipeline.Skip(kept)
+			}
+			if opts.noAI {
+				return "", pipeline.Skip("AI disabled")
+			}
+			if d.Offline {
+				// The heuristic names every staged file, generated ones too.
+				diff, err := gitClient.GetStagedFilesDiff(stagedFiles)
+				if err != nil {
+					return "", fmt.Errorf("failed to get staged diff: %w", err)
+				}
+				message = commitgenService.HeuristicMessage(diff)
+				return "offline, from file names", nil
+			}
+			generated, err := d.CommitGen.GenerateCommitMessage(ctx, stagedDiff, provider)
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			if err != nil {
+				providerFailed = true
+				return "", fmt.Errorf("%s provider failed: %w", provider.Name, err)
+			}
+			message = generated
+			return provider.Name, nil
+		}},
+		{Name: "Post-process message", Run: func(ctx context.Context) (string, error) {
+			steps := d.Config.Get().Message.PostProcess
+			switch {
+			case opts.message != "":
+				return "", pipeline.Skip("message given with -m")
+			case kept != "":
+				return "", pipeline.Skip(kept)
+			case message == "":
+				return "", pipeline.Skip("no message generated")
+			case len(steps) == 0:
+				return "", pipeline.Skip("none configured")
+			}
+			processed, err := commitgenService.PostProcess(ctx, message, steps, secretEnv(d))
+			if err != nil {
+				return "", err
+			}
+			message = processed
+			return plural(len(steps), "step"), nil
+		}},
+		{Name: "Wrap body", Run: func(ctx context.Context) (string, error) {
+			width := d.Config.Get().Message.BodyWidth
+			switch {
+			case opts.message != "":
+				return "", pipeline.Skip("message given with -m")
+			case kept != "":
+				return "", pipeline.Skip(kept)
+			case width <= 0:
+				return "", pipeline.Skip("disabled in config")
+			}
+			wrapped := commitgenService.WrapBody(message, width)
+			if wrapped == message {
+				return "", pipeline.Skip(fmt.Sprintf("within %d columns", width))
+			}
+			message = wrapped
+			return fmt.Sprintf("%d columns", width), nil
+		}},
+		{Name: "Reference issue", Run: func(ctx context.Context) (string, error) {
+			if !d.Config.Get().Issue.Reference {
+				return "", pipeline.Skip("disabled in config")
+			}
+			number := linkedIssue(gitClient)
+			if number == 0 {
+				return "", pipeline.Skip("branch not started for an issue")
+			}
+			message = commitgenService.ReferenceIssue(message, number)
+			return fmt.Sprintf("#%d", number), nil
+		}},
+		{Name: "Validate message", Run: func(ctx context.Context) (string, error) {
+			if strings.TrimSpace(message) == "" {
+				return "", fmt.Errorf("commit message is required. Use -m flag or enable AI generation")
+			}
+			subject := strings.SplitN(strings.TrimSpace(message), "\n", 2)[0]
+			return fmt.Sprintf("subject %d chars", ui.StringWidth(subject)), nil
+		}},
+		{Name: "Check spelling", Run: func(ctx context.Context) (string, error) {
+			if opts.noSpellcheck {
+				return "", pipeline.Skip("--no-spellcheck")
+			}
+			if !d.Config.Get().Spell.Enabled {
+				return "", pipeline.Skip("disabled in config")
+			}
+			checked, detail, err := checkSpelling(ctx, d, message)
+			if err != nil {
+				return "", err
+			}
+			message = checked
+			return detail, nil
+		}},
+		{Name: "Create commit", Run: func(ctx context.Context) (string, error) {
+			if opts.dryRun {
+				return "", pipeline.Skip("dry run")
+			}
+			done, err := protectIndex(d, gitClient)
+			if err != nil {
+				return "", err
+			}
+			defer done()
 
-			generatedMessage, err := commitgenService.GenerateCommitMessage(stagedDiff, provider)
+			obj, err := gitClient.Commit(message, commitOpts)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "error: %s provider failed: %v\n", provider.Name, err)
-				fmt.Printf("Hint: Ensure %s is set or change provider in config file\n", provider.EnvName)
-				os.Exit(1)
+				return "", fmt.Errorf("failed to create commit: %w", err)
 			}
+			commitObj = obj
+			recordMetric(d, func(r metrics.Recorder) error { return r.Commit(messageSource(d, opts, kept != "")) })
+			if opts.changelist != "" {
+				if err := dropFromChangelists(gitClient, stagedFiles); err != nil {
+					// The commit is made; the changelist can be fixed by hand.
+					return obj.Hash.String()[:7] + ", changelist not updated: " + err.Error(), nil
+				}
+			}
+			return obj.Hash.String()[:7], nil
+		}},
+		{Name: "Record environment", Run: func(ctx context.Context) (string, error) {
+			notes := d.Config.Get().Notes
+			if !notes.Environment {
+				return "", pipeline.Skip("disabled in config")
+			}
+			if opts.dryRun {
+				return "", pipeline.Skip("dry run")
+			}
+			if err := gitClient.AddNote(notes.Ref, commitObj.Hash, environmentNote(gitClient)); err != nil {
+				// The commit is made; a missing note is not worth failing it.
+				return "not recorded: " + err.Error(), nil
+			}
+			return "note in " + notes.Ref, nil
+		}},
+	}
 
-			message = generatedMessage
-			fmt.Printf("Generated message: %s\n\n", message)
-		} else if message == "" {
-			fmt.Fprintf(os.Stderr, "error: commit message is required. Use -m flag or enable AI generation\n")
-			os.Exit(1)
+	err = runPipeline(ctx, d, stages)
+	switch {
+	case errors.Is(err, errNothingStaged) && d.Output.JSON():
+		// Tools read an empty stdout with exit status 0 as a commit.
+		return fmt.Errorf("%w; use 'bgit add' to stage files first", err)
+	case errors.Is(err, errNothingStaged):
+		fmt.Fprintln(d.IO.Out, "No staged files to commit. Use 'bgit add' to stage files first.")
+		return nil
+	case errors.Is(err, errConflictMarkers):
+		d.flushOut()
+		items := make([]string, 0, len(markers))
+		for _, m := range markers {
+			items = append(items, fmt.Sprintf("%s:%d: %s", m.Path, m.Line, m.Text))
 		}
+		fmt.Fprint(d.IO.ErrOut, ui.RenderConflictMarkers(items, ui.TerminalWidth(d.IO.ErrOut)))
+		return err
+	case errors.Is(err, errTasksFailed):
+		d.flushOut()
+		fmt.Fprint(d.IO.ErrOut, ui.RenderTaskResults(taskResults(taskRuns), ui.TerminalWidth(d.IO.ErrOut)))
+		return err
+	case errors.Is(err, errTestsFailed):
+		d.flushOut()
+		fmt.Fprint(d.IO.ErrOut, ui.RenderTestResults(taskResults(testRuns), ui.TerminalWidth(d.IO.ErrOut)))
+		return fmt.Errorf("%w (commit without --verify-tests to skip them)", err)
+	case errors.Is(err, errRiskTooHigh):
+		d.flushOut()
+		fmt.Fprint(d.IO.ErrOut, ui.RenderRisk(riskView(*risk), ui.TerminalWidth(d.IO.ErrOut)))
+		return fmt.Errorf("%w: risk.confirm_at is %d; commit on a terminal to confirm, or use --accept-risk", err, d.Config.Get().Risk.ConfirmAt)
+	case errors.Is(err, errCommitAborted) && d.Output.JSON():
+		return err
+	case errors.Is(err, errCommitAborted):
+		fmt.Fprintln(d.IO.Out, "Commit aborted; nothing was committed.")
+		return nil
+	case err != nil && providerFailed:
+		d.flushOut()
+		fmt.Fprintf(d.IO.ErrOut, "Hint: Ensure %s is set or change provider in config file\n", provider.EnvName)
+		return err
+	case err != nil:
+		return err
+	}
 
-		if dryRun {
-			fmt.Println("=== DRY RUN ===")
-			fmt.Printf("Would commit with message: %s\n", message)
-			return
+	if d.Output.JSON() {
+		result := commitResult{
+			Message:    message,
+			Source:     messageSource(d, opts, kept != ""),
+			DurationMS: time.Since(start).Milliseconds(),
+			DryRun:     opts.dryRun,
+			Risk:       risk,
+			Owners:     owners,
+			Reviewers:  ownersService.Reviewers(owners),
 		}
+		if result.Source == "ai" {
+			result.AI = &commitAI{Provider: provider.Name, Model: string(commitgenService.Model)}
+		}
+		return printCommitJSON(d, gitClient, result, stagedFiles, commitObj)
+	}
+	d.infoln()
+
+	if opts.dryRun {
+		fmt.Fprintln(d.IO.Out, "=== DRY RUN ===")
+		fmt.Fprintf(d.IO.Out, "Would commit with message: %s\n", message)
+	} else {
+		stats, _ := gitClient.CommitStats(commitObj) // the summary is fine without them
+		printCommitSummary(d, commitObj, stats, generatedFiles(d, gitClient, stats, opts.generated))
+	}
+	if ownersWarn != "" {
+		d.infof("\n%s%s\n", ui.Icon("⚠️"), ownersWarn)
+	}
+	return nil
+}
+
+// promptDiff is the staged diff as the AI provider is shown it.
+type promptDiff struct {
+	diff *commitgenService.Diff
+	// omitted lists the generated files whose contents were left out.
+	omitted []string
+}
+
+// promptDiffRepo is what building the diff for a prompt reads.
+type promptDiffRepo interface {
+	generatedRepo
+	EachStagedFileDiff(stagedFiles []string, fn func(gitService.FileDiff) error) error
+}
 
-		// Perform the actual commit
-		err = gitClient.Commit(message)
+// buildPromptDiff diffs the staged files for the AI provider: generated
+// files are named but their contents left out (unless includeGenerated),
+// binary files are only named, and the rest is shortened to the ai.* limits.
+// The diff is streamed file by file into the shortening, so a very large
+// change is never held whole.
+func buildPromptDiff(d *Deps, client promptDiffRepo, staged []string, includeGenerated bool) (promptDiff, error) {
+	files, omitted := staged, []string(nil)
+	if !includeGenerated {
+		generated, err := client.GeneratedFiles(staged, d.Config.Get().Generated.Patterns)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "error: failed to create commit: %v\n", err)
-			os.Exit(1)
+			return promptDiff{}, fmt.Errorf("failed to detect generated files: %w", err)
+		}
+		files = nil
+		for _, f := range staged {
+			if generated[f] {
+				omitted = append(omitted, f)
+			} else {
+				files = append(files, f)
+			}
 		}
-	},
+	}
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	var binary []string // complete once pr is read to the end
+	go func() {
+		pw.CloseWithError(client.EachStagedFileDiff(files, func(fd gitService.FileDiff) error {
+			if fd.Binary {
+				binary = append(binary, fd.Path)
+				return nil
+			}
+			_, err := io.WriteString(pw, fd.Patch)
+			return err
+		}))
+	}()
+	diff, err := commitgenService.ReadDiff(pr, promptLimits(d))
+	if err != nil {
+		return promptDiff{}, fmt.Errorf("failed to get staged diff: %w", err)
+	}
+	if len(omitted) > 0 {
+		// Name them so a lockfile-only change still gets a fitting message.
+		diff.Note("\nGenerated files also changed (contents omitted): " + strings.Join(omitted, ", ") + "\n")
+	}
+	if len(binary) > 0 {
+		diff.Note("\nBinary files also changed: " + strings.Join(binary, ", ") + "\n")
+	}
+	return promptDiff{diff: diff, omitted: omitted}, nil
 }
 
-func init() {
-	rootCmd.AddCommand(commitCmd)
-	commitCmd.Flags().StringP("message", "m", "", "Commit message (if omitted uses AI or heuristic)")
-	commitCmd.Flags().Bool("dry-run", false, "Preview commit without creating it")
-	commitCmd.Flags().Bool("no-ai", false, "Disable AI commit message generation")
+// promptLimits are the ai.* limits on the diff sent to an AI provider.
+func promptLimits(d *Deps) commitgenService.Limits {
+	limits := d.Config.Get().AI
+	return commitgenService.Limits{
+		MaxBytes:        limits.MaxDiffBytes,
+		PerFileMaxLines: limits.PerFileMaxLines,
+	}
+}
+
+// messageSource names where the commit message came from, for metrics;
+// the message git wrote for a merge, or that of an amended commit, counts as
+// given.
+func messageSource(d *Deps, opts *commitOptions, kept bool) string {
+	switch {
+	case opts.message != "" || kept:
+		return "message"
+	case d.Offline:
+		return "offline"
+	}
+	return "ai"
+}
+
+// plural formats a count with a naively pluralized noun.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// printCommitSummary reports the freshly created commit and what it changed.
+// Quiet mode reduces it to the short hash and subject.
+func printCommitSummary(d *Deps, commitObj *object.Commit, stats []gitService.FileStat, generated map[string]bool) {
+	if d.Output.Quiet {
+		fmt.Fprintf(d.IO.Out, "%s %s\n", commitObj.Hash.String()[:7], strings.SplitN(commitObj.Message, "\n", 2)[0])
+		return
+	}
+
+	view := ui.CommitView{
+		Hash:      commitObj.Hash.String(),
+		Author:    ui.Person{Name: commitObj.Author.Name, Email: commitObj.Author.Email},
+		Committer: ui.Person{Name: commitObj.Committer.Name, Email: commitObj.Committer.Email},
+		When:      commitObj.Author.When,
+		Message:   commitObj.Message,
+		Stats:     uiStats(stats, generated),
+	}
+	fmt.Fprint(d.IO.Out, ui.RenderCommitSummary(view, ui.TerminalWidth(d.IO.Out)))
+}
+
+// commitResult is what 'bgit commit --output json' prints.
+type commitResult struct {
+	// Hash is empty on a dry run.
+	Hash    string     `json:"hash"`
+	Message string     `json:"message"`
+	Author  commitUser `json:"author"`
+	// When is the author date; zero on a dry run.
+	When       time.Time     `json:"date"`
+	Files      []ui.FileStat `json:"files"`
+	Insertions int           `json:"insertions"`
+	Deletions  int           `json:"deletions"`
+	// Source is where the message came from: ai, offline or message.
+	Source string `json:"source"`
+	// AI names the provider and model that wrote the message, when one did.
+	AI *commitAI `json:"ai,omitempty"`
+	// Risk is the risk score of the change, unless risk.enabled is off.
+	Risk *commitRisk `json:"risk,omitempty"`
+	// Owners groups the committed files by their code owners, and Reviewers
+	// lists those owners once each, for the pull request of the change.
+	// Both are left out without a CODEOWNERS file.
+	Owners    []ownersService.Area `json:"owners,omitempty"`
+	Reviewers []string             `json:"reviewers,omitempty"`
+	// DurationMS is how long the command took, in milliseconds.
+	DurationMS int64 `json:"duration_ms"`
+	DryRun     bool  `json:"dry_run"`
+}
+
+type commitUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type commitAI struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+}
+
+// printCommitJSON completes result from the commit just made (or, on a dry
+// run, from the staged changes) and prints it on standard output.
+func printCommitJSON(d *Deps, client commitRepo, result commitResult, staged []string, commitObj *object.Commit) error {
+	var (
+		stats []gitService.FileStat
+		err   error
+	)
+	if commitObj != nil {
+		result.Hash = commitObj.Hash.String()
+		result.Message = commitObj.Message
+		result.Author = commitUser{Name: commitObj.Author.Name, Email: commitObj.Author.Email}
+		result.When = commitObj.Author.When
+		stats, err = client.CommitStats(commitObj)
+	} else {
+		stats, err = client.DiffStats(true, staged)
+	}
+	if err != nil {
+		// The result is still worth printing without the numbers.
+		d.Log.Debug("commit stats unavailable", "err", err)
+	}
+
+	result.Files = uiStats(stats, generatedFiles(d, client, stats, false))
+	for _, st := range stats {
+		result.Insertions += st.Insertions
+		result.Deletions += st.Deletions
+	}
+	return json.NewEncoder(d.IO.Out).Encode(result)
 }
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/endalk200/bgit/internal/config"
+	"github.com/endalk200/bgit/internal/interrupt"
+	commitgenService "github.com/endalk200/bgit/internal/services/commitgen"
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+)
+
+// fixedConfig is a ConfigStore that only reads, always cfg.
+type fixedConfig struct {
+	ConfigStore
+	cfg *config.Config
+}
+
+func (c fixedConfig) Get() *config.Config { return c.cfg }
+
+func TestCommitOptionsForMaxBackdate(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC)
+	cfg := config.Defaults()
+	cfg.Commit.MaxBackdate = 7 * 24 * time.Hour
+	d := &Deps{Config: fixedConfig{cfg: cfg}}
+
+	tests := []struct {
+		name    string
+		opts    commitOptions
+		refused bool
+	}{
+		{"within the limit", commitOptions{date: "6 days ago"}, false},
+		{"author date too far back", commitOptions{date: "8 days ago"}, true},
+		{"committer date too far back", commitOptions{committerDate: "2025-01-01"}, true},
+		{"allowed", commitOptions{date: "2025-01-01", committerDate: "2025-01-01", allowBackdate: true}, false},
+		// Only how far back counts.
+		{"in the future", commitOptions{date: "2026-01-01"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := commitOptionsFor(d, &tt.opts, now)
+			if refused := errors.Is(err, errBackdated); refused != tt.refused {
+				t.Errorf("commitOptionsFor refused = %v (%v), want %v", refused, err, tt.refused)
+			}
+			if !tt.refused && err != nil {
+				t.Errorf("commitOptionsFor: %v", err)
+			}
+		})
+	}
+
+	t.Run("no limit", func(t *testing.T) {
+		cfg := config.Defaults()
+		cfg.Commit.MaxBackdate = 0
+		options, err := commitOptionsFor(&Deps{Config: fixedConfig{cfg: cfg}}, &commitOptions{date: "2000-01-01"}, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC); !options.AuthorDate.Equal(want) {
+			t.Errorf("AuthorDate = %v, want %v", options.AuthorDate, want)
+		}
+	})
+}
+
+// fakeCommitRepo is a repository with staged changes for runCommit. It
+// implements the part of commitRepo a plain commit reaches; the GitService
+// it embeds is nil, so reaching any other method fails the test.
+type fakeCommitRepo struct {
+	GitService
+	// staged maps the staged files to their patches.
+	staged    map[string]string
+	committed []string
+}
+
+func (r *fakeCommitRepo) MergeMessage() (string, bool) { return "", false }
+
+func (r *fakeCommitRepo) StagedFiles() ([]string, error) {
+	var files []string
+	for path := range r.staged {
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+func (r *fakeCommitRepo) StagedConflictMarkers([]string) ([]gitService.MarkerHit, error) {
+	return nil, nil
+}
+
+func (r *fakeCommitRepo) WorktreeFile(string) ([]byte, error) { return nil, fs.ErrNotExist }
+
+func (r *fakeCommitRepo) GeneratedFiles([]string, []string) (map[string]bool, error) {
+	return map[string]bool{}, nil
+}
+
+func (r *fakeCommitRepo) EachStagedFileDiff(files []string, fn func(gitService.FileDiff) error) error {
+	for _, path := range files {
+		if err := fn(gitService.FileDiff{Path: path, Patch: r.staged[path]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakeCommitRepo) BackupIndex() (*gitService.IndexBackup, error) {
+	return &gitService.IndexBackup{}, nil
+}
+
+func (r *fakeCommitRepo) Commit(message string, _ gitService.CommitOptions) (*object.Commit, error) {
+	r.committed = append(r.committed, message)
+	return &object.Commit{Hash: plumbing.NewHash("4b825dc642cb6eb9a060e54bf8d69288fbee4904"), Message: message}, nil
+}
+
+func (r *fakeCommitRepo) CommitStats(*object.Commit) ([]gitService.FileStat, error) { return nil, nil }
+
+func TestRunCommitGeneratesMessage(t *testing.T) {
+	patch := "--- a/greet.go\n+++ b/greet.go\n@@ -1 +1 @@\n-hello\n+hello, world\n"
+	repo := &fakeCommitRepo{staged: map[string]string{"greet.go": patch}}
+	cfg := config.Defaults()
+	cfg.Spell.Enabled = false
+	cfg.Risk.Enabled = false
+	cfg.Issue.Reference = false
+	cfg.Metrics.Enabled = false
+
+	var prompted string
+	var out bytes.Buffer
+	d := &Deps{
+		IO:     IOStreams{In: strings.NewReader(""), Out: &out, ErrOut: io.Discard},
+		Config: fixedConfig{cfg: cfg},
+		CommitGen: CommitGeneratorFunc(func(_ context.Context, diff *commitgenService.Diff, _ config.Provider) (string, error) {
+			prompted = diff.String()
+			return "feat: greet the world", nil
+		}),
+		Interrupt: &interrupt.Handler{},
+		Log:       log.New(io.Discard),
+		OpenRepo:  func() (GitService, error) { return repo, nil },
+	}
+
+	if err := runCommit(context.Background(), d, &commitOptions{}); err != nil {
+		t.Fatalf("runCommit: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(prompted, "+hello, world") {
+		t.Errorf("the generator was given %q, want the staged diff", prompted)
+	}
+	if want := []string{"feat: greet the world"}; !slices.Equal(repo.committed, want) {
+		t.Errorf("committed %q, want %q", repo.committed, want)
+	}
+}
@@ -1,38 +1,277 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/charmbracelet/huh"
 	"github.com/endalk200/bgit/internal/config"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/settings"
 	"github.com/spf13/cobra"
 )
 
-var configCmd = &cobra.Command{
-	Use:   "config",
-	Short: "Manage bgit configuration",
-	Long: `View and manage bgit configuration settings.
+func newConfigCmd(d *Deps) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage bgit configuration",
+		Long: `View and manage bgit configuration settings.
 
-Configuration is stored in ~/.bgit.yaml by default.
+Configuration is stored in ~/.bgit.yaml by default, with the settings in
+.bgit.yaml in the current directory (the root of a repository) on top.
 You can specify a custom config file with --config flag.`,
+	}
+
+	configCmd.AddCommand(
+		newConfigInitCmd(d),
+		newConfigEditCmd(d),
+		newConfigViewCmd(d),
+		newConfigSetProviderCmd(d),
+		newConfigListProvidersCmd(d),
+		newConfigRefreshCmd(d),
+		newConfigTrustCmd(d),
+	)
+
+	return configCmd
+}
+
+func newConfigInitCmd(d *Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:   "init",
+		Short: "Answer a few questions to set up bgit",
+		Long: `Walk through the main settings: the AI provider used for commit messages,
+the commit message spell-check and whether output uses emoji. Answers are
+saved to the config file straight away.
+
+It needs a terminal; scripts can use 'bgit config set-provider' instead.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !ui.IsInteractive(d.IO.In, d.IO.Out) {
+				return errors.New("config init needs a terminal; use 'bgit config set-provider' in scripts")
+			}
+			d.flushOut()
+			term, _ := ui.TerminalFile(d.IO.Out)
+			return runConfigInit(d, term)
+		},
+	}
+}
+
+// errSetupAborted is returned when the user backs out of a setup question.
+var errSetupAborted = errors.New("setup aborted; nothing more was changed")
+
+// runConfigInit asks for the main settings on term and saves them.
+func runConfigInit(d *Deps, term io.Writer) error {
+	cfg := d.Config.Get()
+	providerName := cfg.AIProvider.Name
+	spell := cfg.Spell.Enabled
+	emoji := !cfg.UI.NoEmoji
+
+	options := make([]huh.Option[string], 0, len(config.AvailableProviders))
+	for _, p := range config.AvailableProviders {
+		state := "not set"
+		if os.Getenv(p.EnvName) != "" {
+			state = "set"
+		}
+		options = append(options, huh.NewOption(fmt.Sprintf("%s (%s %s)", p.Name, p.EnvName, state), p.Name))
+	}
+
+	err := huh.NewForm(huh.NewGroup(
+		huh.NewSelect[string]().
+			Title("Which AI provider should write commit messages?").
+			Options(options...).
+			Value(&providerName),
+		huh.NewConfirm().
+			Title("Spell-check commit messages before committing?").
+			Value(&spell),
+		huh.NewConfirm().
+			Title("Use emoji and symbols in output?").
+			Value(&emoji),
+	)).WithInput(d.IO.In).WithOutput(term).Run()
+	if errors.Is(err, huh.ErrUserAborted) {
+		return errSetupAborted
+	}
+	if err != nil {
+		return err
+	}
+
+	var provider config.Provider
+	for _, p := range config.AvailableProviders {
+		if p.Name == providerName {
+			provider = p
+		}
+	}
+	if err := d.Config.SetProvider(provider.Name, provider.EnvName); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	if err := d.Config.Set("spell.enabled", spell); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	if err := d.Config.Set("ui.no_emoji", !emoji); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	ui.SetPlain(!emoji)
+
+	fmt.Fprintf(term, "%sSaved to %s\n", ui.Icon("✓"), d.Config.Path())
+	if os.Getenv(provider.EnvName) == "" {
+		fmt.Fprintf(term, "  %s is not set. Add it to your shell profile, e.g.\n", provider.EnvName)
+		fmt.Fprintf(term, "    export %s=\"...\"\n", provider.EnvName)
+	}
+	return nil
+}
+
+func newConfigEditCmd(d *Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Edit every setting in a full-screen editor",
+		Long: `Edit every setting in a full-screen editor. Settings are listed by section
+with the value in effect and where it comes from: the repo file
+(./.bgit.yaml), the global file (~/.bgit.yaml), a shared file under extends,
+or the built-in default.
+
+Values are checked as they are typed. Edits are saved, with w, to the file
+shown at the top: the global file, or after tab the repo file, which is
+created if need be. x removes a setting from that file so the value below it
+applies again. The environment variable holding the AI provider key is shown
+with its value masked. Post-processing steps and pre-commit tasks are edited
+in the file itself.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !ui.IsInteractive(d.IO.In, d.IO.Out) {
+				return errors.New("config edit needs a terminal; use 'bgit config set-provider' or edit the file in scripts")
+			}
+			d.flushOut()
+			term, _ := ui.TerminalFile(d.IO.Out)
+			return runConfigEdit(d, term)
+		},
+	}
+}
+
+func runConfigEdit(d *Deps, term io.Writer) error {
+	layers := []settings.Layer{{Name: string(config.Global), Path: d.Config.LayerPath(config.Global)}}
+	if path := d.Config.LayerPath(config.Repo); path != "" {
+		layers = append(layers, settings.Layer{Name: string(config.Repo), Path: path})
+	}
+
+	saved, err := settings.Run(term, d.IO.In, settingFields(d), settings.Options{
+		Layers: layers,
+		Validate: func(key, value string) error {
+			_, err := lookupSetting(key).Parse(value)
+			return err
+		},
+		Save: func(changes []settings.Change) ([]settings.Field, error) {
+			if err := saveSettings(d, changes); err != nil {
+				return nil, err
+			}
+			return settingFields(d), nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range saved {
+		if c.Unset {
+			d.infof("%sRemoved %s from %s\n", ui.Icon("✓"), c.Key, d.Config.LayerPath(config.Layer(c.Layer)))
+		} else {
+			d.infof("%sSet %s to %q in %s\n", ui.Icon("✓"), c.Key, c.Value, d.Config.LayerPath(config.Layer(c.Layer)))
+		}
+	}
+	return nil
+}
+
+// settingFields lists every setting with its value in effect.
+func settingFields(d *Deps) []settings.Field {
+	fields := make([]settings.Field, 0, len(config.Settings))
+	for _, s := range config.Settings {
+		fields = append(fields, settings.Field{
+			Key:       s.Key,
+			Section:   s.Section,
+			Help:      s.Help,
+			Value:     d.Config.Current(s),
+			Origin:    d.Config.Origin(s.Key),
+			Bool:      s.Kind == config.KindBool,
+			Choices:   s.Choices,
+			ReadOnly:  s.Kind == config.KindStructured,
+			SecretEnv: s.SecretEnv,
+		})
+	}
+	return fields
+}
+
+func lookupSetting(key string) config.Setting {
+	for _, s := range config.Settings {
+		if s.Key == key {
+			return s
+		}
+	}
+	return config.Setting{Key: key, Kind: config.KindString}
+}
+
+// saveSettings writes changes to their files. Picking another provider
+// also points the key variable at that provider's, unless it is changed
+// too, as 'bgit config set-provider' does.
+func saveSettings(d *Deps, changes []settings.Change) error {
+	envChanged := false
+	for _, c := range changes {
+		envChanged = envChanged || c.Key == "ai_provider.env_name"
+	}
+	for _, c := range changes {
+		layer := config.Layer(c.Layer)
+		if c.Unset {
+			if err := d.Config.Unset(layer, c.Key); err != nil {
+				return err
+			}
+			continue
+		}
+		value, err := lookupSetting(c.Key).Parse(c.Value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.Key, err)
+		}
+		if err := d.Config.SetIn(layer, c.Key, value); err != nil {
+			return err
+		}
+		if c.Key != "ai_provider.name" || envChanged {
+			continue
+		}
+		for _, p := range config.AvailableProviders {
+			if p.Name == value {
+				if err := d.Config.SetIn(layer, "ai_provider.env_name", p.EnvName); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
 }
 
-var configViewCmd = &cobra.Command{
-	Use:   "view",
-	Short: "View current configuration",
-	Run: func(cmd *cobra.Command, args []string) {
-		cfg := config.GetConfig()
-		fmt.Println("Current Configuration:")
-		fmt.Println("======================")
-		fmt.Printf("AI Provider: %s\n", cfg.AIProvider.Name)
-		fmt.Printf("Environment Variable: %s\n", cfg.AIProvider.EnvName)
-	},
+func newConfigViewCmd(d *Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:         "view",
+		Short:       "View current configuration",
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := d.IO.Out
+			cfg := d.Config.Get()
+			if d.Output.JSON() {
+				return json.NewEncoder(out).Encode(cfg)
+			}
+			fmt.Fprintln(out, "Current Configuration:")
+			fmt.Fprintln(out, "======================")
+			fmt.Fprintf(out, "AI Provider: %s\n", cfg.AIProvider.Name)
+			fmt.Fprintf(out, "Environment Variable: %s\n", cfg.AIProvider.EnvName)
+			return nil
+		},
+	}
 }
 
-var configSetProviderCmd = &cobra.Command{
-	Use:   "set-provider [provider-name]",
-	Short: "Set the AI provider",
-	Long: `Set the AI provider for commit message generation.
+func newConfigSetProviderCmd(d *Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-provider [provider-name]",
+		Short: "Set the AI provider",
+		Long: `Set the AI provider for commit message generation.
 
 Available providers:
   - OpenAI (uses OPENAI_API_KEY)
@@ -41,62 +280,134 @@ Available providers:
 
 Example:
   bgit config set-provider OpenRouter`,
-	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		providerName := args[0]
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigSetProvider(d, args[0])
+		},
+	}
+}
+
+func runConfigSetProvider(d *Deps, providerName string) error {
+	// Find the provider in available providers
+	var found bool
+	var provider config.Provider
+	for _, p := range config.AvailableProviders {
+		if p.Name == providerName {
+			found = true
+			provider = p
+			break
+		}
+	}
 
-		// Find the provider in available providers
-		var found bool
-		var provider config.Provider
+	if !found {
+		fmt.Fprintln(d.IO.ErrOut, "Available providers:")
 		for _, p := range config.AvailableProviders {
-			if p.Name == providerName {
-				found = true
-				provider = p
-				break
-			}
+			fmt.Fprintf(d.IO.ErrOut, "  - %s (env: %s)\n", p.Name, p.EnvName)
 		}
+		return fmt.Errorf("unknown provider '%s'", providerName)
+	}
+
+	if err := d.Config.SetProvider(provider.Name, provider.EnvName); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+
+	d.infof("%sSuccessfully set AI provider to: %s\n", ui.Icon("✓"), provider.Name)
+	d.infof("  Environment variable: %s\n", provider.EnvName)
+	return nil
+}
 
-		if !found {
-			fmt.Fprintf(os.Stderr, "error: unknown provider '%s'\n\n", providerName)
-			fmt.Println("Available providers:")
+func newConfigListProvidersCmd(d *Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list-providers",
+		Short: "List available AI providers",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := d.IO.Out
+			fmt.Fprintln(out, "Available AI Providers:")
+			fmt.Fprintln(out, "=======================")
+			currentProvider := d.Config.Get().AIProvider
 			for _, p := range config.AvailableProviders {
-				fmt.Printf("  - %s (env: %s)\n", p.Name, p.EnvName)
+				current := ""
+				if p.Name == currentProvider.Name {
+					current = " (current)"
+				}
+				fmt.Fprintf(out, "  %s %s%s\n", ui.Bullet(), p.Name, current)
+				fmt.Fprintf(out, "    Environment Variable: %s\n", p.EnvName)
 			}
-			os.Exit(1)
-		}
-
-		err := config.SetProvider(provider.Name, provider.EnvName)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "error: failed to update config: %v\n", err)
-			os.Exit(1)
-		}
+			return nil
+		},
+	}
+}
 
-		fmt.Printf("✓ Successfully set AI provider to: %s\n", provider.Name)
-		fmt.Printf("  Environment variable: %s\n", provider.EnvName)
-	},
+func newConfigRefreshCmd(d *Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:   "refresh",
+		Short: "Fetch the shared config files named under extends again",
+		Long: `Fetch the shared config files named under extends again. They are cached
+after the first fetch and read from the cache from then on, so changes made
+to them upstream only arrive with this command.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if d.Offline {
+				return errOffline
+			}
+			if len(d.Config.Get().Extends) == 0 {
+				d.infoln("The config extends no shared files.")
+				return nil
+			}
+			refreshed, err := d.Config.RefreshExtends()
+			for _, source := range refreshed {
+				d.infof("%sRefreshed %s\n", ui.Icon("✓"), source)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to refresh: %w", err)
+			}
+			return nil
+		},
+	}
 }
 
-var configListProvidersCmd = &cobra.Command{
-	Use:   "list-providers",
-	Short: "List available AI providers",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Available AI Providers:")
-		fmt.Println("=======================")
-		currentProvider := config.GetProvider()
-		for _, p := range config.AvailableProviders {
-			current := ""
-			if p.Name == currentProvider.Name {
-				current = " (current)"
+func newConfigTrustCmd(d *Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:   "trust",
+		Short: "Let the repo config file and shared files run their commands",
+		Long: `Let the repo config file (./.bgit.yaml) and the shared files under extends
+run the commands they set: pre-commit tasks, post-processing commands and
+test commands. Until then bgit leaves them out and warns, so that running
+bgit in a repository you cloned, or after a shared file changed upstream,
+never runs commands you have not read.
+
+Read the commands listed before trusting them. Files are trusted as they are
+now: a hash of each is kept in the global file, and once anything but bgit
+changes one, its commands are left out again until it is trusted anew.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			trusted, err := d.Config.Trust()
+			if err != nil {
+				return fmt.Errorf("failed to trust: %w", err)
 			}
-			fmt.Printf("  • %s%s\n", p.Name, current)
-			fmt.Printf("    Environment Variable: %s\n", p.EnvName)
-		}
-	},
+			if len(trusted) == 0 {
+				d.infoln("No config file in use sets commands that are left out.")
+				return nil
+			}
+			for _, u := range trusted {
+				d.infof("%sTrusted %s, which runs:\n", ui.Icon("✓"), u.Source)
+				for _, c := range u.Commands {
+					d.infof("    %s\n", c)
+				}
+			}
+			return nil
+		},
+	}
 }
 
-func init() {
-	rootCmd.AddCommand(configCmd)
-	configCmd.AddCommand(configViewCmd)
-	configCmd.AddCommand(configSetProviderCmd)
-	configCmd.AddCommand(configListProvidersCmd)
+// warnUntrusted tells which commands set in config files are left out
+// because the files are not trusted.
+func warnUntrusted(d *Deps) {
+	for _, u := range d.Config.Untrusted() {
+		fmt.Fprintf(d.IO.ErrOut, "warning: %s is not trusted, so these commands it sets are left out:\n", u.Source)
+		for _, c := range u.Commands {
+			fmt.Fprintf(d.IO.ErrOut, "    %s\n", c)
+		}
+		fmt.Fprintln(d.IO.ErrOut, "  Read them, then run 'bgit config trust' to let them run.")
+	}
 }
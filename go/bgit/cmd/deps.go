@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/log"
+	"github.com/endalk200/bgit/internal/config"
+	"github.com/endalk200/bgit/internal/interrupt"
+	commitgenService "github.com/endalk200/bgit/internal/services/commitgen"
+	forgeService "github.com/endalk200/bgit/internal/services/forge"
+	gitService "github.com/endalk200/bgit/internal/services/git"
+)
+
+// GitService is every repository operation the commands use. Each command
+// declares the part it uses as an interface of its own beside it (commitRepo
+// for commit, and so on) and opens the repository as that with openRepo, so
+// a test fakes only what the command calls. The concrete implementation
+// lives in internal/services/git.
+type GitService interface {
+	activityRepo
+	addRepo
+	baseRepo
+	blameRepo
+	branchRepo
+	branchesRepo
+	changelistRepo
+	cherryPickRepo
+	ciRepo
+	commitRepo
+	diffRepo
+	explainRepo
+	fetchRepo
+	graphRepo
+	hookRepo
+	hookInstallRepo
+	issueRepo
+	keepRepo
+	logRepo
+	mergeRepo
+	optimizeRepo
+	pullRepo
+	pushRepo
+	rebaseRepo
+	releaseRepo
+	remoteRepo
+	resetRepo
+	resolveRepo
+	showRepo
+	stashRepo
+	statusRepo
+	switchRepo
+	tagRepo
+	testsRepo
+}
+
+// Forge is the hosting service (GitHub) behind the repository's origin.
+type Forge interface {
+	Repo() forgeService.Repo
+	OpenPullRequests(ctx context.Context) ([]forgeService.PullRequest, error)
+	WorkflowRuns(ctx context.Context, filter forgeService.RunFilter) ([]forgeService.WorkflowRun, error)
+	RunJobs(ctx context.Context, runID int64) ([]forgeService.Job, error)
+	Job(ctx context.Context, jobID int64) (forgeService.Job, error)
+	JobLog(ctx context.Context, jobID int64) (io.ReadCloser, error)
+	RerunFailedJobs(ctx context.Context, runID int64) error
+	Issues(ctx context.Context, filter forgeService.IssueFilter) ([]forgeService.Issue, error)
+	Issue(ctx context.Context, number int) (forgeService.Issue, error)
+	AssignIssue(ctx context.Context, number int, login string) error
+	CurrentUser(ctx context.Context) (string, error)
+	ReleaseForge
+	CommitChecks(ctx context.Context, sha string) ([]forgeService.Check, error)
+	PullRequestFor(ctx context.Context, branch string) (forgeService.PullRequest, bool, error)
+	EnableAutoMerge(ctx context.Context, pr forgeService.PullRequest, method string) error
+}
+
+// ForgeRepo is what finding the forge needs from the repository: the URL of
+// its remotes.
+type ForgeRepo interface {
+	RemoteURL(name string) (string, error)
+}
+
+// ReleaseForge is the part of a forge that publishes releases, which bgit
+// can do on GitLab as well as GitHub.
+type ReleaseForge interface {
+	Repo() forgeService.Repo
+	CompareURL(from, to string) string
+	ReleaseByTag(ctx context.Context, tag string) (forgeService.Release, bool, error)
+	CreateRelease(ctx context.Context, release forgeService.NewRelease) (forgeService.Release, error)
+	UploadAsset(ctx context.Context, release forgeService.Release, name string, content io.Reader, size int64) error
+}
+
+// CommitGenerator produces a commit message for a diff using an AI provider.
+type CommitGenerator interface {
+	GenerateCommitMessage(ctx context.Context, diff *commitgenService.Diff, provider config.Provider) (string, error)
+}
+
+// CommitGeneratorFunc adapts a plain function to the CommitGenerator interface.
+type CommitGeneratorFunc func(ctx context.Context, diff *commitgenService.Diff, provider config.Provider) (string, error)
+
+// GenerateCommitMessage calls f(ctx, diff, provider).
+func (f CommitGeneratorFunc) GenerateCommitMessage(ctx context.Context, diff *commitgenService.Diff, provider config.Provider) (string, error) {
+	return f(ctx, diff, provider)
+}
+
+// Explainer asks an AI provider to explain a change in plain language.
+type Explainer interface {
+	Explain(ctx context.Context, about string, diff *commitgenService.Diff, depth commitgenService.Depth, provider config.Provider) (string, error)
+}
+
+// ExplainerFunc adapts a plain function to the Explainer interface.
+type ExplainerFunc func(ctx context.Context, about string, diff *commitgenService.Diff, depth commitgenService.Depth, provider config.Provider) (string, error)
+
+// Explain calls f(ctx, about, diff, depth, provider).
+func (f ExplainerFunc) Explain(ctx context.Context, about string, diff *commitgenService.Diff, depth commitgenService.Depth, provider config.Provider) (string, error) {
+	return f(ctx, about, diff, depth, provider)
+}
+
+// RiskRater asks an AI provider how risky a change is, from 0 to 100.
+type RiskRater interface {
+	RateRisk(ctx context.Context, diff *commitgenService.Diff, findings string, provider config.Provider) (int, string, error)
+}
+
+// RiskRaterFunc adapts a plain function to the RiskRater interface.
+type RiskRaterFunc func(ctx context.Context, diff *commitgenService.Diff, findings string, provider config.Provider) (int, string, error)
+
+// RateRisk calls f(ctx, diff, findings, provider).
+func (f RiskRaterFunc) RateRisk(ctx context.Context, diff *commitgenService.Diff, findings string, provider config.Provider) (int, string, error) {
+	return f(ctx, diff, findings, provider)
+}
+
+// ConfigStore loads, reads and persists bgit configuration.
+type ConfigStore interface {
+	// Load reads the config file and the shared files it extends; offline,
+	// extended files are only read from the cache.
+	Load(cfgFile string, offline bool) error
+	Get() *config.Config
+	SetProvider(name, envName string) error
+	// Set updates one key, such as "spell.enabled", and saves the file.
+	Set(key string, value any) error
+	// SetIn saves one key in the file behind layer and reloads; Unset
+	// removes it from there.
+	SetIn(layer config.Layer, key string, value any) error
+	Unset(layer config.Layer, key string) error
+	// LayerPath is the file behind layer, or "" when it does not apply.
+	LayerPath(layer config.Layer) string
+	// Origin says where the value of key comes from: "repo", "global",
+	// "extends" or "default".
+	Origin(key string) string
+	// Current renders the value of setting in effect, as it is edited.
+	Current(setting config.Setting) string
+	// FirstRun reports whether Load had to create the config file.
+	FirstRun() bool
+	// Path is the config file in use.
+	Path() string
+	// RefreshExtends fetches the shared files under extends again.
+	RefreshExtends() ([]string, error)
+	// Untrusted lists the files whose commands were left out until they
+	// are trusted; Trust trusts them as they are now.
+	Untrusted() []config.Untrusted
+	Trust() ([]config.Untrusted, error)
+}
+
+// IOStreams bundles the standard streams so commands never touch os.Std*
+// directly.
+type IOStreams struct {
+	In     io.Reader
+	Out    io.Writer
+	ErrOut io.Writer
+}
+
+// Deps carries everything a command needs to run. It is built once by the
+// composition root in root.go and handed to every command constructor.
+type Deps struct {
+	IO        IOStreams
+	Config    ConfigStore
+	CommitGen CommitGenerator
+	Explainer Explainer
+	RiskRater RiskRater
+
+	// Interrupt runs registered cleanups when the user hits Ctrl-C.
+	Interrupt *interrupt.Handler
+
+	// Log is the diagnostic logger (stderr; debug level with --debug).
+	Log *log.Logger
+
+	// Output holds the global presentation flags for the running command.
+	// It is filled in by the output-options middleware.
+	Output OutputOptions
+
+	// Offline is set by --offline or BGIT_OFFLINE. The offline middleware
+	// then swaps the network-backed dependencies for ones that refuse.
+	Offline bool
+
+	// OpenRepo opens the repository for the current working directory. It is
+	// a function rather than a value because not every command needs a
+	// repository (e.g. config), and opening one outside a repo is an error.
+	OpenRepo func() (GitService, error)
+
+	// OpenForge connects to the forge hosting repo's origin remote. It fails
+	// with forgeService.ErrUnsupportedForge for hosts bgit cannot talk to.
+	OpenForge func(repo ForgeRepo) (Forge, error)
+	// OpenReleaseForge is OpenForge for publishing releases, which GitLab
+	// supports as well.
+	OpenReleaseForge func(repo ForgeRepo) (ReleaseForge, error)
+}
+
+// openRepo opens the repository as R, the operations the command uses.
+// GitService includes every such interface, so the repository always is an
+// R.
+func openRepo[R any](d *Deps) (R, error) {
+	var none R
+	repo, err := d.OpenRepo()
+	if err != nil {
+		return none, err
+	}
+	r, ok := repo.(R)
+	if !ok {
+		return none, fmt.Errorf("%T lacks operations the command uses", repo)
+	}
+	return r, nil
+}
+
+// indexRepo backs up the index.
+type indexRepo interface {
+	BackupIndex() (*gitService.IndexBackup, error)
+}
+
+// gitDirRepo locates files in the git directory, where bgit keeps its own
+// state.
+type gitDirRepo interface {
+	GitPath(name string) (string, error)
+}
+
+// protectIndex backs up the index and arranges for it to be restored if the
+// command is interrupted while rewriting it. Call done once the index write
+// has finished, successfully or not, to drop the backup.
+func protectIndex(d *Deps, repo indexRepo) (done func(), err error) {
+	backup, err := repo.BackupIndex()
+	if err != nil {
+		return nil, err
+	}
+	release := d.Interrupt.Register("restore index", backup.Restore)
+	return func() {
+		release()
+		_ = backup.Discard()
+	}, nil
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/pager"
+	"github.com/spf13/cobra"
+)
+
+type diffOptions struct {
+	staged  bool
+	noPager bool
+	stat    statOptions
+}
+
+func newDiffCmd(d *Deps) *cobra.Command {
+	opts := &diffOptions{}
+
+	diffCmd := &cobra.Command{
+		Use:   "diff [paths...]",
+		Short: "Show unstaged or staged changes",
+		Long: `Show changes in the working tree that are not staged yet, or with --staged
+the changes staged for the next commit. Paths limit the diff to those files
+and directories.
+
+The diff is computed by bgit itself rather than git, and colored with the
+code on each line highlighted for its language. In a terminal it opens in a
+pager, where n and N step from file to file; --no-pager prints it instead.
+
+--stat summarizes the changes per file with a histogram scaled to the
+terminal, leaving out generated files (lockfiles, minified and vendored code)
+unless --generated is given; --numstat prints the counts of every file
+tab-separated for scripts.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiff(d, args, opts)
+		},
+	}
+
+	diffCmd.Flags().BoolVar(&opts.staged, "staged", false, "Show changes staged for the next commit")
+	diffCmd.Flags().BoolVar(&opts.staged, "cached", false, "Synonym for --staged")
+	diffCmd.Flags().BoolVar(&opts.noPager, "no-pager", false, "Print the diff instead of opening the pager")
+	addStatFlags(diffCmd, &opts.stat)
+
+	return diffCmd
+}
+
+// diffRepo is what 'bgit diff' reads from the repository.
+type diffRepo interface {
+	generatedRepo
+	DiffStats(staged bool, paths []string) ([]gitService.FileStat, error)
+	Diff(staged bool, paths []string) (string, error)
+}
+
+func runDiff(d *Deps, paths []string, opts *diffOptions) error {
+	if err := opts.stat.validate(); err != nil {
+		return err
+	}
+	client, err := openRepo[diffRepo](d)
+	if err != nil {
+		return err
+	}
+
+	if opts.stat.any() {
+		stats, err := client.DiffStats(opts.staged, paths)
+		if err != nil {
+			return fmt.Errorf("failed to compute diffstat: %w", err)
+		}
+		printStats(d, client, opts.stat, stats)
+		return nil
+	}
+
+	diff, err := client.Diff(opts.staged, paths)
+	if err != nil {
+		return fmt.Errorf("failed to compute diff: %w", err)
+	}
+	files := ui.SplitPatch(diff)
+	sections := make([]string, 0, len(files))
+	for _, f := range files {
+		sections = append(sections, ui.RenderPatch(f))
+	}
+
+	term, ok := ui.TerminalFile(d.IO.Out)
+	if opts.noPager || len(sections) == 0 || !ok || !ui.IsInteractive(d.IO.In, d.IO.Out) {
+		fmt.Fprint(d.IO.Out, strings.Join(sections, ""))
+		return nil
+	}
+
+	title := "diff"
+	if opts.staged {
+		title += " --staged"
+	}
+	d.flushOut()
+	return pager.Run(term, d.IO.In, sections, pager.Options{Title: title, Noun: "file"})
+}
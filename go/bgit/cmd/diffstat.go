@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// statOptions are the diffstat flags shared by every command that shows
+// changes.
+type statOptions struct {
+	stat      bool
+	numstat   bool
+	generated bool
+}
+
+func addStatFlags(cmd *cobra.Command, opts *statOptions) {
+	cmd.Flags().BoolVar(&opts.stat, "stat", false, "Show a diffstat (changed lines per file) instead of the patch")
+	cmd.Flags().BoolVar(&opts.numstat, "numstat", false, "Show insertions and deletions per file as tab-separated numbers")
+	cmd.Flags().BoolVar(&opts.generated, "generated", false, "Include generated files (lockfiles, vendored code) in the diffstat")
+}
+
+// validate rejects asking for both formats at once.
+func (o statOptions) validate() error {
+	if o.stat && o.numstat {
+		return errors.New("--stat and --numstat cannot be used together")
+	}
+	return nil
+}
+
+// any reports whether a stat format replaces the patch.
+func (o statOptions) any() bool { return o.stat || o.numstat }
+
+// generatedRepo tells generated files apart.
+type generatedRepo interface {
+	GeneratedFiles(paths []string, extra []string) (map[string]bool, error)
+}
+
+// printStats writes stats in the selected format. --numstat is for scripts
+// and always lists every file.
+func printStats(d *Deps, client generatedRepo, opts statOptions, stats []gitService.FileStat) {
+	if opts.numstat {
+		fmt.Fprint(d.IO.Out, ui.RenderNumstat(uiStats(stats, nil)))
+		return
+	}
+	generated := generatedFiles(d, client, stats, opts.generated)
+	fmt.Fprint(d.IO.Out, ui.RenderDiffStat(uiStats(stats, generated), ui.TerminalWidth(d.IO.Out)))
+}
+
+// generatedFiles picks out the generated files among stats, or none when
+// include is set. A failed check hides nothing rather than failing the
+// command.
+func generatedFiles(d *Deps, client generatedRepo, stats []gitService.FileStat, include bool) map[string]bool {
+	if include || len(stats) == 0 {
+		return nil
+	}
+	paths := make([]string, 0, len(stats))
+	for _, st := range stats {
+		paths = append(paths, gitService.StatPath(st.Path))
+	}
+	generated, err := client.GeneratedFiles(paths, d.Config.Get().Generated.Patterns)
+	if err != nil {
+		d.Log.Debug("generated file check failed", "err", err)
+		return nil
+	}
+	return generated
+}
+
+func uiStats(stats []gitService.FileStat, generated map[string]bool) []ui.FileStat {
+	out := make([]ui.FileStat, 0, len(stats))
+	for _, st := range stats {
+		out = append(out, ui.FileStat{
+			Path:       st.Path,
+			Insertions: st.Insertions,
+			Deletions:  st.Deletions,
+			Binary:     st.Binary,
+			Generated:  generated[gitService.StatPath(st.Path)],
+		})
+	}
+	return out
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	commitgenService "github.com/endalk200/bgit/internal/services/commitgen"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/pipeline"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+type explainOptions struct {
+	staged    bool
+	depth     string
+	generated bool
+}
+
+func newExplainCmd(d *Deps) *cobra.Command {
+	opts := &explainOptions{}
+
+	explainCmd := &cobra.Command{
+		Use:   "explain [<commit> | --staged]",
+		Short: "Ask the AI provider to explain a commit or the staged changes",
+		Long: `Ask the configured AI provider to explain in plain language what a commit
+does, HEAD by default, or with --staged what the staged changes do: useful
+for reviewing code you do not know.
+
+--depth summary (the default) gives the purpose of the change, its notable
+parts and anything that looks risky; --depth lines walks through the change
+file by file and hunk by hunk.
+
+The diff is shortened to the ai.max_diff_bytes and ai.per_file_max_lines
+limits of the config before it is sent, and the contents of generated files
+in the staged changes are left out unless --generated is given, as for
+'bgit commit'. With --offline there is no one to ask and the command fails.`,
+		Example: `  bgit explain
+  bgit explain 3fd3808 --depth lines
+  bgit explain --staged`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rev := ""
+			if len(args) == 1 {
+				rev = args[0]
+			}
+			return runExplain(cmd.Context(), d, rev, opts)
+		},
+	}
+
+	explainCmd.Flags().BoolVar(&opts.staged, "staged", false, "Explain the staged changes instead of a commit")
+	explainCmd.Flags().StringVar(&opts.depth, "depth", string(commitgenService.DepthSummary), "How closely to explain: summary or lines")
+	explainCmd.Flags().BoolVar(&opts.generated, "generated", false, "Include generated files in the staged diff sent to the provider")
+
+	return explainCmd
+}
+
+// explainResult is what 'bgit explain --output json' prints.
+type explainResult struct {
+	Subject     string `json:"subject"`
+	Commit      string `json:"commit,omitempty"`
+	Depth       string `json:"depth"`
+	Provider    string `json:"provider"`
+	Model       string `json:"model"`
+	Explanation string `json:"explanation"`
+	Truncated   bool   `json:"truncated"`
+}
+
+// explainRepo is what 'bgit explain' reads from the repository.
+type explainRepo interface {
+	promptDiffRepo
+	StagedFiles() ([]string, error)
+	ResolveCommit(rev string) (*object.Commit, error)
+	CommitPatch(c *object.Commit) (*object.Patch, error)
+}
+
+func runExplain(ctx context.Context, d *Deps, rev string, opts *explainOptions) error {
+	depth := commitgenService.Depth(opts.depth)
+	if !slices.Contains(commitgenService.Depths, depth) {
+		return fmt.Errorf("unknown --depth %q (want summary or lines)", opts.depth)
+	}
+	if opts.staged && rev != "" {
+		return errors.New("give a commit or --staged, not both")
+	}
+	client, err := openRepo[explainRepo](d)
+	if err != nil {
+		return err
+	}
+
+	var (
+		provider = d.Config.Get().AIProvider
+		result   = explainResult{Depth: string(depth), Provider: provider.Name, Model: string(commitgenService.Model)}
+		about    string
+		diff     *commitgenService.Diff
+		note     string
+
+		// providerFailed tells the error path to add a configuration hint.
+		providerFailed bool
+	)
+
+	stages := []pipeline.Stage{
+		{Name: "Build diff", Run: func(ctx context.Context) (string, error) {
+			if opts.staged {
+				files, err := client.StagedFiles()
+				if err != nil {
+					return "", fmt.Errorf("failed to get staged files: %w", err)
+				}
+				if len(files) == 0 {
+					return "", errNothingStaged
+				}
+				pd, err := buildPromptDiff(d, client, files, opts.generated)
+				if err != nil {
+					return "", err
+				}
+				diff, result.Subject, result.Truncated = pd.diff, "the staged changes", pd.diff.Truncation().Truncated()
+				return plural(len(files), "file"), nil
+			}
+
+			if rev == "" {
+				rev = "HEAD"
+			}
+			c, err := client.ResolveCommit(rev)
+			if err != nil {
+				return "", err
+			}
+			patch, err := client.CommitPatch(c)
+			if err != nil {
+				return "", fmt.Errorf("failed to diff %s: %w", rev, err)
+			}
+			pr, pw := io.Pipe()
+			go func() { pw.CloseWithError(patch.Encode(pw)) }()
+			diff, err = commitgenService.ReadDiff(pr, promptLimits(d))
+			pr.Close()
+			if err != nil {
+				return "", fmt.Errorf("failed to diff %s: %w", rev, err)
+			}
+			about = strings.TrimSpace(c.Message)
+			result.Commit = c.Hash.String()
+			result.Subject = c.Hash.String()[:7] + " " + strings.SplitN(about, "\n", 2)[0]
+			result.Truncated = diff.Truncation().Truncated()
+			return plural(diff.Len(), "byte"), nil
+		}},
+		{Name: "Ask " + provider.Name, Run: func(ctx context.Context) (string, error) {
+			text, err := d.Explainer.Explain(ctx, about, diff, depth, provider)
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			if err != nil {
+				providerFailed = !errors.Is(err, errOffline)
+				return "", fmt.Errorf("%s provider failed: %w", provider.Name, err)
+			}
+			result.Explanation = text
+			return plural(len(strings.Fields(text)), "word"), nil
+		}},
+	}
+
+	err = runPipeline(ctx, d, stages)
+	switch {
+	case errors.Is(err, errNothingStaged):
+		return fmt.Errorf("%w; use 'bgit add' to stage files first", err)
+	case err != nil && providerFailed:
+		d.flushOut()
+		fmt.Fprintf(d.IO.ErrOut, "Hint: Ensure %s is set or change provider in config file\n", provider.EnvName)
+		return err
+	case err != nil:
+		return err
+	}
+
+	if d.Output.JSON() {
+		return json.NewEncoder(d.IO.Out).Encode(result)
+	}
+	if result.Truncated {
+		note = "Part of the diff was left out to fit the ai.* limits of the config."
+	}
+	width := ui.TerminalWidth(d.IO.Out)
+	d.infoln()
+	fmt.Fprint(d.IO.Out, ui.RenderExplanation(ui.ExplainView{
+		Subject:  result.Subject,
+		Provider: provider.Name,
+		Text:     strings.TrimPrefix(commitgenService.WrapBody("\n"+result.Explanation, width), "\n"),
+		Note:     note,
+	}, width))
+	return nil
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui/pipeline"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/spf13/cobra"
+)
+
+type fetchOptions struct {
+	all   bool
+	prune bool
+}
+
+func newFetchCmd(d *Deps) *cobra.Command {
+	opts := &fetchOptions{}
+
+	fetchCmd := &cobra.Command{
+		Use:   "fetch [remote]",
+		Short: "Download the branches and tags of a remote",
+		Long: `Download the branches and tags of a remote and update its remote-tracking
+branches, without touching the local ones.
+
+The remote is the one named, or else the one the current branch tracks, or
+else origin (or the only remote there is); --all fetches every remote.
+--prune deletes the remote-tracking branches whose branch is gone from the
+remote. Progress is shown as the remote reports it, then what moved and how
+far the current branch is ahead of and behind its upstream.
+
+Credentials are found as for 'bgit push': from the environment, or else from
+git's credential helpers. HTTP(S) remotes are reached through the proxy git
+would use: remote.<name>.proxy, or http.proxy, or else HTTPS_PROXY.
+
+A fetch that fails on a dropped connection, a timeout or a busy server is
+tried again, a few times, waiting longer each time. When many branches
+changed they are fetched a batch at a time, and the batches that arrived are
+kept should the fetch fail, so that fetching again carries on from there.
+
+With --output json standard output carries the remotes fetched, the
+remote-tracking branches each changed, and the current branch with its
+upstream and ahead/behind counts.`,
+		Example: `  bgit fetch
+  bgit fetch upstream
+  bgit fetch --all --prune`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			remote := ""
+			if len(args) == 1 {
+				if opts.all {
+					return errors.New("--all fetches every remote; leave out the remote name")
+				}
+				remote = args[0]
+			}
+			return runFetch(cmd.Context(), d, opts, remote)
+		},
+	}
+
+	fetchCmd.Flags().BoolVar(&opts.all, "all", false, "Fetch every remote")
+	fetchCmd.Flags().BoolVar(&opts.prune, "prune", false, "Delete remote-tracking branches whose branch is gone")
+
+	return fetchCmd
+}
+
+// fetchResult is what 'bgit fetch --output json' prints.
+type fetchResult struct {
+	Remotes []fetchedRemote `json:"remotes"`
+	Branch  *trackingJSON   `json:"branch,omitempty"`
+}
+
+type fetchedRemote struct {
+	Name    string       `json:"name"`
+	Updated []updatedRef `json:"updated"`
+}
+
+// updatedRef is a remote-tracking branch a fetch changed; Old is empty for
+// a new branch and New for a deleted one.
+type updatedRef struct {
+	Ref string `json:"ref"`
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+type trackingJSON struct {
+	Name     string `json:"name"`
+	Upstream string `json:"upstream,omitempty"`
+	Ahead    int    `json:"ahead"`
+	Behind   int    `json:"behind"`
+	Gone     bool   `json:"upstream_gone,omitempty"`
+}
+
+// fetchRepo is what 'bgit fetch' needs from the repository.
+type fetchRepo interface {
+	fetchStageRepo
+	remoteTargetRepo
+	CurrentBranch() (string, error)
+}
+
+func runFetch(ctx context.Context, d *Deps, opts *fetchOptions, remote string) error {
+	if d.Offline {
+		return errOffline
+	}
+	client, err := openRepo[fetchRepo](d)
+	if err != nil {
+		return err
+	}
+
+	var remotes []string
+	if opts.all {
+		all, err := client.Remotes()
+		if err != nil {
+			return err
+		}
+		for _, r := range all {
+			remotes = append(remotes, r.Name)
+		}
+		if len(remotes) == 0 {
+			return errors.New("the repository has no remote; add one with 'git remote add'")
+		}
+	} else {
+		branch, _ := client.CurrentBranch() // a hash when detached, which tracks nothing
+		if remote, _, err = remoteTarget(client, branch, remote); err != nil {
+			return err
+		}
+		remotes = []string{remote}
+	}
+
+	results := make([]gitService.FetchResult, len(remotes))
+	stages := make([]pipeline.Stage, 0, len(remotes))
+	for i, name := range remotes {
+		stages = append(stages, fetchStage(d, client, name, opts.prune, &results[i]))
+	}
+	if err := runPipeline(ctx, d, stages); err != nil {
+		return err
+	}
+
+	current := results[len(results)-1].Current
+	if d.Output.JSON() {
+		out := fetchResult{Remotes: make([]fetchedRemote, 0, len(results))}
+		for _, r := range results {
+			fr := fetchedRemote{Name: r.Remote, Updated: []updatedRef{}}
+			for _, u := range r.Updated {
+				fr.Updated = append(fr.Updated, updatedRef{Ref: u.Name, Old: hashOrEmpty(u.Old), New: hashOrEmpty(u.New)})
+			}
+			out.Remotes = append(out.Remotes, fr)
+		}
+		if current.Name != "" {
+			out.Branch = &trackingJSON{Name: current.Name, Upstream: current.Upstream, Ahead: current.Ahead, Behind: current.Behind, Gone: current.UpstreamGone}
+		}
+		return json.NewEncoder(d.IO.Out).Encode(out)
+	}
+	if line := trackingLine(current); line != "" {
+		d.infof("\n%s\n", line)
+	}
+	return nil
+}
+
+// fetchStageRepo is what fetching one remote needs.
+type fetchStageRepo interface {
+	ForgeRepo
+	Fetch(ctx context.Context, opts gitService.FetchOptions) (gitService.FetchResult, error)
+}
+
+// fetchStage fetches one remote into result.
+func fetchStage(d *Deps, client fetchStageRepo, remote string, prune bool, result *gitService.FetchResult) pipeline.Stage {
+	return pipeline.Stage{Name: "Fetch " + remote, Run: func(ctx context.Context) (string, error) {
+		url, err := client.RemoteURL(remote)
+		if err != nil {
+			return "", err
+		}
+		res, err := client.Fetch(ctx, gitService.FetchOptions{
+			Remote:   remote,
+			Prune:    prune,
+			Auth:     remoteAuth(ctx, d, url),
+			Progress: &progressWriter{ctx: ctx},
+		})
+		if err != nil {
+			return "", withRemoteHint(err)
+		}
+		*result = res
+		return fetchDetail(res), nil
+	}}
+}
+
+// fetchDetail sums up what a fetch changed for its pipeline stage.
+func fetchDetail(res gitService.FetchResult) string {
+	var moved, created, deleted int
+	for _, u := range res.Updated {
+		switch {
+		case u.Old.IsZero():
+			created++
+		case u.New.IsZero():
+			deleted++
+		default:
+			moved++
+		}
+	}
+	if moved+created+deleted == 0 {
+		return "up to date"
+	}
+	var parts []string
+	if moved > 0 {
+		parts = append(parts, fmt.Sprintf("%d updated", moved))
+	}
+	if created > 0 {
+		parts = append(parts, fmt.Sprintf("%d new", created))
+	}
+	if deleted > 0 {
+		parts = append(parts, fmt.Sprintf("%d pruned", deleted))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// trackingLine says how far the current branch is from its upstream, or ""
+// when there is nothing to say.
+func trackingLine(b gitService.Branch) string {
+	switch {
+	case b.Name == "" || b.Upstream == "":
+		return ""
+	case b.UpstreamGone:
+		return fmt.Sprintf("%s tracks %s, which is gone from the remote.", b.Name, b.Upstream)
+	case b.Ahead > 0 && b.Behind > 0:
+		return fmt.Sprintf("%s and %s have diverged: %d and %d commits of their own; run 'bgit pull' (or --rebase) to bring them together.", b.Name, b.Upstream, b.Ahead, b.Behind)
+	case b.Behind > 0:
+		return fmt.Sprintf("%s is %s behind %s; run 'bgit pull' to catch up.", b.Name, plural(b.Behind, "commit"), b.Upstream)
+	case b.Ahead > 0:
+		return fmt.Sprintf("%s is %s ahead of %s; run 'bgit push' to publish.", b.Name, plural(b.Ahead, "commit"), b.Upstream)
+	}
+	return fmt.Sprintf("%s is up to date with %s.", b.Name, b.Upstream)
+}
+
+// hashOrEmpty is the full hash h, or "" when it is zero.
+func hashOrEmpty(h plumbing.Hash) string {
+	if h.IsZero() {
+		return ""
+	}
+	return h.String()
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+type graphOptions struct {
+	svg      bool
+	html     bool
+	dot      bool
+	file     string
+	maxCount int
+	oneline  bool
+}
+
+var errGraphFormat = errors.New("choose a format with --svg, --html or --dot, or a --file ending in .svg, .html or .dot")
+
+func newGraphCmd(d *Deps) *cobra.Command {
+	opts := &graphOptions{}
+
+	graphCmd := &cobra.Command{
+		Use:   "graph [<range>]",
+		Short: "Export the commit graph of a range as SVG, HTML or DOT",
+		Long: `Export the commit graph that 'bgit log' draws, for documentation and
+reviews: the commits of a range, newest first, each with its short hash,
+subject, author and date, and the lines between them showing where branches
+split off and were merged.
+
+The range is a branch or any revision, for its whole history, or since..until
+for the commits until reaches that since does not, as git log reads it;
+either side left out is HEAD. Without one it is the current branch.
+
+--svg draws an image laid out like the terminal graph, one column per line
+of history in the same colours; --html puts that image on a web page of its
+own; --dot writes the commits and their parents in Graphviz's language, to
+lay out with dot. The format can also come from the name given to --file.
+Without --file the export is printed.`,
+		Example: `  bgit graph --svg -f history.svg
+  bgit graph main..feature/login --html -f review.html
+  bgit graph v1.2.0..v1.3.0 --dot | dot -Tpng -o release.png`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rev := ""
+			if len(args) == 1 {
+				rev = args[0]
+			}
+			return runGraphExport(d, rev, opts)
+		},
+	}
+
+	graphCmd.Flags().BoolVar(&opts.svg, "svg", false, "Draw the graph as an SVG image")
+	graphCmd.Flags().BoolVar(&opts.html, "html", false, "Draw the graph on a web page")
+	graphCmd.Flags().BoolVar(&opts.dot, "dot", false, "Write the graph in Graphviz's DOT language")
+	graphCmd.Flags().StringVarP(&opts.file, "file", "f", "", "Write the export to this file instead of printing it")
+	graphCmd.Flags().IntVarP(&opts.maxCount, "max-count", "n", 0, "Export at most this many commits")
+	graphCmd.Flags().BoolVar(&opts.oneline, "oneline", false, "Show only the hash and subject of each commit")
+	graphCmd.MarkFlagsMutuallyExclusive("svg", "html", "dot")
+
+	return graphCmd
+}
+
+// graphRepo is what 'bgit graph export' reads from the repository.
+type graphRepo interface {
+	CurrentBranch() (string, error)
+	Log(rev string, max int) ([]*object.Commit, error)
+	ResolveCommit(rev string) (*object.Commit, error)
+	CommitsBetween(since, until plumbing.Hash) ([]*object.Commit, error)
+}
+
+func runGraphExport(d *Deps, rev string, opts *graphOptions) error {
+	format, err := graphFormat(opts)
+	if err != nil {
+		return err
+	}
+	client, err := openRepo[graphRepo](d)
+	if err != nil {
+		return err
+	}
+	commits, err := rangeCommits(client, rev, opts.maxCount)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return errors.New("no commits to export")
+	}
+
+	title := "History of " + rev
+	if rev == "" {
+		branch, err := client.CurrentBranch()
+		if err != nil || branch == "" {
+			branch = "HEAD"
+		}
+		title = "History of " + branch
+	}
+	view := ui.GraphView{Commits: graphCommits(commits), Now: time.Now(), Oneline: opts.oneline}
+	var out string
+	switch format {
+	case "svg":
+		out = ui.RenderGraphSVG(view)
+	case "html":
+		out = ui.RenderGraphHTML(view, title)
+	default:
+		out = ui.RenderGraphDOT(view)
+	}
+
+	if opts.file == "" {
+		_, err := fmt.Fprint(d.IO.Out, out)
+		return err
+	}
+	if err := os.WriteFile(opts.file, []byte(out), 0o644); err != nil {
+		return err
+	}
+	d.infof("%sWrote the graph of %s to %s\n", ui.Icon("✓"), plural(len(commits), "commit"), opts.file)
+	return nil
+}
+
+// graphFormat is the export format the flags ask for, or the one the
+// output file's name implies.
+func graphFormat(opts *graphOptions) (string, error) {
+	switch {
+	case opts.svg:
+		return "svg", nil
+	case opts.html:
+		return "html", nil
+	case opts.dot:
+		return "dot", nil
+	}
+	switch strings.ToLower(filepath.Ext(opts.file)) {
+	case ".svg":
+		return "svg", nil
+	case ".html", ".htm":
+		return "html", nil
+	case ".dot", ".gv":
+		return "dot", nil
+	}
+	return "", errGraphFormat
+}
+
+// rangeCommits lists the commits of rev, newest first: the history of a
+// single revision, or those of since..until that since does not reach.
+// HEAD stands in for rev and for either side of a range left empty.
+func rangeCommits(client graphRepo, rev string, max int) ([]*object.Commit, error) {
+	since, until, isRange := strings.Cut(rev, "..")
+	if !isRange {
+		commits, err := client.Log(rev, max)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+		return commits, nil
+	}
+	if strings.HasPrefix(until, ".") {
+		return nil, fmt.Errorf("%s: symmetric ranges are not supported; give since..until", rev)
+	}
+	resolve := func(rev string) (*object.Commit, error) {
+		if rev == "" {
+			rev = "HEAD"
+		}
+		return client.ResolveCommit(rev)
+	}
+	from, err := resolve(since)
+	if err != nil {
+		return nil, err
+	}
+	to, err := resolve(until)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := client.CommitsBetween(from.Hash, to.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	if max > 0 && len(commits) > max {
+		commits = commits[:max]
+	}
+	return commits, nil
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	commitgenService "github.com/endalk200/bgit/internal/services/commitgen"
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/spf13/cobra"
+)
+
+// prepareCommitMsgHook is the script installed as .git/hooks/prepare-commit-msg.
+// It hands over to bgit, and does nothing where bgit is not installed.
+const prepareCommitMsgHook = `#!/bin/sh
+` + gitService.HookMarker + `: suggests a commit message for plain 'git commit'.
+command -v bgit >/dev/null 2>&1 || exit 0
+exec bgit hook prepare-commit-msg "$@"
+`
+
+func newHookCmd(d *Deps) *cobra.Command {
+	hookCmd := &cobra.Command{
+		Use:    "hook",
+		Short:  "Entry points for git hooks installed by bgit",
+		Hidden: true,
+	}
+	hookCmd.AddCommand(&cobra.Command{
+		Use:   "prepare-commit-msg <message-file> [source] [commit]",
+		Short: "Fill in a generated message for git commit",
+		Long: `Called by git before the commit message editor opens. When git has no
+message of its own (no -m, template, merge or amend) the staged changes are
+described the way 'bgit commit' would and the result is written above git's
+comments. Failures are reported but never stop the commit.`,
+		Args: cobra.RangeArgs(1, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source := ""
+			if len(args) > 1 {
+				source = args[1]
+			}
+			return runPrepareCommitMsg(cmd.Context(), d, args[0], source)
+		},
+	})
+	return hookCmd
+}
+
+func runPrepareCommitMsg(ctx context.Context, d *Deps, file, source string) error {
+	if source != "" {
+		return nil // git already has a message
+	}
+	message, err := hookMessage(ctx, d)
+	if err != nil {
+		fmt.Fprintf(d.IO.ErrOut, "bgit: no commit message suggested: %v\n", err)
+		return nil
+	}
+	if message == "" {
+		return nil
+	}
+
+	existing, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, []byte(strings.TrimSpace(message)+"\n"+string(existing)), 0o644)
+}
+
+// hookRepo is what the commit-msg hook reads from the repository.
+type hookRepo interface {
+	issueLinkRepo
+	promptDiffRepo
+	StagedFiles() ([]string, error)
+	GetStagedFilesDiff(stagedFiles []string) (string, error)
+}
+
+// hookMessage describes the staged changes, from the AI provider or, offline,
+// from the file names. It returns "" when nothing is staged.
+func hookMessage(ctx context.Context, d *Deps) (string, error) {
+	client, err := openRepo[hookRepo](d)
+	if err != nil {
+		return "", err
+	}
+	staged, err := client.StagedFiles()
+	if err != nil || len(staged) == 0 {
+		return "", err
+	}
+
+	var message string
+	if d.Offline {
+		diff, err := client.GetStagedFilesDiff(staged)
+		if err != nil {
+			return "", err
+		}
+		message = commitgenService.HeuristicMessage(diff)
+	} else {
+		pd, err := buildPromptDiff(d, client, staged, false)
+		if err != nil {
+			return "", err
+		}
+		if message, err = d.CommitGen.GenerateCommitMessage(ctx, pd.diff, d.Config.Get().AIProvider); err != nil {
+			return "", err
+		}
+	}
+	if message, err = commitgenService.PostProcess(ctx, message, d.Config.Get().Message.PostProcess, secretEnv(d)); err != nil {
+		return "", err
+	}
+	message = commitgenService.WrapBody(message, d.Config.Get().Message.BodyWidth)
+	if number := linkedIssue(client); number != 0 && d.Config.Get().Issue.Reference {
+		message = commitgenService.ReferenceIssue(message, number)
+	}
+	return message, nil
+}
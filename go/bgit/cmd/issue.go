@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/endalk200/bgit/internal/config"
+	forgeService "github.com/endalk200/bgit/internal/services/forge"
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+type issueOptions struct {
+	state    string
+	assignee string
+	label    string
+	limit    int
+	mine     bool
+	noAssign bool
+}
+
+func newIssueCmd(d *Deps) *cobra.Command {
+	opts := &issueOptions{}
+
+	issueCmd := &cobra.Command{
+		Use:   "issue",
+		Short: "Browse GitHub issues and start a branch for one",
+		Long: `Browse the issues of the repository on GitHub, and start work on one.
+
+'issue start <number>' creates a branch named after the issue (see
+issue.branch_template in the config), switches to it with any uncommitted
+changes, assigns the issue to you and remembers the link in the repository's
+git config. Commits made with 'bgit commit' on that branch then end with a
+"Refs #<number>" trailer, unless the message mentions the issue already.
+
+The API is called with GITHUB_TOKEN or GH_TOKEN when set; private
+repositories and assigning issues need one.`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List issues, most recently updated first",
+		Long: `List the open issues of the repository, most recently updated first.
+--state closed or all shows the others, and --assignee, --mine and --label
+narrow the list.`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIssueList(cmd.Context(), d, opts)
+		},
+	}
+	listCmd.Flags().StringVar(&opts.state, "state", "open", "Which issues to list: open, closed or all")
+	listCmd.Flags().StringVar(&opts.assignee, "assignee", "", "Only issues assigned to this user")
+	listCmd.Flags().BoolVar(&opts.mine, "mine", false, "Only issues assigned to you")
+	listCmd.Flags().StringVar(&opts.label, "label", "", "Only issues with this label (comma-separate several)")
+	listCmd.Flags().IntVarP(&opts.limit, "limit", "n", 30, "List at most this many issues")
+	listCmd.MarkFlagsMutuallyExclusive("assignee", "mine")
+
+	viewCmd := &cobra.Command{
+		Use:   "view [number]",
+		Short: "Show an issue, by default the one the branch was started for",
+		Long: `Show an issue with its labels, assignees and description. Without a
+number, the issue the current branch was started for with 'issue start' is
+shown.`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIssueView(cmd.Context(), d, args)
+		},
+	}
+
+	startCmd := &cobra.Command{
+		Use:   "start <number>",
+		Short: "Create a branch for an issue, assign it to you and link them",
+		Long: `Create a branch for the issue from the current commit and switch to it,
+keeping uncommitted changes. The branch is named by issue.branch_template in
+the config, by default {type}/{number}-{slug}: fix for issues labelled as
+bugs, docs for documentation and feat otherwise, then the number and the
+title, e.g. fix/42-add-fails-on-deleted-files.
+
+The issue is assigned to the owner of the GitHub token (skip with
+--no-assign), and the branch is linked to it so that commits on it refer to
+the issue.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIssueStart(cmd.Context(), d, args[0], opts)
+		},
+	}
+	startCmd.Flags().BoolVar(&opts.noAssign, "no-assign", false, "Do not assign the issue to yourself")
+
+	issueCmd.AddCommand(listCmd, viewCmd, startCmd)
+	return issueCmd
+}
+
+// issueNumber parses an issue number given as 42 or #42.
+func issueNumber(arg string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%q is not an issue number", arg)
+	}
+	return n, nil
+}
+
+func issueRow(i forgeService.Issue) ui.IssueRow {
+	row := ui.IssueRow{
+		Number:    i.Number,
+		Title:     i.Title,
+		State:     i.State,
+		Author:    i.Author,
+		Labels:    i.Labels,
+		Assignees: i.Assignees,
+		Comments:  i.Comments,
+		When:      i.Updated,
+		URL:       i.URL,
+	}
+	if row.Labels == nil {
+		row.Labels = []string{}
+	}
+	if row.Assignees == nil {
+		row.Assignees = []string{}
+	}
+	return row
+}
+
+// issueRepo is what the 'bgit issue' commands need from the repository.
+type issueRepo interface {
+	issueLinkRepo
+	ForgeRepo
+	LocalBranches() ([]string, error)
+	StartBranch(name string) error
+	SetBranchIssue(branch string, number int) error
+}
+
+func runIssueList(ctx context.Context, d *Deps, opts *issueOptions) error {
+	switch opts.state {
+	case "open", "closed", "all":
+	default:
+		return fmt.Errorf("--state must be open, closed or all, not %q", opts.state)
+	}
+	client, err := openRepo[issueRepo](d)
+	if err != nil {
+		return err
+	}
+	forge, err := openForge(d, client)
+	if err != nil {
+		return fmt.Errorf("cannot list issues: %w", err)
+	}
+
+	filter := forgeService.IssueFilter{State: opts.state, Assignee: opts.assignee, Label: opts.label, Limit: opts.limit}
+	if opts.mine {
+		if filter.Assignee, err = forge.CurrentUser(ctx); err != nil {
+			return fmt.Errorf("failed to find out who you are on GitHub (is GITHUB_TOKEN set?): %w", err)
+		}
+	}
+	issues, err := forge.Issues(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	list := ui.IssueList{Repo: forge.Repo().String(), State: opts.state, Issues: []ui.IssueRow{}, Now: time.Now()}
+	for _, i := range issues {
+		list.Issues = append(list.Issues, issueRow(i))
+	}
+	if d.Output.JSON() {
+		return json.NewEncoder(d.IO.Out).Encode(list.Issues)
+	}
+	fmt.Fprint(d.IO.Out, ui.RenderIssueList(list, ui.TerminalWidth(d.IO.Out)))
+	return nil
+}
+
+func runIssueView(ctx context.Context, d *Deps, args []string) error {
+	client, err := openRepo[issueRepo](d)
+	if err != nil {
+		return err
+	}
+
+	var number int
+	if len(args) == 1 {
+		if number, err = issueNumber(args[0]); err != nil {
+			return err
+		}
+	} else {
+		branch, err := client.CurrentBranch()
+		if err != nil {
+			return err
+		}
+		if number, err = client.BranchIssue(branch); err != nil {
+			return err
+		}
+		if number == 0 {
+			return fmt.Errorf("branch %s was not started for an issue; pass an issue number", branch)
+		}
+	}
+
+	forge, err := openForge(d, client)
+	if err != nil {
+		return fmt.Errorf("cannot show issue #%d: %w", number, err)
+	}
+	issue, err := forge.Issue(ctx, number)
+	if err != nil {
+		return fmt.Errorf("failed to fetch issue #%d: %w", number, err)
+	}
+
+	view := ui.IssueView{IssueRow: issueRow(issue), Body: issue.Body, Created: issue.Created, Now: time.Now()}
+	if view.Branch, err = issueBranch(client, number); err != nil {
+		return err
+	}
+	if d.Output.JSON() {
+		return json.NewEncoder(d.IO.Out).Encode(view)
+	}
+	fmt.Fprint(d.IO.Out, ui.RenderIssue(view, ui.TerminalWidth(d.IO.Out)))
+	return nil
+}
+
+// issueBranch finds the local branch started for issue number, if any.
+func issueBranch(client issueRepo, number int) (string, error) {
+	branches, err := client.LocalBranches()
+	if err != nil {
+		return "", err
+	}
+	for _, b := range branches {
+		if n, err := client.BranchIssue(b); err == nil && n == number {
+			return b, nil
+		}
+	}
+	return "", nil
+}
+
+func runIssueStart(ctx context.Context, d *Deps, arg string, opts *issueOptions) error {
+	number, err := issueNumber(arg)
+	if err != nil {
+		return err
+	}
+	client, err := openRepo[issueRepo](d)
+	if err != nil {
+		return err
+	}
+	forge, err := openForge(d, client)
+	if err != nil {
+		return fmt.Errorf("cannot start issue #%d: %w", number, err)
+	}
+	issue, err := forge.Issue(ctx, number)
+	if err != nil {
+		return fmt.Errorf("failed to fetch issue #%d: %w", number, err)
+	}
+	if issue.State == "closed" {
+		fmt.Fprintf(d.IO.ErrOut, "warning: issue #%d is closed\n", number)
+	}
+
+	branch := issueBranchName(d.Config.Get().Issue.BranchTemplate, issue)
+	if err := client.StartBranch(branch); err != nil {
+		if errors.As(err, new(gitService.ErrBranchExists)) {
+			return fmt.Errorf("%w; switch to it, or change issue.branch_template", err)
+		}
+		return fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+	d.infof("%sSwitched to a new branch '%s'\n", ui.Icon("🌱"), branch)
+
+	if err := client.SetBranchIssue(branch, number); err != nil {
+		return fmt.Errorf("failed to link %s to #%d: %w", branch, number, err)
+	}
+
+	if !opts.noAssign {
+		if err := assignToMe(ctx, forge, issue); err != nil {
+			// The branch is ready; the assignment can be done by hand.
+			fmt.Fprintf(d.IO.ErrOut, "warning: issue #%d not assigned: %v\n", number, err)
+		}
+	}
+
+	if d.Config.Get().Issue.Reference {
+		d.infof("Commits on this branch will refer to #%d.\n", number)
+	}
+	return nil
+}
+
+// assignToMe adds the owner of the token to the issue's assignees, unless
+// they are there already.
+func assignToMe(ctx context.Context, forge Forge, issue forgeService.Issue) error {
+	if forgeService.GitHubToken() == "" {
+		return errors.New("set GITHUB_TOKEN to assign issues")
+	}
+	me, err := forge.CurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+	for _, a := range issue.Assignees {
+		if strings.EqualFold(a, me) {
+			return nil
+		}
+	}
+	return forge.AssignIssue(ctx, issue.Number, me)
+}
+
+// slugUnsafe matches the runs of characters left out of branch slugs.
+var slugUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// maxSlug bounds the title part of a branch name, so names stay typeable.
+const maxSlug = 40
+
+// issueBranchName fills in the branch template for issue.
+func issueBranchName(template string, issue forgeService.Issue) string {
+	if template == "" {
+		template = config.DefaultBranchTemplate
+	}
+	slug := strings.Trim(slugUnsafe.ReplaceAllString(strings.ToLower(issue.Title), "-"), "-")
+	if len(slug) > maxSlug {
+		slug = slug[:maxSlug]
+		if i := strings.LastIndex(slug, "-"); i > maxSlug/2 {
+			slug = slug[:i]
+		}
+		slug = strings.Trim(slug, "-")
+	}
+
+	kind := "feat"
+	for _, l := range issue.Labels {
+		l = strings.ToLower(l)
+		switch {
+		case strings.Contains(l, "bug"):
+			kind = "fix"
+		case strings.Contains(l, "doc") && kind != "fix":
+			kind = "docs"
+		}
+	}
+
+	return strings.NewReplacer(
+		"{type}", kind,
+		"{number}", strconv.Itoa(issue.Number),
+		"{slug}", slug,
+	).Replace(template)
+}
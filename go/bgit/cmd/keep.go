@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newKeepCmd(d *Deps) *cobra.Command {
+	keepCmd := &cobra.Command{
+		Use:   "keep [dirs...]",
+		Short: "Track empty directories by putting a .gitkeep in them",
+		Long: `git tracks files, not directories, so a directory with nothing in it is
+left out of every commit. keep puts an empty .gitkeep (keep.file in the
+config) in each directory named and stages it, creating the directory when
+it does not exist yet.
+
+A directory whose subdirectories are empty gets the file in the innermost
+of them, which keeps the ones around them too. A directory holding files
+git tracks, or one that is ignored, is left alone.
+
+Without directories keep does this for every empty directory in the working
+tree, the ones 'bgit status' lists.`,
+		Example: `  bgit keep logs tmp/cache
+  bgit keep`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runKeep(d, args)
+		},
+	}
+
+	return keepCmd
+}
+
+// keepRepo is what 'bgit keep' needs from the repository.
+type keepRepo interface {
+	indexRepo
+	EmptyDirs(dir string) ([]string, error)
+	Keep(dirs []string, name string) ([]string, error)
+}
+
+func runKeep(d *Deps, args []string) error {
+	client, err := openRepo[keepRepo](d)
+	if err != nil {
+		return err
+	}
+
+	var dirs, skipped []string
+	if len(args) == 0 {
+		if dirs, err = client.EmptyDirs(""); err != nil {
+			return err
+		}
+		if len(dirs) == 0 {
+			d.infof("No empty directories to keep.\n")
+			return nil
+		}
+	}
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			dirs = append(dirs, filepath.ToSlash(filepath.Clean(arg)))
+			continue
+		case err != nil:
+			return err
+		case !info.IsDir():
+			skipped = append(skipped, fmt.Sprintf("%s (not a directory)", arg))
+			continue
+		}
+		empty, err := client.EmptyDirs(arg)
+		if err != nil {
+			return err
+		}
+		if len(empty) == 0 {
+			skipped = append(skipped, fmt.Sprintf("%s (has files to track, or is ignored)", arg))
+		}
+		dirs = append(dirs, empty...)
+	}
+
+	done, err := protectIndex(d, client)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	files, err := client.Keep(dirs, d.Config.Get().Keep.File)
+	printPaths(d, ui.Icon("✓")+"Staged", files)
+	if err != nil {
+		return err
+	}
+	if len(skipped) > 0 {
+		d.flushOut()
+		fmt.Fprint(d.IO.ErrOut, ui.RenderSkippedPaths(skipped, ui.TerminalWidth(d.IO.ErrOut)))
+	}
+	if len(files) == 0 {
+		return errors.New("no directory needed keeping")
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/pager"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+type logOptions struct {
+	follow   bool
+	patch    bool
+	maxCount int
+	noPager  bool
+	branch   string
+	oneline  bool
+}
+
+func newLogCmd(d *Deps) *cobra.Command {
+	opts := &logOptions{}
+
+	logCmd := &cobra.Command{
+		Use:   "log [flags] [<path>]",
+		Short: "Show the commit graph, or the history of a file",
+		Long: `Show the commits of the current branch as a graph, newest first, the way
+git log --graph draws it: a line per commit with its short hash, subject,
+author and how long ago it was made, and the lines between them showing
+where branches were merged. --branch draws another branch, or any revision,
+instead; --oneline leaves out the author and date.
+
+Given a path, show the commits that changed that file instead, each with a
+diffstat of the change to that file or, with -p, its patch.
+
+--follow keeps going when the file was renamed, continuing the history under
+its earlier name, so the whole series of patches can be read from today back
+to the file's creation.
+
+On a terminal either view opens in a pager: n and N step from commit to
+commit, enter opens the whole commit with every file it touched, and esc
+goes back. When output is piped, or with --no-pager, it is printed instead.`,
+		Example: `  bgit log
+  bgit log --branch feature/login --oneline -n 20
+  bgit log --follow -p internal/ui/render.go`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				if opts.follow || opts.patch {
+					return errors.New("--follow and --patch need a path")
+				}
+				return runGraph(d, opts)
+			}
+			if opts.branch != "" || opts.oneline {
+				return errors.New("--branch and --oneline draw the commit graph and cannot be used with a path")
+			}
+			return runLog(d, args[0], opts)
+		},
+	}
+
+	logCmd.Flags().BoolVar(&opts.follow, "follow", false, "Continue the history of the file across renames")
+	logCmd.Flags().BoolVarP(&opts.patch, "patch", "p", false, "Show the patch of each change")
+	logCmd.Flags().IntVarP(&opts.maxCount, "max-count", "n", 0, "Show at most this many commits")
+	logCmd.Flags().BoolVar(&opts.noPager, "no-pager", false, "Print the history instead of opening the pager")
+	logCmd.Flags().StringVarP(&opts.branch, "branch", "b", "", "Draw the graph from this branch or revision instead of HEAD")
+	logCmd.Flags().BoolVar(&opts.oneline, "oneline", false, "Show only the hash and subject of each commit in the graph")
+
+	return logCmd
+}
+
+// logRepo is what 'bgit log' and 'bgit graph' read from the repository.
+type logRepo interface {
+	FileHistory(file string, follow bool, max int) ([]gitService.FileRevision, error)
+	Log(rev string, max int) ([]*object.Commit, error)
+	CommitPatch(c *object.Commit) (*object.Patch, error)
+}
+
+func runLog(d *Deps, path string, opts *logOptions) error {
+	client, err := openRepo[logRepo](d)
+	if err != nil {
+		return err
+	}
+
+	path = filepath.ToSlash(filepath.Clean(path))
+	revs, err := client.FileHistory(path, opts.follow, opts.maxCount)
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+	if len(revs) == 0 {
+		fmt.Fprintf(d.IO.Out, "No commits changed %s.\n", path)
+		return nil
+	}
+
+	width := ui.TerminalWidth(d.IO.Out)
+	sections := make([]string, 0, len(revs))
+	for _, rev := range revs {
+		view := commitView(rev.Commit)
+		if !opts.patch {
+			view.Stats = uiStats(gitService.PatchStats(rev.Patch), nil)
+		}
+		section := ui.RenderCommit(view, width)
+		if opts.patch {
+			section += "\n" + ui.RenderPatch(rev.Patch.String())
+		}
+		sections = append(sections, section)
+	}
+
+	term, ok := ui.TerminalFile(d.IO.Out)
+	if opts.noPager || !ok || !ui.IsInteractive(d.IO.In, d.IO.Out) {
+		fmt.Fprint(d.IO.Out, strings.Join(sections, "\n"))
+		return nil
+	}
+
+	d.flushOut()
+	return pager.Run(term, d.IO.In, sections, pager.Options{
+		Title: "log " + path,
+		Noun:  "commit",
+		Open: func(i int) (string, error) {
+			return renderFullCommit(client, revs[i].Commit, width)
+		},
+	})
+}
+
+// runGraph draws the history of a branch as a graph.
+func runGraph(d *Deps, opts *logOptions) error {
+	client, err := openRepo[logRepo](d)
+	if err != nil {
+		return err
+	}
+	commits, err := client.Log(opts.branch, opts.maxCount)
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+	if len(commits) == 0 {
+		fmt.Fprintln(d.IO.Out, "No commits yet.")
+		return nil
+	}
+
+	view := ui.GraphView{Commits: graphCommits(commits), Now: time.Now(), Oneline: opts.oneline}
+	width := ui.TerminalWidth(d.IO.Out)
+	sections := ui.RenderGraph(view, width)
+
+	term, ok := ui.TerminalFile(d.IO.Out)
+	if opts.noPager || !ok || !ui.IsInteractive(d.IO.In, d.IO.Out) {
+		fmt.Fprint(d.IO.Out, strings.Join(sections, ""))
+		return nil
+	}
+
+	title := "log"
+	if opts.branch != "" {
+		title += " " + opts.branch
+	}
+	d.flushOut()
+	return pager.Run(term, d.IO.In, sections, pager.Options{
+		Title:   title,
+		Noun:    "commit",
+		Compact: true,
+		Open: func(i int) (string, error) {
+			c := commits[i]
+			patch, err := client.CommitPatch(c)
+			if err != nil {
+				return "", err
+			}
+			view := commitView(c)
+			view.Stats = uiStats(gitService.PatchStats(patch), nil)
+			return ui.RenderCommit(view, width) + "\n" + ui.RenderPatch(patch.String()), nil
+		},
+	})
+}
+
+// patchRepo reads the changes of a commit.
+type patchRepo interface {
+	CommitPatch(c *object.Commit) (*object.Patch, error)
+}
+
+// renderFullCommit shows a commit that changed a file in full, as show
+// would: every file it touched, not only the one being read.
+func renderFullCommit(client patchRepo, c *object.Commit, width int) (string, error) {
+	patch, err := client.CommitPatch(c)
+	if err != nil {
+		return "", err
+	}
+	view := commitView(c)
+	view.Stats = uiStats(gitService.PatchStats(patch), nil)
+	return ui.RenderCommit(view, width) + "\n" + ui.RenderPatch(patch.String()), nil
+}
+
+// graphCommits turns commits into the commits of a graph view.
+func graphCommits(commits []*object.Commit) []ui.GraphCommit {
+	out := make([]ui.GraphCommit, 0, len(commits))
+	for _, c := range commits {
+		parents := make([]string, 0, len(c.ParentHashes))
+		for _, p := range c.ParentHashes {
+			parents = append(parents, p.String())
+		}
+		out = append(out, ui.GraphCommit{
+			Hash:    c.Hash.String(),
+			Parents: parents,
+			Subject: strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0],
+			Author:  c.Author.Name,
+			When:    c.Author.When,
+		})
+	}
+	return out
+}
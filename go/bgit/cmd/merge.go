@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+type mergeOptions struct {
+	ffOnly   bool
+	noFF     bool
+	noCommit bool
+	message  string
+	abort    bool
+}
+
+func newMergeCmd(d *Deps) *cobra.Command {
+	opts := &mergeOptions{}
+
+	mergeCmd := &cobra.Command{
+		Use:   "merge <branch>",
+		Short: "Merge a branch into the current one",
+		Long: `Merge <branch>, or any commit, into the current branch. A branch with no
+commits of its own since the merge base is fast-forwarded to <branch>;
+otherwise the changes of both sides since the merge base are combined in a
+merge commit. --ff-only refuses to make a merge commit, and --no-ff makes one
+even for a fast-forward.
+
+--no-commit stops before committing the merge, leaving the result staged and
+the message in MERGE_MSG; 'bgit commit' then makes the merge commit, with that
+message unless -m gives another.
+
+When the merge conflicts it stops and lists the conflicted files. 'bgit
+resolve' resolves them and commits the merge; 'bgit merge --abort' gives up,
+putting the branch back as it was.`,
+		Example: `  bgit merge feature/login
+  bgit merge --no-ff feature/login
+  bgit merge --ff-only origin/main
+  bgit merge --no-commit feature/login
+  bgit merge --abort`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.abort {
+				if len(args) > 0 {
+					return errors.New("--abort takes no branch")
+				}
+				return runMergeAbort(d)
+			}
+			if len(args) != 1 {
+				return errors.New("name the branch to merge")
+			}
+			return runMerge(d, args[0], opts)
+		},
+	}
+
+	mergeCmd.Flags().BoolVar(&opts.ffOnly, "ff-only", false, "Only fast-forward; refuse to make a merge commit")
+	mergeCmd.Flags().BoolVar(&opts.noFF, "no-ff", false, "Make a merge commit even when the branch could fast-forward")
+	mergeCmd.Flags().BoolVar(&opts.noCommit, "no-commit", false, "Stop before committing the merge")
+	mergeCmd.Flags().StringVarP(&opts.message, "message", "m", "", "Message of the merge commit")
+	mergeCmd.Flags().BoolVar(&opts.abort, "abort", false, "Give up a merge that stopped")
+	mergeCmd.MarkFlagsMutuallyExclusive("ff-only", "no-ff")
+	mergeCmd.MarkFlagsMutuallyExclusive("ff-only", "no-commit")
+	for _, f := range []string{"ff-only", "no-ff", "no-commit", "message"} {
+		mergeCmd.MarkFlagsMutuallyExclusive("abort", f)
+	}
+
+	return mergeCmd
+}
+
+// mergeRepo is what 'bgit merge' needs from the repository.
+type mergeRepo interface {
+	OperationInProgress() gitService.Operation
+	HeadBranch() (name string, detached bool, err error)
+	Merge(rev string, opts gitService.MergeOptions) (gitService.MergeResult, error)
+	ResolveCommit(rev string) (*object.Commit, error)
+	CommitsBetween(since, until plumbing.Hash) ([]*object.Commit, error)
+	Conflicts() ([]gitService.Conflict, error)
+	AbortMerge() error
+}
+
+func runMerge(d *Deps, rev string, opts *mergeOptions) error {
+	client, err := openRepo[mergeRepo](d)
+	if err != nil {
+		return err
+	}
+	if op := client.OperationInProgress(); op != gitService.NoOperation {
+		return fmt.Errorf("a %s is in progress; finish it with 'bgit resolve' first", op)
+	}
+	branch, detached, err := client.HeadBranch()
+	if err != nil {
+		return err
+	}
+	if detached {
+		branch = "HEAD"
+	}
+
+	res, mergeErr := client.Merge(rev, gitService.MergeOptions{
+		FastForwardOnly: opts.ffOnly,
+		NoFastForward:   opts.noFF,
+		NoCommit:        opts.noCommit,
+		Message:         opts.message,
+	})
+	conflicted := errors.As(mergeErr, new(gitService.ErrIntegrateConflict))
+	if mergeErr != nil && !conflicted {
+		return mergeErr
+	}
+
+	summary := ui.MergeSummary{Rev: rev, Branch: branch, Kind: string(res.Kind), NoCommit: opts.noCommit}
+	if !res.Commit.IsZero() {
+		summary.Commit = res.Commit.String()
+	}
+	if base, err := client.ResolveCommit(res.Base.String()); err == nil {
+		summary.Base = mergeCommit(base.Hash.String(), base.Message)
+	}
+	incoming, err := client.CommitsBetween(res.Head, res.Theirs)
+	if err != nil {
+		return err
+	}
+	for _, c := range incoming {
+		summary.Incoming = append(summary.Incoming, mergeCommit(c.Hash.String(), c.Message))
+	}
+
+	if !conflicted {
+		d.infof("%s", ui.RenderMergeSummary(summary, ui.TerminalWidth(d.IO.Out)))
+		if opts.noCommit && res.Kind == gitService.MergeThreeWay {
+			d.infof("Commit the merge with 'bgit commit'.\n")
+		}
+		return nil
+	}
+	conflicts, err := client.Conflicts()
+	if err != nil {
+		return fmt.Errorf("failed to list conflicts: %w", err)
+	}
+	for _, c := range conflicts {
+		summary.Conflicts = append(summary.Conflicts, c.Path)
+	}
+	fmt.Fprint(d.IO.ErrOut, ui.RenderMergeSummary(summary, ui.TerminalWidth(d.IO.ErrOut)))
+	fmt.Fprintln(d.IO.ErrOut, "Hint: run 'bgit resolve' to resolve the conflicts and commit the merge, or 'bgit merge --abort' to give up")
+	return mergeErr
+}
+
+func mergeCommit(hash, message string) ui.MergeCommit {
+	return ui.MergeCommit{Hash: hash, Subject: strings.SplitN(strings.TrimSpace(message), "\n", 2)[0]}
+}
+
+func runMergeAbort(d *Deps) error {
+	client, err := openRepo[mergeRepo](d)
+	if err != nil {
+		return err
+	}
+	if client.OperationInProgress() != gitService.Merge {
+		return errors.New("no merge to abort")
+	}
+	if err := client.AbortMerge(); err != nil {
+		return err
+	}
+	head, err := client.ResolveCommit("HEAD")
+	if err != nil {
+		return err
+	}
+	d.infof("%sMerge aborted; HEAD is back at %s %s\n", ui.Icon("✓"), head.Hash.String()[:7], strings.SplitN(strings.TrimSpace(head.Message), "\n", 2)[0])
+	return nil
+}
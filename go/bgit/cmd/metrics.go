@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/endalk200/bgit/internal/config"
+	"github.com/endalk200/bgit/internal/metrics"
+	commitgenService "github.com/endalk200/bgit/internal/services/commitgen"
+	"github.com/spf13/cobra"
+)
+
+func newMetricsCmd(d *Deps) *cobra.Command {
+	metricsCmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Export what bgit recorded about commits and AI requests",
+		Long: `bgit counts, on each machine, the commits it creates (by where the message
+came from: ai, offline or message), how long successful AI requests take
+(as a histogram per provider) and how many fail, per provider. The counters
+are kept in bgit/metrics.json in the user's cache directory (metrics.file in
+the config moves it) and can be turned off with metrics.enabled.
+
+'metrics export' writes them in the Prometheus text format, ready for the
+node_exporter textfile collector on developer machines and CI runners.`,
+	}
+
+	var file string
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the metrics in Prometheus textfile format",
+		Long: `Write the metrics in the Prometheus text exposition format, to standard
+output or, with --file, to a file that is replaced in one step so the
+textfile collector never reads it half-written. Point --file into the
+collector's directory, with a .prom extension, and run the export from cron
+or at the end of a CI job.`,
+		Example: `  bgit metrics export
+  bgit metrics export --file /var/lib/node_exporter/textfile/bgit.prom`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMetricsExport(d, file)
+		},
+	}
+	exportCmd.Flags().StringVar(&file, "file", "", "Write to this file instead of standard output")
+
+	metricsCmd.AddCommand(exportCmd)
+	return metricsCmd
+}
+
+func runMetricsExport(d *Deps, file string) error {
+	path, err := metricsPath(d.Config.Get().Metrics)
+	if err != nil {
+		return err
+	}
+	store, err := metrics.Recorder{Path: path}.Load()
+	if err != nil {
+		return err
+	}
+	if file == "" {
+		return store.WriteText(d.IO.Out)
+	}
+	var buf bytes.Buffer
+	if err := store.WriteText(&buf); err != nil {
+		return err
+	}
+	if err := metrics.WriteFileAtomic(file, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	d.infof("Wrote metrics to %s\n", file)
+	return nil
+}
+
+func metricsPath(cfg config.Metrics) (string, error) {
+	if cfg.File != "" {
+		return cfg.File, nil
+	}
+	return metrics.DefaultPath()
+}
+
+// recordMetric applies record to the metrics store when metrics are on. A
+// failure only shows in the debug log: metrics never get in the way.
+func recordMetric(d *Deps, record func(metrics.Recorder) error) {
+	cfg := d.Config.Get().Metrics
+	if !cfg.Enabled {
+		return
+	}
+	path, err := metricsPath(cfg)
+	if err == nil {
+		err = record(metrics.Recorder{Path: path})
+	}
+	if err != nil {
+		d.Log.Debug("metrics not recorded", "err", err)
+	}
+}
+
+// measuredGenerator times the AI requests made through it and counts the
+// failures, by provider.
+type measuredGenerator struct {
+	next CommitGenerator
+	d    *Deps
+}
+
+func (g measuredGenerator) GenerateCommitMessage(ctx context.Context, diff *commitgenService.Diff, provider config.Provider) (string, error) {
+	start := time.Now()
+	message, err := g.next.GenerateCommitMessage(ctx, diff, provider)
+	// A request the user cancelled says nothing about the provider.
+	if !errors.Is(err, context.Canceled) {
+		took := time.Since(start)
+		recordMetric(g.d, func(r metrics.Recorder) error { return r.AIRequest(provider.Name, took, err) })
+	}
+	return message, err
+}
+
+// measuredExplainer is measuredGenerator for explanations.
+type measuredExplainer struct {
+	next Explainer
+	d    *Deps
+}
+
+func (e measuredExplainer) Explain(ctx context.Context, about string, diff *commitgenService.Diff, depth commitgenService.Depth, provider config.Provider) (string, error) {
+	start := time.Now()
+	text, err := e.next.Explain(ctx, about, diff, depth, provider)
+	if !errors.Is(err, context.Canceled) {
+		took := time.Since(start)
+		recordMetric(e.d, func(r metrics.Recorder) error { return r.AIRequest(provider.Name, took, err) })
+	}
+	return text, err
+}
+
+// measuredRiskRater is measuredGenerator for risk ratings.
+type measuredRiskRater struct {
+	next RiskRater
+	d    *Deps
+}
+
+func (m measuredRiskRater) RateRisk(ctx context.Context, diff *commitgenService.Diff, findings string, provider config.Provider) (int, string, error) {
+	start := time.Now()
+	score, reason, err := m.next.RateRisk(ctx, diff, findings, provider)
+	if !errors.Is(err, context.Canceled) {
+		took := time.Since(start)
+		recordMetric(m.d, func(r metrics.Recorder) error { return r.AIRequest(provider.Name, took, err) })
+	}
+	return score, reason, err
+}
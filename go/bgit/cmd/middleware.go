@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/endalk200/bgit/internal/config"
+	commitgenService "github.com/endalk200/bgit/internal/services/commitgen"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+)
+
+// issuesURL is where crash reports should be filed.
+const issuesURL = "https://github.com/endalk200/cli-tui.poc/issues"
+
+// jsonAnnotation marks commands that can emit --output json.
+const jsonAnnotation = "bgit/json"
+
+// RunFunc is the signature of a cobra RunE.
+type RunFunc func(cmd *cobra.Command, args []string) error
+
+// Middleware wraps a RunFunc with behaviour shared by every command.
+type Middleware func(next RunFunc) RunFunc
+
+// useMiddleware wraps the RunE of cmd and all of its descendants so every
+// command gets the same panic recovery, timing, flag handling and flushing
+// without repeating it.
+func useMiddleware(cmd *cobra.Command, mws ...Middleware) {
+	if cmd.RunE != nil {
+		run := RunFunc(cmd.RunE)
+		for i := len(mws) - 1; i >= 0; i-- {
+			run = mws[i](run)
+		}
+		cmd.RunE = run
+	}
+	for _, child := range cmd.Commands() {
+		useMiddleware(child, mws...)
+	}
+}
+
+// defaultMiddleware is the stack applied to every command, outermost first.
+func defaultMiddleware(d *Deps) []Middleware {
+	return []Middleware{
+		withFlush(d),
+		withTiming(d),
+		withRecover(d),
+		withOutputOptions(d),
+		withOffline(d),
+	}
+}
+
+// withFlush buffers standard output for the duration of the command and
+// guarantees it is flushed however the command ends.
+func withFlush(d *Deps) Middleware {
+	return func(next RunFunc) RunFunc {
+		return func(cmd *cobra.Command, args []string) error {
+			orig := d.IO.Out
+			buf := &bufferedWriter{Writer: bufio.NewWriter(orig), under: orig}
+			d.IO.Out = buf
+			defer func() {
+				_ = buf.Flush()
+				d.IO.Out = orig
+			}()
+			return next(cmd, args)
+		}
+	}
+}
+
+// bufferedWriter is a bufio.Writer that still exposes the stream it wraps,
+// so terminal detection keeps working on buffered output.
+type bufferedWriter struct {
+	*bufio.Writer
+	under io.Writer
+}
+
+// Unwrap returns the underlying stream.
+func (w *bufferedWriter) Unwrap() io.Writer { return w.under }
+
+// withTiming logs how long the command took at debug level.
+func withTiming(d *Deps) Middleware {
+	return func(next RunFunc) RunFunc {
+		return func(cmd *cobra.Command, args []string) error {
+			start := time.Now()
+			d.Log.Debug("command started", "cmd", cmd.CommandPath(), "args", args)
+			err := next(cmd, args)
+			d.Log.Debug("command finished", "cmd", cmd.CommandPath(), "duration", time.Since(start).Round(time.Millisecond), "err", err)
+			return err
+		}
+	}
+}
+
+// withRecover turns a panic into an error with a bug-report hint instead of
+// a raw goroutine dump.
+func withRecover(d *Deps) Middleware {
+	return func(next RunFunc) RunFunc {
+		return func(cmd *cobra.Command, args []string) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				d.Log.Debug("panic", "value", r, "stack", string(debug.Stack()))
+				fmt.Fprintf(d.IO.ErrOut, "bgit hit an unexpected internal error while running '%s'.\n", cmd.CommandPath())
+				fmt.Fprintf(d.IO.ErrOut, "This is a bug. Please report it at %s\n", issuesURL)
+				fmt.Fprintln(d.IO.ErrOut, "and include the output of the same command re-run with --debug.")
+				err = fmt.Errorf("internal error: %v", r)
+			}()
+			return next(cmd, args)
+		}
+	}
+}
+
+// withOutputOptions resolves --output, --no-color, --quiet and --no-emoji
+// (falling back to the ui.* config keys) into d.Output and configures the
+// renderer accordingly.
+func withOutputOptions(d *Deps) Middleware {
+	return func(next RunFunc) RunFunc {
+		return func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("output")
+			noColor, _ := cmd.Flags().GetBool("no-color")
+			if _, ok := os.LookupEnv("NO_COLOR"); ok {
+				noColor = true
+			}
+
+			switch format {
+			case "text":
+			case "json":
+				if cmd.Annotations[jsonAnnotation] != "true" {
+					return fmt.Errorf("'%s' does not support --output json", cmd.CommandPath())
+				}
+			default:
+				return fmt.Errorf("invalid --output %q (want text or json)", format)
+			}
+
+			uiCfg := d.Config.Get().UI
+			d.Output = OutputOptions{
+				Format:  format,
+				NoColor: noColor,
+				Quiet:   boolFlagOr(cmd, "quiet", uiCfg.Quiet),
+				NoEmoji: boolFlagOr(cmd, "no-emoji", uiCfg.NoEmoji),
+			}
+			if noColor || d.Output.JSON() {
+				lipgloss.SetColorProfile(termenv.Ascii)
+			}
+			ui.SetPlain(d.Output.NoEmoji)
+			return next(cmd, args)
+		}
+	}
+}
+
+// boolFlagOr returns the flag value when it was set explicitly on the command
+// line and fallback (usually from config) otherwise.
+func boolFlagOr(cmd *cobra.Command, name string, fallback bool) bool {
+	if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+		v, _ := cmd.Flags().GetBool(name)
+		return v
+	}
+	return fallback
+}
+
+// errOffline is returned by anything that would need the network while
+// --offline is in effect.
+var errOffline = errors.New("network access is disabled (--offline or BGIT_OFFLINE)")
+
+// withOffline resolves --offline and BGIT_OFFLINE into d.Offline and, when
+// set, replaces the AI provider and forge with stand-ins that fail with
+// errOffline, so no code path can reach the network by accident.
+func withOffline(d *Deps) Middleware {
+	return func(next RunFunc) RunFunc {
+		return func(cmd *cobra.Command, args []string) error {
+			d.Offline = boolFlagOr(cmd, "offline", envTrue("BGIT_OFFLINE"))
+			if d.Offline {
+				d.CommitGen = CommitGeneratorFunc(func(context.Context, *commitgenService.Diff, config.Provider) (string, error) {
+					return "", errOffline
+				})
+				d.Explainer = ExplainerFunc(func(context.Context, string, *commitgenService.Diff, commitgenService.Depth, config.Provider) (string, error) {
+					return "", errOffline
+				})
+				d.RiskRater = RiskRaterFunc(func(context.Context, *commitgenService.Diff, string, config.Provider) (int, string, error) {
+					return 0, "", errOffline
+				})
+				d.OpenForge = func(ForgeRepo) (Forge, error) { return nil, errOffline }
+				d.OpenReleaseForge = func(ForgeRepo) (ReleaseForge, error) { return nil, errOffline }
+			}
+			return next(cmd, args)
+		}
+	}
+}
+
+// envTrue reports whether the environment variable is set to a true value
+// such as 1, true or yes.
+func envTrue(name string) bool {
+	switch strings.ToLower(os.Getenv(name)) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// submoduleRepo reports the submodules that do not match their commit.
+type submoduleRepo interface {
+	DirtySubmodules() ([]string, error)
+}
+
+// environmentNote describes the machine a commit is made on: enough to tell,
+// in a later audit, which toolchain built it and whether submodules matched
+// what the commit records.
+func environmentNote(client submoduleRepo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "os: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "go: %s\n", toolOutput("go", "env", "GOVERSION"))
+	fmt.Fprintf(&b, "git: %s\n", strings.TrimPrefix(toolOutput("git", "--version"), "git version "))
+
+	dirty, err := client.DirtySubmodules()
+	switch {
+	case err != nil:
+		fmt.Fprintf(&b, "dirty-submodules: unknown (%v)\n", err)
+	case len(dirty) == 0:
+		b.WriteString("dirty-submodules: none\n")
+	default:
+		fmt.Fprintf(&b, "dirty-submodules: %s\n", strings.Join(dirty, ", "))
+	}
+	return b.String()
+}
+
+// toolOutput runs a version query and returns its first line, or "not found"
+// when the tool is not installed.
+func toolOutput(name string, args ...string) string {
+	out, err := exec.Command(name, args...).Output()
+	if err != nil {
+		return "not found"
+	}
+	return strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+}
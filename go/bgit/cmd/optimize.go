@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newOptimizeCmd(d *Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:   "optimize",
+		Short: "Write the commit-graph that speeds up walking the history",
+		Long: `Write a commit-graph: an index of every commit reachable from a branch,
+tag or remote branch, with its parents and its generation, how far it is
+from the first commit. Walking the history then reads the index instead of
+every commit, and stops where two histories meet rather than at the first
+commit, which makes 'bgit log', the ahead/behind counts of status, fetch
+and branches, and finding a merge base much faster on a long history.
+
+The file is the one git writes with 'git commit-graph write', so git uses
+it too, and bgit uses one git wrote. Commits made after it was written are
+still found, only more slowly; run this again after fetching a lot of new
+history.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runOptimize(d)
+		},
+	}
+}
+
+// optimizeRepo is what 'bgit optimize' does to the repository.
+type optimizeRepo interface {
+	WriteCommitGraph() (int, error)
+}
+
+func runOptimize(d *Deps) error {
+	client, err := openRepo[optimizeRepo](d)
+	if err != nil {
+		return err
+	}
+	n, err := client.WriteCommitGraph()
+	if err != nil {
+		return fmt.Errorf("failed to write the commit-graph: %w", err)
+	}
+	d.infof("%sWrote the commit-graph of %s\n", ui.Icon("✓"), plural(n, "commit"))
+	return nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import "fmt"
+
+// OutputOptions are the global presentation flags, resolved once per run.
+type OutputOptions struct {
+	Format  string // "text" or "json"
+	NoColor bool
+	Quiet   bool
+	NoEmoji bool
+}
+
+// JSON reports whether machine-readable output was requested.
+func (o OutputOptions) JSON() bool { return o.Format == "json" }
+
+// infof prints informational chatter (progress, hints, confirmations) that
+// --quiet suppresses. Results and errors must not go through it.
+func (d *Deps) infof(format string, a ...any) {
+	if d.Output.Quiet {
+		return
+	}
+	fmt.Fprintf(d.IO.Out, format, a...)
+}
+
+// infoln is the Println flavour of infof.
+func (d *Deps) infoln(a ...any) {
+	if d.Output.Quiet {
+		return
+	}
+	fmt.Fprintln(d.IO.Out, a...)
+}
+
+// flushOut pushes any buffered standard output to the terminal. Call it
+// before handing the terminal to an interactive view so earlier output is
+// not drawn underneath it.
+func (d *Deps) flushOut() {
+	if f, ok := d.IO.Out.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	ownersService "github.com/endalk200/bgit/internal/services/owners"
+	"github.com/endalk200/bgit/internal/ui"
+)
+
+// ownersRepo reads files from the work tree.
+type ownersRepo interface {
+	WorktreeFile(name string) ([]byte, error)
+}
+
+// loadOwners reads the CODEOWNERS file of the repository from the first of
+// the places GitHub looks that has one. Without one the rules are nil.
+func loadOwners(client ownersRepo) (ownersService.Rules, string, error) {
+	for _, name := range ownersService.Locations {
+		data, err := client.WorktreeFile(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		rules, err := ownersService.Parse(bytes.NewReader(data))
+		if err != nil {
+			return nil, name, fmt.Errorf("%s: %w", name, err)
+		}
+		return rules, name, nil
+	}
+	return nil, "", nil
+}
+
+// ownerAreas prepares ownership areas for display.
+func ownerAreas(areas []ownersService.Area) []ui.OwnerArea {
+	out := make([]ui.OwnerArea, 0, len(areas))
+	for _, a := range areas {
+		out = append(out, ui.OwnerArea{Pattern: a.Pattern, Owners: a.Owners, Files: a.Files})
+	}
+	return out
+}
+
+// checkOwners is the owners stage of the commit pipeline: it names the
+// owners the staged files need a review from, and from owners.warn_areas
+// owned areas warns that the commit might be better split.
+func checkOwners(d *Deps, areas []ownersService.Area) (detail string, warn bool) {
+	reviewers := ownersService.Reviewers(areas)
+	owned := ownersService.Owned(areas)
+	if len(reviewers) == 0 {
+		return "no owners for the staged files", false
+	}
+	detail = plural(owned, "area") + ": " + strings.Join(reviewers, ", ")
+	if warnAt := d.Config.Get().Owners.WarnAreas; warnAt > 0 && owned >= warnAt {
+		return detail + ", consider splitting", true
+	}
+	return detail, false
+}
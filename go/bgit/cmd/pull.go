@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui/pipeline"
+	"github.com/spf13/cobra"
+)
+
+type pullOptions struct {
+	rebase bool
+}
+
+func newPullCmd(d *Deps) *cobra.Command {
+	opts := &pullOptions{}
+
+	pullCmd := &cobra.Command{
+		Use:   "pull",
+		Short: "Fetch the upstream of the current branch and merge or rebase onto it",
+		Long: `Fetch the remote the current branch tracks, then bring the branch up to date
+with its upstream: by merging the upstream in, or with --rebase by replaying
+the branch's own commits on top of it. A branch with no commits of its own is
+fast-forwarded either way, and one already up to date is left alone.
+
+The branch must track a remote branch; 'bgit push -u' sets that up. When the
+merge or rebase stops on conflicts they are left in the working tree: resolve
+them with 'bgit resolve', which goes on with the rebase once they are.
+
+Credentials are taken from the environment as for 'bgit push'.`,
+		Example: `  bgit pull
+  bgit pull --rebase`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPull(cmd.Context(), d, opts)
+		},
+	}
+
+	pullCmd.Flags().BoolVarP(&opts.rebase, "rebase", "r", false, "Rebase the branch onto its upstream instead of merging")
+
+	return pullCmd
+}
+
+// pullRepo is what 'bgit pull' needs from the repository.
+type pullRepo interface {
+	fetchStageRepo
+	HeadBranch() (name string, detached bool, err error)
+	BranchUpstream(branch string) (remote, remoteBranch string, err error)
+	Integrate(upstream string, rebase bool) error
+	BranchTracking(name string) (gitService.Branch, error)
+}
+
+func runPull(ctx context.Context, d *Deps, opts *pullOptions) error {
+	if d.Offline {
+		return errOffline
+	}
+	client, err := openRepo[pullRepo](d)
+	if err != nil {
+		return err
+	}
+	branch, detached, err := client.HeadBranch()
+	if err != nil {
+		return err
+	}
+	if detached {
+		return errors.New("HEAD is detached; check out the branch to pull into")
+	}
+	remote, remoteBranch, err := client.BranchUpstream(branch)
+	if err != nil {
+		return err
+	}
+	switch remote {
+	case "":
+		return fmt.Errorf("%s tracks no remote branch; push it with 'bgit push -u' first", branch)
+	case ".":
+		return fmt.Errorf("%s tracks the local branch %s; there is nothing to fetch", branch, remoteBranch)
+	}
+	upstream := remote + "/" + remoteBranch
+
+	var fetched gitService.FetchResult
+	verb := "Merge " + upstream
+	if opts.rebase {
+		verb = "Rebase onto " + upstream
+	}
+	stages := []pipeline.Stage{
+		fetchStage(d, client, remote, false, &fetched),
+		{Name: verb, Run: func(ctx context.Context) (string, error) {
+			b := fetched.Current
+			switch {
+			case b.UpstreamGone:
+				return "", fmt.Errorf("%s is gone from the remote", upstream)
+			case b.Behind == 0:
+				return "", pipeline.Skip("already up to date")
+			}
+			if err := client.Integrate(upstream, opts.rebase); err != nil {
+				return "", err
+			}
+			switch {
+			case b.Ahead == 0:
+				return fmt.Sprintf("fast-forward, %s", plural(b.Behind, "commit")), nil
+			case opts.rebase:
+				return fmt.Sprintf("%s replayed onto %s", plural(b.Ahead, "commit"), plural(b.Behind, "new commit")), nil
+			}
+			return fmt.Sprintf("%s merged", plural(b.Behind, "commit")), nil
+		}},
+	}
+
+	err = runPipeline(ctx, d, stages)
+	if errors.As(err, new(gitService.ErrIntegrateConflict)) {
+		d.flushOut()
+		fmt.Fprintln(d.IO.ErrOut, "Hint: run 'bgit resolve' to resolve the conflicts and finish")
+	}
+	if err != nil {
+		return err
+	}
+
+	if after, err := client.BranchTracking(branch); err == nil {
+		if line := trackingLine(after); line != "" {
+			d.infof("\n%s\n", line)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,512 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	forgeService "github.com/endalk200/bgit/internal/services/forge"
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/pipeline"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+type pushOptions struct {
+	remotes        []string
+	setUpstream    bool
+	forceWithLease bool
+	tags           bool
+	whenGreen      bool
+	to             string
+	mergeMethod    string
+	timeout        time.Duration
+}
+
+func newPushCmd(d *Deps) *cobra.Command {
+	opts := &pushOptions{}
+
+	pushCmd := &cobra.Command{
+		Use:   "push",
+		Short: "Push the current branch, optionally landing it once CI is green",
+		Long: `Push the current branch to the branch it tracks, or else to the remote branch
+of the same name.
+
+The remote is --remote, or else the one the branch tracks, or else origin (or
+the only remote there is). --remote can be repeated, or list remotes with
+commas, to push to several in one go, each with a line of its own. The first
+is the one --set-upstream makes the current branch track and --when-green
+lands on; if a push to any of the others fails, the rest still go ahead.
+
+A push that is not a fast-forward is refused unless --force-with-lease is
+given, which replaces the remote branch only while it is where it was at the
+last fetch. --tags pushes every tag along.
+
+Remotes marked with 'bgit remote mirror' are pushed to after the others every
+time, forced to match, so that they stay in step.
+
+Over HTTPS the token in BGIT_GIT_TOKEN is sent, or for github.com GITHUB_TOKEN
+(or GH_TOKEN), or else what git's credential helpers have for the remote, as
+git would push with. Over SSH the key set with 'bgit remote key' is used, or
+the one in BGIT_SSH_KEY, decrypted with BGIT_SSH_KEY_PASSPHRASE, or else the
+SSH agent, or else the usual keys in ~/.ssh. A server known_hosts does not
+list shows its key fingerprint and asks to be trusted first; one whose key
+changed is refused. HTTP(S) remotes are reached through the proxy git would
+use, and a push that could not connect is tried again.
+
+With --when-green, bgit then waits for the checks on the pushed commit (check
+runs and commit statuses) to finish, showing them as they go, and lands the
+commit only if they all pass:
+
+  --to <branch>   push the commit on to <branch> too, e.g. a protected main
+                  that only takes commits CI has seen; the push is
+                  fast-forward only
+  (default)       turn on auto-merge for the branch's open pull request, so
+                  GitHub merges it with --merge-method once reviews allow
+
+It stops as soon as a check fails, listing the failures, and gives up after
+--timeout. The checks are looked at often at first and less often as the wait
+goes on. Ctrl-C stops waiting; the branch stays pushed.
+
+--when-green needs GITHUB_TOKEN (or GH_TOKEN) with access to the repository.`,
+		Example: `  bgit push
+  bgit push -u
+  bgit push --force-with-lease
+  bgit push --remote upstream --tags
+  bgit push --remote origin,backup
+  bgit push --when-green
+  bgit push --when-green --to main`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !opts.whenGreen && (cmd.Flags().Changed("to") || cmd.Flags().Changed("merge-method") || cmd.Flags().Changed("timeout")) {
+				return errors.New("--to, --merge-method and --timeout only apply with --when-green")
+			}
+			return runPush(cmd.Context(), d, opts)
+		},
+	}
+
+	pushCmd.Flags().StringSliceVar(&opts.remotes, "remote", nil, "Remote to push to; repeat or separate with commas for several (default: the tracked remote, or origin)")
+	pushCmd.Flags().BoolVarP(&opts.setUpstream, "set-upstream", "u", false, "Make the pushed branch the one the current branch tracks")
+	pushCmd.Flags().BoolVar(&opts.forceWithLease, "force-with-lease", false, "Replace the remote branch if it has not moved since the last fetch")
+	pushCmd.Flags().BoolVar(&opts.tags, "tags", false, "Push every tag too")
+	pushCmd.Flags().BoolVar(&opts.whenGreen, "when-green", false, "Wait for CI on the pushed commit, then land it")
+	pushCmd.Flags().StringVar(&opts.to, "to", "", "With --when-green, push the commit to this branch instead of auto-merging its pull request")
+	pushCmd.Flags().StringVar(&opts.mergeMethod, "merge-method", "merge", "With --when-green, how auto-merge merges the pull request: merge, squash or rebase")
+	pushCmd.Flags().DurationVar(&opts.timeout, "timeout", 30*time.Minute, "With --when-green, how long to wait for the checks")
+
+	return pushCmd
+}
+
+// Wait-for-green looks at the checks every firstPoll at first, backing off
+// to maxPoll; checks usually take minutes, and the early looks catch the
+// ones that fail fast.
+var (
+	firstPoll = 5 * time.Second
+	maxPoll   = time.Minute
+	// noChecksAfter is how long a commit may go without any check before
+	// bgit concludes CI does not run on it.
+	noChecksAfter = 2 * time.Minute
+)
+
+// pushRepo is what 'bgit push' needs from the repository.
+type pushRepo interface {
+	remoteTargetRepo
+	pushStageRepo
+	ForgeRepo
+	ResolveCommit(rev string) (*object.Commit, error)
+	HeadBranch() (name string, detached bool, err error)
+	Push(remote string, refspecs ...string) error
+}
+
+func runPush(ctx context.Context, d *Deps, opts *pushOptions) error {
+	switch opts.mergeMethod {
+	case "merge", "squash", "rebase":
+	default:
+		return fmt.Errorf("--merge-method must be merge, squash or rebase, not %q", opts.mergeMethod)
+	}
+	if d.Offline {
+		return errOffline
+	}
+	client, err := openRepo[pushRepo](d)
+	if err != nil {
+		return err
+	}
+	head, err := client.ResolveCommit("HEAD")
+	if err != nil {
+		return err
+	}
+	branch, detached, err := client.HeadBranch()
+	if err != nil {
+		return err
+	}
+	if detached {
+		return errors.New("HEAD is detached; check out the branch to push")
+	}
+	if opts.to == branch {
+		return fmt.Errorf("--to names the current branch, %s", branch)
+	}
+	sha := head.Hash.String()
+
+	targets, err := pushTargets(client, branch, opts.remotes)
+	if err != nil {
+		return err
+	}
+	remote := targets[0].remote
+
+	var (
+		forge  Forge
+		pr     forgeService.PullRequest
+		landed string
+	)
+	if opts.whenGreen {
+		// Fail before pushing if the landing cannot happen.
+		if forge, err = openForge(d, client); err != nil {
+			return fmt.Errorf("cannot wait for CI: %w", err)
+		}
+		if opts.to == "" {
+			var ok bool
+			if pr, ok, err = forge.PullRequestFor(ctx, branch); err != nil {
+				return fmt.Errorf("failed to look up the pull request for %s: %w", branch, err)
+			}
+			if !ok {
+				return fmt.Errorf("%s has no open pull request to auto-merge; open one, or pass --to <branch> to push the commit there", branch)
+			}
+		}
+	}
+
+	var stages []pipeline.Stage
+	for i, t := range targets {
+		stages = append(stages, pushStage(d, client, branch, t, i == 0, opts))
+	}
+	if opts.whenGreen {
+		stages = append(stages,
+			pipeline.Stage{Name: "Wait for checks", Run: func(ctx context.Context) (string, error) {
+				ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+				defer cancel()
+				return waitForGreen(ctx, forge, sha)
+			}},
+		)
+		if opts.to != "" {
+			stages = append(stages, pipeline.Stage{Name: "Push to " + opts.to, Run: func(ctx context.Context) (string, error) {
+				if err := client.Push(remote, sha+":refs/heads/"+opts.to); err != nil {
+					return "", err
+				}
+				landed = fmt.Sprintf("%s is on %s/%s", sha[:7], remote, opts.to)
+				return fmt.Sprintf("%s → %s/%s", sha[:7], remote, opts.to), nil
+			}})
+			for _, t := range targets {
+				if !t.mirror {
+					continue
+				}
+				stages = append(stages, pipeline.Stage{Name: "Mirror " + opts.to + " to " + t.remote, KeepGoing: true, Run: func(ctx context.Context) (string, error) {
+					if err := client.Push(t.remote, "+"+sha+":refs/heads/"+opts.to); err != nil {
+						return "", err
+					}
+					return fmt.Sprintf("%s → %s/%s", sha[:7], t.remote, opts.to), nil
+				}})
+			}
+		} else {
+			stages = append(stages, pipeline.Stage{Name: "Enable auto-merge", Run: func(ctx context.Context) (string, error) {
+				if err := forge.EnableAutoMerge(ctx, pr, opts.mergeMethod); err != nil {
+					return "", fmt.Errorf("failed to enable auto-merge on #%d: %w", pr.Number, err)
+				}
+				landed = fmt.Sprintf("#%d will be merged (%s) once it may be: %s", pr.Number, opts.mergeMethod, pr.URL)
+				return fmt.Sprintf("#%d, %s", pr.Number, opts.mergeMethod), nil
+			}})
+		}
+	}
+
+	if err := runPipeline(ctx, d, stages); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("checks on %s still running after %s; %s is pushed, run 'bgit push --when-green' again to keep waiting", sha[:7], opts.timeout, branch)
+		}
+		return err
+	}
+	if landed != "" {
+		d.infof("\n%s%s\n", ui.Icon("🚀"), landed)
+	}
+	return nil
+}
+
+// pushTarget is a remote a push goes to and the branch on it.
+type pushTarget struct {
+	remote       string
+	remoteBranch string
+	// mirror is set for a remote pushed to because it is a mirror.
+	mirror bool
+}
+
+// pushTargets lists where branch is pushed: each of the remotes named, or
+// the one remoteTarget picks when none is, then every mirror not named.
+func pushTargets(client pushRepo, branch string, names []string) ([]pushTarget, error) {
+	if len(names) == 0 {
+		names = []string{""}
+	}
+	var targets []pushTarget
+	seen := map[string]bool{}
+	for _, name := range names {
+		remote, remoteBranch, err := remoteTarget(client, branch, name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[remote] {
+			seen[remote] = true
+			targets = append(targets, pushTarget{remote: remote, remoteBranch: remoteBranch})
+		}
+	}
+	remotes, err := client.Remotes()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range remotes {
+		if r.Mirror && !seen[r.Name] {
+			targets = append(targets, pushTarget{remote: r.Name, remoteBranch: branch, mirror: true})
+		}
+	}
+	return targets, nil
+}
+
+// pushStageRepo is what pushing to one remote needs.
+type pushStageRepo interface {
+	ForgeRepo
+	PushBranch(ctx context.Context, opts gitService.PushOptions) (gitService.PushResult, error)
+}
+
+// pushStage pushes branch to t. Only the first target is tracked with
+// --set-upstream, and a push to any other that fails leaves the rest to go
+// ahead. Mirrors are forced to match.
+func pushStage(d *Deps, client pushStageRepo, branch string, t pushTarget, first bool, opts *pushOptions) pipeline.Stage {
+	name := "Push " + branch
+	switch {
+	case t.mirror:
+		name = "Mirror to " + t.remote
+	case !first:
+		name = "Push to " + t.remote
+	}
+	stageOpts := *opts
+	stageOpts.setUpstream = first && opts.setUpstream
+	return pipeline.Stage{Name: name, KeepGoing: !first, Run: func(ctx context.Context) (string, error) {
+		url, err := client.RemoteURL(t.remote)
+		if err != nil {
+			return "", err
+		}
+		res, err := client.PushBranch(ctx, gitService.PushOptions{
+			Remote:         t.remote,
+			Branch:         branch,
+			RemoteBranch:   t.remoteBranch,
+			SetUpstream:    stageOpts.setUpstream,
+			ForceWithLease: opts.forceWithLease && !t.mirror,
+			Force:          t.mirror,
+			Tags:           opts.tags,
+			Auth:           remoteAuth(ctx, d, url),
+			Progress:       &progressWriter{ctx: ctx},
+		})
+		if err != nil {
+			return "", withRemoteHint(err)
+		}
+		return pushDetail(res, &stageOpts), nil
+	}}
+}
+
+// remoteTargetRepo is what picking the remote of a branch reads.
+type remoteTargetRepo interface {
+	BranchUpstream(branch string) (remote, remoteBranch string, err error)
+	Remotes() ([]gitService.Remote, error)
+}
+
+// remoteTarget picks the remote and the branch on it that branch is pushed
+// to and fetched from: the one it tracks, on remote if given; else the
+// branch of the same name on remote, origin, or the only remote there is.
+func remoteTarget(client remoteTargetRepo, branch, remote string) (string, string, error) {
+	upRemote, upBranch, err := client.BranchUpstream(branch)
+	if err != nil {
+		return "", "", err
+	}
+	if upRemote == "." {
+		upRemote = "" // tracks a local branch
+	}
+	remoteBranch := branch
+	if upRemote != "" && (remote == "" || remote == upRemote) {
+		remote, remoteBranch = upRemote, upBranch
+	}
+
+	remotes, err := client.Remotes()
+	if err != nil {
+		return "", "", err
+	}
+	names := make([]string, 0, len(remotes))
+	for _, r := range remotes {
+		names = append(names, r.Name)
+	}
+	switch {
+	case len(names) == 0:
+		return "", "", errors.New("the repository has no remote; add one with 'git remote add'")
+	case remote == "" && slices.Contains(names, "origin"):
+		remote = "origin"
+	case remote == "" && len(names) == 1:
+		remote = names[0]
+	case remote == "":
+		return "", "", fmt.Errorf("no origin remote; pick one of %s with --remote", strings.Join(names, ", "))
+	case !slices.Contains(names, remote):
+		return "", "", fmt.Errorf("no remote named %s; the remotes are %s", remote, strings.Join(names, ", "))
+	}
+	return remote, remoteBranch, nil
+}
+
+// remoteAuth gathers the credentials for the remote at url from the
+// environment. GitHub tokens are only sent to github.com. An SSH server
+// known_hosts does not list is trusted only when confirmed on a terminal.
+func remoteAuth(ctx context.Context, d *Deps, url string) gitService.Auth {
+	auth := gitService.Auth{
+		Token:            os.Getenv("BGIT_GIT_TOKEN"),
+		SSHKey:           os.Getenv("BGIT_SSH_KEY"),
+		SSHKeyPassphrase: os.Getenv("BGIT_SSH_KEY_PASSPHRASE"),
+		TrustHost: func(key gitService.HostKey) (bool, error) {
+			return trustHost(ctx, d, key)
+		},
+	}
+	if auth.Token == "" {
+		if repo, err := forgeService.ParseRemoteURL(url); err == nil && repo.Host == "github.com" {
+			auth.Token = forgeService.GitHubToken()
+		}
+	}
+	return auth
+}
+
+// trustHost shows the key of an SSH server that known_hosts does not list
+// and asks whether to trust it. Without a terminal to ask on it is not.
+func trustHost(ctx context.Context, d *Deps, key gitService.HostKey) (bool, error) {
+	if !ui.IsInteractive(d.IO.In, progressOut(d)) {
+		return false, nil
+	}
+	trusted := false
+	err := pipeline.Suspend(ctx, func() error {
+		term, _ := ui.TerminalFile(progressOut(d))
+		return huh.NewForm(huh.NewGroup(
+			huh.NewConfirm().
+				Title("Trust the SSH host " + key.Host + "?").
+				Description(fmt.Sprintf("It is not in known_hosts yet. Its %s key has the fingerprint\n%s\nCompare it with the one the host publishes before trusting it.", key.Type, key.Fingerprint)).
+				Affirmative("Trust").
+				Negative("Refuse").
+				Value(&trusted),
+		)).WithInput(d.IO.In).WithOutput(term).Run()
+	})
+	if errors.Is(err, huh.ErrUserAborted) {
+		return false, nil
+	}
+	return trusted, err
+}
+
+// withRemoteHint adds to err what to do about the credentials or the SSH
+// host key a remote was refused over.
+func withRemoteHint(err error) error {
+	var changed gitService.ErrHostKeyChanged
+	switch {
+	case errors.As(err, new(gitService.ErrRemoteAuth)):
+		return fmt.Errorf("%w\nHint: set BGIT_GIT_TOKEN or use a git credential helper for HTTPS remotes, or BGIT_SSH_KEY or 'bgit remote key' for SSH ones", err)
+	case errors.As(err, new(gitService.ErrHostKeyUnknown)):
+		return fmt.Errorf("%w\nHint: compare the fingerprint with the one the host publishes, then run bgit on a terminal to trust it", err)
+	case errors.As(err, &changed):
+		return fmt.Errorf("%w\nHint: the host may be impersonated; only if its key was replaced, drop the old one with 'ssh-keygen -R %s' and connect again", err, changed.Key.Host)
+	}
+	return err
+}
+
+// pushDetail sums up what a push did for its pipeline stage.
+func pushDetail(res gitService.PushResult, opts *pushOptions) string {
+	var detail string
+	switch {
+	case res.UpToDate || res.Old == res.New:
+		detail = res.Ref + " up to date"
+	case res.Old.IsZero():
+		detail = fmt.Sprintf("%s → %s, new branch", res.New.String()[:7], res.Ref)
+	default:
+		detail = fmt.Sprintf("%s..%s → %s", res.Old.String()[:7], res.New.String()[:7], res.Ref)
+	}
+	if res.Forced {
+		detail += ", forced"
+	}
+	if opts.tags {
+		detail += ", tags pushed"
+	}
+	if opts.setUpstream {
+		detail += ", tracking set"
+	}
+	return detail
+}
+
+// progressWriter shows the last line of what the remote reports as the
+// running detail of the stage.
+type progressWriter struct {
+	ctx context.Context
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	// Remotes redraw their counters with carriage returns.
+	lines := strings.FieldsFunc(string(p), func(r rune) bool { return r == '\r' || r == '\n' })
+	if len(lines) > 0 {
+		pipeline.Progress(w.ctx, strings.TrimSpace(lines[len(lines)-1]))
+	}
+	return len(p), nil
+}
+
+// waitForGreen polls the checks on sha until all have passed, one has
+// failed or ctx is done, reporting their progress as it goes.
+func waitForGreen(ctx context.Context, forge Forge, sha string) (string, error) {
+	start := time.Now()
+	interval := firstPoll
+	for {
+		checks, err := forge.CommitChecks(ctx, sha)
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			return "", fmt.Errorf("failed to fetch the checks: %w", err)
+		}
+
+		var passed, failed, running []string
+		for _, c := range checks {
+			switch state := c.State(); {
+			case forgeService.Passed(state):
+				passed = append(passed, c.Name)
+			case c.Status == "completed":
+				failed = append(failed, c.Name)
+			default:
+				running = append(running, c.Name)
+			}
+		}
+		switch {
+		case len(failed) > 0:
+			slices.Sort(failed)
+			return "", fmt.Errorf("%s failed: %s", plural(len(failed), "check"), strings.Join(failed, ", "))
+		case len(checks) > 0 && len(running) == 0:
+			return fmt.Sprintf("%s passed", plural(len(passed), "check")), nil
+		case len(checks) == 0 && time.Since(start) > noChecksAfter:
+			return "", fmt.Errorf("no checks reported on %s after %s; push without --when-green", sha[:7], noChecksAfter)
+		}
+		pipeline.Progress(ctx, checksProgress(len(checks), passed, running))
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+		interval = min(interval*3/2, maxPoll)
+	}
+}
+
+// checksProgress is the running detail of the wait: how many checks passed
+// and which are still going.
+func checksProgress(total int, passed, running []string) string {
+	if total == 0 {
+		return "waiting for checks to start"
+	}
+	slices.Sort(running)
+	return fmt.Sprintf("%d of %d passed · running %s", len(passed), total, ui.TruncateMiddle(strings.Join(running, ", "), 40))
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	commitgenService "github.com/endalk200/bgit/internal/services/commitgen"
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/rebasetool"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+type rebaseOptions struct {
+	interactive bool
+	abort       bool
+}
+
+func newRebaseCmd(d *Deps) *cobra.Command {
+	opts := &rebaseOptions{}
+
+	rebaseCmd := &cobra.Command{
+		Use:   "rebase <base>",
+		Short: "Replay the commits of the branch onto another; -i plans it first",
+		Long: `Replay the commits of the current branch that <base> does not have onto
+<base>, as new commits.
+
+With -i (--interactive) the commits are listed first, oldest first, to plan
+what happens to each: J and K move a commit down or up, s squashes it into the
+one above it (combining their messages), f fixes it up into it (dropping its
+message), d drops it and p picks it as it is. r rewords it in an editor, where
+ctrl+g has the AI provider write a new message from the commit's changes (or,
+with --offline, one from the names of the files it changed). enter runs the
+plan and q gives it up. Merge commits are left out, as git does.
+
+When a commit conflicts the rebase stops and lists the conflicted files.
+'bgit resolve' resolves them and carries on with the plan; 'bgit rebase
+--abort' gives up, putting the branch back as it was.`,
+		Example: `  bgit rebase main
+  bgit rebase -i main
+  bgit rebase -i HEAD~5
+  bgit rebase --abort`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.abort {
+				if len(args) > 0 {
+					return errors.New("--abort takes no base")
+				}
+				return runRebaseAbort(d)
+			}
+			if len(args) != 1 {
+				return errors.New("name the branch or commit to rebase onto")
+			}
+			return runRebase(cmd.Context(), d, args[0], opts)
+		},
+	}
+
+	rebaseCmd.Flags().BoolVarP(&opts.interactive, "interactive", "i", false, "Plan the rebase in a list of the commits first")
+	rebaseCmd.Flags().BoolVar(&opts.abort, "abort", false, "Give up a rebase that stopped")
+	rebaseCmd.MarkFlagsMutuallyExclusive("interactive", "abort")
+
+	return rebaseCmd
+}
+
+// rebaseRepo is what 'bgit rebase' needs from the repository.
+type rebaseRepo interface {
+	OperationInProgress() gitService.Operation
+	ResolveCommit(rev string) (*object.Commit, error)
+	HeadBranch() (name string, detached bool, err error)
+	CommitsBetween(since, until plumbing.Hash) ([]*object.Commit, error)
+	Integrate(upstream string, rebase bool) error
+	RebaseInteractive(onto string, steps []gitService.RebaseStep) error
+	Conflicts() ([]gitService.Conflict, error)
+	CommitPatch(c *object.Commit) (*object.Patch, error)
+	AbortRebase() error
+}
+
+func runRebase(ctx context.Context, d *Deps, base string, opts *rebaseOptions) error {
+	client, err := openRepo[rebaseRepo](d)
+	if err != nil {
+		return err
+	}
+	if op := client.OperationInProgress(); op != gitService.NoOperation {
+		return fmt.Errorf("a %s is in progress; finish it with 'bgit resolve' first", op)
+	}
+	onto, err := client.ResolveCommit(base)
+	if err != nil {
+		return err
+	}
+	head, err := client.ResolveCommit("HEAD")
+	if err != nil {
+		return err
+	}
+	branch, detached, err := client.HeadBranch()
+	if err != nil {
+		return err
+	}
+	if detached {
+		branch = "HEAD"
+	}
+
+	commits, err := client.CommitsBetween(onto.Hash, head.Hash)
+	if err != nil {
+		return err
+	}
+	commits = slices.DeleteFunc(commits, func(c *object.Commit) bool { return c.NumParents() > 1 })
+	slices.Reverse(commits)
+
+	if !opts.interactive {
+		if err := client.Integrate(base, true); err != nil {
+			return rebaseStopped(d, client, err)
+		}
+		if len(commits) == 0 {
+			d.infof("%sFast-forwarded %s to %s\n", ui.Icon("✓"), branch, base)
+			return nil
+		}
+		d.infof("%sRebased %s onto %s: %s replayed\n", ui.Icon("✓"), branch, base, plural(len(commits), "commit"))
+		return nil
+	}
+
+	if len(commits) == 0 {
+		d.infof("Nothing to rebase: %s has no commits that %s does not.\n", branch, base)
+		return nil
+	}
+	if !ui.IsInteractive(d.IO.In, d.IO.Out) {
+		return errors.New("rebase -i needs a terminal to plan the rebase in")
+	}
+	original := make([]rebasetool.Commit, 0, len(commits))
+	for _, c := range commits {
+		original = append(original, rebasetool.Commit{Hash: c.Hash.String(), Message: c.Message, Action: rebasetool.Pick})
+	}
+
+	d.flushOut()
+	term, _ := ui.TerminalFile(d.IO.Out)
+	plan, run, err := rebasetool.Run(term, d.IO.In, original, rebasetool.Options{
+		Branch:     branch,
+		Onto:       base,
+		Regenerate: rebaseMessage(ctx, d, client),
+	})
+	if err != nil {
+		return err
+	}
+	if !run {
+		d.infof("Rebase given up; nothing changed.\n")
+		return nil
+	}
+
+	steps := make([]gitService.RebaseStep, 0, len(plan))
+	for _, c := range plan {
+		steps = append(steps, gitService.RebaseStep{
+			Action:  gitService.RebaseAction(c.Action),
+			Commit:  plumbing.NewHash(c.Hash),
+			Message: c.Message,
+		})
+	}
+	if err := client.RebaseInteractive(base, steps); err != nil {
+		return rebaseStopped(d, client, err)
+	}
+
+	after, err := client.ResolveCommit("HEAD")
+	if err != nil {
+		return err
+	}
+	d.infof("%sRebased %s onto %s: %s\n", ui.Icon("✓"), branch, base, rebaseSummary(plan))
+	d.infof("HEAD is at %s %s\n", after.Hash.String()[:7], strings.SplitN(strings.TrimSpace(after.Message), "\n", 2)[0])
+	return nil
+}
+
+// rebaseSummary counts what the plan did, like "2 picked, 1 squashed".
+func rebaseSummary(plan []rebasetool.Commit) string {
+	done := map[rebasetool.Action]string{
+		rebasetool.Pick:   "picked",
+		rebasetool.Reword: "reworded",
+		rebasetool.Squash: "squashed",
+		rebasetool.Fixup:  "fixed up",
+		rebasetool.Drop:   "dropped",
+	}
+	var parts []string
+	for _, action := range []rebasetool.Action{rebasetool.Pick, rebasetool.Reword, rebasetool.Squash, rebasetool.Fixup, rebasetool.Drop} {
+		n := 0
+		for _, c := range plan {
+			if c.Action == action {
+				n++
+			}
+		}
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, done[action]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// rebaseStopped lists the files a rebase stopped on, with how to go on,
+// and returns err.
+func rebaseStopped(d *Deps, client rebaseRepo, err error) error {
+	if !errors.As(err, new(gitService.ErrIntegrateConflict)) {
+		return err
+	}
+	d.flushOut()
+	if conflicts, cerr := client.Conflicts(); cerr == nil {
+		fmt.Fprintln(d.IO.ErrOut, "Conflicted files:")
+		for _, c := range conflicts {
+			fmt.Fprintf(d.IO.ErrOut, "  %s %s\n", ui.Bullet(), c.Path)
+		}
+	}
+	fmt.Fprintln(d.IO.ErrOut, "Hint: run 'bgit resolve' to resolve the conflicts and carry on with the rebase, or 'bgit rebase --abort' to give up")
+	return err
+}
+
+// rebaseMessage writes a new message for a commit being reworded, from the
+// changes it made, as 'bgit commit' would for them.
+func rebaseMessage(ctx context.Context, d *Deps, client rebaseRepo) func(hash string) (string, error) {
+	cfg := d.Config.Get()
+	return func(hash string) (string, error) {
+		c, err := client.ResolveCommit(hash)
+		if err != nil {
+			return "", err
+		}
+		patch, err := client.CommitPatch(c)
+		if err != nil {
+			return "", fmt.Errorf("failed to diff %s: %w", hash[:7], err)
+		}
+		var message string
+		if d.Offline {
+			message = commitgenService.HeuristicMessage(patch.String())
+		} else {
+			pr, pw := io.Pipe()
+			go func() { pw.CloseWithError(patch.Encode(pw)) }()
+			diff, err := commitgenService.ReadDiff(pr, promptLimits(d))
+			pr.Close()
+			if err != nil {
+				return "", fmt.Errorf("failed to diff %s: %w", hash[:7], err)
+			}
+			if message, err = d.CommitGen.GenerateCommitMessage(ctx, diff, cfg.AIProvider); err != nil {
+				return "", fmt.Errorf("%s provider failed: %w", cfg.AIProvider.Name, err)
+			}
+			if message, err = commitgenService.PostProcess(ctx, message, cfg.Message.PostProcess, secretEnv(d)); err != nil {
+				return "", err
+			}
+		}
+		if cfg.Message.BodyWidth > 0 {
+			message = commitgenService.WrapBody(message, cfg.Message.BodyWidth)
+		}
+		return message, nil
+	}
+}
+
+func runRebaseAbort(d *Deps) error {
+	client, err := openRepo[rebaseRepo](d)
+	if err != nil {
+		return err
+	}
+	if client.OperationInProgress() != gitService.Rebase {
+		return errors.New("no rebase to abort")
+	}
+	if err := client.AbortRebase(); err != nil {
+		return err
+	}
+	head, err := client.ResolveCommit("HEAD")
+	if err != nil {
+		return err
+	}
+	d.infof("%sRebase aborted; HEAD is back at %s %s\n", ui.Icon("✓"), head.Hash.String()[:7], strings.SplitN(strings.TrimSpace(head.Message), "\n", 2)[0])
+	return nil
+}
@@ -0,0 +1,502 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	forgeService "github.com/endalk200/bgit/internal/services/forge"
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	releaseService "github.com/endalk200/bgit/internal/services/release"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/pipeline"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+type releaseOptions struct {
+	assets     []string
+	changelog  string
+	remote     string
+	draft      bool
+	prerelease bool
+	dryRun     bool
+	abort      bool
+}
+
+func newReleaseCmd(d *Deps) *cobra.Command {
+	opts := &releaseOptions{}
+
+	releaseCmd := &cobra.Command{
+		Use:   "release <version|auto>",
+		Short: "Tag a release, update the changelog and publish it on GitHub",
+		Long: `Cut a release of the current branch in one go:
+
+  1. check that nothing is uncommitted and no merge or rebase is under way
+  2. pick the version: the one given, or with 'auto' the next one after the
+     latest version tag, by the conventional commits since then (a breaking
+     change bumps the major version, a feature the minor, anything else the
+     patch; before 1.0.0 one step less)
+  3. add a section for the release to CHANGELOG.md, grouping the commits by
+     type, and commit it
+  4. create an annotated tag and push it with the branch
+  5. publish a GitHub or GitLab release with the changelog section as its
+     notes
+  6. upload the files matching --assets to it
+
+Each step checks whether it was done already, so when one fails (a push
+rejected, the network gone) running the same command again picks up where
+it stopped. 'auto' keeps to the version it chose until the release is
+finished; --abort forgets an unfinished release, leaving any tag or commit
+it made in place.
+
+--dry-run shows the version and changelog section without changing
+anything. Publishing on GitHub needs GITHUB_TOKEN (or GH_TOKEN) with write
+access to the repository, and on GitLab GITLAB_TOKEN (or GITLAB_ACCESS_TOKEN)
+with the api scope. GitLab has neither drafts nor a pre-release mark:
+--draft is refused there and --prerelease does nothing.`,
+		Example: `  bgit release auto
+  bgit release v1.4.0 --assets 'dist/*.tar.gz' --assets dist/checksums.txt
+  bgit release auto --dry-run`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if opts.abort {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.abort {
+				return abortRelease(d)
+			}
+			return runRelease(cmd.Context(), d, args[0], opts)
+		},
+	}
+
+	releaseCmd.Flags().StringArrayVar(&opts.assets, "assets", nil, "Upload the files matching this glob to the release (repeatable)")
+	releaseCmd.Flags().StringVar(&opts.changelog, "changelog", "CHANGELOG.md", "Changelog file to add the release section to")
+	releaseCmd.Flags().StringVar(&opts.remote, "remote", "origin", "Remote to push the tag and branch to")
+	releaseCmd.Flags().BoolVar(&opts.draft, "draft", false, "Publish the release as a draft (GitHub only)")
+	releaseCmd.Flags().BoolVar(&opts.prerelease, "prerelease", false, "Mark the release as a pre-release (implied by versions such as v2.0.0-rc.1)")
+	releaseCmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show the version and changelog section without changing anything")
+	releaseCmd.Flags().BoolVar(&opts.abort, "abort", false, "Forget an unfinished release so a new one can start")
+
+	return releaseCmd
+}
+
+// releaseState is what a release records in the git directory while it is
+// under way, so a rerun after a failure resumes the same release.
+type releaseState struct {
+	Version  string `json:"version"`
+	Previous string `json:"previous,omitempty"`
+	// Notes is the changelog section, without its heading.
+	Notes string `json:"notes"`
+	// Commit is the changelog commit, once made.
+	Commit string `json:"commit,omitempty"`
+}
+
+// releaseStateFile lives in the git directory, next to git's own state.
+const releaseStateFile = "bgit-release.json"
+
+func loadReleaseState(client gitDirRepo) (*releaseState, string, error) {
+	path, err := client.GitPath(releaseStateFile)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, path, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	var st releaseState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, "", fmt.Errorf("unreadable release state in %s (remove it or run 'bgit release --abort'): %w", path, err)
+	}
+	return &st, path, nil
+}
+
+func saveReleaseState(path string, st *releaseState) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// releaseRepo is what 'bgit release' needs from the repository.
+type releaseRepo interface {
+	ForgeRepo
+	gitDirRepo
+	OperationInProgress() gitService.Operation
+	StagedFiles() ([]string, error)
+	ModifiedFiles() ([]string, error)
+	DeletedFiles() ([]string, error)
+	HeadBranch() (name string, detached bool, err error)
+	AddFiles(files []string) (gitService.AddResult, error)
+	Commit(message string, opts gitService.CommitOptions) (*object.Commit, error)
+	ResolveCommit(rev string) (*object.Commit, error)
+	Tags() ([]gitService.Tag, error)
+	CreateTag(name string, target plumbing.Hash, message string) error
+	Push(remote string, refspecs ...string) error
+	CommitsSince(since plumbing.Hash) ([]*object.Commit, error)
+}
+
+func abortRelease(d *Deps) error {
+	client, err := openRepo[releaseRepo](d)
+	if err != nil {
+		return err
+	}
+	st, path, err := loadReleaseState(client)
+	if err != nil {
+		return err
+	}
+	if st == nil {
+		d.infoln("No release is under way.")
+		return nil
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	d.infof("Forgot the release of %s. Its tag and changelog commit, if made, are left as they are.\n", st.Version)
+	return nil
+}
+
+func runRelease(ctx context.Context, d *Deps, arg string, opts *releaseOptions) error {
+	client, err := openRepo[releaseRepo](d)
+	if err != nil {
+		return err
+	}
+	st, statePath, err := loadReleaseState(client)
+	if err != nil {
+		return err
+	}
+	if st != nil && arg != "auto" && arg != st.Version {
+		return fmt.Errorf("the release of %s is unfinished; run 'bgit release %s' to finish it or 'bgit release --abort' to drop it", st.Version, st.Version)
+	}
+	resuming := st != nil
+
+	var (
+		forge   ReleaseForge
+		branch  string
+		version releaseService.Version
+		rel     forgeService.Release
+		section string
+		assets  []string
+	)
+	if !resuming {
+		st = &releaseState{}
+	}
+
+	stages := []pipeline.Stage{
+		{Name: "Check working tree", Run: func(ctx context.Context) (string, error) {
+			if op := client.OperationInProgress(); op != gitService.NoOperation {
+				return "", fmt.Errorf("a %s is in progress; finish it first", op)
+			}
+			var dirty []string
+			for _, list := range []func() ([]string, error){client.StagedFiles, client.ModifiedFiles, client.DeletedFiles} {
+				files, err := list()
+				if err != nil {
+					return "", err
+				}
+				dirty = append(dirty, files...)
+			}
+			// A release that stopped after writing the changelog may
+			// leave it uncommitted.
+			dirty = slices.DeleteFunc(dirty, func(f string) bool { return resuming && f == filepath.ToSlash(opts.changelog) })
+			if len(dirty) > 0 {
+				slices.Sort(dirty)
+				return "", fmt.Errorf("uncommitted changes in %s; commit or stash them first", strings.Join(slices.Compact(dirty), ", "))
+			}
+			var detached bool
+			if branch, detached, err = client.HeadBranch(); err != nil {
+				return "", err
+			}
+			if detached {
+				return "", errors.New("HEAD is detached; check out the branch to release from")
+			}
+			for _, pattern := range opts.assets {
+				matches, err := filepath.Glob(pattern)
+				if err != nil {
+					return "", fmt.Errorf("--assets %q: %w", pattern, err)
+				}
+				if len(matches) == 0 {
+					return "", fmt.Errorf("--assets %q matches no files", pattern)
+				}
+				assets = append(assets, matches...)
+			}
+			if !opts.dryRun {
+				// Fail now rather than after tagging.
+				if forge, err = openReleaseForge(d, client); err != nil {
+					return "", fmt.Errorf("cannot publish the release: %w", err)
+				}
+			}
+			return "clean on " + branch, nil
+		}},
+		{Name: "Pick version", Run: func(ctx context.Context) (string, error) {
+			if resuming {
+				if version, err = releaseService.ParseVersion(st.Version); err != nil {
+					return "", err
+				}
+				section = st.Notes
+				return st.Version + ", resuming", nil
+			}
+			detail, err := pickVersion(client, forge, arg, st, &version, &section)
+			if err != nil || opts.dryRun {
+				return detail, err
+			}
+			return detail, saveReleaseState(statePath, st)
+		}},
+		{Name: "Write changelog", Run: func(ctx context.Context) (string, error) {
+			existing, err := os.ReadFile(opts.changelog)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return "", err
+			}
+			if releaseService.HasSection(string(existing), version.String()) {
+				return "", pipeline.Skip(opts.changelog + " has " + version.String())
+			}
+			if opts.dryRun {
+				return "", pipeline.Skip("dry run")
+			}
+			updated := releaseService.InsertSection(string(existing), section)
+			if err := os.WriteFile(opts.changelog, []byte(updated), 0o644); err != nil {
+				return "", err
+			}
+			return opts.changelog, nil
+		}},
+		{Name: "Commit changelog", Run: func(ctx context.Context) (string, error) {
+			if opts.dryRun {
+				return "", pipeline.Skip("dry run")
+			}
+			if _, err := client.AddFiles([]string{opts.changelog}); err != nil {
+				return "", err
+			}
+			staged, err := client.StagedFiles()
+			if err != nil {
+				return "", err
+			}
+			if !slices.Contains(staged, filepath.ToSlash(opts.changelog)) {
+				return "", pipeline.Skip("nothing to commit")
+			}
+			commit, err := client.Commit("chore(release): "+version.String(), gitService.CommitOptions{})
+			if err != nil {
+				return "", fmt.Errorf("failed to commit %s: %w", opts.changelog, err)
+			}
+			st.Commit = commit.Hash.String()
+			if err := saveReleaseState(statePath, st); err != nil {
+				return "", err
+			}
+			return commit.Hash.String()[:7], nil
+		}},
+		{Name: "Create tag", Run: func(ctx context.Context) (string, error) {
+			if opts.dryRun {
+				return "", pipeline.Skip("dry run")
+			}
+			head, err := client.ResolveCommit("HEAD")
+			if err != nil {
+				return "", err
+			}
+			tags, err := client.Tags()
+			if err != nil {
+				return "", err
+			}
+			for _, t := range tags {
+				if t.Name != version.String() {
+					continue
+				}
+				if t.Commit != head.Hash {
+					return "", fmt.Errorf("tag %s already exists on %s, not on HEAD", t.Name, t.Commit.String()[:7])
+				}
+				return "", pipeline.Skip(t.Name + " exists")
+			}
+			if err := client.CreateTag(version.String(), head.Hash, "Release "+version.String()); err != nil {
+				return "", err
+			}
+			return version.String(), nil
+		}},
+		{Name: "Push", Run: func(ctx context.Context) (string, error) {
+			if opts.dryRun {
+				return "", pipeline.Skip("dry run")
+			}
+			if err := client.Push(opts.remote, "refs/heads/"+branch, "refs/tags/"+version.String()); err != nil {
+				return "", fmt.Errorf("failed to push to %s: %w", opts.remote, err)
+			}
+			return opts.remote, nil
+		}},
+		{Name: "Publish release", Run: func(ctx context.Context) (string, error) {
+			if opts.dryRun {
+				return "", pipeline.Skip("dry run")
+			}
+			existing, ok, err := forge.ReleaseByTag(ctx, version.String())
+			if err != nil {
+				return "", fmt.Errorf("failed to look up the release: %w", err)
+			}
+			if ok {
+				rel = existing
+				return "", pipeline.Skip("already published")
+			}
+			rel, err = forge.CreateRelease(ctx, forgeService.NewRelease{
+				Tag:        version.String(),
+				Name:       version.String(),
+				Notes:      releaseNotes(section),
+				Draft:      opts.draft,
+				Prerelease: opts.prerelease || version.Pre != "",
+			})
+			if err != nil {
+				return "", fmt.Errorf("failed to publish the release: %w", err)
+			}
+			return forge.Repo().Owner + "/" + forge.Repo().Name, nil
+		}},
+		{Name: "Upload assets", Run: func(ctx context.Context) (string, error) {
+			switch {
+			case len(opts.assets) == 0:
+				return "", pipeline.Skip("no --assets")
+			case opts.dryRun:
+				return "", pipeline.Skip(fmt.Sprintf("dry run, %s", plural(len(assets), "file")))
+			}
+			uploaded := 0
+			for _, path := range assets {
+				name := filepath.Base(path)
+				if slices.Contains(rel.Assets, name) {
+					continue
+				}
+				if err := uploadAsset(ctx, forge, rel, path); err != nil {
+					return "", fmt.Errorf("failed to upload %s: %w", path, err)
+				}
+				uploaded++
+			}
+			detail := plural(uploaded, "file")
+			if skipped := len(assets) - uploaded; skipped > 0 {
+				detail += fmt.Sprintf(", %d already there", skipped)
+			}
+			return detail, nil
+		}},
+	}
+
+	if err := runPipeline(ctx, d, stages); err != nil {
+		if st.Version != "" && !opts.dryRun {
+			return fmt.Errorf("%w\nFix the problem and run 'bgit release %s' again to pick up where this stopped.", err, version)
+		}
+		return err
+	}
+
+	if opts.dryRun {
+		d.infof("\nThe changelog section for %s would be:\n\n%s", version, section)
+		return nil
+	}
+	if err := os.Remove(statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	d.infof("\n%sReleased %s", ui.Icon("🚀"), version)
+	if rel.URL != "" {
+		d.infof(": %s", rel.URL)
+	}
+	d.infoln()
+	return nil
+}
+
+// pickVersion settles the version to release from arg (a version or
+// "auto") and the commits since the latest version tag, and renders the
+// changelog section for it into st.
+func pickVersion(client releaseRepo, forge ReleaseForge, arg string, st *releaseState, version *releaseService.Version, section *string) (string, error) {
+	tags, err := client.Tags()
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(tags))
+	byName := map[string]gitService.Tag{}
+	for _, t := range tags {
+		names = append(names, t.Name)
+		byName[t.Name] = t
+	}
+	latest, latestTag, found := releaseService.Latest(names)
+
+	var since gitService.Tag
+	if found {
+		since = byName[latestTag]
+	}
+	commits, err := client.CommitsSince(since.Commit)
+	if err != nil {
+		return "", err
+	}
+	changes := make([]releaseService.Change, 0, len(commits))
+	for _, c := range commits {
+		changes = append(changes, releaseService.ParseChange(c.Hash.String(), c.Message))
+	}
+
+	detail := ""
+	if arg == "auto" {
+		if !found {
+			return "", errors.New("no version tag to count from; give the first version, e.g. 'bgit release v0.1.0'")
+		}
+		if len(commits) == 0 {
+			return "", fmt.Errorf("nothing to release: no commits since %s", latestTag)
+		}
+		bump := releaseService.NextBump(latest, changes)
+		*version = latest.Apply(bump)
+		detail = fmt.Sprintf("%s, %s bump", version, bump)
+	} else {
+		if *version, err = releaseService.ParseVersion(arg); err != nil {
+			return "", err
+		}
+		if found && !latest.Less(*version) {
+			return "", fmt.Errorf("%s is not newer than the latest release, %s", version, latestTag)
+		}
+		detail = version.String()
+	}
+	if found {
+		detail += fmt.Sprintf(", %s since %s", plural(len(commits), "commit"), latestTag)
+	}
+
+	compareURL := ""
+	if found && forge != nil {
+		compareURL = forge.CompareURL(latestTag, version.String())
+	}
+	*section = releaseService.Changelog(version.String(), time.Now(), changes, compareURL)
+	*st = releaseState{Version: version.String(), Previous: latestTag, Notes: *section}
+	return detail, nil
+}
+
+// openReleaseForge is openForge for publishing a release, on GitHub or
+// GitLab.
+func openReleaseForge(d *Deps, client ForgeRepo) (ReleaseForge, error) {
+	forge, err := d.OpenReleaseForge(client)
+	switch {
+	case errors.Is(err, forgeService.ErrUnsupportedForge):
+		return nil, errors.New("origin is not hosted on GitHub or GitLab")
+	case errors.Is(err, errOffline):
+		return nil, errors.New("offline")
+	case errors.As(err, new(gitService.ErrNoRemote)):
+		return nil, errors.New("the repository has no origin remote")
+	case err != nil:
+		return nil, err
+	}
+	return forge, nil
+}
+
+// releaseNotes is the changelog section without its heading, which the
+// release shows as its title already.
+func releaseNotes(section string) string {
+	_, body, _ := strings.Cut(section, "\n")
+	return strings.TrimSpace(body)
+}
+
+func uploadAsset(ctx context.Context, forge ReleaseForge, rel forgeService.Release, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return forge.UploadAsset(ctx, rel, filepath.Base(path), f, info.Size())
+}
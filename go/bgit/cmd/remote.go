@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+func newRemoteCmd(d *Deps) *cobra.Command {
+	remoteCmd := &cobra.Command{
+		Use:   "remote",
+		Short: "List remotes and choose the mirrors that push keeps in step",
+		Long: `List the remotes with their URLs. Without a subcommand they are listed, as
+with 'remote list'; add them with 'git remote add'.
+
+A remote marked as a mirror with 'remote mirror' is kept in step with the
+others: after every 'bgit push' the branch pushed, and the tags with --tags,
+are pushed on to each mirror too, replacing whatever the mirror had, so a
+backup or a second host always matches. A mirror that cannot be reached
+fails the push after the others are done, each remote with its own line.
+
+A remote reached over SSH can have a key of its own, set with 'remote key',
+which the list shows under it.`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRemoteList(d)
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:         "list",
+		Aliases:     []string{"ls"},
+		Short:       "List remotes with their URL",
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRemoteList(d)
+		},
+	}
+
+	mirrorCmd := &cobra.Command{
+		Use:   "mirror <name>...",
+		Short: "Keep remotes in step with every push",
+		Long: `Mark remotes as mirrors: every 'bgit push' then pushes the branch on to them
+as well, forcing it to match, and the tags too with --tags.`,
+		Example: `  git remote add backup git@backup.example.com:team/app.git
+  bgit remote mirror backup`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRemoteMirror(d, args, true)
+		},
+	}
+
+	unmirrorCmd := &cobra.Command{
+		Use:   "unmirror <name>...",
+		Short: "Stop keeping remotes in step with every push",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRemoteMirror(d, args, false)
+		},
+	}
+
+	var unsetKey bool
+	keyCmd := &cobra.Command{
+		Use:   "key <name> [<path>]",
+		Short: "Choose the SSH key to sign in to a remote with",
+		Long: `Set the private key bgit signs in to a remote with over SSH, in place of
+BGIT_SSH_KEY, the SSH agent or the usual keys in ~/.ssh, for a remote that
+takes another key than the rest, like a work account's. The key is checked
+to be one before it is set; BGIT_SSH_KEY_PASSPHRASE decrypts it when it
+has a passphrase.
+
+Without a path, the keys in ~/.ssh are listed to pick one from. --unset goes
+back to the usual keys.`,
+		Example: `  bgit remote key work ~/.ssh/id_ed25519_work
+  bgit remote key work
+  bgit remote key work --unset`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case unsetKey && len(args) == 2:
+				return errors.New("--unset takes no key path")
+			case unsetKey:
+				return runRemoteKey(d, args[0], "")
+			case len(args) == 2:
+				return runRemoteKey(d, args[0], args[1])
+			}
+			if !ui.IsInteractive(d.IO.In, d.IO.Out) {
+				return errors.New("give the path of the key; picking one needs a terminal")
+			}
+			d.flushOut()
+			term, _ := ui.TerminalFile(d.IO.Out)
+			return runRemoteKeyPicker(d, term, args[0])
+		},
+	}
+	keyCmd.Flags().BoolVar(&unsetKey, "unset", false, "Sign in with the usual keys again")
+
+	remoteCmd.AddCommand(listCmd, mirrorCmd, unmirrorCmd, keyCmd)
+	return remoteCmd
+}
+
+// remoteRepo is what the 'bgit remote' commands do to the repository.
+type remoteRepo interface {
+	Remotes() ([]gitService.Remote, error)
+	SetRemoteMirror(name string, mirror bool) error
+	SetRemoteSSHKey(name, path string) error
+}
+
+func runRemoteList(d *Deps) error {
+	client, err := openRepo[remoteRepo](d)
+	if err != nil {
+		return err
+	}
+	remotes, err := client.Remotes()
+	if err != nil {
+		return fmt.Errorf("failed to list remotes: %w", err)
+	}
+	entries := make([]ui.RemoteEntry, 0, len(remotes))
+	for _, r := range remotes {
+		e := ui.RemoteEntry{Name: r.Name, Mirror: r.Mirror, SSHKey: r.SSHKey}
+		if len(r.URLs) > 0 {
+			e.URL = r.URLs[0]
+		}
+		entries = append(entries, e)
+	}
+	if d.Output.JSON() {
+		return json.NewEncoder(d.IO.Out).Encode(entries)
+	}
+	fmt.Fprint(d.IO.Out, ui.RenderRemoteList(entries, ui.TerminalWidth(d.IO.Out)))
+	return nil
+}
+
+func runRemoteMirror(d *Deps, names []string, mirror bool) error {
+	client, err := openRepo[remoteRepo](d)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := client.SetRemoteMirror(name, mirror); err != nil {
+			return err
+		}
+		if mirror {
+			d.infof("%s%s is a mirror; bgit push keeps it in step\n", ui.Icon("✓"), name)
+		} else {
+			d.infof("%s%s is no longer a mirror\n", ui.Icon("✓"), name)
+		}
+	}
+	return nil
+}
+
+// runRemoteKey sets path as the SSH key of the remote name, or unsets its
+// key when path is empty.
+func runRemoteKey(d *Deps, name, path string) error {
+	client, err := openRepo[remoteRepo](d)
+	if err != nil {
+		return err
+	}
+	if path != "" {
+		if path, err = checkSSHKey(path); err != nil {
+			return err
+		}
+	}
+	if err := client.SetRemoteSSHKey(name, path); err != nil {
+		return err
+	}
+	if path == "" {
+		d.infof("%s%s signs in with the usual SSH keys again\n", ui.Icon("✓"), name)
+		return nil
+	}
+	d.infof("%s%s signs in with %s\n", ui.Icon("✓"), name, path)
+	return nil
+}
+
+// checkSSHKey makes sure path holds an SSH private key, encrypted or not,
+// and returns it absolute, or under ~ as given.
+func checkSSHKey(path string) (string, error) {
+	file := path
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		file = filepath.Join(home, rest)
+	} else {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return "", err
+		}
+		path, file = abs, abs
+	}
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	if _, err := ssh.ParseRawPrivateKey(pem); err != nil && !errors.As(err, new(*ssh.PassphraseMissingError)) {
+		return "", fmt.Errorf("%s is not an SSH private key: %w", path, err)
+	}
+	return path, nil
+}
+
+// runRemoteKeyPicker lists the keys in ~/.ssh on term, each with the type,
+// fingerprint and comment of its public half, and sets the one picked as
+// the SSH key of the remote name.
+func runRemoteKeyPicker(d *Deps, term io.Writer, name string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	pubs, _ := filepath.Glob(filepath.Join(home, ".ssh", "*.pub"))
+	type sshKey struct{ path, label string }
+	var keys []sshKey
+	nameWidth := 0
+	for _, pub := range pubs {
+		private := strings.TrimSuffix(pub, ".pub")
+		if _, err := os.Stat(private); err != nil {
+			continue
+		}
+		b, err := os.ReadFile(pub)
+		if err != nil {
+			continue
+		}
+		key, comment, _, _, err := ssh.ParseAuthorizedKey(b)
+		if err != nil {
+			continue
+		}
+		label := strings.TrimPrefix(key.Type(), "ssh-") + "  " + ssh.FingerprintSHA256(key)
+		if comment != "" {
+			label += "  " + comment
+		}
+		keys = append(keys, sshKey{path: "~/.ssh/" + filepath.Base(private), label: label})
+		nameWidth = max(nameWidth, ui.StringWidth(filepath.Base(private)))
+	}
+	if len(keys) == 0 {
+		return errors.New("no SSH keys with a .pub file in ~/.ssh; give the path of the key")
+	}
+	options := make([]huh.Option[string], 0, len(keys))
+	for _, k := range keys {
+		base := filepath.Base(k.path)
+		options = append(options, huh.NewOption(base+strings.Repeat(" ", nameWidth-ui.StringWidth(base))+"  "+k.label, k.path))
+	}
+
+	var picked string
+	err = huh.NewForm(huh.NewGroup(
+		huh.NewSelect[string]().
+			Title("Sign in to " + name + " with which key?").
+			Options(options...).
+			Height(min(len(options)+2, 15)).
+			Value(&picked),
+	)).WithInput(d.IO.In).WithOutput(term).Run()
+	if errors.Is(err, huh.ErrUserAborted) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return runRemoteKey(d, name, picked)
+}
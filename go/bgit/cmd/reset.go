@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+// errResetUnconfirmed is returned for a hard reset that would throw work
+// away when there is no terminal to confirm it on.
+var errResetUnconfirmed = errors.New("a hard reset that throws work away needs confirming; run it on a terminal, or pass --yes")
+
+var errResetAborted = errors.New("reset aborted; nothing was changed")
+
+type resetOptions struct {
+	soft   bool
+	mixed  bool
+	hard   bool
+	yes    bool
+	dryRun bool
+}
+
+func newResetCmd(d *Deps) *cobra.Command {
+	opts := &resetOptions{}
+
+	resetCmd := &cobra.Command{
+		Use:   "reset [--soft|--mixed|--hard] [<ref>]",
+		Short: "Move the current branch to another commit",
+		Long: `Move the current branch, or a detached HEAD, to <ref>, HEAD when left out,
+and bring as much of the rest in line with it as the mode says:
+
+  --soft    only the branch moves; the staging area and the working tree
+            stay as they are, so what the commits left behind changed shows
+            as staged, ready to commit again
+  --mixed   (default) the staging area matches <ref> too; staged changes and
+            those of the commits left behind become unstaged changes
+  --hard    the staging area and the tracked files match <ref>; uncommitted
+            changes to them are thrown away, untracked files stay
+
+Before resetting, bgit explains what is about to happen: the commits that
+leave the branch and join it, and for --hard the files whose changes are
+lost. A hard reset that loses commits or changes asks for confirmation
+first; without a terminal it needs --yes. --dry-run only explains.
+
+The commits left behind are not gone: 'bgit reset ORIG_HEAD' puts the
+branch back where it was before the last reset.`,
+		Example: `  bgit reset
+  bgit reset --soft HEAD~1
+  bgit reset --hard origin/main
+  bgit reset --hard --dry-run`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rev := "HEAD"
+			if len(args) == 1 {
+				rev = args[0]
+			}
+			return runReset(d, rev, opts)
+		},
+	}
+
+	resetCmd.Flags().BoolVar(&opts.soft, "soft", false, "Move only the branch")
+	resetCmd.Flags().BoolVar(&opts.mixed, "mixed", false, "Move the branch and reset the staging area (the default)")
+	resetCmd.Flags().BoolVar(&opts.hard, "hard", false, "Move the branch and reset the staging area and tracked files")
+	resetCmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Reset --hard without asking for confirmation")
+	resetCmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Explain the reset without doing it")
+	resetCmd.MarkFlagsMutuallyExclusive("soft", "mixed", "hard")
+
+	return resetCmd
+}
+
+// mode is the reset mode the flags ask for.
+func (o *resetOptions) mode() gitService.ResetMode {
+	switch {
+	case o.soft:
+		return gitService.ResetSoft
+	case o.hard:
+		return gitService.ResetHard
+	}
+	return gitService.ResetMixed
+}
+
+// resetRepo is what 'bgit reset' needs from the repository.
+type resetRepo interface {
+	ResolveCommit(rev string) (*object.Commit, error)
+	Reset(target plumbing.Hash, mode gitService.ResetMode) error
+	HeadBranch() (name string, detached bool, err error)
+	CommitsBetween(since, until plumbing.Hash) ([]*object.Commit, error)
+	ModifiedFiles() ([]string, error)
+	UntrackedFiles() ([]string, error)
+}
+
+func runReset(d *Deps, rev string, opts *resetOptions) error {
+	client, err := openRepo[resetRepo](d)
+	if err != nil {
+		return err
+	}
+	plan, err := resetPlan(client, rev, opts.mode())
+	if err != nil {
+		return err
+	}
+	if opts.dryRun {
+		fmt.Fprint(d.IO.Out, ui.RenderResetPlan(plan, ui.TerminalWidth(d.IO.Out)))
+		return nil
+	}
+
+	if opts.mode() == gitService.ResetHard && (len(plan.Leaving) > 0 || len(plan.Lost) > 0) && !opts.yes {
+		if err := confirmReset(d, plan); err != nil {
+			return err
+		}
+	} else {
+		d.infof("%s\n", ui.RenderResetPlan(plan, ui.TerminalWidth(d.IO.Out)))
+	}
+
+	to, err := client.ResolveCommit(plan.To)
+	if err != nil {
+		return err
+	}
+	if err := client.Reset(to.Hash, opts.mode()); err != nil {
+		return err
+	}
+	d.infof("%s%s is at %s %s\n", ui.Icon("✓"), plan.Branch, plan.To[:7], plan.ToSubject)
+	return nil
+}
+
+// resetPlan works out what resetting to rev in mode does.
+func resetPlan(client resetRepo, rev string, mode gitService.ResetMode) (ui.ResetPlan, error) {
+	head, err := client.ResolveCommit("HEAD")
+	if err != nil {
+		return ui.ResetPlan{}, err
+	}
+	to, err := client.ResolveCommit(rev)
+	if err != nil {
+		return ui.ResetPlan{}, err
+	}
+	branch, detached, err := client.HeadBranch()
+	if err != nil {
+		return ui.ResetPlan{}, err
+	}
+	if detached {
+		branch = "HEAD"
+	}
+
+	plan := ui.ResetPlan{
+		Mode:      string(mode),
+		Branch:    branch,
+		From:      head.Hash.String(),
+		To:        to.Hash.String(),
+		ToSubject: strings.SplitN(strings.TrimSpace(to.Message), "\n", 2)[0],
+	}
+	leaving, err := client.CommitsBetween(to.Hash, head.Hash)
+	if err != nil {
+		return ui.ResetPlan{}, err
+	}
+	joining, err := client.CommitsBetween(head.Hash, to.Hash)
+	if err != nil {
+		return ui.ResetPlan{}, err
+	}
+	plan.Leaving, plan.Joining = resetCommits(leaving), resetCommits(joining)
+
+	if mode == gitService.ResetHard {
+		changed, err := client.ModifiedFiles()
+		if err != nil {
+			return ui.ResetPlan{}, err
+		}
+		untracked, err := client.UntrackedFiles()
+		if err != nil {
+			return ui.ResetPlan{}, err
+		}
+		for _, f := range changed {
+			if !slices.Contains(untracked, f) {
+				plan.Lost = append(plan.Lost, f)
+			}
+		}
+		slices.Sort(plan.Lost)
+	}
+	return plan, nil
+}
+
+func resetCommits(commits []*object.Commit) []ui.ResetCommit {
+	out := make([]ui.ResetCommit, 0, len(commits))
+	for _, c := range commits {
+		out = append(out, ui.ResetCommit{Hash: c.Hash.String(), Subject: strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0]})
+	}
+	return out
+}
+
+// confirmReset shows plan on a terminal and asks whether to go ahead.
+func confirmReset(d *Deps, plan ui.ResetPlan) error {
+	if !ui.IsInteractive(d.IO.In, d.IO.Out) {
+		return errResetUnconfirmed
+	}
+	d.flushOut()
+	term, _ := ui.TerminalFile(d.IO.Out)
+	fmt.Fprint(term, ui.RenderResetPlan(plan, ui.TerminalWidth(term))+"\n")
+
+	goAhead := false
+	err := huh.NewForm(huh.NewGroup(
+		huh.NewConfirm().
+			Title("Reset --hard?").
+			Description(resetLosses(plan)).
+			Affirmative("Reset").
+			Negative("Abort").
+			Value(&goAhead),
+	)).WithInput(d.IO.In).WithOutput(term).Run()
+	if errors.Is(err, huh.ErrUserAborted) || (err == nil && !goAhead) {
+		return errResetAborted
+	}
+	return err
+}
+
+// resetLosses sums up what a hard reset to plan throws away.
+func resetLosses(plan ui.ResetPlan) string {
+	var losses []string
+	if len(plan.Leaving) > 0 {
+		losses = append(losses, plan.Branch+" loses "+plural(len(plan.Leaving), "commit"))
+	}
+	if len(plan.Lost) > 0 {
+		losses = append(losses, "the changes to "+plural(len(plan.Lost), "file")+" are lost")
+	}
+	return strings.Join(losses, "; ")
+}
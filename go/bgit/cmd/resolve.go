@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/mergetool"
+	"github.com/spf13/cobra"
+)
+
+type resolveOptions struct {
+	noContinue bool
+}
+
+func newResolveCmd(d *Deps) *cobra.Command {
+	opts := &resolveOptions{}
+
+	resolveCmd := &cobra.Command{
+		Use:     "resolve [paths...]",
+		Aliases: []string{"mergetool"},
+		Short:   "Resolve merge and rebase conflicts in an interactive merge tool",
+		Long: `Resolve the conflicts left by a merge, rebase, cherry-pick or revert.
+
+Each conflicted file opens in a three-pane merge tool: the base version and
+both sides of every conflicting hunk sit side by side above the result. Pick
+ours, theirs, both or the base for each hunk, or edit the result by hand,
+then write the file to stage it. Paths limit resolving to those files.
+
+Once nothing is left conflicted the stopped operation is continued, and if
+the next step of a rebase conflicts too the merge tool opens again. Use
+--no-continue to stop after staging the resolved files.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runResolve(d, args, opts)
+		},
+	}
+
+	resolveCmd.Flags().BoolVar(&opts.noContinue, "no-continue", false, "Stage resolved files without continuing the operation")
+
+	return resolveCmd
+}
+
+// errResolveNeedsTerminal is returned when the merge tool cannot be drawn.
+var errResolveNeedsTerminal = errors.New("resolve needs an interactive terminal")
+
+// resolveRepo is what 'bgit resolve' needs from the repository.
+type resolveRepo interface {
+	indexRepo
+	OperationInProgress() gitService.Operation
+	Conflicts() ([]gitService.Conflict, error)
+	ContinueOperation(op gitService.Operation) error
+	MergeConflict(c gitService.Conflict) (string, error)
+	ResolveConflict(path string, content []byte) error
+}
+
+func runResolve(d *Deps, paths []string, opts *resolveOptions) error {
+	client, err := openRepo[resolveRepo](d)
+	if err != nil {
+		return err
+	}
+
+	for {
+		op := client.OperationInProgress()
+		conflicts, err := client.Conflicts()
+		if err != nil {
+			return fmt.Errorf("failed to list conflicts: %w", err)
+		}
+		conflicts = filterConflicts(conflicts, paths)
+
+		if len(conflicts) == 0 && op == gitService.NoOperation {
+			fmt.Fprintln(d.IO.Out, "No conflicts to resolve.")
+			return nil
+		}
+
+		left, err := resolveConflicts(d, client, conflicts)
+		if err != nil {
+			return err
+		}
+		if left > 0 {
+			d.infof("%s still conflicted. Run 'bgit resolve' again when ready.\n", plural(left, "file"))
+			return nil
+		}
+		if op == gitService.NoOperation || opts.noContinue {
+			d.infof("%sAll conflicts resolved.\n", ui.Icon("✓"))
+			return nil
+		}
+
+		// Paths only narrow what is opened in the merge tool; other files
+		// may still be conflicted.
+		if remaining, err := client.Conflicts(); err != nil {
+			return fmt.Errorf("failed to list conflicts: %w", err)
+		} else if len(remaining) > 0 {
+			d.infof("%s still conflicted outside the given paths.\n", plural(len(remaining), "file"))
+			return nil
+		}
+
+		d.infof("Continuing %s...\n", op)
+		if err := client.ContinueOperation(op); err != nil {
+			return fmt.Errorf("git %s --continue failed: %w", op, err)
+		}
+		if client.OperationInProgress() == gitService.NoOperation {
+			d.infof("%s%s completed.\n", ui.Icon("✓"), capitalize(string(op)))
+			return nil
+		}
+		// A rebase picks the next commit and may stop on it; go round again.
+		paths = nil
+	}
+}
+
+// resolveConflicts opens the merge tool for every conflict in turn and stages
+// the files the user writes. It returns how many were left conflicted.
+func resolveConflicts(d *Deps, client resolveRepo, conflicts []gitService.Conflict) (left int, err error) {
+	if len(conflicts) == 0 {
+		return 0, nil
+	}
+
+	term, ok := ui.TerminalFile(d.IO.Out)
+	if !ok {
+		fmt.Fprintln(d.IO.ErrOut, "Conflicted files:")
+		for _, c := range conflicts {
+			fmt.Fprintf(d.IO.ErrOut, "  %s %s\n", ui.Bullet(), c.Path)
+		}
+		return 0, errResolveNeedsTerminal
+	}
+
+	for i, c := range conflicts {
+		if c.Binary() {
+			d.infof("Skipping %s: binary files must be resolved by hand\n", c.Path)
+			left++
+			continue
+		}
+
+		merged, err := client.MergeConflict(c)
+		if err != nil {
+			return 0, fmt.Errorf("failed to merge %s: %w", c.Path, err)
+		}
+		file, err := mergetool.Parse(c.Path, merged)
+		if err != nil {
+			return 0, err
+		}
+
+		d.flushOut()
+		outcome, err := mergetool.Run(term, d.IO.In, file)
+		if err != nil {
+			return 0, err
+		}
+
+		switch outcome {
+		case mergetool.Quit:
+			return left + len(conflicts) - i, nil
+		case mergetool.Skipped:
+			left++
+			continue
+		}
+
+		done, err := protectIndex(d, client)
+		if err != nil {
+			return 0, err
+		}
+		err = client.ResolveConflict(c.Path, []byte(file.Result()))
+		done()
+		if err != nil {
+			return 0, fmt.Errorf("failed to stage %s: %w", c.Path, err)
+		}
+		d.infof("  %s %s\n", ui.Bullet(), c.Path)
+	}
+	return left, nil
+}
+
+// filterConflicts keeps the conflicts at or below one of paths; no paths
+// keeps them all.
+func filterConflicts(conflicts []gitService.Conflict, paths []string) []gitService.Conflict {
+	if len(paths) == 0 {
+		return conflicts
+	}
+	var kept []gitService.Conflict
+	for _, c := range conflicts {
+		for _, p := range paths {
+			p = strings.TrimSuffix(p, "/")
+			if c.Path == p || strings.HasPrefix(c.Path, p+"/") {
+				kept = append(kept, c)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// capitalize upper-cases the first letter of an operation name.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/endalk200/bgit/internal/config"
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	riskService "github.com/endalk200/bgit/internal/services/risk"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/pipeline"
+	"github.com/go-git/go-git/v6/plumbing/object"
+)
+
+// errRiskTooHigh stops the commit pipeline when the risk score calls for a
+// confirmation that cannot be asked for.
+var errRiskTooHigh = errors.New("commit too risky to make unconfirmed")
+
+// fixHistory is how many recent commits are searched for bug fixes.
+const fixHistory = 200
+
+// commitRisk is the risk score as 'bgit commit --output json' prints it.
+type commitRisk struct {
+	Score   int                  `json:"score"`
+	Level   string               `json:"level"`
+	Factors []riskService.Factor `json:"factors"`
+}
+
+// riskRepo is what assessing the risk of the staged changes reads.
+type riskRepo interface {
+	promptDiffRepo
+	DiffStats(staged bool, paths []string) ([]gitService.FileStat, error)
+	Log(rev string, max int) ([]*object.Commit, error)
+	ChangedPaths(c *object.Commit) ([]string, error)
+}
+
+// assessRisk scores the staged files, refining the score with the AI
+// provider when risk.ai is set. A provider that cannot be asked leaves the
+// heuristic score as it is, and note says so.
+func assessRisk(ctx context.Context, d *Deps, client riskRepo, staged []string) (a riskService.Assessment, note string, err error) {
+	cfg := d.Config.Get()
+	stats, err := client.DiffStats(true, staged)
+	if err != nil {
+		return a, "", fmt.Errorf("failed to diff staged files: %w", err)
+	}
+
+	paths := make([]string, 0, len(stats))
+	for _, st := range stats {
+		paths = append(paths, renamedTo(st.Path))
+	}
+	generated, err := client.GeneratedFiles(paths, cfg.Generated.Patterns)
+	if err != nil {
+		return a, "", fmt.Errorf("failed to detect generated files: %w", err)
+	}
+	sensitive := append(append([]string(nil), riskService.DefaultSensitivePatterns...), cfg.Risk.Sensitive...)
+
+	in := riskService.Input{Fixes: map[string]int{}}
+	for i, st := range stats {
+		in.Files = append(in.Files, riskService.File{
+			Path:       paths[i],
+			Insertions: st.Insertions,
+			Deletions:  st.Deletions,
+			Generated:  generated[paths[i]],
+			Sensitive:  gitService.MatchGenerated(paths[i], sensitive),
+		})
+	}
+	commits, err := client.Log("", fixHistory)
+	if err != nil {
+		return a, "", err
+	}
+	for _, c := range commits {
+		if !riskService.IsBugFix(c.Message) {
+			continue
+		}
+		changed, err := client.ChangedPaths(c)
+		if err != nil {
+			return a, "", err
+		}
+		for _, p := range changed {
+			in.Fixes[p]++
+		}
+	}
+	a = riskService.Assess(in)
+
+	if !cfg.Risk.AI {
+		return a, "", nil
+	}
+	pd, err := buildPromptDiff(d, client, staged, false)
+	if err != nil {
+		return a, "", err
+	}
+	score, reason, err := d.RiskRater.RateRisk(ctx, pd.diff, riskFindings(a), cfg.AIProvider)
+	switch {
+	case ctx.Err() != nil:
+		return a, "", ctx.Err()
+	case errors.Is(err, errOffline):
+		return a, "offline, heuristics only", nil
+	case err != nil:
+		d.Log.Debug("risk rating failed", "provider", cfg.AIProvider.Name, "err", err)
+		return a, cfg.AIProvider.Name + " unavailable, heuristics only", nil
+	}
+	a.Refine(score, reason)
+	return a, "", nil
+}
+
+// renamedTo is the new name of a file stat path like "old => new".
+func renamedTo(path string) string {
+	if _, to, ok := strings.Cut(path, " => "); ok {
+		return to
+	}
+	return path
+}
+
+// riskFindings sums the heuristic factors up for the AI provider.
+func riskFindings(a riskService.Assessment) string {
+	parts := make([]string, 0, len(a.Factors))
+	for _, f := range a.Factors {
+		parts = append(parts, fmt.Sprintf("%s (%s)", strings.ToLower(f.Name), f.Detail))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// riskLevel names a score by the thresholds in the config: high from
+// risk.confirm_at, medium from risk.warn_at, low below that.
+func riskLevel(score int, cfg config.Risk) string {
+	switch {
+	case cfg.ConfirmAt > 0 && score >= cfg.ConfirmAt:
+		return "high"
+	case score >= cfg.WarnAt:
+		return "medium"
+	}
+	return "low"
+}
+
+// riskView prepares a risk score for display.
+func riskView(r commitRisk) ui.RiskView {
+	v := ui.RiskView{Score: r.Score, Level: r.Level}
+	for _, f := range r.Factors {
+		v.Factors = append(v.Factors, ui.RiskFactor{Name: f.Name, Points: f.Points, Detail: f.Detail})
+	}
+	return v
+}
+
+// checkRisk is the risk stage of the commit pipeline. The score is shown
+// with, from risk.warn_at, what makes it up; from risk.confirm_at the commit
+// pauses on a terminal to ask whether to go ahead, and fails elsewhere,
+// unless --accept-risk is given. A dry run never asks.
+func checkRisk(ctx context.Context, d *Deps, r commitRisk, opts *commitOptions) (string, error) {
+	detail := fmt.Sprintf("%d/100, %s", r.Score, r.Level)
+	if r.Score >= d.Config.Get().Risk.WarnAt && len(r.Factors) > 0 {
+		names := make([]string, 0, len(r.Factors))
+		for _, f := range r.Factors {
+			names = append(names, strings.ToLower(f.Name))
+		}
+		detail += ": " + strings.Join(names, ", ")
+	}
+	switch {
+	case r.Level != "high" || opts.dryRun:
+		return detail, nil
+	case opts.acceptRisk:
+		return detail + ", accepted", nil
+	case !ui.IsInteractive(d.IO.In, progressOut(d)):
+		return "", errRiskTooHigh
+	}
+
+	err := pipeline.Suspend(ctx, func() error {
+		term, _ := ui.TerminalFile(progressOut(d))
+		fmt.Fprint(term, "\n"+ui.RenderRisk(riskView(r), ui.TerminalWidth(term))+"\n")
+
+		goAhead := false
+		err := huh.NewForm(huh.NewGroup(
+			huh.NewConfirm().
+				Title("Commit anyway?").
+				Affirmative("Commit").
+				Negative("Abort").
+				Value(&goAhead),
+		)).WithInput(d.IO.In).WithOutput(term).Run()
+		if errors.Is(err, huh.ErrUserAborted) || (err == nil && !goAhead) {
+			return errCommitAborted
+		}
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return detail + ", confirmed", nil
+}
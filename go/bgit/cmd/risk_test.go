@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/endalk200/bgit/internal/config"
+	riskService "github.com/endalk200/bgit/internal/services/risk"
+)
+
+func TestRiskLevel(t *testing.T) {
+	cfg := config.Risk{WarnAt: 40, ConfirmAt: 70}
+	tests := []struct {
+		score int
+		cfg   config.Risk
+		want  string
+	}{
+		{0, cfg, "low"},
+		{39, cfg, "low"},
+		{40, cfg, "medium"},
+		{69, cfg, "medium"},
+		{70, cfg, "high"},
+		{100, cfg, "high"},
+		// Without confirm_at nothing is high.
+		{100, config.Risk{WarnAt: 40}, "medium"},
+	}
+	for _, tt := range tests {
+		if got := riskLevel(tt.score, tt.cfg); got != tt.want {
+			t.Errorf("riskLevel(%d, %+v) = %q, want %q", tt.score, tt.cfg, got, tt.want)
+		}
+	}
+}
+
+func TestCheckRisk(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Risk.WarnAt, cfg.Risk.ConfirmAt = 40, 70
+	factors := []riskService.Factor{{Name: "Large change", Points: 35}, {Name: "No tests", Points: 20}}
+
+	tests := []struct {
+		name   string
+		score  int
+		opts   commitOptions
+		detail string
+		err    error
+	}{
+		{"below warn_at", 39, commitOptions{}, "39/100, low", nil},
+		{"at warn_at", 40, commitOptions{}, "40/100, medium: large change, no tests", nil},
+		{"just below confirm_at", 69, commitOptions{}, "69/100, medium: large change, no tests", nil},
+		// Off a terminal there is no one to ask.
+		{"at confirm_at", 70, commitOptions{}, "", errRiskTooHigh},
+		{"above confirm_at", 95, commitOptions{}, "", errRiskTooHigh},
+		{"accepted", 70, commitOptions{acceptRisk: true}, "70/100, high: large change, no tests, accepted", nil},
+		{"dry run", 95, commitOptions{dryRun: true}, "95/100, high: large change, no tests", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			d := &Deps{
+				IO:     IOStreams{In: strings.NewReader(""), Out: &out, ErrOut: &out},
+				Config: fixedConfig{cfg: cfg},
+			}
+			r := commitRisk{Score: tt.score, Level: riskLevel(tt.score, cfg.Risk), Factors: factors}
+			detail, err := checkRisk(context.Background(), d, r, &tt.opts)
+			if !errors.Is(err, tt.err) || detail != tt.detail {
+				t.Errorf("checkRisk = %q, %v; want %q, %v", detail, err, tt.detail, tt.err)
+			}
+		})
+	}
+}
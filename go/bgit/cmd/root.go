@@ -1,23 +1,23 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"sync"
 
+	"github.com/charmbracelet/log"
 	"github.com/endalk200/bgit/internal/config"
+	"github.com/endalk200/bgit/internal/interrupt"
+	commitgenService "github.com/endalk200/bgit/internal/services/commitgen"
+	forgeService "github.com/endalk200/bgit/internal/services/forge"
+	gitService "github.com/endalk200/bgit/internal/services/git"
 	"github.com/spf13/cobra"
 )
 
-// rootCmd represents the base command when called without any subcommands.
-// bgit is a learning / experimental Git wrapper built with go-git and Cobra.
-// It aims to provide modern, readable output while exposing internal concepts
-// clearly for educational purposes. The goal is to be production-grade in
-// structure (error handling, separation of concerns, testability) while also
-// being approachable for someone studying how Git works under the hood.
-var rootCmd = &cobra.Command{
-	Use:   "bgit",
-	Short: "Modern, educational Git wrapper CLI",
-	Long: `bgit is a modern, educational Git wrapper built on top of the pure Go
+const rootLong = `bgit is a modern, educational Git wrapper built on top of the pure Go
 implementation of Git (go-git). It focuses on:
 
   • Clean, readable, colorized output
@@ -26,10 +26,43 @@ implementation of Git (go-git). It focuses on:
 
 Currently implemented subcommands:
 
-  status  – Show repository status (staged / unstaged / untracked) with color
-  add     – Stage file(s) or all changes with --all
-  commit  – Create a commit; auto-generates a message when -m not supplied
-  config  – View and manage configuration (AI provider settings)
+  status     – Show repository status (staged / unstaged / untracked) with color
+  add        – Stage file(s) or all changes with --all
+  keep       – Track empty directories by staging a .gitkeep in them
+  changelist – Group changed files into named changelists for separate commits
+  diff       – Show unstaged or staged (--staged) changes, or a --stat summary
+  commit     – Create a commit; auto-generates a message when -m not supplied
+  tests      – Show the tests the staged changes affect; --run runs them
+  push       – Push the branch; --when-green lands it once CI passes
+  fetch      – Download a remote's branches and report ahead/behind
+  pull       – Fetch the upstream and merge it, or --rebase onto it
+  merge      – Merge a branch in, fast-forwarding when it can; lists conflicts
+  rebase     – Replay the branch onto another; -i reorders, squashes, rewords
+  remote     – List remotes; 'remote mirror' keeps one in step with every push
+  reset      – Move the branch to another commit, --soft, --mixed or --hard
+  cherry-pick – Apply the changes of commits onto the branch, or --no-commit
+  stash      – Set changes aside, list them, and pop, apply or drop them
+  show       – Show a commit with its patch or --stat summary
+  explain    – Ask the AI provider to explain a commit or the staged changes
+  log        – Show the commit graph, or a file's history with -p and --follow
+  blame      – Show who last changed each line of a file, coloured by age
+  graph      – Export the commit graph of a range as SVG, HTML or DOT
+  activity   – Show a heatmap of commits per day; pick a day to list them
+  branch     – List, create, delete and rename branches
+  switch     – Switch branches, create one with -c, or pick one from a list
+  branches   – List branches; --compare shows ahead/behind, age and PRs
+  base       – Print the common ancestor of two branches or commits
+  ci         – Show CI runs for the branch, read failed logs, re-run jobs
+  issue      – Browse issues; 'issue start' branches off for one
+  tag        – List, create, delete and push tags
+  release    – Tag a version, update the changelog and publish a release
+  resolve    – Resolve merge / rebase conflicts in a three-pane merge tool
+  optimize   – Write the commit-graph that speeds up log and ahead/behind
+  config     – View and edit settings; 'config edit' opens a full-screen editor
+  setup      – Walk through the first-run setup (shown automatically once)
+  metrics    – Export commit and AI request metrics for Prometheus
+
+Any executable named bgit-<name> on your PATH runs as 'bgit <name>'.
 
 Examples:
   bgit status
@@ -40,41 +73,341 @@ Examples:
 
 Configuration:
   bgit uses Viper for configuration management. Settings are stored in
-  ~/.bgit.yaml by default. Use 'bgit config' to manage settings.
+  ~/.bgit.yaml by default, with ./.bgit.yaml of the repository on top.
+  Use 'bgit config' to manage settings.
 
-More commands will be added incrementally as learning exercises.`,
-}
+More commands will be added incrementally as learning exercises.`
 
-var cfgFile string
+// NewRootCmd builds the base command when called without any subcommands.
+// bgit is a learning / experimental Git wrapper built with go-git and Cobra.
+// It aims to provide modern, readable output while exposing internal concepts
+// clearly for educational purposes. The goal is to be production-grade in
+// structure (error handling, separation of concerns, testability) while also
+// being approachable for someone studying how Git works under the hood.
+//
+// Every subcommand receives the same Deps, so tests can build a root command
+// around fakes and drive it with SetArgs.
+func NewRootCmd(d *Deps) *cobra.Command {
+	var (
+		cfgFile string
+		debug   bool
+	)
 
-// Execute adds all child commands to the root command and sets flags appropriately.
-// This is called by main.main(). It only needs to happen once to the rootCmd.
-func Execute() {
-	err := rootCmd.Execute()
-	if err != nil {
-		os.Exit(1)
+	rootCmd := &cobra.Command{
+		Use:           "bgit",
+		Short:         "Modern, educational Git wrapper CLI",
+		Long:          rootLong,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		// Unknown commands reach RunE instead of cobra's own check, so the
+		// suggestions can take aliases and extensions into account.
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			return unknownCommand(cmd, args[0])
+		},
+		// Load configuration before running any command.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if debug {
+				d.Log.SetLevel(log.DebugLevel)
+			}
+			err := d.Config.Load(cfgFile, boolFlagOr(cmd, "offline", envTrue("BGIT_OFFLINE")))
+			if errors.As(err, new(config.ErrExtendsFailed)) {
+				// The shared settings are missing, but the command can
+				// still run on the rest.
+				for _, line := range strings.Split(err.Error(), "\n") {
+					fmt.Fprintf(d.IO.ErrOut, "warning: %s\n", line)
+				}
+			} else if err != nil {
+				return fmt.Errorf("loading config: %w", err)
+			}
+			if cmd.CommandPath() != "bgit config trust" {
+				warnUntrusted(d)
+			}
+			if !d.Config.FirstRun() {
+				return nil
+			}
+			if !wantsOnboarding(cmd, d) {
+				fmt.Fprintf(d.IO.ErrOut, "Created default config file at: %s\n", d.Config.Path())
+				return nil
+			}
+			err = runSetup(cmd.Root(), d)
+			if errors.Is(err, errSetupAborted) {
+				fmt.Fprintf(d.IO.ErrOut, "%v. Run 'bgit setup' to finish setting up.\n", err)
+				return nil
+			}
+			return err
+		},
 	}
-}
 
-func init() {
-	// Initialize config before running any commands
-	cobra.OnInitialize(initConfig)
+	rootCmd.SetIn(d.IO.In)
+	rootCmd.SetOut(d.IO.Out)
+	rootCmd.SetErr(d.IO.ErrOut)
 
-	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
-
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.bgit.yaml)")
+	rootCmd.PersistentFlags().StringP("output", "o", "text", "output format: text or json")
+	rootCmd.PersistentFlags().Bool("no-color", false, "disable colored output (also honors NO_COLOR)")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "only print results and errors (config: ui.quiet)")
+	rootCmd.PersistentFlags().Bool("no-emoji", false, "use plain ASCII instead of emoji and symbols (config: ui.no_emoji)")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "log diagnostic details to stderr")
+	rootCmd.PersistentFlags().Bool("offline", false, "never use the network: no AI, forge or remote calls (also BGIT_OFFLINE=1)")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+	rootCmd.AddCommand(
+		newStatusCmd(d),
+		newAddCmd(d),
+		newKeepCmd(d),
+		newChangelistCmd(d),
+		newDiffCmd(d),
+		newCommitCmd(d),
+		newTestsCmd(d),
+		newPushCmd(d),
+		newFetchCmd(d),
+		newPullCmd(d),
+		newMergeCmd(d),
+		newRebaseCmd(d),
+		newRemoteCmd(d),
+		newResetCmd(d),
+		newCherryPickCmd(d),
+		newStashCmd(d),
+		newShowCmd(d),
+		newExplainCmd(d),
+		newLogCmd(d),
+		newBlameCmd(d),
+		newGraphCmd(d),
+		newActivityCmd(d),
+		newBranchCmd(d),
+		newSwitchCmd(d),
+		newBranchesCmd(d),
+		newBaseCmd(d),
+		newCICmd(d),
+		newIssueCmd(d),
+		newTagCmd(d),
+		newReleaseCmd(d),
+		newResolveCmd(d),
+		newOptimizeCmd(d),
+		newConfigCmd(d),
+		newSetupCmd(d),
+		newHookCmd(d),
+		newMetricsCmd(d),
+	)
+
+	useMiddleware(rootCmd, defaultMiddleware(d)...)
+
+	return rootCmd
+}
+
+// defaultDeps is the composition root: it wires the concrete services used
+// by the real binary.
+func defaultDeps() *Deps {
+	streams := IOStreams{
+		In:     os.Stdin,
+		Out:    os.Stdout,
+		ErrOut: os.Stderr,
+	}
+
+	d := &Deps{
+		IO:        streams,
+		Config:    &storeConfig{},
+		CommitGen: CommitGeneratorFunc(commitgenService.GenerateCommitMessage),
+		Explainer: ExplainerFunc(commitgenService.Explain),
+		RiskRater: RiskRaterFunc(commitgenService.RateRisk),
+		Interrupt: interrupt.New(),
+		Log: log.NewWithOptions(streams.ErrOut, log.Options{
+			Level:           log.WarnLevel,
+			ReportTimestamp: true,
+		}),
+		OpenRepo: func() (GitService, error) {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return nil, ErrCanNotDetermineWorkingDirectory{Message: err.Error()}
+			}
+			return gitService.NewGitClient(cwd, gitService.Options{})
+		},
+		OpenForge: func(repo ForgeRepo) (Forge, error) {
+			remote, err := repo.RemoteURL("origin")
+			if err != nil {
+				return nil, err
+			}
+			return forgeService.Open(remote)
+		},
+		OpenReleaseForge: func(repo ForgeRepo) (ReleaseForge, error) {
+			remote, err := repo.RemoteURL("origin")
+			if err != nil {
+				return nil, err
+			}
+			if gitlab, err := forgeService.OpenGitLab(remote); err == nil {
+				return gitlab, nil
+			}
+			github, err := forgeService.Open(remote)
+			if err != nil {
+				return nil, err
+			}
+			return github, nil
+		},
+	}
+	d.CommitGen = measuredGenerator{next: d.CommitGen, d: d}
+	d.Explainer = measuredExplainer{next: d.Explainer, d: d}
+	d.RiskRater = measuredRiskRater{next: d.RiskRater, d: d}
+	return d
+}
+
+// storeConfig adapts a config.Store to ConfigStore. It holds the Store
+// Load returns; until then Get returns the defaults.
+type storeConfig struct {
+	mu    sync.RWMutex
+	store *config.Store
+}
+
+func (c *storeConfig) Load(cfgFile string, offline bool) error {
+	store, err := config.Load(config.Options{File: cfgFile, Offline: offline})
+	if store != nil {
+		c.mu.Lock()
+		c.store = store
+		c.mu.Unlock()
+	}
+	return err
+}
+
+// loaded returns the Store, or an error when Load has not run or failed.
+func (c *storeConfig) loaded() (*config.Store, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.store == nil {
+		return nil, errors.New("the configuration is not loaded")
+	}
+	return c.store, nil
+}
+
+func (c *storeConfig) Get() *config.Config {
+	if s, err := c.loaded(); err == nil {
+		return s.Get()
+	}
+	return config.Defaults()
+}
+
+func (c *storeConfig) SetProvider(name, envName string) error {
+	s, err := c.loaded()
+	if err != nil {
+		return err
+	}
+	return s.SetProvider(name, envName)
+}
+
+func (c *storeConfig) Set(key string, value any) error {
+	s, err := c.loaded()
+	if err != nil {
+		return err
+	}
+	return s.Set(key, value)
+}
+
+func (c *storeConfig) SetIn(layer config.Layer, key string, value any) error {
+	s, err := c.loaded()
+	if err != nil {
+		return err
+	}
+	return s.SetIn(layer, key, value)
+}
+
+func (c *storeConfig) Unset(layer config.Layer, key string) error {
+	s, err := c.loaded()
+	if err != nil {
+		return err
+	}
+	return s.Unset(layer, key)
+}
+
+func (c *storeConfig) LayerPath(layer config.Layer) string {
+	if s, err := c.loaded(); err == nil {
+		return s.LayerPath(layer)
+	}
+	return ""
+}
+
+func (c *storeConfig) Origin(key string) string {
+	if s, err := c.loaded(); err == nil {
+		return s.Origin(key)
+	}
+	return "default"
+}
+
+func (c *storeConfig) Current(setting config.Setting) string {
+	if s, err := c.loaded(); err == nil {
+		return s.Current(setting)
+	}
+	return ""
+}
+
+func (c *storeConfig) FirstRun() bool {
+	s, err := c.loaded()
+	return err == nil && s.FirstRun()
 }
 
-// initConfig reads in config file and ENV variables if set.
-func initConfig() {
-	if err := config.InitConfig(cfgFile); err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+func (c *storeConfig) Path() string {
+	if s, err := c.loaded(); err == nil {
+		return s.Path()
+	}
+	return ""
+}
+
+func (c *storeConfig) RefreshExtends() ([]string, error) {
+	s, err := c.loaded()
+	if err != nil {
+		return nil, err
+	}
+	return s.RefreshExtends()
+}
+
+func (c *storeConfig) Untrusted() []config.Untrusted {
+	if s, err := c.loaded(); err == nil {
+		return s.Untrusted()
+	}
+	return nil
+}
+
+func (c *storeConfig) Trust() ([]config.Untrusted, error) {
+	s, err := c.loaded()
+	if err != nil {
+		return nil, err
+	}
+	return s.Trust()
+}
+
+// Execute builds the command tree with the production dependencies and runs
+// it. This is called by main.main(). Errors returned by commands are printed
+// once here, so individual commands never call os.Exit.
+//
+// The command runs under a context that Ctrl-C cancels. If that happens,
+// registered cleanups (index restore, temp files, terminal mode) run before
+// exiting with the conventional status 130.
+func Execute() {
+	d := defaultDeps()
+	d.Interrupt.RestoreTerminal(os.Stdin.Fd())
+
+	root := NewRootCmd(d)
+	if code, ok := runExtension(root, d, os.Args[1:]); ok {
+		os.Exit(code)
+	}
+
+	ctx, stop := d.Interrupt.Notify(context.Background())
+	err := root.ExecuteContext(ctx)
+	stop()
+
+	if d.Interrupt.Interrupted() {
+		d.Interrupt.RunCleanups()
+		fmt.Fprintln(d.IO.ErrOut, "interrupted")
+		os.Exit(interrupt.ExitCode)
+	}
+	if err != nil {
+		fmt.Fprintf(d.IO.ErrOut, "error: %v\n", err)
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/endalk200/bgit/internal/config"
+)
+
+func TestStoreConfigConcurrentLoad(t *testing.T) {
+	t.Parallel()
+	c := &storeConfig{}
+	if got := c.Get(); got.Message.BodyWidth != config.DefaultBodyWidth {
+		t.Errorf("Get before Load has body width %d, want the default", got.Message.BodyWidth)
+	}
+	if err := c.Set("message.body_width", 60); err == nil {
+		t.Error("Set before Load saved nowhere without failing")
+	}
+
+	// Run with -race: the Store is replaced under readers and writers.
+	file := filepath.Join(t.TempDir(), "bgit.yaml")
+	if err := os.WriteFile(file, []byte("ui:\n  quiet: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			if err := c.Load(file, true); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			// Until a Load has finished there is nothing to set.
+			_ = c.Set("message.body_width", 60+i)
+		}()
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				if c.Get() == nil {
+					t.Error("Get returned no configuration")
+				}
+				_ = c.Origin("message.body_width")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := c.Set("message.body_width", 99); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Load(file, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Get().Message.BodyWidth; got != 99 {
+		t.Errorf("body width %d after loading again, want the 99 set", got)
+	}
+}
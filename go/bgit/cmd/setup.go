@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/huh"
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// cheatSheet closes the setup with the commands most people need first.
+var cheatSheet = []ui.CheatEntry{
+	{Command: "bgit status", Summary: "See what is staged, modified and untracked"},
+	{Command: "bgit add --all", Summary: "Stage every change (or name files to stage)"},
+	{Command: "bgit diff --staged", Summary: "Review what is about to be committed"},
+	{Command: "bgit commit", Summary: "Commit with a message written from the staged diff"},
+	{Command: "bgit commit -m \"...\"", Summary: "Commit with your own message"},
+	{Command: "bgit log -p <file>", Summary: "Read a file's history with its patches"},
+	{Command: "bgit branches --compare", Summary: "See how branches stand against main"},
+	{Command: "bgit config init", Summary: "Answer the configuration questions again"},
+	{Command: "bgit --help", Summary: "Every command and flag"},
+}
+
+func newSetupCmd(d *Deps) *cobra.Command {
+	return &cobra.Command{
+		Use:   "setup",
+		Short: "Walk through the first-run setup again",
+		Long: `Run the guided setup bgit shows the first time it is used: a short
+introduction, the 'config init' questions, optional shell completion and an
+optional prepare-commit-msg hook for the current repository, so plain
+'git commit' gets a suggested message too. It ends with a cheat-sheet of the
+common commands.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !ui.IsInteractive(d.IO.In, d.IO.Out) {
+				return errors.New("setup needs a terminal")
+			}
+			d.flushOut()
+			return runSetup(cmd.Root(), d)
+		},
+	}
+}
+
+// wantsOnboarding reports whether the first run of bgit should open the
+// guided setup before running cmd. It never does for scripts, for machine
+// output, or for the commands the setup itself installs or runs.
+func wantsOnboarding(cmd *cobra.Command, d *Deps) bool {
+	if !d.Config.FirstRun() || !ui.IsInteractive(d.IO.In, d.IO.Out) {
+		return false
+	}
+	if format, _ := cmd.Flags().GetString("output"); format == "json" {
+		return false
+	}
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		return false
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		switch c.Name() {
+		case "setup", "init", "hook", "completion", "help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+			return false
+		}
+	}
+	return true
+}
+
+// runSetup is the guided setup. Each step can be declined; backing out of a
+// question ends the setup without undoing the steps already taken.
+func runSetup(root *cobra.Command, d *Deps) error {
+	term, _ := ui.TerminalFile(d.IO.Out)
+	width := ui.TerminalWidth(term)
+
+	fmt.Fprint(term, ui.RenderWelcome(width))
+	if err := runConfigInit(d, term); err != nil {
+		return err
+	}
+	fmt.Fprintln(term)
+	if err := offerCompletion(root, d, term); err != nil {
+		return err
+	}
+	fmt.Fprintln(term)
+	if err := offerHook(d, term); err != nil {
+		return err
+	}
+	fmt.Fprintln(term)
+	fmt.Fprint(term, ui.RenderCheatSheet(cheatSheet, width))
+	fmt.Fprintln(term)
+	return nil
+}
+
+// confirm asks a yes/no question, defaulting to yes.
+func confirm(d *Deps, term io.Writer, title, description string) (bool, error) {
+	yes := true
+	err := huh.NewForm(huh.NewGroup(
+		huh.NewConfirm().Title(title).Description(description).Value(&yes),
+	)).WithInput(d.IO.In).WithOutput(term).Run()
+	if errors.Is(err, huh.ErrUserAborted) {
+		return false, errSetupAborted
+	}
+	return yes, err
+}
+
+// completionScript is where a shell loads completions from without further
+// setup (or with the one line hint), and how to generate bgit's.
+type completionScript struct {
+	path  string
+	gen   func(root *cobra.Command, w io.Writer) error
+	hint  string
+	shell string
+}
+
+// completionFor picks the completion script for the login shell, or false
+// for shells bgit cannot install one for.
+func completionFor(shell string) (completionScript, bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return completionScript{}, false
+	}
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(home, ".config")
+	}
+
+	switch filepath.Base(shell) {
+	case "bash":
+		return completionScript{
+			shell: "bash",
+			path:  filepath.Join(dataHome, "bash-completion", "completions", "bgit"),
+			gen:   func(root *cobra.Command, w io.Writer) error { return root.GenBashCompletionV2(w, true) },
+			hint:  "Loaded by the bash-completion package in new shells.",
+		}, true
+	case "zsh":
+		return completionScript{
+			shell: "zsh",
+			path:  filepath.Join(home, ".zsh", "completions", "_bgit"),
+			gen:   func(root *cobra.Command, w io.Writer) error { return root.GenZshCompletion(w) },
+			hint:  "Add 'fpath=(~/.zsh/completions $fpath)' before compinit in ~/.zshrc if it is not there.",
+		}, true
+	case "fish":
+		return completionScript{
+			shell: "fish",
+			path:  filepath.Join(configHome, "fish", "completions", "bgit.fish"),
+			gen:   func(root *cobra.Command, w io.Writer) error { return root.GenFishCompletion(w, true) },
+			hint:  "Loaded by fish in new shells.",
+		}, true
+	}
+	return completionScript{}, false
+}
+
+// offerCompletion installs tab completion for the user's shell.
+func offerCompletion(root *cobra.Command, d *Deps, term io.Writer) error {
+	script, ok := completionFor(os.Getenv("SHELL"))
+	if !ok {
+		fmt.Fprintln(term, "Shell completion: see 'bgit completion --help' to set it up for your shell.")
+		return nil
+	}
+	yes, err := confirm(d, term, "Install tab completion for "+script.shell+"?", "Writes "+script.path)
+	if err != nil || !yes {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(script.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(script.path)
+	if err != nil {
+		return err
+	}
+	if err := script.gen(root, f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(term, "%sInstalled completion at %s\n", ui.Icon("✓"), script.path)
+	fmt.Fprintf(term, "  %s\n", script.hint)
+	return nil
+}
+
+// hookInstallRepo installs git hooks.
+type hookInstallRepo interface {
+	InstallHook(name, script string) (string, error)
+}
+
+// offerHook installs the prepare-commit-msg hook in the current repository.
+func offerHook(d *Deps, term io.Writer) error {
+	repo, err := openRepo[hookInstallRepo](d)
+	if err != nil {
+		fmt.Fprintln(term, "Commit hook: run 'bgit setup' inside a repository to have plain 'git commit' suggest messages.")
+		return nil
+	}
+	yes, err := confirm(d, term, "Install the prepare-commit-msg hook in this repository?",
+		"'git commit' will then open the editor with a suggested message.")
+	if err != nil || !yes {
+		return err
+	}
+
+	path, err := repo.InstallHook("prepare-commit-msg", prepareCommitMsgHook)
+	var exists gitService.ErrHookExists
+	if errors.As(err, &exists) {
+		fmt.Fprintf(term, "%sLeft the existing hook at %s alone.\n", ui.Icon("⚠️"), exists.Path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(term, "%sInstalled hook at %s\n", ui.Icon("✓"), path)
+	return nil
+}
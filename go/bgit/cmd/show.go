@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+func newShowCmd(d *Deps) *cobra.Command {
+	var (
+		stat  statOptions
+		notes bool
+	)
+
+	showCmd := &cobra.Command{
+		Use:   "show [revision]",
+		Short: "Show a commit and the changes it made",
+		Long: `Show a commit (HEAD by default): its hash, author, date and message,
+followed by the patch against its first parent.
+
+--stat replaces the patch with a per-file summary and histogram, in which
+generated files are counted but not listed unless --generated is given;
+--numstat prints only the tab-separated counts, for scripts.
+
+--notes adds the note bgit keeps under notes.ref (refs/notes/bgit by
+default), such as the environment recorded with notes.environment.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rev := "HEAD"
+			if len(args) == 1 {
+				rev = args[0]
+			}
+			return runShow(d, rev, stat, notes)
+		},
+	}
+
+	addStatFlags(showCmd, &stat)
+	showCmd.Flags().BoolVar(&notes, "notes", false, "Show the commit's bgit note")
+
+	return showCmd
+}
+
+// showRepo is what 'bgit show' reads from the repository.
+type showRepo interface {
+	generatedRepo
+	ResolveCommit(rev string) (*object.Commit, error)
+	CommitPatch(c *object.Commit) (*object.Patch, error)
+	Note(ref string, commit plumbing.Hash) (string, error)
+}
+
+func runShow(d *Deps, rev string, stat statOptions, notes bool) error {
+	if err := stat.validate(); err != nil {
+		return err
+	}
+	client, err := openRepo[showRepo](d)
+	if err != nil {
+		return err
+	}
+
+	commit, err := client.ResolveCommit(rev)
+	if err != nil {
+		return err
+	}
+	patch, err := client.CommitPatch(commit)
+	if err != nil {
+		return fmt.Errorf("failed to compute patch: %w", err)
+	}
+	stats := gitService.PatchStats(patch)
+
+	if stat.numstat {
+		printStats(d, client, stat, stats)
+		return nil
+	}
+
+	view := commitView(commit)
+	if notes {
+		ref := d.Config.Get().Notes.Ref
+		if view.Notes, err = client.Note(ref, commit.Hash); err != nil {
+			return fmt.Errorf("failed to read notes: %w", err)
+		}
+		view.NotesRef = strings.TrimPrefix(ref, "refs/notes/")
+	}
+	if stat.stat {
+		view.Stats = uiStats(stats, generatedFiles(d, client, stats, stat.generated))
+	}
+	fmt.Fprint(d.IO.Out, ui.RenderCommit(view, ui.TerminalWidth(d.IO.Out)))
+	if !stat.any() {
+		fmt.Fprintln(d.IO.Out)
+		fmt.Fprint(d.IO.Out, ui.RenderPatch(patch.String()))
+	}
+	return nil
+}
+
+// commitView describes c for the ui renderers.
+func commitView(c *object.Commit) ui.CommitView {
+	return ui.CommitView{
+		Hash:      c.Hash.String(),
+		Author:    ui.Person{Name: c.Author.Name, Email: c.Author.Email},
+		Committer: ui.Person{Name: c.Committer.Name, Email: c.Committer.Email},
+		When:      c.Author.When,
+		Message:   c.Message,
+	}
+}
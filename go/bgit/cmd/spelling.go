@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	spellcheckService "github.com/endalk200/bgit/internal/services/spellcheck"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/pipeline"
+)
+
+// errCommitAborted is returned when the user backs out of a commit from a
+// prompt.
+var errCommitAborted = errors.New("commit aborted")
+
+const (
+	spellApply = "apply"
+	spellKeep  = "keep"
+	spellEdit  = "edit"
+	spellAbort = "abort"
+)
+
+// checkSpelling is the spell-check stage of the commit pipeline. On a
+// terminal it pauses the pipeline to offer the corrections; otherwise it only
+// reports how many typos it suspects. It returns the message to commit.
+func checkSpelling(ctx context.Context, d *Deps, message string) (string, string, error) {
+	cfg := d.Config.Get().Spell
+	issues := spellcheckService.New(cfg.Words, cfg.Terms).Check(message)
+	if len(issues) == 0 {
+		return message, "no typos", nil
+	}
+	if !ui.IsInteractive(d.IO.In, progressOut(d)) {
+		return message, plural(len(issues), "possible typo") + ": " + summarizeIssues(issues), nil
+	}
+
+	err := pipeline.Suspend(ctx, func() error {
+		reviewed, err := reviewSpelling(d, message, issues)
+		message = reviewed
+		return err
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return message, plural(len(issues), "possible typo") + " reviewed", nil
+}
+
+// reviewSpelling shows the flagged words and asks what to do about them.
+func reviewSpelling(d *Deps, message string, issues []spellcheckService.Issue) (string, error) {
+	term, _ := ui.TerminalFile(progressOut(d))
+
+	view := make([]ui.SpellingIssue, 0, len(issues))
+	for _, is := range issues {
+		view = append(view, ui.SpellingIssue{Offset: is.Offset, Word: is.Word, Suggestion: is.Suggestion, Kind: is.Kind.String()})
+	}
+	fmt.Fprint(term, "\n"+ui.RenderSpelling(message, view, ui.TerminalWidth(term))+"\n")
+
+	choice := spellApply
+	err := huh.NewForm(huh.NewGroup(
+		huh.NewSelect[string]().
+			Title("Apply the suggested corrections?").
+			Options(
+				huh.NewOption("Apply corrections", spellApply),
+				huh.NewOption("Keep the message as written", spellKeep),
+				huh.NewOption("Edit the message", spellEdit),
+				huh.NewOption("Abort the commit", spellAbort),
+			).
+			Value(&choice),
+	)).WithInput(d.IO.In).WithOutput(term).Run()
+	if errors.Is(err, huh.ErrUserAborted) {
+		return "", errCommitAborted
+	}
+	if err != nil {
+		return "", err
+	}
+
+	switch choice {
+	case spellKeep:
+		return message, nil
+	case spellAbort:
+		return "", errCommitAborted
+	case spellEdit:
+		edited := spellcheckService.Apply(message, issues)
+		err := huh.NewForm(huh.NewGroup(
+			huh.NewText().Title("Commit message").Lines(8).CharLimit(0).Value(&edited),
+		)).WithInput(d.IO.In).WithOutput(term).Run()
+		if errors.Is(err, huh.ErrUserAborted) {
+			return "", errCommitAborted
+		}
+		return edited, err
+	default:
+		return spellcheckService.Apply(message, issues), nil
+	}
+}
+
+// summarizeIssues lists the first few corrections for a one-line report.
+func summarizeIssues(issues []spellcheckService.Issue) string {
+	const shown = 3
+	var parts []string
+	for i, is := range issues {
+		if i == shown {
+			parts = append(parts, fmt.Sprintf("and %d more", len(issues)-shown))
+			break
+		}
+		parts = append(parts, strings.TrimSpace(is.Word)+" → "+is.Suggestion)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newStashCmd(d *Deps) *cobra.Command {
+	opts := &gitService.StashOptions{}
+	var restoreIndex bool
+
+	stashCmd := &cobra.Command{
+		Use:   "stash",
+		Short: "Set changes aside, list them, and pop, apply or drop them",
+		Long: `Set the changes in the index and the working tree aside, leaving the
+working tree as it is at HEAD, to bring them back later. Without a
+subcommand the changes are saved, as with 'stash save'.
+
+Stashes are numbered from the last one saved, stash@{0}; pop, apply and drop
+take that one unless given another, as stash@{2} or just 2. 'bgit status'
+lists them too.`,
+		Example: `  bgit stash -m "half-done parser"
+  bgit stash save -u -- internal/parser
+  bgit stash list
+  bgit stash pop
+  bgit stash drop 2`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStashSave(d, *opts)
+		},
+	}
+	stashSaveFlags(stashCmd, opts)
+
+	saveCmd := &cobra.Command{
+		Use:     "save [<path>...]",
+		Aliases: []string{"push"},
+		Short:   "Save the changes as a new stash and revert them",
+		Long: `Save the staged and unstaged changes as a new stash@{0} and revert them to
+HEAD, or only those under the paths given. Untracked files stay where they
+are unless --include-untracked saves them too; --keep-index leaves what is
+staged in place as well as saving it.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Paths = args
+			return runStashSave(d, *opts)
+		},
+	}
+	stashSaveFlags(saveCmd, opts)
+
+	listCmd := &cobra.Command{
+		Use:         "list",
+		Aliases:     []string{"ls"},
+		Short:       "List the stashes, the last one saved first",
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStashList(d)
+		},
+	}
+
+	popCmd := &cobra.Command{
+		Use:   "pop [<stash>]",
+		Short: "Apply a stash and drop it",
+		Long: `Apply the changes of a stash, by default stash@{0}, to the working tree
+and drop it from the stash. When they conflict with the working tree the
+conflicts are left to resolve and the stash is kept.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStashApply(d, args, true, restoreIndex)
+		},
+	}
+
+	applyCmd := &cobra.Command{
+		Use:   "apply [<stash>]",
+		Short: "Apply a stash, keeping it in the stash",
+		Long: `Apply the changes of a stash, by default stash@{0}, to the working tree
+and keep it in the stash, to apply it elsewhere too.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStashApply(d, args, false, restoreIndex)
+		},
+	}
+	for _, c := range []*cobra.Command{popCmd, applyCmd} {
+		c.Flags().BoolVar(&restoreIndex, "index", false, "Stage again the changes that were staged")
+	}
+
+	dropCmd := &cobra.Command{
+		Use:     "drop [<stash>]",
+		Aliases: []string{"rm"},
+		Short:   "Delete a stash",
+		Long: `Delete a stash, by default stash@{0}. The ones saved before it move up by
+one.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStashDrop(d, args)
+		},
+	}
+
+	stashCmd.AddCommand(saveCmd, listCmd, popCmd, applyCmd, dropCmd)
+	return stashCmd
+}
+
+// stashSaveFlags adds the flags of 'stash save' to c, which 'stash' shares.
+func stashSaveFlags(c *cobra.Command, opts *gitService.StashOptions) {
+	flags := c.Flags()
+	flags.StringVarP(&opts.Message, "message", "m", "", "Describe the stash")
+	flags.BoolVarP(&opts.Untracked, "include-untracked", "u", false, "Save and remove untracked files too")
+	flags.BoolVar(&opts.KeepIndex, "keep-index", false, "Leave the staged changes in place")
+}
+
+// stashRepo is what the 'bgit stash' commands do to the repository.
+type stashRepo interface {
+	StashSave(opts gitService.StashOptions) (gitService.Stash, error)
+	Stashes() ([]gitService.Stash, error)
+	StashApply(index int, pop, restoreIndex bool) (gitService.Stash, error)
+	StashDrop(index int) (gitService.Stash, error)
+}
+
+func runStashSave(d *Deps, opts gitService.StashOptions) error {
+	client, err := openRepo[stashRepo](d)
+	if err != nil {
+		return err
+	}
+	entry, err := client.StashSave(opts)
+	if errors.As(err, new(gitService.ErrNothingToStash)) {
+		fmt.Fprintln(d.IO.Out, "No local changes to stash.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	d.infof("%sSaved %s: %s\n", ui.Icon("✓"), entry.Ref(), entry.Message)
+	return nil
+}
+
+func runStashList(d *Deps) error {
+	client, err := openRepo[stashRepo](d)
+	if err != nil {
+		return err
+	}
+	stashes, err := client.Stashes()
+	if err != nil {
+		return fmt.Errorf("failed to list stashes: %w", err)
+	}
+	entries := stashEntries(stashes)
+	if d.Output.JSON() {
+		if entries == nil {
+			entries = []ui.StashEntry{} // encode as [] rather than null
+		}
+		return json.NewEncoder(d.IO.Out).Encode(entries)
+	}
+	fmt.Fprint(d.IO.Out, ui.RenderStashList(entries, time.Now(), ui.TerminalWidth(d.IO.Out)))
+	return nil
+}
+
+func runStashApply(d *Deps, args []string, pop, restoreIndex bool) error {
+	index, err := stashIndex(args)
+	if err != nil {
+		return err
+	}
+	client, err := openRepo[stashRepo](d)
+	if err != nil {
+		return err
+	}
+	entry, err := client.StashApply(index, pop, restoreIndex)
+	if errors.As(err, new(gitService.ErrStashConflict)) {
+		hint := "Hint: run 'bgit resolve' to resolve the conflicts"
+		if pop {
+			hint += fmt.Sprintf(", then 'bgit stash drop %d'", index)
+		}
+		fmt.Fprintln(d.IO.ErrOut, hint)
+	}
+	if err != nil {
+		return err
+	}
+	verb := "Applied"
+	if pop {
+		verb = "Applied and dropped"
+	}
+	d.infof("%s%s %s: %s\n", ui.Icon("✓"), verb, entry.Ref(), entry.Message)
+	return nil
+}
+
+func runStashDrop(d *Deps, args []string) error {
+	index, err := stashIndex(args)
+	if err != nil {
+		return err
+	}
+	client, err := openRepo[stashRepo](d)
+	if err != nil {
+		return err
+	}
+	entry, err := client.StashDrop(index)
+	if err != nil {
+		return err
+	}
+	d.infof("%sDropped %s (%s): %s\n", ui.Icon("✓"), entry.Ref(), entry.Hash.String()[:7], entry.Message)
+	return nil
+}
+
+// stashIndex reads which stash args name, as stash@{2} or 2; none names
+// stash@{0}.
+func stashIndex(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, nil
+	}
+	s := args[0]
+	if rest, ok := strings.CutPrefix(s, "stash@{"); ok {
+		s = strings.TrimSuffix(rest, "}")
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q is not a stash; name one as stash@{2} or 2", args[0])
+	}
+	return n, nil
+}
+
+// stashEntries prepares stashes for display.
+func stashEntries(stashes []gitService.Stash) []ui.StashEntry {
+	var entries []ui.StashEntry
+	for _, s := range stashes {
+		entries = append(entries, ui.StashEntry{
+			Ref:     s.Ref(),
+			Hash:    s.Hash.String(),
+			Branch:  s.Branch,
+			Message: s.Message,
+			When:    s.When,
+		})
+	}
+	return entries
+}
@@ -1,109 +1,167 @@
 package cmd
 
 import (
-	"errors"
+	"encoding/json"
 	"fmt"
 
-	"os"
-	"strings"
-
 	gitService "github.com/endalk200/bgit/internal/services/git"
-	"github.com/go-git/go-git/v6"
+	"github.com/endalk200/bgit/internal/ui"
 	"github.com/spf13/cobra"
 )
 
-// formatSection renders a titled list with bullet points.
-func formatSection(title string, items []string) string {
-	if len(items) == 0 {
-		return ""
-	}
-	var b strings.Builder
-	b.WriteString(title)
-	b.WriteString(" (" + fmt.Sprintf("%d", len(items)) + ")\n")
-	for _, it := range items {
-		b.WriteString("  • ")
-		b.WriteString(it)
-		b.WriteString("\n")
+func newStatusCmd(d *Deps) *cobra.Command {
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show repository status with modern formatting",
+		Long: `Displays tracked, staged, modified, and untracked files with concise
+categorization. Mirrors 'git status' conceptually but focuses on clarity.
+
+When the repository has a CODEOWNERS file (in .github/, the root or docs/),
+the staged files are grouped by the owners whose review they will need.
+
+The heading says how far the branch is ahead of and behind its upstream, as
+of the last 'bgit fetch'. Directories with nothing in them, which git does
+not track, are listed with a hint to keep them with 'bgit keep'. Changes set
+aside with 'bgit stash' are listed last.`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStatus(d)
+		},
 	}
-	return b.String()
+
+	statusCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output (future use)")
+
+	return statusCmd
 }
 
-var statusCmd = &cobra.Command{
-	Use:   "status",
-	Short: "Show repository status with modern formatting",
-	Long: `Displays tracked, staged, modified, and untracked files with concise
-categorization. Mirrors 'git status' conceptually but focuses on clarity.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		cwd, err := os.Getwd()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "error: cannot determine working directory: %v\n", err)
-			os.Exit(1)
-		}
+// statusRepo is what 'bgit status' reads from the repository.
+type statusRepo interface {
+	ownersRepo
+	gitDirRepo
+	CurrentBranch() (string, error)
+	StagedFiles() ([]string, error)
+	ModifiedFiles() ([]string, error)
+	AddedFiles() ([]string, error)
+	DeletedFiles() ([]string, error)
+	RenamedFiles() ([]string, error)
+	UntrackedFiles() ([]string, error)
+	IntentToAddFiles() ([]string, error)
+	BranchTracking(name string) (gitService.Branch, error)
+	EmptyDirs(dir string) ([]string, error)
+	Stashes() ([]gitService.Stash, error)
+}
 
-		gitClient, err := gitService.NewGitClient(cwd)
-		if err != nil {
-			if errors.Is(err, git.ErrRepositoryNotExists) {
-				fmt.Fprintf(os.Stderr, "error: no git repository found at %s\n", cwd)
-				os.Exit(1)
+func runStatus(d *Deps) error {
+	gitClient, err := openRepo[statusRepo](d)
+	if err != nil {
+		return err
+	}
+
+	view := collectStatus(gitClient)
+	lists, _, err := loadChangelists(gitClient)
+	if err != nil {
+		return err
+	}
+	view.Changelists = lists.view(view, false)
+	if rules, _, err := loadOwners(gitClient); err != nil {
+		d.Log.Debug("code owners unavailable", "err", err) // non-critical
+	} else if rules != nil {
+		view.Owners = ownerAreas(rules.Areas(view.Staged))
+	}
+
+	if d.Output.JSON() {
+		for _, list := range []*[]string{&view.Staged, &view.Added, &view.Modified, &view.Deleted, &view.Renamed, &view.Untracked, &view.Intent} {
+			if *list == nil {
+				*list = []string{} // encode as [] rather than null
 			}
-			fmt.Fprintf(os.Stderr, "error: %v\n", err)
-			os.Exit(1)
 		}
+		return json.NewEncoder(d.IO.Out).Encode(view)
+	}
 
-		branch, _ := gitClient.CurrentBranch() // non-critical
+	fmt.Fprint(d.IO.Out, ui.RenderStatus(view, ui.TerminalWidth(d.IO.Out)))
+	return nil
+}
 
-		staged, err := gitClient.StagedFiles()
-		if err != nil {
-			staged = []string{}
-		}
+// collectStatus reads what the status screen shows. Files it cannot read
+// are left out rather than failing the command.
+func collectStatus(client statusRepo) ui.StatusView {
+	branch, _ := client.CurrentBranch() // non-critical
 
-		modified, err := gitClient.ModifiedFiles()
-		if err != nil {
-			modified = []string{}
-		}
+	staged, err := client.StagedFiles()
+	if err != nil {
+		staged = []string{}
+	}
 
-		added, err := gitClient.AddedFiles()
-		if err != nil {
-			added = []string{}
-		}
+	modified, err := client.ModifiedFiles()
+	if err != nil {
+		modified = []string{}
+	}
 
-		deleted, err := gitClient.DeletedFiles()
-		if err != nil {
-			deleted = []string{}
-		}
+	added, err := client.AddedFiles()
+	if err != nil {
+		added = []string{}
+	}
 
-		renamed, err := gitClient.RenamedFiles()
-		if err != nil {
-			renamed = []string{}
-		}
+	deleted, err := client.DeletedFiles()
+	if err != nil {
+		deleted = []string{}
+	}
 
-		untracked, err := gitClient.UntrackedFiles()
-		if err != nil {
-			untracked = []string{}
-		}
+	renamed, err := client.RenamedFiles()
+	if err != nil {
+		renamed = []string{}
+	}
 
-		var out strings.Builder
-		out.WriteString(fmt.Sprintf("On branch %s\n\n", branch))
-
-		// Sections
-		out.WriteString(formatSection("Staged (index)", staged))
-		out.WriteString(formatSection("Added (staged new files)", added))
-		out.WriteString(formatSection("Modified (worktree)", modified))
-		out.WriteString(formatSection("Deleted", deleted))
-		out.WriteString(formatSection("Renamed", renamed))
-		out.WriteString(formatSection("Untracked", untracked))
-
-		// If there are no changes at all show a single line.
-		if len(staged)+len(modified)+len(added)+len(deleted)+len(renamed)+len(untracked) == 0 {
-			fmt.Println("Working tree clean")
-			return
-		}
+	untracked, err := client.UntrackedFiles()
+	if err != nil {
+		untracked = []string{}
+	}
 
-		fmt.Print(out.String())
-	},
+	// Files added with -N are neither staged nor modified in the usual sense.
+	intents, err := client.IntentToAddFiles()
+	if err != nil {
+		intents = []string{}
+	}
+	modified, added = without(modified, intents), without(added, intents)
+
+	view := ui.StatusView{
+		Branch:    branch,
+		Staged:    staged,
+		Added:     added,
+		Modified:  modified,
+		Deleted:   deleted,
+		Renamed:   renamed,
+		Untracked: untracked,
+		Intent:    intents,
+	}
+	// Counted against the upstream as of the last fetch; 'bgit fetch'
+	// brings it up to date.
+	if b, err := client.BranchTracking(branch); err == nil {
+		view.Upstream, view.Ahead, view.Behind, view.UpstreamGone = b.Upstream, b.Ahead, b.Behind, b.UpstreamGone
+	}
+	if dirs, err := client.EmptyDirs(""); err == nil {
+		view.EmptyDirs = dirs
+	}
+	if stashes, err := client.Stashes(); err == nil {
+		view.Stashes = stashEntries(stashes)
+	}
+	return view
 }
 
-func init() {
-	rootCmd.AddCommand(statusCmd)
-	statusCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output (future use)")
+// without returns list minus the entries in drop.
+func without(list, drop []string) []string {
+	if len(drop) == 0 {
+		return list
+	}
+	skip := make(map[string]bool, len(drop))
+	for _, p := range drop {
+		skip[p] = true
+	}
+	var kept []string
+	for _, p := range list {
+		if !skip[p] {
+			kept = append(kept, p)
+		}
+	}
+	return kept
 }
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// extensionPrefix marks executables on PATH that act as bgit subcommands:
+// bgit-foo runs as 'bgit foo', the way git finds git-foo.
+const extensionPrefix = "bgit-"
+
+// extensions maps the name of each extension on PATH to its executable. The
+// first one found wins, as it would for the shell.
+func extensions() map[string]string {
+	found := map[string]string{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, ok := strings.CutPrefix(e.Name(), extensionPrefix)
+			if !ok || name == "" || found[name] != "" {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil || info.IsDir() || info.Mode()&0o111 == 0 {
+				continue
+			}
+			found[name] = filepath.Join(dir, e.Name())
+		}
+	}
+	return found
+}
+
+// runExtension runs the extension args name, passing it the remaining
+// arguments and the standard streams. ok is false when args[0] is a built-in
+// command or no extension of that name exists, so bgit should handle them.
+func runExtension(root *cobra.Command, d *Deps, args []string) (code int, ok bool) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return 0, false
+	}
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+	if c, _, err := root.Find(args); err != nil || c != root {
+		return 0, false
+	}
+	path := extensions()[args[0]]
+	if path == "" {
+		return 0, false
+	}
+
+	ext := exec.Command(path, args[1:]...)
+	ext.Stdin, ext.Stdout, ext.Stderr = d.IO.In, d.IO.Out, d.IO.ErrOut
+	err := ext.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		return exitErr.ExitCode(), true
+	case err != nil:
+		fmt.Fprintf(d.IO.ErrOut, "error: running %s: %v\n", path, err)
+		return 1, true
+	}
+	return 0, true
+}
+
+// unknownCommand is the error for a first argument that is neither a
+// command nor an extension, naming the closest ones.
+func unknownCommand(root *cobra.Command, name string) error {
+	msg := fmt.Sprintf("unknown command %q for %q", name, root.CommandPath())
+	if s := suggestCommands(root, name); len(s) > 0 {
+		if len(s) == 1 {
+			msg += fmt.Sprintf("; did you mean '%s'?", s[0])
+		} else {
+			msg += fmt.Sprintf("; did you mean one of '%s'?", strings.Join(s, "', '"))
+		}
+	}
+	return errors.New(msg + "\nRun 'bgit --help' for the list of commands.")
+}
+
+// suggestCommands returns the commands, aliases and extensions closest to
+// name, best first: those within a couple of edits and those name is the
+// start of.
+func suggestCommands(root *cobra.Command, name string) []string {
+	var candidates []string
+	for _, c := range root.Commands() {
+		if c.Hidden || c.Deprecated != "" {
+			continue
+		}
+		candidates = append(candidates, c.Name())
+		candidates = append(candidates, c.Aliases...)
+	}
+	for ext := range extensions() {
+		candidates = append(candidates, ext)
+	}
+
+	typed := strings.ToLower(name)
+	maxDist := min(2, max(len(typed)/2, 1))
+	dist := map[string]int{}
+	for _, c := range candidates {
+		d := levenshtein(typed, strings.ToLower(c))
+		if strings.HasPrefix(strings.ToLower(c), typed) {
+			d = min(d, 1)
+		}
+		if d <= maxDist {
+			if old, seen := dist[c]; !seen || d < old {
+				dist[c] = d
+			}
+		}
+	}
+
+	suggestions := make([]string, 0, len(dist))
+	for c := range dist {
+		suggestions = append(suggestions, c)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if dist[a] != dist[b] {
+			return dist[a] < dist[b]
+		}
+		return a < b
+	})
+	if len(suggestions) > 3 {
+		suggestions = suggestions[:3]
+	}
+	return suggestions
+}
+
+// levenshtein is the edit distance between a and b, counting insertions,
+// deletions and substitutions of runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
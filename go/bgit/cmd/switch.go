@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/spf13/cobra"
+)
+
+func newSwitchCmd(d *Deps) *cobra.Command {
+	var create string
+
+	switchCmd := &cobra.Command{
+		Use:     "switch [<branch>]",
+		Aliases: []string{"checkout", "co"},
+		Short:   "Switch branches, or pick one from a list",
+		Long: `Switch to another local branch, carrying uncommitted changes over. When
+the changes would be overwritten by the switch, nothing happens: commit or
+stash them first. A branch that only a remote has, like feature for
+origin/feature, gets a local branch tracking it.
+
+-c <name> creates a branch at HEAD, or at <branch> when given, and switches
+to it.
+
+Without a branch, a list of the other local branches opens to pick one
+from; typing narrows it to the branches whose name or last commit subject
+contains the text.`,
+		Example: `  bgit switch
+  bgit switch main
+  bgit switch -c feature/login
+  bgit switch -c hotfix v1.2.0`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if create != "" {
+				start := ""
+				if len(args) == 1 {
+					start = args[0]
+				}
+				return runSwitchCreate(d, create, start)
+			}
+			if len(args) == 1 {
+				return runSwitch(d, args[0])
+			}
+			if !ui.IsInteractive(d.IO.In, d.IO.Out) {
+				return errors.New("name the branch to switch to; picking one needs a terminal")
+			}
+			d.flushOut()
+			term, _ := ui.TerminalFile(d.IO.Out)
+			return runSwitchPicker(d, term)
+		},
+	}
+
+	switchCmd.Flags().StringVarP(&create, "create", "c", "", "Create a branch with this name and switch to it")
+
+	return switchCmd
+}
+
+// switchRepo is what 'bgit switch' needs from the repository.
+type switchRepo interface {
+	Checkout(name string) error
+	CreateBranch(name, start string) (plumbing.Hash, error)
+	DeleteBranch(name string, force bool) error
+	Branches() ([]gitService.Branch, error)
+}
+
+func runSwitch(d *Deps, name string) error {
+	client, err := openRepo[switchRepo](d)
+	if err != nil {
+		return err
+	}
+	if err := client.Checkout(name); err != nil {
+		return err
+	}
+	d.infof("%sSwitched to branch %s\n", ui.Icon("✓"), name)
+	return nil
+}
+
+func runSwitchCreate(d *Deps, name, start string) error {
+	client, err := openRepo[switchRepo](d)
+	if err != nil {
+		return err
+	}
+	hash, err := client.CreateBranch(name, start)
+	if err != nil {
+		return err
+	}
+	if err := client.Checkout(name); err != nil {
+		// Leave no branch behind that nobody asked for.
+		_ = client.DeleteBranch(name, true)
+		return err
+	}
+	d.infof("%sSwitched to a new branch %s at %s\n", ui.Icon("✓"), name, hash.String()[:7])
+	return nil
+}
+
+// runSwitchPicker lists the other local branches on term, each with its
+// last commit, and switches to the one picked.
+func runSwitchPicker(d *Deps, term io.Writer) error {
+	client, err := openRepo[switchRepo](d)
+	if err != nil {
+		return err
+	}
+	branches, err := client.Branches()
+	if err != nil {
+		return fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	current, nameWidth := "", 0
+	for _, b := range branches {
+		nameWidth = max(nameWidth, ui.StringWidth(b.Name))
+	}
+	options := make([]huh.Option[string], 0, len(branches))
+	for _, b := range branches {
+		if b.Current {
+			current = b.Name
+			continue
+		}
+		label := b.Name + strings.Repeat(" ", nameWidth-ui.StringWidth(b.Name)) + "  " + b.Subject
+		options = append(options, huh.NewOption(label, b.Name))
+	}
+	if len(options) == 0 {
+		return errors.New("no other branch to switch to; create one with 'bgit switch -c <name>'")
+	}
+
+	title := "Switch to which branch?"
+	if current != "" {
+		title = fmt.Sprintf("Switch from %s to which branch?", current)
+	}
+	var picked string
+	err = huh.NewForm(huh.NewGroup(
+		huh.NewSelect[string]().
+			Title(title).
+			Options(options...).
+			Filtering(true).
+			Height(min(len(options)+2, 15)).
+			Value(&picked),
+	)).WithInput(d.IO.In).WithOutput(term).Run()
+	if errors.Is(err, huh.ErrUserAborted) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return runSwitch(d, picked)
+}
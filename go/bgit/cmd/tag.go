@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	gitService "github.com/endalk200/bgit/internal/services/git"
+	releaseService "github.com/endalk200/bgit/internal/services/release"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+type tagCreateOptions struct {
+	message  string
+	generate bool
+}
+
+func newTagCmd(d *Deps) *cobra.Command {
+	createOpts := &tagCreateOptions{}
+	var remote, deleteRemote string
+
+	tagCmd := &cobra.Command{
+		Use:   "tag",
+		Short: "List, create, delete and push tags",
+		Long: `Manage tags. Without a subcommand the tags are listed, as with 'tag list'.
+
+'bgit release' tags a version as part of cutting a whole release.`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTagList(d)
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:         "list",
+		Aliases:     []string{"ls"},
+		Short:       "List tags with their commit and message",
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTagList(d)
+		},
+	}
+
+	createCmd := &cobra.Command{
+		Use:     "create <name> [<commit>]",
+		Aliases: []string{"new"},
+		Short:   "Tag HEAD or another commit",
+		Long: `Tag HEAD, or <commit> when given: a branch, tag, commit or any revision
+git understands. The tag is lightweight, a bare name for the commit, unless
+it is given a message with -m or --generate, which make an annotated tag
+recording who tagged it and when.
+
+--generate writes the message from the conventional commits since the
+previous version tag, grouped by type as 'bgit release' does in the
+changelog.`,
+		Example: `  bgit tag create v1.4.0 -m "First release with the new parser"
+  bgit tag create v1.4.0 --generate
+  bgit tag create before-refactor HEAD~3`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := "HEAD"
+			if len(args) == 2 {
+				target = args[1]
+			}
+			return runTagCreate(d, args[0], target, createOpts)
+		},
+	}
+	createCmd.Flags().StringVarP(&createOpts.message, "message", "m", "", "Make an annotated tag with this message")
+	createCmd.Flags().BoolVar(&createOpts.generate, "generate", false, "Make an annotated tag with a message listing the changes since the previous version")
+	createCmd.MarkFlagsMutuallyExclusive("message", "generate")
+
+	deleteCmd := &cobra.Command{
+		Use:     "delete <name>...",
+		Aliases: []string{"rm"},
+		Short:   "Delete tags",
+		Long: `Delete tags. Only the local tags go unless --remote names a remote to
+delete them from too.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTagDelete(d, args, deleteRemote)
+		},
+	}
+	deleteCmd.Flags().StringVar(&deleteRemote, "remote", "", "Delete the tags from this remote as well")
+
+	pushCmd := &cobra.Command{
+		Use:   "push [<name>...]",
+		Short: "Push tags to a remote",
+		Long: `Push the tags named, or every tag when none is, to a remote. Either all of
+them are pushed or, when one is rejected, none is.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTagPush(d, args, remote)
+		},
+	}
+	pushCmd.Flags().StringVar(&remote, "remote", "origin", "Remote to push the tags to")
+
+	tagCmd.AddCommand(listCmd, createCmd, deleteCmd, pushCmd)
+	return tagCmd
+}
+
+// tagRepo is what the 'bgit tag' commands need from the repository.
+type tagRepo interface {
+	Tags() ([]gitService.Tag, error)
+	ResolveCommit(rev string) (*object.Commit, error)
+	CreateTag(name string, target plumbing.Hash, message string) error
+	CommitsBetween(since, until plumbing.Hash) ([]*object.Commit, error)
+	DeleteTag(name string) (gitService.Tag, error)
+	Push(remote string, refspecs ...string) error
+}
+
+func runTagList(d *Deps) error {
+	client, err := openRepo[tagRepo](d)
+	if err != nil {
+		return err
+	}
+	tags, err := client.Tags()
+	if err != nil {
+		return fmt.Errorf("failed to list tags: %w", err)
+	}
+	entries := make([]ui.TagEntry, 0, len(tags))
+	for _, t := range tags {
+		entries = append(entries, ui.TagEntry{
+			Name:      t.Name,
+			Commit:    t.Commit.String(),
+			Annotated: t.Annotated,
+			Message:   t.Message,
+			When:      t.When,
+		})
+	}
+	if d.Output.JSON() {
+		return json.NewEncoder(d.IO.Out).Encode(entries)
+	}
+	fmt.Fprint(d.IO.Out, ui.RenderTagList(entries, time.Now(), ui.TerminalWidth(d.IO.Out)))
+	return nil
+}
+
+func runTagCreate(d *Deps, name, target string, opts *tagCreateOptions) error {
+	client, err := openRepo[tagRepo](d)
+	if err != nil {
+		return err
+	}
+	commit, err := client.ResolveCommit(target)
+	if err != nil {
+		return err
+	}
+	message := opts.message
+	if opts.generate {
+		if message, err = tagMessage(client, name, commit.Hash); err != nil {
+			return err
+		}
+	}
+	if err := client.CreateTag(name, commit.Hash, message); err != nil {
+		return err
+	}
+	kind := "lightweight tag"
+	if message != "" {
+		kind = "tag"
+	}
+	d.infof("%sCreated %s %s on %s\n", ui.Icon("✓"), kind, name, commit.Hash.String()[:7])
+	if opts.generate {
+		d.infof("\n%s\n", message)
+	}
+	return nil
+}
+
+// tagMessage writes the message of a tag on commit from the conventional
+// commits since the previous version tag, or since the first commit when
+// there is none.
+func tagMessage(client tagRepo, name string, commit plumbing.Hash) (string, error) {
+	tags, err := client.Tags()
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(tags))
+	byName := map[string]gitService.Tag{}
+	for _, t := range tags {
+		// Another version tag on the same commit is not the previous one.
+		if t.Name != name && t.Commit != commit {
+			names = append(names, t.Name)
+			byName[t.Name] = t
+		}
+	}
+	var since gitService.Tag
+	if _, latest, ok := releaseService.Latest(names); ok {
+		since = byName[latest]
+	}
+	commits, err := client.CommitsBetween(since.Commit, commit)
+	if err != nil {
+		return "", err
+	}
+	changes := make([]releaseService.Change, 0, len(commits))
+	for _, c := range commits {
+		changes = append(changes, releaseService.ParseChange(c.Hash.String(), c.Message))
+	}
+	section := releaseService.Changelog(name, time.Now(), changes, "")
+	return "Release " + name + "\n\n" + releaseNotes(section), nil
+}
+
+func runTagDelete(d *Deps, names []string, remote string) error {
+	client, err := openRepo[tagRepo](d)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	var deleted []string
+	for _, name := range names {
+		tag, err := client.DeleteTag(name)
+		if err != nil {
+			fmt.Fprintf(d.IO.ErrOut, "error: %s\n", err)
+			failed++
+			continue
+		}
+		deleted = append(deleted, ":refs/tags/"+name)
+		d.infof("%sDeleted tag %s (was %s)\n", ui.Icon("✓"), name, tag.Commit.String()[:7])
+	}
+	if remote != "" && len(deleted) > 0 {
+		if err := client.Push(remote, deleted...); err != nil {
+			return fmt.Errorf("failed to delete the tags from %s: %w", remote, err)
+		}
+		d.infof("%sDeleted %s from %s\n", ui.Icon("✓"), plural(len(deleted), "tag"), remote)
+	}
+	switch {
+	case failed == 1:
+		return errors.New("1 tag not deleted")
+	case failed > 1:
+		return fmt.Errorf("%d tags not deleted", failed)
+	}
+	return nil
+}
+
+func runTagPush(d *Deps, names []string, remote string) error {
+	client, err := openRepo[tagRepo](d)
+	if err != nil {
+		return err
+	}
+	refspecs := []string{"refs/tags/*:refs/tags/*"}
+	if len(names) > 0 {
+		tags, err := client.Tags()
+		if err != nil {
+			return fmt.Errorf("failed to list tags: %w", err)
+		}
+		known := map[string]bool{}
+		for _, t := range tags {
+			known[t.Name] = true
+		}
+		refspecs = refspecs[:0]
+		for _, name := range names {
+			if !known[name] {
+				return gitService.ErrNoTag{Name: name}
+			}
+			refspecs = append(refspecs, "refs/tags/"+name)
+		}
+	}
+	if err := client.Push(remote, refspecs...); err != nil {
+		return fmt.Errorf("failed to push to %s: %w", remote, err)
+	}
+	what := "every tag"
+	if len(names) > 0 {
+		what = plural(len(names), "tag")
+	}
+	d.infof("%sPushed %s to %s\n", ui.Icon("✓"), what, remote)
+	return nil
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/endalk200/bgit/internal/config"
+	"github.com/endalk200/bgit/internal/sandbox"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/pipeline"
+)
+
+// errTasksFailed stops the commit pipeline when a pre-commit task failed.
+var errTasksFailed = errors.New("pre-commit tasks failed (use --no-verify to commit anyway)")
+
+// secretEnv names the variables, besides the ones that look like
+// credentials, that commands bgit runs for the user must not see: the AI
+// provider key, whatever it is called.
+func secretEnv(d *Deps) []string {
+	secrets := []string{d.Config.Get().AIProvider.EnvName}
+	for _, p := range config.AvailableProviders {
+		secrets = append(secrets, p.EnvName)
+	}
+	return secrets
+}
+
+// runTasks runs tasks one after another in dir, reporting progress on the
+// running pipeline stage, and returns how each ended. It stops early only
+// when ctx is done.
+func runTasks(ctx context.Context, d *Deps, dir string, tasks []config.Task) []sandbox.Result {
+	results := make([]sandbox.Result, 0, len(tasks))
+	for i, t := range tasks {
+		if ctx.Err() != nil {
+			break
+		}
+		name := t.Name
+		if name == "" {
+			name = t.Run
+		}
+		pipeline.Progress(ctx, fmt.Sprintf("%s (%d/%d)", name, i+1, len(tasks)))
+		results = append(results, sandbox.Run(ctx, sandbox.Task{
+			Name:    name,
+			Command: t.Run,
+			Dir:     dir,
+			Timeout: t.Timeout,
+			Pass:    t.Env,
+			Secrets: secretEnv(d),
+		}))
+	}
+	return results
+}
+
+// taskResults converts results for display.
+func taskResults(results []sandbox.Result) []ui.TaskResult {
+	rows := make([]ui.TaskResult, 0, len(results))
+	for _, r := range results {
+		rows = append(rows, ui.TaskResult{Name: r.Name, Summary: r.Summary(), Failed: r.Failed(), Output: r.Output()})
+	}
+	return rows
+}
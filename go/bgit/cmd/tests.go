@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/endalk200/bgit/internal/sandbox"
+	testimpactService "github.com/endalk200/bgit/internal/services/testimpact"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/pipeline"
+	"github.com/spf13/cobra"
+)
+
+// errTestsFailed stops the commit pipeline when a test command failed.
+var errTestsFailed = errors.New("tests for the staged changes failed")
+
+type testsOptions struct {
+	run bool
+}
+
+func newTestsCmd(d *Deps) *cobra.Command {
+	opts := &testsOptions{}
+
+	testsCmd := &cobra.Command{
+		Use:   "tests",
+		Short: "Show the tests the staged changes affect, and run them with --run",
+		Long: `Show the tests the staged changes are likely to affect and the commands that
+run them.
+
+A staged Go file affects the tests of its package: the _test.go files beside
+it, its own first, run with go test from the directory of its go.mod. Other
+files are looked up in tests.mappings of the config, which says where the
+tests of matching files are and how to run them. Staged source files for
+which no test was found are listed too.
+
+--run runs the commands, each bounded by tests.timeout and without bgit's
+credentials in its environment, like the pre-commit tasks; 'bgit commit
+--verify-tests' does the same before committing.`,
+		Example: `  bgit tests
+  bgit tests --run`,
+		Annotations: map[string]string{jsonAnnotation: "true"},
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTests(cmd.Context(), d, opts)
+		},
+	}
+
+	testsCmd.Flags().BoolVar(&opts.run, "run", false, "Run the tests")
+
+	return testsCmd
+}
+
+// testsRepo is what 'bgit tests' reads from the repository.
+type testsRepo interface {
+	StagedFiles() ([]string, error)
+}
+
+func runTests(ctx context.Context, d *Deps, opts *testsOptions) error {
+	client, err := openRepo[testsRepo](d)
+	if err != nil {
+		return err
+	}
+	staged, err := client.StagedFiles()
+	if err != nil {
+		return fmt.Errorf("failed to get staged files: %w", err)
+	}
+	if len(staged) == 0 {
+		fmt.Fprintln(d.IO.Out, "No staged files. Use 'bgit add' to stage files first.")
+		return nil
+	}
+	suites, untested := testimpactService.Plan(os.DirFS("."), staged, d.Config.Get().Tests.Mappings)
+	cmds := testimpactService.Commands(suites)
+	view := testPlanView(suites, untested, cmds)
+
+	if !opts.run {
+		if d.Output.JSON() {
+			return json.NewEncoder(d.IO.Out).Encode(view)
+		}
+		fmt.Fprint(d.IO.Out, ui.RenderTestPlan(view, ui.TerminalWidth(d.IO.Out)))
+		return nil
+	}
+	if len(cmds) == 0 {
+		fmt.Fprintln(d.IO.Out, "No tests found for the staged files.")
+		return nil
+	}
+
+	var results []sandbox.Result
+	err = runPipeline(ctx, d, []pipeline.Stage{testStage(d, cmds, &results)})
+	if errors.Is(err, errTestsFailed) {
+		d.flushOut()
+		fmt.Fprint(d.IO.ErrOut, ui.RenderTestResults(taskResults(results), ui.TerminalWidth(d.IO.ErrOut)))
+	}
+	return err
+}
+
+// testStage runs the test commands as a pipeline stage, keeping how each
+// ended in results.
+func testStage(d *Deps, cmds []testimpactService.Command, results *[]sandbox.Result) pipeline.Stage {
+	return pipeline.Stage{Name: "Run affected tests", Run: func(ctx context.Context) (string, error) {
+		*results = runTestCommands(ctx, d, cmds)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		for _, r := range *results {
+			if r.Failed() {
+				return "", errTestsFailed
+			}
+		}
+		return plural(len(cmds), "command") + " passed", nil
+	}}
+}
+
+// runTestCommands runs cmds one after another, each in its directory under
+// tests.timeout, the way runTasks runs the pre-commit tasks.
+func runTestCommands(ctx context.Context, d *Deps, cmds []testimpactService.Command) []sandbox.Result {
+	results := make([]sandbox.Result, 0, len(cmds))
+	for i, c := range cmds {
+		if ctx.Err() != nil {
+			break
+		}
+		pipeline.Progress(ctx, fmt.Sprintf("%s (%d/%d)", c.Name, i+1, len(cmds)))
+		results = append(results, sandbox.Run(ctx, sandbox.Task{
+			Name:    c.Name,
+			Command: c.Run,
+			Dir:     c.Dir,
+			Timeout: d.Config.Get().Tests.Timeout,
+			Secrets: secretEnv(d),
+		}))
+	}
+	return results
+}
+
+// testPlanView prepares a test plan for display.
+func testPlanView(suites []testimpactService.Suite, untested []string, cmds []testimpactService.Command) ui.TestPlanView {
+	v := ui.TestPlanView{Suites: []ui.TestSuite{}, Untested: untested, Commands: []ui.TestCommand{}}
+	if v.Untested == nil {
+		v.Untested = []string{}
+	}
+	for _, s := range suites {
+		v.Suites = append(v.Suites, ui.TestSuite{Name: s.Name, Tests: s.Tests, Sources: s.Sources})
+	}
+	for _, c := range cmds {
+		v.Commands = append(v.Commands, ui.TestCommand{Run: c.Run, Dir: c.Dir})
+	}
+	return v
+}
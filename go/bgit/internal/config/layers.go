@@ -0,0 +1,210 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Layer is one of the config files settings are read from and saved to.
+type Layer string
+
+const (
+	// Global is the config file in use: ~/.bgit.yaml, or the file given
+	// with --config.
+	Global Layer = "global"
+	// Repo is .bgit.yaml in the current directory, normally the root of a
+	// repository. Its settings win over the global file's.
+	Repo Layer = "repo"
+)
+
+// RepoFile is the name of the repository config file.
+const RepoFile = ".bgit.yaml"
+
+// RepoPath returns the repository config file, whether or not it exists yet,
+// or "" when there is no repo layer: with --config, or in the directory that
+// holds the global file.
+func (s *Store) RepoPath() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.repoPath
+}
+
+// LayerPath returns the file behind layer, or "" when it does not apply.
+func (s *Store) LayerPath(layer Layer) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.layerPath(layer)
+}
+
+func (s *Store) layerPath(layer Layer) string {
+	if layer == Repo {
+		return s.repoPath
+	}
+	return s.path
+}
+
+// mergeRepoLayer reads the repository file on top of the global one, at
+// path, and returns where it is. Unless the file is among trusted, the
+// commands it sets are left out and returned as untrusted; the env of its
+// tasks always is.
+func mergeRepoLayer(v *viper.Viper, opts Options, path string, trusted []TrustedFile) (string, *Untrusted, error) {
+	if opts.File != "" {
+		return "", nil, nil
+	}
+	repoPath, err := filepath.Abs(filepath.Join(opts.Dir, RepoFile))
+	if err != nil {
+		return "", nil, nil
+	}
+	if global, err := filepath.Abs(path); err == nil && global == repoPath {
+		return "", nil, nil
+	}
+
+	data, err := os.ReadFile(repoPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return repoPath, nil, nil
+	}
+	if err != nil {
+		return repoPath, nil, err
+	}
+	file := viper.New()
+	file.SetConfigType("yaml")
+	if err := file.ReadConfig(bytes.NewReader(data)); err != nil {
+		return repoPath, nil, fmt.Errorf("failed to read %s: %w", repoPath, err)
+	}
+	settings := file.AllSettings()
+	delete(settings, trustedKey)
+	stripTaskEnv(settings)
+	var untrusted *Untrusted
+	if sum := fileSum(data); !isTrusted(trusted, repoPath, sum) {
+		if removed := stripCommands(settings); len(removed) > 0 {
+			untrusted = &Untrusted{Source: repoPath, Commands: removed, sum: sum}
+		}
+	}
+	return repoPath, untrusted, v.MergeConfigMap(settings)
+}
+
+// readLayer reads one config file on its own, without defaults. A missing
+// file is nil.
+func readLayer(path string) (*viper.Viper, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	file := viper.New()
+	file.SetConfigFile(path)
+	file.SetConfigType("yaml")
+	if err := file.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// Origin says where the value in effect for key comes from: "repo",
+// "global", "extends" or "default".
+func (s *Store) Origin(key string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, layer := range []Layer{Repo, Global} {
+		if path := s.layerPath(layer); path != "" {
+			if file, err := readLayer(path); err == nil && file != nil && file.IsSet(key) {
+				return string(layer)
+			}
+		}
+	}
+	if _, ok := s.extended[key]; ok {
+		return "extends"
+	}
+	return "default"
+}
+
+// SetIn saves key in the file behind layer, creating the file if need be,
+// and reloads the configuration so the value in effect reflects every layer.
+func (s *Store) SetIn(layer Layer, key string, value any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := s.layerPath(layer)
+	if path == "" {
+		return fmt.Errorf("no %s config file in use", layer)
+	}
+	sum := s.sum(path)
+	if err := writeFileAt(path, map[string]any{key: value}); err != nil {
+		return err
+	}
+	return s.rewritten(layer, path, sum)
+}
+
+// Unset removes key from the file behind layer, so the value from a lower
+// layer applies again, and reloads the configuration.
+func (s *Store) Unset(layer Layer, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := s.layerPath(layer)
+	if path == "" {
+		return fmt.Errorf("no %s config file in use", layer)
+	}
+	file, err := readLayer(path)
+	if err != nil || file == nil || !file.IsSet(key) {
+		return err
+	}
+
+	settings := file.AllSettings()
+	parts := strings.Split(key, ".")
+	parent := settings
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := parent[part].(map[string]any)
+		if !ok {
+			return nil
+		}
+		parent = child
+	}
+	delete(parent, parts[len(parts)-1])
+
+	out := viper.New()
+	out.SetConfigType("yaml")
+	if err := out.MergeConfigMap(settings); err != nil {
+		return err
+	}
+	sum := s.sum(path)
+	if err := out.WriteConfigAs(path); err != nil {
+		return err
+	}
+	return s.rewritten(layer, path, sum)
+}
+
+// sum is the hash of the file at path, "" when it cannot be read.
+func (s *Store) sum(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return fileSum(data)
+}
+
+// rewritten reloads the configuration after the file behind layer, at
+// path, was changed; a repo file that was trusted, with the hash sum, stays
+// trusted.
+func (s *Store) rewritten(layer Layer, path, sum string) error {
+	if layer == Repo {
+		if err := s.retrust(path, sum); err != nil {
+			return err
+		}
+	}
+	return s.reload()
+}
+
+// reload reads every layer again, with s.mu held. Shared files come from
+// the cache; one that is not cached was already reported when the
+// configuration was first loaded.
+func (s *Store) reload() error {
+	err := s.read(true)
+	var extendsErr ErrExtendsFailed
+	if errors.As(err, &extendsErr) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind is the type of a setting's value.
+type Kind int
+
+const (
+	KindBool Kind = iota
+	KindInt
+	KindString
+	// KindChoice is a string from Setting.Choices.
+	KindChoice
+	// KindList is a list of strings, edited as comma-separated text.
+	KindList
+	// KindStructured is a list of records (post-processing steps, tasks)
+	// that is only edited in the file itself.
+	KindStructured
+)
+
+// Setting describes one config key, for editors.
+type Setting struct {
+	Key     string
+	Section string
+	Help    string
+	Kind    Kind
+	Choices []string
+	// SecretEnv marks a setting whose value names the environment variable
+	// that holds a secret, such as the AI provider key.
+	SecretEnv bool
+	// check rejects parsed values that are the right type but unusable.
+	check func(any) error
+}
+
+// Settings lists every config key, grouped by section in the order of
+// CONFIG.md.
+var Settings = []Setting{
+	{Key: "ai_provider.name", Section: "AI provider", Kind: KindChoice, Choices: providerNames(),
+		Help: "Provider that writes commit messages"},
+	{Key: "ai_provider.env_name", Section: "AI provider", Kind: KindString, SecretEnv: true,
+		Help: "Environment variable holding the provider's API key", check: checkEnvName},
+	{Key: "ai.max_diff_bytes", Section: "AI diff limits", Kind: KindInt,
+		Help: "Largest diff sent to the provider, in bytes (0 for no limit)", check: checkNonNegative},
+	{Key: "ai.per_file_max_lines", Section: "AI diff limits", Kind: KindInt,
+		Help: "Most diff lines kept for any one file (0 for no limit)", check: checkNonNegative},
+	{Key: "ui.quiet", Section: "Output", Kind: KindBool,
+		Help: "Leave out progress and hints, keeping results and errors"},
+	{Key: "ui.no_emoji", Section: "Output", Kind: KindBool,
+		Help: "Use plain ASCII instead of emoji and symbols"},
+	{Key: "spell.enabled", Section: "Spell-check", Kind: KindBool,
+		Help: "Spell-check commit messages before committing"},
+	{Key: "spell.words", Section: "Spell-check", Kind: KindList,
+		Help: "Words accepted as written"},
+	{Key: "spell.terms", Section: "Spell-check", Kind: KindList,
+		Help: "Names whose capitalization is enforced"},
+	{Key: "generated.patterns", Section: "Generated files", Kind: KindList,
+		Help: "More patterns for files left out of prompts and diffstats"},
+	{Key: "message.post_process", Section: "Commit messages", Kind: KindStructured,
+		Help: "Steps applied to generated messages"},
+	{Key: "message.body_width", Section: "Commit messages", Kind: KindInt,
+		Help: "Column the body of generated messages is wrapped at (0 to leave as is)", check: checkNonNegative},
+	{Key: "commit.max_backdate", Section: "Commit dates", Kind: KindString,
+		Help: "How far back a commit may be dated without --allow-backdate, like 168h (0 for no limit)", check: checkBackdate},
+	{Key: "tasks.pre_commit", Section: "Pre-commit tasks", Kind: KindStructured,
+		Help: "Commands run before every commit"},
+	{Key: "tests.mappings", Section: "Test impact", Kind: KindStructured,
+		Help: "Where the tests of non-Go source files are and how to run them"},
+	{Key: "tests.timeout", Section: "Test impact", Kind: KindString,
+		Help: "How long each test command may run, like 5m", check: checkDuration},
+	{Key: "risk.enabled", Section: "Commit risk", Kind: KindBool,
+		Help: "Score the risk of every commit before making it"},
+	{Key: "risk.warn_at", Section: "Commit risk", Kind: KindInt,
+		Help: "Score from which the commit lists what makes it risky", check: checkScore},
+	{Key: "risk.confirm_at", Section: "Commit risk", Kind: KindInt,
+		Help: "Score from which the commit asks first (0 never asks)", check: checkScore},
+	{Key: "risk.ai", Section: "Commit risk", Kind: KindBool,
+		Help: "Have the AI provider rate the diff too"},
+	{Key: "risk.sensitive", Section: "Commit risk", Kind: KindList,
+		Help: "More patterns for migration and configuration files"},
+	{Key: "owners.warn_areas", Section: "Code owners", Kind: KindInt,
+		Help: "Ownership areas from which a commit warns it needs many reviews (0 never)", check: checkNonNegative},
+	{Key: "notes.environment", Section: "Commit notes", Kind: KindBool,
+		Help: "Attach the environment to every commit as a git note"},
+	{Key: "notes.ref", Section: "Commit notes", Kind: KindString,
+		Help: "Notes ref the environment is written to", check: checkNotesRef},
+	{Key: "issue.branch_template", Section: "Issue branches", Kind: KindString,
+		Help: "Name of branches started for an issue", check: checkBranchTemplate},
+	{Key: "issue.reference", Section: "Issue branches", Kind: KindBool,
+		Help: `Add "Refs #N" to commits on a branch started for issue N`},
+	{Key: "keep.file", Section: "Empty directories", Kind: KindString,
+		Help: "Name of the empty file 'bgit keep' puts in a directory", check: checkKeepFile},
+	{Key: "metrics.enabled", Section: "Metrics", Kind: KindBool,
+		Help: "Count commits and AI requests on this machine"},
+	{Key: "metrics.file", Section: "Metrics", Kind: KindString,
+		Help: "Where the counters are kept (empty for the cache directory)"},
+	{Key: "extends", Section: "Shared configuration", Kind: KindList,
+		Help: "Shared config files whose settings apply where this one sets none", check: checkExtends},
+}
+
+func providerNames() []string {
+	names := make([]string, 0, len(AvailableProviders))
+	for _, p := range AvailableProviders {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// Current renders the value of setting in effect, as it is edited.
+func (s *Store) Current(setting Setting) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	switch setting.Kind {
+	case KindBool:
+		return strconv.FormatBool(s.v.GetBool(setting.Key))
+	case KindInt:
+		return strconv.Itoa(s.v.GetInt(setting.Key))
+	case KindList:
+		return strings.Join(s.v.GetStringSlice(setting.Key), ", ")
+	case KindStructured:
+		entries, _ := s.v.Get(setting.Key).([]any)
+		if len(entries) == 1 {
+			return "1 entry"
+		}
+		return fmt.Sprintf("%d entries", len(entries))
+	}
+	return s.v.GetString(setting.Key)
+}
+
+// Parse turns text as typed in an editor into the setting's value, or says
+// why it is not one.
+func (s Setting) Parse(text string) (any, error) {
+	text = strings.TrimSpace(text)
+	var value any
+	switch s.Kind {
+	case KindBool:
+		v, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("want true or false")
+		}
+		value = v
+	case KindInt:
+		v, err := strconv.Atoi(text)
+		if err != nil {
+			return nil, fmt.Errorf("want a whole number")
+		}
+		value = v
+	case KindChoice:
+		for _, c := range s.Choices {
+			if strings.EqualFold(c, text) {
+				return c, nil
+			}
+		}
+		return nil, fmt.Errorf("want one of %s", strings.Join(s.Choices, ", "))
+	case KindList:
+		items := []string{}
+		for _, item := range strings.Split(text, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value = items
+	case KindStructured:
+		return nil, fmt.Errorf("edit %s in the config file", s.Key)
+	default:
+		value = text
+	}
+	if s.check != nil {
+		if err := s.check(value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func checkEnvName(v any) error {
+	if !envNamePattern.MatchString(v.(string)) {
+		return fmt.Errorf("not an environment variable name")
+	}
+	return nil
+}
+
+func checkNonNegative(v any) error {
+	if v.(int) < 0 {
+		return fmt.Errorf("cannot be negative")
+	}
+	return nil
+}
+
+func checkDuration(v any) error {
+	if d, err := time.ParseDuration(v.(string)); err != nil || d <= 0 {
+		return fmt.Errorf("want a duration like 90s or 5m")
+	}
+	return nil
+}
+
+func checkBackdate(v any) error {
+	if d, err := time.ParseDuration(v.(string)); err != nil || d < 0 {
+		return fmt.Errorf("want a duration like 168h, or 0")
+	}
+	return nil
+}
+
+func checkScore(v any) error {
+	if n := v.(int); n < 0 || n > 100 {
+		return fmt.Errorf("want a score from 0 to 100")
+	}
+	return nil
+}
+
+func checkNotesRef(v any) error {
+	if !strings.HasPrefix(v.(string), "refs/notes/") {
+		return fmt.Errorf("must start with refs/notes/")
+	}
+	return nil
+}
+
+func checkBranchTemplate(v any) error {
+	if t := v.(string); t != "" && !strings.Contains(t, "{number}") {
+		return fmt.Errorf("must contain {number}")
+	}
+	return nil
+}
+
+func checkKeepFile(v any) error {
+	if f := v.(string); f == "" || f == "." || f == ".." || strings.ContainsAny(f, `/\`) {
+		return fmt.Errorf("want a file name, like .gitkeep")
+	}
+	return nil
+}
+
+func checkExtends(v any) error {
+	for _, raw := range v.([]string) {
+		if _, err := parseExtends(raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// A repository file comes with whatever was cloned, and a shared file under
+// extends can change upstream after it was read, so the commands they set
+// (pre-commit tasks, post-processing commands, test commands) would run on
+// this machine without the user having seen them. They are left out until
+// the user trusts the file as it is now, which records a hash of it in the
+// global file; once the file changes, they are left out again.
+
+// trustedKey holds the trusted files in the global file. It is only read
+// from there.
+const trustedKey = "trusted"
+
+// TrustedFile is a config file whose commands run.
+type TrustedFile struct {
+	// Source is the path of the repo file, or the extends source as written.
+	Source string `mapstructure:"source"`
+	// SHA256 is the hash of its contents when it was trusted.
+	SHA256 string `mapstructure:"sha256"`
+}
+
+// Untrusted is a config file whose commands were left out because it has
+// not been trusted as it is now.
+type Untrusted struct {
+	Source string
+	// Commands are the commands left out, as written in the file.
+	Commands []string
+
+	sum string
+}
+
+// fileSum is the hash trust is recorded with.
+func fileSum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// readTrusted returns the files trusted in the global file at path.
+func readTrusted(path string) ([]TrustedFile, error) {
+	file, err := readLayer(path)
+	if err != nil || file == nil {
+		return nil, err
+	}
+	var trusted []TrustedFile
+	if err := file.UnmarshalKey(trustedKey, &trusted); err != nil {
+		return nil, fmt.Errorf("%s in %s: %w", trustedKey, path, err)
+	}
+	return trusted, nil
+}
+
+func isTrusted(trusted []TrustedFile, source, sum string) bool {
+	return slices.Contains(trusted, TrustedFile{Source: source, SHA256: sum})
+}
+
+// stripCommands removes the settings that run commands from settings, a
+// whole config file, and returns the commands removed.
+func stripCommands(settings map[string]any) []string {
+	var removed []string
+	if tasks, ok := settings["tasks"].(map[string]any); ok {
+		if steps, ok := tasks["pre_commit"].([]any); ok {
+			for _, step := range steps {
+				step, _ := step.(map[string]any)
+				removed = append(removed, fmt.Sprint(step["run"]))
+			}
+			delete(tasks, "pre_commit")
+		}
+	}
+	if message, ok := settings["message"].(map[string]any); ok {
+		removed = append(removed, dropSteps(message, "post_process", "command")...)
+	}
+	if tests, ok := settings["tests"].(map[string]any); ok {
+		removed = append(removed, dropSteps(tests, "mappings", "run")...)
+	}
+	return removed
+}
+
+// stripTaskEnv removes env from the tasks in settings, a whole config file:
+// only the global file may hand credentials to a task.
+func stripTaskEnv(settings map[string]any) {
+	tasks, _ := settings["tasks"].(map[string]any)
+	steps, _ := tasks["pre_commit"].([]any)
+	for _, step := range steps {
+		if step, ok := step.(map[string]any); ok {
+			delete(step, "env")
+		}
+	}
+}
+
+// dropSteps removes the items of the list parent[key] that set field, and
+// returns the values they had. The list is removed when none is left, so
+// that a lower layer's applies.
+func dropSteps(parent map[string]any, key, field string) []string {
+	steps, ok := parent[key].([]any)
+	if !ok {
+		return nil
+	}
+	var kept []any
+	var dropped []string
+	for _, step := range steps {
+		if m, ok := step.(map[string]any); ok && m[field] != nil && m[field] != "" {
+			dropped = append(dropped, fmt.Sprint(m[field]))
+			continue
+		}
+		kept = append(kept, step)
+	}
+	if len(kept) == 0 {
+		delete(parent, key)
+	} else {
+		parent[key] = kept
+	}
+	return dropped
+}
+
+// Untrusted lists the config files in use whose commands were left out.
+func (s *Store) Untrusted() []Untrusted {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.untrusted)
+}
+
+// Trust records every file Untrusted lists as trusted, as it was when it
+// was read, and reloads the configuration so their commands apply. It
+// returns the files trusted.
+func (s *Store) Trust() ([]Untrusted, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.untrusted) == 0 {
+		return nil, nil
+	}
+	trusted, err := readTrusted(s.path)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range s.untrusted {
+		trusted = slices.DeleteFunc(trusted, func(t TrustedFile) bool { return t.Source == u.Source })
+		trusted = append(trusted, TrustedFile{Source: u.Source, SHA256: u.sum})
+	}
+	if err := writeTrusted(s.path, trusted); err != nil {
+		return nil, err
+	}
+	done := s.untrusted
+	return done, s.reload()
+}
+
+// retrust records the repo file at path as trusted again after bgit itself
+// changed it, if it was trusted before: sum is its hash then.
+func (s *Store) retrust(path, sum string) error {
+	trusted, err := readTrusted(s.path)
+	if err != nil || !isTrusted(trusted, path, sum) {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	for i := range trusted {
+		if trusted[i].Source == path {
+			trusted[i].SHA256 = fileSum(data)
+		}
+	}
+	return writeTrusted(s.path, trusted)
+}
+
+func writeTrusted(path string, trusted []TrustedFile) error {
+	list := make([]map[string]any, 0, len(trusted))
+	for _, t := range trusted {
+		list = append(list, map[string]any{"source": t.Source, "sha256": t.SHA256})
+	}
+	return writeFileAt(path, map[string]any{trustedKey: list})
+}
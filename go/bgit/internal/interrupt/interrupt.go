@@ -0,0 +1,184 @@
+// Package interrupt turns Ctrl-C into an orderly shutdown. A single Handler
+// owns the process signals: the first SIGINT/SIGTERM cancels the command
+// context and gives the command a grace period to unwind, after which (or on
+// a second signal) registered cleanups run and the process exits.
+//
+// Cleanups undo partial state that only matters when a command is cut
+// short: temp files, a half-written index, a terminal left in raw mode.
+// They run in reverse registration order, like defers.
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// ExitCode is the conventional status for a process killed by SIGINT.
+const ExitCode = 130
+
+// DefaultGrace is how long a cancelled command may take to return on its own
+// before cleanups are forced.
+const DefaultGrace = 2 * time.Second
+
+type cleanup struct {
+	id   int
+	name string
+	fn   func() error
+}
+
+// Handler cancels a context on interrupt and runs registered cleanups.
+type Handler struct {
+	// Grace bounds how long to wait for the command to return after the
+	// first signal.
+	Grace time.Duration
+	// Log receives cleanup failures. Defaults to os.Stderr.
+	Log io.Writer
+	// Exit terminates the process. Tests replace it.
+	Exit func(code int)
+
+	mu          sync.Mutex
+	cleanups    []cleanup
+	nextID      int
+	interrupted bool
+	ran         bool
+}
+
+// New returns a Handler with production defaults.
+func New() *Handler {
+	return &Handler{
+		Grace: DefaultGrace,
+		Log:   os.Stderr,
+		Exit:  os.Exit,
+	}
+}
+
+// Register adds fn to the cleanups run on interrupt. The returned release
+// function removes it again and should be called once the protected work
+// has completed normally.
+func (h *Handler) Register(name string, fn func() error) (release func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.nextID++
+	id := h.nextID
+	h.cleanups = append(h.cleanups, cleanup{id: id, name: name, fn: fn})
+
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		for i, c := range h.cleanups {
+			if c.id == id {
+				h.cleanups = append(h.cleanups[:i], h.cleanups[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// RemoveOnInterrupt registers removal of a temporary file or directory.
+func (h *Handler) RemoveOnInterrupt(path string) (release func()) {
+	return h.Register("remove "+path, func() error {
+		if err := os.RemoveAll(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	})
+}
+
+// RestoreTerminal snapshots the mode of the terminal on fd and registers a
+// cleanup that puts it back, so an interrupted raw-mode UI does not leave the
+// shell unusable. It is a no-op when fd is not a terminal.
+func (h *Handler) RestoreTerminal(fd uintptr) {
+	if !term.IsTerminal(fd) {
+		return
+	}
+	state, err := term.GetState(fd)
+	if err != nil {
+		return
+	}
+	h.Register("restore terminal", func() error {
+		return term.Restore(fd, state)
+	})
+}
+
+// Interrupted reports whether a signal has been received.
+func (h *Handler) Interrupted() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.interrupted
+}
+
+// RunCleanups runs every registered cleanup once, newest first, reporting
+// failures to Log. Later calls do nothing.
+func (h *Handler) RunCleanups() {
+	h.mu.Lock()
+	if h.ran {
+		h.mu.Unlock()
+		return
+	}
+	h.ran = true
+	pending := h.cleanups
+	h.cleanups = nil
+	h.mu.Unlock()
+
+	for i := len(pending) - 1; i >= 0; i-- {
+		c := pending[i]
+		if err := c.fn(); err != nil {
+			fmt.Fprintf(h.Log, "warning: cleanup %q failed: %v\n", c.name, err)
+		}
+	}
+}
+
+// Notify installs the signal handler and returns a context that is
+// cancelled on the first SIGINT or SIGTERM. Call stop once the command has
+// returned to uninstall it.
+func (h *Handler) Notify(parent context.Context) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(parent)
+
+	sig := make(chan os.Signal, 2)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-sig:
+		case <-done:
+			return
+		}
+
+		h.mu.Lock()
+		h.interrupted = true
+		h.mu.Unlock()
+		cancel()
+
+		// Give the command a chance to notice the cancellation and return;
+		// the caller then runs cleanups itself. Force them on a second
+		// signal or when the grace period runs out.
+		select {
+		case <-done:
+			return
+		case <-sig:
+		case <-time.After(h.Grace):
+		}
+		h.RunCleanups()
+		h.Exit(ExitCode)
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(sig)
+			close(done)
+			cancel()
+		})
+	}
+}
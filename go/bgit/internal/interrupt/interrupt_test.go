@@ -0,0 +1,112 @@
+package interrupt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunCleanupsNewestFirst(t *testing.T) {
+	var log bytes.Buffer
+	h := &Handler{Log: &log}
+	var ran []string
+	add := func(name string, err error) func() {
+		return h.Register(name, func() error {
+			ran = append(ran, name)
+			return err
+		})
+	}
+	add("first", nil)
+	release := add("released", nil)
+	add("failing", errors.New("disk full"))
+	add("last", nil)
+	release()
+
+	h.RunCleanups()
+	h.RunCleanups()
+	if want := []string{"last", "failing", "first"}; !slices.Equal(ran, want) {
+		t.Errorf("cleanups ran %v, want %v once each", ran, want)
+	}
+	if got := log.String(); !strings.Contains(got, `cleanup "failing" failed: disk full`) {
+		t.Errorf("log = %q, want the failed cleanup reported", got)
+	}
+}
+
+// interrupt sends the test process SIGINT, which Notify catches.
+func interrupt(t *testing.T) {
+	t.Helper()
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNotifyExitsAfterGrace(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sends itself SIGINT")
+	}
+	exited := make(chan int, 1)
+	h := &Handler{Grace: 50 * time.Millisecond, Exit: func(code int) { exited <- code }}
+	cleaned := make(chan struct{})
+	h.Register("mark", func() error { close(cleaned); return nil })
+
+	ctx, stop := h.Notify(context.Background())
+	defer stop()
+	interrupt(t)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the context was not cancelled on SIGINT")
+	}
+	if !h.Interrupted() {
+		t.Error("Interrupted = false after SIGINT")
+	}
+	// The command never returns, so the cleanups are forced once the
+	// grace period is over.
+	select {
+	case code := <-exited:
+		if code != ExitCode {
+			t.Errorf("exit code %d, want %d", code, ExitCode)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no exit after the grace period")
+	}
+	select {
+	case <-cleaned:
+	default:
+		t.Error("exited without running the cleanups")
+	}
+}
+
+func TestNotifyStopWithinGrace(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sends itself SIGINT")
+	}
+	exited := make(chan int, 1)
+	h := &Handler{Grace: time.Hour, Exit: func(code int) { exited <- code }}
+	ctx, stop := h.Notify(context.Background())
+	interrupt(t)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the context was not cancelled on SIGINT")
+	}
+	// The command returned in time; its caller runs the cleanups.
+	stop()
+	select {
+	case code := <-exited:
+		t.Errorf("exited with %d after the command returned", code)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
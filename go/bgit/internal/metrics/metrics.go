@@ -0,0 +1,258 @@
+// Package metrics keeps counters of what bgit did on this machine (commits
+// made, AI requests and how long they took, provider failures) in a small
+// JSON file, and writes them out in the Prometheus text exposition format so
+// a node_exporter textfile collector can pick them up.
+//
+// Every bgit process updates the same file, so updates take a lock file and
+// replace the store atomically. Metrics are best effort: a failed update is
+// reported to the caller and otherwise ignored.
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Buckets are the upper bounds, in seconds, of the AI latency histogram.
+// Provider calls take from under a second to the better part of a minute.
+var Buckets = []float64{0.5, 1, 2, 5, 10, 20, 30, 60}
+
+// Histogram counts observations into Buckets. Counts are per bucket, not
+// cumulative; the exposition format's cumulative counts are worked out when
+// writing.
+type Histogram struct {
+	Counts []uint64 `json:"counts"`
+	Sum    float64  `json:"sum"`
+	Count  uint64   `json:"count"`
+}
+
+func (h *Histogram) observe(seconds float64) {
+	if len(h.Counts) != len(Buckets) {
+		h.Counts = make([]uint64, len(Buckets))
+	}
+	for i, le := range Buckets {
+		if seconds <= le {
+			h.Counts[i]++
+			break
+		}
+	}
+	h.Sum += seconds
+	h.Count++
+}
+
+// Store is everything recorded so far.
+type Store struct {
+	// Commits counts commits created, by where the message came from: ai,
+	// offline or message (-m).
+	Commits map[string]uint64 `json:"commits"`
+	// AILatency holds the duration of successful AI requests, by provider.
+	AILatency map[string]*Histogram `json:"ai_latency"`
+	// AIFailures counts failed AI requests, by provider.
+	AIFailures map[string]uint64 `json:"ai_failures"`
+	// Since is when recording started.
+	Since time.Time `json:"since"`
+}
+
+// Recorder updates the store kept at Path.
+type Recorder struct {
+	Path string
+}
+
+// DefaultPath is the store in the user's cache directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bgit", "metrics.json"), nil
+}
+
+// Commit counts a commit whose message came from source.
+func (r Recorder) Commit(source string) error {
+	return r.update(func(s *Store) { s.Commits[source]++ })
+}
+
+// AIRequest records a request to provider that took took, counting it as a
+// failure when err is set.
+func (r Recorder) AIRequest(provider string, took time.Duration, err error) error {
+	return r.update(func(s *Store) {
+		if err != nil {
+			s.AIFailures[provider]++
+			return
+		}
+		h := s.AILatency[provider]
+		if h == nil {
+			h = &Histogram{}
+			s.AILatency[provider] = h
+		}
+		h.observe(took.Seconds())
+	})
+}
+
+// Load reads the store. A missing store is an empty one.
+func (r Recorder) Load() (*Store, error) {
+	s := &Store{}
+	data, err := os.ReadFile(r.Path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(data, s); err != nil {
+			return nil, fmt.Errorf("unreadable metrics in %s: %w", r.Path, err)
+		}
+	}
+	if s.Commits == nil {
+		s.Commits = map[string]uint64{}
+	}
+	if s.AILatency == nil {
+		s.AILatency = map[string]*Histogram{}
+	}
+	if s.AIFailures == nil {
+		s.AIFailures = map[string]uint64{}
+	}
+	return s, nil
+}
+
+// lockWait bounds how long an update waits for another process; a lock
+// older than staleLock was left by a process that died holding it.
+const (
+	lockWait  = time.Second
+	staleLock = 10 * time.Second
+)
+
+// update applies fn to the store under the lock and saves the result.
+func (r Recorder) update(fn func(*Store)) error {
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0o755); err != nil {
+		return err
+	}
+	unlock, err := lock(r.Path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	s, err := r.Load()
+	if err != nil {
+		return err
+	}
+	if s.Since.IsZero() {
+		s.Since = time.Now().UTC().Truncate(time.Second)
+	}
+	fn(s)
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(r.Path, data)
+}
+
+func lock(path string) (unlock func(), err error) {
+	deadline := time.Now().Add(lockWait)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is held by another bgit process", path)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// WriteFileAtomic replaces path with data in one step, so readers such as
+// the textfile collector never see a half-written file.
+func WriteFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// WriteText writes the store in the Prometheus text exposition format, with
+// series in a stable order.
+func (s *Store) WriteText(w io.Writer) error {
+	var b strings.Builder
+	header := func(name, kind, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+
+	header("bgit_commits_total", "counter", "Commits created with bgit, by where the message came from.")
+	for _, source := range sortedKeys(s.Commits) {
+		fmt.Fprintf(&b, "bgit_commits_total{source=%s} %d\n", quote(source), s.Commits[source])
+	}
+
+	header("bgit_ai_request_duration_seconds", "histogram", "Time taken by successful AI requests for commit messages.")
+	for _, provider := range sortedKeys(s.AILatency) {
+		h := s.AILatency[provider]
+		label := "provider=" + quote(provider)
+		var cumulative uint64
+		for i, le := range Buckets {
+			if i < len(h.Counts) {
+				cumulative += h.Counts[i]
+			}
+			fmt.Fprintf(&b, "bgit_ai_request_duration_seconds_bucket{%s,le=%q} %d\n", label, strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(&b, "bgit_ai_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", label, h.Count)
+		fmt.Fprintf(&b, "bgit_ai_request_duration_seconds_sum{%s} %s\n", label, strconv.FormatFloat(h.Sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "bgit_ai_request_duration_seconds_count{%s} %d\n", label, h.Count)
+	}
+
+	header("bgit_ai_failures_total", "counter", "AI requests for commit messages that failed, by provider.")
+	for _, provider := range sortedKeys(s.AIFailures) {
+		fmt.Fprintf(&b, "bgit_ai_failures_total{provider=%s} %d\n", quote(provider), s.AIFailures[provider])
+	}
+
+	if !s.Since.IsZero() {
+		header("bgit_metrics_since_timestamp_seconds", "gauge", "When bgit started recording these metrics.")
+		fmt.Fprintf(&b, "bgit_metrics_since_timestamp_seconds %d\n", s.Since.Unix())
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// quote renders a label value, escaping as the exposition format requires.
+func quote(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v) + `"`
+}
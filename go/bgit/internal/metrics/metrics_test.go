@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/endalk200/bgit/internal/ui/uitest"
+)
+
+func TestWriteText(t *testing.T) {
+	s := &Store{
+		Commits: map[string]uint64{"message": 2, "ai": 5},
+		AILatency: map[string]*Histogram{
+			"openai": {},
+			// Label values are escaped.
+			"say \"hi\" \\ then\nbye": {},
+		},
+		AIFailures: map[string]uint64{"anthropic": 1},
+		Since:      time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC),
+	}
+	for _, took := range []float64{0.2, 0.5, 3, 7.5, 90} {
+		s.AILatency["openai"].observe(took)
+	}
+	s.AILatency["say \"hi\" \\ then\nbye"].observe(1)
+
+	var b strings.Builder
+	if err := s.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	uitest.AssertGolden(t, "exposition", b.String())
+}
+
+func TestWriteTextEmpty(t *testing.T) {
+	s, err := Recorder{Path: filepath.Join(t.TempDir(), "metrics.json")}.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := s.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	// The families are described even before anything is recorded.
+	for _, want := range []string{"# TYPE bgit_commits_total counter\n", "# TYPE bgit_ai_request_duration_seconds histogram\n"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("WriteText = %q, want it to contain %q", b.String(), want)
+		}
+	}
+	if strings.Contains(b.String(), "since") {
+		t.Errorf("WriteText = %q, want no start time before anything is recorded", b.String())
+	}
+}
+
+func TestRecorderConcurrent(t *testing.T) {
+	r := Recorder{Path: filepath.Join(t.TempDir(), "bgit", "metrics.json")}
+	const workers, each = 4, 5
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*each*2)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range each {
+				errs <- r.Commit("ai")
+				errs <- r.AIRequest("openai", 2*time.Second, nil)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.AIRequest("openai", time.Second, errors.New("timeout")); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := r.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A lost update would show as a lower count.
+	if got := s.Commits["ai"]; got != workers*each {
+		t.Errorf("commits = %d, want %d", got, workers*each)
+	}
+	if h := s.AILatency["openai"]; h == nil || h.Count != workers*each || h.Sum != 2*workers*each {
+		t.Errorf("latency = %+v, want %d requests of 2s", h, workers*each)
+	}
+	if got := s.AIFailures["openai"]; got != 1 {
+		t.Errorf("failures = %d, want 1", got)
+	}
+	if s.Since.IsZero() {
+		t.Error("Since is not set")
+	}
+}
@@ -0,0 +1,223 @@
+// Package sandbox runs user-configured shell commands (pre-commit tasks,
+// message filters) so that they cannot hang bgit or see its secrets: each
+// runs with API keys and tokens removed from its environment, under a
+// timeout that kills it and anything it started, with its output captured up
+// to a bound.
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Defaults for tasks that do not set their own limits.
+const (
+	DefaultTimeout   = 2 * time.Minute
+	DefaultMaxOutput = 1 << 20
+)
+
+// Task is a command to run through the shell.
+type Task struct {
+	Name    string
+	Command string
+	// Dir is the working directory; empty means the current one.
+	Dir   string
+	Stdin string
+	// Timeout bounds the run; 0 means DefaultTimeout.
+	Timeout time.Duration
+	// MaxOutput bounds what is kept of stdout and of stderr, each; 0 means
+	// DefaultMaxOutput. Output past the bound is discarded, not buffered.
+	MaxOutput int
+	// Pass names variables that look like credentials the task may see
+	// after all.
+	Pass []string
+	// Secrets names more variables to remove, on top of the ones that look
+	// like credentials, whatever Pass says.
+	Secrets []string
+}
+
+// Result is how a task ended.
+type Result struct {
+	Name   string
+	Stdout []byte
+	Stderr []byte
+	// Truncated is set when output was cut at MaxOutput.
+	Truncated bool
+	// ExitCode is the command's exit status, -1 when it did not exit on its
+	// own (killed, timed out, or never started).
+	ExitCode int
+	TimedOut bool
+	Took     time.Duration
+	// Err is set when the task did not succeed: a non-zero exit, a
+	// timeout, or a failure to start.
+	Err error
+}
+
+// Failed reports whether the task did not succeed.
+func (r Result) Failed() bool { return r.Err != nil }
+
+// Summary says how the task ended, e.g. "exit status 2 after 1.4s".
+func (r Result) Summary() string {
+	took := r.Took.Round(100 * time.Millisecond)
+	switch {
+	case r.TimedOut:
+		return fmt.Sprintf("timed out after %s", took)
+	case r.ExitCode > 0:
+		return fmt.Sprintf("exit status %d after %s", r.ExitCode, took)
+	case r.Err != nil:
+		return r.Err.Error()
+	}
+	return fmt.Sprintf("passed in %s", took)
+}
+
+// Output is the task's stderr and stdout together, for showing why it
+// failed.
+func (r Result) Output() string {
+	out := strings.TrimRight(string(r.Stderr), "\n")
+	if stdout := strings.TrimRight(string(r.Stdout), "\n"); stdout != "" {
+		if out != "" {
+			out += "\n"
+		}
+		out += stdout
+	}
+	if r.Truncated {
+		out += "\n[output truncated]"
+	}
+	return out
+}
+
+// Run runs task to completion, its timeout or the end of ctx, whichever
+// comes first.
+func Run(ctx context.Context, task Task) Result {
+	timeout := task.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	limit := task.MaxOutput
+	if limit <= 0 {
+		limit = DefaultMaxOutput
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	cmd := exec.CommandContext(ctx, shell, flag, task.Command)
+	cmd.Dir = task.Dir
+	cmd.Env = ScrubEnv(os.Environ(), task.Secrets, task.Pass)
+	cmd.Stdin = strings.NewReader(task.Stdin)
+	stdout, stderr := &boundedBuffer{limit: limit}, &boundedBuffer{limit: limit}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	isolate(cmd)
+	// A child that keeps the output pipes open must not keep us waiting
+	// once the command itself is gone.
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	err := cmd.Run()
+	r := Result{
+		Name:      task.Name,
+		Stdout:    stdout.Bytes(),
+		Stderr:    stderr.Bytes(),
+		Truncated: stdout.truncated || stderr.truncated,
+		ExitCode:  -1,
+		Took:      time.Since(start),
+	}
+	if cmd.ProcessState != nil && cmd.ProcessState.Exited() {
+		r.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		r.TimedOut = true
+		r.Err = fmt.Errorf("timed out after %s", timeout)
+	case ctx.Err() != nil:
+		r.Err = ctx.Err()
+	case err != nil && r.ExitCode > 0:
+		r.Err = fmt.Errorf("exit status %d", r.ExitCode)
+	case errors.Is(err, exec.ErrWaitDelay):
+		// The command exited cleanly; something it started held on to
+		// its output.
+	case err != nil:
+		r.Err = err
+	}
+	return r
+}
+
+// secretMarks mark variables that hold credentials anywhere in their
+// names, as in AWS_SECRET_ACCESS_KEY or GITHUB_TOKEN_FOR_CI.
+var secretMarks = []string{"SECRET", "TOKEN", "PASSWORD", "PASSWD", "CREDENTIAL"}
+
+// secretWords mark them as one word of the name, so that GPG_PRIVATE_KEY
+// and DEPLOY_KEY_PATH are secret and KEYBOARD or MONKEY_PATCH are not.
+var secretWords = []string{"KEY", "APIKEY"}
+
+// isSecret reports whether the variable called name looks like it holds a
+// credential.
+func isSecret(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, mark := range secretMarks {
+		if strings.Contains(upper, mark) {
+			return true
+		}
+	}
+	words := strings.FieldsFunc(upper, func(r rune) bool { return r == '_' || r == '-' || r == '.' })
+	for _, w := range words {
+		if slices.Contains(secretWords, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScrubEnv returns env without the variables that look like credentials
+// (names with SECRET, TOKEN, PASSWORD or the word KEY in them, and the
+// like), except the ones named in pass, and without those named in secrets,
+// which pass cannot bring back.
+func ScrubEnv(env, secrets, pass []string) []string {
+	out := make([]string, 0, len(env))
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		secret := isSecret(name)
+		for _, p := range pass {
+			if strings.EqualFold(p, name) {
+				secret = false
+			}
+		}
+		for _, s := range secrets {
+			secret = secret || strings.EqualFold(s, name)
+		}
+		if !secret {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
+
+// boundedBuffer keeps the first limit bytes written to it and drops the
+// rest, while still accepting them so the writer is never blocked.
+type boundedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *boundedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.Buffer.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
@@ -0,0 +1,79 @@
+package sandbox
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestScrubEnv(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret bool
+	}{
+		{"OPENAI_API_KEY", true},
+		{"STRIPE_APIKEY", true},
+		{"GITHUB_TOKEN", true},
+		{"CI_JOB_TOKEN_FILE", true},
+		{"AWS_SECRET_ACCESS_KEY", true},
+		{"AWS_ACCESS_KEY_ID", true},
+		{"SSH_PRIVATE_KEY", true},
+		{"GPG_KEY", true},
+		{"DEPLOY_KEY_PATH", true},
+		{"DB_PASSWORD", true},
+		{"MYSQL_ROOT_PASSWD", true},
+		{"PASSWORD", true},
+		{"GOOGLE_APPLICATION_CREDENTIALS", true},
+		{"client_secret", true},
+		{"npm_config_authtoken", true},
+		{"PATH", false},
+		{"HOME", false},
+		{"GOFLAGS", false},
+		{"KEYBOARD_LAYOUT", false},
+		{"MONKEY_PATCH", false},
+		{"SSH_AUTH_SOCK", false},
+		{"GIT_ASKPASS", false},
+	}
+	var env []string
+	for _, tt := range tests {
+		env = append(env, tt.name+"=value")
+	}
+	kept := ScrubEnv(env, nil, nil)
+	for _, tt := range tests {
+		if got := !slices.Contains(kept, tt.name+"=value"); got != tt.secret {
+			t.Errorf("%s removed = %v, want %v", tt.name, got, tt.secret)
+		}
+	}
+}
+
+func TestScrubEnvSecretsAndPass(t *testing.T) {
+	env := []string{"PATH=/bin", "ANTHROPIC_API_KEY=sk-ant", "MY_PROVIDER=sk-x", "NPM_TOKEN=npm", "LANG=C"}
+	kept := ScrubEnv(env, []string{"my_provider"}, []string{"npm_token"})
+	want := []string{"PATH=/bin", "NPM_TOKEN=npm", "LANG=C"}
+	if !slices.Equal(kept, want) {
+		t.Errorf("ScrubEnv = %v, want %v", kept, want)
+	}
+
+	// A task cannot ask for the provider key back.
+	kept = ScrubEnv(env, []string{"ANTHROPIC_API_KEY", "MY_PROVIDER"}, []string{"anthropic_api_key", "MY_PROVIDER", "NPM_TOKEN"})
+	if !slices.Equal(kept, want) {
+		t.Errorf("ScrubEnv passing the secrets = %v, want %v", kept, want)
+	}
+}
+
+func TestRunHidesSecrets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("runs sh")
+	}
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "hunter2")
+	t.Setenv("BGIT_SANDBOX_VISIBLE", "shown")
+	r := Run(context.Background(), Task{Command: `echo "[$AWS_SECRET_ACCESS_KEY][$BGIT_SANDBOX_VISIBLE]"`})
+	if r.Failed() {
+		t.Fatalf("the task failed: %s", r.Summary())
+	}
+	if got := strings.TrimSpace(string(r.Stdout)); got != "[][shown]" {
+		t.Errorf("the task saw %s, want the secret hidden and the rest shown", got)
+	}
+}
@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/endalk200/bgit/internal/config"
+)
+
+// Depth is how closely Explain goes through a change.
+type Depth string
+
+const (
+	// DepthSummary explains what the change does as a whole.
+	DepthSummary Depth = "summary"
+	// DepthLines walks through the change file by file and hunk by hunk.
+	DepthLines Depth = "lines"
+)
+
+// Depths lists the depths Explain accepts.
+var Depths = []Depth{DepthSummary, DepthLines}
+
+// explainPrompts ask for an explanation at each depth. The reader is
+// assumed not to know the code, so the answer is meant for reviewing it.
+var explainPrompts = map[Depth]string{
+	DepthSummary: `Explain in plain language what the following change does, for a reviewer who does not know this code.
+Start with one or two sentences on its purpose, then list the notable changes as short bullet points,
+and end with anything that looks risky or worth a closer look. Do not repeat the diff.`,
+	DepthLines: `Walk a reviewer who does not know this code through the following change, file by file and hunk by hunk.
+For each file give its name as a heading, then explain what each changed block does and why it may have
+been changed, quoting only the lines you discuss. End with anything that looks risky or worth a closer look.`,
+}
+
+// Explain asks the configured provider to explain a change in plain
+// language at depth. about describes the change, such as the message of
+// the commit being explained, and may be empty. Cancelling ctx aborts the
+// in-flight request.
+func Explain(ctx context.Context, about string, diff *Diff, depth Depth, provider config.Provider) (string, error) {
+	instructions, ok := explainPrompts[depth]
+	if !ok {
+		return "", fmt.Errorf("unknown depth %q", depth)
+	}
+	prompt := instructions + "\n"
+	if about != "" {
+		prompt += "\nThe change is described as:\n" + about + "\n"
+	}
+	prompt += "\nThe diff:\n"
+	return complete(ctx, prompt, diff, provider)
+}
@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// fileChange is what a diff did to one file.
+type fileChange struct {
+	verb string // Add, Update, Remove or Rename
+	path string
+	from string // the old path of a rename
+}
+
+// HeuristicMessage writes a commit message for diff without asking an AI
+// provider: a subject naming the file or directory that changed and, for
+// more than one file, a body listing them. It is the fallback when the
+// network may not be used.
+func HeuristicMessage(diff string) string {
+	changes := diffChanges(diff)
+	if len(changes) == 0 {
+		return "Update files"
+	}
+
+	prefix := ""
+	switch {
+	case all(changes, isDoc):
+		prefix = "docs: "
+	case all(changes, isTest):
+		prefix = "test: "
+	}
+
+	if len(changes) == 1 {
+		c := changes[0]
+		if c.verb == "Rename" {
+			return fmt.Sprintf("%sRename %s to %s", prefix, c.from, c.path)
+		}
+		return fmt.Sprintf("%s%s %s", prefix, c.verb, c.path)
+	}
+
+	verb := changes[0].verb
+	for _, c := range changes[1:] {
+		if c.verb != verb {
+			verb = "Update"
+			break
+		}
+	}
+	subject := fmt.Sprintf("%s%s %d files", prefix, verb, len(changes))
+	if dir := commonDir(changes); dir != "" {
+		subject += " in " + dir
+	}
+
+	var b strings.Builder
+	b.WriteString(subject + "\n\n")
+	for _, c := range changes {
+		if c.verb == "Rename" {
+			fmt.Fprintf(&b, "- Rename %s to %s\n", c.from, c.path)
+			continue
+		}
+		fmt.Fprintf(&b, "- %s %s\n", c.verb, c.path)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// diffChanges reads the file headers of a unified diff.
+func diffChanges(diff string) []fileChange {
+	var changes []fileChange
+	var cur *fileChange
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			changes = append(changes, fileChange{verb: "Update", path: diffFileName(line)})
+			cur = &changes[len(changes)-1]
+		case cur == nil:
+		case strings.HasPrefix(line, "new file mode"):
+			cur.verb = "Add"
+		case strings.HasPrefix(line, "deleted file mode"):
+			cur.verb = "Remove"
+		case strings.HasPrefix(line, "rename from "):
+			cur.verb, cur.from = "Rename", strings.TrimPrefix(line, "rename from ")
+		}
+	}
+	return changes
+}
+
+// commonDir is the deepest directory holding every changed file, or "" when
+// they only share the repository root.
+func commonDir(changes []fileChange) string {
+	dirs := make([]string, 0, len(changes))
+	for _, c := range changes {
+		dirs = append(dirs, path.Dir(c.path))
+	}
+	sort.Strings(dirs)
+	first, last := strings.Split(dirs[0], "/"), strings.Split(dirs[len(dirs)-1], "/")
+	n := 0
+	for n < len(first) && n < len(last) && first[n] == last[n] {
+		n++
+	}
+	dir := strings.Join(first[:n], "/")
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+func all(changes []fileChange, pred func(string) bool) bool {
+	for _, c := range changes {
+		if !pred(c.path) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDoc(p string) bool {
+	ext := strings.ToLower(path.Ext(p))
+	return ext == ".md" || ext == ".rst" || ext == ".txt" || strings.HasPrefix(p, "docs/")
+}
+
+func isTest(p string) bool {
+	base := path.Base(p)
+	return strings.HasSuffix(base, "_test.go") || strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") ||
+		strings.HasPrefix(p, "test/") || strings.HasPrefix(p, "tests/") || strings.Contains(p, "/testdata/")
+}
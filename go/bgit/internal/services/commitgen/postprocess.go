@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/endalk200/bgit/internal/config"
+	"github.com/endalk200/bgit/internal/sandbox"
+)
+
+// ErrPostProcessFailed reports which configured step could not be applied.
+type ErrPostProcessFailed struct {
+	Step    int // 1-based, as a reader counts the config entries
+	Message string
+}
+
+func (e ErrPostProcessFailed) Error() string {
+	return fmt.Sprintf("message post-processor %d: %s", e.Step, e.Message)
+}
+
+// PostProcess applies steps to a generated message in order, each working on
+// the result of the one before:
+//
+//   - Replace rewrites every match of a regular expression, with $1 and the
+//     like expanding to its groups;
+//   - Prefix is put in front of the subject unless it is already there;
+//   - Command runs through the shell with the message on stdin and its
+//     output becomes the message. It runs in a sandbox: without API keys,
+//     tokens or the variables named in secrets, and killed after its
+//     timeout.
+func PostProcess(ctx context.Context, message string, steps []config.PostProcessor, secrets []string) (string, error) {
+	for i, step := range steps {
+		fail := func(format string, a ...any) (string, error) {
+			return "", ErrPostProcessFailed{Step: i + 1, Message: fmt.Sprintf(format, a...)}
+		}
+		switch {
+		case step.Replace != "":
+			re, err := regexp.Compile(step.Replace)
+			if err != nil {
+				return fail("invalid pattern: %v", err)
+			}
+			message = re.ReplaceAllString(message, step.With)
+		case step.Prefix != "":
+			if !strings.HasPrefix(message, step.Prefix) {
+				message = step.Prefix + message
+			}
+		case step.Command != "":
+			timeout := step.Timeout
+			if timeout <= 0 {
+				timeout = config.DefaultFilterTimeout
+			}
+			r := sandbox.Run(ctx, sandbox.Task{Command: step.Command, Stdin: message, Timeout: timeout, MaxOutput: maxFilterOutput, Secrets: secrets})
+			switch {
+			case r.Failed():
+				if msg := strings.TrimSpace(string(r.Stderr)); msg != "" {
+					return fail("%s: %s: %s", step.Command, r.Summary(), msg)
+				}
+				return fail("%s: %s", step.Command, r.Summary())
+			case r.Truncated:
+				return fail("%s: printed more than %d bytes", step.Command, maxFilterOutput)
+			case strings.TrimSpace(string(r.Stdout)) == "":
+				return fail("%s: printed an empty message", step.Command)
+			}
+			message = string(r.Stdout)
+		default:
+			return fail("needs one of replace, prefix or command")
+		}
+	}
+	return strings.TrimSpace(message), nil
+}
+
+// maxFilterOutput bounds what a filter command may print; a commit message
+// is never this long.
+const maxFilterOutput = 64 << 10
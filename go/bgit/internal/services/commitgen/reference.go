@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// trailerLine matches a git trailer such as "Signed-off-by: Ada <ada@x>".
+// Issue references such as "Refs #12" or "Closes #3" count too.
+var trailerLine = regexp.MustCompile(`^(?:[A-Za-z][A-Za-z0-9-]*: |(?i:refs|closes|fixes|resolves) #\d+$)`)
+
+// ReferenceIssue adds a "Refs #number" trailer to message, so the forge links
+// the commit to the issue. A message that already mentions #number is left
+// alone. The trailer joins an existing trailer block at the end of the
+// message, or starts one after a blank line.
+func ReferenceIssue(message string, number int) string {
+	ref := fmt.Sprintf("#%d", number)
+	if regexp.MustCompile(regexp.QuoteMeta(ref) + `\b`).MatchString(message) {
+		return message
+	}
+	message = strings.TrimRight(message, "\n")
+	paragraphs := strings.Split(message, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+	sep := "\n\n"
+	if len(paragraphs) > 1 && allTrailers(last) {
+		sep = "\n"
+	}
+	return message + sep + "Refs " + ref + "\n"
+}
+
+// allTrailers reports whether every line of paragraph is a git trailer or
+// issue reference.
+func allTrailers(paragraph string) bool {
+	for _, line := range strings.Split(paragraph, "\n") {
+		if !trailerLine.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
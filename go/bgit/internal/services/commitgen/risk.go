@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/endalk200/bgit/internal/config"
+)
+
+// riskPrompt asks for a score in a form rateAnswer can read back.
+const riskPrompt = `Rate how risky it is to commit the following change, from 0 (trivial, safe) to 100 (likely to break
+something), as a careful reviewer would: consider what could break, how hard it would be to notice, and
+whether the change is covered by tests. Heuristics found: %s.
+Answer with exactly two lines and nothing else:
+SCORE: <a whole number from 0 to 100>
+REASON: <one short sentence>
+
+The diff:
+`
+
+var (
+	scoreLine  = regexp.MustCompile(`(?im)^\W*score\W*(\d{1,3})\b`)
+	reasonLine = regexp.MustCompile(`(?im)^\W*reason\W*(.+)$`)
+)
+
+// RateRisk asks the configured provider to rate the risk of a change from
+// 0 to 100, given the heuristic findings so far, and returns the score
+// with the provider's reason. Cancelling ctx aborts the in-flight request.
+func RateRisk(ctx context.Context, diff *Diff, findings string, provider config.Provider) (int, string, error) {
+	if findings == "" {
+		findings = "none"
+	}
+	answer, err := complete(ctx, fmt.Sprintf(riskPrompt, findings), diff, provider)
+	if err != nil {
+		return 0, "", err
+	}
+	return rateAnswer(answer)
+}
+
+// rateAnswer reads the score and reason out of the provider's answer.
+func rateAnswer(answer string) (int, string, error) {
+	m := scoreLine.FindStringSubmatch(answer)
+	if m == nil {
+		return 0, "", ErrAIProviderCallFailed{Code: 500, Message: "no score in the AI response"}
+	}
+	score, _ := strconv.Atoi(m[1])
+	if score > 100 {
+		return 0, "", ErrAIProviderCallFailed{Code: 500, Message: fmt.Sprintf("score %d out of range in the AI response", score)}
+	}
+	reason := "no reason given"
+	if m := reasonLine.FindStringSubmatch(answer); m != nil {
+		reason = strings.TrimSpace(m[1])
+	}
+	return score, reason, nil
+}
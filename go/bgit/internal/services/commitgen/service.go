@@ -1,17 +1,27 @@
 package internal
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/endalk200/bgit/internal/config"
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
 )
 
+// Model is the chat model asked for commit messages and explanations,
+// whichever provider serves it.
+const Model = openai.ChatModelGPT5Mini
+
 type ErrAPIKeyNotFound struct {
 	Code    int
 	Message string
@@ -48,34 +58,34 @@ func (e ErrUnknownIssue) Error() string {
 	return fmt.Sprintf("unknown issue: %d %s", e.Code, e.Message)
 }
 
-func GenerateCommitMessage(diff string, provider config.Provider) (string, error) {
-	prompt := fmt.Sprintf("Generate a concise conventional commit style message summarizing changes made in this git diff. \n%s", diff)
+// GenerateCommitMessage asks the configured provider for a commit message
+// describing diff. Cancelling ctx aborts the in-flight request.
+func GenerateCommitMessage(ctx context.Context, diff *Diff, provider config.Provider) (string, error) {
+	return complete(ctx, "Generate a concise conventional commit style message summarizing changes made in this git diff. \n", diff, provider)
+}
 
+// complete sends instructions followed by diff to the configured provider
+// and returns its answer. diff may be nil.
+func complete(ctx context.Context, instructions string, diff *Diff, provider config.Provider) (string, error) {
+	prompt := func() io.Reader {
+		if diff == nil {
+			return strings.NewReader(instructions)
+		}
+		return io.MultiReader(strings.NewReader(instructions), diff.Reader())
+	}
 	switch provider.Name {
 	case "OpenAI":
 		API_KEY, err := getOpenAIAPIKey(provider.EnvName)
 		if err != nil {
 			return "", err
 		}
-
-		commitMessage, err := OpenAIChatCompletion(prompt, API_KEY)
-		if err != nil {
-			return "", err
-		}
-
-		return commitMessage, nil
+		return OpenAIChatCompletion(ctx, prompt, API_KEY)
 	case "OpenRouter":
 		API_KEY, err := getOpenRouterAPIKey(provider.EnvName)
 		if err != nil {
 			return "", err
 		}
-
-		commitMessage, err := OpenRouterChatCompletion(prompt, API_KEY)
-		if err != nil {
-			return "", err
-		}
-
-		return commitMessage, nil
+		return OpenRouterChatCompletion(ctx, prompt, API_KEY)
 	default:
 		return "", ErrUnkownAIProvider{
 			Code:    400,
@@ -84,35 +94,15 @@ func GenerateCommitMessage(diff string, provider config.Provider) (string, error
 	}
 }
 
-func OpenAIChatCompletion(prompt string, API_KEY string) (string, error) {
-	ctx := context.Background()
-
+// OpenAIChatCompletion asks OpenAI to answer the prompt that prompt reads
+// out, calling it again for each attempt.
+func OpenAIChatCompletion(ctx context.Context, prompt func() io.Reader, API_KEY string) (string, error) {
 	client := openai.NewClient(option.WithAPIKey(API_KEY))
-	response, err := client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
-		Messages: []openai.ChatCompletionMessageParamUnion{
-			openai.UserMessage(prompt),
-		},
-		Model: openai.ChatModelGPT5Mini,
-	})
-	if err != nil {
-		return "", ErrAIProviderCallFailed{
-			Code:    500,
-			Message: err.Error(),
-		}
-	}
-
-	if len(response.Choices) == 0 || response.Choices[0].Message.Content == "" {
-		return "", ErrAIProviderCallFailed{
-			Code:    500,
-			Message: "no AI response content",
-		}
-	}
-	return strings.TrimSpace(response.Choices[0].Message.Content), nil
+	return chatCompletion(ctx, client, prompt)
 }
 
-func OpenRouterChatCompletion(prompt string, API_KEY string) (string, error) {
-	ctx := context.Background()
-
+// OpenRouterChatCompletion is OpenAIChatCompletion through OpenRouter.
+func OpenRouterChatCompletion(ctx context.Context, prompt func() io.Reader, API_KEY string) (string, error) {
 	header := http.Header{}
 	header.Set("X-Title", "bgit")
 
@@ -120,12 +110,35 @@ func OpenRouterChatCompletion(prompt string, API_KEY string) (string, error) {
 		option.WithAPIKey(API_KEY),
 		option.WithBaseURL("https://openrouter.ai/api/v1"),
 	)
-	response, err := client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
-		Messages: []openai.ChatCompletionMessageParamUnion{
-			openai.UserMessage(prompt),
-		},
-		Model: openai.ChatModelGPT5Mini,
-	})
+	return chatCompletion(ctx, client, prompt)
+}
+
+// maxAttempts is how many times a request that failed for a reason that
+// may pass, like a rate limit, is sent.
+const maxAttempts = 3
+
+// chatCompletion sends the prompt as a chat completion request. The body is
+// written as it is sent, so the client cannot replay it to retry; requests
+// are retried here instead, reading the prompt again.
+func chatCompletion(ctx context.Context, client openai.Client, prompt func() io.Reader) (string, error) {
+	var response openai.ChatCompletion
+	var err error
+	for attempt := range maxAttempts {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+		err = client.Post(ctx, "chat/completions", requestBody(prompt()), &response, option.WithMaxRetries(0))
+		if err == nil || ctx.Err() != nil || !retryable(err) {
+			break
+		}
+	}
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
 	if err != nil {
 		return "", ErrAIProviderCallFailed{
 			Code:    500,
@@ -142,7 +155,70 @@ func OpenRouterChatCompletion(prompt string, API_KEY string) (string, error) {
 	return strings.TrimSpace(response.Choices[0].Message.Content), nil
 }
 
-func AntropicChatCompletion(prompt string, API_KEY string) (string, error) {
+// retryable reports whether a request that failed with err may succeed if
+// sent again: it never reached the provider, was rate limited, or met a
+// server error.
+func retryable(err error) bool {
+	var apiErr *openai.Error
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+	return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+}
+
+// requestBody is the JSON of a chat completion request for prompt, with
+// the prompt escaped into it as the body is read.
+func requestBody(prompt io.Reader) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		model, _ := json.Marshal(Model)
+		w := bufio.NewWriter(pw)
+		w.WriteString(`{"model":` + string(model) + `,"messages":[{"role":"user","content":"`)
+		err := writeJSONString(w, bufio.NewReader(prompt))
+		w.WriteString(`"}]}`)
+		if ferr := w.Flush(); err == nil {
+			err = ferr
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// writeJSONString writes what r reads as the inside of a JSON string,
+// replacing bytes that are not UTF-8 as encoding/json does.
+func writeJSONString(w *bufio.Writer, r *bufio.Reader) error {
+	const hex = "0123456789abcdef"
+	for {
+		c, size, err := r.ReadRune()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch {
+		case c == '"' || c == '\\':
+			w.WriteByte('\\')
+			w.WriteRune(c)
+		case c == '\n':
+			w.WriteString(`\n`)
+		case c == '\r':
+			w.WriteString(`\r`)
+		case c == '\t':
+			w.WriteString(`\t`)
+		case c < 0x20:
+			w.WriteString(`\u00`)
+			w.WriteByte(hex[c>>4])
+			w.WriteByte(hex[c&0xf])
+		case c == utf8.RuneError && size == 1:
+			w.WriteString(`\ufffd`)
+		default:
+			w.WriteRune(c)
+		}
+	}
+}
+
+func AntropicChatCompletion(ctx context.Context, prompt func() io.Reader, API_KEY string) (string, error) {
 	return "", nil
 }
 
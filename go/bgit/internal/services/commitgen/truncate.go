@@ -0,0 +1,399 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Limits bound the diff sent to an AI provider. Zero means no limit.
+type Limits struct {
+	// MaxBytes caps the whole diff; the notice about what was left out
+	// comes on top.
+	MaxBytes int
+	// PerFileMaxLines caps the hunk lines kept for any one file, so a single
+	// large change does not crowd out the rest.
+	PerFileMaxLines int
+}
+
+// Truncation describes what TruncateDiff left out.
+type Truncation struct {
+	// Lines is the number of diff lines dropped from files that were kept.
+	Lines int
+	// Files lists the files that were shortened.
+	Files []string
+	// Dropped lists the files left out entirely because even their headers
+	// did not fit.
+	Dropped []string
+}
+
+// Truncated reports whether anything was left out.
+func (t Truncation) Truncated() bool { return t.Lines > 0 || len(t.Dropped) > 0 }
+
+// fileDiff is the part of a unified diff that belongs to one file.
+type fileDiff struct {
+	name   string
+	header []string // diff --git, index, ---, +++ lines
+	hunks  []hunk
+	// cutHunks counts hunks dropped whole from the end once even their
+	// headers no longer fit.
+	cutHunks int
+}
+
+type hunk struct {
+	header  string // @@ -a,b +c,d @@
+	lines   []string
+	omitted int // lines cut from the end, shown as a marker
+}
+
+// Diff is a diff shortened for a prompt with ReadDiff, kept file by file
+// and sent by reading it, so the whole of it is never one string.
+type Diff struct {
+	files []fileDiff
+	// raw is what came before the first file header, passed on as it was.
+	raw   string
+	notes []string
+	size  int
+	cut   Truncation
+}
+
+// ReadDiff reads a unified diff from r file by file and shortens it to fit
+// limits while keeping it readable: every file keeps its headers and every
+// hunk its @@ line, context is cut down to the line either side of a change,
+// and what still does not fit is dropped from the end of each hunk. Each file
+// is shortened to the limits as soon as it has been read, so only one file
+// is held whole. The byte budget is then shared fairly, so small files are
+// kept whole and large ones give way. When anything is left out a notice
+// saying so follows the diff for the model to read.
+func ReadDiff(r io.Reader, limits Limits) (*Diff, error) {
+	d := &Diff{}
+	shorten := func(i, maxLines, maxBytes int) {
+		if omitted := d.files[i].truncate(maxLines, maxBytes); omitted > 0 {
+			d.cut.Lines += omitted
+			if !contains(d.cut.Files, d.files[i].name) {
+				d.cut.Files = append(d.cut.Files, d.files[i].name)
+			}
+		}
+	}
+
+	br := bufio.NewReader(r)
+	var raw strings.Builder
+	for {
+		line, err := br.ReadString('\n')
+		d.size += len(line)
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "diff --git "):
+			if n := len(d.files); n > 0 {
+				shorten(n-1, limits.PerFileMaxLines, limits.MaxBytes)
+			}
+			d.files = append(d.files, fileDiff{name: diffFileName(line), header: []string{line}})
+		case len(d.files) == 0:
+			raw.WriteString(line)
+		default:
+			cur := &d.files[len(d.files)-1]
+			switch {
+			case strings.HasPrefix(line, "@@"):
+				cur.hunks = append(cur.hunks, hunk{header: line})
+			case len(cur.hunks) == 0:
+				cur.header = append(cur.header, line)
+			default:
+				h := &cur.hunks[len(cur.hunks)-1]
+				h.lines = append(h.lines, line)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	d.raw = raw.String()
+	if len(d.files) == 0 {
+		return d, nil
+	}
+	shorten(len(d.files)-1, limits.PerFileMaxLines, limits.MaxBytes)
+
+	if limits.MaxBytes > 0 && totalSize(d.files) > limits.MaxBytes {
+		for i, budget := range shareBudget(d.files, limits.MaxBytes) {
+			if budget >= 0 {
+				shorten(i, 0, budget)
+			}
+		}
+		// Markers and the hunk headers that are always kept can overshoot a
+		// share; take the excess from the largest file.
+		for range 3 {
+			sizes := fileSizes(d.files)
+			largest, total := 0, 0
+			for i, size := range sizes {
+				total += size
+				if size > sizes[largest] {
+					largest = i
+				}
+			}
+			over := total - limits.MaxBytes
+			if over <= 0 {
+				break
+			}
+			shorten(largest, 0, sizes[largest]-over)
+		}
+		// Headers alone can overflow a small budget with many files.
+		sizes := fileSizes(d.files)
+		total := 0
+		for _, size := range sizes {
+			total += size
+		}
+		for len(d.files) > 1 && total > limits.MaxBytes {
+			last := d.files[len(d.files)-1]
+			total -= sizes[len(d.files)-1]
+			d.files = d.files[:len(d.files)-1]
+			d.cut.Dropped = append([]string{last.name}, d.cut.Dropped...)
+		}
+	}
+	if d.cut.Truncated() {
+		d.notes = append(d.notes, "\n"+d.cut.notice())
+	}
+	return d, nil
+}
+
+// Note adds text after the diff, such as a list of files left out of it.
+func (d *Diff) Note(text string) {
+	d.notes = append(d.notes, text)
+}
+
+// Truncation is what ReadDiff left out.
+func (d *Diff) Truncation() Truncation { return d.cut }
+
+// Size is the length of the diff as it was read, before it was shortened.
+func (d *Diff) Size() int { return d.size }
+
+// Len is the number of bytes Reader yields.
+func (d *Diff) Len() int {
+	n := len(d.raw) + totalSize(d.files)
+	for _, note := range d.notes {
+		n += len(note)
+	}
+	return n
+}
+
+// Reader reads the shortened diff and its notes from the start. Each file
+// is written out only as it is reached.
+func (d *Diff) Reader() io.Reader {
+	return &diffReader{d: d, part: -1}
+}
+
+// String is the shortened diff and its notes as one string.
+func (d *Diff) String() string {
+	var b strings.Builder
+	_, _ = io.Copy(&b, d.Reader())
+	return b.String()
+}
+
+// diffReader reads the parts of a Diff in turn: raw, the files, then the
+// notes.
+type diffReader struct {
+	d    *Diff
+	part int
+	cur  strings.Reader
+}
+
+func (r *diffReader) Read(p []byte) (int, error) {
+	for r.cur.Len() == 0 {
+		r.part++
+		switch i := r.part - 1; {
+		case r.part == 0:
+			r.cur.Reset(r.d.raw)
+		case i < len(r.d.files):
+			r.cur.Reset(r.d.files[i].String())
+		case i-len(r.d.files) < len(r.d.notes):
+			r.cur.Reset(r.d.notes[i-len(r.d.files)])
+		default:
+			return 0, io.EOF
+		}
+	}
+	return r.cur.Read(p)
+}
+
+// notice tells the model that it is not seeing the whole change.
+func (t Truncation) notice() string {
+	var parts []string
+	if t.Lines > 0 {
+		parts = append(parts, fmt.Sprintf("%d diff lines were omitted from %s", t.Lines, strings.Join(t.Files, ", ")))
+	}
+	if len(t.Dropped) > 0 {
+		parts = append(parts, "these files also changed but are not shown: "+strings.Join(t.Dropped, ", "))
+	}
+	return "[Note: this diff was shortened to fit the size limit; " + strings.Join(parts, "; ") +
+		". Hunk headers mark where changes were cut. Describe the change as a whole.]\n"
+}
+
+// diffFileName takes the new path from a "diff --git a/x b/y" line.
+func diffFileName(line string) string {
+	line = strings.TrimSpace(strings.TrimPrefix(line, "diff --git "))
+	if i := strings.LastIndex(line, " b/"); i >= 0 {
+		return line[i+3:]
+	}
+	return line
+}
+
+func (f fileDiff) String() string {
+	var b strings.Builder
+	for _, l := range f.header {
+		b.WriteString(l)
+	}
+	for _, h := range f.hunks {
+		b.WriteString(h.header)
+		for _, l := range h.lines {
+			b.WriteString(l)
+		}
+		if h.omitted > 0 {
+			b.WriteString(omittedMarker(h.omitted))
+		}
+	}
+	if f.cutHunks > 0 {
+		b.WriteString(cutHunksMarker(f.cutHunks))
+	}
+	return b.String()
+}
+
+func (f fileDiff) size() int { return len(f.String()) }
+
+func (f fileDiff) lineCount() int {
+	n := 0
+	for _, h := range f.hunks {
+		n += len(h.lines)
+	}
+	return n
+}
+
+// truncate shortens the hunks to at most maxLines lines and maxBytes bytes
+// overall (zero for no limit on either) and returns how many lines it
+// dropped. File headers are always kept, and hunk headers as long as they
+// fit.
+func (f *fileDiff) truncate(maxLines, maxBytes int) int {
+	fits := func() bool {
+		return (maxLines <= 0 || f.lineCount() <= maxLines) && (maxBytes <= 0 || f.size() <= maxBytes)
+	}
+	if fits() {
+		return 0
+	}
+
+	before := f.lineCount()
+	for i := range f.hunks {
+		f.hunks[i].lines = trimContext(f.hunks[i].lines)
+	}
+	if fits() {
+		return before - f.lineCount()
+	}
+
+	lines, bytes := 0, 0
+	for _, l := range f.header {
+		bytes += len(l)
+	}
+	if f.cutHunks > 0 {
+		bytes += len(cutHunksMarker(f.cutHunks))
+	}
+	marker := len(omittedMarker(before))
+	for i := range f.hunks {
+		h := &f.hunks[i]
+		if maxBytes > 0 && i > 0 && bytes+len(h.header)+2*marker > maxBytes {
+			f.cutHunks += len(f.hunks) - i
+			f.hunks = f.hunks[:i]
+			break
+		}
+		bytes += len(h.header)
+		keep := 0
+		for _, l := range h.lines {
+			if (maxLines > 0 && lines+1 > maxLines) || (maxBytes > 0 && bytes+len(l)+marker > maxBytes) {
+				break
+			}
+			lines++
+			bytes += len(l)
+			keep++
+		}
+		h.omitted += len(h.lines) - keep
+		h.lines = h.lines[:keep]
+		if h.omitted > 0 {
+			bytes += marker
+		}
+	}
+	return before - f.lineCount()
+}
+
+func omittedMarker(n int) string {
+	return fmt.Sprintf("[... %d lines omitted]\n", n)
+}
+
+func cutHunksMarker(n int) string {
+	return fmt.Sprintf("[... %d more hunks omitted]\n", n)
+}
+
+// trimContext keeps changed lines and the context line on either side of
+// each change, dropping the rest of the unchanged lines.
+func trimContext(lines []string) []string {
+	changed := func(i int) bool {
+		return i >= 0 && i < len(lines) && (strings.HasPrefix(lines[i], "+") || strings.HasPrefix(lines[i], "-"))
+	}
+	var out []string
+	for i, l := range lines {
+		if changed(i) || changed(i-1) || changed(i+1) || strings.HasPrefix(l, `\`) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// shareBudget splits maxBytes between files: files smaller than an even share
+// keep their size, and what they leave over is shared among the larger ones.
+// Files that already fit get -1.
+func shareBudget(files []fileDiff, maxBytes int) []int {
+	sizes := fileSizes(files)
+	order := make([]int, len(files))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return sizes[order[a]] < sizes[order[b]] })
+
+	budgets := make([]int, len(files))
+	remaining := maxBytes
+	for n, i := range order {
+		share := remaining / (len(files) - n)
+		if size := sizes[i]; size <= share {
+			budgets[i] = -1
+			remaining -= size
+			continue
+		}
+		budgets[i] = share
+		remaining -= share
+	}
+	return budgets
+}
+
+// fileSizes is the size of each file, each laid out once.
+func fileSizes(files []fileDiff) []int {
+	sizes := make([]int, len(files))
+	for i, f := range files {
+		sizes[i] = f.size()
+	}
+	return sizes
+}
+
+func totalSize(files []fileDiff) int {
+	n := 0
+	for _, f := range files {
+		n += f.size()
+	}
+	return n
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
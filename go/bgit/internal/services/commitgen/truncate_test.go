@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// diffOf is the diff of a file name that gains lines lines after a line of
+// context.
+func diffOf(name string, lines int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\nindex 1111111..2222222 100644\n--- a/%s\n+++ b/%s\n", name, name, name, name)
+	fmt.Fprintf(&b, "@@ -1,1 +1,%d @@\n package main\n", lines+1)
+	for i := range lines {
+		fmt.Fprintf(&b, "+var line%d = %d\n", i, i)
+	}
+	return b.String()
+}
+
+func readDiff(t *testing.T, diff string, limits Limits) *Diff {
+	t.Helper()
+	d, err := ReadDiff(strings.NewReader(diff), limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestReadDiffWithinLimits(t *testing.T) {
+	diff := diffOf("a.go", 3) + diffOf("b.go", 2)
+	d := readDiff(t, diff, Limits{MaxBytes: 10_000, PerFileMaxLines: 100})
+	if d.Truncation().Truncated() {
+		t.Errorf("a diff within the limits was truncated: %+v", d.Truncation())
+	}
+	if got := d.String(); got != diff {
+		t.Errorf("a diff within the limits changed:\n%s", got)
+	}
+	if d.Size() != len(diff) || d.Len() != len(diff) {
+		t.Errorf("Size = %d, Len = %d, want both %d", d.Size(), d.Len(), len(diff))
+	}
+}
+
+func TestReadDiffPerFileMaxLines(t *testing.T) {
+	diff := diffOf("big.go", 50) + diffOf("small.go", 2)
+	d := readDiff(t, diff, Limits{PerFileMaxLines: 10})
+
+	cut := d.Truncation()
+	// The context line goes with the first change; of the 51 lines 10 are
+	// kept.
+	if cut.Lines != 41 || len(cut.Files) != 1 || cut.Files[0] != "big.go" || len(cut.Dropped) != 0 {
+		t.Errorf("Truncation = %+v, want 41 lines cut from big.go only", cut)
+	}
+	got := d.String()
+	for _, want := range []string{
+		"+++ b/big.go\n@@ -1,1 +1,51 @@\n",
+		"+var line8 = 8\n[... 41 lines omitted]\n",
+		diffOf("small.go", 2),
+		"[Note: this diff was shortened to fit the size limit; 41 diff lines were omitted from big.go. ",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("the truncated diff does not contain %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "line9 ") {
+		t.Errorf("the truncated diff kept more than 10 lines of big.go")
+	}
+	if !strings.HasSuffix(got, "Describe the change as a whole.]\n") {
+		t.Errorf("the truncation notice does not end the diff:\n%s", got)
+	}
+}
+
+func TestReadDiffMaxBytes(t *testing.T) {
+	small := diffOf("small.go", 2)
+	diff := diffOf("big.go", 400) + small + diffOf("medium.go", 40)
+	const maxBytes = 2000
+	d := readDiff(t, diff, Limits{MaxBytes: maxBytes})
+
+	cut := d.Truncation()
+	if !cut.Truncated() || len(cut.Dropped) != 0 {
+		t.Fatalf("Truncation = %+v, want lines cut and no file dropped", cut)
+	}
+	got := d.String()
+	notice := got[strings.Index(got, "\n[Note:"):]
+	if size := len(got) - len(notice); size > maxBytes {
+		t.Errorf("the diff is %d bytes without the notice, over the %d allowed", size, maxBytes)
+	}
+	// The budget is shared so the small file is kept whole and the big
+	// one gives way.
+	if !strings.Contains(got, small) {
+		t.Errorf("the small file was not kept whole:\n%s", got)
+	}
+	for _, name := range []string{"big.go", "medium.go"} {
+		if !strings.Contains(got, "+++ b/"+name+"\n@@ ") {
+			t.Errorf("%s lost its headers", name)
+		}
+	}
+	if cut.Files[0] != "big.go" {
+		t.Errorf("Truncation.Files = %v, want big.go first", cut.Files)
+	}
+	if !strings.Contains(got, "lines omitted]\n") {
+		t.Errorf("no marker says where lines were cut:\n%s", got)
+	}
+	if d.Len() != len(got) {
+		t.Errorf("Len = %d, want the %d bytes String returns", d.Len(), len(got))
+	}
+}
+
+func TestReadDiffDropsFiles(t *testing.T) {
+	var diff strings.Builder
+	for i := range 20 {
+		diff.WriteString(diffOf(fmt.Sprintf("file%02d.go", i), 5))
+	}
+	d := readDiff(t, diff.String(), Limits{MaxBytes: 600})
+
+	cut := d.Truncation()
+	if len(cut.Dropped) == 0 {
+		t.Fatalf("Truncation = %+v, want files dropped when their headers do not fit", cut)
+	}
+	if last := cut.Dropped[len(cut.Dropped)-1]; last != "file19.go" {
+		t.Errorf("the last file dropped is %s, want the files dropped from the end", last)
+	}
+	got := d.String()
+	if !strings.Contains(got, "these files also changed but are not shown: "+strings.Join(cut.Dropped, ", ")) {
+		t.Errorf("the notice does not name the dropped files:\n%s", got)
+	}
+	if strings.Contains(got, "diff --git a/file19.go") {
+		t.Errorf("a dropped file is still in the diff")
+	}
+}
+
+func TestDiffReader(t *testing.T) {
+	d := readDiff(t, "preamble\n"+diffOf("big.go", 100), Limits{MaxBytes: 800})
+	d.Note("\nalso: vendor/ was left out\n")
+
+	var b strings.Builder
+	// A small buffer reads it across the parts.
+	buf := make([]byte, 7)
+	r := d.Reader()
+	for {
+		n, err := r.Read(buf)
+		b.Write(buf[:n])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := b.String(), d.String(); got != want {
+		t.Errorf("Reader read\n%s\nwant\n%s", got, want)
+	}
+	if !strings.HasPrefix(b.String(), "preamble\n") || !strings.HasSuffix(b.String(), "also: vendor/ was left out\n") {
+		t.Errorf("Reader lost what comes before the files or the notes after them:\n%s", b.String())
+	}
+}
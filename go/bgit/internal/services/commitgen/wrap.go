@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// listItem matches the marker of a Markdown list item: "- ", "* ", "+ ",
+// "1. " or "1) ", possibly indented.
+var listItem = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+`)
+
+// WrapBody re-wraps the body of message so no line is longer than width,
+// leaving the subject line alone. Paragraphs are refilled; list items are
+// refilled with their continuation lines indented under the item text.
+// Fenced and indented code, quotes, tables and the trailer block at the end
+// are kept as written, and words longer than width (URLs, paths) are not
+// broken. A width of 0 or less returns message unchanged.
+func WrapBody(message string, width int) string {
+	if width <= 0 {
+		return message
+	}
+	subject, body, ok := strings.Cut(message, "\n")
+	if !ok {
+		return message
+	}
+
+	lines := strings.Split(body, "\n")
+	// The trailer block is the last paragraph when every line of it is a
+	// trailer; it is left out of the refilling.
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	trailers := end
+	for trailers > 0 && strings.TrimSpace(lines[trailers-1]) != "" {
+		trailers--
+	}
+	if trailers == 0 || !allTrailers(strings.Join(lines[trailers:end], "\n")) {
+		trailers = end
+	}
+
+	var (
+		out []string
+		// prefix and words are the paragraph or list item being filled;
+		// indent is how its continuation lines start.
+		prefix, indent string
+		words          []string
+		fence          string
+	)
+	flush := func() {
+		if prefix != "" || len(words) > 0 {
+			out = append(out, fill(prefix, indent, words, width)...)
+		}
+		prefix, indent, words = "", "", nil
+	}
+
+	for i, line := range lines[:trailers] {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			out = append(out, line)
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flush()
+			fence = trimmed[:3]
+			out = append(out, line)
+		case trimmed == "":
+			flush()
+			out = append(out, "")
+		case isIndentedCode(line) && (i == 0 || strings.TrimSpace(lines[i-1]) == "" || isIndentedCode(lines[i-1])) && len(words) == 0:
+			out = append(out, line)
+		case strings.HasPrefix(trimmed, ">") || strings.HasPrefix(trimmed, "|"):
+			flush()
+			out = append(out, line)
+		case listItem.MatchString(line):
+			flush()
+			marker := listItem.FindString(line)
+			prefix, indent = marker, strings.Repeat(" ", utf8.RuneCountInString(marker))
+			words = strings.Fields(line[len(marker):])
+		default:
+			words = append(words, strings.Fields(line)...)
+		}
+	}
+	flush()
+	out = append(out, lines[trailers:]...)
+	return subject + "\n" + strings.Join(out, "\n")
+}
+
+// isIndentedCode reports whether line is indented as a Markdown code block.
+func isIndentedCode(line string) bool {
+	return strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "    ")
+}
+
+// fill lays words out after prefix in lines of at most width runes, starting
+// each line after the first with indent.
+func fill(prefix, indent string, words []string, width int) []string {
+	var lines []string
+	line, n := prefix, utf8.RuneCountInString(prefix)
+	empty := true
+	for _, w := range words {
+		wn := utf8.RuneCountInString(w)
+		if !empty && n+1+wn > width {
+			lines = append(lines, line)
+			line, n, empty = indent, utf8.RuneCountInString(indent), true
+		}
+		if !empty {
+			line += " "
+			n++
+		}
+		line += w
+		n += wn
+		empty = false
+	}
+	return append(lines, strings.TrimRight(line, " "))
+}
@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWrapBody(t *testing.T) {
+	const url = "https://example.com/a/very/long/path/that/goes/past/the/width"
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{
+			name: "paragraph refilled",
+			in: []string{"A subject line that is longer than the width", "",
+				"The quick brown fox", "jumps over the lazy dog and keeps running."},
+			want: []string{"A subject line that is longer than the width", "",
+				"The quick brown fox jumps over", "the lazy dog and keeps", "running."},
+		},
+		{
+			name: "bullet list",
+			in: []string{"Subject", "",
+				"- first item is long enough that it has to wrap around",
+				"- second", "  item",
+				"* third"},
+			want: []string{"Subject", "",
+				"- first item is long enough", "  that it has to wrap around",
+				"- second item",
+				"* third"},
+		},
+		{
+			name: "numbered list",
+			in: []string{"Subject", "",
+				"Steps:", "",
+				"1. open the settings and pick a theme", "10) save"},
+			want: []string{"Subject", "",
+				"Steps:", "",
+				"1. open the settings and pick", "   a theme", "10) save"},
+		},
+		{
+			name: "indented code",
+			in: []string{"Subject", "",
+				"Run it like this:", "",
+				"    bgit commit --date 'yesterday 14:00' --allow-backdate",
+				"\tgo test ./... -run TestWrapBody -count=1",
+				"", "and it works."},
+			want: []string{"Subject", "",
+				"Run it like this:", "",
+				"    bgit commit --date 'yesterday 14:00' --allow-backdate",
+				"\tgo test ./... -run TestWrapBody -count=1",
+				"", "and it works."},
+		},
+		{
+			// Indented straight after text, it continues the paragraph.
+			name: "indented continuation",
+			in:   []string{"Subject", "", "A paragraph", "    indented too"},
+			want: []string{"Subject", "", "A paragraph indented too"},
+		},
+		{
+			name: "fenced code",
+			in: []string{"Subject", "",
+				"```go",
+				"if err := run(); err != nil { return fmt.Errorf(\"run: %w\", err) }",
+				"",
+				"    still code",
+				"```",
+				"Text after the fence is refilled as usual."},
+			want: []string{"Subject", "",
+				"```go",
+				"if err := run(); err != nil { return fmt.Errorf(\"run: %w\", err) }",
+				"",
+				"    still code",
+				"```",
+				"Text after the fence is", "refilled as usual."},
+		},
+		{
+			name: "long url",
+			in:   []string{"Subject", "", "See " + url + " for details."},
+			want: []string{"Subject", "", "See", url, "for details."},
+		},
+		{
+			name: "long url in a list item",
+			in:   []string{"Subject", "", "- docs at " + url},
+			want: []string{"Subject", "", "- docs at", "  " + url},
+		},
+		{
+			name: "quotes and tables",
+			in: []string{"Subject", "",
+				"> a quoted line that is much longer than the width allows",
+				"| column one | column two | column three |"},
+			want: []string{"Subject", "",
+				"> a quoted line that is much longer than the width allows",
+				"| column one | column two | column three |"},
+		},
+		{
+			name: "trailers",
+			in: []string{"Subject", "",
+				"Some body text.", "",
+				"Signed-off-by: Alice Example <alice@example.com>",
+				"Refs: #1234"},
+			want: []string{"Subject", "",
+				"Some body text.", "",
+				"Signed-off-by: Alice Example <alice@example.com>",
+				"Refs: #1234"},
+		},
+		{
+			name: "subject only",
+			in:   []string{"A subject line that is longer than the width"},
+			want: []string{"A subject line that is longer than the width"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WrapBody(strings.Join(tt.in, "\n"), 30)
+			if want := strings.Join(tt.want, "\n"); got != want {
+				t.Errorf("WrapBody =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestWrapBodyNoWidth(t *testing.T) {
+	message := "Subject\n\nA body line that would otherwise be refilled at some width."
+	if got := WrapBody(message, 0); got != message {
+		t.Errorf("WrapBody with width 0 changed the message to %q", got)
+	}
+}
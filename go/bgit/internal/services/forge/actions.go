@@ -0,0 +1,230 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WorkflowRun is one run of a CI workflow (GitHub Actions).
+type WorkflowRun struct {
+	ID       int64
+	Workflow string
+	// Title is the commit message headline or pull request title the run
+	// was started for.
+	Title   string
+	Event   string
+	Branch  string
+	SHA     string
+	Attempt int
+	// Status is queued, in_progress, completed and so on; Conclusion says
+	// how a completed run ended: success, failure, cancelled, skipped...
+	Status     string
+	Conclusion string
+	URL        string
+	Created    time.Time
+	Updated    time.Time
+}
+
+// State is the conclusion of a completed run, else its status.
+func (r WorkflowRun) State() string { return state(r.Status, r.Conclusion) }
+
+// Job is one job of a workflow run.
+type Job struct {
+	ID         int64
+	RunID      int64
+	Name       string
+	Status     string
+	Conclusion string
+	URL        string
+	Started    time.Time
+	Completed  time.Time
+	Steps      []Step
+}
+
+// State is the conclusion of a completed job, else its status.
+func (j Job) State() string { return state(j.Status, j.Conclusion) }
+
+// Step is one step of a job.
+type Step struct {
+	Number     int
+	Name       string
+	Status     string
+	Conclusion string
+}
+
+// State is the conclusion of a completed step, else its status.
+func (s Step) State() string { return state(s.Status, s.Conclusion) }
+
+func state(status, conclusion string) string {
+	if status == "completed" && conclusion != "" {
+		return conclusion
+	}
+	return status
+}
+
+// Failed reports whether a run, job or step state means it went wrong.
+func Failed(state string) bool {
+	switch state {
+	case "failure", "timed_out", "startup_failure":
+		return true
+	}
+	return false
+}
+
+// RunFilter narrows the workflow runs listed. Empty fields do not filter.
+type RunFilter struct {
+	Branch string
+	SHA    string
+	// Limit caps the number of runs returned, newest first (default 30).
+	Limit int
+}
+
+// WorkflowRuns lists the workflow runs of the repository, newest first.
+func (g *GitHub) WorkflowRuns(ctx context.Context, filter RunFilter) ([]WorkflowRun, error) {
+	q := url.Values{}
+	if filter.Branch != "" {
+		q.Set("branch", filter.Branch)
+	}
+	if filter.SHA != "" {
+		q.Set("head_sha", filter.SHA)
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 30
+	}
+	q.Set("per_page", fmt.Sprint(min(limit, 100)))
+
+	var resp struct {
+		Runs []struct {
+			ID           int64     `json:"id"`
+			Name         string    `json:"name"`
+			DisplayTitle string    `json:"display_title"`
+			Event        string    `json:"event"`
+			HeadBranch   string    `json:"head_branch"`
+			HeadSHA      string    `json:"head_sha"`
+			RunAttempt   int       `json:"run_attempt"`
+			Status       string    `json:"status"`
+			Conclusion   string    `json:"conclusion"`
+			HTMLURL      string    `json:"html_url"`
+			CreatedAt    time.Time `json:"created_at"`
+			UpdatedAt    time.Time `json:"updated_at"`
+		} `json:"workflow_runs"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/actions/runs?%s", g.repo.Owner, g.repo.Name, q.Encode())
+	if err := g.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	runs := make([]WorkflowRun, 0, len(resp.Runs))
+	for _, r := range resp.Runs {
+		runs = append(runs, WorkflowRun{
+			ID:         r.ID,
+			Workflow:   r.Name,
+			Title:      r.DisplayTitle,
+			Event:      r.Event,
+			Branch:     r.HeadBranch,
+			SHA:        r.HeadSHA,
+			Attempt:    r.RunAttempt,
+			Status:     r.Status,
+			Conclusion: r.Conclusion,
+			URL:        r.HTMLURL,
+			Created:    r.CreatedAt,
+			Updated:    r.UpdatedAt,
+		})
+	}
+	if len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return runs, nil
+}
+
+// apiJob is a job as the API returns it.
+type apiJob struct {
+	ID          int64     `json:"id"`
+	RunID       int64     `json:"run_id"`
+	Name        string    `json:"name"`
+	Status      string    `json:"status"`
+	Conclusion  string    `json:"conclusion"`
+	HTMLURL     string    `json:"html_url"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+	Steps       []struct {
+		Number     int    `json:"number"`
+		Name       string `json:"name"`
+		Status     string `json:"status"`
+		Conclusion string `json:"conclusion"`
+	} `json:"steps"`
+}
+
+func (j apiJob) job() Job {
+	job := Job{
+		ID:         j.ID,
+		RunID:      j.RunID,
+		Name:       j.Name,
+		Status:     j.Status,
+		Conclusion: j.Conclusion,
+		URL:        j.HTMLURL,
+		Started:    j.StartedAt,
+		Completed:  j.CompletedAt,
+	}
+	for _, s := range j.Steps {
+		job.Steps = append(job.Steps, Step{Number: s.Number, Name: s.Name, Status: s.Status, Conclusion: s.Conclusion})
+	}
+	return job
+}
+
+// RunJobs lists the jobs of the latest attempt of a workflow run.
+func (g *GitHub) RunJobs(ctx context.Context, runID int64) ([]Job, error) {
+	var jobs []Job
+	for page := 1; ; page++ {
+		var resp struct {
+			Jobs []apiJob `json:"jobs"`
+		}
+		path := fmt.Sprintf("/repos/%s/%s/actions/runs/%d/jobs?per_page=100&page=%d", g.repo.Owner, g.repo.Name, runID, page)
+		if err := g.get(ctx, path, &resp); err != nil {
+			return nil, err
+		}
+		for _, j := range resp.Jobs {
+			jobs = append(jobs, j.job())
+		}
+		if len(resp.Jobs) < 100 {
+			return jobs, nil
+		}
+	}
+}
+
+// Job fetches one job by its ID.
+func (g *GitHub) Job(ctx context.Context, jobID int64) (Job, error) {
+	var j apiJob
+	path := fmt.Sprintf("/repos/%s/%s/actions/jobs/%d", g.repo.Owner, g.repo.Name, jobID)
+	if err := g.get(ctx, path, &j); err != nil {
+		return Job{}, err
+	}
+	return j.job(), nil
+}
+
+// JobLog opens the plain-text log of a finished job. GitHub only serves the
+// log once the job has completed. The caller closes the reader.
+func (g *GitHub) JobLog(ctx context.Context, jobID int64) (io.ReadCloser, error) {
+	// The API answers with a redirect to short-lived storage, which the
+	// client follows; logs can take longer to download than an API call, so
+	// the deadline is left to ctx.
+	path := fmt.Sprintf("/repos/%s/%s/actions/jobs/%d/logs", g.repo.Owner, g.repo.Name, jobID)
+	logs := *g
+	logs.client = &http.Client{Transport: g.client.Transport}
+	resp, err := logs.send(ctx, http.MethodGet, g.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// RerunFailedJobs starts a new attempt of a run for its failed jobs and the
+// jobs that depend on them. It needs a token with write access.
+func (g *GitHub) RerunFailedJobs(ctx context.Context, runID int64) error {
+	path := fmt.Sprintf("/repos/%s/%s/actions/runs/%d/rerun-failed-jobs", g.repo.Owner, g.repo.Name, runID)
+	return g.call(ctx, http.MethodPost, path, nil, nil)
+}
@@ -0,0 +1,149 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Check is one check reported on a commit: a check run (GitHub Actions and
+// other apps) or a commit status set by an external CI service.
+type Check struct {
+	Name string
+	// Status and Conclusion are as for workflow runs. Commit statuses are
+	// mapped onto them: pending is in_progress, error is failure.
+	Status     string
+	Conclusion string
+	URL        string
+	Started    time.Time
+	Completed  time.Time
+}
+
+// State is the conclusion of a completed check, else its status.
+func (c Check) State() string { return state(c.Status, c.Conclusion) }
+
+// Passed reports whether a completed check state lets a change through.
+// Skipped and neutral checks do not hold a merge up on GitHub either.
+func Passed(state string) bool {
+	switch state {
+	case "success", "skipped", "neutral":
+		return true
+	}
+	return false
+}
+
+// CommitChecks lists the checks on commit sha, check runs first, then
+// commit statuses. A check that ran more than once is listed by its latest
+// run only.
+func (g *GitHub) CommitChecks(ctx context.Context, sha string) ([]Check, error) {
+	var checks []Check
+	seen := map[string]bool{}
+	for page := 1; ; page++ {
+		var resp struct {
+			CheckRuns []struct {
+				Name        string    `json:"name"`
+				Status      string    `json:"status"`
+				Conclusion  string    `json:"conclusion"`
+				HTMLURL     string    `json:"html_url"`
+				StartedAt   time.Time `json:"started_at"`
+				CompletedAt time.Time `json:"completed_at"`
+			} `json:"check_runs"`
+		}
+		path := fmt.Sprintf("/repos/%s/%s/commits/%s/check-runs?filter=latest&per_page=100&page=%d", g.repo.Owner, g.repo.Name, sha, page)
+		if err := g.get(ctx, path, &resp); err != nil {
+			return nil, err
+		}
+		for _, r := range resp.CheckRuns {
+			if seen[r.Name] {
+				continue
+			}
+			seen[r.Name] = true
+			checks = append(checks, Check{
+				Name:       r.Name,
+				Status:     r.Status,
+				Conclusion: r.Conclusion,
+				URL:        r.HTMLURL,
+				Started:    r.StartedAt,
+				Completed:  r.CompletedAt,
+			})
+		}
+		if len(resp.CheckRuns) < 100 {
+			break
+		}
+	}
+
+	// The combined status lists the latest status of each context.
+	var combined struct {
+		Statuses []struct {
+			Context   string    `json:"context"`
+			State     string    `json:"state"`
+			TargetURL string    `json:"target_url"`
+			CreatedAt time.Time `json:"created_at"`
+			UpdatedAt time.Time `json:"updated_at"`
+		} `json:"statuses"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/commits/%s/status?per_page=100", g.repo.Owner, g.repo.Name, sha)
+	if err := g.get(ctx, path, &combined); err != nil {
+		return nil, err
+	}
+	for _, s := range combined.Statuses {
+		c := Check{Name: s.Context, URL: s.TargetURL, Started: s.CreatedAt, Status: "completed"}
+		switch s.State {
+		case "pending":
+			c.Status = "in_progress"
+		case "error", "failure":
+			c.Conclusion, c.Completed = "failure", s.UpdatedAt
+		default:
+			c.Conclusion, c.Completed = s.State, s.UpdatedAt
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
+
+// PullRequestFor finds the open pull request from branch, if any.
+func (g *GitHub) PullRequestFor(ctx context.Context, branch string) (PullRequest, bool, error) {
+	prs, err := g.OpenPullRequests(ctx)
+	if err != nil {
+		return PullRequest{}, false, err
+	}
+	for _, pr := range prs {
+		if pr.Head == branch {
+			return pr, true, nil
+		}
+	}
+	return PullRequest{}, false, nil
+}
+
+// EnableAutoMerge turns on auto-merge for pr, so GitHub merges it with method
+// (merge, squash or rebase) once its required reviews and checks pass. Only
+// the GraphQL API can do this.
+func (g *GitHub) EnableAutoMerge(ctx context.Context, pr PullRequest, method string) error {
+	if pr.nodeID == "" {
+		return ErrForgeCallFailed{Code: 0, Message: fmt.Sprintf("pull request #%d has no node ID", pr.Number)}
+	}
+	body := map[string]any{
+		"query": `mutation($id: ID!, $method: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $method}) { clientMutationId }
+}`,
+		"variables": map[string]any{"id": pr.nodeID, "method": strings.ToUpper(method)},
+	}
+	var resp struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	// GraphQL lives beside the REST API: /graphql on api.github.com,
+	// /api/graphql on Enterprise servers.
+	endpoint := strings.TrimSuffix(g.baseURL, "/v3") + "/graphql"
+	if err := g.callURL(ctx, http.MethodPost, endpoint, body, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		// GraphQL reports failures with a 200.
+		return ErrForgeCallFailed{Code: http.StatusOK, Message: resp.Errors[0].Message}
+	}
+	return nil
+}
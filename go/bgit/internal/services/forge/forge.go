@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrUnsupportedForge is returned for remotes hosted somewhere bgit cannot
+// talk to yet.
+var ErrUnsupportedForge = errors.New("unsupported forge")
+
+// ErrForgeCallFailed is a failed request to the forge API.
+type ErrForgeCallFailed struct {
+	Code    int
+	Message string
+}
+
+func (e ErrForgeCallFailed) Error() string {
+	return fmt.Sprintf("forge call failed: %d %s", e.Code, e.Message)
+}
+
+// Repo identifies a repository on a forge.
+type Repo struct {
+	Host  string
+	Owner string
+	Name  string
+}
+
+func (r Repo) String() string { return r.Host + "/" + r.Owner + "/" + r.Name }
+
+// ParseRemoteURL works out the forge repository a git remote points at. It
+// understands the scp-like SSH form (git@host:owner/name.git) as well as
+// https://, ssh:// and git:// URLs.
+func ParseRemoteURL(remote string) (Repo, error) {
+	var host, path string
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if at, rest, ok := strings.Cut(remote, ":"); ok && !strings.Contains(at, "/") {
+		host, path = at, rest
+		if i := strings.LastIndex(host, "@"); i >= 0 {
+			host = host[i+1:]
+		}
+	} else {
+		return Repo{}, fmt.Errorf("%w: cannot parse remote URL %q", ErrUnsupportedForge, remote)
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(path, ".git"), "/"), "/")
+	if len(parts) < 2 {
+		return Repo{}, fmt.Errorf("%w: %q does not name an owner and repository", ErrUnsupportedForge, remote)
+	}
+	return Repo{
+		Host:  strings.ToLower(host),
+		Owner: strings.Join(parts[:len(parts)-1], "/"),
+		Name:  parts[len(parts)-1],
+	}, nil
+}
+
+// PullRequest is an open pull (or merge) request.
+type PullRequest struct {
+	Number int
+	Title  string
+	// Head is the name of the branch the changes come from.
+	Head  string
+	URL   string
+	Draft bool
+
+	nodeID string
+}
+
+// Open returns a client for the forge hosting remoteURL. Only GitHub (and
+// GitHub Enterprise servers, recognized by "github" in the host name) is
+// supported so far.
+func Open(remoteURL string) (*GitHub, error) {
+	repo, err := ParseRemoteURL(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(repo.Host, "github") {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedForge, repo.Host)
+	}
+	return NewGitHub(repo, GitHubToken()), nil
+}
+
+// OpenGitLab returns a client for the GitLab project remoteURL points at,
+// on gitlab.com or a self-managed server, recognized by "gitlab" in the
+// host name.
+func OpenGitLab(remoteURL string) (*GitLab, error) {
+	repo, err := ParseRemoteURL(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(repo.Host, "gitlab") {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedForge, repo.Host)
+	}
+	return NewGitLab(repo, GitLabToken()), nil
+}
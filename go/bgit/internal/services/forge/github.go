@@ -0,0 +1,173 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// GitHub talks to the GitHub REST API for one repository.
+type GitHub struct {
+	repo    Repo
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+// NewGitHub returns a client for repo. token may be empty, in which case
+// only public repositories can be read, under a low rate limit. Hosts other
+// than github.com are treated as GitHub Enterprise servers.
+func NewGitHub(repo Repo, token string) *GitHub {
+	base := "https://api.github.com"
+	if repo.Host != "github.com" {
+		base = "https://" + repo.Host + "/api/v3"
+	}
+	return &GitHub{repo: repo, token: token, baseURL: base, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// GitHubToken finds an API token in the environment, preferring
+// GITHUB_TOKEN as the gh CLI does.
+func GitHubToken() string {
+	for _, name := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// OpenPullRequests lists the open pull requests of the repository.
+func (g *GitHub) OpenPullRequests(ctx context.Context) ([]PullRequest, error) {
+	var prs []PullRequest
+	for page := 1; ; page++ {
+		var batch []struct {
+			Number  int    `json:"number"`
+			Title   string `json:"title"`
+			HTMLURL string `json:"html_url"`
+			Draft   bool   `json:"draft"`
+			NodeID  string `json:"node_id"`
+			Head    struct {
+				Ref  string `json:"ref"`
+				Repo *struct {
+					FullName string `json:"full_name"`
+				} `json:"repo"`
+			} `json:"head"`
+		}
+		path := fmt.Sprintf("/repos/%s/%s/pulls?state=open&per_page=100&page=%d", g.repo.Owner, g.repo.Name, page)
+		if err := g.get(ctx, path, &batch); err != nil {
+			return nil, err
+		}
+		for _, pr := range batch {
+			// A branch of the same name in a fork is not ours.
+			if pr.Head.Repo != nil && !strings.EqualFold(pr.Head.Repo.FullName, g.repo.Owner+"/"+g.repo.Name) {
+				continue
+			}
+			prs = append(prs, PullRequest{Number: pr.Number, Title: pr.Title, Head: pr.Head.Ref, URL: pr.HTMLURL, Draft: pr.Draft, nodeID: pr.NodeID})
+		}
+		if len(batch) < 100 {
+			return prs, nil
+		}
+	}
+}
+
+// get fetches path from the API and decodes the JSON response into v.
+func (g *GitHub) get(ctx context.Context, path string, v any) error {
+	return g.call(ctx, http.MethodGet, path, nil, v)
+}
+
+// call sends a request to the API, with body encoded as JSON unless it is
+// nil, and decodes the JSON response into v unless that is nil.
+func (g *GitHub) call(ctx context.Context, method, path string, body, v any) error {
+	return g.callURL(ctx, method, g.baseURL+path, body, v)
+}
+
+// callURL is call for an endpoint outside the REST API, such as GraphQL.
+func (g *GitHub) callURL(ctx context.Context, method, url string, body, v any) error {
+	resp, err := g.send(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return ErrForgeCallFailed{Code: resp.StatusCode, Message: "decoding response: " + err.Error()}
+	}
+	return nil
+}
+
+// send sends a request to url, with body encoded as JSON unless it is nil,
+// and returns the response if it succeeded. The caller closes its body.
+func (g *GitHub) send(ctx context.Context, method, url string, body any) (*http.Response, error) {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, ErrForgeCallFailed{Code: 0, Message: err.Error()}
+		}
+		payload = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, payload)
+	if err != nil {
+		return nil, ErrForgeCallFailed{Code: 0, Message: err.Error()}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	g.authorize(req)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, ErrForgeCallFailed{Code: 0, Message: err.Error()}
+	}
+	if err := checkResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// authorize sets the headers every API request carries.
+func (g *GitHub) authorize(req *http.Request) {
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", "bgit")
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+}
+
+// checkResponse turns an unsuccessful response into ErrForgeCallFailed,
+// with the message the forge gave when there is one. GitLab gives the
+// fields it rejects as an object in place of a message, or an error.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+	var failure struct {
+		Message json.RawMessage `json:"message"`
+		Error   string          `json:"error"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&failure)
+	var message string
+	if json.Unmarshal(failure.Message, &message) != nil {
+		message = string(failure.Message)
+	}
+	if message == "" {
+		message = failure.Error
+	}
+	if message == "" {
+		message = resp.Status
+	}
+	return ErrForgeCallFailed{Code: resp.StatusCode, Message: message}
+}
+
+// Repo is the repository the client talks to.
+func (g *GitHub) Repo() Repo { return g.repo }
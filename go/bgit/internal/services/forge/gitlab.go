@@ -0,0 +1,209 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// GitLab talks to the GitLab REST API for one project. It covers releases
+// only; the rest of what bgit asks of a forge is GitHub's.
+type GitLab struct {
+	repo    Repo
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+// NewGitLab returns a client for repo, whose owner is the project's group
+// path. token may be empty, in which case only public projects can be read.
+// Hosts other than gitlab.com are treated as self-managed servers.
+func NewGitLab(repo Repo, token string) *GitLab {
+	return &GitLab{repo: repo, token: token, baseURL: "https://" + repo.Host + "/api/v4", client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// GitLabToken finds an API token in the environment, GITLAB_TOKEN as the
+// glab CLI reads it or else GITLAB_ACCESS_TOKEN.
+func GitLabToken() string {
+	for _, name := range []string{"GITLAB_TOKEN", "GITLAB_ACCESS_TOKEN"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Repo is the project the client talks to.
+func (g *GitLab) Repo() Repo { return g.repo }
+
+// CompareURL is the page comparing two revisions of the project.
+func (g *GitLab) CompareURL(from, to string) string {
+	return fmt.Sprintf("https://%s/%s/%s/-/compare/%s...%s", g.repo.Host, g.repo.Owner, g.repo.Name, from, to)
+}
+
+// project is the path of the project in the API, which takes its full
+// path escaped in place of its ID.
+func (g *GitLab) project() string {
+	return "/projects/" + url.PathEscape(g.repo.Owner+"/"+g.repo.Name)
+}
+
+type gitlabRelease struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	Links   struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+	Assets struct {
+		Links []struct {
+			Name string `json:"name"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+func (r gitlabRelease) release() Release {
+	rel := Release{Tag: r.TagName, Name: r.Name, URL: r.Links.Self}
+	for _, l := range r.Assets.Links {
+		rel.Assets = append(rel.Assets, l.Name)
+	}
+	return rel
+}
+
+// ReleaseByTag fetches the release of tag. ok is false when there is none.
+func (g *GitLab) ReleaseByTag(ctx context.Context, tag string) (rel Release, ok bool, err error) {
+	var r gitlabRelease
+	err = g.call(ctx, http.MethodGet, g.project()+"/releases/"+url.PathEscape(tag), nil, &r)
+	var callErr ErrForgeCallFailed
+	if errors.As(err, &callErr) && callErr.Code == http.StatusNotFound {
+		return Release{}, false, nil
+	}
+	if err != nil {
+		return Release{}, false, err
+	}
+	return r.release(), true, nil
+}
+
+// CreateRelease publishes a release for a tag that is already on the
+// forge. GitLab has no drafts, so a draft is refused; it has no mark for a
+// pre-release either, and Prerelease is left to the version to tell.
+func (g *GitLab) CreateRelease(ctx context.Context, release NewRelease) (Release, error) {
+	if release.Draft {
+		return Release{}, errors.New("GitLab has no draft releases; publish without --draft")
+	}
+	body := map[string]any{
+		"tag_name":    release.Tag,
+		"name":        release.Name,
+		"description": release.Notes,
+	}
+	var r gitlabRelease
+	if err := g.call(ctx, http.MethodPost, g.project()+"/releases", body, &r); err != nil {
+		return Release{}, err
+	}
+	return r.release(), nil
+}
+
+// UploadAsset attaches a file to a release under name: the file is
+// uploaded to the project and the release given a link to it. size is the
+// length of content.
+func (g *GitLab) UploadAsset(ctx context.Context, release Release, name string, content io.Reader, size int64) error {
+	// The form is written as it is sent, so the file is never held whole.
+	pr, pw := io.Pipe()
+	form := multipart.NewWriter(pw)
+	go func() {
+		part, err := form.CreateFormFile("file", name)
+		if err == nil {
+			_, err = io.CopyN(part, content, size)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+g.project()+"/uploads", pr)
+	if err != nil {
+		pr.Close()
+		return ErrForgeCallFailed{Code: 0, Message: err.Error()}
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	g.authorize(req)
+
+	// Uploads take as long as they take; the deadline is left to ctx.
+	resp, err := (&http.Client{Transport: g.client.Transport}).Do(req)
+	if err != nil {
+		pr.Close()
+		return ErrForgeCallFailed{Code: 0, Message: err.Error()}
+	}
+	defer resp.Body.Close()
+	pr.Close()
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	var upload struct {
+		URL      string `json:"url"`
+		FullPath string `json:"full_path"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&upload); err != nil {
+		return ErrForgeCallFailed{Code: resp.StatusCode, Message: "decoding response: " + err.Error()}
+	}
+	// full_path is from the root of the server; servers before it had
+	// only url, from the project's page.
+	link := "https://" + g.repo.Host + upload.FullPath
+	if upload.FullPath == "" {
+		link = fmt.Sprintf("https://%s/%s/%s%s", g.repo.Host, g.repo.Owner, g.repo.Name, upload.URL)
+	}
+
+	path := g.project() + "/releases/" + url.PathEscape(release.Tag) + "/assets/links"
+	return g.call(ctx, http.MethodPost, path, map[string]any{"name": name, "url": link}, nil)
+}
+
+// call sends a request to the API, with body encoded as JSON unless it is
+// nil, and decodes the JSON response into v unless that is nil.
+func (g *GitLab) call(ctx context.Context, method, path string, body, v any) error {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return ErrForgeCallFailed{Code: 0, Message: err.Error()}
+		}
+		payload = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.baseURL+path, payload)
+	if err != nil {
+		return ErrForgeCallFailed{Code: 0, Message: err.Error()}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	g.authorize(req)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return ErrForgeCallFailed{Code: 0, Message: err.Error()}
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return ErrForgeCallFailed{Code: resp.StatusCode, Message: "decoding response: " + err.Error()}
+	}
+	return nil
+}
+
+// authorize sets the headers every API request carries.
+func (g *GitLab) authorize(req *http.Request) {
+	req.Header.Set("User-Agent", "bgit")
+	if g.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", g.token)
+	}
+}
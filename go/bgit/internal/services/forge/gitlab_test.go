@@ -0,0 +1,160 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeGitLab serves the release endpoints of the project group/sub/app,
+// keeping the releases and asset links it is sent.
+func fakeGitLab(t *testing.T) *GitLab {
+	releases := map[string]*gitlabRelease{}
+	const project = "/api/v4/projects/group%2Fsub%2Fapp"
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+project+"/releases/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		rel, ok := releases[r.PathValue("tag")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message":"404 Not Found"}`)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(rel)
+	})
+	mux.HandleFunc("POST "+project+"/releases", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "glpat-test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = io.WriteString(w, `{"message":"401 Unauthorized"}`)
+			return
+		}
+		var body struct {
+			TagName     string `json:"tag_name"`
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.Description == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"error":"description is missing"}`)
+			return
+		}
+		rel := &gitlabRelease{TagName: body.TagName, Name: body.Name}
+		rel.Links.Self = "https://gitlab.example.com/group/sub/app/-/releases/" + body.TagName
+		releases[body.TagName] = rel
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(rel)
+	})
+	mux.HandleFunc("POST "+project+"/uploads", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("the upload has no file: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		if string(data) != "tarball" {
+			t.Errorf("uploaded %q, want the asset's content", data)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"url":"/uploads/abc123/`+header.Filename+`","full_path":"/-/project/42/uploads/abc123/`+header.Filename+`"}`)
+	})
+	mux.HandleFunc("POST "+project+"/releases/{tag}/assets/links", func(w http.ResponseWriter, r *http.Request) {
+		var link struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&link)
+		if want := "https://gitlab.example.com/-/project/42/uploads/abc123/" + link.Name; link.URL != want {
+			t.Errorf("the release links to %s, want %s", link.URL, want)
+		}
+		rel := releases[r.PathValue("tag")]
+		rel.Assets.Links = append(rel.Assets.Links, struct {
+			Name string `json:"name"`
+		}{link.Name})
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	g := NewGitLab(Repo{Host: "gitlab.example.com", Owner: "group/sub", Name: "app"}, "glpat-test")
+	g.baseURL = server.URL + "/api/v4"
+	return g
+}
+
+func TestGitLabRelease(t *testing.T) {
+	ctx := context.Background()
+	g := fakeGitLab(t)
+
+	if _, ok, err := g.ReleaseByTag(ctx, "v1.0.0"); err != nil || ok {
+		t.Fatalf("ReleaseByTag before publishing = %v, %v; want none", ok, err)
+	}
+	rel, err := g.CreateRelease(ctx, NewRelease{Tag: "v1.0.0", Name: "v1.0.0", Notes: "### Features\n\n- a feature"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.URL != "https://gitlab.example.com/group/sub/app/-/releases/v1.0.0" {
+		t.Errorf("the release is at %s", rel.URL)
+	}
+
+	if err := g.UploadAsset(ctx, rel, "app.tar.gz", strings.NewReader("tarball"), int64(len("tarball"))); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err := g.ReleaseByTag(ctx, "v1.0.0")
+	if err != nil || !ok {
+		t.Fatalf("ReleaseByTag after publishing = %v, %v", ok, err)
+	}
+	if len(got.Assets) != 1 || got.Assets[0] != "app.tar.gz" {
+		t.Errorf("the release has the assets %v, want app.tar.gz", got.Assets)
+	}
+}
+
+func TestGitLabReleaseRefused(t *testing.T) {
+	ctx := context.Background()
+	g := fakeGitLab(t)
+
+	if _, err := g.CreateRelease(ctx, NewRelease{Tag: "v1.0.0", Notes: "notes", Draft: true}); err == nil {
+		t.Error("a draft release was published on GitLab, which has none")
+	}
+
+	var callErr ErrForgeCallFailed
+	_, err := g.CreateRelease(ctx, NewRelease{Tag: "v1.0.0"})
+	if !errors.As(err, &callErr) || callErr.Code != http.StatusBadRequest || callErr.Message != "description is missing" {
+		t.Errorf("CreateRelease = %v, want GitLab's error", err)
+	}
+
+	g.token = ""
+	_, err = g.CreateRelease(ctx, NewRelease{Tag: "v1.0.0", Notes: "notes"})
+	if !errors.As(err, &callErr) || callErr.Code != http.StatusUnauthorized {
+		t.Errorf("CreateRelease without a token = %v, want 401", err)
+	}
+}
+
+func TestOpenGitLab(t *testing.T) {
+	for remote, want := range map[string]string{
+		"git@gitlab.com:group/sub/app.git":                    "gitlab.com/group/sub/app",
+		"https://gitlab.example.com/group/app.git":            "gitlab.example.com/group/app",
+		"ssh://git@gitlab.internal:2222/platform/tools/x.git": "gitlab.internal/platform/tools/x",
+	} {
+		g, err := OpenGitLab(remote)
+		if err != nil {
+			t.Errorf("OpenGitLab(%q): %v", remote, err)
+			continue
+		}
+		if got := g.Repo().String(); got != want {
+			t.Errorf("OpenGitLab(%q) is for %s, want %s", remote, got, want)
+		}
+	}
+	if _, err := OpenGitLab("git@github.com:owner/app.git"); !errors.Is(err, ErrUnsupportedForge) {
+		t.Errorf("OpenGitLab of a GitHub remote = %v, want ErrUnsupportedForge", err)
+	}
+	g := NewGitLab(Repo{Host: "gitlab.com", Owner: "group", Name: "app"}, "")
+	if got, want := g.CompareURL("v1.0.0", "v1.1.0"), "https://gitlab.com/group/app/-/compare/v1.0.0...v1.1.0"; got != want {
+		t.Errorf("CompareURL = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Issue is an issue of the repository.
+type Issue struct {
+	Number    int
+	Title     string
+	Body      string
+	State     string // open or closed
+	Author    string
+	Labels    []string
+	Assignees []string
+	Comments  int
+	URL       string
+	Created   time.Time
+	Updated   time.Time
+}
+
+// IssueFilter narrows the issues listed. Empty fields do not filter.
+type IssueFilter struct {
+	// State is open (the default), closed or all.
+	State    string
+	Assignee string
+	Label    string
+	// Limit caps the number of issues returned, most recently updated
+	// first (default 30).
+	Limit int
+}
+
+// apiIssue is an issue as the API returns it.
+type apiIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	State  string `json:"state"`
+	User   struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+	Comments    int       `json:"comments"`
+	HTMLURL     string    `json:"html_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+func (i apiIssue) issue() Issue {
+	issue := Issue{
+		Number:   i.Number,
+		Title:    i.Title,
+		Body:     i.Body,
+		State:    i.State,
+		Author:   i.User.Login,
+		Comments: i.Comments,
+		URL:      i.HTMLURL,
+		Created:  i.CreatedAt,
+		Updated:  i.UpdatedAt,
+	}
+	for _, l := range i.Labels {
+		issue.Labels = append(issue.Labels, l.Name)
+	}
+	for _, a := range i.Assignees {
+		issue.Assignees = append(issue.Assignees, a.Login)
+	}
+	return issue
+}
+
+// Issues lists the issues of the repository, most recently updated first.
+// GitHub counts pull requests as issues; they are left out.
+func (g *GitHub) Issues(ctx context.Context, filter IssueFilter) ([]Issue, error) {
+	q := url.Values{"sort": {"updated"}, "per_page": {"100"}}
+	if filter.State != "" {
+		q.Set("state", filter.State)
+	}
+	if filter.Assignee != "" {
+		q.Set("assignee", filter.Assignee)
+	}
+	if filter.Label != "" {
+		q.Set("labels", filter.Label)
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 30
+	}
+
+	var issues []Issue
+	for page := 1; len(issues) < limit; page++ {
+		q.Set("page", fmt.Sprint(page))
+		var batch []apiIssue
+		path := fmt.Sprintf("/repos/%s/%s/issues?%s", g.repo.Owner, g.repo.Name, q.Encode())
+		if err := g.get(ctx, path, &batch); err != nil {
+			return nil, err
+		}
+		for _, i := range batch {
+			if i.PullRequest == nil && len(issues) < limit {
+				issues = append(issues, i.issue())
+			}
+		}
+		if len(batch) < 100 {
+			break
+		}
+	}
+	return issues, nil
+}
+
+// Issue fetches one issue by number. It fails for a pull request.
+func (g *GitHub) Issue(ctx context.Context, number int) (Issue, error) {
+	var i apiIssue
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d", g.repo.Owner, g.repo.Name, number)
+	if err := g.get(ctx, path, &i); err != nil {
+		return Issue{}, err
+	}
+	if i.PullRequest != nil {
+		return Issue{}, ErrForgeCallFailed{Code: http.StatusNotFound, Message: fmt.Sprintf("#%d is a pull request, not an issue", number)}
+	}
+	return i.issue(), nil
+}
+
+// AssignIssue adds login to the assignees of an issue.
+func (g *GitHub) AssignIssue(ctx context.Context, number int, login string) error {
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d/assignees", g.repo.Owner, g.repo.Name, number)
+	return g.call(ctx, http.MethodPost, path, map[string][]string{"assignees": {login}}, nil)
+}
+
+// CurrentUser returns the login of the user the token belongs to.
+func (g *GitHub) CurrentUser(ctx context.Context) (string, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := g.get(ctx, "/user", &user); err != nil {
+		return "", err
+	}
+	return user.Login, nil
+}
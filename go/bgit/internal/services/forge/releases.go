@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Release is a release published on the forge for a tag.
+type Release struct {
+	ID   int64
+	Tag  string
+	Name string
+	URL  string
+	// Assets lists the names of the files attached to the release.
+	Assets []string
+
+	uploadURL string
+}
+
+// NewRelease describes a release to create.
+type NewRelease struct {
+	Tag        string
+	Name       string
+	Notes      string
+	Draft      bool
+	Prerelease bool
+}
+
+type apiRelease struct {
+	ID        int64  `json:"id"`
+	TagName   string `json:"tag_name"`
+	Name      string `json:"name"`
+	HTMLURL   string `json:"html_url"`
+	UploadURL string `json:"upload_url"`
+	Assets    []struct {
+		Name string `json:"name"`
+	} `json:"assets"`
+}
+
+func (r apiRelease) release() Release {
+	rel := Release{ID: r.ID, Tag: r.TagName, Name: r.Name, URL: r.HTMLURL, uploadURL: r.UploadURL}
+	for _, a := range r.Assets {
+		rel.Assets = append(rel.Assets, a.Name)
+	}
+	return rel
+}
+
+// ReleaseByTag fetches the release of tag. ok is false when there is none.
+func (g *GitHub) ReleaseByTag(ctx context.Context, tag string) (rel Release, ok bool, err error) {
+	var r apiRelease
+	path := fmt.Sprintf("/repos/%s/%s/releases/tags/%s", g.repo.Owner, g.repo.Name, url.PathEscape(tag))
+	err = g.get(ctx, path, &r)
+	var callErr ErrForgeCallFailed
+	if errors.As(err, &callErr) && callErr.Code == http.StatusNotFound {
+		return Release{}, false, nil
+	}
+	if err != nil {
+		return Release{}, false, err
+	}
+	return r.release(), true, nil
+}
+
+// CreateRelease publishes a release for a tag that is already on the forge.
+func (g *GitHub) CreateRelease(ctx context.Context, release NewRelease) (Release, error) {
+	body := map[string]any{
+		"tag_name":   release.Tag,
+		"name":       release.Name,
+		"body":       release.Notes,
+		"draft":      release.Draft,
+		"prerelease": release.Prerelease,
+	}
+	var r apiRelease
+	path := fmt.Sprintf("/repos/%s/%s/releases", g.repo.Owner, g.repo.Name)
+	if err := g.call(ctx, http.MethodPost, path, body, &r); err != nil {
+		return Release{}, err
+	}
+	return r.release(), nil
+}
+
+// CompareURL is the page comparing two revisions of the repository.
+func (g *GitHub) CompareURL(from, to string) string {
+	return fmt.Sprintf("https://%s/%s/%s/compare/%s...%s", g.repo.Host, g.repo.Owner, g.repo.Name, from, to)
+}
+
+// UploadAsset attaches a file to a release under name. size must be the
+// exact length of content.
+func (g *GitHub) UploadAsset(ctx context.Context, release Release, name string, content io.Reader, size int64) error {
+	// upload_url is a URI template: https://uploads.github.com/.../assets{?name,label}
+	target, _, _ := strings.Cut(release.uploadURL, "{")
+	if target == "" {
+		return ErrForgeCallFailed{Code: 0, Message: "the release has no upload URL"}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target+"?name="+url.QueryEscape(name), content)
+	if err != nil {
+		return ErrForgeCallFailed{Code: 0, Message: err.Error()}
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	g.authorize(req)
+
+	// Uploads take as long as they take; the deadline is left to ctx.
+	resp, err := (&http.Client{Transport: g.client.Transport}).Do(req)
+	if err != nil {
+		return ErrForgeCallFailed{Code: 0, Message: err.Error()}
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp)
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing/object"
+)
+
+// CommitFilter picks commits by when and by whom they were authored.
+type CommitFilter struct {
+	// Since and Until bound the author date, both inclusive; zero leaves
+	// that end open.
+	Since time.Time
+	Until time.Time
+	// Author matches the author's name or email, ignoring case, anywhere
+	// in it.
+	Author string
+}
+
+// matches reports whether c passes the filter.
+func (f CommitFilter) matches(c *object.Commit) bool {
+	when := c.Author.When
+	if !f.Since.IsZero() && when.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && when.After(f.Until) {
+		return false
+	}
+	if f.Author == "" {
+		return true
+	}
+	author := strings.ToLower(c.Author.Name + " <" + c.Author.Email + ">")
+	return strings.Contains(author, strings.ToLower(f.Author))
+}
+
+// Commits lists the commits reachable from HEAD that pass filter, newest
+// first.
+func (g *GitCLI) Commits(filter CommitFilter) ([]*object.Commit, error) {
+	head, err := g.repo.Head()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	opts := &git.LogOptions{From: head.Hash(), Order: git.LogOrderCommitterTime}
+	if !filter.Since.IsZero() {
+		// Commits are committed after they are authored, so this only
+		// skips the ones the filter would drop anyway.
+		opts.Since = &filter.Since
+	}
+	iter, err := g.repo.Log(opts)
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	defer iter.Close()
+
+	var commits []*object.Commit
+	err = iter.ForEach(func(c *object.Commit) error {
+		if filter.matches(c) {
+			commits = append(commits, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return commits, nil
+}
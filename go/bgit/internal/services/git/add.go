@@ -0,0 +1,261 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v6/plumbing/format/index"
+)
+
+// ErrNothingToAdd is returned by AddAllFiles when the working tree is clean.
+var ErrNothingToAdd = errors.New("nothing to add: the working tree is clean")
+
+// SkipReason says why a path given to AddFiles was not staged.
+type SkipReason string
+
+const (
+	SkipNotFound  SkipReason = "not found"
+	SkipIgnored   SkipReason = "ignored"
+	SkipUnchanged SkipReason = "unchanged"
+	SkipNoMatch   SkipReason = "matched no files"
+	SkipTracked   SkipReason = "already tracked"
+)
+
+// SkippedPath is a path AddFiles left alone.
+type SkippedPath struct {
+	Path   string
+	Reason SkipReason
+}
+
+// AddResult reports what an add staged and what it left alone.
+type AddResult struct {
+	// Staged lists the files whose changes are now in the index, deletions
+	// included.
+	Staged  []string
+	Skipped []SkippedPath
+}
+
+// AddFiles stages the given paths one by one. A directory stages the changed
+// files under it, and a glob (see MatchGlob) the changed files it matches;
+// either way ignored files are left out. A path that cannot be staged (it
+// does not exist, is ignored, or has nothing new to stage) is reported in
+// Skipped rather than failing the others.
+func (g *GitCLI) AddFiles(files []string) (AddResult, error) {
+	targets, skipped, _, err := g.addTargets(files)
+	if err != nil {
+		return AddResult{}, err
+	}
+	workTree, err := g.repo.Worktree()
+	if err != nil {
+		return AddResult{}, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	for _, p := range targets {
+		// addTargets took the status already; don't let go-git take it again
+		// for every file.
+		if err := workTree.AddWithOptions(&git.AddOptions{Path: p, SkipStatus: true}); err != nil {
+			return AddResult{}, ErrUnknownGitIssue{Message: err.Error()}
+		}
+	}
+	if err := g.clearIntentToAdd(targets); err != nil {
+		return AddResult{}, err
+	}
+	return AddResult{Staged: targets, Skipped: skipped}, nil
+}
+
+// IntentToAdd records the untracked files among paths in the index without
+// their content, as git add -N does: they then show up in the unstaged diff
+// as new files, and are committed only once staged for real. Paths are
+// expanded as by AddFiles; files git already tracks are skipped.
+func (g *GitCLI) IntentToAdd(files []string) (AddResult, error) {
+	targets, skipped, status, err := g.addTargets(files)
+	if err != nil {
+		return AddResult{}, err
+	}
+	var untracked []string
+	for _, p := range targets {
+		if status.File(p).Worktree == git.Untracked {
+			untracked = append(untracked, p)
+		} else {
+			skipped = append(skipped, SkippedPath{Path: p, Reason: SkipTracked})
+		}
+	}
+	if len(untracked) > 0 {
+		// go-git cannot write the flag through its worktree API.
+		cmd := exec.Command("git", append([]string{"add", "--intent-to-add", "--"}, untracked...)...)
+		cmd.Dir = g.path
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return AddResult{}, ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+		}
+	}
+	return AddResult{Staged: untracked, Skipped: skipped}, nil
+}
+
+// StagePatch applies patch, a diff of the working tree against the index
+// such as Diff returns or a part of one, to the index alone, as git apply
+// --cached does; the working tree keeps every change. Either all of the
+// patch applies or none of it does.
+func (g *GitCLI) StagePatch(patch string) error {
+	// go-git cannot apply a patch.
+	cmd := exec.Command("git", "apply", "--cached", "--whitespace=nowarn", "-")
+	cmd.Dir = g.path
+	cmd.Stdin = strings.NewReader(patch)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+	}
+	return nil
+}
+
+// addTargets expands the paths given to an add into the changed files they
+// name, in order and without repeats, and reports the paths that name none.
+func (g *GitCLI) addTargets(files []string) ([]string, []SkippedPath, git.Status, error) {
+	workTree, err := g.repo.Worktree()
+	if err != nil {
+		return nil, nil, nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	status, err := workTree.Status()
+	if err != nil {
+		return nil, nil, nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	idx, err := g.repo.Storer.Index()
+	if err != nil {
+		return nil, nil, nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	ignored, err := ignoreMatcher(workTree)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var (
+		targets []string
+		skipped []SkippedPath
+		seen    = map[string]bool{}
+	)
+	for _, file := range files {
+		p := filepath.ToSlash(filepath.Clean(file))
+		_, lookupErr := idx.Entry(p)
+		tracked := lookupErr == nil
+
+		info, err := os.Lstat(filepath.Join(g.path, p))
+		isDir := err == nil && info.IsDir()
+		var changed []string
+		switch {
+		case errors.Is(err, os.ErrNotExist) && IsGlob(p):
+			changed = changedMatching(status, p)
+			if len(changed) == 0 {
+				reason := SkipNoMatch
+				if indexMatches(idx.Entries, p) {
+					reason = SkipUnchanged
+				}
+				skipped = append(skipped, SkippedPath{Path: file, Reason: reason})
+				continue
+			}
+		case errors.Is(err, os.ErrNotExist) && !tracked:
+			skipped = append(skipped, SkippedPath{Path: file, Reason: SkipNotFound})
+			continue
+		case err != nil && !errors.Is(err, os.ErrNotExist):
+			return nil, nil, nil, ErrUnknownGitIssue{Message: err.Error()}
+		case isDir:
+			changed = changedUnder(status, p)
+		default:
+			if st, ok := status[p]; ok && st.Worktree != git.Unmodified {
+				changed = []string{p}
+			}
+		}
+
+		if len(changed) == 0 {
+			reason := SkipUnchanged
+			if !tracked && ignored.Match(strings.Split(p, "/"), isDir) {
+				reason = SkipIgnored
+			}
+			skipped = append(skipped, SkippedPath{Path: file, Reason: reason})
+			continue
+		}
+		for _, c := range changed {
+			if !seen[c] {
+				seen[c] = true
+				targets = append(targets, c)
+			}
+		}
+	}
+	return targets, skipped, status, nil
+}
+
+// AddAllFiles stages every change in the working tree, untracked files and
+// deletions included, and returns the paths it staged, sorted.
+func (g *GitCLI) AddAllFiles() ([]string, error) {
+	workTree, err := g.repo.Worktree()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	status, err := workTree.Status()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+
+	changed := changedUnder(status, ".")
+	if len(changed) == 0 {
+		return nil, ErrNothingToAdd
+	}
+	if err := workTree.AddWithOptions(&git.AddOptions{All: true, Path: "."}); err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	if err := g.clearIntentToAdd(changed); err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// changedUnder lists the paths under dir ("." for all) with unstaged
+// changes, sorted.
+func changedUnder(status git.Status, dir string) []string {
+	var paths []string
+	for p, s := range status {
+		if s.Worktree == git.Unmodified {
+			continue
+		}
+		if dir == "." || p == dir || strings.HasPrefix(p, dir+"/") {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// changedMatching lists the paths with unstaged changes that pattern
+// matches, sorted.
+func changedMatching(status git.Status, pattern string) []string {
+	var paths []string
+	for _, p := range changedUnder(status, ".") {
+		if MatchGlob(pattern, p) {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// indexMatches reports whether pattern matches any file in the index.
+func indexMatches(entries []*index.Entry, pattern string) bool {
+	for _, e := range entries {
+		if MatchGlob(pattern, e.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoreMatcher matches paths against the repository's .gitignore files and
+// excludes.
+func ignoreMatcher(workTree *git.Worktree) (gitignore.Matcher, error) {
+	patterns, err := gitignore.ReadPatterns(workTree.Filesystem, nil)
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return gitignore.NewMatcher(append(patterns, workTree.Excludes...)), nil
+}
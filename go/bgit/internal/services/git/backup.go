@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// IndexBackup is a copy of the index taken before an operation that
+// rewrites it, so an interrupted add or commit can put the staging area back
+// exactly as it was.
+type IndexBackup struct {
+	indexPath  string
+	backupPath string
+	existed    bool
+}
+
+// BackupIndex copies the current index aside. A repository without an index
+// yet (fresh init) is recorded as such and restored by deleting the index.
+func (g *GitCLI) BackupIndex() (*IndexBackup, error) {
+	// A linked worktree has an index of its own, away from .git (a file
+	// there).
+	indexPath, err := g.GitPath("index")
+	if err != nil {
+		return nil, err
+	}
+	b := &IndexBackup{
+		indexPath:  indexPath,
+		backupPath: indexPath + ".bgit-backup",
+	}
+
+	src, err := os.Open(indexPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	defer src.Close()
+
+	if err := copyToFile(b.backupPath, src); err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	b.existed = true
+	return b, nil
+}
+
+// Restore puts the saved index back in place.
+func (b *IndexBackup) Restore() error {
+	if !b.existed {
+		if err := os.Remove(b.indexPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return os.Rename(b.backupPath, b.indexPath)
+}
+
+// Discard deletes the backup once the operation has completed.
+func (b *IndexBackup) Discard() error {
+	if !b.existed {
+		return nil
+	}
+	if err := os.Remove(b.backupPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func copyToFile(path string, r io.Reader) error {
+	dst, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, r); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
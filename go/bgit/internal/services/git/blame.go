@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+)
+
+// BlameLine is a line of a file with the commit that last changed it.
+type BlameLine struct {
+	Commit plumbing.Hash
+	Author string
+	Email  string
+	// When is when the commit was authored.
+	When time.Time
+	// Subject is the first line of the commit's message.
+	Subject string
+	Text    string
+}
+
+// Blame finds the commit that last changed each line of file as it is in c,
+// the way git blame does.
+func (g *GitCLI) Blame(c *object.Commit, file string) ([]BlameLine, error) {
+	file = path.Clean(file)
+	f, err := c.File(file)
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: fmt.Sprintf("%s is not in %s", file, c.Hash.String()[:7])}
+	}
+	if binary, err := f.IsBinary(); err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	} else if binary {
+		return nil, ErrUnknownGitIssue{Message: fmt.Sprintf("%s is a binary file", file)}
+	}
+
+	result, err := git.Blame(c, file)
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	// Many lines share a commit; its subject is looked up once.
+	subjects := map[plumbing.Hash]string{}
+	lines := make([]BlameLine, 0, len(result.Lines))
+	for _, l := range result.Lines {
+		subject, ok := subjects[l.Hash]
+		if !ok {
+			if commit, err := g.repo.CommitObject(l.Hash); err == nil {
+				subject = strings.SplitN(strings.TrimSpace(commit.Message), "\n", 2)[0]
+			}
+			subjects[l.Hash] = subject
+		}
+		lines = append(lines, BlameLine{
+			Commit:  l.Hash,
+			Author:  l.AuthorName,
+			Email:   l.Author,
+			When:    l.Date,
+			Subject: subject,
+			Text:    l.Text,
+		})
+	}
+	return lines, nil
+}
@@ -0,0 +1,479 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/config"
+	"github.com/go-git/go-git/v6/plumbing"
+)
+
+// BranchInfo describes a local branch relative to a base branch.
+type BranchInfo struct {
+	Name    string
+	Current bool
+	// Ahead counts commits on the branch that are not on the base; Behind
+	// counts commits on the base that are not on the branch.
+	Ahead  int
+	Behind int
+	// Tip is the branch's last commit.
+	Tip      plumbing.Hash
+	TipWhen  time.Time
+	TipOwner string // author name of the tip
+	Subject  string
+}
+
+// LocalBranches lists the names of the local branches, sorted.
+func (g *GitCLI) LocalBranches() ([]string, error) {
+	iter, err := g.repo.Branches()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	var names []string
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		names = append(names, ref.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// DefaultBranch guesses the branch work is merged into: the branch origin's
+// HEAD points at, or else main or master if one exists locally.
+func (g *GitCLI) DefaultBranch() (string, error) {
+	if ref, err := g.repo.Reference(plumbing.NewRemoteHEADReferenceName("origin"), false); err == nil && ref.Type() == plumbing.SymbolicReference {
+		name := strings.TrimPrefix(ref.Target().String(), "refs/remotes/origin/")
+		if _, err := g.repo.Reference(plumbing.NewBranchReferenceName(name), false); err == nil {
+			return name, nil
+		}
+	}
+	for _, name := range []string{"main", "master"} {
+		if _, err := g.repo.Reference(plumbing.NewBranchReferenceName(name), false); err == nil {
+			return name, nil
+		}
+	}
+	return "", ErrUnknownGitIssue{Message: "no default branch found (neither origin/HEAD, main nor master); pass one with --base"}
+}
+
+// CompareBranches describes every local branch against base: how far ahead
+// and behind it is and who committed to it last.
+func (g *GitCLI) CompareBranches(base string) ([]BranchInfo, error) {
+	baseRef, err := g.repo.Reference(plumbing.NewBranchReferenceName(base), true)
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: "branch " + base + ": " + err.Error()}
+	}
+	// Without generation numbers each branch is counted against the whole
+	// history of the base, which is then walked only once.
+	var baseSet map[plumbing.Hash]bool
+	if !g.generations {
+		if baseSet, err = g.ancestors(baseRef.Hash(), nil); err != nil {
+			return nil, err
+		}
+	}
+
+	current := ""
+	if head, err := g.repo.Head(); err == nil && head.Name().IsBranch() {
+		current = head.Name().Short()
+	}
+
+	names, err := g.LocalBranches()
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]BranchInfo, 0, len(names))
+	for _, name := range names {
+		ref, err := g.repo.Reference(plumbing.NewBranchReferenceName(name), true)
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		tip, err := g.repo.CommitObject(ref.Hash())
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+
+		info := BranchInfo{
+			Name:     name,
+			Current:  name == current,
+			Tip:      tip.Hash,
+			TipWhen:  tip.Committer.When,
+			TipOwner: tip.Author.Name,
+			Subject:  strings.SplitN(strings.TrimSpace(tip.Message), "\n", 2)[0],
+		}
+		if info.Ahead, info.Behind, err = g.aheadBehind(tip.Hash, baseRef.Hash(), baseSet); err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// aheadBehind counts the commits reachable from tip but not from base
+// (ahead) and those reachable from base but not from tip (behind). With a
+// commit-graph's generation numbers only the commits down to where the two
+// meet are walked. Otherwise the whole history of base is, unless the
+// caller already has it in baseSet; the walk from tip then stops at the
+// first commits it shares with the base, whose own history is counted once
+// within the base.
+func (g *GitCLI) aheadBehind(tip, base plumbing.Hash, baseSet map[plumbing.Hash]bool) (ahead, behind int, err error) {
+	if g.generations {
+		return g.aheadBehindByGeneration(tip, base)
+	}
+	if baseSet == nil {
+		if baseSet, err = g.ancestors(base, nil); err != nil {
+			return 0, 0, err
+		}
+	}
+	var shared []plumbing.Hash
+	own, err := g.ancestors(tip, func(h plumbing.Hash) bool {
+		if baseSet[h] {
+			shared = append(shared, h)
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	common := map[plumbing.Hash]bool{}
+	for _, h := range shared {
+		if common[h] {
+			continue
+		}
+		if _, err := g.ancestorsInto(h, common, nil); err != nil {
+			return 0, 0, err
+		}
+	}
+	return len(own), len(baseSet) - len(common), nil
+}
+
+// ancestors collects the commits reachable from start, start included. A
+// commit for which stop returns true is neither collected nor walked past.
+func (g *GitCLI) ancestors(start plumbing.Hash, stop func(plumbing.Hash) bool) (map[plumbing.Hash]bool, error) {
+	return g.ancestorsInto(start, map[plumbing.Hash]bool{}, stop)
+}
+
+// ancestorsInto is ancestors adding to seen, whose commits are not walked
+// again.
+func (g *GitCLI) ancestorsInto(start plumbing.Hash, seen map[plumbing.Hash]bool, stop func(plumbing.Hash) bool) (map[plumbing.Hash]bool, error) {
+	queue := []plumbing.Hash{start}
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		if seen[h] || (stop != nil && stop(h)) {
+			continue
+		}
+		seen[h] = true
+		node, err := g.nodes.Get(h)
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			continue // the edge of a shallow clone
+		}
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		queue = append(queue, node.ParentHashes()...)
+	}
+	return seen, nil
+}
+
+// ErrBranchExists is returned when creating a branch whose name is taken.
+type ErrBranchExists struct {
+	Name string
+}
+
+func (e ErrBranchExists) Error() string {
+	return fmt.Sprintf("a branch named %s already exists", e.Name)
+}
+
+// StartBranch creates a branch at HEAD and switches to it, carrying over
+// uncommitted changes in the working tree and index.
+func (g *GitCLI) StartBranch(name string) error {
+	ref := plumbing.NewBranchReferenceName(name)
+	if err := ref.Validate(); err != nil {
+		return ErrUnknownGitIssue{Message: fmt.Sprintf("%q is not a valid branch name", name)}
+	}
+	if _, err := g.repo.Reference(ref, false); err == nil {
+		return ErrBranchExists{Name: name}
+	}
+	workTree, err := g.repo.Worktree()
+	if err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	if err := workTree.Checkout(&git.CheckoutOptions{Branch: ref, Create: true, Keep: true}); err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return nil
+}
+
+// issueKey is the branch.<name> config option linking a branch to an issue.
+const issueKey = "bgit-issue"
+
+// SetBranchIssue records in the repository config that branch is the work
+// on issue number, next to the branch's other settings.
+func (g *GitCLI) SetBranchIssue(branch string, number int) error {
+	// go-git drops settings it does not know from branch sections when it
+	// writes the config, so git writes this one.
+	return g.gitConfig("branch."+branch+"."+issueKey, strconv.Itoa(number))
+}
+
+// BranchIssue returns the issue branch was started for with SetBranchIssue,
+// or 0.
+func (g *GitCLI) BranchIssue(branch string) (int, error) {
+	cfg, err := g.repo.Config()
+	if err != nil {
+		return 0, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	section := cfg.Raw.Section("branch")
+	if !section.HasSubsection(branch) {
+		return 0, nil
+	}
+	n, _ := strconv.Atoi(section.Subsection(branch).Option(issueKey))
+	return n, nil
+}
+
+// Branch is a local branch and the remote branch it tracks.
+type Branch struct {
+	Name    string
+	Current bool
+	Tip     plumbing.Hash
+	Subject string
+	// Upstream is the branch it tracks, like origin/main, or empty. Ahead
+	// and Behind count the commits between them when the upstream has been
+	// fetched; UpstreamGone is set when it has not, or was deleted.
+	Upstream     string
+	Ahead        int
+	Behind       int
+	UpstreamGone bool
+}
+
+// Branches lists the local branches, sorted by name, with what they track.
+func (g *GitCLI) Branches() ([]Branch, error) {
+	names, err := g.LocalBranches()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := g.repo.Config()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	current := ""
+	if head, err := g.repo.Head(); err == nil && head.Name().IsBranch() {
+		current = head.Name().Short()
+	}
+
+	branches := make([]Branch, 0, len(names))
+	for _, name := range names {
+		b, err := g.branch(name, current, cfg)
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, b)
+	}
+	return branches, nil
+}
+
+// BranchTracking describes one local branch and how far it is ahead of and
+// behind its upstream, as of the last fetch.
+func (g *GitCLI) BranchTracking(name string) (Branch, error) {
+	cfg, err := g.repo.Config()
+	if err != nil {
+		return Branch{}, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	current := ""
+	if head, err := g.repo.Head(); err == nil && head.Name().IsBranch() {
+		current = head.Name().Short()
+	}
+	return g.branch(name, current, cfg)
+}
+
+// branch describes the local branch name for Branches.
+func (g *GitCLI) branch(name, current string, cfg *config.Config) (Branch, error) {
+	ref, err := g.repo.Reference(plumbing.NewBranchReferenceName(name), true)
+	if err != nil {
+		return Branch{}, ErrUnknownGitIssue{Message: fmt.Sprintf("branch %s not found", name)}
+	}
+	b := Branch{Name: name, Current: name == current, Tip: ref.Hash()}
+	if tip, err := g.repo.CommitObject(ref.Hash()); err == nil {
+		b.Subject = strings.SplitN(strings.TrimSpace(tip.Message), "\n", 2)[0]
+	}
+
+	bc, ok := cfg.Branches[name]
+	if !ok || !bc.Merge.IsBranch() {
+		return b, nil
+	}
+	upstream := plumbing.NewRemoteReferenceName(bc.Remote, bc.Merge.Short())
+	b.Upstream = bc.Remote + "/" + bc.Merge.Short()
+	if bc.Remote == "." {
+		upstream, b.Upstream = bc.Merge, bc.Merge.Short()
+	}
+	up, err := g.repo.Reference(upstream, true)
+	if err != nil {
+		b.UpstreamGone = true
+		return b, nil
+	}
+	if b.Ahead, b.Behind, err = g.aheadBehind(ref.Hash(), up.Hash(), nil); err != nil {
+		return Branch{}, err
+	}
+	return b, nil
+}
+
+// BranchUpstream returns the remote and the branch on it that branch
+// tracks, or empty strings when it tracks none. A branch tracking another
+// local branch has "." as its remote.
+func (g *GitCLI) BranchUpstream(branch string) (remote, remoteBranch string, err error) {
+	cfg, err := g.repo.Config()
+	if err != nil {
+		return "", "", ErrUnknownGitIssue{Message: err.Error()}
+	}
+	bc, ok := cfg.Branches[branch]
+	if !ok || !bc.Merge.IsBranch() {
+		return "", "", nil
+	}
+	return bc.Remote, bc.Merge.Short(), nil
+}
+
+// CreateBranch creates a branch at start, a revision such as a branch, tag
+// or commit, or at HEAD when start is empty, and returns the commit it
+// points at. The current branch does not change.
+func (g *GitCLI) CreateBranch(name, start string) (plumbing.Hash, error) {
+	ref := plumbing.NewBranchReferenceName(name)
+	if err := ref.Validate(); err != nil {
+		return plumbing.ZeroHash, ErrUnknownGitIssue{Message: fmt.Sprintf("%q is not a valid branch name", name)}
+	}
+	if _, err := g.repo.Reference(ref, false); err == nil {
+		return plumbing.ZeroHash, ErrBranchExists{Name: name}
+	}
+	if start == "" {
+		start = "HEAD"
+	}
+	hash, err := g.repo.ResolveRevision(plumbing.Revision(start))
+	if err != nil {
+		return plumbing.ZeroHash, ErrUnknownGitIssue{Message: fmt.Sprintf("%s: %s", start, err)}
+	}
+	if err := g.repo.Storer.SetReference(plumbing.NewHashReference(ref, *hash)); err != nil {
+		return plumbing.ZeroHash, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return *hash, nil
+}
+
+// ErrBranchNotMerged is returned when deleting a branch would lose commits
+// that are on neither HEAD nor the branch's upstream.
+type ErrBranchNotMerged struct {
+	Name string
+}
+
+func (e ErrBranchNotMerged) Error() string {
+	return fmt.Sprintf("branch %s is not fully merged", e.Name)
+}
+
+// DeleteBranch deletes a local branch and its settings, such as what it
+// tracks. Unless force is set, a branch whose commits are not all on its
+// upstream, or on HEAD when it has none, is kept and ErrBranchNotMerged
+// returned, the way git branch -d does. The current branch is never
+// deleted.
+func (g *GitCLI) DeleteBranch(name string, force bool) error {
+	ref, err := g.repo.Reference(plumbing.NewBranchReferenceName(name), true)
+	if err != nil {
+		return ErrUnknownGitIssue{Message: fmt.Sprintf("branch %s not found", name)}
+	}
+	if head, err := g.repo.Head(); err == nil && head.Name() == ref.Name() {
+		return ErrUnknownGitIssue{Message: fmt.Sprintf("cannot delete branch %s: it is checked out", name)}
+	}
+
+	if !force {
+		into := plumbing.ReferenceName(plumbing.HEAD)
+		if cfg, err := g.repo.Config(); err == nil {
+			if bc, ok := cfg.Branches[name]; ok && bc.Merge.IsBranch() {
+				into = plumbing.NewRemoteReferenceName(bc.Remote, bc.Merge.Short())
+				if bc.Remote == "." {
+					into = bc.Merge
+				}
+			}
+		}
+		target, err := g.repo.Reference(into, true)
+		if err != nil {
+			return ErrUnknownGitIssue{Message: fmt.Sprintf("cannot tell whether %s is merged: %s", name, err)}
+		}
+		merged, err := g.ancestors(target.Hash(), nil)
+		if err != nil {
+			return err
+		}
+		if !merged[ref.Hash()] {
+			return ErrBranchNotMerged{Name: name}
+		}
+	}
+
+	if err := g.repo.Storer.RemoveReference(ref.Name()); err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	// go-git would drop the settings it does not know from the other
+	// branches when writing the config; git removes just this section.
+	_ = g.gitConfig("--remove-section", "branch."+name) // absent when it tracks nothing
+	return nil
+}
+
+// RenameBranch renames a local branch, keeping its settings, and moves HEAD
+// along when it is the current branch.
+func (g *GitCLI) RenameBranch(oldName, newName string) error {
+	oldRef := plumbing.NewBranchReferenceName(oldName)
+	newRef := plumbing.NewBranchReferenceName(newName)
+	if err := newRef.Validate(); err != nil {
+		return ErrUnknownGitIssue{Message: fmt.Sprintf("%q is not a valid branch name", newName)}
+	}
+	ref, err := g.repo.Reference(oldRef, true)
+	if err != nil {
+		return ErrUnknownGitIssue{Message: fmt.Sprintf("branch %s not found", oldName)}
+	}
+	if _, err := g.repo.Reference(newRef, false); err == nil {
+		return ErrBranchExists{Name: newName}
+	}
+
+	if err := g.repo.Storer.SetReference(plumbing.NewHashReference(newRef, ref.Hash())); err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	if head, err := g.repo.Storer.Reference(plumbing.HEAD); err == nil && head.Type() == plumbing.SymbolicReference && head.Target() == oldRef {
+		if err := g.repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, newRef)); err != nil {
+			return ErrUnknownGitIssue{Message: err.Error()}
+		}
+	}
+	if err := g.repo.Storer.RemoveReference(oldRef); err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	_ = g.gitConfig("--rename-section", "branch."+oldName, "branch."+newName) // absent when it tracks nothing
+	return nil
+}
+
+// Checkout switches to the local branch name, carrying uncommitted changes
+// over, and refuses when the switch would overwrite them. A name that only a
+// remote has, like feature for origin/feature, gets a local branch tracking
+// it.
+func (g *GitCLI) Checkout(name string) error {
+	// go-git either refuses any uncommitted change or leaves the working
+	// tree as it is, so git switches.
+	cmd := exec.Command("git", "switch", name)
+	cmd.Dir = g.path
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+	}
+	return nil
+}
+
+// gitConfig runs git config with args in the repository.
+func (g *GitCLI) gitConfig(args ...string) error {
+	cmd := exec.Command("git", append([]string{"config"}, args...)...)
+	cmd.Dir = g.path
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v6/plumbing"
+)
+
+// ErrCherryPickConflict is returned when a cherry-pick stops on conflicts,
+// which are left in the working tree to resolve. Applied is how many of the
+// commits went in before Commit, the one that conflicted.
+type ErrCherryPickConflict struct {
+	Commit  plumbing.Hash
+	Applied int
+}
+
+func (e ErrCherryPickConflict) Error() string {
+	return fmt.Sprintf("cherry-picking %s stopped on conflicts", e.Commit.String()[:7])
+}
+
+// CherryPick applies the changes of commits onto HEAD, oldest first, each as
+// a new commit with its message and author. With noCommit they are only
+// applied to the staging area and the working tree, to commit together.
+//
+// A cherry-pick that conflicts stops there with ErrCherryPickConflict.
+// Committing, git remembers the commits still to pick, and continuing the
+// cherry-pick once the conflicts are resolved picks them too; with noCommit
+// there is nothing to continue, and the rest are not picked.
+func (g *GitCLI) CherryPick(commits []plumbing.Hash, noCommit bool) error {
+	// go-git has no cherry-pick, and git's leaves the state that resolving
+	// and continuing rely on.
+	if !noCommit {
+		err := g.cherryPick(commits)
+		if err != nil && g.OperationInProgress() == CherryPick {
+			if stopped, ok := g.cherryPickHead(); ok {
+				if conflicts, cerr := g.Conflicts(); cerr == nil && len(conflicts) > 0 {
+					return ErrCherryPickConflict{Commit: stopped, Applied: max(slices.Index(commits, stopped), 0)}
+				}
+			}
+		}
+		return err
+	}
+	// git picks several commits without committing as one sequence, which
+	// it then cannot continue: picking one at a time leaves nothing behind.
+	for i, c := range commits {
+		if err := g.cherryPick([]plumbing.Hash{c}, "--no-commit"); err != nil {
+			if conflicts, cerr := g.Conflicts(); cerr == nil && len(conflicts) > 0 {
+				return ErrCherryPickConflict{Commit: c, Applied: i}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// AbortCherryPick gives up a cherry-pick that stopped on conflicts, putting
+// the branch, the staging area and the working tree back as they were
+// before it started.
+func (g *GitCLI) AbortCherryPick() error {
+	cmd := exec.Command("git", "cherry-pick", "--abort")
+	cmd.Dir = g.path
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+	}
+	return nil
+}
+
+func (g *GitCLI) cherryPick(commits []plumbing.Hash, flags ...string) error {
+	args := append([]string{"cherry-pick"}, flags...)
+	for _, c := range commits {
+		args = append(args, c.String())
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.path
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+	}
+	return nil
+}
+
+// cherryPickHead is the commit a stopped cherry-pick was picking.
+func (g *GitCLI) cherryPickHead() (plumbing.Hash, bool) {
+	path, err := g.GitPath("CHERRY_PICK_HEAD")
+	if err != nil {
+		return plumbing.ZeroHash, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return plumbing.ZeroHash, false
+	}
+	return plumbing.NewHash(strings.TrimSpace(string(data))), true
+}
@@ -0,0 +1,372 @@
+package internal
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+	"path"
+
+	"github.com/go-git/go-git/v6/plumbing"
+	graphfile "github.com/go-git/go-git/v6/plumbing/format/commitgraph"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/go-git/go-git/v6/plumbing/object/commitgraph"
+	"github.com/go-git/go-git/v6/plumbing/storer"
+	"github.com/go-git/go-git/v6/storage/filesystem"
+)
+
+// commitGraphPath is where git keeps a repository's commit-graph, next to
+// its objects.
+var commitGraphPath = path.Join("objects", "info", "commit-graph")
+
+// ErrShallowCommitGraph is returned when writing a commit-graph for a
+// shallow clone, whose oldest commits have parents it does not have.
+type ErrShallowCommitGraph struct{}
+
+func (ErrShallowCommitGraph) Error() string {
+	return "a shallow clone cannot have a commit-graph; fetch its whole history with git fetch --unshallow first"
+}
+
+// loadCommitGraph opens the repository's commit-graph, written by git or by
+// WriteCommitGraph, so that walking the history reads each commit's parents
+// and generation from it instead of decompressing the commit. Commits newer
+// than the file, and every commit when there is none or it cannot be read,
+// come from the object store as before.
+func (g *GitCLI) loadCommitGraph() {
+	g.nodes = commitgraph.NewObjectCommitNodeIndex(g.repo.Storer)
+	storage, ok := g.repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return
+	}
+	index, err := graphfile.OpenChainOrFileIndex(storage.Filesystem())
+	if err != nil {
+		return
+	}
+	g.graph = index
+	g.nodes = commitgraph.NewGraphCommitNodeIndex(index, g.repo.Storer)
+	// git before 2.19 wrote zeros for the generations; the parents are
+	// still worth reading from such a graph.
+	if data, err := index.GetCommitDataByIndex(0); err == nil {
+		g.generations = data.Generation > 0
+	}
+}
+
+// WriteCommitGraph writes a commit-graph of every commit reachable from a
+// reference, in git's format so that git uses it too, and returns how many
+// commits it holds. It replaces a graph written before; commits made since
+// are still found, only more slowly, until it is written again.
+func (g *GitCLI) WriteCommitGraph() (int, error) {
+	storage, ok := g.repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return 0, ErrUnknownGitIssue{Message: "the repository is not on disk"}
+	}
+	if shallow, err := g.repo.Storer.Shallow(); err == nil && len(shallow) > 0 {
+		return 0, ErrShallowCommitGraph{}
+	}
+	tips, err := g.referencedCommits()
+	if err != nil {
+		return 0, err
+	}
+
+	// The generation of a commit is one more than the greatest of its
+	// parents' and its corrected date is its commit date, moved later when
+	// needed so that it too comes after every parent's: both need the
+	// parents done first.
+	index := graphfile.NewMemoryIndex()
+	done := map[plumbing.Hash]*graphfile.CommitData{}
+	type frame struct {
+		commit *object.Commit
+		next   int
+	}
+	for _, tip := range tips {
+		if done[tip] != nil {
+			continue
+		}
+		c, err := g.repo.CommitObject(tip)
+		if err != nil {
+			return 0, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		stack := []*frame{{commit: c}}
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if top.next < len(top.commit.ParentHashes) {
+				parent := top.commit.ParentHashes[top.next]
+				top.next++
+				if done[parent] != nil {
+					continue
+				}
+				pc, err := g.repo.CommitObject(parent)
+				if err != nil {
+					return 0, ErrUnknownGitIssue{Message: err.Error()}
+				}
+				stack = append(stack, &frame{commit: pc})
+				continue
+			}
+			stack = stack[:len(stack)-1]
+			c := top.commit
+			if done[c.Hash] != nil {
+				continue // reached twice before it was done
+			}
+			data := &graphfile.CommitData{
+				TreeHash:     c.TreeHash,
+				ParentHashes: c.ParentHashes,
+				Generation:   1,
+				GenerationV2: uint64(max(c.Committer.When.Unix(), 0)),
+				When:         c.Committer.When,
+			}
+			for _, p := range c.ParentHashes {
+				data.Generation = max(data.Generation, done[p].Generation+1)
+				data.GenerationV2 = max(data.GenerationV2, done[p].GenerationV2+1)
+			}
+			done[c.Hash] = data
+			index.Add(c.Hash, data)
+		}
+	}
+
+	fs := storage.Filesystem()
+	if err := fs.MkdirAll(path.Dir(commitGraphPath), 0o755); err != nil {
+		return 0, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	tmp, err := fs.TempFile(path.Dir(commitGraphPath), "tmp_graph_")
+	if err != nil {
+		return 0, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	err = graphfile.NewEncoder(tmp).Encode(index)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = fs.Rename(tmp.Name(), commitGraphPath)
+	}
+	if err != nil {
+		_ = fs.Remove(tmp.Name())
+		return 0, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return len(done), nil
+}
+
+// referencedCommits returns the commits HEAD and every reference point at,
+// through annotated tags. References to other objects are left out.
+func (g *GitCLI) referencedCommits() ([]plumbing.Hash, error) {
+	var tips []plumbing.Hash
+	if head, err := g.repo.Head(); err == nil {
+		tips = append(tips, head.Hash())
+	}
+	refs, err := g.repo.References()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		h := ref.Hash()
+		for {
+			tag, err := g.repo.TagObject(h)
+			if err != nil {
+				break
+			}
+			h = tag.Target
+		}
+		if _, err := g.repo.CommitObject(h); err == nil {
+			tips = append(tips, h)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return tips, nil
+}
+
+// walkByDate calls fn with the commits reachable from start, newest first
+// by commit date as git log lists them, until fn returns storer.ErrStop.
+// Commits are read from the commit-graph where they are in it, so fn
+// decodes only those it needs with Commit.
+func (g *GitCLI) walkByDate(start plumbing.Hash, fn func(commitgraph.CommitNode) error) error {
+	queue := &nodeQueue{less: func(a, b commitgraph.CommitNode) bool {
+		return a.CommitTime().After(b.CommitTime())
+	}}
+	seen := map[plumbing.Hash]bool{}
+	push := func(h plumbing.Hash) error {
+		if seen[h] {
+			return nil
+		}
+		seen[h] = true
+		node, err := g.nodes.Get(h)
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil // the edge of a shallow clone
+		}
+		if err != nil {
+			return err
+		}
+		heap.Push(queue, node)
+		return nil
+	}
+	if err := push(start); err != nil {
+		return err
+	}
+	for queue.Len() > 0 {
+		node := heap.Pop(queue).(commitgraph.CommitNode)
+		if err := fn(node); err != nil {
+			if errors.Is(err, storer.ErrStop) {
+				return nil
+			}
+			return err
+		}
+		for _, p := range node.ParentHashes() {
+			if err := push(p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Which side of a comparison a commit was reached from.
+const (
+	fromTip uint8 = 1 << iota
+	fromBase
+	fromBoth = fromTip | fromBase
+)
+
+// aheadBehindByGeneration counts what aheadBehind does, walking down from
+// tip and base together in order of generation, so that every commit comes
+// after all of its children and is counted knowing which sides reach it.
+// The walk ends once every commit left to visit is reachable from both,
+// near where the two histories meet, rather than at the first commit.
+func (g *GitCLI) aheadBehindByGeneration(tip, base plumbing.Hash) (ahead, behind int, err error) {
+	gens := map[plumbing.Hash]uint64{}
+	queue := &nodeQueue{less: byGeneration(gens)}
+	sides := map[plumbing.Hash]uint8{}
+	visited := map[plumbing.Hash]bool{}
+	oneSided := 0 // queued commits reachable from only one side
+	reach := func(h plumbing.Hash, from uint8) error {
+		was, queued := sides[h]
+		now := was | from
+		if visited[h] || (queued && was == now) {
+			return nil
+		}
+		sides[h] = now
+		if queued {
+			if now == fromBoth {
+				oneSided--
+			}
+			return nil
+		}
+		node, err := g.nodes.Get(h)
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil // the edge of a shallow clone
+		}
+		if err == nil {
+			err = g.generationOf(node, gens)
+		}
+		if err != nil {
+			return ErrUnknownGitIssue{Message: err.Error()}
+		}
+		if now != fromBoth {
+			oneSided++
+		}
+		heap.Push(queue, node)
+		return nil
+	}
+	if err := reach(tip, fromTip); err != nil {
+		return 0, 0, err
+	}
+	if err := reach(base, fromBase); err != nil {
+		return 0, 0, err
+	}
+	for oneSided > 0 {
+		node := heap.Pop(queue).(commitgraph.CommitNode)
+		h := node.ID()
+		visited[h] = true
+		from := sides[h]
+		switch from {
+		case fromTip:
+			ahead++
+		case fromBase:
+			behind++
+		}
+		if from != fromBoth {
+			oneSided--
+		}
+		for _, p := range node.ParentHashes() {
+			if err := reach(p, from); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	return ahead, behind, nil
+}
+
+// generationOf records in gens the generation of node. The commit-graph
+// has it for the commits in it; for those made since, or all of them when
+// there is no graph, it is worked out from their parents', down to the
+// commits the graph has. Dates cannot stand in for it, as commits made in
+// the same second share theirs.
+func (g *GitCLI) generationOf(node commitgraph.CommitNode, gens map[plumbing.Hash]uint64) error {
+	if _, ok := gens[node.ID()]; ok {
+		return nil
+	}
+	stack := []commitgraph.CommitNode{node}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		if _, ok := gens[top.ID()]; ok {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if gen := top.Generation(); g.generations && gen != math.MaxUint64 {
+			gens[top.ID()] = gen
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		gen, ready := uint64(1), true
+		for _, p := range top.ParentHashes() {
+			if pg, ok := gens[p]; ok {
+				gen = max(gen, pg+1)
+				continue
+			}
+			parent, err := g.nodes.Get(p)
+			if errors.Is(err, plumbing.ErrObjectNotFound) {
+				continue // the edge of a shallow clone
+			}
+			if err != nil {
+				return err
+			}
+			stack, ready = append(stack, parent), false
+		}
+		if ready {
+			gens[top.ID()] = gen
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return nil
+}
+
+// byGeneration orders commits from the highest generation in gens down,
+// so that a commit comes after its children, and by date among equals.
+func byGeneration(gens map[plumbing.Hash]uint64) func(a, b commitgraph.CommitNode) bool {
+	return func(a, b commitgraph.CommitNode) bool {
+		if ga, gb := gens[a.ID()], gens[b.ID()]; ga != gb {
+			return ga > gb
+		}
+		return a.CommitTime().After(b.CommitTime())
+	}
+}
+
+// nodeQueue is a priority queue of commits for container/heap, popping the
+// commit that less puts first.
+type nodeQueue struct {
+	nodes []commitgraph.CommitNode
+	less  func(a, b commitgraph.CommitNode) bool
+}
+
+func (q *nodeQueue) Len() int           { return len(q.nodes) }
+func (q *nodeQueue) Less(i, j int) bool { return q.less(q.nodes[i], q.nodes[j]) }
+func (q *nodeQueue) Swap(i, j int)      { q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i] }
+func (q *nodeQueue) Push(x any)         { q.nodes = append(q.nodes, x.(commitgraph.CommitNode)) }
+
+func (q *nodeQueue) Pop() any {
+	last := q.nodes[len(q.nodes)-1]
+	q.nodes = q.nodes[:len(q.nodes)-1]
+	return last
+}
@@ -0,0 +1,144 @@
+package internal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v6/plumbing"
+)
+
+// graphRepo has a merge in its history and a branch besides master:
+//
+//	a - b - c - m    master
+//	     \     /
+//	      d - e - f  topic
+func graphRepo(t *testing.T) (*testRepo, map[string]plumbing.Hash) {
+	r := newTestRepo(t)
+	h := map[string]plumbing.Hash{}
+	h["a"] = r.commit("a")
+	h["b"] = r.commit("b", h["a"])
+	h["c"] = r.commit("c", h["b"])
+	h["d"] = r.commit("d", h["b"])
+	h["e"] = r.commit("e", h["d"])
+	h["m"] = r.commit("m", h["c"], h["e"])
+	h["f"] = r.commit("f", h["e"])
+	r.branch("master", h["m"])
+	r.branch("topic", h["f"])
+	return r, h
+}
+
+func TestWriteCommitGraph(t *testing.T) {
+	r, h := graphRepo(t)
+	n, err := r.client().WriteCommitGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(h) {
+		t.Errorf("WriteCommitGraph wrote %d commits, want %d", n, len(h))
+	}
+
+	g := r.client()
+	if g.graph == nil {
+		t.Fatal("the commit-graph written was not loaded")
+	}
+	if !g.generations {
+		t.Error("the commit-graph written has no generation numbers")
+	}
+	for name, hash := range h {
+		i, err := g.graph.GetIndexByHash(hash)
+		if err != nil {
+			t.Errorf("commit %s is not in the graph: %v", name, err)
+			continue
+		}
+		data, err := g.graph.GetCommitDataByIndex(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commit, err := g.repo.CommitObject(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data.TreeHash != commit.TreeHash || len(data.ParentHashes) != len(commit.ParentHashes) {
+			t.Errorf("commit %s reads back with tree %s and %d parents, want %s and %d",
+				name, data.TreeHash, len(data.ParentHashes), commit.TreeHash, len(commit.ParentHashes))
+		}
+	}
+
+	// git reads it too.
+	if _, err := exec.LookPath("git"); err == nil {
+		cmd := exec.Command("git", "commit-graph", "verify")
+		cmd.Dir = r.dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("git commit-graph verify: %v\n%s", err, out)
+		}
+	}
+}
+
+func TestCommitGraphGenerations(t *testing.T) {
+	r, h := graphRepo(t)
+	if _, err := r.client().WriteCommitGraph(); err != nil {
+		t.Fatal(err)
+	}
+	g := r.client()
+	// One more than the greatest of the parents'.
+	want := map[string]uint64{"a": 1, "b": 2, "c": 3, "d": 3, "e": 4, "m": 5, "f": 5}
+	for name, gen := range want {
+		node, err := g.nodes.Get(h[name])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := node.Generation(); got != gen {
+			t.Errorf("generation of %s = %d, want %d", name, got, gen)
+		}
+	}
+
+	// A commit made since the graph was written is worked out from its
+	// parents'.
+	after := r.commit("after", h["m"])
+	gens := map[plumbing.Hash]uint64{}
+	if err := g.generationOfHash(after, gens); err != nil {
+		t.Fatal(err)
+	}
+	if gens[after] != 6 {
+		t.Errorf("generation of a commit newer than the graph = %d, want 6", gens[after])
+	}
+}
+
+func TestCommitGraphCorrupt(t *testing.T) {
+	for name, corrupt := range map[string]func(data []byte) []byte{
+		"garbage":   func([]byte) []byte { return []byte("not a commit-graph at all") },
+		"truncated": func(data []byte) []byte { return data[:len(data)/2] },
+		"empty":     func([]byte) []byte { return nil },
+	} {
+		t.Run(name, func(t *testing.T) {
+			r, h := graphRepo(t)
+			if _, err := r.client().WriteCommitGraph(); err != nil {
+				t.Fatal(err)
+			}
+			file := filepath.Join(r.dir, ".git", "objects", "info", "commit-graph")
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(file, corrupt(data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			// A graph that cannot be read is passed over for the object
+			// store, and the history walks as before.
+			g := r.client()
+			if g.graph != nil {
+				t.Error("a corrupt commit-graph was loaded")
+			}
+			ahead, behind, err := g.aheadBehindByGeneration(h["f"], h["m"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ahead != 1 || behind != 2 {
+				t.Errorf("topic is %d ahead and %d behind master, want 1 and 2", ahead, behind)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-git/go-git/v6/plumbing/format/index"
+)
+
+// Operation is a multi-step git operation that can stop on conflicts.
+type Operation string
+
+const (
+	NoOperation Operation = ""
+	Merge       Operation = "merge"
+	Rebase      Operation = "rebase"
+	CherryPick  Operation = "cherry-pick"
+	Revert      Operation = "revert"
+)
+
+// Conflict is an unmerged path with the three versions git recorded for it
+// in the index. A version that does not exist (the file was added on one
+// side only, or deleted on the other) is nil.
+type Conflict struct {
+	Path   string
+	Base   []byte
+	Ours   []byte
+	Theirs []byte
+}
+
+// Binary reports whether any version of the file looks binary, in which case
+// it cannot be merged line by line.
+func (c Conflict) Binary() bool {
+	for _, b := range [][]byte{c.Base, c.Ours, c.Theirs} {
+		if bytes.IndexByte(b, 0) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// OperationInProgress reports which operation, if any, stopped and is waiting
+// for the user, going by the state files git leaves in the git directory
+// (of the worktree, in a linked one).
+func (g *GitCLI) OperationInProgress() Operation {
+	exists := func(name string) bool {
+		p, err := g.GitPath(name)
+		if err != nil {
+			return false
+		}
+		_, err = os.Stat(p)
+		return err == nil
+	}
+
+	switch {
+	case exists("rebase-merge"), exists("rebase-apply"):
+		return Rebase
+	case exists("MERGE_HEAD"):
+		return Merge
+	case exists("CHERRY_PICK_HEAD"):
+		return CherryPick
+	case exists("REVERT_HEAD"):
+		return Revert
+	default:
+		return NoOperation
+	}
+}
+
+// Conflicts lists the unmerged paths in the index, sorted by path.
+func (g *GitCLI) Conflicts() ([]Conflict, error) {
+	idx, err := g.repo.Storer.Index()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+
+	byPath := map[string]*Conflict{}
+	var paths []string
+	for _, e := range idx.Entries {
+		if e.Stage != index.AncestorMode && e.Stage != index.OurMode && e.Stage != index.TheirMode {
+			continue
+		}
+		c, ok := byPath[e.Name]
+		if !ok {
+			c = &Conflict{Path: e.Name}
+			byPath[e.Name] = c
+			paths = append(paths, e.Name)
+		}
+
+		blob, err := g.repo.BlobObject(e.Hash)
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		r, err := blob.Reader()
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+
+		switch e.Stage {
+		case index.AncestorMode:
+			c.Base = content
+		case index.OurMode:
+			c.Ours = content
+		case index.TheirMode:
+			c.Theirs = content
+		}
+	}
+
+	sort.Strings(paths)
+	conflicts := make([]Conflict, 0, len(paths))
+	for _, p := range paths {
+		conflicts = append(conflicts, *byPath[p])
+	}
+	return conflicts, nil
+}
+
+// MergeConflict redoes the three-way merge of c and returns the result with
+// diff3-style conflict markers (ours, base and theirs for every hunk), which
+// is what the merge tool splits into hunks. The working tree copy is not
+// used: it may already be half edited, and without merge.conflictStyle=diff3
+// it lacks the base.
+func (g *GitCLI) MergeConflict(c Conflict) (string, error) {
+	dir, err := os.MkdirTemp("", "bgit-merge-")
+	if err != nil {
+		return "", ErrUnknownGitIssue{Message: err.Error()}
+	}
+	defer os.RemoveAll(dir)
+
+	files := make([]string, 3)
+	for i, content := range [][]byte{c.Ours, c.Base, c.Theirs} {
+		files[i] = filepath.Join(dir, []string{"ours", "base", "theirs"}[i])
+		if err := os.WriteFile(files[i], content, 0o600); err != nil {
+			return "", ErrUnknownGitIssue{Message: err.Error()}
+		}
+	}
+
+	cmd := exec.Command("git", "merge-file", "-p", "--diff3",
+		"-L", "ours", "-L", "base", "-L", "theirs",
+		files[0], files[1], files[2])
+	cmd.Dir = g.path
+	out, err := cmd.Output()
+
+	// merge-file exits with the number of conflicts it left, so a small
+	// positive status is a successful run.
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128) {
+		return "", ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return string(out), nil
+}
+
+// ResolveConflict writes the resolved content of path to the working tree
+// and stages it, which marks the path as merged.
+func (g *GitCLI) ResolveConflict(path string, content []byte) error {
+	full := filepath.Join(g.path, path)
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(full); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(full, content, mode); err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+
+	cmd := exec.Command("git", "add", "--", path)
+	cmd.Dir = g.path
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+	}
+	return nil
+}
+
+// ContinueOperation resumes op once every conflict is resolved. The message
+// git prepared is kept as is rather than opening an editor.
+func (g *GitCLI) ContinueOperation(op Operation) error {
+	cmd := exec.Command("git", string(op), "--continue")
+	cmd.Dir = g.path
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+	}
+	return nil
+}
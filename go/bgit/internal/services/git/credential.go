@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing/transport"
+	"github.com/go-git/go-git/v6/plumbing/transport/http"
+)
+
+// helperCredential is basic auth filled in by git's credential helpers,
+// such as git-credential-manager or osxkeychain. The helpers are told
+// afterwards whether it worked, so that they keep it or drop it.
+type helperCredential struct {
+	*http.BasicAuth
+	// filled is what git credential fill answered, handed back as it was
+	// to approve or reject.
+	filled []byte
+}
+
+// fillCredential asks git's credential helpers for the username and
+// password of url, as git credential fill does. It returns nil when no
+// helper has any: git is not let to prompt for them, as bgit may be
+// drawing on the terminal.
+func (g *GitCLI) fillCredential(url string) *helperCredential {
+	out, err := g.credential("fill", []byte("url="+url+"\n\n"))
+	if err != nil {
+		return nil
+	}
+	auth := &http.BasicAuth{}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		key, value, _ := strings.Cut(sc.Text(), "=")
+		switch key {
+		case "username":
+			auth.Username = value
+		case "password":
+			auth.Password = value
+		}
+	}
+	if auth.Password == "" {
+		return nil
+	}
+	return &helperCredential{BasicAuth: auth, filled: out}
+}
+
+// settleCredential tells the credential helpers how the credentials in
+// auth fared: approved when the operation went through, rejected when the
+// remote turned them down. Credentials that did not come from a helper, and
+// other errors, are left alone.
+func (g *GitCLI) settleCredential(auth transport.AuthMethod, err error) {
+	c, ok := auth.(*helperCredential)
+	if !ok {
+		return
+	}
+	switch {
+	case err == nil, errors.Is(err, git.NoErrAlreadyUpToDate):
+		_, _ = g.credential("approve", c.filled)
+	case errors.Is(err, transport.ErrAuthenticationRequired), errors.Is(err, transport.ErrAuthorizationFailed):
+		_, _ = g.credential("reject", c.filled)
+	}
+}
+
+// credential runs git credential with action, writing input to it, and
+// returns what it prints.
+func (g *GitCLI) credential(action string, input []byte) ([]byte, error) {
+	cmd := exec.Command("git", "credential", action)
+	cmd.Dir = g.path
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: string(bytes.TrimSpace(stderr.Bytes()))}
+	}
+	return out, nil
+}
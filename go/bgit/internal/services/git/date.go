@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDate is returned for a commit date ParseDate cannot read.
+type ErrInvalidDate struct {
+	Date string
+}
+
+func (e ErrInvalidDate) Error() string {
+	return fmt.Sprintf("cannot read the date %q; give one like 2024-05-01 14:00, \"yesterday 14:00\" or \"3 days ago\"", e.Date)
+}
+
+// dateLayouts are the absolute dates ParseDate reads: ISO 8601 ones with or
+// without the time and zone, and git's own format as git log prints it.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"Mon Jan 2 15:04:05 2006 -0700",
+	time.RFC1123Z,
+}
+
+// clockLayouts are the times of day that can follow a relative date.
+var clockLayouts = []string{"15:04", "15:04:05", "3pm", "3:04pm"}
+
+// dateUnits are the units of "<n> <unit> ago", as days, months and years
+// where the length varies, or else as a duration.
+var dateUnits = map[string]struct {
+	d                   time.Duration
+	days, months, years int
+}{
+	"second": {d: time.Second},
+	"minute": {d: time.Minute},
+	"hour":   {d: time.Hour},
+	"day":    {days: 1},
+	"week":   {days: 7},
+	"month":  {months: 1},
+	"year":   {years: 1},
+}
+
+// ParseDate reads the date of a commit the way it is given on the command
+// line: an absolute date (2024-05-01, 2024-05-01 14:00, RFC 3339, git's own
+// format or @<unix seconds>), or one relative to now: "now", "today" or
+// "yesterday", "<n> <unit>s ago", each optionally followed by a time of day
+// ("yesterday 14:00", "today at 9:30am"), or a time of day alone, today.
+// Dates without a zone are in now's.
+func ParseDate(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	invalid := ErrInvalidDate{Date: s}
+	if unix, ok := strings.CutPrefix(s, "@"); ok {
+		sec, err := strconv.ParseInt(unix, 10, 64)
+		if err != nil {
+			return time.Time{}, invalid
+		}
+		return time.Unix(sec, 0).In(now.Location()), nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 {
+		return time.Time{}, invalid
+	}
+	t := now
+	switch {
+	case fields[0] == "now" || fields[0] == "today":
+		fields = fields[1:]
+	case fields[0] == "yesterday":
+		t, fields = now.AddDate(0, 0, -1), fields[1:]
+	case len(fields) >= 3 && fields[2] == "ago":
+		n, err := strconv.Atoi(fields[0])
+		if fields[0] == "a" || fields[0] == "an" {
+			n, err = 1, nil
+		}
+		unit, ok := dateUnits[strings.TrimSuffix(fields[1], "s")]
+		if err != nil || n < 0 || !ok {
+			return time.Time{}, invalid
+		}
+		t = now.Add(-time.Duration(n)*unit.d).AddDate(-n*unit.years, -n*unit.months, -n*unit.days)
+		fields = fields[3:]
+	}
+
+	if len(fields) > 0 && fields[0] == "at" {
+		fields = fields[1:]
+	}
+	switch len(fields) {
+	case 0:
+		return t, nil
+	case 1:
+		for _, layout := range clockLayouts {
+			if clock, err := time.Parse(layout, fields[0]); err == nil {
+				y, m, d := t.Date()
+				return time.Date(y, m, d, clock.Hour(), clock.Minute(), clock.Second(), 0, t.Location()), nil
+			}
+		}
+	}
+	return time.Time{}, invalid
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	zone := time.FixedZone("CET", 60*60)
+	now := time.Date(2025, time.March, 14, 9, 0, 0, 0, zone)
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2025, month, day, hour, min, 0, 0, zone)
+	}
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		// Relative to now.
+		{"now", now},
+		{"today", now},
+		{"  Yesterday  ", at(time.March, 13, 9, 0)},
+		{"yesterday 14:00", at(time.March, 13, 14, 0)},
+		{"yesterday at 3pm", at(time.March, 13, 15, 0)},
+		{"today at 9:30am", at(time.March, 14, 9, 30)},
+		{"14:00:30", time.Date(2025, time.March, 14, 14, 0, 30, 0, zone)},
+		{"2 hours ago", at(time.March, 14, 7, 0)},
+		{"3 days ago", at(time.March, 11, 9, 0)},
+		{"a week ago", at(time.March, 7, 9, 0)},
+		{"1 month ago", at(time.February, 14, 9, 0)},
+		{"2 days ago 18:00", at(time.March, 12, 18, 0)},
+		{"0 minutes ago", now},
+		// Absolute, in now's zone unless they give one.
+		{"2024-05-01", time.Date(2024, time.May, 1, 0, 0, 0, 0, zone)},
+		{"2024-05-01 14:00", time.Date(2024, time.May, 1, 14, 0, 0, 0, zone)},
+		{"2024-05-01T14:00:05", time.Date(2024, time.May, 1, 14, 0, 5, 0, zone)},
+		{"2024-05-01T14:00:00Z", time.Date(2024, time.May, 1, 14, 0, 0, 0, time.UTC)},
+		{"2024-05-01 14:00:00 -0700", time.Date(2024, time.May, 1, 14, 0, 0, 0, time.FixedZone("", -7*60*60))},
+		{"Wed May 1 14:00:00 2024 +0200", time.Date(2024, time.May, 1, 14, 0, 0, 0, time.FixedZone("", 2*60*60))},
+		{"@1714564800", time.Unix(1714564800, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDate(tt.in, now)
+			if err != nil {
+				t.Fatalf("ParseDate(%q): %v", tt.in, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseDate(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	for _, in := range []string{
+		"",
+		"tomorrow",
+		"yesterday noon",
+		"yesterday 25:00",
+		"3 fortnights ago",
+		"-3 days ago",
+		"some days ago",
+		"2024-13-01",
+		"@soon",
+		"14:00 yesterday",
+	} {
+		t.Run("invalid "+in, func(t *testing.T) {
+			_, err := ParseDate(in, now)
+			if !errors.As(err, new(ErrInvalidDate)) {
+				t.Errorf("ParseDate(%q) = %v, want ErrInvalidDate", in, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,425 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/filemode"
+	formatcfg "github.com/go-git/go-git/v6/plumbing/format/config"
+	fdiff "github.com/go-git/go-git/v6/plumbing/format/diff"
+	"github.com/go-git/go-git/v6/plumbing/format/index"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/go-git/go-git/v6/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// Patch is a diff between two states of the repository's files, such as
+// the index and the working tree. It encodes as a git-style unified diff.
+type Patch struct {
+	files []fdiff.FilePatch
+}
+
+func (p *Patch) FilePatches() []fdiff.FilePatch { return p.files }
+
+func (p *Patch) Message() string { return "" }
+
+// String is the patch as a unified diff, as git diff prints it.
+func (p *Patch) String() string {
+	var b strings.Builder
+	_ = fdiff.NewUnifiedEncoder(&b, fdiff.DefaultContextLines).Encode(p)
+	return b.String()
+}
+
+type filePatch struct {
+	from, to *diffFile // nil for a file created or deleted
+	binary   bool
+	chunks   []fdiff.Chunk
+}
+
+func (p filePatch) IsBinary() bool        { return p.binary }
+func (p filePatch) Chunks() []fdiff.Chunk { return p.chunks }
+
+func (p filePatch) Files() (from, to fdiff.File) {
+	// A nil *diffFile would be a non-nil File.
+	if p.from != nil {
+		from = p.from
+	}
+	if p.to != nil {
+		to = p.to
+	}
+	return from, to
+}
+
+type diffFile struct {
+	path string
+	hash plumbing.Hash
+	mode filemode.FileMode
+}
+
+func (f *diffFile) Hash() plumbing.Hash     { return f.hash }
+func (f *diffFile) Mode() filemode.FileMode { return f.mode }
+func (f *diffFile) Path() string            { return f.path }
+
+type chunk struct {
+	content string
+	op      fdiff.Operation
+}
+
+func (c chunk) Content() string       { return c.content }
+func (c chunk) Type() fdiff.Operation { return c.op }
+
+// version is a file as it is on one side of a diff, read only when its
+// content is needed.
+type version struct {
+	hash plumbing.Hash
+	mode filemode.FileMode
+	read func() ([]byte, error)
+}
+
+// DiffPatch computes, with go-git, the changes from the index to the
+// working tree, or from HEAD to the index when staged is set, limited to
+// paths (files or directories from the root of the repository) if any are
+// given. As with git diff, untracked files are left out, and so are files
+// with conflicts.
+func (g *GitCLI) DiffPatch(staged bool, paths []string) (*Patch, error) {
+	idx, err := g.repo.Storer.Index()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+
+	var from, to map[string]version
+	if staged {
+		if from, err = g.headVersions(); err != nil {
+			return nil, err
+		}
+		to = g.indexVersions(idx)
+	} else {
+		from = g.indexVersions(idx)
+		if to, err = g.worktreeVersions(idx); err != nil {
+			return nil, err
+		}
+	}
+
+	patch := &Patch{}
+	err = eachFilePatch(idx, from, to, paths, func(fp fdiff.FilePatch) error {
+		patch.files = append(patch.files, fp)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return patch, nil
+}
+
+// eachFilePatch diffs from against to file by file, in the order of their
+// names, calling fn with each file that changed; the contents of a file are
+// read only once fn has returned for the one before it.
+func eachFilePatch(idx *index.Index, from, to map[string]version, paths []string, fn func(fdiff.FilePatch) error) error {
+	names := make([]string, 0, len(from)+len(to))
+	for name := range from {
+		names = append(names, name)
+	}
+	for name := range to {
+		if _, ok := from[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	unmerged := map[string]bool{}
+	for _, e := range idx.Entries {
+		if unmergedEntry(e) {
+			unmerged[e.Name] = true
+		}
+	}
+
+	for _, name := range names {
+		if unmerged[name] || !underPaths(name, paths) {
+			continue
+		}
+		a, inFrom := from[name]
+		b, inTo := to[name]
+		if inFrom && inTo && a.hash == b.hash && a.mode == b.mode {
+			continue
+		}
+		var fromVer, toVer *version
+		if inFrom {
+			fromVer = &a
+		}
+		if inTo {
+			toVer = &b
+		}
+		fp, err := filePatchOf(name, fromVer, toVer)
+		if err != nil {
+			return err
+		}
+		if err := fn(fp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Diff is the unified diff of the worktree against the index, or of the
+// index against HEAD when staged is set, limited to paths if any are given.
+func (g *GitCLI) Diff(staged bool, paths []string) (string, error) {
+	patch, err := g.DiffPatch(staged, paths)
+	if err != nil {
+		return "", err
+	}
+	return patch.String(), nil
+}
+
+// DiffStats counts the lines changed per file in the same diff Diff shows.
+func (g *GitCLI) DiffStats(staged bool, paths []string) ([]FileStat, error) {
+	patch, err := g.DiffPatch(staged, paths)
+	if err != nil {
+		return nil, err
+	}
+	return PatchStats(patch), nil
+}
+
+// FileDiff is the patch of a single file.
+type FileDiff struct {
+	Path string
+	// Binary is set when either side of the file looks binary; Patch then
+	// only says that the file changed.
+	Binary bool
+	// Patch is the file's unified diff, headers included.
+	Patch string
+}
+
+// EachStagedFileDiff calls fn, file by file, with what committing would
+// change in stagedFiles: the index against HEAD, never the working tree.
+// Only one file's contents are held at a time, however many are staged,
+// and an error from fn stops the walk and is returned.
+func (g *GitCLI) EachStagedFileDiff(stagedFiles []string, fn func(FileDiff) error) error {
+	if len(stagedFiles) == 0 {
+		return nil
+	}
+	idx, err := g.repo.Storer.Index()
+	if err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	from, err := g.headVersions()
+	if err != nil {
+		return err
+	}
+	return eachFilePatch(idx, from, g.indexVersions(idx), stagedFiles, func(fp fdiff.FilePatch) error {
+		return fn(fileDiffOf(fp))
+	})
+}
+
+// GetStagedFilesDiff is the staged diff of stagedFiles as one unified diff.
+func (g *GitCLI) GetStagedFilesDiff(stagedFiles []string) (string, error) {
+	var b strings.Builder
+	err := g.EachStagedFileDiff(stagedFiles, func(d FileDiff) error {
+		b.WriteString(d.Patch)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// fileDiffOf encodes the patch of one file.
+func fileDiffOf(fp fdiff.FilePatch) FileDiff {
+	from, to := fp.Files()
+	name := ""
+	if to != nil {
+		name = to.Path()
+	} else if from != nil {
+		name = from.Path()
+	}
+	single := &Patch{files: []fdiff.FilePatch{fp}}
+	return FileDiff{Path: name, Binary: fp.IsBinary(), Patch: single.String()}
+}
+
+// filePatchOf diffs two versions of a file, either of which may be missing.
+func filePatchOf(name string, from, to *version) (filePatch, error) {
+	var fp filePatch
+	var before, after []byte
+	if from != nil {
+		fp.from = &diffFile{path: name, hash: from.hash, mode: from.mode}
+		data, err := from.read()
+		if err != nil {
+			return fp, err
+		}
+		before = data
+	}
+	if to != nil {
+		fp.to = &diffFile{path: name, hash: to.hash, mode: to.mode}
+		data, err := to.read()
+		if err != nil {
+			return fp, err
+		}
+		after = data
+	}
+	if isBinary(before) || isBinary(after) {
+		fp.binary = true
+		return fp, nil
+	}
+
+	for _, d := range diff.Do(string(before), string(after)) {
+		c := chunk{content: d.Text, op: fdiff.Equal}
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			c.op = fdiff.Add
+		case diffmatchpatch.DiffDelete:
+			c.op = fdiff.Delete
+		}
+		fp.chunks = append(fp.chunks, c)
+	}
+	return fp, nil
+}
+
+// headVersions are the files of the HEAD commit, none before the first
+// commit.
+func (g *GitCLI) headVersions() (map[string]version, error) {
+	versions := map[string]version{}
+	head, err := g.repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return versions, nil
+	}
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	commit, err := g.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	err = tree.Files().ForEach(func(f *object.File) error {
+		versions[f.Name] = version{hash: f.Hash, mode: f.Mode, read: g.blobReader(f.Hash)}
+		return nil
+	})
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return versions, nil
+}
+
+// indexVersions are the files staged in idx. Files with conflicts are left
+// out, and so are those added with -N, which have no content staged.
+func (g *GitCLI) indexVersions(idx *index.Index) map[string]version {
+	versions := map[string]version{}
+	for _, e := range idx.Entries {
+		if unmergedEntry(e) || e.Mode == filemode.Submodule || e.IntentToAdd {
+			continue
+		}
+		versions[e.Name] = version{hash: e.Hash, mode: e.Mode, read: g.blobReader(e.Hash)}
+	}
+	return versions
+}
+
+// worktreeVersions are the files of the working tree that idx tracks. A
+// file whose size and modification time match its entry is taken to be
+// unchanged without reading it, as git does.
+func (g *GitCLI) worktreeVersions(idx *index.Index) (map[string]version, error) {
+	versions := map[string]version{}
+	for _, e := range idx.Entries {
+		if unmergedEntry(e) || e.Mode == filemode.Submodule {
+			continue
+		}
+		full := filepath.Join(g.path, filepath.FromSlash(e.Name))
+		info, err := os.Lstat(full)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		mode := worktreeMode(info)
+		if !e.IntentToAdd && mode == e.Mode && int64(e.Size) == info.Size() && e.ModifiedAt.Equal(info.ModTime()) {
+			versions[e.Name] = version{hash: e.Hash, mode: e.Mode, read: g.blobReader(e.Hash)}
+			continue
+		}
+
+		var data []byte
+		if mode == filemode.Symlink {
+			target, err := os.Readlink(full)
+			if err != nil {
+				return nil, ErrUnknownGitIssue{Message: err.Error()}
+			}
+			data = []byte(filepath.ToSlash(target))
+		} else if data, err = os.ReadFile(full); err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		hash, err := plumbing.FromObjectFormat(formatcfg.SHA1).Compute(plumbing.BlobObject, data)
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		versions[e.Name] = version{hash: hash, mode: mode, read: func() ([]byte, error) { return data, nil }}
+	}
+	return versions, nil
+}
+
+// worktreeMode is the git mode of a file in the working tree.
+func worktreeMode(info fs.FileInfo) filemode.FileMode {
+	switch {
+	case info.Mode()&fs.ModeSymlink != 0:
+		return filemode.Symlink
+	case info.Mode()&0o111 != 0:
+		return filemode.Executable
+	}
+	return filemode.Regular
+}
+
+// blobReader reads the blob with hash when called.
+func (g *GitCLI) blobReader(hash plumbing.Hash) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		blob, err := g.repo.BlobObject(hash)
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		r, err := blob.Reader()
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		defer r.Close()
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		return data, nil
+	}
+}
+
+// unmergedEntry reports whether e is one side of a conflict. Merged entries
+// have stage 0; go-git's index.Merged is 1, the same as AncestorMode.
+func unmergedEntry(e *index.Entry) bool {
+	return e.Stage != 0
+}
+
+// isBinary guesses, as git does, that content with a NUL byte in its first
+// 8000 bytes is binary.
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0
+}
+
+// underPaths reports whether name is one of paths or inside one of them;
+// no paths takes in every file.
+func underPaths(name string, paths []string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+	for _, p := range paths {
+		p = path.Clean(filepath.ToSlash(p))
+		if p == "." || name == p || strings.HasPrefix(name, p+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/config"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/transport"
+)
+
+// FetchOptions says what Fetch fetches.
+type FetchOptions struct {
+	Remote string
+	// Prune deletes the remote-tracking branches whose branch is gone from
+	// the remote.
+	Prune bool
+	Auth  Auth
+	// Progress receives what the remote reports while the fetch runs.
+	Progress io.Writer
+}
+
+// RefUpdate is a remote-tracking branch a fetch moved, created or deleted.
+// Old is zero for a new branch and New for a deleted one.
+type RefUpdate struct {
+	Name string
+	Old  plumbing.Hash
+	New  plumbing.Hash
+}
+
+// FetchResult is what a fetch changed and where the current branch stands
+// afterwards.
+type FetchResult struct {
+	Remote  string
+	Updated []RefUpdate
+	// Current is the branch checked out, with how far it is ahead of and
+	// behind its upstream now; its Name is empty on a detached HEAD.
+	Current Branch
+}
+
+// Fetch downloads the branches and tags of a remote with go-git and
+// updates the remote-tracking branches. Cancelling ctx aborts the transfer.
+func (g *GitCLI) Fetch(ctx context.Context, opts FetchOptions) (FetchResult, error) {
+	result := FetchResult{Remote: opts.Remote}
+	remote, url, auth, err := g.connect(opts.Remote, opts.Auth)
+	if err != nil {
+		return result, err
+	}
+
+	before, err := g.remoteRefs(opts.Remote)
+	if err != nil {
+		return result, err
+	}
+	proxy := g.proxy(opts.Remote, url)
+	fetch := func(specs []config.RefSpec, prune bool) error {
+		return retry(ctx, opts.Progress, transient, func() error {
+			err := remote.FetchContext(ctx, &git.FetchOptions{
+				RemoteName:   opts.Remote,
+				RemoteURL:    url,
+				RefSpecs:     specs,
+				Auth:         auth,
+				Progress:     opts.Progress,
+				Prune:        prune,
+				ProxyOptions: proxy,
+			})
+			if errors.Is(err, git.NoErrAlreadyUpToDate) {
+				return nil
+			}
+			return err
+		})
+	}
+	err = g.fetchInBatches(ctx, opts.Remote, url, auth, proxy, before, fetch)
+	if err == nil {
+		// Everything else, and the tags, pruning as it goes; whatever the
+		// batches fetched is only negotiated.
+		err = fetch(nil, opts.Prune)
+	}
+	g.settleCredential(auth, err)
+	if err != nil {
+		return result, remoteError(opts.Remote, auth, err)
+	}
+
+	after, err := g.remoteRefs(opts.Remote)
+	if err != nil {
+		return result, err
+	}
+	for name, h := range after {
+		if before[name] != h {
+			result.Updated = append(result.Updated, RefUpdate{Name: name, Old: before[name], New: h})
+		}
+	}
+	for name, h := range before {
+		if _, ok := after[name]; !ok {
+			result.Updated = append(result.Updated, RefUpdate{Name: name, Old: h})
+		}
+	}
+	sort.Slice(result.Updated, func(i, j int) bool { return result.Updated[i].Name < result.Updated[j].Name })
+
+	if head, err := g.repo.Head(); err == nil && head.Name().IsBranch() {
+		if result.Current, err = g.BranchTracking(head.Name().Short()); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// fetchInBatches fetches the branches of the remote at url that changed
+// fetchBatch at a time, when there are more than that, so that each batch
+// is kept as it arrives.
+func (g *GitCLI) fetchInBatches(ctx context.Context, name, url string, auth transport.AuthMethod, proxy transport.ProxyOptions, known map[string]plumbing.Hash, fetch func([]config.RefSpec, bool) error) error {
+	cfg, err := g.repo.Remote(name)
+	if err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	remote := git.NewRemote(g.repo.Storer, &config.RemoteConfig{Name: name, URLs: []string{url}})
+	var refs []*plumbing.Reference
+	err = retry(ctx, nil, transient, func() error {
+		refs, err = remote.ListContext(ctx, &git.ListOptions{Auth: auth, ProxyOptions: proxy})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	stale := staleBranches(refs, known, cfg.Config().Fetch)
+	if len(stale) <= fetchBatch {
+		return nil
+	}
+	for batch := range slices.Chunk(stale, fetchBatch) {
+		if err := fetch(batch, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remoteRefs maps the remote-tracking branches of remote, like
+// origin/main, to the commits they point at.
+func (g *GitCLI) remoteRefs(remote string) (map[string]plumbing.Hash, error) {
+	iter, err := g.repo.References()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	refs := map[string]plumbing.Hash{}
+	prefix := "refs/remotes/" + remote + "/"
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference && strings.HasPrefix(ref.Name().String(), prefix) {
+			refs[ref.Name().Short()] = ref.Hash()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return refs, nil
+}
+
+// ErrIntegrateConflict is returned when merging or rebasing onto the
+// upstream stops on conflicts, which are left in the working tree to
+// resolve.
+type ErrIntegrateConflict struct {
+	Upstream string
+	Rebase   bool
+}
+
+func (e ErrIntegrateConflict) Error() string {
+	if e.Rebase {
+		return fmt.Sprintf("rebasing onto %s stopped on conflicts", e.Upstream)
+	}
+	return fmt.Sprintf("merging %s stopped on conflicts", e.Upstream)
+}
+
+// Integrate brings the current branch up to date with upstream, a
+// remote-tracking branch like origin/main, by merging it or by rebasing the
+// branch's own commits onto it. A branch with nothing of its own is
+// fast-forwarded either way.
+func (g *GitCLI) Integrate(upstream string, rebase bool) error {
+	// go-git can only fast-forward, so git merges and rebases.
+	args := []string{"merge", "--no-edit", upstream}
+	if rebase {
+		args = []string{"rebase", upstream}
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.path
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if conflicts, cerr := g.Conflicts(); cerr == nil && len(conflicts) > 0 {
+		return ErrIntegrateConflict{Upstream: upstream, Rebase: rebase}
+	}
+	return ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+}
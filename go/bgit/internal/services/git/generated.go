@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// DefaultGeneratedPatterns recognize files that are produced by tools rather
+// than written by hand: dependency lockfiles, minified assets and vendored
+// dependencies. A pattern without a slash matches the file name anywhere; one
+// with a slash matches the whole path; one ending in a slash matches
+// everything under a directory of that name.
+var DefaultGeneratedPatterns = []string{
+	"package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml", "bun.lockb",
+	"go.sum", "Cargo.lock", "Gemfile.lock", "composer.lock", "poetry.lock", "Pipfile.lock",
+	"uv.lock", "flake.lock", "mix.lock", "pubspec.lock", "Podfile.lock",
+	"*.min.js", "*.min.css", "*.min.js.map", "*.min.css.map",
+	"*.pb.go", "*_pb2.py", "*.generated.*",
+	"vendor/", "node_modules/", "third_party/",
+}
+
+// MatchGenerated reports whether path matches one of patterns.
+func MatchGenerated(p string, patterns []string) bool {
+	base := path.Base(p)
+	for _, pat := range patterns {
+		switch {
+		case strings.HasSuffix(pat, "/"):
+			dir := strings.TrimSuffix(pat, "/")
+			if strings.HasPrefix(p, dir+"/") || strings.Contains(p, "/"+dir+"/") {
+				return true
+			}
+		case strings.Contains(pat, "/"):
+			if ok, _ := path.Match(strings.TrimPrefix(pat, "/"), p); ok {
+				return true
+			}
+		default:
+			if ok, _ := path.Match(pat, base); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GeneratedFiles picks out the generated files among paths. The
+// linguist-generated attribute from .gitattributes decides when it is set
+// either way, so a repository can claim a file the patterns miss or rescue
+// one they catch; otherwise DefaultGeneratedPatterns and extra are used.
+func (g *GitCLI) GeneratedFiles(paths []string, extra []string) (map[string]bool, error) {
+	generated := map[string]bool{}
+	if len(paths) == 0 {
+		return generated, nil
+	}
+
+	attrs, err := g.checkAttr("linguist-generated", paths)
+	if err != nil {
+		return nil, err
+	}
+	patterns := append(append([]string(nil), DefaultGeneratedPatterns...), extra...)
+	for _, p := range paths {
+		switch attrs[p] {
+		case "set", "true":
+			generated[p] = true
+		case "unset", "false":
+		default:
+			if MatchGenerated(p, patterns) {
+				generated[p] = true
+			}
+		}
+	}
+	return generated, nil
+}
+
+// checkAttr returns the value of attr for each path that specifies it.
+func (g *GitCLI) checkAttr(attr string, paths []string) (map[string]string, error) {
+	cmd := exec.Command("git", append([]string{"check-attr", "-z", attr, "--"}, paths...)...)
+	cmd.Dir = g.path
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+
+	// -z output is path NUL attribute NUL value NUL, repeated.
+	fields := strings.Split(string(out), "\x00")
+	values := map[string]string{}
+	for i := 0; i+2 < len(fields); i += 3 {
+		if v := fields[i+2]; v != "unspecified" {
+			values[fields[i]] = v
+		}
+	}
+	return values, nil
+}
+
+// StatPath is the path a FileStat ends up at: the new name of a rename.
+func StatPath(p string) string {
+	if open := strings.Index(p, "{"); open >= 0 {
+		if end := strings.Index(p[open:], "}"); end >= 0 {
+			inner := p[open+1 : open+end]
+			if _, to, ok := strings.Cut(inner, " => "); ok {
+				return path.Clean(p[:open] + to + p[open+end+1:])
+			}
+		}
+	}
+	if _, to, ok := strings.Cut(p, " => "); ok {
+		return to
+	}
+	return p
+}
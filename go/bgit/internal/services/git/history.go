@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+)
+
+// FileRevision is a commit that changed a file, with the change it made.
+type FileRevision struct {
+	Commit *object.Commit
+	// Path is the file's name in this commit; OldPath is its name before
+	// when the commit renamed it.
+	Path    string
+	OldPath string
+	// Patch holds only this file's change, against the first parent it
+	// differs from.
+	Patch *object.Patch
+}
+
+// FileHistory lists the commits reachable from HEAD that changed file, newest
+// first, stopping after max (0 for all). With follow, a rename is traced back
+// to the file's earlier name and its history continues under that name, the
+// way git log --follow does.
+func (g *GitCLI) FileHistory(file string, follow bool, max int) ([]FileRevision, error) {
+	head, err := g.repo.Head()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	iter, err := g.repo.Log(&git.LogOptions{From: head.Hash(), Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	defer iter.Close()
+
+	current := path.Clean(file)
+	var revs []FileRevision
+	err = iter.ForEach(func(c *object.Commit) error {
+		rev, ok, err := g.fileRevision(c, current, follow)
+		if err != nil || !ok {
+			return err
+		}
+		revs = append(revs, rev)
+		if rev.OldPath != "" {
+			current = rev.OldPath
+		}
+		if max > 0 && len(revs) >= max {
+			return io.EOF
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return revs, nil
+}
+
+// fileRevision works out whether c changed file and, if so, how. A merge only
+// counts when the file differs from every parent, so changes brought in from
+// a branch are shown once, on the branch.
+func (g *GitCLI) fileRevision(c *object.Commit, file string, follow bool) (FileRevision, bool, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return FileRevision{}, false, err
+	}
+	hash, mode := treeEntry(tree, file)
+
+	parentTree := &object.Tree{}
+	changedFromAll := true
+	for i := 0; i < c.NumParents(); i++ {
+		parent, err := c.Parent(i)
+		if err != nil {
+			return FileRevision{}, false, err
+		}
+		pt, err := parent.Tree()
+		if err != nil {
+			return FileRevision{}, false, err
+		}
+		if i == 0 {
+			parentTree = pt
+		}
+		if ph, pm := treeEntry(pt, file); ph == hash && pm == mode {
+			changedFromAll = false
+			break
+		}
+	}
+	if !changedFromAll {
+		return FileRevision{}, false, nil
+	}
+
+	renameCheck := follow && !hash.IsZero()
+	if ph, _ := treeEntry(parentTree, file); !ph.IsZero() {
+		renameCheck = false // modified in place, not added
+	}
+	changes, err := object.DiffTreeWithOptions(context.Background(), parentTree, tree,
+		&object.DiffTreeOptions{DetectRenames: renameCheck})
+	if err != nil {
+		return FileRevision{}, false, err
+	}
+
+	for _, ch := range changes {
+		if ch.To.Name != file && (ch.To.Name != "" || ch.From.Name != file) {
+			continue
+		}
+		patch, err := ch.Patch()
+		if err != nil {
+			return FileRevision{}, false, err
+		}
+		rev := FileRevision{Commit: c, Path: file, Patch: patch}
+		if ch.From.Name != "" && ch.To.Name != "" && ch.From.Name != ch.To.Name {
+			rev.OldPath = ch.From.Name
+		}
+		return rev, true, nil
+	}
+	return FileRevision{}, false, nil
+}
+
+// treeEntry returns the blob hash and mode of file in tree, or a zero hash
+// when it is not there.
+func treeEntry(tree *object.Tree, file string) (plumbing.Hash, uint32) {
+	e, err := tree.FindEntry(file)
+	if err != nil {
+		return plumbing.ZeroHash, 0
+	}
+	return e.Hash, uint32(e.Mode)
+}
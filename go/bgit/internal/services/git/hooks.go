@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// HookMarker is written into every hook bgit installs, so bgit can tell its
+// own hooks from ones it must not overwrite.
+const HookMarker = "# Installed by bgit"
+
+// ErrHookExists is returned when a hook bgit did not write is already in
+// place.
+type ErrHookExists struct {
+	Name string
+	Path string
+}
+
+func (e ErrHookExists) Error() string {
+	return fmt.Sprintf("git: a %s hook bgit did not install already exists at %s", e.Name, e.Path)
+}
+
+// InstallHook writes script as the named hook (e.g. prepare-commit-msg) and
+// makes it executable. The hooks directory is the one git itself uses, so
+// core.hooksPath is honored. A hook bgit installed earlier is replaced; any
+// other fails with ErrHookExists. It returns the path written.
+func (g *GitCLI) InstallHook(name, script string) (string, error) {
+	dir, err := g.GitPath("hooks")
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, name)
+	existing, err := os.ReadFile(path)
+	switch {
+	case err == nil && !bytes.Contains(existing, []byte(HookMarker)):
+		return "", ErrHookExists{Name: name, Path: path}
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		return "", ErrUnknownGitIssue{Message: err.Error()}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", ErrUnknownGitIssue{Message: err.Error()}
+	}
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		return "", ErrUnknownGitIssue{Message: err.Error()}
+	}
+	// WriteFile keeps the mode of a file that already existed.
+	if err := os.Chmod(path, 0o755); err != nil {
+		return "", ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return path, nil
+}
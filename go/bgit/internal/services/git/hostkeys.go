@@ -0,0 +1,193 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v6/plumbing/transport"
+	gitssh "github.com/go-git/go-git/v6/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v6/plumbing/transport/ssh/knownhosts"
+	"golang.org/x/crypto/ssh"
+	xknownhosts "golang.org/x/crypto/ssh/knownhosts"
+)
+
+// HostKey is the key an SSH server showed for itself.
+type HostKey struct {
+	// Host is the server as known_hosts names it, like github.com, or
+	// [git.example.com]:2222 off the standard port.
+	Host string
+	// Type is the kind of key, like ssh-ed25519.
+	Type string
+	// Fingerprint is the SHA256 fingerprint ssh shows, like SHA256:+DiY3w….
+	Fingerprint string
+}
+
+// ErrHostKeyUnknown is returned when an SSH server is not in known_hosts and
+// was not trusted.
+type ErrHostKeyUnknown struct {
+	Key HostKey
+}
+
+func (e ErrHostKeyUnknown) Error() string {
+	return fmt.Sprintf("the SSH host %s is not known: its %s key is %s", e.Key.Host, e.Key.Type, e.Key.Fingerprint)
+}
+
+// ErrHostKeyChanged is returned when an SSH server shows a key other than
+// the one known_hosts lists for it, which is what impersonating the server
+// would look like. Such a host is never trusted.
+type ErrHostKeyChanged struct {
+	Key HostKey
+	// Known is where known_hosts lists the key expected, as file:line.
+	Known string
+}
+
+func (e ErrHostKeyChanged) Error() string {
+	return fmt.Sprintf("the SSH host key of %s has changed: it shows %s key %s, not the one at %s", e.Key.Host, e.Key.Type, e.Key.Fingerprint, e.Known)
+}
+
+// knownHostsFiles lists the known_hosts files ssh reads, as go-git does:
+// those in SSH_KNOWN_HOSTS, or else the user's and the system's. The first
+// is where newly trusted hosts are added.
+func knownHostsFiles() []string {
+	if files := filepath.SplitList(os.Getenv("SSH_KNOWN_HOSTS")); len(files) > 0 {
+		return files
+	}
+	home, _ := os.UserHomeDir()
+	return []string{filepath.Join(home, ".ssh", "known_hosts"), "/etc/ssh/ssh_known_hosts"}
+}
+
+// hostKeys checks the key of an SSH server against known_hosts, asking
+// trust about one it does not list.
+type hostKeys struct {
+	files []string
+	// db holds the known_hosts files that exist; nil when none does.
+	db    *knownhosts.HostKeyDB
+	trust func(HostKey) (bool, error)
+	// err is why the last check failed, which go-git does not always pass
+	// on as it was.
+	err error
+}
+
+func newHostKeys(trust func(HostKey) (bool, error)) (*hostKeys, error) {
+	h := &hostKeys{files: knownHostsFiles(), trust: trust}
+	var existing []string
+	for _, f := range h.files {
+		if _, err := os.Stat(f); err == nil {
+			existing = append(existing, f)
+		}
+	}
+	if len(existing) > 0 {
+		db, err := knownhosts.NewDB(existing...)
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		h.db = db
+	}
+	return h, nil
+}
+
+// check is the ssh.HostKeyCallback.
+func (h *hostKeys) check(hostname string, remote net.Addr, key ssh.PublicKey) error {
+	hk := HostKey{Host: knownhosts.Normalize(hostname), Type: key.Type(), Fingerprint: ssh.FingerprintSHA256(key)}
+	h.err = nil
+	if h.db == nil {
+		h.err = h.trustOnFirstUse(hk, hostname, remote, key)
+		return h.err
+	}
+	err := h.db.HostKeyCallback()(hostname, remote, key)
+	var keyErr *xknownhosts.KeyError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &keyErr) && len(keyErr.Want) > 0:
+		want := keyErr.Want[0]
+		h.err = ErrHostKeyChanged{Key: hk, Known: want.Filename + ":" + strconv.Itoa(want.Line)}
+	case errors.As(err, &keyErr):
+		h.err = h.trustOnFirstUse(hk, hostname, remote, key)
+	default:
+		h.err = ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return h.err
+}
+
+// trustOnFirstUse asks whether to trust a host known_hosts does not list,
+// and remembers its key in the first known_hosts file when it is.
+func (h *hostKeys) trustOnFirstUse(hk HostKey, hostname string, remote net.Addr, key ssh.PublicKey) error {
+	if h.trust == nil {
+		return ErrHostKeyUnknown{Key: hk}
+	}
+	ok, err := h.trust(hk)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrHostKeyUnknown{Key: hk}
+	}
+	path := h.files[0]
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	if err := knownhosts.WriteKnownHost(f, hostname, remote, key); err != nil {
+		f.Close()
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	if err := f.Close(); err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return nil
+}
+
+// algorithms are the host key algorithms to offer hostWithPort: those of
+// the keys known_hosts lists for it, so that the server shows one of them,
+// or every one ssh supports for a host it does not list.
+func (h *hostKeys) algorithms(hostWithPort string) []string {
+	if h.db != nil {
+		if algos := h.db.HostKeyAlgorithms(hostWithPort); len(algos) > 0 {
+			return algos
+		}
+	}
+	return ssh.SupportedAlgorithms().HostKeys
+}
+
+// sshAuth is an SSH auth method whose server keys hostKeys checks, in
+// place of go-git's check, which fails outright without a known_hosts file
+// and cannot trust a host on first use.
+type sshAuth struct {
+	gitssh.AuthMethod
+	hosts        *hostKeys
+	hostWithPort string
+}
+
+func (a *sshAuth) ClientConfig() (*ssh.ClientConfig, error) {
+	cfg, err := a.AuthMethod.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg.HostKeyCallback = a.hosts.check
+	cfg.HostKeyAlgorithms = a.hosts.algorithms(a.hostWithPort)
+	return cfg, nil
+}
+
+// remoteError turns what go-git returned from talking to remote over auth
+// into the error to report: the host key check that failed, or
+// ErrRemoteAuth when the remote turned the credentials down.
+func remoteError(remote string, auth transport.AuthMethod, err error) error {
+	if a, ok := auth.(*sshAuth); ok && a.hosts.err != nil {
+		return a.hosts.err
+	}
+	// ssh has no error of its own for credentials turned down.
+	sshDenied := strings.Contains(err.Error(), "ssh: unable to authenticate")
+	if errors.Is(err, transport.ErrAuthenticationRequired) || errors.Is(err, transport.ErrAuthorizationFailed) || sshDenied {
+		return ErrRemoteAuth{Remote: remote, Message: err.Error()}
+	}
+	return ErrUnknownGitIssue{Message: err.Error()}
+}
@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"sort"
+
+	"github.com/go-git/go-git/v6/plumbing/format/index"
+)
+
+// go-git reads and writes the intent-to-add flag of index entries but
+// otherwise treats such entries as staged empty files. The helpers here keep
+// them out of what bgit reports as staged and out of commits.
+
+// IntentToAddFiles lists the files recorded with add -N, sorted.
+func (g *GitCLI) IntentToAddFiles() ([]string, error) {
+	idx, err := g.repo.Storer.Index()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	var paths []string
+	for _, e := range idx.Entries {
+		if e.IntentToAdd {
+			paths = append(paths, e.Name)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// intentToAddSet is IntentToAddFiles as a set.
+func (g *GitCLI) intentToAddSet() (map[string]bool, error) {
+	paths, err := g.IntentToAddFiles()
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		set[p] = true
+	}
+	return set, nil
+}
+
+// clearIntentToAdd drops the flag from paths once their content is staged.
+func (g *GitCLI) clearIntentToAdd(paths []string) error {
+	idx, err := g.repo.Storer.Index()
+	if err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	want := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		want[p] = true
+	}
+	changed := false
+	for _, e := range idx.Entries {
+		if e.IntentToAdd && want[e.Name] {
+			e.IntentToAdd = false
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := g.repo.Storer.SetIndex(idx); err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return nil
+}
+
+// withoutIntentToAdd runs fn with the intent-to-add entries taken out of the
+// index, so a commit does not record them as empty files and git stash does
+// not refuse them, and puts them back afterwards: those whose file is still
+// in the working tree, as a stash with untracked files removes them.
+func (g *GitCLI) withoutIntentToAdd(fn func() error) error {
+	idx, err := g.repo.Storer.Index()
+	if err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	var kept, intents []*index.Entry
+	for _, e := range idx.Entries {
+		if e.IntentToAdd {
+			intents = append(intents, e)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	if len(intents) == 0 {
+		return fn()
+	}
+
+	idx.Entries = kept
+	if err := g.repo.Storer.SetIndex(idx); err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	fnErr := fn()
+
+	idx, err = g.repo.Storer.Index()
+	if err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	workTree, err := g.repo.Worktree()
+	if err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	for _, e := range intents {
+		if _, err := workTree.Filesystem.Lstat(e.Name); err == nil {
+			idx.Entries = append(idx.Entries, e)
+		}
+	}
+	idx.Version = max(idx.Version, 3) // extended flags need version 3
+	if err := g.repo.Storer.SetIndex(idx); err != nil && fnErr == nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return fnErr
+}
@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing/format/gitignore"
+)
+
+// EmptyDirs lists the directories at or under dir, relative to the root of
+// the working tree and slash-separated, that git cannot track as they hold
+// no file it would: only the innermost, as a file kept in those keeps the
+// ones around them too. Ignored directories are left out, and "" looks
+// through the whole working tree.
+func (g *GitCLI) EmptyDirs(dir string) ([]string, error) {
+	workTree, err := g.repo.Worktree()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	matcher, err := ignoreMatcher(workTree)
+	if err != nil {
+		return nil, err
+	}
+	dir = strings.Trim(filepath.ToSlash(filepath.Clean(dir)), "/")
+	if dir == "." {
+		dir = ""
+	}
+	if dir != "" && matcher.Match(strings.Split(dir, "/"), true) {
+		return nil, nil
+	}
+
+	var empty []string
+	if _, err := g.walkEmpty(dir, matcher, &empty); err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	sort.Strings(empty)
+	return empty, nil
+}
+
+// walkEmpty looks through dir for the directories EmptyDirs lists, adding
+// them to empty, and reports whether dir holds a file git would track.
+func (g *GitCLI) walkEmpty(dir string, matcher gitignore.Matcher, empty *[]string) (bool, error) {
+	entries, err := os.ReadDir(filepath.Join(g.path, filepath.FromSlash(dir)))
+	if err != nil {
+		return false, err
+	}
+	hasFiles, innermost := false, true
+	for _, e := range entries {
+		if dir == "" && e.Name() == ".git" {
+			continue
+		}
+		p := path.Join(dir, e.Name())
+		if matcher.Match(strings.Split(p, "/"), e.IsDir()) {
+			continue
+		}
+		if !e.IsDir() {
+			hasFiles = true
+			continue
+		}
+		innermost = false
+		has, err := g.walkEmpty(p, matcher, empty)
+		if err != nil {
+			return false, err
+		}
+		hasFiles = hasFiles || has
+	}
+	if !hasFiles && innermost && dir != "" {
+		*empty = append(*empty, dir)
+	}
+	return hasFiles, nil
+}
+
+// Keep puts an empty file called name in each of dirs, creating the
+// directory if it does not exist, and stages it, so that git tracks the
+// directory. It returns the files it staged; a dir that has the file
+// already is left as it is.
+func (g *GitCLI) Keep(dirs []string, name string) ([]string, error) {
+	workTree, err := g.repo.Worktree()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	var kept []string
+	for _, dir := range dirs {
+		file := path.Join(strings.Trim(filepath.ToSlash(dir), "/"), name)
+		abs := filepath.Join(g.path, filepath.FromSlash(file))
+		if _, err := os.Stat(abs); err == nil {
+			continue
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return kept, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+			return kept, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		if err := os.WriteFile(abs, nil, 0o644); err != nil {
+			return kept, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		if err := workTree.AddWithOptions(&git.AddOptions{Path: file}); err != nil {
+			return kept, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		kept = append(kept, file)
+	}
+	return kept, nil
+}
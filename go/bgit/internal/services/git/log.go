@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/go-git/go-git/v6/plumbing/object/commitgraph"
+	"github.com/go-git/go-git/v6/plumbing/storer"
+)
+
+// Log lists the commits reachable from rev, or from HEAD when rev is empty,
+// newest first by commit date, stopping after max (0 for all). A
+// repository with no commits yet has an empty history.
+func (g *GitCLI) Log(rev string, max int) ([]*object.Commit, error) {
+	head := rev == ""
+	if head {
+		rev = "HEAD"
+	}
+	from, err := g.repo.ResolveRevision(plumbing.Revision(rev))
+	if head && errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, nil // no commits yet
+	}
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: fmt.Sprintf("%s: %s", rev, err)}
+	}
+	var commits []*object.Commit
+	err = g.walkByDate(*from, func(node commitgraph.CommitNode) error {
+		c, err := node.Commit()
+		if err != nil {
+			return err
+		}
+		commits = append(commits, c)
+		if max > 0 && len(commits) == max {
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return commits, nil
+}
@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/go-git/go-git/v6/plumbing/format/index"
+)
+
+// MarkerHit is a conflict marker line found in staged content.
+type MarkerHit struct {
+	Path string
+	Line int
+	Text string
+}
+
+// conflictMarkers open, split and close a conflict. "=======" alone is not
+// searched for: it also underlines headings in Markdown and reStructuredText.
+var conflictMarkers = []string{"<<<<<<<", "|||||||", ">>>>>>>"}
+
+// StagedConflictMarkers scans the staged version of files, as they are in the
+// index and so as they would be committed, for leftover conflict markers.
+// Binary files and files staged for deletion are skipped.
+func (g *GitCLI) StagedConflictMarkers(files []string) ([]MarkerHit, error) {
+	idx, err := g.repo.Storer.Index()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+
+	wanted := make(map[string]bool, len(files))
+	for _, f := range files {
+		wanted[f] = true
+	}
+
+	var hits []MarkerHit
+	for _, e := range idx.Entries {
+		if !wanted[e.Name] || e.Stage != 0 || !e.Mode.IsFile() {
+			continue
+		}
+		blob, err := g.repo.BlobObject(e.Hash)
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		r, err := blob.Reader()
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		hits = append(hits, findMarkers(e, content)...)
+	}
+	return hits, nil
+}
+
+func findMarkers(e *index.Entry, content []byte) []MarkerHit {
+	if bytes.IndexByte(content, 0) >= 0 {
+		return nil // binary
+	}
+
+	var hits []MarkerHit
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	sc.Buffer(nil, 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		for _, m := range conflictMarkers {
+			if line == m || strings.HasPrefix(line, m+" ") {
+				hits = append(hits, MarkerHit{Path: e.Name, Line: n, Text: line})
+				break
+			}
+		}
+	}
+	return hits
+}
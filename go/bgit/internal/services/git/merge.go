@@ -0,0 +1,208 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-git/v6/plumbing"
+)
+
+// MergeKind is how a merge brought the current branch up to date.
+type MergeKind string
+
+const (
+	// MergeUpToDate is a merge of commits the branch already has.
+	MergeUpToDate MergeKind = "up-to-date"
+	// MergeFastForward moved the branch ahead to the merged commit, as it
+	// had nothing of its own since the merge base.
+	MergeFastForward MergeKind = "fast-forward"
+	// MergeThreeWay combined the changes of both sides since the merge base
+	// into a merge commit.
+	MergeThreeWay MergeKind = "three-way"
+)
+
+// MergeOptions says how Merge merges.
+type MergeOptions struct {
+	// FastForwardOnly refuses a merge that needs a merge commit.
+	FastForwardOnly bool
+	// NoFastForward makes a merge commit even when the branch could move
+	// ahead instead.
+	NoFastForward bool
+	// NoCommit stops before committing a three-way merge, leaving the result
+	// staged and MERGE_MSG written, for the merge to be looked over and
+	// committed. It rules out a fast-forward, which has nothing to stop at.
+	NoCommit bool
+	// Message is the message of the merge commit, in place of the one git
+	// writes, like "Merge branch 'feature'".
+	Message string
+}
+
+// MergeResult is what Merge did: how it merged, from which merge base, and
+// the commit the branch is at afterwards.
+type MergeResult struct {
+	Kind MergeKind
+	Head plumbing.Hash
+	// Theirs is the commit merged in.
+	Theirs plumbing.Hash
+	Base   plumbing.Hash
+	// Commit is the merge commit, or the one the branch fast-forwarded to;
+	// zero when the merge stopped before committing.
+	Commit plumbing.Hash
+}
+
+// ErrNotFastForward is returned when a merge limited to a fast-forward
+// would need a merge commit.
+type ErrNotFastForward struct {
+	Rev string
+}
+
+func (e ErrNotFastForward) Error() string {
+	return fmt.Sprintf("the branch has commits %s does not; merging it needs a merge commit, which --ff-only rules out", e.Rev)
+}
+
+// ErrUnrelatedHistories is returned for a merge of a commit that shares no
+// history with HEAD.
+type ErrUnrelatedHistories struct {
+	Rev string
+}
+
+func (e ErrUnrelatedHistories) Error() string {
+	return fmt.Sprintf("%s shares no history with the current branch", e.Rev)
+}
+
+// Merge merges rev, a branch or any commit, into the current branch: by
+// moving the branch ahead when it has nothing of its own, and otherwise
+// with a three-way merge from the merge base. A merge that conflicts stops
+// with ErrIntegrateConflict and the conflicts in the working tree, and
+// MERGE_MSG written for the merge commit that finishes it.
+func (g *GitCLI) Merge(rev string, opts MergeOptions) (MergeResult, error) {
+	var result MergeResult
+	head, err := g.repo.Head()
+	if err != nil {
+		return result, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	theirs, err := g.ResolveCommit(rev)
+	if err != nil {
+		return result, err
+	}
+	result.Head, result.Theirs = head.Hash(), theirs.Hash
+	bases, err := g.MergeBases(result.Head, result.Theirs)
+	if err != nil {
+		return result, err
+	}
+	if len(bases) == 0 {
+		return result, ErrUnrelatedHistories{Rev: rev}
+	}
+	result.Base = bases[0]
+
+	switch {
+	case result.Base == result.Theirs:
+		result.Kind, result.Commit = MergeUpToDate, result.Head
+		return result, nil
+	case result.Base == result.Head && !opts.NoFastForward && !opts.NoCommit:
+		result.Kind = MergeFastForward
+	case opts.FastForwardOnly:
+		return result, ErrNotFastForward{Rev: rev}
+	default:
+		result.Kind = MergeThreeWay
+	}
+
+	// go-git can only fast-forward, so git merges; it writes MERGE_HEAD and
+	// MERGE_MSG for a merge that stops, as committing it again needs.
+	args := []string{"merge", "--no-edit", "--no-ff"}
+	if result.Kind == MergeFastForward {
+		args[2] = "--ff-only"
+	}
+	if opts.NoCommit {
+		args = append(args, "--no-commit")
+	}
+	if opts.Message != "" {
+		args = append(args, "-m", opts.Message)
+	}
+	cmd := exec.Command("git", append(args, rev)...)
+	cmd.Dir = g.path
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if conflicts, cerr := g.Conflicts(); cerr == nil && len(conflicts) > 0 {
+			return result, ErrIntegrateConflict{Upstream: rev}
+		}
+		return result, ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+	}
+	if opts.NoCommit {
+		return result, nil
+	}
+	after, err := g.repo.Head()
+	if err != nil {
+		return result, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	result.Commit = after.Hash()
+	return result, nil
+}
+
+// AbortMerge gives up a merge that stopped, putting the branch, the staging
+// area and the working tree back as they were before it.
+func (g *GitCLI) AbortMerge() error {
+	cmd := exec.Command("git", "merge", "--abort")
+	cmd.Dir = g.path
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+	}
+	return nil
+}
+
+// MergeMessage is the message git wrote to MERGE_MSG for the merge in
+// progress, without its comment lines, and whether one is in progress.
+func (g *GitCLI) MergeMessage() (string, bool) {
+	if g.OperationInProgress() != Merge {
+		return "", false
+	}
+	path, err := g.GitPath("MERGE_MSG")
+	if err != nil {
+		return "", true
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", true
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), true
+}
+
+// mergeParents are the commits a merge in progress merges in, read from
+// MERGE_HEAD, which Commit adds as parents; none when no merge is.
+func (g *GitCLI) mergeParents() ([]plumbing.Hash, error) {
+	path, err := g.GitPath("MERGE_HEAD")
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	var parents []plumbing.Hash
+	for _, line := range strings.Fields(string(data)) {
+		parents = append(parents, plumbing.NewHash(line))
+	}
+	return parents, nil
+}
+
+// finishMerge removes the state of a merge that was just committed, as git
+// commit does.
+func (g *GitCLI) finishMerge() {
+	for _, name := range []string{"MERGE_HEAD", "MERGE_MSG", "MERGE_MODE", "AUTO_MERGE"} {
+		if path, err := g.GitPath(name); err == nil {
+			_ = os.Remove(path)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package internal
+
+import (
+	"container/heap"
+	"errors"
+
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object/commitgraph"
+)
+
+// MergeBases returns the best common ancestors of one and the others taken
+// together, as git merge-base --all does: the commits reachable from one
+// and from at least one of the others that no other such commit descends
+// from. A merge of them would start from these. There is usually one, but
+// criss-cross merges leave several; they come nearest first. Unrelated
+// histories have none.
+func (g *GitCLI) MergeBases(one plumbing.Hash, others ...plumbing.Hash) ([]plumbing.Hash, error) {
+	gens := map[plumbing.Hash]uint64{}
+	candidates, err := g.paintDownToCommon(one, others, gens)
+	if err != nil {
+		return nil, err
+	}
+	return g.removeRedundant(candidates, gens)
+}
+
+// OctopusMergeBases returns the best common ancestors of all the commits,
+// as git merge-base --octopus does for an octopus merge of them: the
+// commits every one of them reaches that no other such commit descends
+// from.
+func (g *GitCLI) OctopusMergeBases(commits ...plumbing.Hash) ([]plumbing.Hash, error) {
+	if len(commits) == 0 {
+		return nil, nil
+	}
+	bases := commits[:1]
+	for _, c := range commits[1:] {
+		var next []plumbing.Hash
+		seen := map[plumbing.Hash]bool{}
+		for _, b := range bases {
+			found, err := g.MergeBases(b, c)
+			if err != nil {
+				return nil, err
+			}
+			for _, h := range found {
+				if !seen[h] {
+					seen[h] = true
+					next = append(next, h)
+				}
+			}
+		}
+		gens := map[plumbing.Hash]uint64{}
+		for _, h := range next {
+			if err := g.generationOfHash(h, gens); err != nil {
+				return nil, err
+			}
+		}
+		var err error
+		if bases, err = g.removeRedundant(next, gens); err != nil {
+			return nil, err
+		}
+	}
+	return bases, nil
+}
+
+// Marks left by paintDownToCommon besides fromTip and fromBase: a stale
+// commit descends from a common ancestor already found, so neither it nor
+// its own ancestors can be a best one.
+const stale uint8 = 1 << 2
+
+// paintDownToCommon walks down from one and the others in order of
+// generation, marking which side reaches each commit, and collects the
+// commits both sides reach first. Walking stops once every commit left is
+// below one of those, so it reads only the history above the merge bases.
+// Some of what it collects may descend from others; removeRedundant drops
+// them.
+func (g *GitCLI) paintDownToCommon(one plumbing.Hash, others []plumbing.Hash, gens map[plumbing.Hash]uint64) ([]plumbing.Hash, error) {
+	queue := &nodeQueue{less: byGeneration(gens)}
+	marks := map[plumbing.Hash]uint8{}
+	visited := map[plumbing.Hash]bool{}
+	live := 0 // queued commits that are not stale
+	reach := func(h plumbing.Hash, mark uint8) error {
+		was, queued := marks[h]
+		now := was | mark
+		if visited[h] || (queued && was == now) {
+			return nil
+		}
+		marks[h] = now
+		if queued {
+			if was&stale == 0 && now&stale != 0 {
+				live--
+			}
+			return nil
+		}
+		node, err := g.nodes.Get(h)
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil // the edge of a shallow clone
+		}
+		if err == nil {
+			err = g.generationOf(node, gens)
+		}
+		if err != nil {
+			return ErrUnknownGitIssue{Message: err.Error()}
+		}
+		if now&stale == 0 {
+			live++
+		}
+		heap.Push(queue, node)
+		return nil
+	}
+
+	if err := reach(one, fromTip); err != nil {
+		return nil, err
+	}
+	for _, h := range others {
+		if err := reach(h, fromBase); err != nil {
+			return nil, err
+		}
+	}
+	var common []plumbing.Hash
+	for live > 0 {
+		node := heap.Pop(queue).(commitgraph.CommitNode)
+		h := node.ID()
+		visited[h] = true
+		mark := marks[h]
+		if mark&stale == 0 {
+			live--
+			if mark&fromBoth == fromBoth {
+				common = append(common, h)
+				mark |= stale
+			}
+		}
+		for _, p := range node.ParentHashes() {
+			if err := reach(p, mark); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return common, nil
+}
+
+// removeRedundant drops the commits among candidates that another one
+// descends from, keeping the order of the rest. gens holds the candidates'
+// generations.
+func (g *GitCLI) removeRedundant(candidates []plumbing.Hash, gens map[plumbing.Hash]uint64) ([]plumbing.Hash, error) {
+	if len(candidates) < 2 {
+		return candidates, nil
+	}
+	var kept []plumbing.Hash
+	for i, c := range candidates {
+		var others []plumbing.Hash
+		for j, o := range candidates {
+			if j != i {
+				others = append(others, o)
+			}
+		}
+		below, err := g.reachesAny(others, c, gens)
+		if err != nil {
+			return nil, err
+		}
+		if !below {
+			kept = append(kept, c)
+		}
+	}
+	return kept, nil
+}
+
+// reachesAny reports whether target is an ancestor of one of from. No
+// commit descends from one of a higher generation, so the walk goes no
+// lower than target's.
+func (g *GitCLI) reachesAny(from []plumbing.Hash, target plumbing.Hash, gens map[plumbing.Hash]uint64) (bool, error) {
+	floor := gens[target]
+	seen := map[plumbing.Hash]bool{}
+	stack := append([]plumbing.Hash(nil), from...)
+	for len(stack) > 0 {
+		h := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if h == target {
+			return true, nil
+		}
+		if seen[h] {
+			continue
+		}
+		seen[h] = true
+		node, err := g.nodes.Get(h)
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			continue // the edge of a shallow clone
+		}
+		if err == nil {
+			err = g.generationOf(node, gens)
+		}
+		if err != nil {
+			return false, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		if gens[h] <= floor {
+			continue
+		}
+		stack = append(stack, node.ParentHashes()...)
+	}
+	return false, nil
+}
+
+// generationOfHash is generationOf for the commit h.
+func (g *GitCLI) generationOfHash(h plumbing.Hash, gens map[plumbing.Hash]uint64) error {
+	node, err := g.nodes.Get(h)
+	if err == nil {
+		err = g.generationOf(node, gens)
+	}
+	if err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return nil
+}
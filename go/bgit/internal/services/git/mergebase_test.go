@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/go-git/go-git/v6/plumbing"
+)
+
+func TestMergeBases(t *testing.T) {
+	r := newTestRepo(t)
+	h := map[string]plumbing.Hash{}
+	commit := func(name string, parents ...string) {
+		var ps []plumbing.Hash
+		for _, p := range parents {
+			ps = append(ps, h[p])
+		}
+		h[name] = r.commit(name, ps...)
+	}
+	// Linear:       a - b - c
+	commit("a")
+	commit("b", "a")
+	commit("c", "b")
+	// Forked:       b - d - e
+	//                \
+	//                 f
+	commit("d", "b")
+	commit("e", "d")
+	commit("f", "b")
+	// Criss-cross: x1 on c and x2 on e, then m1 merging x2 into x1 and m2
+	// merging x1 into x2, so that both x1 and x2 are best common ancestors
+	// of m1 and m2.
+	commit("x1", "c")
+	commit("x2", "e")
+	commit("m1", "x1", "x2")
+	commit("m2", "x2", "x1")
+	// Unrelated:    u - v
+	commit("u")
+	commit("v", "u")
+
+	tests := []struct {
+		name   string
+		one    string
+		others []string
+		want   []string
+	}{
+		{"linear", "c", []string{"a"}, []string{"a"}},
+		{"same commit", "c", []string{"c"}, []string{"c"}},
+		{"fork", "e", []string{"f"}, []string{"b"}},
+		{"fork and linear", "e", []string{"c"}, []string{"b"}},
+		{"criss-cross", "m1", []string{"m2"}, []string{"x1", "x2"}},
+		{"unrelated", "c", []string{"v"}, nil},
+		// Common to one and either of the others, as with several bases.
+		{"several others", "c", []string{"e", "f"}, []string{"b"}},
+	}
+	g := r.client()
+	names := map[plumbing.Hash]string{}
+	for name, hash := range h {
+		names[hash] = name
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var others []plumbing.Hash
+			for _, o := range tt.others {
+				others = append(others, h[o])
+			}
+			bases, err := g.MergeBases(h[tt.one], others...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, b := range bases {
+				got = append(got, names[b])
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("MergeBases(%s, %v) = %v, want %v", tt.one, tt.others, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOctopusMergeBases(t *testing.T) {
+	// a - b - c - d
+	//      \   \
+	//       \   e
+	//        f
+	//
+	// u
+	r := newTestRepo(t)
+	a := r.commit("a")
+	b := r.commit("b", a)
+	c := r.commit("c", b)
+	d := r.commit("d", c)
+	e := r.commit("e", c)
+	f := r.commit("f", b)
+	u := r.commit("u")
+	g := r.client()
+
+	tests := []struct {
+		name    string
+		commits []plumbing.Hash
+		want    []plumbing.Hash
+	}{
+		{"none", nil, nil},
+		{"one", []plumbing.Hash{d}, []plumbing.Hash{d}},
+		{"two", []plumbing.Hash{d, e}, []plumbing.Hash{c}},
+		// What all three reach, not only d and e.
+		{"three", []plumbing.Hash{d, e, f}, []plumbing.Hash{b}},
+		{"order does not matter", []plumbing.Hash{f, e, d}, []plumbing.Hash{b}},
+		{"one unrelated", []plumbing.Hash{d, e, u}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := g.OctopusMergeBases(tt.commits...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("OctopusMergeBases = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
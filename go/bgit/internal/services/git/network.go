@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-git/go-git/v6/config"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/transport"
+	"github.com/go-git/go-git/v6/plumbing/transport/http"
+)
+
+// maxAttempts is how many times a transfer that failed for a reason that
+// may pass, like a dropped connection, is tried.
+const maxAttempts = 4
+
+// retryWait is how long to wait before the first retry.
+const retryWait = time.Second
+
+// retry runs op until it succeeds, fails in a way retryable does not
+// allow for, or has been tried maxAttempts times, waiting twice as long
+// before each try as before the last. Why a try failed, and how long until
+// the next, is written to progress.
+func retry(ctx context.Context, progress io.Writer, retryable func(error) bool, op func() error) error {
+	wait := retryWait
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || ctx.Err() != nil || !retryable(err) {
+			return err
+		}
+		if attempt == maxAttempts {
+			return fmt.Errorf("%w (tried %d times)", err, maxAttempts)
+		}
+		if progress != nil {
+			fmt.Fprintf(progress, "%s; trying again in %s\n", err, wait)
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// transient reports whether a transfer that failed with err may go through
+// if tried again: the connection could not be made, dropped or timed out,
+// or the server was briefly unable to answer.
+func transient(err error) bool {
+	if unreached(err) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var httpErr *http.Err
+	if errors.As(err, &httpErr) {
+		return retryableStatus(httpErr.Status)
+	}
+	// go-git does not always keep the errors it met, only saying what they
+	// were.
+	msg := err.Error()
+	if m := statusCode.FindStringSubmatch(msg); m != nil {
+		status, _ := strconv.Atoi(m[1])
+		return retryableStatus(status)
+	}
+	for _, s := range []string{"connection reset", "unexpected EOF", "broken pipe", "i/o timeout", "TLS handshake timeout"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	// The connection closed before the response came.
+	return strings.HasSuffix(msg, ": EOF")
+}
+
+// statusCode finds the HTTP status in the message of an http.Err.
+var statusCode = regexp.MustCompile(`status code: (\d{3})`)
+
+// retryableStatus reports whether an HTTP response with status says the
+// server, or a proxy on the way, was too busy or briefly down.
+func retryableStatus(status int) bool {
+	return status == 429 || status == 502 || status == 503 || status == 504
+}
+
+// unreached reports whether a transfer that failed with err did so before
+// the remote could hear of it: the host could not be looked up or
+// connected to. Only such a push is tried again, as one that reached the
+// remote may have landed.
+func unreached(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// proxy is the proxy to reach url through, as git would: the one set for
+// the named remote with remote.<name>.proxy, or else the http.proxy that
+// applies to url. Without either, the HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+// variables are followed, and ALL_PROXY over SSH. Only HTTP(S) remotes are
+// sent through a configured proxy, as with git.
+func (g *GitCLI) proxy(name, url string) transport.ProxyOptions {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return transport.ProxyOptions{}
+	}
+	if p := g.configValue("--get", "remote."+name+".proxy"); p != "" {
+		return transport.ProxyOptions{URL: p}
+	}
+	return transport.ProxyOptions{URL: g.configValue("--get-urlmatch", "http.proxy", url)}
+}
+
+// configValue is what git config prints for args, the value of a key, or
+// "" when it is not set.
+func (g *GitCLI) configValue(args ...string) string {
+	cmd := exec.Command("git", append([]string{"config"}, args...)...)
+	cmd.Dir = g.path
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// fetchBatch is how many branches a fetch asks for at once when more than
+// that many changed. Each batch that arrives is kept, so that a fetch that
+// fails part way, over a network that keeps dropping, carries on from the
+// batch it was on instead of starting over.
+const fetchBatch = 16
+
+// staleBranches lists the branches of the remote, as listed in refs, whose
+// remote-tracking branch is missing or elsewhere, with the refspec that
+// fetches each alone.
+func staleBranches(refs []*plumbing.Reference, known map[string]plumbing.Hash, specs []config.RefSpec) []config.RefSpec {
+	var stale []config.RefSpec
+	for _, ref := range refs {
+		if !ref.Name().IsBranch() || ref.Type() != plumbing.HashReference {
+			continue
+		}
+		for _, spec := range specs {
+			if !spec.Match(ref.Name()) {
+				continue
+			}
+			dst := spec.Dst(ref.Name())
+			if known[dst.Short()] != ref.Hash() {
+				stale = append(stale, config.RefSpec("+"+ref.Name().String()+":"+dst.String()))
+			}
+			break
+		}
+	}
+	return stale
+}
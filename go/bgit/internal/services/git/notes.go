@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-git/v6/plumbing"
+)
+
+// AddNote attaches text to commit as a git note under ref (such as
+// refs/notes/bgit), replacing any note already there.
+func (g *GitCLI) AddNote(ref string, commit plumbing.Hash, text string) error {
+	cmd := exec.Command("git", "notes", "--ref", ref, "add", "-f", "-F", "-", commit.String())
+	cmd.Dir = g.path
+	cmd.Stdin = strings.NewReader(text)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+	}
+	return nil
+}
+
+// Note returns the note attached to commit under ref, or "" when there is
+// none.
+func (g *GitCLI) Note(ref string, commit plumbing.Hash) (string, error) {
+	cmd := exec.Command("git", "notes", "--ref", ref, "show", commit.String())
+	cmd.Dir = g.path
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if strings.Contains(stderr.String(), "no note found") {
+			return "", nil
+		}
+		return "", ErrUnknownGitIssue{Message: strings.TrimSpace(stderr.String())}
+	}
+	return string(out), nil
+}
+
+// DirtySubmodules lists the submodules whose checkout is not the commit the
+// superproject records, or that have changes of their own.
+func (g *GitCLI) DirtySubmodules() ([]string, error) {
+	cmd := exec.Command("git", "status", "--porcelain=v2", "--ignore-submodules=none")
+	cmd.Dir = g.path
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, ErrUnknownGitIssue{Message: string(bytes.TrimSpace(exitErr.Stderr))}
+		}
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+
+	var dirty []string
+	for _, line := range strings.Split(string(out), "\n") {
+		// "1 XY Sxxx ..." for changed entries; the third field is "N..." for
+		// anything that is not a submodule.
+		fields := strings.Fields(line)
+		if len(fields) < 9 || (fields[0] != "1" && fields[0] != "2") {
+			continue
+		}
+		if sub := fields[2]; !strings.HasPrefix(sub, "S") || sub == "S..." {
+			continue
+		}
+		path := fields[8]
+		if fields[0] == "2" && len(fields) > 9 {
+			path = fields[9] // renames carry a similarity score first
+		}
+		dirty = append(dirty, path)
+	}
+	return dirty, nil
+}
@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// IsGlob reports whether p contains glob characters, so it names a pattern
+// rather than a path.
+func IsGlob(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// MatchGlob reports whether the repository path p is matched by pattern,
+// as git matches a glob pathspec: against the whole path, with '*' matching
+// any run of characters, '/' included, '?' any one character and '[...]'
+// one of a set ('[!...]' or '[^...]' one not in it). So '*.go' matches the
+// Go files at any depth, 'cmd/*' everything under cmd, and '**' is no
+// different from '*'.
+func MatchGlob(pattern, p string) bool {
+	return wildmatch(strings.TrimPrefix(path.Clean(pattern), "./"), p)
+}
+
+// wildmatch matches s against pat, trying each way a '*' can be taken.
+func wildmatch(pat, s string) bool {
+	for len(pat) > 0 {
+		switch pat[0] {
+		case '*':
+			pat = strings.TrimLeft(pat, "*")
+			if pat == "" {
+				return true
+			}
+			for i := range len(s) + 1 {
+				if wildmatch(pat, s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if s == "" {
+				return false
+			}
+			_, n := utf8.DecodeRuneInString(s)
+			pat, s = pat[1:], s[n:]
+			continue
+		case '[':
+			if class, rest, ok := cutClass(pat); ok {
+				if s == "" {
+					return false
+				}
+				r, n := utf8.DecodeRuneInString(s)
+				if matched, _ := path.Match(class, string(r)); !matched {
+					return false
+				}
+				pat, s = rest, s[n:]
+				continue
+			}
+			// An unclosed '[' is itself.
+		case '\\':
+			if len(pat) > 1 {
+				pat = pat[1:]
+			}
+		}
+		if s == "" || s[0] != pat[0] {
+			return false
+		}
+		pat, s = pat[1:], s[1:]
+	}
+	return s == ""
+}
+
+// cutClass cuts the '[...]' set pat starts with, written as path.Match
+// reads it, from the rest of pat. A ']' straight after the opening bracket
+// is one of the set.
+func cutClass(pat string) (class, rest string, ok bool) {
+	i := 1
+	negated := i < len(pat) && (pat[i] == '!' || pat[i] == '^')
+	if negated {
+		i++
+	}
+	start := i
+	if i < len(pat) && pat[i] == ']' {
+		i++
+	}
+	end := strings.IndexByte(pat[i:], ']')
+	if end < 0 {
+		return "", "", false
+	}
+	end += i
+	set := pat[start:end]
+	if strings.HasPrefix(set, "]") {
+		set = `\]` + set[1:]
+	}
+	if negated {
+		return "[^" + set + "]", pat[end+1:], true
+	}
+	return "[" + set + "]", pat[end+1:], true
+}
@@ -0,0 +1,46 @@
+package internal
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	// Each as git ls-files -- <pattern> matches it.
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/sub/y.go", true},
+		{"*.go", "README", false},
+		{"./*.go", "cmd/x.go", true},
+		{"cmd/*.go", "cmd/x.go", true},
+		{"cmd/*.go", "cmd/sub/y.go", true},
+		{"cmd/*", "cmd/sub/y.go", true},
+		{"cm*", "cmd/x.go", true},
+		{"cm?", "cmd/x.go", false},
+		{"sub/*.go", "cmd/sub/y.go", false},
+		{"?ain.go", "main.go", true},
+		{"?ain.go", "cmd/main.go", false},
+		{"[cm]*.go", "main.go", true},
+		{"[cm]*.go", "cmd/x.go", true},
+		{"[!cm]*.go", "main.go", false},
+		{"[^cm]*.go", "a/b/c/z.go", true},
+		{"[a-c]/*", "a/b/w.txt", true},
+		{"[]]*", "]x", true},
+		{"[unclosed", "[unclosed", true},
+		{`\*.go`, "*.go", true},
+		{`\*.go`, "x.go", false},
+		// '**' is no different from '*'.
+		{"**/*.go", "a/b/c/z.go", true},
+		{"**/*.go", "main.go", false},
+		{"a/**/z.go", "a/b/c/z.go", true},
+		{"a/**", "a/b/w.txt", true},
+		{"**/b", "a/b/w.txt", false},
+		{"a/*/z.go", "a/b/c/z.go", true},
+		{"**", "anything/at/all", true},
+	}
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/config"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/transport"
+	"github.com/go-git/go-git/v6/plumbing/transport/http"
+	"github.com/go-git/go-git/v6/plumbing/transport/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// Auth holds the credentials for a remote. Only the ones its protocol uses
+// are looked at.
+type Auth struct {
+	// Token is sent over HTTPS as the password of basic auth, which is how
+	// GitHub, GitLab and Gitea take personal access tokens. When empty, git's
+	// credential helpers are asked for a username and password instead.
+	Token string
+	// SSHKey is the private key used over SSH, unless the remote has one of
+	// its own set with SetRemoteSSHKey. When empty the SSH agent is asked,
+	// if one is running, or else the usual keys in ~/.ssh are tried.
+	SSHKey string
+	// SSHKeyPassphrase decrypts the SSH key.
+	SSHKeyPassphrase string
+	// TrustHost is asked whether to trust an SSH server that known_hosts
+	// does not list, given the key it showed; a server it trusts is added to
+	// known_hosts. When nil, such servers are refused. A server showing a
+	// key other than the one listed is always refused.
+	TrustHost func(HostKey) (bool, error)
+}
+
+// defaultSSHKeys are tried in order when neither a key nor an agent is
+// given, as ssh does.
+var defaultSSHKeys = []string{"id_ed25519", "id_ecdsa", "id_rsa"}
+
+// authMethod picks the go-git credentials for url. A nil method lets
+// go-git connect without any, which is what public and local remotes need.
+func (g *GitCLI) authMethod(url string, a Auth) (transport.AuthMethod, error) {
+	ep, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: fmt.Sprintf("remote URL %s: %s", url, err)}
+	}
+	switch ep.Protocol {
+	case "http", "https":
+		if ep.Password != "" {
+			return nil, nil // credentials in the URL
+		}
+		if a.Token == "" {
+			if c := g.fillCredential(url); c != nil {
+				return c, nil
+			}
+			return nil, nil // anonymous
+		}
+		user := ep.User
+		if user == "" {
+			user = "x-access-token"
+		}
+		return &http.BasicAuth{Username: user, Password: a.Token}, nil
+	case "ssh":
+		user := ep.User
+		if user == "" {
+			user = "git"
+		}
+		hosts, err := newHostKeys(a.TrustHost)
+		if err != nil {
+			return nil, err
+		}
+		method, err := sshKeys(user, ep.Host, a)
+		if err != nil {
+			return nil, err
+		}
+		port := ep.Port
+		if port == 0 {
+			port = 22
+		}
+		// go-git looks known_hosts up itself when given no check.
+		switch m := method.(type) {
+		case *ssh.PublicKeys:
+			m.HostKeyCallback = hosts.check
+		case *ssh.PublicKeysCallback:
+			m.HostKeyCallback = hosts.check
+		}
+		return &sshAuth{AuthMethod: method, hosts: hosts, hostWithPort: net.JoinHostPort(ep.Host, strconv.Itoa(port))}, nil
+	}
+	return nil, nil
+}
+
+// sshKeys picks the key to sign in to host with: a.SSHKey, or else the SSH
+// agent, or else the first of the usual keys in ~/.ssh.
+func sshKeys(user, host string, a Auth) (ssh.AuthMethod, error) {
+	if a.SSHKey != "" {
+		return loadSSHKey(user, a.SSHKey, a.SSHKeyPassphrase)
+	}
+	if os.Getenv("SSH_AUTH_SOCK") != "" {
+		agent, err := ssh.NewSSHAgentAuth(user)
+		if err == nil {
+			return agent, nil
+		}
+	}
+	home, _ := os.UserHomeDir()
+	for _, name := range defaultSSHKeys {
+		keys, err := ssh.NewPublicKeysFromFile(user, filepath.Join(home, ".ssh", name), "")
+		if err == nil {
+			return keys, nil
+		}
+	}
+	return nil, ErrUnknownGitIssue{Message: "no SSH key for " + host + ": start ssh-agent, set BGIT_SSH_KEY or give the remote one with 'bgit remote key'"}
+}
+
+// loadSSHKey reads the private key at path, decrypting it with passphrase.
+func loadSSHKey(user, path, passphrase string) (*ssh.PublicKeys, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: fmt.Sprintf("SSH key %s: %s", path, err)}
+	}
+	if passphrase == "" {
+		if _, err := gossh.ParsePrivateKey(pem); errors.As(err, new(*gossh.PassphraseMissingError)) {
+			return nil, ErrUnknownGitIssue{Message: fmt.Sprintf("SSH key %s is protected by a passphrase: set BGIT_SSH_KEY_PASSPHRASE, or add the key to ssh-agent", path)}
+		}
+	}
+	keys, err := ssh.NewPublicKeys(user, pem, passphrase)
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: fmt.Sprintf("SSH key %s: %s", path, err)}
+	}
+	return keys, nil
+}
+
+// connect looks up the named remote, the URL to reach it at and the
+// credentials to send there.
+func (g *GitCLI) connect(name string, a Auth) (*git.Remote, string, transport.AuthMethod, error) {
+	remote, err := g.repo.Remote(name)
+	if errors.Is(err, git.ErrRemoteNotFound) {
+		return nil, "", nil, ErrNoRemote{Name: name}
+	}
+	if err != nil {
+		return nil, "", nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return nil, "", nil, ErrUnknownGitIssue{Message: "remote " + name + " has no URL"}
+	}
+	url := urls[len(urls)-1]
+	if key := g.remoteSSHKey(name); key != "" {
+		a.SSHKey = key
+	}
+	auth, err := g.authMethod(url, a)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if !strings.Contains(url, ":") && !filepath.IsAbs(url) {
+		// A local path relative to the repository, which go-git would take
+		// as relative to its own root.
+		if wt, err := g.repo.Worktree(); err == nil {
+			url = filepath.Join(wt.Filesystem.Root(), url)
+		}
+	}
+	return remote, url, auth, nil
+}
+
+// PushOptions says what PushBranch pushes where.
+type PushOptions struct {
+	Remote string
+	// Branch is the local branch pushed, to RemoteBranch on the remote, or
+	// to the branch of the same name when that is empty.
+	Branch       string
+	RemoteBranch string
+	// SetUpstream makes the remote branch the one Branch tracks.
+	SetUpstream bool
+	// ForceWithLease replaces the remote branch even when the push is not a
+	// fast-forward, but only while it is where it was at the last fetch, so
+	// that nobody else's commits are thrown away.
+	ForceWithLease bool
+	// Force replaces the remote branch whatever it holds, as a mirror that
+	// only follows what is pushed elsewhere needs.
+	Force bool
+	// Tags pushes every tag along, as git push --tags does.
+	Tags bool
+	Auth Auth
+	// Progress receives what the remote reports while the push runs.
+	Progress io.Writer
+}
+
+// PushResult is what a push did to the remote branch.
+type PushResult struct {
+	Ref string
+	// Old is where the remote branch was at the last fetch, zero when it
+	// did not exist.
+	Old plumbing.Hash
+	New plumbing.Hash
+	// UpToDate is set when there was nothing to push.
+	UpToDate bool
+	// Forced is set when the push replaced commits on the remote branch.
+	Forced bool
+}
+
+// ErrNonFastForward is returned when the remote branch has commits the
+// pushed branch does not, or, with a lease, moved since the last fetch.
+type ErrNonFastForward struct {
+	Ref   string
+	Lease bool
+}
+
+func (e ErrNonFastForward) Error() string {
+	if e.Lease {
+		return fmt.Sprintf("%s changed on the remote since the last fetch; fetch and look at it before forcing", e.Ref)
+	}
+	return fmt.Sprintf("%s has commits on the remote that are not here; integrate them, or use --force-with-lease to replace them", e.Ref)
+}
+
+// ErrRemoteAuth is returned when a remote refuses the credentials, or
+// wants some and none were given.
+type ErrRemoteAuth struct {
+	Remote  string
+	Message string
+}
+
+func (e ErrRemoteAuth) Error() string {
+	return fmt.Sprintf("%s refused the credentials: %s", e.Remote, e.Message)
+}
+
+// PushRefSpecs builds the refspecs that push branch to remoteBranch on the
+// remote, with every tag when tags is set. force allows a non-fast-forward
+// update, as a leading + does for git.
+func PushRefSpecs(branch, remoteBranch string, force, tags bool) []config.RefSpec {
+	spec := plumbing.NewBranchReferenceName(branch).String() + ":" + plumbing.NewBranchReferenceName(remoteBranch).String()
+	if force {
+		spec = "+" + spec
+	}
+	specs := []config.RefSpec{config.RefSpec(spec)}
+	if tags {
+		specs = append(specs, config.RefSpec("refs/tags/*:refs/tags/*"))
+	}
+	return specs
+}
+
+// PushBranch pushes a local branch with go-git. Cancelling ctx aborts the
+// transfer.
+func (g *GitCLI) PushBranch(ctx context.Context, opts PushOptions) (PushResult, error) {
+	if opts.RemoteBranch == "" {
+		opts.RemoteBranch = opts.Branch
+	}
+	result := PushResult{Ref: opts.Remote + "/" + opts.RemoteBranch}
+
+	local, err := g.repo.Reference(plumbing.NewBranchReferenceName(opts.Branch), true)
+	if err != nil {
+		return result, ErrUnknownGitIssue{Message: fmt.Sprintf("branch %s not found", opts.Branch)}
+	}
+	result.New = local.Hash()
+	remote, url, auth, err := g.connect(opts.Remote, opts.Auth)
+	if err != nil {
+		return result, err
+	}
+
+	tracking := plumbing.NewRemoteReferenceName(opts.Remote, opts.RemoteBranch)
+	lease := false
+	if ref, err := g.repo.Reference(tracking, true); err == nil {
+		result.Old = ref.Hash()
+		// Without a remote-tracking branch there is nothing to hold the
+		// remote to, so the push stays a plain fast-forward.
+		lease = opts.ForceWithLease
+	}
+	if !result.Old.IsZero() && result.Old != result.New {
+		ancestors, err := g.ancestors(result.New, nil)
+		if err != nil {
+			return result, err
+		}
+		result.Forced = !ancestors[result.Old]
+	}
+
+	po := &git.PushOptions{
+		RemoteName:   opts.Remote,
+		RemoteURL:    url,
+		RefSpecs:     PushRefSpecs(opts.Branch, opts.RemoteBranch, lease || opts.Force, opts.Tags),
+		Auth:         auth,
+		Progress:     opts.Progress,
+		ProxyOptions: g.proxy(opts.Remote, url),
+	}
+	if lease {
+		// go-git's own lease looks for the remote-tracking branch by the
+		// local name and checks tags too, so the lease is a requirement on
+		// the remote branch instead.
+		po.RequireRemoteRefs = []config.RefSpec{
+			config.RefSpec(result.Old.String() + ":" + plumbing.NewBranchReferenceName(opts.RemoteBranch).String()),
+		}
+	}
+	// A push that may have reached the remote is not sent again: had it
+	// landed, the lease would refuse it the second time.
+	err = retry(ctx, opts.Progress, unreached, func() error { return remote.PushContext(ctx, po) })
+	g.settleCredential(auth, err)
+	switch {
+	case errors.Is(err, git.NoErrAlreadyUpToDate):
+		result.UpToDate, result.Forced = true, false
+	case err != nil && (strings.Contains(err.Error(), "non-fast-forward") || strings.Contains(err.Error(), "required to be")):
+		return result, ErrNonFastForward{Ref: result.Ref, Lease: lease}
+	case err != nil:
+		return result, remoteError(opts.Remote, auth, err)
+	}
+
+	if opts.SetUpstream {
+		// go-git would drop the settings it does not know from the branch
+		// sections when writing the config, so git writes these.
+		if err := g.gitConfig("branch."+opts.Branch+".remote", opts.Remote); err != nil {
+			return result, err
+		}
+		if err := g.gitConfig("branch."+opts.Branch+".merge", plumbing.NewBranchReferenceName(opts.RemoteBranch).String()); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v6/plumbing"
+)
+
+// RebaseAction is what an interactive rebase does with a commit, as named
+// in git's todo list.
+type RebaseAction string
+
+const (
+	RebasePick   RebaseAction = "pick"
+	RebaseReword RebaseAction = "reword"
+	RebaseSquash RebaseAction = "squash"
+	RebaseFixup  RebaseAction = "fixup"
+	RebaseDrop   RebaseAction = "drop"
+)
+
+// RebaseStep is one commit of an interactive rebase, in the order the
+// commits are to be replayed.
+type RebaseStep struct {
+	Action RebaseAction
+	Commit plumbing.Hash
+	// Message is the new message of a reworded commit.
+	Message string
+}
+
+// RebaseInteractive replays the commits of steps onto onto, in their order,
+// doing with each what its step says: a squashed commit's message is added
+// to the one before it, a fixed up one's dropped. Commits of the branch
+// that steps leave out are dropped too.
+//
+// A rebase that conflicts stops with ErrIntegrateConflict and the conflicts
+// in the working tree; continuing it once they are resolved carries out the
+// rest of steps, new messages included.
+func (g *GitCLI) RebaseInteractive(onto string, steps []RebaseStep) error {
+	// git's rewording asks an editor for the message, which continuing the
+	// rebase later will not have; the new message is put in with an amend
+	// straight after the pick instead, from a file the rebase can still
+	// read after stopping.
+	dir, err := g.GitPath("bgit-rebase")
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+
+	var todo strings.Builder
+	for _, s := range steps {
+		if s.Action != RebaseReword {
+			fmt.Fprintf(&todo, "%s %s\n", s.Action, s.Commit)
+			continue
+		}
+		file := filepath.Join(dir, s.Commit.String())
+		if err := os.WriteFile(file, []byte(s.Message), 0o644); err != nil {
+			return ErrUnknownGitIssue{Message: err.Error()}
+		}
+		// A pick that turns out empty is left out, and the amend must then
+		// not reword the commit before it.
+		fmt.Fprintf(&todo, "pick %s\nexec if [ \"$(git log -1 --format=%%B)\" = \"$(git log -1 --format=%%B %s)\" ]; then git commit --amend --allow-empty --no-verify --quiet --file %s; fi\n",
+			s.Commit, s.Commit, shellQuote(file))
+	}
+	todoFile := filepath.Join(dir, "todo")
+	if err := os.WriteFile(todoFile, []byte(todo.String()), 0o644); err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+
+	cmd := exec.Command("git", "rebase", "--interactive", onto)
+	cmd.Dir = g.path
+	// The todo git writes is replaced with ours, and the combined message
+	// of a squash taken as git writes it.
+	cmd.Env = append(os.Environ(), "GIT_SEQUENCE_EDITOR=cp "+shellQuote(todoFile), "GIT_EDITOR=true")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if conflicts, cerr := g.Conflicts(); cerr == nil && len(conflicts) > 0 {
+			return ErrIntegrateConflict{Upstream: onto, Rebase: true}
+		}
+		return ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+	}
+	_ = os.RemoveAll(dir)
+	return nil
+}
+
+// AbortRebase gives up a rebase that stopped, putting the branch back where
+// it was before it.
+func (g *GitCLI) AbortRebase() error {
+	cmd := exec.Command("git", "rebase", "--abort")
+	cmd.Dir = g.path
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+	}
+	return nil
+}
+
+// shellQuote quotes s for the shell git runs commands of the todo list and
+// editors with.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
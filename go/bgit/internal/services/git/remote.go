@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v6"
+)
+
+// ErrNoRemote is returned when a remote is not configured.
+type ErrNoRemote struct {
+	Name string
+}
+
+func (e ErrNoRemote) Error() string {
+	return fmt.Sprintf("git: no remote named %s", e.Name)
+}
+
+// RemoteURL returns the first URL configured for the named remote.
+func (g *GitCLI) RemoteURL(name string) (string, error) {
+	remote, err := g.repo.Remote(name)
+	if errors.Is(err, git.ErrRemoteNotFound) {
+		return "", ErrNoRemote{Name: name}
+	}
+	if err != nil {
+		return "", ErrUnknownGitIssue{Message: err.Error()}
+	}
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return "", ErrUnknownGitIssue{Message: "remote " + name + " has no URL"}
+	}
+	return urls[0], nil
+}
+
+// Remote is a configured remote and the URLs it fetches from.
+type Remote struct {
+	Name string
+	URLs []string
+	// Mirror is set for a remote SetRemoteMirror made a mirror.
+	Mirror bool
+	// SSHKey is the private key SetRemoteSSHKey set for the remote, as
+	// given; empty when it has none of its own.
+	SSHKey string
+}
+
+// mirrorKey is the remote.<name> config option marking a remote as a
+// mirror of the others.
+const mirrorKey = "bgit-mirror"
+
+// sshKeyKey is the remote.<name> config option naming the SSH key to sign
+// in to the remote with.
+const sshKeyKey = "bgit-ssh-key"
+
+// Remotes lists the configured remotes, sorted by name.
+func (g *GitCLI) Remotes() ([]Remote, error) {
+	remotes, err := g.repo.Remotes()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	cfg, err := g.repo.Config()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	section := cfg.Raw.Section("remote")
+	out := make([]Remote, 0, len(remotes))
+	for _, r := range remotes {
+		name := r.Config().Name
+		var mirror bool
+		var key string
+		if section.HasSubsection(name) {
+			mirror = section.Subsection(name).Option(mirrorKey) == "true"
+			key = section.Subsection(name).Option(sshKeyKey)
+		}
+		out = append(out, Remote{Name: name, URLs: r.Config().URLs, Mirror: mirror, SSHKey: key})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// SetRemoteMirror marks the named remote as a mirror, or no longer one. A
+// mirror is not pushed to on its own: it is kept in step with what is
+// pushed elsewhere.
+func (g *GitCLI) SetRemoteMirror(name string, mirror bool) error {
+	_, err := g.repo.Remote(name)
+	if errors.Is(err, git.ErrRemoteNotFound) {
+		return ErrNoRemote{Name: name}
+	}
+	if err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	key := "remote." + name + "." + mirrorKey
+	if mirror {
+		// go-git drops settings it does not know from remote sections when
+		// it writes the config, so git writes this one.
+		return g.gitConfig(key, "true")
+	}
+	if !g.hasConfig(key) {
+		return nil // not a mirror, and --unset fails on what is not set
+	}
+	return g.gitConfig("--unset", key)
+}
+
+// SetRemoteSSHKey sets the private key to sign in to the named remote with
+// over SSH, in place of the one Auth gives; an empty path unsets it.
+func (g *GitCLI) SetRemoteSSHKey(name, path string) error {
+	_, err := g.repo.Remote(name)
+	if errors.Is(err, git.ErrRemoteNotFound) {
+		return ErrNoRemote{Name: name}
+	}
+	if err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	key := "remote." + name + "." + sshKeyKey
+	if path != "" {
+		return g.gitConfig(key, path)
+	}
+	if !g.hasConfig(key) {
+		return nil
+	}
+	return g.gitConfig("--unset", key)
+}
+
+// remoteSSHKey is the SSH key set for the named remote, with a leading ~
+// standing for the home directory, or "" when it has none.
+func (g *GitCLI) remoteSSHKey(name string) string {
+	cfg, err := g.repo.Config()
+	if err != nil {
+		return ""
+	}
+	section := cfg.Raw.Section("remote")
+	if !section.HasSubsection(name) {
+		return ""
+	}
+	path := section.Subsection(name).Option(sshKeyKey)
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// hasConfig reports whether the repository's config sets key.
+func (g *GitCLI) hasConfig(key string) bool {
+	return g.gitConfig("--get", key) == nil
+}
@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"bytes"
+	"os/exec"
+
+	"github.com/go-git/go-git/v6/plumbing"
+)
+
+// ResetMode is how much of the repository Reset brings in line with the
+// commit it moves HEAD to.
+type ResetMode string
+
+const (
+	// ResetSoft only moves HEAD; the staging area and the working tree stay
+	// as they are.
+	ResetSoft ResetMode = "soft"
+	// ResetMixed moves HEAD and makes the staging area match the commit,
+	// leaving the working tree as it is.
+	ResetMixed ResetMode = "mixed"
+	// ResetHard moves HEAD and makes the staging area and the tracked files
+	// of the working tree match the commit. Untracked files stay.
+	ResetHard ResetMode = "hard"
+)
+
+// Reset moves the current branch, or a detached HEAD, to target, resetting
+// the staging area and the working tree as mode says.
+func (g *GitCLI) Reset(target plumbing.Hash, mode ResetMode) error {
+	// go-git writes neither ORIG_HEAD nor the reflog, and a reset with them
+	// can be undone, so git resets.
+	cmd := exec.Command("git", "reset", "-q", "--"+string(mode), target.String())
+	cmd.Dir = g.path
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+	}
+	return nil
+}
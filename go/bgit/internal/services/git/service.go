@@ -1,19 +1,33 @@
 package internal
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os/exec"
-	"strings"
+	"path/filepath"
 	"time"
 
 	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/cache"
+	graphfile "github.com/go-git/go-git/v6/plumbing/format/commitgraph"
 	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/go-git/go-git/v6/plumbing/object/commitgraph"
 )
 
 type GitCLI struct {
 	repo *git.Repository
 	path string
+	// objects holds the objects decoded from packfiles.
+	objects *cache.ObjectLRU
+	// nodes reads commits for walking the history, from the commit-graph
+	// when graph is one; generations says whether it has generation numbers.
+	nodes       commitgraph.CommitNodeIndex
+	graph       graphfile.Index
+	generations bool
 }
 
 type ErrNotAGitRepository struct {
@@ -32,8 +46,10 @@ func (e ErrUnknownGitIssue) Error() string {
 	return fmt.Sprintf("git: unknown git issue: %s", e.Message)
 }
 
-func NewGitClient(repoPath string) (*GitCLI, error) {
-	repo, err := git.PlainOpen(repoPath)
+// NewGitClient opens the repository at repoPath, read as opts says.
+func NewGitClient(repoPath string, opts Options) (*GitCLI, error) {
+	objects := opts.objectCache()
+	repo, err := openStorage(repoPath, objects)
 	if err != nil {
 		if errors.Is(err, git.ErrRepositoryNotExists) {
 			return nil, ErrNotAGitRepository{
@@ -46,7 +62,9 @@ func NewGitClient(repoPath string) (*GitCLI, error) {
 		}
 	}
 
-	return &GitCLI{repo: repo, path: repoPath}, nil
+	client := &GitCLI{repo: repo, path: repoPath, objects: objects}
+	client.loadCommitGraph()
+	return client, nil
 }
 
 func (g *GitCLI) StagedFiles() ([]string, error) {
@@ -64,70 +82,21 @@ func (g *GitCLI) StagedFiles() ([]string, error) {
 		}
 	}
 
-	var stagedFiles []string
-	for path, s := range status {
-		if s.Staging != git.Unmodified { // something staged
-			stagedFiles = append(stagedFiles, path)
-		}
-	}
-
-	return stagedFiles, nil
-}
-
-func (g *GitCLI) AddFiles(files []string) ([]string, error) {
-	workTree, err := g.repo.Worktree()
+	intents, err := g.intentToAddSet()
 	if err != nil {
-		return nil, ErrUnknownGitIssue{
-			Message: err.Error(),
-		}
+		return nil, err
 	}
 
 	var stagedFiles []string
-
-	for _, file := range files {
-		if _, addErr := workTree.Add(file); addErr != nil {
-			return nil, ErrUnknownGitIssue{
-				Message: addErr.Error(),
-			}
+	for path, s := range status {
+		if s.Staging != git.Unmodified && !intents[path] { // something staged
+			stagedFiles = append(stagedFiles, path)
 		}
-		stagedFiles = append(stagedFiles, file)
 	}
 
 	return stagedFiles, nil
 }
 
-func (g *GitCLI) AddAllFiles() ([]string, error) {
-	workTree, err := g.repo.Worktree()
-	if err != nil {
-		return nil, ErrUnknownGitIssue{
-			Message: err.Error(),
-		}
-	}
-
-	status, err := workTree.Status()
-	if err != nil {
-		return nil, ErrUnknownGitIssue{
-			Message: err.Error(),
-		}
-	}
-
-	if status.IsClean() {
-		return nil, errors.New("nothing to add")
-	}
-
-	err = workTree.AddWithOptions(&git.AddOptions{
-		All:  true,
-		Path: ".",
-	})
-	if err != nil {
-		return nil, ErrUnknownGitIssue{
-			Message: err.Error(),
-		}
-	}
-
-	return nil, nil
-}
-
 // Get all modified files in the working tree and index
 func (g *GitCLI) ModifiedFiles() ([]string, error) {
 	workTree, err := g.repo.Worktree()
@@ -253,82 +222,162 @@ func (g *GitCLI) UntrackedFiles() ([]string, error) {
 	return untrackedFiles, nil
 }
 
-func (g *GitCLI) GetStagedFilesDiff(stagedFiles []string) (string, error) {
-	var diff string
-	for _, file := range stagedFiles {
-		cmd := exec.Command("git", "diff", "--cached", file)
-		cmd.Dir = g.path
-		out, err := cmd.CombinedOutput()
-		if err != nil {
-			return "", ErrUnknownGitIssue{Message: err.Error()}
-		}
-
-		diff += string(out)
-	}
-
-	return diff, nil
+// CurrentBranch names the branch HEAD is on, or the abbreviated hash of
+// the commit when HEAD is detached.
+func (g *GitCLI) CurrentBranch() (string, error) {
+	name, _, err := g.HeadBranch()
+	return name, err
 }
 
-func (g *GitCLI) CurrentBranch() (string, error) {
+// HeadBranch names the branch HEAD is on. A detached HEAD is on none:
+// detached is set and name is the abbreviated hash of its commit.
+func (g *GitCLI) HeadBranch() (name string, detached bool, err error) {
 	headRef, err := g.repo.Head()
 	if err != nil {
-		return "", ErrUnknownGitIssue{
+		return "", false, ErrUnknownGitIssue{
 			Message: err.Error(),
 		}
 	}
-	name := headRef.Name().String()
-	if strings.HasPrefix(name, "refs/heads/") {
-		return strings.TrimPrefix(name, "refs/heads/"), nil
+	if headRef.Name().IsBranch() {
+		return headRef.Name().Short(), false, nil
 	}
-	return headRef.Hash().String()[:12], nil
+	return headRef.Hash().String()[:12], true, nil
 }
 
-func (g *GitCLI) Commit(message string) error {
+// CommitOptions says how Commit commits.
+type CommitOptions struct {
+	// Amend replaces the commit HEAD is at, keeping its parents and its
+	// author, with one of the staged changes on top of it.
+	Amend bool
+	// AuthorDate and CommitterDate date the commit in place of now; the
+	// AuthorDate of an amended commit replaces the one it was authored at.
+	AuthorDate    time.Time
+	CommitterDate time.Time
+}
+
+// Commit records the staged changes as a new commit authored by the
+// repository's configured user and returns the resulting commit object.
+// While a merge is in progress it is the merge commit, finishing it; with
+// options.Amend it replaces HEAD instead.
+func (g *GitCLI) Commit(message string, options CommitOptions) (*object.Commit, error) {
 	workTree, err := g.repo.Worktree()
 	if err != nil {
-		return ErrUnknownGitIssue{
+		return nil, ErrUnknownGitIssue{
 			Message: err.Error(),
 		}
 	}
 
 	repoConfig, err := g.repo.Config()
 	if err != nil {
-		return ErrUnknownGitIssue{
+		return nil, ErrUnknownGitIssue{
 			Message: err.Error(),
 		}
 	}
 
-	author := &object.Signature{
+	now := time.Now()
+	committer := &object.Signature{
 		Name:  repoConfig.Author.Name,
 		Email: repoConfig.Author.Email,
-		When:  time.Now(),
+		When:  now,
+	}
+	author := *committer
+	if !options.CommitterDate.IsZero() {
+		committer.When = options.CommitterDate
 	}
 
-	commitHash, err := workTree.Commit(message, &git.CommitOptions{
-		Author:    author,
-		Committer: author,
+	// What a merge in progress merges in are further parents.
+	merged, err := g.mergeParents()
+	if err != nil {
+		return nil, err
+	}
+	opts := &git.CommitOptions{
+		Author:    &author,
+		Committer: committer,
 		All:       false,
+		Amend:     options.Amend,
+	}
+	if options.Amend {
+		head, err := g.ResolveCommit("HEAD")
+		if err != nil {
+			return nil, err
+		}
+		author = head.Author
+		// Amending may only reword the commit.
+		opts.AllowEmptyCommits = true
+	}
+	if !options.AuthorDate.IsZero() {
+		author.When = options.AuthorDate
+	}
+	if len(merged) > 0 {
+		head, err := g.repo.Head()
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		opts.Parents = append([]plumbing.Hash{head.Hash()}, merged...)
+		// Merging may leave the tree as it was, and the merge still
+		// happened.
+		opts.AllowEmptyCommits = true
+	}
+
+	var commitHash plumbing.Hash
+	err = g.withoutIntentToAdd(func() error {
+		commitHash, err = workTree.Commit(message, opts)
+		return err
 	})
 	if err != nil {
-		return ErrUnknownGitIssue{
+		return nil, ErrUnknownGitIssue{
 			Message: err.Error(),
 		}
 	}
+	if len(merged) > 0 {
+		g.finishMerge()
+	}
 
 	commitObj, err := g.repo.CommitObject(commitHash)
 	if err != nil {
-		return ErrUnknownGitIssue{
+		return nil, ErrUnknownGitIssue{
 			Message: err.Error(),
 		}
 	}
 
-	fmt.Println("✅ Commit created successfully!")
-	fmt.Printf("  📝 Hash: %s\n", commitObj.Hash.String()[:7])
-	fmt.Printf("  👤 Author: %s <%s>\n", commitObj.Author.Name, commitObj.Author.Email)
-	if commitObj.Committer != commitObj.Author {
-		fmt.Printf("  ✉️  Committer: %s <%s>\n", commitObj.Committer.Name, commitObj.Committer.Email)
+	return commitObj, nil
+}
+
+// WorktreeFile reads the file at name, relative to the root of the working
+// tree. A missing file is reported with an error satisfying
+// errors.Is(err, fs.ErrNotExist).
+func (g *GitCLI) WorktreeFile(name string) ([]byte, error) {
+	workTree, err := g.repo.Worktree()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	f, err := workTree.Filesystem.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return data, nil
+}
+
+// GitPath resolves a path inside the repository's git directory, as
+// git rev-parse --git-path does (so worktrees get their own).
+func (g *GitCLI) GitPath(name string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-path", name)
+	cmd.Dir = g.path
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+	}
+	p := string(bytes.TrimSpace(out))
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(g.path, p)
 	}
-	fmt.Printf("  🕐 Date: %s\n", commitObj.Author.When.Format(time.RFC1123))
-	fmt.Printf("  📄 Message: %s\n", message)
-	return nil
+	return p, nil
 }
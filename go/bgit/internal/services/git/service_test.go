@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/cache"
+	"github.com/go-git/go-git/v6/plumbing/object"
+)
+
+// testRepo is a repository on disk for the tests. Its commits are written
+// straight to the object store, all with the empty tree and each a minute
+// after the one before, so they are quick to make in any shape.
+type testRepo struct {
+	t    *testing.T
+	dir  string
+	repo *git.Repository
+	tree plumbing.Hash
+	when time.Time
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &testRepo{t: t, dir: dir, repo: repo, when: time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC)}
+	r.tree = r.store(&object.Tree{})
+	return r
+}
+
+func (r *testRepo) store(o interface {
+	Encode(plumbing.EncodedObject) error
+}) plumbing.Hash {
+	r.t.Helper()
+	obj := r.repo.Storer.NewEncodedObject()
+	if err := o.Encode(obj); err != nil {
+		r.t.Fatal(err)
+	}
+	h, err := r.repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	return h
+}
+
+// commit writes a commit with message on top of parents and returns it.
+func (r *testRepo) commit(message string, parents ...plumbing.Hash) plumbing.Hash {
+	r.t.Helper()
+	r.when = r.when.Add(time.Minute)
+	sig := object.Signature{Name: "Alice Example", Email: "alice@example.com", When: r.when}
+	return r.store(&object.Commit{
+		Author:       sig,
+		Committer:    sig,
+		Message:      message + "\n",
+		TreeHash:     r.tree,
+		ParentHashes: parents,
+	})
+}
+
+// branch points the branch name at h; master is the one HEAD is on.
+func (r *testRepo) branch(name string, h plumbing.Hash) {
+	r.t.Helper()
+	if err := r.repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(name), h)); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// client opens the repository as the commands do.
+func (r *testRepo) client() *GitCLI {
+	r.t.Helper()
+	g, err := NewGitClient(r.dir, Options{})
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	r.t.Cleanup(func() { _ = g.Close() })
+	return g
+}
+
+func TestNewGitClientObjectCache(t *testing.T) {
+	r := newTestRepo(t)
+	r.commit("initial")
+	tests := []struct {
+		size, want cache.FileSize
+	}{
+		{0, DefaultObjectCacheSize},
+		{cache.MiByte, cache.MiByte},
+	}
+	for _, tt := range tests {
+		g, err := NewGitClient(r.dir, Options{ObjectCacheSize: tt.size})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := g.objects.MaxSize; got != tt.want {
+			t.Errorf("ObjectCacheSize %d: the cache holds up to %d bytes, want %d", tt.size, got, tt.want)
+		}
+		if err := g.Close(); err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v6/plumbing"
+)
+
+// Stash is an entry of the stash, newest first: stash@{0} is the last one
+// saved.
+type Stash struct {
+	Index int
+	Hash  plumbing.Hash
+	// Branch is the branch the changes were saved on, empty when HEAD was
+	// detached.
+	Branch string
+	// Message is what git recorded, like "On main: half-done parser" or
+	// "WIP on main: 1a2b3c4 Add parser" when none was given.
+	Message string
+	When    time.Time
+}
+
+// Ref is how git names the entry, like stash@{0}.
+func (s Stash) Ref() string {
+	return fmt.Sprintf("stash@{%d}", s.Index)
+}
+
+// StashOptions says what StashSave saves.
+type StashOptions struct {
+	Message string
+	// Untracked saves untracked files too, and removes them like the
+	// tracked changes.
+	Untracked bool
+	// KeepIndex leaves the staged changes in place as well as saving them.
+	KeepIndex bool
+	// Paths limits the stash to these paths; empty saves every change.
+	Paths []string
+}
+
+// ErrNothingToStash is returned when there are no changes to save.
+type ErrNothingToStash struct{}
+
+func (ErrNothingToStash) Error() string {
+	return "no local changes to stash"
+}
+
+// ErrNoStash is returned when the stash has no entry at Index.
+type ErrNoStash struct {
+	Index int
+}
+
+func (e ErrNoStash) Error() string {
+	if e.Index == 0 {
+		return "the stash is empty"
+	}
+	return fmt.Sprintf("there is no stash@{%d}", e.Index)
+}
+
+// ErrStashConflict is returned when applying a stash stops on conflicts,
+// which are left in the working tree to resolve. The entry stays in the
+// stash even when it was being popped.
+type ErrStashConflict struct {
+	Ref string
+}
+
+func (e ErrStashConflict) Error() string {
+	return fmt.Sprintf("applying %s stopped on conflicts; it was kept in the stash", e.Ref)
+}
+
+// Stashes lists the stash, newest first, read from the reflog of
+// refs/stash.
+func (g *GitCLI) Stashes() ([]Stash, error) {
+	// go-git reads no reflogs, and has no stash, so git lists it.
+	out, err := g.stash("list", "--format=%H%x1f%ct%x1f%gs")
+	if err != nil {
+		return nil, err
+	}
+	var stashes []Stash
+	for i, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.SplitN(line, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		secs, _ := strconv.ParseInt(fields[1], 10, 64)
+		stashes = append(stashes, Stash{
+			Index:   i,
+			Hash:    plumbing.NewHash(fields[0]),
+			Branch:  stashBranch(fields[2]),
+			Message: fields[2],
+			When:    time.Unix(secs, 0),
+		})
+	}
+	return stashes, nil
+}
+
+// stashBranch reads the branch out of a stash message, which git starts
+// with "On <branch>:" or "WIP on <branch>:"; "(no branch)" stands for a
+// detached HEAD.
+func stashBranch(message string) string {
+	rest, ok := strings.CutPrefix(message, "WIP on ")
+	if !ok {
+		if rest, ok = strings.CutPrefix(message, "On "); !ok {
+			return ""
+		}
+	}
+	branch, _, ok := strings.Cut(rest, ": ")
+	if !ok || branch == "(no branch)" {
+		return ""
+	}
+	return branch
+}
+
+// StashSave saves the changes in the index and the working tree as a new
+// stash@{0} and reverts them to HEAD. Files added with add -N are new files
+// to the stash: they stay as they are unless Untracked saves them too.
+func (g *GitCLI) StashSave(opts StashOptions) (Stash, error) {
+	before, _ := g.repo.Reference(plumbing.ReferenceName("refs/stash"), true)
+
+	args := []string{"push"}
+	if opts.Message != "" {
+		args = append(args, "--message", opts.Message)
+	}
+	if opts.Untracked {
+		args = append(args, "--include-untracked")
+	}
+	if opts.KeepIndex {
+		args = append(args, "--keep-index")
+	}
+	if len(opts.Paths) > 0 {
+		args = append(append(args, "--"), opts.Paths...)
+	}
+	// git refuses to stash intent-to-add entries.
+	err := g.withoutIntentToAdd(func() error {
+		_, err := g.stash(args...)
+		return err
+	})
+	if err != nil {
+		return Stash{}, err
+	}
+
+	// git succeeds without saving anything when nothing changed, which
+	// shows as refs/stash not moving.
+	after, err := g.repo.Reference(plumbing.ReferenceName("refs/stash"), true)
+	if err != nil || (before != nil && before.Hash() == after.Hash()) {
+		return Stash{}, ErrNothingToStash{}
+	}
+	stashes, err := g.Stashes()
+	if err != nil {
+		return Stash{}, err
+	}
+	if len(stashes) == 0 {
+		return Stash{}, ErrNothingToStash{}
+	}
+	return stashes[0], nil
+}
+
+// StashApply applies the changes of stash@{index} to the working tree, and
+// with pop drops the entry once they applied cleanly. With restoreIndex the
+// changes that were staged are staged again.
+func (g *GitCLI) StashApply(index int, pop, restoreIndex bool) (Stash, error) {
+	entry, err := g.stashEntry(index)
+	if err != nil {
+		return Stash{}, err
+	}
+	args := []string{"apply"}
+	if pop {
+		args[0] = "pop"
+	}
+	if restoreIndex {
+		args = append(args, "--index")
+	}
+	// As in StashSave, git refuses intent-to-add entries.
+	err = g.withoutIntentToAdd(func() error {
+		_, err := g.stash(append(args, entry.Ref())...)
+		return err
+	})
+	if err != nil {
+		if conflicts, cerr := g.Conflicts(); cerr == nil && len(conflicts) > 0 {
+			return entry, ErrStashConflict{Ref: entry.Ref()}
+		}
+		return entry, err
+	}
+	return entry, nil
+}
+
+// StashDrop deletes stash@{index}; the entries after it move up by one.
+func (g *GitCLI) StashDrop(index int) (Stash, error) {
+	entry, err := g.stashEntry(index)
+	if err != nil {
+		return Stash{}, err
+	}
+	if _, err := g.stash("drop", entry.Ref()); err != nil {
+		return entry, err
+	}
+	return entry, nil
+}
+
+// stashEntry looks up stash@{index}.
+func (g *GitCLI) stashEntry(index int) (Stash, error) {
+	stashes, err := g.Stashes()
+	if err != nil {
+		return Stash{}, err
+	}
+	if index < 0 || index >= len(stashes) {
+		return Stash{}, ErrNoStash{Index: index}
+	}
+	return stashes[index], nil
+}
+
+// stash runs git stash with args and returns its standard output.
+func (g *GitCLI) stash(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"stash"}, args...)...)
+	cmd.Dir = g.path
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := bytes.TrimSpace(stderr.Bytes())
+		if len(msg) == 0 {
+			msg = bytes.TrimSpace(out)
+		}
+		return "", ErrUnknownGitIssue{Message: string(msg)}
+	}
+	return string(out), nil
+}
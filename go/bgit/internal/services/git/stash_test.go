@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// gitRepo is a repository made with git itself, for the operations bgit
+// hands to git: a committed file, tracked, with a change on top.
+func gitRepo(t *testing.T) (dir string, git func(args ...string) string) {
+	t.Helper()
+	dir = t.TempDir()
+	for _, v := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(v, "Alice Example")
+	}
+	for _, v := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(v, "alice@example.com")
+	}
+	git = func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return string(out)
+	}
+	git("init", "--quiet")
+	write(t, dir, "tracked.txt", "one\n")
+	git("add", "tracked.txt")
+	git("commit", "--quiet", "-m", "initial")
+	write(t, dir, "tracked.txt", "one\ntwo\n")
+	return dir, git
+}
+
+func write(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStashSaveIntentToAdd(t *testing.T) {
+	tests := []struct {
+		name      string
+		untracked bool
+		// kept says whether the new file stays behind, still intent-to-add.
+		kept bool
+	}{
+		{"tracked changes only", false, true},
+		{"with untracked files", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, git := gitRepo(t)
+			write(t, dir, "new.txt", "new\n")
+			git("add", "--intent-to-add", "new.txt")
+
+			g, err := NewGitClient(dir, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer g.Close()
+			if _, err := g.StashSave(StashOptions{Untracked: tt.untracked}); err != nil {
+				t.Fatalf("StashSave: %v", err)
+			}
+
+			if got := git("status", "--porcelain"); tt.kept && got != " A new.txt\n" || !tt.kept && got != "" {
+				t.Errorf("status after the stash = %q", got)
+			}
+			intents, err := g.IntentToAddFiles()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []string{"new.txt"}; tt.kept != slices.Equal(intents, want) {
+				t.Errorf("intent-to-add after the stash = %q, want kept %v", intents, tt.kept)
+			}
+
+			if _, err := g.StashApply(0, true, false); err != nil {
+				t.Fatalf("StashApply: %v", err)
+			}
+			for name, want := range map[string]string{"tracked.txt": "one\ntwo\n", "new.txt": "new\n"} {
+				if data, err := os.ReadFile(filepath.Join(dir, name)); err != nil || string(data) != want {
+					t.Errorf("%s after popping = %q, %v; want %q", name, data, err, want)
+				}
+			}
+		})
+	}
+}
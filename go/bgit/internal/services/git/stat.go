@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v6/plumbing"
+	fdiff "github.com/go-git/go-git/v6/plumbing/format/diff"
+	"github.com/go-git/go-git/v6/plumbing/object"
+)
+
+// FileStat is the size of the change to one file. Renames are reported as
+// "old => new". Binary files have no line counts.
+type FileStat struct {
+	Path       string
+	Insertions int
+	Deletions  int
+	Binary     bool
+}
+
+// ResolveCommit looks up the commit a revision (hash, branch, tag, HEAD~2...)
+// points at.
+func (g *GitCLI) ResolveCommit(rev string) (*object.Commit, error) {
+	hash, err := g.repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: fmt.Sprintf("%s: %s", rev, err)}
+	}
+	commit, err := g.repo.CommitObject(*hash)
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return commit, nil
+}
+
+// CommitPatch is the change a commit made relative to its first parent, or
+// to the empty tree for a root commit.
+func (g *GitCLI) CommitPatch(c *object.Commit) (*object.Patch, error) {
+	to, err := c.Tree()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	from := &object.Tree{}
+	if c.NumParents() > 0 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		if from, err = parent.Tree(); err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+	}
+
+	patch, err := from.Patch(to)
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return patch, nil
+}
+
+// ChangedPaths lists the files a commit changed against its first parent,
+// comparing trees only, which is much cheaper than a patch.
+func (g *GitCLI) ChangedPaths(c *object.Commit) ([]string, error) {
+	to, err := c.Tree()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	from := &object.Tree{}
+	if c.NumParents() > 0 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+		if from, err = parent.Tree(); err != nil {
+			return nil, ErrUnknownGitIssue{Message: err.Error()}
+		}
+	}
+
+	changes, err := object.DiffTree(from, to)
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	paths := make([]string, 0, len(changes))
+	for _, ch := range changes {
+		if ch.To.Name != "" {
+			paths = append(paths, ch.To.Name)
+		} else {
+			paths = append(paths, ch.From.Name)
+		}
+	}
+	return paths, nil
+}
+
+// CommitStats counts the lines a commit inserted and deleted in each file.
+func (g *GitCLI) CommitStats(c *object.Commit) ([]FileStat, error) {
+	patch, err := g.CommitPatch(c)
+	if err != nil {
+		return nil, err
+	}
+	return PatchStats(patch), nil
+}
+
+// PatchStats counts inserted and deleted lines per file of a patch, of a
+// commit or from DiffPatch. Unlike go-git's own Stats, binary files are kept
+// (flagged) rather than dropped.
+func PatchStats(patch fdiff.Patch) []FileStat {
+	var stats []FileStat
+	for _, fp := range patch.FilePatches() {
+		from, to := fp.Files()
+		var st FileStat
+		switch {
+		case from == nil:
+			st.Path = to.Path()
+		case to == nil:
+			st.Path = from.Path()
+		case from.Path() != to.Path():
+			st.Path = from.Path() + " => " + to.Path()
+		default:
+			st.Path = from.Path()
+		}
+
+		if fp.IsBinary() {
+			st.Binary = true
+			stats = append(stats, st)
+			continue
+		}
+		for _, chunk := range fp.Chunks() {
+			n := countLines(chunk.Content())
+			switch chunk.Type() {
+			case fdiff.Add:
+				st.Insertions += n
+			case fdiff.Delete:
+				st.Deletions += n
+			}
+		}
+		if st.Insertions+st.Deletions > 0 || from == nil || to == nil || from.Path() != to.Path() {
+			stats = append(stats, st) // mode-only changes are left out, as git does
+		}
+	}
+	return stats
+}
+
+func countLines(s string) int {
+	if s == "" {
+		return 0
+	}
+	n := strings.Count(s, "\n")
+	if s[len(s)-1] != '\n' {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"errors"
+
+	"github.com/go-git/go-billy/v6"
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing/cache"
+	"github.com/go-git/go-git/v6/storage/filesystem"
+)
+
+// DefaultObjectCacheSize bounds the objects go-git keeps decoded between
+// reads. go-git's own default is 96 MiB; walking the history of a large
+// repository fills all of it, so bgit keeps less.
+const DefaultObjectCacheSize = 32 * cache.MiByte
+
+// largeObjectThreshold is the size above which a packed object is read from
+// its packfile each time it is used rather than held in memory, so that
+// diffing a large file does not hold it twice.
+const largeObjectThreshold = 8 * int64(cache.MiByte)
+
+// Options says how NewGitClient reads the repository. The zero value uses
+// the default object cache.
+type Options struct {
+	// ObjectCacheSize bounds the object cache; zero picks
+	// DefaultObjectCacheSize.
+	ObjectCacheSize cache.FileSize
+}
+
+// objectCache returns the object cache opts asks for.
+func (opts Options) objectCache() *cache.ObjectLRU {
+	if opts.ObjectCacheSize == 0 {
+		return cache.NewObjectLRU(DefaultObjectCacheSize)
+	}
+	return cache.NewObjectLRU(opts.ObjectCacheSize)
+}
+
+// openStorage opens the repository at repoPath, keeping decoded objects in
+// objects.
+func openStorage(repoPath string, objects cache.Object) (*git.Repository, error) {
+	// PlainOpen finds the .git directory, which in a linked worktree is a
+	// file pointing into the main repository's, sharing its objects and
+	// branches; its storage is then replaced by one with these options.
+	repo, err := git.PlainOpenWithOptions(repoPath, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return nil, err
+	}
+	fsStorage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return repo, nil
+	}
+	var worktree billy.Filesystem
+	if wt, err := repo.Worktree(); err == nil {
+		worktree = wt.Filesystem
+	} else if !errors.Is(err, git.ErrIsBareRepository) {
+		return nil, err
+	}
+	storage := filesystem.NewStorageWithOptions(fsStorage.Filesystem(), objects, filesystem.Options{
+		LargeObjectThreshold: largeObjectThreshold,
+	})
+	return git.Open(storage, worktree)
+}
+
+// Close releases the files the storage and the commit-graph hold open. The
+// client is not usable afterwards.
+func (g *GitCLI) Close() error {
+	if g.graph != nil {
+		if err := g.graph.Close(); err != nil {
+			return ErrUnknownGitIssue{Message: err.Error()}
+		}
+	}
+	if storage, ok := g.repo.Storer.(*filesystem.Storage); ok {
+		if err := storage.Close(); err != nil {
+			return ErrUnknownGitIssue{Message: err.Error()}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+	"github.com/go-git/go-git/v6/plumbing/object/commitgraph"
+)
+
+// Tag is a tag and the commit it points at, annotated tags peeled.
+type Tag struct {
+	Name   string
+	Commit plumbing.Hash
+	// Annotated is set for a tag object, which has a message and a tagger,
+	// as opposed to a lightweight tag, a bare name for a commit.
+	Annotated bool
+	Message   string
+	// When is when an annotated tag was made, or when the commit of a
+	// lightweight one was.
+	When time.Time
+}
+
+// ErrTagExists is returned when creating a tag whose name is taken.
+type ErrTagExists struct {
+	Name string
+}
+
+func (e ErrTagExists) Error() string {
+	return fmt.Sprintf("tag %s already exists", e.Name)
+}
+
+// ErrNoTag is returned when the named tag does not exist.
+type ErrNoTag struct {
+	Name string
+}
+
+func (e ErrNoTag) Error() string {
+	return fmt.Sprintf("there is no tag %s", e.Name)
+}
+
+// Tags lists the tags that point at commits, sorted by name.
+func (g *GitCLI) Tags() ([]Tag, error) {
+	iter, err := g.repo.Tags()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	var tags []Tag
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if tag, ok := g.tagOf(ref); ok {
+			tags = append(tags, tag)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+// tagOf reads the tag ref names. ok is false for a tag of a tree or blob.
+func (g *GitCLI) tagOf(ref *plumbing.Reference) (Tag, bool) {
+	tag := Tag{Name: ref.Name().Short(), Commit: ref.Hash()}
+	if obj, err := g.repo.TagObject(ref.Hash()); err == nil {
+		c, err := obj.Commit()
+		if err != nil {
+			return Tag{}, false
+		}
+		tag.Commit, tag.Annotated, tag.Message, tag.When = c.Hash, true, strings.TrimRight(obj.Message, "\n"), obj.Tagger.When
+		return tag, true
+	}
+	c, err := g.repo.CommitObject(ref.Hash())
+	if err != nil {
+		return Tag{}, false
+	}
+	tag.When = c.Committer.When
+	return tag, true
+}
+
+// CreateTag creates a tag on target: annotated, tagged by the repository's
+// configured user, when there is a message, and lightweight when message is
+// empty.
+func (g *GitCLI) CreateTag(name string, target plumbing.Hash, message string) error {
+	var opts *git.CreateTagOptions
+	if message != "" {
+		repoConfig, err := g.repo.Config()
+		if err != nil {
+			return ErrUnknownGitIssue{Message: err.Error()}
+		}
+		opts = &git.CreateTagOptions{
+			Tagger: &object.Signature{
+				Name:  repoConfig.Author.Name,
+				Email: repoConfig.Author.Email,
+				When:  time.Now(),
+			},
+			Message: message,
+		}
+	}
+	_, err := g.repo.CreateTag(name, target, opts)
+	if errors.Is(err, git.ErrTagExists) {
+		return ErrTagExists{Name: name}
+	}
+	if err != nil {
+		return ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return nil
+}
+
+// DeleteTag deletes the tag name and returns it as it was. Only the local
+// tag goes; a pushed one stays on the remote.
+func (g *GitCLI) DeleteTag(name string) (Tag, error) {
+	ref, err := g.repo.Tag(name)
+	if errors.Is(err, git.ErrTagNotFound) {
+		return Tag{}, ErrNoTag{Name: name}
+	}
+	if err != nil {
+		return Tag{}, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	tag, _ := g.tagOf(ref)
+	if err := g.repo.DeleteTag(name); err != nil {
+		return Tag{}, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return tag, nil
+}
+
+// CommitsSince lists the commits reachable from HEAD but not from since,
+// newest first. A zero since lists the whole history.
+func (g *GitCLI) CommitsSince(since plumbing.Hash) ([]*object.Commit, error) {
+	head, err := g.repo.Head()
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return g.CommitsBetween(since, head.Hash())
+}
+
+// CommitsBetween lists the commits reachable from until but not from since,
+// newest first. A zero since lists the whole history of until.
+func (g *GitCLI) CommitsBetween(since, until plumbing.Hash) ([]*object.Commit, error) {
+	exclude := map[plumbing.Hash]bool{}
+	if !since.IsZero() {
+		var err error
+		if exclude, err = g.ancestors(since, func(plumbing.Hash) bool { return false }); err != nil {
+			return nil, err
+		}
+	}
+
+	var commits []*object.Commit
+	err := g.walkByDate(until, func(node commitgraph.CommitNode) error {
+		if exclude[node.ID()] {
+			return nil
+		}
+		c, err := node.Commit()
+		if err != nil {
+			return err
+		}
+		commits = append(commits, c)
+		return nil
+	})
+	if err != nil {
+		return nil, ErrUnknownGitIssue{Message: err.Error()}
+	}
+	return commits, nil
+}
+
+// Push pushes refspecs to remote in one go: either all of them update or
+// none does. git does the pushing so credential helpers and SSH agents work
+// as usual; it is not allowed to prompt, as there may be no terminal.
+func (g *GitCLI) Push(remote string, refspecs ...string) error {
+	cmd := exec.Command("git", append([]string{"push", "--atomic", remote}, refspecs...)...)
+	cmd.Dir = g.path
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return ErrUnknownGitIssue{Message: string(bytes.TrimSpace(out))}
+	}
+	return nil
+}
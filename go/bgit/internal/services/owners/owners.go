@@ -0,0 +1,194 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Locations are where a CODEOWNERS file is looked for, in the order GitHub
+// looks; the first one found is used.
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule is one line of a CODEOWNERS file: a pattern and the users
+// (@login), teams (@org/team) or email addresses that own what it matches.
+// A rule with no owners leaves what it matches unowned.
+type Rule struct {
+	Pattern string
+	Owners  []string
+	Line    int
+	re      *regexp.Regexp
+}
+
+// Rules are the rules of a CODEOWNERS file. The last rule that matches a
+// path decides its owners.
+type Rules []Rule
+
+// ErrBadRule is returned for a CODEOWNERS line that cannot be used.
+type ErrBadRule struct {
+	Line    int
+	Message string
+}
+
+func (e ErrBadRule) Error() string {
+	return fmt.Sprintf("CODEOWNERS line %d: %s", e.Line, e.Message)
+}
+
+// Parse reads a CODEOWNERS file. Blank lines and comments are skipped.
+func Parse(r io.Reader) (Rules, error) {
+	var rules Rules
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		re, err := compile(fields[0])
+		if err != nil {
+			return nil, ErrBadRule{Line: n, Message: err.Error()}
+		}
+		rules = append(rules, Rule{Pattern: fields[0], Owners: fields[1:], Line: n, re: re})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// compile turns a CODEOWNERS pattern, which follows the rules of
+// .gitignore, into a regular expression on slash-separated paths. A
+// pattern with a slash anywhere but at its end is anchored at the root of
+// the repository; one without matches at any depth. Either matches the
+// contents of a directory it names.
+func compile(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, "!") || strings.Contains(pattern, "[") {
+		return nil, fmt.Errorf("%s: negation and character ranges are not supported", pattern)
+	}
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.Trim(pattern, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case strings.HasSuffix(p, "/*"):
+		// docs/* owns the files in docs but not those in its subdirectories.
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
+
+// Match returns the rule that decides who owns path, the last one matching
+// it.
+func (rs Rules) Match(path string) (Rule, bool) {
+	for i := len(rs) - 1; i >= 0; i-- {
+		if rs[i].re.MatchString(path) {
+			return rs[i], true
+		}
+	}
+	return Rule{}, false
+}
+
+// Area is a group of changed files that the same rule gives owners to.
+// Files no rule owns make up an area with no pattern and no owners.
+type Area struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+	Files   []string `json:"files"`
+}
+
+// Areas groups paths by the rule that owns them, in the order the rules
+// appear in the file, with the unowned files last.
+func (rs Rules) Areas(paths []string) []Area {
+	byLine := map[int]*Area{}
+	var unowned *Area
+	for _, p := range paths {
+		rule, ok := rs.Match(p)
+		if !ok || len(rule.Owners) == 0 {
+			if unowned == nil {
+				unowned = &Area{Owners: []string{}}
+			}
+			unowned.Files = append(unowned.Files, p)
+			continue
+		}
+		a := byLine[rule.Line]
+		if a == nil {
+			a = &Area{Pattern: rule.Pattern, Owners: rule.Owners}
+			byLine[rule.Line] = a
+		}
+		a.Files = append(a.Files, p)
+	}
+
+	lines := make([]int, 0, len(byLine))
+	for line := range byLine {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+	areas := make([]Area, 0, len(lines)+1)
+	for _, line := range lines {
+		areas = append(areas, *byLine[line])
+	}
+	if unowned != nil {
+		areas = append(areas, *unowned)
+	}
+	return areas
+}
+
+// Reviewers lists, once each and sorted, the owners of areas: those whose
+// review a pull request of the change will require.
+func Reviewers(areas []Area) []string {
+	seen := map[string]bool{}
+	var reviewers []string
+	for _, a := range areas {
+		for _, o := range a.Owners {
+			if !seen[o] {
+				seen[o] = true
+				reviewers = append(reviewers, o)
+			}
+		}
+	}
+	sort.Strings(reviewers)
+	return reviewers
+}
+
+// Owned counts the areas that have owners.
+func Owned(areas []Area) int {
+	n := 0
+	for _, a := range areas {
+		if len(a.Owners) > 0 {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func parse(t *testing.T, file string) Rules {
+	t.Helper()
+	rules, err := Parse(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rules
+}
+
+// owners is who owns path under rules, nil for no one.
+func owners(rules Rules, path string) []string {
+	rule, ok := rules.Match(path)
+	if !ok {
+		return nil
+	}
+	return rule.Owners
+}
+
+func TestParseComments(t *testing.T) {
+	rules := parse(t, `# Owners of everything
+*       @org/core   # the default
+
+  # indented comment
+docs/   @writer
+file#1  @hash
+`)
+	if len(rules) != 3 {
+		t.Fatalf("parsed %d rules, want 3: %+v", len(rules), rules)
+	}
+	if !slices.Equal(rules[0].Owners, []string{"@org/core"}) || rules[0].Line != 2 {
+		t.Errorf("the trailing comment was taken as an owner, or the line is wrong: %+v", rules[0])
+	}
+	// Only " #" starts a comment; one inside a pattern is part of it.
+	if rules[2].Pattern != "file#1" || rules[2].Line != 6 {
+		t.Errorf("rule %+v, want the pattern file#1 on line 6", rules[2])
+	}
+}
+
+func TestParseUnsupported(t *testing.T) {
+	for _, file := range []string{
+		"*.go @gopher\n!vendor/ @nobody\n",
+		"*.go @gopher\n[abc].md @docs\n",
+	} {
+		_, err := Parse(strings.NewReader(file))
+		var bad ErrBadRule
+		if !errors.As(err, &bad) || bad.Line != 2 {
+			t.Errorf("Parse(%q) = %v, want ErrBadRule on line 2", file, err)
+		}
+	}
+}
+
+func TestMatchLastRuleWins(t *testing.T) {
+	rules := parse(t, `*                @org/core
+*.go             @gopher
+/internal/ui/    @org/ui
+/internal/ui/legacy.go
+`)
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"README.md", []string{"@org/core"}},
+		{"cmd/root.go", []string{"@gopher"}},
+		// A later rule wins over an earlier one, however broad.
+		{"internal/ui/status.go", []string{"@org/ui"}},
+		{"internal/ui/theme.css", []string{"@org/ui"}},
+		// A rule with no owners leaves the path unowned.
+		{"internal/ui/legacy.go", []string{}},
+	}
+	for _, tt := range tests {
+		if got := owners(rules, tt.path); !slices.Equal(got, tt.want) || got == nil {
+			t.Errorf("owners of %s = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMatchAnchoring(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		// Without a slash, at any depth.
+		{"*.js", "app.js", true},
+		{"*.js", "web/src/app.js", true},
+		{"build", "build/out.txt", true},
+		{"build", "web/build/out.txt", true},
+		// A trailing slash only makes it a directory.
+		{"logs/", "logs/today.log", true},
+		{"logs/", "services/api/logs/today.log", true},
+		{"logs/", "logs", false},
+		// A slash elsewhere anchors it at the root.
+		{"/build", "build/out.txt", true},
+		{"/build", "web/build/out.txt", false},
+		{"docs/api", "docs/api/index.md", true},
+		{"docs/api", "site/docs/api/index.md", false},
+		// docs/* is the files in docs, not those further down.
+		{"docs/*", "docs/index.md", true},
+		{"docs/*", "docs/api/index.md", false},
+		{"**/testdata", "internal/ui/testdata/x.golden", true},
+		{"/internal/**/*.go", "internal/ui/view.go", true},
+		{"/internal/**/*.go", "cmd/root.go", false},
+		{"?.txt", "a.txt", true},
+		{"?.txt", "ab.txt", false},
+	}
+	for _, tt := range tests {
+		rules := parse(t, tt.pattern+" @owner\n")
+		if _, got := rules.Match(tt.path); got != tt.want {
+			t.Errorf("%q matches %s = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAreas(t *testing.T) {
+	rules := parse(t, `*.go        @gopher
+/docs/      @writer @editor
+/vendor/
+`)
+	areas := rules.Areas([]string{"docs/a.md", "main.go", "vendor/x/y.go", "docs/b.md", "Makefile"})
+	want := []Area{
+		{Pattern: "*.go", Owners: []string{"@gopher"}, Files: []string{"main.go"}},
+		{Pattern: "/docs/", Owners: []string{"@writer", "@editor"}, Files: []string{"docs/a.md", "docs/b.md"}},
+		{Owners: []string{}, Files: []string{"vendor/x/y.go", "Makefile"}},
+	}
+	if len(areas) != len(want) {
+		t.Fatalf("Areas = %+v, want %+v", areas, want)
+	}
+	for i := range want {
+		if areas[i].Pattern != want[i].Pattern || !slices.Equal(areas[i].Owners, want[i].Owners) || !slices.Equal(areas[i].Files, want[i].Files) {
+			t.Errorf("area %d = %+v, want %+v", i, areas[i], want[i])
+		}
+	}
+	if got := Reviewers(areas); !slices.Equal(got, []string{"@editor", "@gopher", "@writer"}) {
+		t.Errorf("Reviewers = %v", got)
+	}
+	if got := Owned(areas); got != 2 {
+		t.Errorf("Owned = %d, want 2", got)
+	}
+}
@@ -0,0 +1,296 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidVersion is a version that is not semantic (v1.2.3).
+type ErrInvalidVersion struct {
+	Version string
+}
+
+func (e ErrInvalidVersion) Error() string {
+	return fmt.Sprintf("%q is not a semantic version such as v1.2.3", e.Version)
+}
+
+// Version is a semantic version. Prefix keeps the "v" a tag was written
+// with, so the next version is tagged the same way.
+type Version struct {
+	Prefix              string
+	Major, Minor, Patch int
+	// Pre is the pre-release part after the dash, as in v2.0.0-rc.1.
+	Pre string
+}
+
+var versionPattern = regexp.MustCompile(`^(v?)(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z.-]+))?$`)
+
+// ParseVersion parses a version such as v1.2.3, 1.2.3 or v2.0.0-rc.1.
+func ParseVersion(s string) (Version, error) {
+	m := versionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, ErrInvalidVersion{Version: s}
+	}
+	v := Version{Prefix: m[1], Pre: m[5]}
+	v.Major, _ = strconv.Atoi(m[2])
+	v.Minor, _ = strconv.Atoi(m[3])
+	v.Patch, _ = strconv.Atoi(m[4])
+	return v, nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// Less orders versions by precedence. Pre-releases come before the release
+// and are compared as semantic versioning says: identifier by identifier,
+// numbers by value (rc.2 before rc.10) and before words, and a shorter list
+// before a longer one it starts.
+func (v Version) Less(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major < o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor < o.Minor
+	}
+	if v.Patch != o.Patch {
+		return v.Patch < o.Patch
+	}
+	switch {
+	case v.Pre == o.Pre:
+		return false
+	case v.Pre == "":
+		return false
+	case o.Pre == "":
+		return true
+	}
+	return lessPre(v.Pre, o.Pre)
+}
+
+func lessPre(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			return an < bn
+		case aErr == nil || bErr == nil:
+			return aErr == nil
+		}
+		return as[i] < bs[i]
+	}
+	return len(as) < len(bs)
+}
+
+// Latest returns the highest release among tags, ignoring tags that are not
+// versions and pre-releases. ok is false when there is none.
+func Latest(tags []string) (latest Version, tag string, ok bool) {
+	for _, t := range tags {
+		v, err := ParseVersion(t)
+		if err != nil || v.Pre != "" {
+			continue
+		}
+		if !ok || latest.Less(v) {
+			latest, tag, ok = v, t, true
+		}
+	}
+	return latest, tag, ok
+}
+
+// Change is a commit read as a conventional commit: "type(scope)!: subject".
+// Commits that do not follow the convention have an empty Type.
+type Change struct {
+	Hash     string
+	Type     string
+	Scope    string
+	Subject  string
+	Breaking bool
+}
+
+var conventional = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^)]*)\))?(!)?: (.+)$`)
+
+// ParseChange reads a commit message as a conventional commit. A
+// "BREAKING CHANGE:" footer marks it as breaking as "!" does.
+func ParseChange(hash, message string) Change {
+	subject, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	c := Change{Hash: hash, Subject: subject}
+	if m := conventional.FindStringSubmatch(subject); m != nil {
+		c.Type, c.Scope, c.Breaking, c.Subject = strings.ToLower(m[1]), m[2], m[3] == "!", m[4]
+	}
+	if strings.Contains(body, "BREAKING CHANGE:") || strings.Contains(body, "BREAKING-CHANGE:") {
+		c.Breaking = true
+	}
+	return c
+}
+
+// Bump is the part of the version a release changes.
+type Bump int
+
+const (
+	BumpPatch Bump = iota
+	BumpMinor
+	BumpMajor
+)
+
+func (b Bump) String() string {
+	return [...]string{"patch", "minor", "major"}[b]
+}
+
+// NextBump works out the bump changes call for: major for a breaking
+// change, minor for a feature, patch otherwise. Before 1.0.0 everything is
+// one step smaller, as breaking changes are expected there.
+func NextBump(current Version, changes []Change) Bump {
+	bump := BumpPatch
+	for _, c := range changes {
+		switch {
+		case c.Breaking:
+			bump = BumpMajor
+		case c.Type == "feat" && bump < BumpMinor:
+			bump = BumpMinor
+		}
+	}
+	if current.Major == 0 && bump > BumpPatch {
+		bump--
+	}
+	return bump
+}
+
+// Apply returns v bumped, as a release (without a pre-release part).
+func (v Version) Apply(b Bump) Version {
+	next := Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	switch b {
+	case BumpMajor:
+		next.Major, next.Minor, next.Patch = v.Major+1, 0, 0
+	case BumpMinor:
+		next.Minor, next.Patch = v.Minor+1, 0
+	default:
+		// Releasing v1.2.3-rc.1 as v1.2.3 is the patch step.
+		if v.Pre == "" {
+			next.Patch++
+		}
+	}
+	return next
+}
+
+// changelogGroups are the changelog sections, in order, and the commit types
+// that go in each. Types not listed go under "Other Changes".
+var changelogGroups = []struct {
+	title string
+	types []string
+}{
+	{"Features", []string{"feat"}},
+	{"Bug Fixes", []string{"fix"}},
+	{"Performance", []string{"perf"}},
+	{"Documentation", []string{"docs"}},
+}
+
+// skippedTypes are left out of the changelog: they change nothing a user of
+// the release would notice.
+var skippedTypes = map[string]bool{"chore": true, "ci": true, "test": true, "style": true, "build": true}
+
+// Changelog renders the Markdown section for a release: a heading with the
+// version and date, the breaking changes, then the changes grouped by type.
+// compareURL, when set, links the heading to the diff from the previous
+// release.
+func Changelog(version string, date time.Time, changes []Change, compareURL string) string {
+	var b strings.Builder
+	heading := version
+	if compareURL != "" {
+		heading = fmt.Sprintf("[%s](%s)", version, compareURL)
+	}
+	fmt.Fprintf(&b, "## %s (%s)\n", heading, date.Format("2006-01-02"))
+
+	line := func(c Change) string {
+		s := "- "
+		if c.Scope != "" {
+			s += "**" + c.Scope + ":** "
+		}
+		s += c.Subject
+		if len(c.Hash) >= 7 {
+			s += " (" + c.Hash[:7] + ")"
+		}
+		return s + "\n"
+	}
+	wrote := false
+	section := func(title string, items []Change) {
+		if len(items) == 0 {
+			return
+		}
+		wrote = true
+		b.WriteString("\n### " + title + "\n\n")
+		for _, c := range items {
+			b.WriteString(line(c))
+		}
+	}
+
+	var breaking, other []Change
+	grouped := map[string][]Change{}
+	for _, c := range changes {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		}
+		placed := false
+		for _, g := range changelogGroups {
+			for _, t := range g.types {
+				if c.Type == t {
+					grouped[g.title] = append(grouped[g.title], c)
+					placed = true
+				}
+			}
+		}
+		if !placed && !skippedTypes[c.Type] && !c.Breaking {
+			other = append(other, c)
+		}
+	}
+
+	section("Breaking Changes", breaking)
+	for _, g := range changelogGroups {
+		section(g.title, grouped[g.title])
+	}
+	section("Other Changes", other)
+	if !wrote {
+		b.WriteString("\nNo user-facing changes.\n")
+	}
+	return b.String()
+}
+
+// HasSection reports whether changelog already has a section for version.
+func HasSection(changelog, version string) bool {
+	for _, line := range strings.Split(changelog, "\n") {
+		rest, ok := strings.CutPrefix(line, "## ")
+		if !ok {
+			continue
+		}
+		rest = strings.TrimPrefix(rest, "[")
+		if rest == version || strings.HasPrefix(rest, version+" ") || strings.HasPrefix(rest, version+"]") {
+			return true
+		}
+	}
+	return false
+}
+
+// InsertSection puts section above the newest release in changelog, below
+// any title and introduction, starting a changelog when there is none.
+func InsertSection(changelog, section string) string {
+	if strings.TrimSpace(changelog) == "" {
+		return "# Changelog\n\n" + section
+	}
+	lines := strings.SplitAfter(changelog, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			return strings.Join(lines[:i], "") + section + "\n" + strings.Join(lines[i:], "")
+		}
+	}
+	return strings.TrimRight(changelog, "\n") + "\n\n" + section
+}
@@ -0,0 +1,149 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+		ok   bool
+	}{
+		{"v1.2.3", Version{Prefix: "v", Major: 1, Minor: 2, Patch: 3}, true},
+		{"1.2.3", Version{Major: 1, Minor: 2, Patch: 3}, true},
+		{"v2.0.0-rc.1", Version{Prefix: "v", Major: 2, Pre: "rc.1"}, true},
+		{"v0.10.0", Version{Prefix: "v", Minor: 10}, true},
+		{"v1.2", Version{}, false},
+		{"v01.2.3", Version{}, false},
+		{"release-1.2.3", Version{}, false},
+		{"v1.2.3-", Version{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.in)
+		if tt.ok != (err == nil) || got != tt.want {
+			t.Errorf("ParseVersion(%q) = %+v, %v; want %+v, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+		var invalid ErrInvalidVersion
+		if !tt.ok && !errors.As(err, &invalid) {
+			t.Errorf("ParseVersion(%q) error = %v, want ErrInvalidVersion", tt.in, err)
+		}
+		if tt.ok && got.String() != tt.in {
+			t.Errorf("ParseVersion(%q).String() = %q", tt.in, got.String())
+		}
+	}
+}
+
+func TestVersionLess(t *testing.T) {
+	// Each version is lower than the next.
+	ordered := []string{
+		"v0.9.9",
+		"v1.0.0-alpha",
+		"v1.0.0-alpha.1",
+		"v1.0.0-alpha.beta",
+		"v1.0.0-beta",
+		"v1.0.0-beta.2",
+		"v1.0.0-beta.11",
+		"v1.0.0-rc.1",
+		"v1.0.0",
+		"v1.0.1",
+		"v1.2.0",
+		"v1.10.0",
+		"v2.0.0-rc.2",
+		"v2.0.0-rc.10",
+		"v2.0.0",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			a, _ := ParseVersion(ordered[i])
+			b, _ := ParseVersion(ordered[j])
+			if got, want := a.Less(b), i < j; got != want {
+				t.Errorf("%s.Less(%s) = %v, want %v", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+}
+
+func TestLatest(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want string
+	}{
+		{"highest release", []string{"v1.2.0", "v1.10.0", "v1.9.3"}, "v1.10.0"},
+		{"pre-releases skipped", []string{"v1.2.0", "v2.0.0-rc.1"}, "v1.2.0"},
+		{"other tags skipped", []string{"nightly", "v0.3.0", "deploy-2025"}, "v0.3.0"},
+		{"tag kept as written", []string{"0.3.0", "v0.2.0"}, "0.3.0"},
+		{"only pre-releases", []string{"v1.0.0-rc.1"}, ""},
+		{"none", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, tag, ok := Latest(tt.tags)
+			if tag != tt.want || ok != (tt.want != "") {
+				t.Errorf("Latest(%q) = %q, %v; want %q", tt.tags, tag, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseChange(t *testing.T) {
+	tests := []struct {
+		message string
+		want    Change
+	}{
+		{"feat(cli): add --json", Change{Type: "feat", Scope: "cli", Subject: "add --json"}},
+		{"Fix: handle empty input", Change{Type: "fix", Subject: "handle empty input"}},
+		{"feat!: drop the v1 API", Change{Type: "feat", Subject: "drop the v1 API", Breaking: true}},
+		{"refactor(core)!: rename Run", Change{Type: "refactor", Scope: "core", Subject: "rename Run", Breaking: true}},
+		{"fix: parse dates\n\nBREAKING CHANGE: dates are UTC now", Change{Type: "fix", Subject: "parse dates", Breaking: true}},
+		{"fix: parse dates\n\nBREAKING-CHANGE: dates are UTC now", Change{Type: "fix", Subject: "parse dates", Breaking: true}},
+		{"Update README", Change{Subject: "Update README"}},
+		{"feat:missing space", Change{Subject: "feat:missing space"}},
+	}
+	for _, tt := range tests {
+		tt.want.Hash = "abc1234"
+		if got := ParseChange("abc1234", tt.message); got != tt.want {
+			t.Errorf("ParseChange(%q) = %+v, want %+v", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestNextBump(t *testing.T) {
+	var (
+		fix      = Change{Type: "fix"}
+		feat     = Change{Type: "feat"}
+		breaking = Change{Type: "fix", Breaking: true}
+	)
+	tests := []struct {
+		name    string
+		current string
+		changes []Change
+		want    Bump
+		next    string
+	}{
+		{"fixes", "v1.2.3", []Change{fix, {Type: "docs"}}, BumpPatch, "v1.2.4"},
+		{"feature", "v1.2.3", []Change{fix, feat}, BumpMinor, "v1.3.0"},
+		{"breaking", "v1.2.3", []Change{feat, breaking, fix}, BumpMajor, "v2.0.0"},
+		{"no changes", "1.2.3", nil, BumpPatch, "1.2.4"},
+		{"pre-1.0 feature", "v0.4.1", []Change{feat}, BumpPatch, "v0.4.2"},
+		{"pre-1.0 breaking", "v0.4.1", []Change{breaking}, BumpMinor, "v0.5.0"},
+		{"pre-release", "v2.0.0-rc.1", []Change{fix}, BumpPatch, "v2.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, err := ParseVersion(tt.current)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := NextBump(current, tt.changes)
+			if got != tt.want {
+				t.Errorf("NextBump = %s, want %s", got, tt.want)
+			}
+			if next := current.Apply(got).String(); next != tt.next {
+				t.Errorf("%s.Apply(%s) = %s, want %s", tt.current, got, next, tt.next)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package internal
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// File is one changed file and the size of its change.
+type File struct {
+	Path       string
+	Insertions int
+	Deletions  int
+	// Generated files (lockfiles, vendored code) count towards neither the
+	// size of the change nor its missing tests.
+	Generated bool
+	// Sensitive files are migrations or configuration, such as those
+	// DefaultSensitivePatterns match.
+	Sensitive bool
+}
+
+// Input is what a change is scored on.
+type Input struct {
+	Files []File
+	// Fixes counts, per path, the recent bug-fix commits that changed it.
+	Fixes map[string]int
+}
+
+// Factor is one reason a change is risky and the points it adds.
+type Factor struct {
+	Name   string `json:"name"`
+	Points int    `json:"points"`
+	Detail string `json:"detail"`
+}
+
+// Assessment is the risk score of a change, out of 100, and the factors
+// that make it up, largest first.
+type Assessment struct {
+	Score   int      `json:"score"`
+	Factors []Factor `json:"factors"`
+}
+
+// Points for each factor. A change scores at most 100 in all.
+const (
+	// Changes of up to smallChange lines are not risky for their size;
+	// above that the points grow with every doubling, up to maxSizePoints.
+	smallChange   = 50
+	maxSizePoints = 35
+	// hotFileFixes is how many recent fixes make a file a hotspot, each of
+	// which adds hotFilePoints, up to maxHotPoints.
+	hotFileFixes  = 2
+	hotFilePoints = 10
+	maxHotPoints  = 30
+	// untestedPoints is added when source changes with no test beside it.
+	untestedPoints = 20
+	// sensitivePoints is added when migrations or configuration change.
+	sensitivePoints = 20
+)
+
+// DefaultSensitivePatterns recognize database migrations and configuration
+// that changes how software is built, deployed or run. They take the form of
+// the generated file patterns of the git service.
+var DefaultSensitivePatterns = []string{
+	"migrations/", "migrate/", "*.sql",
+	"Dockerfile", "*.dockerfile", "docker-compose*.yml", "docker-compose*.yaml",
+	"*.tf", "*.tfvars", "*.hcl", ".github/workflows/", ".gitlab-ci.yml", "Jenkinsfile",
+	".env", ".env.*", "*.ini", "*.conf", "*.toml", "*.yaml", "*.yml",
+	"go.mod", "package.json", "Makefile",
+}
+
+// sourceExtensions are the files whose changes want tests.
+var sourceExtensions = map[string]bool{
+	".go": true, ".py": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true,
+	".java": true, ".kt": true, ".rb": true, ".rs": true, ".c": true, ".cc": true,
+	".cpp": true, ".h": true, ".cs": true, ".php": true, ".swift": true, ".scala": true,
+}
+
+// testPattern recognizes test files by the conventions of the common
+// languages, or by living under a test directory.
+var testPattern = regexp.MustCompile(`(^|/)(tests?|__tests__|spec)/|_test\.[a-z]+$|(^|/)test_[^/]*\.py$|\.(test|spec)\.[a-z]+$|Tests?\.(java|kt|cs|swift)$`)
+
+// IsTest reports whether p looks like a test file.
+func IsTest(p string) bool {
+	return testPattern.MatchString(p)
+}
+
+// fixPattern recognizes the messages of commits that fix bugs.
+var fixPattern = regexp.MustCompile(`(?i)\b(fix(es|ed)?|bug(fix)?|hotfix|revert(s|ed)?|regression)\b`)
+
+// IsBugFix reports whether a commit message says the commit fixes a bug.
+func IsBugFix(message string) bool {
+	subject, _, _ := strings.Cut(message, "\n")
+	return fixPattern.MatchString(subject)
+}
+
+// Assess scores a change on its size, the files in it that recent bug fixes
+// keep coming back to, source changed without tests, and migrations or
+// configuration it touches.
+func Assess(in Input) Assessment {
+	var (
+		lines, files   int
+		hot, sensitive []string
+		source, tests  int
+	)
+	for _, f := range in.Files {
+		if in.Fixes[f.Path] >= hotFileFixes {
+			hot = append(hot, f.Path)
+		}
+		if f.Sensitive {
+			sensitive = append(sensitive, f.Path)
+		}
+		if f.Generated {
+			continue
+		}
+		lines += f.Insertions + f.Deletions
+		files++
+		switch {
+		case IsTest(f.Path):
+			tests++
+		case sourceExtensions[path.Ext(f.Path)]:
+			source++
+		}
+	}
+
+	var a Assessment
+	if points := sizePoints(lines); points > 0 {
+		a.Factors = append(a.Factors, Factor{Name: "Large change", Points: points,
+			Detail: fmt.Sprintf("%s in %s", count(lines, "line"), count(files, "file"))})
+	}
+	if len(hot) > 0 {
+		sort.SliceStable(hot, func(i, j int) bool { return in.Fixes[hot[i]] > in.Fixes[hot[j]] })
+		named := make([]string, len(hot))
+		for i, p := range hot {
+			named[i] = fmt.Sprintf("%s (%d fixes)", p, in.Fixes[p])
+		}
+		a.Factors = append(a.Factors, Factor{Name: "Bug-prone files", Points: min(len(hot)*hotFilePoints, maxHotPoints),
+			Detail: listed(named)})
+	}
+	if source > 0 && tests == 0 {
+		a.Factors = append(a.Factors, Factor{Name: "No tests", Points: untestedPoints,
+			Detail: count(source, "source file") + " changed without a test"})
+	}
+	if len(sensitive) > 0 {
+		a.Factors = append(a.Factors, Factor{Name: "Migrations or config", Points: sensitivePoints,
+			Detail: listed(sensitive)})
+	}
+
+	sort.SliceStable(a.Factors, func(i, j int) bool { return a.Factors[i].Points > a.Factors[j].Points })
+	for _, f := range a.Factors {
+		a.Score += f.Points
+	}
+	a.Score = min(a.Score, 100)
+	return a
+}
+
+// Refine averages the score with one from the AI provider, adding the
+// difference it makes as a factor with the provider's reason.
+func (a *Assessment) Refine(score int, reason string) {
+	score = max(0, min(score, 100))
+	refined := (a.Score + score + 1) / 2
+	a.Factors = append(a.Factors, Factor{Name: "AI review", Points: refined - a.Score,
+		Detail: fmt.Sprintf("rated %d: %s", score, reason)})
+	a.Score = refined
+}
+
+// sizePoints grows by 7 with every doubling of the change beyond
+// smallChange lines.
+func sizePoints(lines int) int {
+	points := 0
+	for n := smallChange; n < lines && points < maxSizePoints; n *= 2 {
+		points += 7
+	}
+	return min(points, maxSizePoints)
+}
+
+// listed names the first few paths and counts the rest.
+func listed(paths []string) string {
+	const shown = 3
+	if len(paths) <= shown {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(paths[:shown], ", "), len(paths)-shown)
+}
+
+func count(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
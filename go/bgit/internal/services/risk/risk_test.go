@@ -0,0 +1,186 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIsTest(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"cmd/commit_test.go", true},
+		{"tests/test_parse.py", true},
+		{"pkg/test_parse.py", true},
+		{"src/app.test.tsx", true},
+		{"src/app.spec.js", true},
+		{"src/__tests__/app.js", true},
+		{"spec/models/user_spec.rb", true},
+		{"src/test/java/UserTest.java", true},
+		{"Sources/AppTests.swift", true},
+		{"test/fixtures/data.json", true},
+		{"cmd/commit.go", false},
+		{"src/latest.py", false},
+		{"contest/entry.go", false},
+		{"src/Testing.java", false},
+		{"docs/testing.md", false},
+	}
+	for _, tt := range tests {
+		if got := IsTest(tt.path); got != tt.want {
+			t.Errorf("IsTest(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestIsBugFix(t *testing.T) {
+	tests := []struct {
+		message string
+		want    bool
+	}{
+		{"fix: handle empty input", true},
+		{"Fixes #12: crash on start", true},
+		{"fix(parser): off by one", true},
+		{"Hotfix for the login page", true},
+		{"Revert \"feat: add cache\"", true},
+		{"bugfix: nil map", true},
+		{"Guard against a regression in dates", true},
+		{"feat: add --json", false},
+		{"Add prefix handling", false},
+		{"docs: explain fixtures", false},
+		// Only the subject counts.
+		{"feat: add cache\n\nThis fixes nothing yet.", false},
+	}
+	for _, tt := range tests {
+		if got := IsBugFix(tt.message); got != tt.want {
+			t.Errorf("IsBugFix(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestAssess(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      Input
+		score   int
+		factors []string
+	}{
+		{
+			name:    "small change with a test",
+			in:      Input{Files: []File{{Path: "a.go", Insertions: 10}, {Path: "a_test.go", Insertions: 10}}},
+			score:   0,
+			factors: nil,
+		},
+		{
+			name:    "no tests",
+			in:      Input{Files: []File{{Path: "a.go", Insertions: 10}, {Path: "README.md", Insertions: 5}}},
+			score:   untestedPoints,
+			factors: []string{"No tests"},
+		},
+		{
+			name:    "large change",
+			in:      Input{Files: []File{{Path: "a_test.go", Insertions: 150, Deletions: 51}}},
+			score:   21,
+			factors: []string{"Large change"},
+		},
+		{
+			name:    "size is capped",
+			in:      Input{Files: []File{{Path: "a_test.go", Insertions: 1 << 20}}},
+			score:   maxSizePoints,
+			factors: []string{"Large change"},
+		},
+		{
+			name: "generated files do not count",
+			in: Input{Files: []File{
+				{Path: "go.sum", Insertions: 5000, Generated: true},
+				{Path: "gen/api.go", Insertions: 5000, Generated: true},
+			}},
+			score:   0,
+			factors: nil,
+		},
+		{
+			name: "bug-prone files",
+			in: Input{
+				Files: []File{{Path: "a_test.go"}, {Path: "b_test.go"}, {Path: "c_test.go"}},
+				Fixes: map[string]int{"a_test.go": 2, "b_test.go": 5, "c_test.go": 1},
+			},
+			score:   2 * hotFilePoints,
+			factors: []string{"Bug-prone files"},
+		},
+		{
+			name: "migrations and config",
+			in: Input{Files: []File{
+				{Path: "db/migrations/001.sql", Insertions: 3, Sensitive: true},
+			}},
+			score:   sensitivePoints,
+			factors: []string{"Migrations or config"},
+		},
+		{
+			name: "everything, capped at 100",
+			in: Input{
+				Files: []File{
+					{Path: "a.go", Insertions: 1 << 20},
+					{Path: "b.go"}, {Path: "c.go"}, {Path: "d.go"},
+					{Path: "config.yaml", Sensitive: true},
+				},
+				Fixes: map[string]int{"a.go": 3, "b.go": 3, "c.go": 3, "d.go": 3},
+			},
+			score:   100,
+			factors: []string{"Large change", "Bug-prone files", "No tests", "Migrations or config"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := Assess(tt.in)
+			var names []string
+			for _, f := range a.Factors {
+				names = append(names, f.Name)
+			}
+			if a.Score != tt.score || !slices.Equal(names, tt.factors) {
+				t.Errorf("Assess = %d %q, want %d %q", a.Score, names, tt.score, tt.factors)
+			}
+		})
+	}
+}
+
+func TestAssessDetails(t *testing.T) {
+	a := Assess(Input{
+		Files: []File{{Path: "a.go"}, {Path: "b.go"}, {Path: "c.go"}, {Path: "d.go"}, {Path: "e.go"}},
+		Fixes: map[string]int{"a.go": 2, "b.go": 4, "c.go": 3, "d.go": 2},
+	})
+	want := map[string]string{
+		// The most fixed first, then the rest after three.
+		"Bug-prone files": "b.go (4 fixes), c.go (3 fixes), a.go (2 fixes) and 1 more",
+		"No tests":        "5 source files changed without a test",
+	}
+	for _, f := range a.Factors {
+		if f.Detail != want[f.Name] {
+			t.Errorf("%s: detail %q, want %q", f.Name, f.Detail, want[f.Name])
+		}
+	}
+	if len(a.Factors) != len(want) {
+		t.Errorf("factors = %+v, want %d", a.Factors, len(want))
+	}
+}
+
+func TestRefine(t *testing.T) {
+	tests := []struct {
+		score, rated, want int
+	}{
+		{40, 80, 60},
+		{41, 80, 61},
+		{40, 0, 20},
+		{40, 250, 70},
+		{40, -10, 20},
+	}
+	for _, tt := range tests {
+		a := Assessment{Score: tt.score}
+		a.Refine(tt.rated, "because")
+		if a.Score != tt.want {
+			t.Errorf("Refine(%d) of %d = %d, want %d", tt.rated, tt.score, a.Score, tt.want)
+		}
+		if f := a.Factors[len(a.Factors)-1]; f.Points != tt.want-tt.score {
+			t.Errorf("Refine(%d) of %d added %d points, want %d", tt.rated, tt.score, f.Points, tt.want-tt.score)
+		}
+	}
+}
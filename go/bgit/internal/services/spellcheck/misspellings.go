@@ -0,0 +1,211 @@
+package internal
+
+// misspellings maps common misspellings, most of them seen in real commit
+// logs, to their correction. Keys are lower case.
+var misspellings = map[string]string{
+	"accidentaly":      "accidentally",
+	"accomodate":       "accommodate",
+	"acheive":          "achieve",
+	"accross":          "across",
+	"adress":           "address",
+	"agressive":        "aggressive",
+	"alot":             "a lot",
+	"allready":         "already",
+	"alse":             "else",
+	"annoucement":      "announcement",
+	"aparent":          "apparent",
+	"appearence":       "appearance",
+	"arguement":        "argument",
+	"asynchonous":      "asynchronous",
+	"asyncronous":      "asynchronous",
+	"atleast":          "at least",
+	"attribtue":        "attribute",
+	"authenication":    "authentication",
+	"authentification": "authentication",
+	"availabe":         "available",
+	"avaliable":        "available",
+	"backwords":        "backwards",
+	"becase":           "because",
+	"becuase":          "because",
+	"begining":         "beginning",
+	"beleive":          "believe",
+	"boundry":          "boundary",
+	"buffor":           "buffer",
+	"calender":         "calendar",
+	"cancelation":      "cancellation",
+	"catagory":         "category",
+	"changable":        "changeable",
+	"charachter":       "character",
+	"charater":         "character",
+	"checksume":        "checksum",
+	"childs":           "children",
+	"comming":          "coming",
+	"commited":         "committed",
+	"commiting":        "committing",
+	"comparision":      "comparison",
+	"compatability":    "compatibility",
+	"compatable":       "compatible",
+	"completly":        "completely",
+	"concurent":        "concurrent",
+	"configuraiton":    "configuration",
+	"conjuction":       "conjunction",
+	"consistant":       "consistent",
+	"containes":        "contains",
+	"continous":        "continuous",
+	"convertor":        "converter",
+	"copmlete":         "complete",
+	"corectly":         "correctly",
+	"correclty":        "correctly",
+	"curently":         "currently",
+	"currenty":         "currently",
+	"dafault":          "default",
+	"defualt":          "default",
+	"definately":       "definitely",
+	"defintion":        "definition",
+	"dependancy":       "dependency",
+	"dependancies":     "dependencies",
+	"depricated":       "deprecated",
+	"desciption":       "description",
+	"descripton":       "description",
+	"destory":          "destroy",
+	"diffrent":         "different",
+	"directoy":         "directory",
+	"direcotry":        "directory",
+	"dissapear":        "disappear",
+	"documenation":     "documentation",
+	"doesnt":           "doesn't",
+	"dont":             "don't",
+	"efficent":         "efficient",
+	"enviroment":       "environment",
+	"enviornment":      "environment",
+	"eror":             "error",
+	"exection":         "execution",
+	"existant":         "existent",
+	"exisiting":        "existing",
+	"experiance":       "experience",
+	"explicitely":      "explicitly",
+	"extention":        "extension",
+	"familar":          "familiar",
+	"finaly":           "finally",
+	"fucntion":         "function",
+	"funciton":         "function",
+	"functionaly":      "functionally",
+	"futher":           "further",
+	"garantee":         "guarantee",
+	"genereate":        "generate",
+	"gaurd":            "guard",
+	"happend":          "happened",
+	"heirarchy":        "hierarchy",
+	"identifer":        "identifier",
+	"immediatly":       "immediately",
+	"implemenation":    "implementation",
+	"implemetation":    "implementation",
+	"inconsistant":     "inconsistent",
+	"incorect":         "incorrect",
+	"independant":      "independent",
+	"infomation":       "information",
+	"initalize":        "initialize",
+	"initialise":       "initialize",
+	"instanciate":      "instantiate",
+	"intial":           "initial",
+	"interupt":         "interrupt",
+	"invalide":         "invalid",
+	"isnt":             "isn't",
+	"langauge":         "language",
+	"lenght":           "length",
+	"libary":           "library",
+	"licence":          "license",
+	"maintainance":     "maintenance",
+	"managment":        "management",
+	"mesage":           "message",
+	"messsage":         "message",
+	"migth":            "might",
+	"minumum":          "minimum",
+	"mispelled":        "misspelled",
+	"neccessary":       "necessary",
+	"necessery":        "necessary",
+	"occurence":        "occurrence",
+	"occured":          "occurred",
+	"occuring":         "occurring",
+	"ommit":            "omit",
+	"optinal":          "optional",
+	"optionnal":        "optional",
+	"orignal":          "original",
+	"otherwize":        "otherwise",
+	"overriden":        "overridden",
+	"paramter":         "parameter",
+	"parmeter":         "parameter",
+	"particulary":      "particularly",
+	"perfomance":       "performance",
+	"permision":        "permission",
+	"persistant":       "persistent",
+	"posible":          "possible",
+	"preceeding":       "preceding",
+	"prefered":         "preferred",
+	"presense":         "presence",
+	"previosly":        "previously",
+	"privilage":        "privilege",
+	"proccess":         "process",
+	"procesing":        "processing",
+	"propery":          "property",
+	"proprety":         "property",
+	"pubilc":           "public",
+	"recieve":          "receive",
+	"recieved":         "received",
+	"recomend":         "recommend",
+	"recursivly":       "recursively",
+	"redundent":        "redundant",
+	"refered":          "referred",
+	"refernce":         "reference",
+	"relevent":         "relevant",
+	"remaing":          "remaining",
+	"remvoe":           "remove",
+	"repositry":        "repository",
+	"repostiory":       "repository",
+	"reponse":          "response",
+	"resouce":          "resource",
+	"responsability":   "responsibility",
+	"retreive":         "retrieve",
+	"retrun":           "return",
+	"reuslt":           "result",
+	"sematic":          "semantic",
+	"seperate":         "separate",
+	"seperator":        "separator",
+	"sequencial":       "sequential",
+	"shoud":            "should",
+	"similiar":         "similar",
+	"sucess":           "success",
+	"succesful":        "successful",
+	"successfull":      "successful",
+	"sufficent":        "sufficient",
+	"suport":           "support",
+	"supress":          "suppress",
+	"synchonize":       "synchronize",
+	"sytem":            "system",
+	"teh":              "the",
+	"temporay":         "temporary",
+	"threshhold":       "threshold",
+	"throught":         "through",
+	"tranfer":          "transfer",
+	"trasnform":        "transform",
+	"truely":           "truly",
+	"udpate":           "update",
+	"unecessary":       "unnecessary",
+	"unneccessary":     "unnecessary",
+	"unkown":           "unknown",
+	"untill":           "until",
+	"updade":           "update",
+	"usefull":          "useful",
+	"usualy":           "usually",
+	"valdiate":         "validate",
+	"varible":          "variable",
+	"verison":          "version",
+	"visable":          "visible",
+	"wether":           "whether",
+	"whcih":            "which",
+	"wich":             "which",
+	"wierd":            "weird",
+	"withing":          "within",
+	"wokr":             "work",
+	"writting":         "writing",
+}
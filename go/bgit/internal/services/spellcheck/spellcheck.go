@@ -0,0 +1,149 @@
+package internal
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind is what is wrong with a word.
+type Kind int
+
+const (
+	// Misspelling is a word from the list of common misspellings.
+	Misspelling Kind = iota
+	// Terminology is a project term written with the wrong capitalization,
+	// e.g. "Github" for "GitHub".
+	Terminology
+	// Repeated is the same word twice in a row ("the the").
+	Repeated
+)
+
+func (k Kind) String() string {
+	return [...]string{"misspelling", "terminology", "repeated word"}[k]
+}
+
+// Issue is one problem found in a message. Offset is the byte offset of Word
+// in the message; Suggestion replaces it (empty to delete a repeated word).
+type Issue struct {
+	Word       string
+	Offset     int
+	Suggestion string
+	Kind       Kind
+}
+
+// Checker finds likely typos in commit messages. It does not know every
+// English word, so rather than flagging anything unfamiliar, which would
+// drown real typos in identifiers and jargon, it looks for well-known
+// misspellings and for project terms written the wrong way.
+type Checker struct {
+	allowed map[string]bool
+	terms   map[string]string // lower case -> canonical spelling
+}
+
+// New builds a checker. words are accepted as written even when they look
+// like a misspelling (jargon, names); terms are product and project names
+// whose capitalization is enforced.
+func New(words, terms []string) *Checker {
+	c := &Checker{allowed: map[string]bool{}, terms: map[string]string{}}
+	for _, w := range words {
+		c.allowed[strings.ToLower(w)] = true
+	}
+	for _, t := range terms {
+		c.terms[strings.ToLower(t)] = t
+	}
+	return c
+}
+
+var (
+	// skipPattern matches text that is not prose: code spans, URLs and the
+	// type(scope): prefix of a conventional commit subject.
+	skipPattern = regexp.MustCompile("`[^`]*`|\\S+://\\S+|^[a-z]+(\\([^)]*\\))?!?:")
+	wordPattern = regexp.MustCompile(`[\p{L}\p{N}_'./-]+`)
+)
+
+// Check returns the issues in message in order of appearance.
+func (c *Checker) Check(message string) []Issue {
+	skipped := skipPattern.FindAllStringIndex(message, -1)
+	inSkipped := func(at int) bool {
+		for _, s := range skipped {
+			if at >= s[0] && at < s[1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var issues []Issue
+	prev, prevEnd := "", -1
+	for _, loc := range wordPattern.FindAllStringIndex(message, -1) {
+		start, end := loc[0], loc[1]
+		word := strings.Trim(message[start:end], "'.-/")
+		start += strings.Index(message[start:end], word)
+		end = start + len(word)
+		if word == "" || inSkipped(start) || !isProse(word) {
+			prev = ""
+			continue
+		}
+
+		lower := strings.ToLower(word)
+		switch {
+		case lower == prev && isSpace(message[prevEnd:start]) && !c.allowed[lower]:
+			// Delete the space before the repeat along with it.
+			issues = append(issues, Issue{Word: message[prevEnd:end], Offset: prevEnd, Kind: Repeated})
+		case c.terms[lower] != "" && c.terms[lower] != word && !isLower(word):
+			issues = append(issues, Issue{Word: word, Offset: start, Suggestion: c.terms[lower], Kind: Terminology})
+		case misspellings[lower] != "" && !c.allowed[lower]:
+			issues = append(issues, Issue{Word: word, Offset: start, Suggestion: matchCase(misspellings[lower], word), Kind: Misspelling})
+		}
+		prev, prevEnd = lower, end
+	}
+	return issues
+}
+
+// Apply replaces every issue with its suggestion.
+func Apply(message string, issues []Issue) string {
+	sorted := append([]Issue(nil), issues...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Offset > sorted[j].Offset })
+	for _, is := range sorted {
+		message = message[:is.Offset] + is.Suggestion + message[is.Offset+len(is.Word):]
+	}
+	return message
+}
+
+// isProse reports whether word is an ordinary word rather than an
+// identifier, path, version or acronym, none of which are spell-checked.
+func isProse(word string) bool {
+	if strings.ContainsAny(word, "_./0123456789") {
+		return false
+	}
+	upper, lowerSeen := 0, false
+	for _, r := range word {
+		switch {
+		case unicode.IsUpper(r):
+			if lowerSeen {
+				return false // camelCase
+			}
+			upper++
+		case unicode.IsLower(r):
+			lowerSeen = true
+		}
+	}
+	allCaps := upper > 1 && !lowerSeen
+	return !allCaps
+}
+
+func isLower(word string) bool { return word == strings.ToLower(word) }
+
+func isSpace(s string) bool { return s != "" && strings.TrimSpace(s) == "" }
+
+// matchCase capitalizes suggestion when word starts with a capital.
+func matchCase(suggestion, word string) string {
+	if first, _ := utf8.DecodeRuneInString(word); !unicode.IsUpper(first) {
+		return suggestion
+	}
+	r, size := utf8.DecodeRuneInString(suggestion)
+	return string(unicode.ToUpper(r)) + suggestion[size:]
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	c := New([]string{"seperate"}, []string{"GitHub", "PostgreSQL"})
+	tests := []struct {
+		name    string
+		message string
+		want    []Issue
+	}{
+		{"misspelling", "fix: recieve the event",
+			[]Issue{{Word: "recieve", Offset: 5, Suggestion: "receive", Kind: Misspelling}}},
+		{"capital kept", "Recieve events once",
+			[]Issue{{Word: "Recieve", Offset: 0, Suggestion: "Receive", Kind: Misspelling}}},
+		{"terminology", "docs: link to Github",
+			[]Issue{{Word: "Github", Offset: 14, Suggestion: "GitHub", Kind: Terminology}}},
+		{"term in lower case left alone", "run the github action", nil},
+		{"term as written", "move to PostgreSQL", nil},
+		{"repeated word", "update the the docs",
+			[]Issue{{Word: " the", Offset: 10, Kind: Repeated}}},
+		{"repeat across a line break", "drop the\nthe cache",
+			[]Issue{{Word: "\nthe", Offset: 8, Kind: Repeated}}},
+		{"allowed word", "keep seperate builds", nil},
+		{"code span", "rename `recieve` to receive", nil},
+		{"URL", "see https://example.com/recieve for details", nil},
+		{"conventional prefix", "teh(scope): add it", nil},
+		{"identifiers", "rename recieve_event, recieveEvent and pkg/recieve.go", nil},
+		{"acronym", "TEH is an acronym here", nil},
+		{"in order", "Teh change was definately needed",
+			[]Issue{
+				{Word: "Teh", Offset: 0, Suggestion: "The", Kind: Misspelling},
+				{Word: "definately", Offset: 15, Suggestion: "definitely", Kind: Misspelling},
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Check(tt.message); !slices.Equal(got, tt.want) {
+				t.Errorf("Check(%q) = %+v, want %+v", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	c := New(nil, []string{"GitHub"})
+	tests := []struct {
+		message, want string
+	}{
+		{"fix: recieve teh Github event", "fix: receive the GitHub event"},
+		{"Teh the the change occured", "The the change occurred"},
+		{"nothing wrong here", "nothing wrong here"},
+		{"first line\n\nWe recieve it on Github now", "first line\n\nWe receive it on GitHub now"},
+	}
+	for _, tt := range tests {
+		if got := Apply(tt.message, c.Check(tt.message)); got != tt.want {
+			t.Errorf("Apply(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestMatchCase(t *testing.T) {
+	tests := []struct {
+		suggestion, word, want string
+	}{
+		{"receive", "recieve", "receive"},
+		{"receive", "Recieve", "Receive"},
+		{"receive", "RECIEVE", "Receive"},
+		{"a lot", "Alot", "A lot"},
+		{"über", "Uber", "Über"},
+	}
+	for _, tt := range tests {
+		if got := matchCase(tt.suggestion, tt.word); got != tt.want {
+			t.Errorf("matchCase(%q, %q) = %q, want %q", tt.suggestion, tt.word, got, tt.want)
+		}
+	}
+}
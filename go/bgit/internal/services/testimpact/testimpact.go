@@ -0,0 +1,214 @@
+package internal
+
+import (
+	"io/fs"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/endalk200/bgit/internal/config"
+)
+
+// Suite is a group of tests that changed files affect: a Go package, or
+// the tests a mapping of the config finds for one source file.
+type Suite struct {
+	// Name is the directory of a Go package from the root of the
+	// repository, like ./internal/ui, or the mapping's source pattern.
+	Name string
+	// Tests are the test files most likely to cover Sources, the ones
+	// beside them first.
+	Tests []string
+	// Sources are the changed files that lead to the suite.
+	Sources []string
+	// module is the directory of the go.mod a Go package belongs to and
+	// pkg the package relative to it; run is the command of a mapping.
+	module string
+	pkg    string
+	run    string
+}
+
+// Command is a shell command that runs one or more suites.
+type Command struct {
+	Name string
+	Run  string
+	// Dir is where it runs, relative to the root of the repository.
+	Dir string
+}
+
+// Plan finds the tests that changed files, relative to the root of the
+// repository fsys, affect. A Go file affects the tests of its package; other
+// files those the first mapping matching them lists. Untested lists the
+// source files for which no test was found.
+func Plan(fsys fs.FS, changed []string, mappings []config.TestMapping) (suites []Suite, untested []string) {
+	byName := map[string]*Suite{}
+	var order []string
+	add := func(s Suite, source string) {
+		existing, ok := byName[s.Name]
+		if !ok {
+			existing = &s
+			byName[s.Name] = existing
+			order = append(order, s.Name)
+		}
+		existing.Sources = append(existing.Sources, source)
+		for _, t := range s.Tests {
+			if !slices.Contains(existing.Tests, t) {
+				existing.Tests = append(existing.Tests, t)
+			}
+		}
+	}
+
+	for _, p := range changed {
+		if m, ok := mappingFor(p, mappings); ok {
+			if tests := mappedTests(fsys, p, m); len(tests) > 0 {
+				add(Suite{Name: m.Source, Tests: tests, run: m.Run}, p)
+				continue
+			}
+			untested = append(untested, p)
+			continue
+		}
+		// Vendored code is a module's, at the root or further down.
+		if path.Ext(p) != ".go" || strings.HasPrefix(p, "vendor/") || strings.Contains(p, "/vendor/") {
+			continue
+		}
+		dir := path.Dir(p)
+		tests := goTests(fsys, dir, p)
+		if len(tests) == 0 {
+			untested = append(untested, p)
+			continue
+		}
+		module, ok := goModule(fsys, dir)
+		if !ok {
+			untested = append(untested, p)
+			continue
+		}
+		pkg := "."
+		if dir != module {
+			pkg = "./" + strings.TrimPrefix(dir, module+"/")
+			if module == "." {
+				pkg = "./" + dir
+			}
+		}
+		name := "."
+		if dir != "." {
+			name = "./" + dir
+		}
+		add(Suite{Name: name, Tests: tests, module: module, pkg: pkg}, p)
+	}
+
+	suites = make([]Suite, 0, len(order))
+	for _, name := range order {
+		suites = append(suites, *byName[name])
+	}
+	return suites, untested
+}
+
+// Commands are the commands that run suites: one go test per Go module,
+// and one per mapping command with every test file it found.
+func Commands(suites []Suite) []Command {
+	var cmds []Command
+	goPkgs := map[string][]string{}
+	var modules []string
+	mapped := map[string][]string{}
+	var runs []string
+	for _, s := range suites {
+		if s.run == "" {
+			if _, ok := goPkgs[s.module]; !ok {
+				modules = append(modules, s.module)
+			}
+			goPkgs[s.module] = append(goPkgs[s.module], s.pkg)
+			continue
+		}
+		if _, ok := mapped[s.run]; !ok {
+			runs = append(runs, s.run)
+		}
+		for _, t := range s.Tests {
+			if !slices.Contains(mapped[s.run], t) {
+				mapped[s.run] = append(mapped[s.run], t)
+			}
+		}
+	}
+	for _, module := range modules {
+		pkgs := goPkgs[module]
+		sort.Strings(pkgs)
+		name := "go test"
+		if module != "." {
+			name += " in " + module
+		}
+		cmds = append(cmds, Command{Name: name, Run: "go test " + strings.Join(pkgs, " "), Dir: module})
+	}
+	for _, run := range runs {
+		tests := mapped[run]
+		cmds = append(cmds, Command{Name: run, Run: strings.ReplaceAll(run, "{tests}", strings.Join(tests, " ")), Dir: "."})
+	}
+	return cmds
+}
+
+// goTests lists the _test.go files in dir, the one named after file first.
+func goTests(fsys fs.FS, dir, file string) []string {
+	matches, _ := fs.Glob(fsys, path.Join(dir, "*_test.go"))
+	own := strings.TrimSuffix(file, "_test.go")
+	own = strings.TrimSuffix(own, ".go") + "_test.go"
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i] == own && matches[j] != own })
+	return matches
+}
+
+// goModule finds the directory of the go.mod that dir belongs to.
+func goModule(fsys fs.FS, dir string) (string, bool) {
+	for {
+		if _, err := fs.Stat(fsys, path.Join(dir, "go.mod")); err == nil {
+			return dir, true
+		}
+		if dir == "." {
+			return "", false
+		}
+		dir = path.Dir(dir)
+	}
+}
+
+// mappingFor returns the first mapping whose source pattern matches p.
+func mappingFor(p string, mappings []config.TestMapping) (config.TestMapping, bool) {
+	for _, m := range mappings {
+		if matchSource(p, m.Source) {
+			return m, true
+		}
+	}
+	return config.TestMapping{}, false
+}
+
+// matchSource matches p against a pattern in the form of
+// generated.patterns: a directory ending in /, a glob on the path when it
+// has a slash, and on the file name otherwise.
+func matchSource(p, pattern string) bool {
+	switch {
+	case strings.HasSuffix(pattern, "/"):
+		dir := strings.TrimSuffix(pattern, "/")
+		return strings.HasPrefix(p, dir+"/") || strings.Contains(p, "/"+dir+"/")
+	case strings.Contains(pattern, "/"):
+		ok, _ := path.Match(strings.TrimPrefix(pattern, "/"), p)
+		return ok
+	}
+	ok, _ := path.Match(pattern, path.Base(p))
+	return ok
+}
+
+// mappedTests expands the test patterns of m for source file p and lists
+// the files they find.
+func mappedTests(fsys fs.FS, p string, m config.TestMapping) []string {
+	ext := path.Ext(p)
+	r := strings.NewReplacer(
+		"{dir}", path.Dir(p),
+		"{name}", strings.TrimSuffix(path.Base(p), ext),
+		"{ext}", strings.TrimPrefix(ext, "."),
+	)
+	var tests []string
+	for _, pattern := range m.Tests {
+		matches, _ := fs.Glob(fsys, path.Clean(r.Replace(pattern)))
+		for _, t := range matches {
+			if !slices.Contains(tests, t) {
+				tests = append(tests, t)
+			}
+		}
+	}
+	return tests
+}
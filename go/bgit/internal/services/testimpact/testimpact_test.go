@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/endalk200/bgit/internal/config"
+)
+
+// repo is a repository with a Go module in go/bgit, a web app tested
+// through a mapping, and Go files outside any module.
+var repo = fstest.MapFS{
+	"README.md":                         {},
+	"go/bgit/go.mod":                    {Data: []byte("module example.com/bgit\n")},
+	"go/bgit/cmd/commit.go":             {},
+	"go/bgit/cmd/commit_test.go":        {},
+	"go/bgit/cmd/root.go":               {},
+	"go/bgit/cmd/root_test.go":          {},
+	"go/bgit/internal/ui/view.go":       {},
+	"go/bgit/internal/ui/view_test.go":  {},
+	"go/bgit/internal/config/config.go": {},
+	"go/bgit/vendor/x/y/y.go":           {},
+	"go/bgit/vendor/x/y/y_test.go":      {},
+	"tools/main.go":                     {},
+	"tools/main_test.go":                {},
+	"web/src/app.ts":                    {},
+	"web/src/app.test.ts":               {},
+	"web/src/util.ts":                   {},
+}
+
+var webTests = []config.TestMapping{{
+	Source: "*.ts",
+	Tests:  []string{"{dir}/{name}.test.{ext}"},
+	Run:    "npx vitest run {tests}",
+}}
+
+func TestPlan(t *testing.T) {
+	changed := []string{
+		"go/bgit/cmd/root.go",
+		"README.md",
+		"go/bgit/internal/ui/view.go",
+		"web/src/app.ts",
+		"go/bgit/internal/config/config.go",
+		"go/bgit/cmd/commit.go",
+		"web/src/util.ts",
+		"tools/main.go",
+		"go/bgit/vendor/x/y/y.go",
+	}
+	suites, untested := Plan(repo, changed, webTests)
+
+	want := []Suite{
+		{
+			Name: "./go/bgit/cmd",
+			// The test file of the first file changed comes first.
+			Tests:   []string{"go/bgit/cmd/root_test.go", "go/bgit/cmd/commit_test.go"},
+			Sources: []string{"go/bgit/cmd/root.go", "go/bgit/cmd/commit.go"},
+			module:  "go/bgit", pkg: "./cmd",
+		},
+		{
+			Name:    "./go/bgit/internal/ui",
+			Tests:   []string{"go/bgit/internal/ui/view_test.go"},
+			Sources: []string{"go/bgit/internal/ui/view.go"},
+			module:  "go/bgit", pkg: "./internal/ui",
+		},
+		{
+			Name:    "*.ts",
+			Tests:   []string{"web/src/app.test.ts"},
+			Sources: []string{"web/src/app.ts"},
+			run:     "npx vitest run {tests}",
+		},
+	}
+	if !reflect.DeepEqual(suites, want) {
+		t.Errorf("Plan suites =\n%+v\nwant\n%+v", suites, want)
+	}
+	// No tests beside config.go or util.ts, and tools is in no module;
+	// README.md and vendored code are not looked at.
+	wantUntested := []string{"go/bgit/internal/config/config.go", "web/src/util.ts", "tools/main.go"}
+	if !reflect.DeepEqual(untested, wantUntested) {
+		t.Errorf("Plan untested = %v, want %v", untested, wantUntested)
+	}
+
+	cmds := Commands(suites)
+	wantCmds := []Command{
+		{Name: "go test in go/bgit", Run: "go test ./cmd ./internal/ui", Dir: "go/bgit"},
+		{Name: "npx vitest run {tests}", Run: "npx vitest run web/src/app.test.ts", Dir: "."},
+	}
+	if !reflect.DeepEqual(cmds, wantCmds) {
+		t.Errorf("Commands =\n%+v\nwant\n%+v", cmds, wantCmds)
+	}
+}
+
+func TestPlanRootModule(t *testing.T) {
+	fsys := fstest.MapFS{
+		"go.mod":              {Data: []byte("module example.com/app\n")},
+		"main.go":             {},
+		"main_test.go":        {},
+		"api/handler.go":      {},
+		"api/handler_test.go": {},
+	}
+	suites, untested := Plan(fsys, []string{"api/handler.go", "main.go"}, nil)
+	if len(untested) != 0 {
+		t.Errorf("Plan untested = %v, want none", untested)
+	}
+	var names []string
+	for _, s := range suites {
+		names = append(names, s.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"./api", "."}) {
+		t.Errorf("Plan suites %v, want ./api and .", names)
+	}
+	want := []Command{{Name: "go test", Run: "go test . ./api", Dir: "."}}
+	if cmds := Commands(suites); !reflect.DeepEqual(cmds, want) {
+		t.Errorf("Commands = %+v, want %+v", cmds, want)
+	}
+}
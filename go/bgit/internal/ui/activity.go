@@ -0,0 +1,228 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DayLayout keys ActivityView.Counts.
+const DayLayout = "2006-01-02"
+
+// ActivityView is a contribution heatmap: the commits of each day, one
+// column per week, Sunday at the top.
+type ActivityView struct {
+	// From and To are the first and last days counted.
+	From, To time.Time
+	// Counts holds the number of commits of each day, keyed by DayLayout.
+	Counts map[string]int
+	// Author is the filter the commits were picked with, if any.
+	Author string
+	// Selected is the day highlighted; zero for none.
+	Selected time.Time
+}
+
+var (
+	// levelStyles shade a day by how busy it was, from none to the
+	// busiest, the way GitHub's contribution graph does.
+	levelStyles = []lipgloss.Style{
+		lipgloss.NewStyle().Foreground(lipgloss.Color("238")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("22")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("28")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("34")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("40")),
+	}
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+)
+
+// levelGlyphs get denser with the level, so the map reads without colour
+// too.
+var (
+	levelGlyphs      = []string{"·", "░", "▒", "▓", "█"}
+	plainLevelGlyphs = []string{".", "-", "+", "*", "#"}
+)
+
+// dayLabelWidth is the room for the weekday names on the left.
+const dayLabelWidth = 4
+
+// ActivityRange returns the days the heatmap has room for at width: the
+// most recent weeks up to v.To, starting no earlier than v.From.
+func ActivityRange(v ActivityView, width int) (first, last time.Time) {
+	start := weekStart(v.From)
+	weeks := daysBetween(start, dayOf(v.To))/7 + 1
+	if fit := max((width-dayLabelWidth)/2, 1); weeks > fit {
+		start = start.AddDate(0, 0, 7*(weeks-fit))
+	}
+	first = dayOf(v.From)
+	if start.After(first) {
+		first = start
+	}
+	return first, dayOf(v.To)
+}
+
+// RenderActivity renders v as a grid of days, a column per week with the
+// months above, followed by a summary and a legend.
+func RenderActivity(v ActivityView, width int) string {
+	first, last := ActivityRange(v, width)
+	start := weekStart(first)
+	weeks := daysBetween(start, last)/7 + 1
+
+	busiest := 0
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		busiest = max(busiest, v.Counts[d.Format(DayLayout)])
+	}
+	glyphs := levelGlyphs
+	if plain {
+		glyphs = plainLevelGlyphs
+	}
+
+	var b strings.Builder
+	b.WriteString(activityTitle(v, first, last) + "\n\n")
+
+	// Month names over the first week of each month, where they fit.
+	months := []byte(strings.Repeat(" ", dayLabelWidth+2*weeks))
+	free := 0
+	for w := 0; w < weeks; w++ {
+		day := start.AddDate(0, 0, 7*w)
+		if w == 0 {
+			day = first
+		} else if day.Month() == day.AddDate(0, 0, -7).Month() {
+			continue
+		}
+		x := dayLabelWidth + 2*w
+		name := day.Format("Jan")
+		if x < free || x+len(name) > len(months) {
+			continue
+		}
+		copy(months[x:], name)
+		free = x + len(name) + 1
+	}
+	b.WriteString(mutedStyle.Render(strings.TrimRight(string(months), " ")) + "\n")
+
+	for weekday := 0; weekday < 7; weekday++ {
+		label := ""
+		if weekday%2 == 1 {
+			label = time.Weekday(weekday).String()[:3]
+		}
+		b.WriteString(mutedStyle.Render(fmt.Sprintf("%-*s", dayLabelWidth, label)))
+		for w := 0; w < weeks; w++ {
+			day := start.AddDate(0, 0, 7*w+weekday)
+			if day.Before(first) || day.After(last) {
+				b.WriteString("  ")
+				continue
+			}
+			level := activityLevel(v.Counts[day.Format(DayLayout)], busiest)
+			cell := levelStyles[level].Render(glyphs[level])
+			if !v.Selected.IsZero() && day.Equal(dayOf(v.Selected)) {
+				cell = selectedStyle.Render(glyphs[level])
+			}
+			b.WriteString(cell + " ")
+		}
+		b.WriteString("\n")
+	}
+
+	legend := mutedStyle.Render("Less")
+	for level, glyph := range glyphs {
+		legend += " " + levelStyles[level].Render(glyph)
+	}
+	legend += " " + mutedStyle.Render("More")
+	b.WriteString("\n" + mutedStyle.Render(activitySummary(v, first, last)) + "\n")
+	b.WriteString(legend + "\n")
+	return b.String()
+}
+
+// activityLevel buckets count into one of four levels of the busiest day,
+// with 0 for none.
+func activityLevel(count, busiest int) int {
+	if count <= 0 || busiest <= 0 {
+		return 0
+	}
+	return min((count*4+busiest-1)/busiest, 4)
+}
+
+func activityTitle(v ActivityView, first, last time.Time) string {
+	total := 0
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		total += v.Counts[d.Format(DayLayout)]
+	}
+	title := headerStyle.Render(pluralize(total, "commit", "commits")) +
+		fmt.Sprintf(" from %s to %s", first.Format("Jan 2, 2006"), last.Format("Jan 2, 2006"))
+	if v.Author != "" {
+		title += " by " + v.Author
+	}
+	return title
+}
+
+// activitySummary names the busiest day and the longest run of days with
+// commits.
+func activitySummary(v ActivityView, first, last time.Time) string {
+	active, streak, longest := 0, 0, 0
+	var busiest time.Time
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		n := v.Counts[d.Format(DayLayout)]
+		if n == 0 {
+			streak = 0
+			continue
+		}
+		active++
+		streak++
+		longest = max(longest, streak)
+		if busiest.IsZero() || n > v.Counts[busiest.Format(DayLayout)] {
+			busiest = d
+		}
+	}
+	if active == 0 {
+		return "No commits in this period."
+	}
+	return fmt.Sprintf("%s with commits · busiest %s (%d) · longest streak %s",
+		pluralize(active, "day", "days"), busiest.Format("Jan 2, 2006"), v.Counts[busiest.Format(DayLayout)],
+		pluralize(longest, "day", "days"))
+}
+
+// DayCommit is one commit listed under the heatmap for the selected day.
+type DayCommit struct {
+	Hash    string    `json:"hash"`
+	Subject string    `json:"subject"`
+	Author  string    `json:"author"`
+	When    time.Time `json:"date"`
+}
+
+// RenderDayCommits lists the commits of day, each with its short hash,
+// time, subject and author.
+func RenderDayCommits(day time.Time, commits []DayCommit, width int) string {
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(day.Format("Mon Jan 2, 2006")) + mutedStyle.Render(" · "+pluralize(len(commits), "commit", "commits")) + "\n")
+	for _, c := range commits {
+		short := c.Hash
+		if len(short) > 7 {
+			short = short[:7]
+		}
+		author := TruncateMiddle(c.Author, 20)
+		room := max(width-2-7-2-5-2-2-StringWidth(author), 10)
+		subject := TruncateMiddle(c.Subject, room)
+		subject += strings.Repeat(" ", max(room-StringWidth(subject), 0))
+		fmt.Fprintf(&b, "  %s  %s  %s  %s\n", hashStyle.Render(short), mutedStyle.Render(c.When.Format("15:04")), subject, mutedStyle.Render(author))
+	}
+	return b.String()
+}
+
+// dayOf is the midnight starting t's day, in t's location.
+func dayOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween counts the days from a to b, both midnights; a day lost or
+// gained to daylight saving time does not matter.
+func daysBetween(a, b time.Time) int {
+	return int(math.Round(b.Sub(a).Hours() / 24))
+}
+
+// weekStart is the Sunday on or before t.
+func weekStart(t time.Time) time.Time {
+	t = dayOf(t)
+	return t.AddDate(0, 0, -int(t.Weekday()))
+}
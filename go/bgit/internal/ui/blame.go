@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// BlameLine is a line of a blamed file with the commit that last changed
+// it.
+type BlameLine struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Email   string    `json:"email"`
+	When    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+	Text    string    `json:"text"`
+}
+
+// BlameView is a file with the commit that last changed each of its lines.
+type BlameView struct {
+	Path string
+	// Rev is the revision the file is blamed at, as given.
+	Rev   string
+	Lines []BlameLine
+	// Now is what the age of each line is measured against.
+	Now time.Time
+}
+
+// ageStyles colour a line by how long ago it last changed, from this week
+// to more than a year ago, hot to cold; ageLimits are where each level
+// ends.
+var (
+	ageStyles = []lipgloss.Style{
+		lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("208")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("220")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("73")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("61")),
+	}
+	ageLimits = []time.Duration{7 * 24 * time.Hour, 30 * 24 * time.Hour, 182 * 24 * time.Hour, 365 * 24 * time.Hour}
+)
+
+// Room given to the parts of a blame line before the file's own text.
+const (
+	blameAuthorWidth = 16
+	blameMinText     = 40
+)
+
+// AgeStyle is the colour of something last changed at t, seen from now.
+func AgeStyle(t, now time.Time) lipgloss.Style {
+	age := now.Sub(t)
+	for i, limit := range ageLimits {
+		if age < limit {
+			return ageStyles[i]
+		}
+	}
+	return ageStyles[len(ageStyles)-1]
+}
+
+// RenderBlame renders the lines of v, one string (with its newline) per
+// line of the file: a bar coloured by the line's age, then, where a run of
+// lines from one commit starts, the commit's short hash, author and age,
+// then the line number and text. The author and then the age are left out
+// when width has too little room for the text.
+func RenderBlame(v BlameView, width int) []string {
+	if len(v.Lines) == 0 {
+		return nil
+	}
+	bar := "▌"
+	if plain {
+		bar = "|"
+	}
+
+	authorWidth, ageWidth := 0, 0
+	ages := make([]string, len(v.Lines))
+	for i, l := range v.Lines {
+		authorWidth = max(authorWidth, StringWidth(l.Author))
+		ages[i] = RelativeTime(l.When, v.Now)
+		ageWidth = max(ageWidth, StringWidth(ages[i]))
+	}
+	authorWidth = min(authorWidth, blameAuthorWidth)
+	numWidth := len(strconv.Itoa(len(v.Lines)))
+
+	// "▌ hash author age num │ text"
+	fixed := 2 + 8 + authorWidth + 1 + ageWidth + 1 + numWidth + 3
+	if width-fixed < blameMinText {
+		fixed -= authorWidth + 1
+		authorWidth = 0
+	}
+	if width-fixed < blameMinText/2 {
+		fixed -= ageWidth + 1
+		ageWidth = 0
+	}
+	textWidth := max(width-fixed, 1)
+
+	out := make([]string, 0, len(v.Lines))
+	for i, l := range v.Lines {
+		style := AgeStyle(l.When, v.Now)
+		var b strings.Builder
+		b.WriteString(style.Render(bar) + " ")
+		if i == 0 || v.Lines[i-1].Hash != l.Hash {
+			b.WriteString(hashStyle.Render(shortHash(l.Hash)) + " ")
+			if authorWidth > 0 {
+				b.WriteString(padRight(truncateEnd(l.Author, authorWidth), authorWidth) + " ")
+			}
+			if ageWidth > 0 {
+				b.WriteString(style.Render(padRight(ages[i], ageWidth)) + " ")
+			}
+		} else {
+			b.WriteString(strings.Repeat(" ", fixed-2-numWidth-3))
+		}
+		num := strconv.Itoa(i + 1)
+		b.WriteString(mutedStyle.Render(strings.Repeat(" ", numWidth-len(num))+num+" │") + " ")
+		b.WriteString(truncateEnd(expandTabs(l.Text), textWidth))
+		out = append(out, strings.TrimRight(b.String(), " ")+"\n")
+	}
+	return out
+}
+
+// padRight pads s with spaces to width cells.
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-StringWidth(s), 0))
+}
+
+// expandTabs replaces the tabs of s with spaces up to the next multiple of
+// four, so the text lines up however the terminal sets its tab stops.
+func expandTabs(s string) string {
+	if !strings.Contains(s, "\t") {
+		return s
+	}
+	var b strings.Builder
+	col := 0
+	for _, r := range s {
+		if r == '\t' {
+			n := 4 - col%4
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+		b.WriteRune(r)
+		col += StringWidth(string(r))
+	}
+	return b.String()
+}
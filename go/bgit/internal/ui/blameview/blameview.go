@@ -0,0 +1,305 @@
+// Package blameview is the full-screen reader of 'bgit blame': every line of
+// a file with the commit that last changed it, coloured by age. The commit
+// of a line can be opened to read it whole, or the file blamed as it was
+// before that commit to see who wrote the line before.
+package blameview
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/endalk200/bgit/internal/ui"
+)
+
+var (
+	barStyle    = lipgloss.NewStyle().Reverse(true)
+	cursorStyle = lipgloss.NewStyle().Reverse(true).Bold(true)
+	mutedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	noteStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+)
+
+// Fallback size, used until the terminal reports its own.
+const (
+	fallbackWidth  = 80
+	fallbackHeight = 24
+)
+
+// Options configure the viewer.
+type Options struct {
+	// Open returns the commit with hash as it is shown when the reader
+	// presses enter on one of its lines. Nil disables opening.
+	Open func(hash string) (string, error)
+	// Before blames the file as it was before the commit with hash changed
+	// it, for b. Nil disables it.
+	Before func(hash string) (ui.BlameView, error)
+}
+
+// level is one blame of the file: the one asked for first, then one for
+// each time the reader went back before a commit.
+type level struct {
+	blame  ui.BlameView
+	lines  []string
+	cursor int
+	view   viewport.Model
+}
+
+type model struct {
+	opts   Options
+	levels []level
+	// commit is the commit opened over the blame, if any.
+	commit      *viewport.Model
+	commitTitle string
+	width       int
+	height      int
+	note        string
+}
+
+func newModel(blame ui.BlameView, opts Options, width, height int) model {
+	if width == 0 || height == 0 {
+		width, height = fallbackWidth, fallbackHeight
+	}
+	m := model{opts: opts, width: width, height: height}
+	m.push(blame, 0)
+	return m
+}
+
+// push blames the file again on top of the current level, with the cursor
+// on line cursor, or as near it as the file now goes.
+func (m *model) push(blame ui.BlameView, cursor int) {
+	l := level{blame: blame, view: viewport.New(m.width, m.height-1)}
+	l.lines = ui.RenderBlame(blame, m.width)
+	l.cursor = min(cursor, max(len(l.lines)-1, 0))
+	l.render()
+	l.show()
+	m.levels = append(m.levels, l)
+}
+
+func (m *model) top() *level { return &m.levels[len(m.levels)-1] }
+
+// render lays out the lines with the cursor's highlighted.
+func (l *level) render() {
+	var b strings.Builder
+	for i, line := range l.lines {
+		line = strings.TrimSuffix(line, "\n")
+		if i == l.cursor {
+			line = cursorStyle.Render(ansi.Strip(line))
+		}
+		b.WriteString(line + "\n")
+	}
+	l.view.SetContent(strings.TrimSuffix(b.String(), "\n"))
+}
+
+// show scrolls just enough to keep the cursor on the screen.
+func (l *level) show() {
+	switch {
+	case l.cursor < l.view.YOffset:
+		l.view.SetYOffset(l.cursor)
+	case l.cursor >= l.view.YOffset+l.view.Height:
+		l.view.SetYOffset(l.cursor - l.view.Height + 1)
+	}
+}
+
+// moveTo puts the cursor on line i, kept within the file.
+func (l *level) moveTo(i int) {
+	i = max(min(i, len(l.lines)-1), 0)
+	if i == l.cursor {
+		return
+	}
+	l.cursor = i
+	l.render()
+	l.show()
+}
+
+// nextCommit returns the first line after the cursor (dir 1) or before it
+// (dir -1) where a run of lines from one commit starts, or the cursor when
+// there is none.
+func (l *level) nextCommit(dir int) int {
+	lines := l.blame.Lines
+	for i := l.cursor + dir; i >= 0 && i < len(lines); i += dir {
+		if i == 0 || lines[i].Hash != lines[i-1].Hash {
+			return i
+		}
+	}
+	return l.cursor
+}
+
+// current is the blamed line under the cursor.
+func (l *level) current() (ui.BlameLine, bool) {
+	if l.cursor >= len(l.blame.Lines) {
+		return ui.BlameLine{}, false
+	}
+	return l.blame.Lines[l.cursor], true
+}
+
+func (m model) Init() tea.Cmd { return nil }
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		for i := range m.levels {
+			l := &m.levels[i]
+			l.view.Width, l.view.Height = msg.Width, msg.Height-1
+			l.lines = ui.RenderBlame(l.blame, msg.Width)
+			l.render()
+			l.show()
+		}
+		if m.commit != nil {
+			m.commit.Width, m.commit.Height = msg.Width, msg.Height-1
+		}
+		return m, nil
+	case tea.KeyMsg:
+		return m.updateKey(msg)
+	}
+	return m, nil
+}
+
+func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.note = ""
+	if m.commit != nil {
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace", "left", "h":
+			m.commit = nil
+			return m, nil
+		}
+		var cmd tea.Cmd
+		*m.commit, cmd = m.commit.Update(msg)
+		return m, cmd
+	}
+
+	l := m.top()
+	page := max(l.view.Height-1, 1)
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace", "left", "h":
+		if len(m.levels) == 1 {
+			if msg.String() == "esc" {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		m.levels = m.levels[:len(m.levels)-1]
+	case "down", "j":
+		l.moveTo(l.cursor + 1)
+	case "up", "k":
+		l.moveTo(l.cursor - 1)
+	case "pgdown", "f", " ":
+		l.moveTo(l.cursor + page)
+	case "pgup":
+		l.moveTo(l.cursor - page)
+	case "ctrl+d":
+		l.moveTo(l.cursor + page/2)
+	case "ctrl+u":
+		l.moveTo(l.cursor - page/2)
+	case "g", "home":
+		l.moveTo(0)
+	case "G", "end":
+		l.moveTo(len(l.lines) - 1)
+	case "n", "tab":
+		l.moveTo(l.nextCommit(1))
+	case "N", "p", "shift+tab":
+		l.moveTo(l.nextCommit(-1))
+	case "enter", "right", "l":
+		line, ok := l.current()
+		if m.opts.Open == nil || !ok {
+			return m, nil
+		}
+		content, err := m.opts.Open(line.Hash)
+		if err != nil {
+			m.note = err.Error()
+			return m, nil
+		}
+		view := viewport.New(m.width, m.height-1)
+		view.SetContent(strings.TrimRight(content, "\n"))
+		m.commit, m.commitTitle = &view, "commit "+line.Hash[:min(len(line.Hash), 7)]
+	case "b":
+		if m.opts.Before != nil {
+			return m.before()
+		}
+	}
+	return m, nil
+}
+
+// before blames the file as it was before the commit of the cursor's line,
+// keeping the cursor on the same line number.
+func (m model) before() (tea.Model, tea.Cmd) {
+	l := m.top()
+	line, ok := l.current()
+	if !ok {
+		return m, nil
+	}
+	blame, err := m.opts.Before(line.Hash)
+	if err != nil {
+		m.note = err.Error()
+		return m, nil
+	}
+	if len(blame.Lines) == 0 {
+		m.note = fmt.Sprintf("%s is empty before %s", blame.Path, line.Hash[:min(len(line.Hash), 7)])
+		return m, nil
+	}
+	m.push(blame, l.cursor)
+	return m, nil
+}
+
+func (m model) View() string {
+	if m.commit != nil {
+		return m.commit.View() + "\n" + m.statusBar()
+	}
+	return m.levels[len(m.levels)-1].view.View() + "\n" + m.statusBar()
+}
+
+// statusBar shows the file, the line the cursor is on and its commit, and
+// the keys that apply.
+func (m model) statusBar() string {
+	var left, keys string
+	if m.commit != nil {
+		left = fmt.Sprintf(" %s  %d%%", m.commitTitle, int(m.commit.ScrollPercent()*100))
+		keys = "esc back  q quit"
+	} else {
+		l := m.levels[len(m.levels)-1]
+		left = " " + l.blame.Path
+		if l.blame.Rev != "" {
+			left += " @ " + l.blame.Rev
+		}
+		left += fmt.Sprintf("  %d/%d", l.cursor+1, len(l.lines))
+		if line, ok := l.current(); ok {
+			left += "  " + line.Hash[:min(len(line.Hash), 7)] + " " + line.Subject
+		}
+		keys = "n/N commits  enter show  b before  q quit"
+		switch {
+		case m.opts.Open == nil && m.opts.Before == nil:
+			keys = "n/N commits  q quit"
+		case len(m.levels) > 1:
+			keys = "enter show  b before  esc back  q quit"
+		}
+	}
+	if m.note != "" {
+		keys = noteStyle.Render(m.note)
+	}
+
+	room := m.width - ui.StringWidth(keys) - 2
+	if room < 20 {
+		return barStyle.Render(ansi.Truncate(left, max(m.width, 1), "…"))
+	}
+	if ui.StringWidth(left) > room {
+		left = ansi.Truncate(left, room-1, "") + "…"
+	}
+	gap := room - ui.StringWidth(left) + 1
+	return barStyle.Render(left+strings.Repeat(" ", gap)) + " " + mutedStyle.Render(keys)
+}
+
+// Run shows blame full screen on w until the reader quits.
+func Run(w io.Writer, in io.Reader, blame ui.BlameView, opts Options) error {
+	p := tea.NewProgram(newModel(blame, opts, 0, 0),
+		tea.WithOutput(w), tea.WithInput(in), tea.WithAltScreen(), tea.WithoutSignalHandler())
+	_, err := p.Run()
+	return err
+}
@@ -0,0 +1,136 @@
+package blameview
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/uitest"
+	"github.com/muesli/termenv"
+)
+
+func TestMain(m *testing.M) {
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	os.Exit(m.Run())
+}
+
+var now = time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC)
+
+// blame is a short file whose lines come from four commits, one of them
+// twice over.
+func blame() ui.BlameView {
+	line := func(hash, author, subject string, ago time.Duration, text string) ui.BlameLine {
+		return ui.BlameLine{Hash: hash + strings.Repeat("0", 33), Author: author, Subject: subject, When: now.Add(-ago), Text: text}
+	}
+	day := 24 * time.Hour
+	return ui.BlameView{Path: "session.go", Rev: "HEAD", Now: now, Lines: []ui.BlameLine{
+		line("1a2b3c4", "Alice Example", "Add sessions", 400*day, "package auth"),
+		line("1a2b3c4", "Alice Example", "Add sessions", 400*day, ""),
+		line("1a2b3c4", "Alice Example", "Add sessions", 400*day, "type Session struct {"),
+		line("9a8b7c6", "Chen Wei", "Remember the device of a session", 3*time.Hour, "\tUserID string"),
+		line("9a8b7c6", "Chen Wei", "Remember the device of a session", 3*time.Hour, "\tDevice string"),
+		line("0f1e2d3", "Dana", "Expire sessions", 20*day, "\tExpiresAt time.Time"),
+		line("1a2b3c4", "Alice Example", "Add sessions", 400*day, "}"),
+	}}
+}
+
+func cursor(m tea.Model) int {
+	levels := m.(model).levels
+	return levels[len(levels)-1].cursor
+}
+
+func TestJumpBetweenCommits(t *testing.T) {
+	var m tea.Model = newModel(blame(), Options{}, 80, 6)
+	for _, want := range []int{3, 5, 6, 6} {
+		m = uitest.Press(m, "n")
+		if got := cursor(m); got != want {
+			t.Fatalf("n moved to line %d, want %d", got+1, want+1)
+		}
+	}
+	m = uitest.Press(m, "N", "N")
+	if got := cursor(m); got != 3 {
+		t.Errorf("N N moved to line %d, want 4", got+1)
+	}
+	// The screen scrolls to keep the cursor on it.
+	m = uitest.Press(m, "G")
+	uitest.AssertGolden(t, "bottom", m.View())
+}
+
+func TestOpenCommit(t *testing.T) {
+	var opened string
+	var m tea.Model = newModel(blame(), Options{
+		Open: func(hash string) (string, error) {
+			opened = hash
+			return "commit " + hash + "\n\n    Remember the device of a session\n", nil
+		},
+	}, 80, 6)
+	m = uitest.Press(m, "down", "down", "down", "enter")
+	if opened != blame().Lines[3].Hash {
+		t.Fatalf("enter opened %q, want the commit of the cursor's line", opened)
+	}
+	uitest.AssertGolden(t, "commit", m.View())
+
+	m = uitest.Press(m, "esc")
+	if m.(model).commit != nil || cursor(m) != 3 {
+		t.Errorf("esc did not go back to the blame at line 4")
+	}
+}
+
+func TestOpenError(t *testing.T) {
+	var m tea.Model = newModel(blame(), Options{
+		Open: func(string) (string, error) { return "", errors.New("object not found") },
+	}, 80, 6)
+	m = uitest.Press(m, "enter")
+	if m.(model).commit != nil {
+		t.Errorf("a commit was opened after a failed open")
+	}
+	if !strings.Contains(uitest.StripANSI(m.View()), "object not found") {
+		t.Errorf("the error is not shown in the status bar")
+	}
+}
+
+func TestBlameBefore(t *testing.T) {
+	var asked string
+	earlier := blame()
+	earlier.Rev = "9a8b7c6^"
+	earlier.Lines = append(earlier.Lines[:3:3], earlier.Lines[5:]...)
+	var m tea.Model = newModel(blame(), Options{
+		Before: func(hash string) (ui.BlameView, error) {
+			asked = hash
+			return earlier, nil
+		},
+	}, 80, 6)
+	m = uitest.Press(m, "n", "n", "b")
+	if asked != blame().Lines[5].Hash {
+		t.Fatalf("b blamed before %q, want the commit of the cursor's line", asked)
+	}
+	if levels := len(m.(model).levels); levels != 2 {
+		t.Fatalf("%d levels after b, want 2", levels)
+	}
+	if got := cursor(m); got != 4 {
+		t.Errorf("cursor on line %d after b, want it kept as near line 6 as the file goes, 5", got+1)
+	}
+	if !strings.Contains(uitest.StripANSI(m.View()), "@ 9a8b7c6^") {
+		t.Errorf("the status bar does not say which revision is blamed")
+	}
+
+	m = uitest.Press(m, "esc")
+	if levels := len(m.(model).levels); levels != 1 || cursor(m) != 5 {
+		t.Errorf("esc left %d levels with the cursor on line %d, want the first blame back at line 6", levels, cursor(m)+1)
+	}
+}
+
+func TestViewGolden(t *testing.T) {
+	for _, w := range []int{40, 80, 120} {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			m := newModel(blame(), Options{Open: func(string) (string, error) { return "", nil }}, w, 10)
+			uitest.AssertGolden(t, fmt.Sprintf("blame_%d", w), m.View())
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StaleAfter is how long a branch can go without commits before it is
+// flagged as stale.
+const StaleAfter = 90 * 24 * time.Hour
+
+// BranchRow is one branch of the comparison table.
+type BranchRow struct {
+	Name    string    `json:"name"`
+	Current bool      `json:"current"`
+	Ahead   int       `json:"ahead"`
+	Behind  int       `json:"behind"`
+	When    time.Time `json:"last_commit"`
+	Author  string    `json:"author"`
+	// PR is the open pull request for the branch, e.g. "#42", or empty.
+	PR string `json:"pull_request,omitempty"`
+}
+
+// BranchTable compares local branches with Base.
+type BranchTable struct {
+	Base string
+	Rows []BranchRow
+	Now  time.Time
+	// ShowPR adds the pull request column; PRNote explains why it is
+	// missing when pull requests could not be looked up.
+	ShowPR bool
+	PRNote string
+}
+
+// RenderBranchTable renders branches as a table of commits ahead of and
+// behind the base, the age and author of the last commit and, when known,
+// the open pull request. Branches fully merged into the base and branches
+// without commits for StaleAfter are marked so leftover work stands out.
+func RenderBranchTable(t BranchTable, width int) string {
+	headers := []string{"", "BRANCH", "AHEAD", "BEHIND", "LAST COMMIT", "AUTHOR"}
+	if t.ShowPR {
+		headers = append(headers, "PR")
+	}
+	headers = append(headers, "")
+
+	rows := make([][]string, 0, len(t.Rows))
+	notes := make([]string, 0, len(t.Rows))
+	for _, r := range t.Rows {
+		mark := " "
+		if r.Current {
+			mark = "*"
+		}
+		ahead, behind := strconv.Itoa(r.Ahead), strconv.Itoa(r.Behind)
+		note := ""
+		switch {
+		case r.Name == t.Base:
+			ahead, behind, note = "-", "-", "base"
+		case t.Now.Sub(r.When) > StaleAfter && r.Ahead > 0:
+			note = "stale"
+		case r.Ahead == 0:
+			note = "merged"
+		}
+		row := []string{mark, r.Name, ahead, behind, RelativeTime(r.When, t.Now), TruncateMiddle(r.Author, 20)}
+		if t.ShowPR {
+			pr := r.PR
+			if pr == "" {
+				pr = "-"
+			}
+			row = append(row, pr)
+		}
+		rows = append(rows, append(row, note))
+		notes = append(notes, note)
+	}
+
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = StringWidth(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], StringWidth(cell))
+		}
+	}
+	// Give the branch column whatever the others leave over.
+	others := 0
+	for i, w := range widths {
+		if i != 1 {
+			others += w + 2
+		}
+	}
+	if widths[1]+others > width {
+		widths[1] = max(width-others, 10)
+	}
+
+	numeric := map[int]bool{2: true, 3: true}
+	line := func(cells []string, style func(i int, s string) string) string {
+		var b strings.Builder
+		for i, cell := range cells {
+			if i == 1 {
+				cell = TruncateMiddle(cell, widths[1])
+			}
+			pad := strings.Repeat(" ", widths[i]-StringWidth(cell))
+			if numeric[i] {
+				cell = pad + cell
+			} else {
+				cell += pad
+			}
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			b.WriteString(style(i, cell))
+		}
+		return strings.TrimRight(b.String(), " ") + "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Branches compared with %s\n\n", headerStyle.Render(t.Base)))
+	b.WriteString(line(headers, func(_ int, s string) string { return mutedStyle.Render(s) }))
+	for n, row := range rows {
+		b.WriteString(line(row, func(i int, s string) string {
+			switch {
+			case i == 1 && t.Rows[n].Current:
+				return stagedStyle.Render(s)
+			case i == 2 && t.Rows[n].Ahead > 0 && notes[n] != "base":
+				return insertStyle.Render(s)
+			case i == 3 && t.Rows[n].Behind > 0 && notes[n] != "base":
+				return deleteStyle.Render(s)
+			case i == len(row)-1 && notes[n] == "stale":
+				return modifiedStyle.Render(s)
+			case i == len(row)-1:
+				return mutedStyle.Render(s)
+			}
+			return s
+		}))
+	}
+	if t.PRNote != "" {
+		b.WriteString("\n" + mutedStyle.Render(t.PRNote) + "\n")
+	}
+	return b.String()
+}
+
+// RelativeTime describes how long before now t was, e.g. "3 days ago".
+func RelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return pluralize(int(d/time.Minute), "minute", "minutes") + " ago"
+	case d < 24*time.Hour:
+		return pluralize(int(d/time.Hour), "hour", "hours") + " ago"
+	case d < 14*24*time.Hour:
+		return pluralize(int(d/(24*time.Hour)), "day", "days") + " ago"
+	case d < 60*24*time.Hour:
+		return pluralize(int(d/(7*24*time.Hour)), "week", "weeks") + " ago"
+	case d < 365*24*time.Hour:
+		return pluralize(int(d/(30*24*time.Hour)), "month", "months") + " ago"
+	default:
+		return pluralize(int(d/(365*24*time.Hour)), "year", "years") + " ago"
+	}
+}
+
+// BranchListRow is one local branch and what it tracks.
+type BranchListRow struct {
+	Name    string `json:"name"`
+	Current bool   `json:"current"`
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+	// Upstream is the branch tracked, or empty; Gone says it no longer
+	// exists or was never fetched.
+	Upstream string `json:"upstream,omitempty"`
+	Ahead    int    `json:"ahead"`
+	Behind   int    `json:"behind"`
+	Gone     bool   `json:"upstream_gone,omitempty"`
+}
+
+// RenderBranchList lists local branches, the current one marked, each with
+// its last commit and the branch it tracks: how far ahead and behind that
+// is, or that it is gone.
+func RenderBranchList(rows []BranchListRow, width int) string {
+	if len(rows) == 0 {
+		return "No branches yet.\n"
+	}
+	nameWidth := 0
+	for _, r := range rows {
+		nameWidth = max(nameWidth, StringWidth(r.Name))
+	}
+	nameWidth = min(nameWidth, max(width/3, 10))
+	trackWidth := 0
+	tracks := make([]string, len(rows))
+	for i, r := range rows {
+		tracks[i] = branchTracking(r, max(width/3, 16))
+		trackWidth = max(trackWidth, StringWidth(tracks[i]))
+	}
+
+	var b strings.Builder
+	for i, r := range rows {
+		mark, name := "  ", TruncateMiddle(r.Name, nameWidth)
+		pad := strings.Repeat(" ", nameWidth-StringWidth(name))
+		if r.Current {
+			mark, name = "* ", stagedStyle.Render(name)
+		}
+		short := r.Hash
+		if len(short) > 7 {
+			short = short[:7]
+		}
+		used := 2 + nameWidth + 2 + len(short)
+		if trackWidth > 0 {
+			used += 2 + trackWidth
+		}
+		subject := ""
+		if room := width - used - 2; room >= 10 {
+			subject = TruncateMiddle(r.Subject, room)
+		}
+
+		line := mark + name + pad + "  " + hashStyle.Render(short)
+		if track := tracks[i]; track != "" {
+			if subject != "" {
+				track += strings.Repeat(" ", trackWidth-StringWidth(track))
+			}
+			style := mutedStyle
+			if r.Gone {
+				style = deleteStyle
+			}
+			line += "  " + style.Render(track)
+		} else if subject != "" && trackWidth > 0 {
+			line += strings.Repeat(" ", trackWidth+2)
+		}
+		if subject != "" {
+			line += "  " + subject
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// branchTracking describes what a branch tracks, e.g. "origin/main [ahead 2]",
+// shortening the branch name to keep within width.
+func branchTracking(r BranchListRow, width int) string {
+	if r.Upstream == "" {
+		return ""
+	}
+	var state []string
+	switch {
+	case r.Gone:
+		state = append(state, "gone")
+	default:
+		if r.Ahead > 0 {
+			state = append(state, fmt.Sprintf("ahead %d", r.Ahead))
+		}
+		if r.Behind > 0 {
+			state = append(state, fmt.Sprintf("behind %d", r.Behind))
+		}
+	}
+	if len(state) == 0 {
+		return TruncateMiddle(r.Upstream, width)
+	}
+	note := " [" + strings.Join(state, ", ") + "]"
+	return TruncateMiddle(r.Upstream, max(width-StringWidth(note), 10)) + note
+}
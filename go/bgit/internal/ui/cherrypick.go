@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CherryPickCommit is a commit 'bgit cherry-pick' was asked to pick, with
+// the commit it became, when it was committed.
+type CherryPickCommit struct {
+	Hash    string
+	Subject string
+	Picked  string
+}
+
+// CherryPickResult is how 'bgit cherry-pick' went: the first Applied of
+// Commits went onto Branch, as commits or, with NoCommit, as staged
+// changes. When Conflicts is not empty it stopped on the next one, and
+// those are the files left to resolve.
+type CherryPickResult struct {
+	Branch    string
+	Commits   []CherryPickCommit
+	Applied   int
+	NoCommit  bool
+	Conflicts []string
+}
+
+// RenderCherryPick lists the commits of r, each marked as applied, stopped
+// on or not picked, followed by the conflicted files.
+func RenderCherryPick(r CherryPickResult, width int) string {
+	var b strings.Builder
+	head := "Picked onto " + r.Branch
+	if r.NoCommit {
+		head = "Applied to the staging area"
+	}
+	count := pluralize(len(r.Commits), "commit", "commits")
+	if r.Applied < len(r.Commits) {
+		count = fmt.Sprintf("%d of %s", r.Applied, count)
+	}
+	b.WriteString(headerStyle.Render(head) + mutedStyle.Render(" ("+count+")") + "\n")
+
+	for i, c := range r.Commits {
+		mark, note := stagedStyle.Render("✓"), ""
+		switch {
+		case i < r.Applied:
+			if c.Picked != "" {
+				note = "  → " + shortHash(c.Picked)
+			}
+		case i == r.Applied && len(r.Conflicts) > 0:
+			mark, note = deletedStyle.Render("✗"), "  conflicts"
+		default:
+			mark, note = mutedStyle.Render(Bullet()), "  not picked"
+		}
+		subject := truncateEnd(c.Subject, max(width-StringWidth(note)-14, 10))
+		b.WriteString("  " + mark + " " + hashStyle.Render(shortHash(c.Hash)) + " " + subject + mutedStyle.Render(note) + "\n")
+	}
+
+	if len(r.Conflicts) > 0 {
+		b.WriteString("\n" + RenderSection("Conflicts", r.Conflicts, deletedStyle, width))
+	}
+	return b.String()
+}
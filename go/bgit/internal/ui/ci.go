@@ -0,0 +1,198 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RunRow is one workflow run of the CI table.
+type RunRow struct {
+	ID       int64  `json:"id"`
+	Workflow string `json:"workflow"`
+	Event    string `json:"event"`
+	// State is the run's conclusion once completed (success, failure...),
+	// else its status (queued, in_progress...).
+	State   string    `json:"state"`
+	SHA     string    `json:"head_sha"`
+	Attempt int       `json:"attempt"`
+	When    time.Time `json:"updated_at"`
+	URL     string    `json:"url"`
+}
+
+// JobRow is a job that failed in one of the runs.
+type JobRow struct {
+	ID       int64  `json:"id"`
+	RunID    int64  `json:"run_id"`
+	Workflow string `json:"workflow"`
+	Name     string `json:"name"`
+	State    string `json:"state"`
+	// Step is the first step that failed, when known.
+	Step string `json:"failed_step,omitempty"`
+	URL  string `json:"url"`
+}
+
+// CIView is the CI state of a branch: the latest run of each workflow and
+// the jobs that failed in them.
+type CIView struct {
+	Repo   string    `json:"repo"`
+	Branch string    `json:"branch"`
+	Head   string    `json:"head"`
+	Runs   []RunRow  `json:"runs"`
+	Failed []JobRow  `json:"failed_jobs"`
+	Now    time.Time `json:"-"`
+}
+
+// RenderCI renders the workflow runs of a branch as a table, marking runs of
+// commits other than the branch head, followed by the failed jobs with the
+// IDs 'bgit ci logs' takes.
+func RenderCI(v CIView, width int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CI for %s at %s %s\n\n", headerStyle.Render(v.Branch), hashStyle.Render(shortSHA(v.Head)), mutedStyle.Render("("+v.Repo+")"))
+	if len(v.Runs) == 0 {
+		b.WriteString(mutedStyle.Render("No workflow runs.") + "\n")
+		return b.String()
+	}
+
+	headers := []string{"", "WORKFLOW", "EVENT", "STATE", "COMMIT", "UPDATED", "RUN"}
+	rows := make([][]string, 0, len(v.Runs))
+	for _, r := range v.Runs {
+		rows = append(rows, []string{stateMark(r.State), r.Workflow, r.Event, stateText(r.State), shortSHA(r.SHA), RelativeTime(r.When, v.Now), fmt.Sprint(r.ID)})
+	}
+
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = StringWidth(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], StringWidth(cell))
+		}
+	}
+	// Give the workflow column whatever the others leave over.
+	others := 0
+	for i, w := range widths {
+		if i != 1 {
+			others += w + 2
+		}
+	}
+	if widths[1]+others > width {
+		widths[1] = max(width-others, 10)
+	}
+
+	line := func(cells []string, style func(i int, s string) string) string {
+		var lb strings.Builder
+		for i, cell := range cells {
+			if i == 1 {
+				cell = TruncateMiddle(cell, widths[1])
+			}
+			cell += strings.Repeat(" ", widths[i]-StringWidth(cell))
+			if i > 0 {
+				lb.WriteString("  ")
+			}
+			lb.WriteString(style(i, cell))
+		}
+		return strings.TrimRight(lb.String(), " ") + "\n"
+	}
+
+	b.WriteString(line(headers, func(_ int, s string) string { return mutedStyle.Render(s) }))
+	for n, row := range rows {
+		r := v.Runs[n]
+		b.WriteString(line(row, func(i int, s string) string {
+			switch {
+			case i == 0 || i == 3:
+				return stateStyle(r.State).Render(s)
+			case i == 4 && r.SHA == v.Head:
+				return hashStyle.Render(s)
+			case i >= 4:
+				return mutedStyle.Render(s)
+			}
+			return s
+		}))
+	}
+
+	if len(v.Failed) > 0 {
+		b.WriteString("\n" + headerStyle.Render("Failed jobs") + "\n")
+		for _, j := range v.Failed {
+			mark := "  " + stateStyle(j.State).Render(stateMark(j.State)) + " "
+			text := j.Workflow + " › " + j.Name + "  " + mutedStyle.Render(fmt.Sprintf("job %d", j.ID))
+			b.WriteString(HangingIndent(mark, text, width) + "\n")
+			if j.Step != "" {
+				b.WriteString(HangingIndent("    ", mutedStyle.Render("failed at: "+j.Step), width) + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// stateMark is the glyph shown next to a run or job state.
+func stateMark(state string) string {
+	switch state {
+	case "success":
+		return "✓"
+	case "failure", "timed_out", "startup_failure":
+		return "✗"
+	case "in_progress":
+		return "●"
+	case "queued", "requested", "waiting", "pending":
+		return "○"
+	case "action_required":
+		return "!"
+	}
+	return "-"
+}
+
+// stateText spells a state out for reading: "in_progress" as "in progress".
+func stateText(state string) string {
+	return strings.ReplaceAll(state, "_", " ")
+}
+
+func stateStyle(state string) lipgloss.Style {
+	switch stateMark(state) {
+	case "✓":
+		return insertStyle
+	case "✗":
+		return deleteStyle
+	case "●", "○", "!":
+		return modifiedStyle
+	}
+	return mutedStyle
+}
+
+// shortSHA abbreviates a commit hash the way git does by default.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// logTimestamp matches the time GitHub puts at the start of every log line.
+var logTimestamp = regexp.MustCompile(`^\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d(\.\d+)?Z ?`)
+
+// RenderLogLine renders one line of a CI job log without its timestamp,
+// turning the workflow command markers into headers and colored
+// annotations. group reports whether the line starts a group, which for
+// Actions logs is usually a step; a line that ends one renders empty.
+func RenderLogLine(line string) (text string, group bool) {
+	line = logTimestamp.ReplaceAllString(strings.TrimSuffix(line, "\r"), "")
+	switch {
+	case strings.HasPrefix(line, "##[group]"):
+		return headerStyle.Render("▸ " + strings.TrimPrefix(line, "##[group]")), true
+	case strings.HasPrefix(line, "##[endgroup]"):
+		return "", false
+	case strings.HasPrefix(line, "##[error]"):
+		return deleteStyle.Render("error: " + strings.TrimPrefix(line, "##[error]")), false
+	case strings.HasPrefix(line, "##[warning]"):
+		return modifiedStyle.Render("warning: " + strings.TrimPrefix(line, "##[warning]")), false
+	case strings.HasPrefix(line, "##[notice]"):
+		return hunkStyle.Render("notice: " + strings.TrimPrefix(line, "##[notice]")), false
+	case strings.HasPrefix(line, "##[debug]"), strings.HasPrefix(line, "##[command]"):
+		_, rest, _ := strings.Cut(line, "]")
+		return mutedStyle.Render(rest), false
+	}
+	return line, false
+}
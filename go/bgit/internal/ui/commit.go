@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"strings"
+	"time"
+)
+
+// Person is a name/email pair as recorded on a commit.
+type Person struct {
+	Name  string
+	Email string
+}
+
+// CommitView describes a freshly created commit.
+type CommitView struct {
+	Hash      string
+	Author    Person
+	Committer Person
+	When      time.Time
+	Message   string
+	Stats     []FileStat // shown as a diffstat under the message when set
+	// Notes is a git note shown under the message, headed with NotesRef.
+	Notes    string
+	NotesRef string
+}
+
+// RenderCommitSummary renders the confirmation shown after a commit. The
+// message wraps at width so long AI subjects stay readable.
+func RenderCommitSummary(c CommitView, width int) string {
+	short := c.Hash
+	if len(short) > 7 {
+		short = short[:7]
+	}
+
+	var b strings.Builder
+	b.WriteString(okStyle.Render(Icon("✅")+"Commit created successfully!") + "\n")
+	b.WriteString("  " + Icon("📝") + "Hash: " + hashStyle.Render(short) + "\n")
+	b.WriteString("  " + Icon("👤") + "Author: " + c.Author.Name + " <" + c.Author.Email + ">\n")
+	if c.Committer != c.Author {
+		b.WriteString("  " + Icon("✉️ ") + "Committer: " + c.Committer.Name + " <" + c.Committer.Email + ">\n")
+	}
+	b.WriteString("  " + Icon("🕐") + "Date: " + c.When.Format(time.RFC1123) + "\n")
+
+	b.WriteString(HangingIndent("  "+Icon("📄")+"Message: ", strings.TrimSpace(c.Message), width))
+	b.WriteString("\n")
+
+	if len(c.Stats) > 0 {
+		b.WriteString("\n")
+		for _, line := range strings.SplitAfter(RenderDiffStat(c.Stats, width-1), "\n") {
+			if line != "" {
+				b.WriteString(" " + line)
+			}
+		}
+	}
+	return b.String()
+}
+
+// RenderCommit renders a commit for reading, in the layout of git show: the
+// full hash, author, date and the message indented under them, followed by
+// any note and the diffstat when Stats is set.
+func RenderCommit(c CommitView, width int) string {
+	var b strings.Builder
+	b.WriteString(hashStyle.Render("commit "+c.Hash) + "\n")
+	b.WriteString("Author: " + c.Author.Name + " <" + c.Author.Email + ">\n")
+	b.WriteString("Date:   " + c.When.Format(time.RFC1123) + "\n\n")
+	for _, line := range strings.Split(strings.TrimRight(c.Message, "\n"), "\n") {
+		b.WriteString(strings.TrimRight("    "+line, " ") + "\n")
+	}
+	if c.Notes != "" {
+		b.WriteString("\n" + mutedStyle.Render("Notes ("+c.NotesRef+"):") + "\n")
+		for _, line := range strings.Split(strings.TrimRight(c.Notes, "\n"), "\n") {
+			b.WriteString(strings.TrimRight("    "+line, " ") + "\n")
+		}
+	}
+	if len(c.Stats) > 0 {
+		b.WriteString("\n")
+		b.WriteString(RenderDiffStat(c.Stats, width))
+	}
+	return b.String()
+}
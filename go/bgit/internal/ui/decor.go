@@ -0,0 +1,31 @@
+package ui
+
+// plain disables emoji and other decorative glyphs. It is process-wide, in
+// the same way lipgloss.SetColorProfile is, because it reflects the
+// capabilities of the terminal rather than of any one view.
+var plain bool
+
+// SetPlain switches every renderer to undecorated ASCII output: no emoji
+// and "-" instead of "•" for bullets. Meant for CI logs and terminals
+// without emoji fonts.
+func SetPlain(on bool) { plain = on }
+
+// Plain reports whether decorations are disabled.
+func Plain() bool { return plain }
+
+// Icon returns emoji followed by a space, or nothing in plain mode, so it can
+// be prefixed to a label unconditionally.
+func Icon(emoji string) string {
+	if plain {
+		return ""
+	}
+	return emoji + " "
+}
+
+// Bullet is the list marker used throughout bgit's output.
+func Bullet() string {
+	if plain {
+		return "-"
+	}
+	return "•"
+}
@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FileStat is one file of a diffstat. Path is "old => new" for renames.
+// Generated files (lockfiles, vendored code) are left out of the histogram.
+type FileStat struct {
+	Path       string `json:"path"`
+	Insertions int    `json:"insertions"`
+	Deletions  int    `json:"deletions"`
+	Binary     bool   `json:"binary"`
+	Generated  bool   `json:"generated,omitempty"`
+}
+
+// minBarWidth is the room kept for the histogram before long paths are
+// shortened.
+const minBarWidth = 10
+
+// RenderDiffStat renders stats the way git diff --stat does: one row per file
+// with its change count and a +/- histogram, followed by the totals. Bars are
+// scaled down so the widest one fits in width. Generated files get no row;
+// a note after the totals says how many were hidden.
+func RenderDiffStat(all []FileStat, width int) string {
+	if len(all) == 0 {
+		return ""
+	}
+	var stats, hidden []FileStat
+	for _, st := range all {
+		if st.Generated {
+			hidden = append(hidden, st)
+		} else {
+			stats = append(stats, st)
+		}
+	}
+	if len(stats) == 0 {
+		return " " + generatedNote(hidden) + "\n"
+	}
+
+	nameWidth, most, countWidth := 0, 0, 0
+	for _, st := range stats {
+		nameWidth = max(nameWidth, StringWidth(st.Path))
+		most = max(most, st.Insertions+st.Deletions)
+		if st.Binary {
+			countWidth = max(countWidth, len("Bin"))
+		}
+	}
+	countWidth = max(countWidth, len(strconv.Itoa(most)))
+
+	// " name | count bar": everything but the bar and the name is fixed.
+	fixed := 1 + 3 + countWidth + 1
+	if width-fixed-nameWidth < minBarWidth {
+		nameWidth = max(width-fixed-minBarWidth, minBarWidth)
+	}
+	barWidth := max(width-fixed-nameWidth, 1)
+
+	var b strings.Builder
+	for _, st := range stats {
+		name := TruncateMiddle(st.Path, nameWidth)
+		b.WriteString(" " + name + strings.Repeat(" ", nameWidth-StringWidth(name)) + " | ")
+		if st.Binary {
+			b.WriteString(fmt.Sprintf("%*s\n", countWidth, "Bin"))
+			continue
+		}
+		b.WriteString(fmt.Sprintf("%*d", countWidth, st.Insertions+st.Deletions))
+		plus, minus := scaleBar(st.Insertions, st.Deletions, most, barWidth)
+		if plus+minus > 0 {
+			b.WriteString(" " + insertStyle.Render(strings.Repeat("+", plus)) + deleteStyle.Render(strings.Repeat("-", minus)))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(" " + DiffStatSummary(stats) + "\n")
+	if len(hidden) > 0 {
+		b.WriteString(" " + generatedNote(hidden) + "\n")
+	}
+	return b.String()
+}
+
+// generatedNote is the muted "+ 2 generated files (+120 -40)" line that
+// stands in for hidden rows.
+func generatedNote(hidden []FileStat) string {
+	ins, del := 0, 0
+	for _, st := range hidden {
+		ins += st.Insertions
+		del += st.Deletions
+	}
+	return mutedStyle.Render(fmt.Sprintf("+ %s not shown (+%d -%d)", pluralize(len(hidden), "generated file", "generated files"), ins, del))
+}
+
+// scaleBar splits a bar of at most width cells between insertions and
+// deletions in proportion to the largest change. Any change gets at least
+// one cell, so small edits next to large ones stay visible.
+func scaleBar(ins, del, most, width int) (plus, minus int) {
+	if most <= width {
+		return ins, del
+	}
+	scale := func(n int) int {
+		if n == 0 {
+			return 0
+		}
+		return max(n*width/most, 1)
+	}
+	total := scale(ins + del)
+	plus = scale(ins)
+	if plus > total {
+		plus = total
+	}
+	minus = total - plus
+	if del > 0 && minus == 0 && total > 1 {
+		plus, minus = plus-1, 1
+	}
+	return plus, minus
+}
+
+// DiffStatSummary is the totals line of a diffstat, e.g.
+// "3 files changed, 10 insertions(+), 2 deletions(-)".
+func DiffStatSummary(stats []FileStat) string {
+	ins, del := 0, 0
+	for _, st := range stats {
+		ins += st.Insertions
+		del += st.Deletions
+	}
+
+	parts := []string{pluralize(len(stats), "file", "files") + " changed"}
+	if ins > 0 || del == 0 {
+		parts = append(parts, pluralize(ins, "insertion", "insertions")+"(+)")
+	}
+	if del > 0 || ins == 0 {
+		parts = append(parts, pluralize(del, "deletion", "deletions")+"(-)")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// RenderNumstat renders stats in git's --numstat machine format: insertions,
+// deletions and path separated by tabs, with "-" counts for binary files.
+// It is never styled.
+func RenderNumstat(stats []FileStat) string {
+	var b strings.Builder
+	for _, st := range stats {
+		if st.Binary {
+			fmt.Fprintf(&b, "-\t-\t%s\n", st.Path)
+			continue
+		}
+		fmt.Fprintf(&b, "%d\t%d\t%s\n", st.Insertions, st.Deletions, st.Path)
+	}
+	return b.String()
+}
+
+func pluralize(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
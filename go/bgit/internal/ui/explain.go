@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"strings"
+)
+
+// ExplainView is an AI explanation of a commit or of the staged changes.
+type ExplainView struct {
+	// Subject names what was explained, e.g. "3fd3808 Add the heatmap".
+	Subject  string
+	Provider string
+	// Text is the explanation, wrapped already; Markdown headings in it are
+	// shown bold without their #s.
+	Text string
+	// Note says what the provider was not shown, if anything.
+	Note string
+}
+
+// RenderExplanation renders v under a title naming what was explained and
+// who explained it.
+func RenderExplanation(v ExplainView, width int) string {
+	var b strings.Builder
+	title := headerStyle.Render(TruncateMiddle("Explanation of "+v.Subject, max(width-StringWidth(v.Provider)-3, 10)))
+	if v.Provider != "" {
+		title += mutedStyle.Render(" · " + v.Provider)
+	}
+	b.WriteString(title + "\n\n")
+
+	fenced := false
+	for _, line := range strings.Split(strings.TrimSpace(v.Text), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			fenced = !fenced
+		}
+		if !fenced && strings.HasPrefix(trimmed, "#") {
+			line = headerStyle.Render(strings.TrimSpace(strings.TrimLeft(trimmed, "#")))
+		}
+		b.WriteString(line + "\n")
+	}
+	if v.Note != "" {
+		b.WriteString("\n" + mutedStyle.Render(v.Note) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// GraphCommit is one commit in a history graph.
+type GraphCommit struct {
+	Hash    string
+	Parents []string
+	Subject string
+	Author  string
+	When    time.Time
+}
+
+// GraphView is a history drawn as a graph, newest commit first, the way
+// git log --graph draws it.
+type GraphView struct {
+	Commits []GraphCommit
+	// Now is what commit dates are shown relative to.
+	Now time.Time
+	// Oneline leaves out the author and date.
+	Oneline bool
+}
+
+// laneStyles colour the lines of the graph, one colour per column, cycling
+// when there are more columns than colours.
+var laneStyles = []lipgloss.Style{
+	lipgloss.NewStyle().Foreground(lipgloss.Color("39")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("42")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("170")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("81")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("203")),
+}
+
+// graphCell is one character of the graph and the column whose line it
+// belongs to, for its colour.
+type graphCell struct {
+	glyph string
+	lane  int
+}
+
+// graphRow is one line of the graph, two characters per column: the line
+// itself and the gap to the right of it, where lines cross between columns.
+type graphRow []graphCell
+
+func newGraphRow(lanes int) graphRow {
+	row := make(graphRow, 2*lanes)
+	for i := range row {
+		row[i] = graphCell{glyph: " ", lane: i / 2}
+	}
+	return row
+}
+
+func (r graphRow) set(pos int, glyph string, lane int) {
+	if pos >= 0 && pos < len(r) {
+		r[pos] = graphCell{glyph: glyph, lane: lane}
+	}
+}
+
+func (r graphRow) String() string {
+	var b strings.Builder
+	for _, c := range r {
+		if c.glyph == " " {
+			b.WriteString(" ")
+			continue
+		}
+		b.WriteString(laneStyles[c.lane%len(laneStyles)].Render(c.glyph))
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// graphSection is the part of the graph drawn for one commit: the row of
+// the commit itself, in column col, then the rows that carry the graph down
+// to the next commit.
+type graphSection struct {
+	col  int
+	rows []graphRow
+}
+
+// RenderGraph draws v one section per commit: the commit's own line with
+// its short hash, subject, author and date, followed by the lines that
+// carry the graph down to the next commit.
+func RenderGraph(v GraphView, width int) []string {
+	sections := make([]string, 0, len(v.Commits))
+	for n, s := range layoutGraph(v.Commits) {
+		var b strings.Builder
+		graph := s.rows[0].String()
+		b.WriteString(graph + " " + graphText(v, v.Commits[n], width-StringWidth(graph)-1) + "\n")
+		for _, row := range s.rows[1:] {
+			b.WriteString(row.String() + "\n")
+		}
+		sections = append(sections, b.String())
+	}
+	return sections
+}
+
+// layoutGraph lays out the graph of commits, newest first, keeping one
+// column per line of history: the terminal graph and the exported ones
+// draw the same layout.
+func layoutGraph(commits []GraphCommit) []graphSection {
+	// lanes holds the commit each column of the graph is heading for.
+	var lanes []string
+	sections := make([]graphSection, 0, len(commits))
+	for n, c := range commits {
+		col := -1
+		for i, h := range lanes {
+			if h == c.Hash {
+				col = i
+				break
+			}
+		}
+		if col < 0 {
+			lanes = append(lanes, c.Hash)
+			col = len(lanes) - 1
+		}
+
+		row := newGraphRow(len(lanes))
+		for i := range lanes {
+			row.set(2*i, "|", i)
+		}
+		row.set(2*col, "*", col)
+		s := graphSection{col: col, rows: []graphRow{row}}
+
+		switch {
+		case len(c.Parents) == 0:
+			// A root commit ends its column; the ones right of it close up.
+			if col < len(lanes)-1 {
+				row := newGraphRow(len(lanes))
+				for i := 0; i < col; i++ {
+					row.set(2*i, "|", i)
+				}
+				for i := col + 1; i < len(lanes); i++ {
+					row.set(2*i-1, "/", i)
+				}
+				s.rows = append(s.rows, row)
+			}
+			lanes = append(lanes[:col], lanes[col+1:]...)
+		default:
+			lanes[col] = c.Parents[0]
+			// Each further parent of a merge opens a column right of this
+			// one, pushing the others over.
+			for k, parent := range c.Parents[1:] {
+				at := col + 1 + k
+				row := newGraphRow(len(lanes) + 1)
+				for i := 0; i < at; i++ {
+					row.set(2*i, "|", i)
+				}
+				for i := at; i < len(lanes); i++ {
+					row.set(2*i+1, "\\", i+1)
+				}
+				row.set(2*at-1, "\\", at)
+				s.rows = append(s.rows, row)
+				lanes = append(lanes[:at], append([]string{parent}, lanes[at:]...)...)
+			}
+		}
+		if n+1 < len(commits) {
+			lanes = joinLanes(&s, lanes, commits[n+1].Hash)
+		}
+		sections = append(sections, s)
+	}
+	return sections
+}
+
+// joinLanes draws the columns heading for the same commit joining the
+// leftmost of them, as they do just above that commit, and returns the
+// columns left.
+func joinLanes(s *graphSection, lanes []string, hash string) []string {
+	col := -1
+	for i, h := range lanes {
+		if h == hash {
+			col = i
+			break
+		}
+	}
+	if col < 0 {
+		return lanes
+	}
+	for j := len(lanes) - 1; j > col; j-- {
+		if lanes[j] != hash {
+			continue
+		}
+		row := newGraphRow(len(lanes))
+		for i := 0; i < j; i++ {
+			row.set(2*i, "|", i)
+		}
+		for i := col; i < j-1; i++ {
+			row.set(2*i+1, "_", j)
+		}
+		row.set(2*j-1, "/", j)
+		for i := j + 1; i < len(lanes); i++ {
+			row.set(2*i-1, "/", i)
+		}
+		s.rows = append(s.rows, row)
+		lanes = append(lanes[:j], lanes[j+1:]...)
+	}
+	return lanes
+}
+
+// graphText is the part of a commit's line right of the graph, fitted to
+// width.
+func graphText(v GraphView, c GraphCommit, width int) string {
+	short := shortHash(c.Hash)
+	meta := ""
+	if !v.Oneline {
+		meta = c.Author + ", " + RelativeTime(c.When, v.Now)
+	}
+	room := width - len(short) - 1
+	if meta != "" && room-StringWidth(meta)-2 < 20 {
+		// Too narrow for both: the subject comes first.
+		meta = ""
+	}
+	if meta != "" {
+		room -= StringWidth(meta) + 2
+	}
+	text := hashStyle.Render(short) + " " + ansi.Truncate(c.Subject, max(room, 1), "…")
+	if meta != "" {
+		text += "  " + mutedStyle.Render(meta)
+	}
+	return text
+}
@@ -0,0 +1,218 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// laneColors are the colours of laneStyles as CSS colours, for the
+// exported graphs: xterm's 39, 42, 214, 170, 81 and 203.
+var laneColors = []string{"#00afff", "#00d787", "#ffaf00", "#d75fd7", "#5fd7ff", "#ff5f5f"}
+
+// Sizes of the exported SVG, in pixels. A column of the graph is two cells
+// wide, like two characters of the terminal graph.
+const (
+	svgMargin    = 12
+	svgCell      = 8
+	svgCommitRow = 22 // a row with a commit and its text
+	svgLinkRow   = 12 // a row that only carries lines down
+	svgCharWidth = 7.2
+	svgDot       = 4
+)
+
+// svgPoint is a point of the graph: a cell position along a row, and
+// whether it is at the bottom of the row rather than the top.
+type svgPoint struct {
+	pos    int
+	bottom bool
+}
+
+// glyphSegment is the line a glyph of the terminal graph stands for, from
+// one point of its row to another. Every line ends in a column, so the rows
+// above and below meet it.
+func glyphSegment(glyph string, pos int) (from, to svgPoint) {
+	switch glyph {
+	case "/":
+		return svgPoint{pos + 1, false}, svgPoint{pos - 1, true}
+	case "\\":
+		return svgPoint{pos - 1, false}, svgPoint{pos + 1, true}
+	case "_":
+		return svgPoint{pos - 1, true}, svgPoint{pos + 1, true}
+	}
+	return svgPoint{pos, false}, svgPoint{pos, true}
+}
+
+// RenderGraphSVG draws v as an SVG image, laid out like the terminal graph:
+// a dot per commit with its short hash, subject and, unless v.Oneline, its
+// author and date, and the lines of history between them. Hovering over a
+// commit shows its full hash.
+func RenderGraphSVG(v GraphView) string {
+	sections := layoutGraph(v.Commits)
+	cells := 0
+	for _, s := range sections {
+		for _, row := range s.rows {
+			cells = max(cells, len(row))
+		}
+	}
+	textX := svgMargin + cells*svgCell + svgCell
+	longest := 0
+	for _, c := range v.Commits {
+		longest = max(longest, StringWidth(graphLabel(v, c)))
+	}
+
+	var body strings.Builder
+	x := func(pos int) int { return svgMargin + pos*svgCell + svgCell/2 }
+	line := func(x1, y1, x2, y2, lane int) {
+		fmt.Fprintf(&body, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s"/>`+"\n",
+			x1, y1, x2, y2, laneColors[lane%len(laneColors)])
+	}
+	y := svgMargin
+	ends := map[int]bool{} // positions where a line left the row above
+	for n, s := range sections {
+		c := v.Commits[n]
+		for r, row := range s.rows {
+			height := svgLinkRow
+			if r == 0 {
+				height = svgCommitRow
+			}
+			next := map[int]bool{}
+			for pos, cell := range row {
+				switch cell.glyph {
+				case " ":
+					continue
+				case "*":
+					// The line through a commit comes from its children
+					// and goes on to its parents.
+					if ends[pos] {
+						line(x(pos), y, x(pos), y+height/2, cell.lane)
+					}
+					if len(c.Parents) > 0 {
+						line(x(pos), y+height/2, x(pos), y+height, cell.lane)
+						next[pos] = true
+					}
+					continue
+				}
+				from, to := glyphSegment(cell.glyph, pos)
+				y1, y2 := y, y+height
+				if from.bottom {
+					y1 = y2
+				}
+				line(x(from.pos), y1, x(to.pos), y2, cell.lane)
+				next[to.pos] = true
+			}
+			if r == 0 {
+				color := laneColors[s.col%len(laneColors)]
+				fmt.Fprintf(&body, `<g class="commit"><title>%s</title>`, html.EscapeString(c.Hash))
+				fmt.Fprintf(&body, `<circle cx="%d" cy="%d" r="%d" fill="%s"/>`, x(2*s.col), y+height/2, svgDot, color)
+				fmt.Fprintf(&body, `<text x="%d" y="%d">%s</text></g>`+"\n", textX, y+height/2+4, svgText(v, c))
+			}
+			ends = next
+			y += height
+		}
+	}
+
+	width := textX + int(float64(longest)*svgCharWidth) + svgMargin
+	height := y + svgMargin
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	b.WriteString(`<style>
+line { stroke-width: 2; stroke-linecap: round; }
+text { font: 12px ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; fill: #24292f; }
+.hash { fill: #b08800; }
+.meta { fill: #6e7781; }
+</style>
+`)
+	b.WriteString(body.String())
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// graphLabel is the text shown right of a commit in the exported graphs.
+// Dates are written out rather than relative, as the export is read later.
+func graphLabel(v GraphView, c GraphCommit) string {
+	label := shortHash(c.Hash) + " " + c.Subject
+	if !v.Oneline {
+		label += "  " + graphMeta(c)
+	}
+	return label
+}
+
+func graphMeta(c GraphCommit) string {
+	return c.Author + ", " + c.When.Format("2006-01-02")
+}
+
+// svgText is graphLabel as the contents of an SVG text element.
+func svgText(v GraphView, c GraphCommit) string {
+	text := `<tspan class="hash">` + html.EscapeString(shortHash(c.Hash)) + "</tspan> " + html.EscapeString(c.Subject)
+	if !v.Oneline {
+		text += `<tspan class="meta" dx="14">` + html.EscapeString(graphMeta(c)) + "</tspan>"
+	}
+	return text
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// RenderGraphHTML is a web page showing the SVG graph of v under title.
+func RenderGraphHTML(v GraphView, title string) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(title))
+	b.WriteString(`<style>
+body { margin: 2rem; font-family: system-ui, sans-serif; color: #24292f; }
+h1 { font-size: 1.25rem; font-weight: 600; }
+.graph { overflow-x: auto; }
+</style>
+</head>
+<body>
+`)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", html.EscapeString(title))
+	fmt.Fprintf(&b, "<p>%s</p>\n", pluralize(len(v.Commits), "commit", "commits"))
+	b.WriteString("<div class=\"graph\">\n" + RenderGraphSVG(v) + "</div>\n</body>\n</html>\n")
+	return b.String()
+}
+
+// RenderGraphDOT writes v in Graphviz's DOT language, a node per commit
+// coloured like its column of the terminal graph and an edge to each parent
+// shown, for dot to lay out.
+func RenderGraphDOT(v GraphView) string {
+	shown := make(map[string]bool, len(v.Commits))
+	for _, c := range v.Commits {
+		shown[c.Hash] = true
+	}
+	var b strings.Builder
+	b.WriteString("digraph history {\n")
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [shape=box, style=\"rounded\", fontname=\"monospace\", fontsize=10];\n")
+	b.WriteString("  edge [arrowhead=none];\n")
+	for n, s := range layoutGraph(v.Commits) {
+		c := v.Commits[n]
+		label := shortHash(c.Hash) + " " + c.Subject
+		if !v.Oneline {
+			label += "\n" + graphMeta(c)
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, color=%q];\n", dotID(c.Hash), dotID(label), laneColors[s.col%len(laneColors)])
+	}
+	for _, c := range v.Commits {
+		for _, p := range c.Parents {
+			if shown[p] {
+				fmt.Fprintf(&b, "  %s -> %s;\n", dotID(c.Hash), dotID(p))
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotID quotes s as a DOT identifier.
+func dotID(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,146 @@
+// Package heatmap is the interactive contribution heatmap: a day is picked
+// with the arrow keys, and the commits of that day are listed under the
+// map.
+package heatmap
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/endalk200/bgit/internal/ui"
+)
+
+var mutedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+
+// Fallback size, used until the terminal reports its own.
+const (
+	fallbackWidth  = 80
+	fallbackHeight = 24
+)
+
+type model struct {
+	view ui.ActivityView
+	// commits are the commits of each day, keyed by ui.DayLayout.
+	commits map[string][]ui.DayCommit
+	width   int
+	height  int
+}
+
+func newModel(view ui.ActivityView, commits map[string][]ui.DayCommit, width, height int) model {
+	if view.Selected.IsZero() {
+		view.Selected = view.To
+	}
+	m := model{view: view, commits: commits, width: width, height: height}
+	m.clamp()
+	return m
+}
+
+func (m model) Init() tea.Cmd { return nil }
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.clamp()
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return m, tea.Quit
+		case "left", "h":
+			m.move(-7)
+		case "right", "l":
+			m.move(7)
+		case "up", "k":
+			m.move(-1)
+		case "down", "j":
+			m.move(1)
+		case "n":
+			m.jump(1)
+		case "N", "p":
+			m.jump(-1)
+		case "home", "g":
+			m.view.Selected, _ = ui.ActivityRange(m.view, m.size())
+		case "end", "G", "t":
+			m.view.Selected = m.view.To
+		}
+	}
+	return m, nil
+}
+
+func (m model) size() int {
+	if m.width <= 0 {
+		return fallbackWidth
+	}
+	return m.width
+}
+
+// move shifts the selection by days, staying on the map.
+func (m *model) move(days int) {
+	m.view.Selected = m.view.Selected.AddDate(0, 0, days)
+	m.clamp()
+}
+
+// jump moves to the next day with commits in direction dir (1 or -1), if
+// there is one on the map.
+func (m *model) jump(dir int) {
+	first, last := ui.ActivityRange(m.view, m.size())
+	for d := m.view.Selected.AddDate(0, 0, dir); !d.Before(first) && !d.After(last); d = d.AddDate(0, 0, dir) {
+		if len(m.commits[d.Format(ui.DayLayout)]) > 0 {
+			m.view.Selected = d
+			return
+		}
+	}
+}
+
+// clamp keeps the selection on a day the map shows.
+func (m *model) clamp() {
+	first, last := ui.ActivityRange(m.view, m.size())
+	switch {
+	case m.view.Selected.Before(first):
+		m.view.Selected = first
+	case m.view.Selected.After(last):
+		m.view.Selected = last
+	}
+}
+
+func (m model) View() string {
+	width := m.size()
+	height := m.height
+	if height <= 0 {
+		height = fallbackHeight
+	}
+
+	top := ui.RenderActivity(m.view, width)
+	day := m.view.Selected
+	list := ui.RenderDayCommits(day, m.commits[day.Format(ui.DayLayout)], width)
+	help := mutedStyle.Render(ansi.Truncate("←/→ week · ↑/↓ day · n/N next/prev day with commits · t last day · q quit", width, "…"))
+
+	// The list gets what the map leaves, less the help line.
+	room := max(height-strings.Count(top, "\n")-2, 1)
+	lines := strings.Split(strings.TrimSuffix(list, "\n"), "\n")
+	if len(lines) > room {
+		more := len(lines) - room + 1
+		lines = append(lines[:room-1], mutedStyle.Render(moreLine(more)))
+	}
+	return top + "\n" + strings.Join(lines, "\n") + strings.Repeat("\n", room-len(lines)+1) + help
+}
+
+// moreLine stands in for the n commits there is no room for.
+func moreLine(n int) string {
+	if n == 1 {
+		return "  … 1 more commit"
+	}
+	return fmt.Sprintf("  … %d more commits", n)
+}
+
+// Run shows the heatmap full screen on w until the user quits.
+func Run(w io.Writer, in io.Reader, view ui.ActivityView, commits map[string][]ui.DayCommit) error {
+	p := tea.NewProgram(newModel(view, commits, 0, 0),
+		tea.WithOutput(w), tea.WithInput(in), tea.WithAltScreen(), tea.WithoutSignalHandler())
+	_, err := p.Run()
+	return err
+}
@@ -0,0 +1,83 @@
+package heatmap
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/endalk200/bgit/internal/ui"
+	"github.com/endalk200/bgit/internal/ui/uitest"
+)
+
+func date(month time.Month, day int) time.Time {
+	return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// fixture spans March 2025 with commits on three days.
+func fixture() (ui.ActivityView, map[string][]ui.DayCommit) {
+	commits := map[string][]ui.DayCommit{}
+	counts := map[string]int{}
+	for i, day := range []time.Time{date(time.March, 3), date(time.March, 12), date(time.March, 13)} {
+		key := day.Format(ui.DayLayout)
+		for n := 0; n <= i; n++ {
+			commits[key] = append(commits[key], ui.DayCommit{
+				Hash:    "3fd3808a1b2c3d4e5f60718293a4b5c6d7e8f901",
+				Subject: "Change " + key,
+				Author:  "Alice Example",
+				When:    day.Add(time.Duration(9+n) * time.Hour),
+			})
+		}
+		counts[key] = i + 1
+	}
+	return ui.ActivityView{From: date(time.March, 1), To: date(time.March, 31), Counts: counts}, commits
+}
+
+func selected(m tea.Model) time.Time { return m.(model).view.Selected }
+
+func TestMovingAndJumping(t *testing.T) {
+	view, commits := fixture()
+	var m tea.Model = newModel(view, commits, 60, 20)
+	if got := selected(m); !got.Equal(date(time.March, 31)) {
+		t.Fatalf("starts on %s, want the last day", got.Format(ui.DayLayout))
+	}
+
+	m = uitest.Press(m, "left")
+	m = uitest.Press(m, "up")
+	if got := selected(m); !got.Equal(date(time.March, 23)) {
+		t.Fatalf("left, up selected %s, want 2025-03-23", got.Format(ui.DayLayout))
+	}
+
+	m = uitest.Press(m, "N")
+	uitest.AssertGolden(t, "busiest", m.View())
+	m = uitest.Press(m, "N")
+	m = uitest.Press(m, "N")
+	if got := selected(m); !got.Equal(date(time.March, 3)) {
+		t.Fatalf("N N N selected %s, want 2025-03-03", got.Format(ui.DayLayout))
+	}
+	m = uitest.Press(m, "N")
+	if got := selected(m); !got.Equal(date(time.March, 3)) {
+		t.Fatalf("N past the first day with commits moved to %s", got.Format(ui.DayLayout))
+	}
+
+	m = uitest.Press(m, "n")
+	if got := selected(m); !got.Equal(date(time.March, 12)) {
+		t.Fatalf("n selected %s, want 2025-03-12", got.Format(ui.DayLayout))
+	}
+
+	// Moving off the map stays on its first day.
+	m = uitest.Press(m, "left")
+	m = uitest.Press(m, "left")
+	if got := selected(m); !got.Equal(date(time.March, 1)) {
+		t.Fatalf("left off the map selected %s, want 2025-03-01", got.Format(ui.DayLayout))
+	}
+}
+
+func TestLongDayIsClipped(t *testing.T) {
+	view, commits := fixture()
+	key := date(time.March, 13).Format(ui.DayLayout)
+	for len(commits[key]) < 30 {
+		commits[key] = append(commits[key], commits[key][0])
+	}
+	view.Selected = date(time.March, 13)
+	uitest.AssertGolden(t, "clipped", newModel(view, commits, 60, 20).View())
+}
@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// IssueRow is one issue of the issue list.
+type IssueRow struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	State     string    `json:"state"`
+	Author    string    `json:"author"`
+	Labels    []string  `json:"labels"`
+	Assignees []string  `json:"assignees"`
+	Comments  int       `json:"comments"`
+	When      time.Time `json:"updated_at"`
+	URL       string    `json:"url"`
+}
+
+// IssueList is a page of issues of a repository.
+type IssueList struct {
+	Repo string
+	// State is what was listed: open, closed or all.
+	State  string
+	Issues []IssueRow
+	Now    time.Time
+}
+
+// RenderIssueList renders issues as a table of number, title, labels,
+// assignees and last update, giving the title whatever width is left.
+func RenderIssueList(l IssueList, width int) string {
+	var b strings.Builder
+	what := "Issues"
+	if l.State != "all" {
+		what = strings.ToUpper(l.State[:1]) + l.State[1:] + " issues"
+	}
+	fmt.Fprintf(&b, "%s in %s\n\n", what, headerStyle.Render(l.Repo))
+	if len(l.Issues) == 0 {
+		b.WriteString(mutedStyle.Render("No issues.") + "\n")
+		return b.String()
+	}
+
+	headers := []string{"ISSUE", "TITLE", "LABELS", "ASSIGNEES", "UPDATED"}
+	rows := make([][]string, 0, len(l.Issues))
+	for _, i := range l.Issues {
+		rows = append(rows, []string{
+			fmt.Sprintf("#%d", i.Number),
+			i.Title,
+			TruncateMiddle(strings.Join(i.Labels, ", "), 24),
+			TruncateMiddle(strings.Join(i.Assignees, ", "), 16),
+			RelativeTime(i.When, l.Now),
+		})
+	}
+
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = StringWidth(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], StringWidth(cell))
+		}
+	}
+	// Give the title column whatever the others leave over.
+	others := 0
+	for i, w := range widths {
+		if i != 1 {
+			others += w + 2
+		}
+	}
+	if widths[1]+others > width {
+		widths[1] = max(width-others, 10)
+	}
+
+	line := func(cells []string, style func(i int, s string) string) string {
+		var lb strings.Builder
+		for i, cell := range cells {
+			if i == 1 {
+				cell = truncateEnd(cell, widths[1])
+			}
+			cell += strings.Repeat(" ", widths[i]-StringWidth(cell))
+			if i > 0 {
+				lb.WriteString("  ")
+			}
+			lb.WriteString(style(i, cell))
+		}
+		return strings.TrimRight(lb.String(), " ") + "\n"
+	}
+
+	b.WriteString(line(headers, func(_ int, s string) string { return mutedStyle.Render(s) }))
+	for n, row := range rows {
+		closed := l.Issues[n].State == "closed"
+		b.WriteString(line(row, func(i int, s string) string {
+			switch {
+			case i == 0 && closed:
+				return mutedStyle.Render(s)
+			case i == 0:
+				return stagedStyle.Render(s)
+			case i == 2:
+				return hunkStyle.Render(s)
+			case i >= 3:
+				return mutedStyle.Render(s)
+			}
+			return s
+		}))
+	}
+	return b.String()
+}
+
+// IssueView is one issue in full.
+type IssueView struct {
+	IssueRow
+	Body string `json:"body"`
+	// Branch is the local branch started for the issue, if any.
+	Branch  string    `json:"branch,omitempty"`
+	Created time.Time `json:"created_at"`
+	Now     time.Time `json:"-"`
+}
+
+// RenderIssue renders an issue: its title, state and people, then the body
+// as written, wrapped to width.
+func RenderIssue(v IssueView, width int) string {
+	var b strings.Builder
+	b.WriteString(HangingIndent(hashStyle.Render(fmt.Sprintf("#%d", v.Number))+" ", headerStyle.Render(v.Title), width) + "\n")
+
+	state := stagedStyle.Render(v.State)
+	if v.State == "closed" {
+		state = deletedStyle.Render(v.State)
+	}
+	meta := fmt.Sprintf("%s · opened by %s %s · %s", state, v.Author, RelativeTime(v.Created, v.Now), pluralize(v.Comments, "comment", "comments"))
+	b.WriteString(HangingIndent("", meta, width) + "\n")
+	if len(v.Labels) > 0 {
+		b.WriteString(HangingIndent(mutedStyle.Render("Labels:    "), hunkStyle.Render(strings.Join(v.Labels, ", ")), width) + "\n")
+	}
+	if len(v.Assignees) > 0 {
+		b.WriteString(HangingIndent(mutedStyle.Render("Assignees: "), strings.Join(v.Assignees, ", "), width) + "\n")
+	}
+	if v.Branch != "" {
+		b.WriteString(HangingIndent(mutedStyle.Render("Branch:    "), v.Branch, width) + "\n")
+	}
+
+	body := strings.TrimSpace(strings.ReplaceAll(v.Body, "\r\n", "\n"))
+	if body == "" {
+		body = mutedStyle.Render("No description provided.")
+	}
+	b.WriteString("\n")
+	for _, line := range strings.Split(body, "\n") {
+		b.WriteString(ansi.Wrap(line, max(width, 1), "") + "\n")
+	}
+	if v.URL != "" {
+		b.WriteString("\n" + mutedStyle.Render(v.URL) + "\n")
+	}
+	return b.String()
+}
+
+// truncateEnd shortens s to at most width cells, ending it with an ellipsis,
+// for text such as titles whose start matters most.
+func truncateEnd(s string, width int) string {
+	if StringWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	return takeHead(s, width-1) + "…"
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StackBelow is the width under which multi-column layouts collapse into a
+// single stacked column.
+const StackBelow = 100
+
+// columnGap separates side-by-side columns.
+const columnGap = "   "
+
+// Columns places blocks side by side when the terminal is wide enough and
+// stacks them otherwise. Empty blocks are dropped so a missing section does
+// not leave a hole.
+func Columns(width int, blocks ...string) string {
+	var present []string
+	for _, b := range blocks {
+		if strings.TrimSpace(b) != "" {
+			present = append(present, strings.TrimRight(b, "\n"))
+		}
+	}
+	if len(present) == 0 {
+		return ""
+	}
+
+	if width < StackBelow || len(present) == 1 {
+		return strings.Join(present, "\n") + "\n"
+	}
+
+	colWidth := ColumnWidth(width, len(present))
+	cells := make([]string, 0, 2*len(present)-1)
+	for i, b := range present {
+		if i > 0 {
+			cells = append(cells, columnGap)
+		}
+		cells = append(cells, lipgloss.NewStyle().Width(colWidth).Render(b))
+	}
+
+	// lipgloss pads every column to its full width; trailing padding on the
+	// last column is noise in a terminal.
+	joined := lipgloss.JoinHorizontal(lipgloss.Top, cells...)
+	lines := strings.Split(joined, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// ColumnWidth is the width available to each of n columns laid out by
+// Columns at the given terminal width. Below StackBelow every block gets the
+// whole width.
+func ColumnWidth(width, n int) int {
+	if width < StackBelow || n <= 1 {
+		return width
+	}
+	return max((width-(n-1)*StringWidth(columnGap))/n, 1)
+}
+
+// TruncateMiddle shortens s to at most width cells by replacing its middle
+// with an ellipsis. For paths the final element is preserved whenever it
+// fits, because the file name is usually what the reader is looking for.
+func TruncateMiddle(s string, width int) string {
+	if StringWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	if width == 1 {
+		return "…"
+	}
+
+	avail := width - 1 // room left after the ellipsis
+	tailWidth := avail - avail/2
+	if i := strings.LastIndex(s, "/"); i >= 0 {
+		if base := s[i:]; StringWidth(base) < avail {
+			tailWidth = max(tailWidth, StringWidth(base))
+		}
+	}
+	headWidth := avail - tailWidth
+
+	return takeHead(s, headWidth) + "…" + takeTail(s, tailWidth)
+}
+
+// takeHead returns the longest prefix of s that fits in width cells.
+func takeHead(s string, width int) string {
+	clusters, widths := graphemes(s)
+	w, n := 0, 0
+	for i, c := range clusters {
+		if w+widths[i] > width {
+			break
+		}
+		w += widths[i]
+		n += len(c)
+	}
+	return s[:n]
+}
+
+// takeTail returns the longest suffix of s that fits in width cells.
+func takeTail(s string, width int) string {
+	clusters, widths := graphemes(s)
+	w, n := 0, 0
+	for i := len(clusters) - 1; i >= 0; i-- {
+		if w+widths[i] > width {
+			break
+		}
+		w += widths[i]
+		n += len(clusters[i])
+	}
+	return s[len(s)-n:]
+}
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTruncateMiddle(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		width int
+		want  string
+	}{
+		{"fits", "cmd/status.go", 20, "cmd/status.go"},
+		{"keeps file name", "internal/services/git/service.go", 20, "internal…/service.go"},
+		{"splits when base too long", "a/very_long_file_name_indeed.go", 10, "a/ve…ed.go"},
+		{"no slash", "abcdefghijklmnop", 7, "abc…nop"},
+		{"one cell", "abcdef", 1, "…"},
+		{"zero cells", "abcdef", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateMiddle(tt.in, tt.width); got != tt.want {
+				t.Errorf("TruncateMiddle(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumns(t *testing.T) {
+	if got, want := Columns(StackBelow-1, "a\n", "b\n"), "a\nb\n"; got != want {
+		t.Errorf("narrow Columns() = %q, want %q", got, want)
+	}
+
+	wide := Columns(StackBelow, "a\n", "", "b\n")
+	if lines := strings.Split(strings.TrimSuffix(wide, "\n"), "\n"); len(lines) != 1 ||
+		!strings.HasPrefix(lines[0], "a ") || !strings.HasSuffix(lines[0], " b") {
+		t.Errorf("wide Columns() = %q, want a and b on one line", wide)
+	}
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// mergeIncomingLimit is how many of the commits a merge brings in are
+// listed; the rest are counted.
+const mergeIncomingLimit = 10
+
+// MergeCommit is a commit named in a merge summary.
+type MergeCommit struct {
+	Hash    string
+	Subject string
+}
+
+// MergeSummary is how 'bgit merge' merged Rev into Branch (HEAD when
+// detached): Kind is up-to-date, fast-forward or three-way. Commit is the
+// merge commit, or where the branch fast-forwarded to, and is empty when
+// the merge stopped: on Conflicts, or before committing with NoCommit.
+type MergeSummary struct {
+	Rev       string
+	Branch    string
+	Kind      string
+	Base      MergeCommit
+	Commit    string
+	NoCommit  bool
+	Incoming  []MergeCommit
+	Conflicts []string
+}
+
+// RenderMergeSummary explains s: what happened to the branch, the merge
+// base of a three-way merge, the commits the merge brings in and the files left conflicted.
+func RenderMergeSummary(s MergeSummary, width int) string {
+	var b strings.Builder
+	var head string
+	switch {
+	case s.Kind == "up-to-date":
+		return headerStyle.Render(s.Branch+" already has "+s.Rev) + mutedStyle.Render(" (up to date)") + "\n"
+	case len(s.Conflicts) > 0:
+		head = headerStyle.Render("Merging "+s.Rev+" into "+s.Branch) + " " + deletedStyle.Render("stopped on conflicts")
+	case s.Kind == "fast-forward":
+		head = headerStyle.Render("Fast-forwarded "+s.Branch+" to "+s.Rev) + " " + hashStyle.Render(shortHash(s.Commit))
+	case s.NoCommit:
+		head = headerStyle.Render("Merged "+s.Rev+" into "+s.Branch) + mutedStyle.Render(", not committed yet")
+	default:
+		head = headerStyle.Render("Merged "+s.Rev+" into "+s.Branch) + " " + hashStyle.Render(shortHash(s.Commit))
+	}
+	if s.Kind == "fast-forward" {
+		b.WriteString(head + "\n")
+	} else {
+		b.WriteString(head + mutedStyle.Render(" (three-way)") + "\n")
+		b.WriteString(mutedStyle.Render("  from the merge base ") + hashStyle.Render(shortHash(s.Base.Hash)) + " " + truncateEnd(s.Base.Subject, max(width-31, 10)) + "\n")
+	}
+
+	if len(s.Incoming) > 0 {
+		b.WriteString("\n" + headerStyle.Render("Incoming") + mutedStyle.Render(" ("+pluralize(len(s.Incoming), "commit", "commits")+")") + "\n")
+		for i, c := range s.Incoming {
+			if i == mergeIncomingLimit {
+				b.WriteString(mutedStyle.Render(fmt.Sprintf("  … and %d more", len(s.Incoming)-i)) + "\n")
+				break
+			}
+			b.WriteString("  " + stagedStyle.Render(Bullet()) + " " + hashStyle.Render(shortHash(c.Hash)) + " " + truncateEnd(c.Subject, max(width-12, 10)) + "\n")
+		}
+	}
+
+	if len(s.Conflicts) > 0 {
+		b.WriteString("\n" + RenderSection("Conflicts", s.Conflicts, deletedStyle, width))
+	}
+	return b.String()
+}
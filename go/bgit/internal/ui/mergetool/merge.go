@@ -0,0 +1,173 @@
+// Package mergetool is bgit's interactive conflict editor. A conflicted file
+// is split into the text both sides agree on and the hunks where they do
+// not; a full-screen Bubble Tea view shows each hunk as base, ours and
+// theirs side by side above the result, and lets the user pick a side,
+// combine them or edit the result by hand.
+package mergetool
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Choice is how a hunk has been resolved.
+type Choice int
+
+const (
+	Unresolved Choice = iota
+	Ours
+	Theirs
+	OursThenTheirs
+	TheirsThenOurs
+	Base
+	Edited
+)
+
+func (c Choice) String() string {
+	return [...]string{"unresolved", "ours", "theirs", "ours + theirs", "theirs + ours", "base", "edited"}[c]
+}
+
+// Hunk is one conflicting region. Every line keeps its own line ending so
+// the resolved file is byte-for-byte what the user picked.
+type Hunk struct {
+	Ours   []string
+	Base   []string
+	Theirs []string
+
+	Choice Choice
+	Edited []string // the result when Choice is Edited
+}
+
+// Lines is the resolved text of the hunk. An unresolved hunk keeps its
+// conflict markers, as git would leave it.
+func (h *Hunk) Lines() []string {
+	switch h.Choice {
+	case Ours:
+		return h.Ours
+	case Theirs:
+		return h.Theirs
+	case OursThenTheirs:
+		return concat(h.Ours, h.Theirs)
+	case TheirsThenOurs:
+		return concat(h.Theirs, h.Ours)
+	case Base:
+		return h.Base
+	case Edited:
+		return h.Edited
+	default:
+		return concat(
+			[]string{markerOurs + " ours\n"}, h.Ours,
+			[]string{markerBase + " base\n"}, h.Base,
+			[]string{markerSep + "\n"}, h.Theirs,
+			[]string{markerTheirs + " theirs\n"},
+		)
+	}
+}
+
+// Chunk is either text outside any conflict or a single hunk.
+type Chunk struct {
+	Common []string
+	Hunk   *Hunk
+}
+
+// File is a conflicted file split into chunks.
+type File struct {
+	Path   string
+	Chunks []Chunk
+	Hunks  []*Hunk // the hunks of Chunks, in order
+}
+
+const (
+	markerOurs   = "<<<<<<<"
+	markerBase   = "|||||||"
+	markerSep    = "======="
+	markerTheirs = ">>>>>>>"
+)
+
+// ErrMalformed is returned for merge output whose markers do not nest.
+var ErrMalformed = errors.New("malformed conflict markers")
+
+// Parse splits merged, the output of a diff3-style merge, into chunks.
+func Parse(path, merged string) (*File, error) {
+	f := &File{Path: path}
+	var (
+		common []string
+		hunk   *Hunk
+		side   *[]string
+	)
+
+	for n, line := range strings.SplitAfter(merged, "\n") {
+		if line == "" {
+			continue // SplitAfter leaves an empty string after a final newline
+		}
+		marker := func(m string) bool {
+			return strings.HasPrefix(line, m) && (len(line) == len(m) || line[len(m)] == ' ' || line[len(m)] == '\n')
+		}
+
+		switch {
+		case marker(markerOurs) && hunk == nil:
+			if len(common) > 0 {
+				f.Chunks = append(f.Chunks, Chunk{Common: common})
+				common = nil
+			}
+			hunk = &Hunk{}
+			side = &hunk.Ours
+		case marker(markerBase) && hunk != nil && side == &hunk.Ours:
+			side = &hunk.Base
+		case marker(markerSep) && hunk != nil && side != &hunk.Theirs:
+			side = &hunk.Theirs
+		case marker(markerTheirs) && hunk != nil && side == &hunk.Theirs:
+			f.Chunks = append(f.Chunks, Chunk{Hunk: hunk})
+			f.Hunks = append(f.Hunks, hunk)
+			hunk, side = nil, nil
+		case hunk != nil:
+			*side = append(*side, line)
+		case marker(markerOurs) || marker(markerBase) || marker(markerSep) || marker(markerTheirs):
+			return nil, fmt.Errorf("%s:%d: %w", path, n+1, ErrMalformed)
+		default:
+			common = append(common, line)
+		}
+	}
+	if hunk != nil {
+		return nil, fmt.Errorf("%s: unterminated conflict: %w", path, ErrMalformed)
+	}
+	if len(common) > 0 {
+		f.Chunks = append(f.Chunks, Chunk{Common: common})
+	}
+	return f, nil
+}
+
+// Unresolved counts the hunks still waiting for a choice.
+func (f *File) Unresolved() int {
+	n := 0
+	for _, h := range f.Hunks {
+		if h.Choice == Unresolved {
+			n++
+		}
+	}
+	return n
+}
+
+// Result is the file as resolved so far.
+func (f *File) Result() string {
+	var b strings.Builder
+	for _, c := range f.Chunks {
+		lines := c.Common
+		if c.Hunk != nil {
+			lines = c.Hunk.Lines()
+		}
+		for _, l := range lines {
+			b.WriteString(l)
+		}
+	}
+	return b.String()
+}
+
+func concat(parts ...[]string) []string {
+	var out []string
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package mergetool
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/endalk200/bgit/internal/ui/uitest"
+	"github.com/muesli/termenv"
+)
+
+func TestMain(m *testing.M) {
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	os.Exit(m.Run())
+}
+
+// merged is git merge-file --diff3 output with two conflicting hunks.
+const merged = `package main
+
+<<<<<<< ours
+const greeting = "hello"
+||||||| base
+const greeting = "hi"
+=======
+const greeting = "hey"
+>>>>>>> theirs
+
+func main() {
+<<<<<<< ours
+	fmt.Println(greeting)
+||||||| base
+	println(greeting)
+=======
+	log.Println(greeting)
+	log.Println("done")
+>>>>>>> theirs
+}
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse("main.go", merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Chunks) != 5 || len(f.Hunks) != 2 {
+		t.Fatalf("%d chunks and %d hunks, want 5 and 2", len(f.Chunks), len(f.Hunks))
+	}
+	if h := f.Hunks[1]; len(h.Ours) != 1 || len(h.Base) != 1 || len(h.Theirs) != 2 {
+		t.Errorf("second hunk has %d/%d/%d lines, want 1/1/2", len(h.Ours), len(h.Base), len(h.Theirs))
+	}
+	// Nothing resolved: the result keeps the markers exactly as merged.
+	if got := f.Result(); got != merged {
+		t.Errorf("unresolved Result() =\n%s\nwant\n%s", got, merged)
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	for name, text := range map[string]string{
+		"unterminated": "a\n<<<<<<< ours\nb\n=======\nc\n",
+		"stray end":    "a\n>>>>>>> theirs\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse("x", text); !errors.Is(err, ErrMalformed) {
+				t.Errorf("err = %v, want ErrMalformed", err)
+			}
+		})
+	}
+}
+
+func TestResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		first  Choice
+		second Choice
+		edited []string
+		want   string
+	}{
+		{"ours", Ours, Ours, nil, "package main\n\nconst greeting = \"hello\"\n\nfunc main() {\n\tfmt.Println(greeting)\n}\n"},
+		{"theirs then base", Theirs, Base, nil, "package main\n\nconst greeting = \"hey\"\n\nfunc main() {\n\tprintln(greeting)\n}\n"},
+		{"both", Base, OursThenTheirs, nil, "package main\n\nconst greeting = \"hi\"\n\nfunc main() {\n\tfmt.Println(greeting)\n\tlog.Println(greeting)\n\tlog.Println(\"done\")\n}\n"},
+		{"edited", Edited, Ours, []string{"const greeting = \"hallo\"\n"}, "package main\n\nconst greeting = \"hallo\"\n\nfunc main() {\n\tfmt.Println(greeting)\n}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse("main.go", merged)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.Hunks[0].Choice, f.Hunks[0].Edited = tt.first, tt.edited
+			f.Hunks[1].Choice = tt.second
+			if f.Unresolved() != 0 {
+				t.Errorf("%d hunks unresolved, want 0", f.Unresolved())
+			}
+			if got := f.Result(); got != tt.want {
+				t.Errorf("Result() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	got := splitLines("a\nb")
+	if len(got) != 2 || got[0] != "a\n" || got[1] != "b\n" {
+		t.Errorf("splitLines = %q, want every line ending in a newline", got)
+	}
+	if got := splitLines(""); got != nil {
+		t.Errorf("splitLines(\"\") = %q, want no lines", got)
+	}
+}
+
+func TestViewGolden(t *testing.T) {
+	for _, w := range []int{80, 120} {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			f, err := Parse("main.go", merged)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m := newModel(f, w, 20)
+			uitest.AssertGolden(t, fmt.Sprintf("unresolved_%d", w), m.View())
+
+			m.choose(Theirs) // resolves the first hunk and moves to the second
+			uitest.AssertGolden(t, fmt.Sprintf("second_hunk_%d", w), m.View())
+		})
+	}
+}
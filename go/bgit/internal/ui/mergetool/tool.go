@@ -0,0 +1,358 @@
+package mergetool
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/endalk200/bgit/internal/ui"
+)
+
+// Outcome is how the user left the merge tool.
+type Outcome int
+
+const (
+	// Written means every hunk is resolved and the result should be saved.
+	Written Outcome = iota
+	// Skipped leaves the file conflicted and moves on to the next one.
+	Skipped
+	// Quit stops resolving altogether.
+	Quit
+)
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	mutedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	pickedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	conflictStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	noteStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+)
+
+// contextLines is how much agreed text is shown around a hunk in the
+// result pane.
+const contextLines = 3
+
+// fallbackHeight is used until the terminal reports its size.
+const fallbackHeight = 24
+
+// model is the merge tool for one file. Choices are recorded on the hunks
+// of file directly.
+type model struct {
+	file    *File
+	current int
+	width   int
+	height  int
+	note    string // one-off feedback, cleared by the next key
+
+	editing bool
+	editor  textarea.Model
+
+	outcome Outcome
+}
+
+func newModel(f *File, width, height int) model {
+	editor := textarea.New()
+	editor.ShowLineNumbers = false
+	editor.MaxHeight = 0
+	editor.MaxWidth = 0
+	return model{file: f, width: width, height: height, editor: editor, outcome: Quit}
+}
+
+func (m model) Init() tea.Cmd { return nil }
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.resizeEditor()
+		return m, nil
+	case tea.KeyMsg:
+		if m.editing {
+			return m.updateEditor(msg)
+		}
+		return m.updateKey(msg)
+	}
+	if m.editing {
+		var cmd tea.Cmd
+		m.editor, cmd = m.editor.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// updateKey handles a key while browsing hunks.
+func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.note = ""
+	hunk := m.file.Hunks[m.current]
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		m.outcome = Quit
+		return m, tea.Quit
+	case "s":
+		m.outcome = Skipped
+		return m, tea.Quit
+	case "w":
+		if n := m.file.Unresolved(); n > 0 {
+			m.note = fmt.Sprintf("%d of %d hunks still unresolved", n, len(m.file.Hunks))
+			return m, nil
+		}
+		m.outcome = Written
+		return m, tea.Quit
+	case "right", "l", "n", "tab":
+		m.current = (m.current + 1) % len(m.file.Hunks)
+	case "left", "h", "p", "shift+tab":
+		m.current = (m.current + len(m.file.Hunks) - 1) % len(m.file.Hunks)
+	case "o":
+		m.choose(Ours)
+	case "t":
+		m.choose(Theirs)
+	case "b":
+		m.choose(OursThenTheirs)
+	case "B":
+		m.choose(TheirsThenOurs)
+	case "a":
+		m.choose(Base)
+	case "u":
+		hunk.Choice = Unresolved
+	case "e":
+		m.editing = true
+		start := hunk.Lines()
+		if hunk.Choice == Unresolved {
+			start = concat(hunk.Ours, hunk.Theirs)
+		}
+		m.editor.SetValue(strings.TrimSuffix(strings.Join(start, ""), "\n"))
+		m.resizeEditor()
+		return m, m.editor.Focus()
+	}
+	return m, nil
+}
+
+// updateEditor handles a key while editing the current hunk by hand.
+func (m model) updateEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.editing = false
+		m.editor.Blur()
+		return m, nil
+	case "ctrl+s":
+		hunk := m.file.Hunks[m.current]
+		hunk.Edited = splitLines(m.editor.Value())
+		hunk.Choice = Edited
+		m.editing = false
+		m.editor.Blur()
+		m.advance()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	return m, cmd
+}
+
+// choose resolves the current hunk and moves on to the next unresolved one.
+func (m *model) choose(c Choice) {
+	m.file.Hunks[m.current].Choice = c
+	m.advance()
+}
+
+// advance moves to the next unresolved hunk after the current one, if any.
+func (m *model) advance() {
+	for i := 1; i < len(m.file.Hunks); i++ {
+		next := (m.current + i) % len(m.file.Hunks)
+		if m.file.Hunks[next].Choice == Unresolved {
+			m.current = next
+			return
+		}
+	}
+}
+
+func (m *model) resizeEditor() {
+	_, resultRows := m.rows()
+	m.editor.SetWidth(max(m.width-2, 1))
+	m.editor.SetHeight(max(resultRows, 1))
+}
+
+// rows splits the screen height between the three panes and the result,
+// leaving room for the header, the help line and the borders. The panes
+// take no more than half, and only as much as the hunk needs.
+func (m model) rows() (panes, result int) {
+	height := m.height
+	if height <= 0 {
+		height = fallbackHeight
+	}
+	avail := max(height-2-4, 2)
+	hunk := m.file.Hunks[m.current]
+	tallest := max(len(hunk.Base), len(hunk.Ours), len(hunk.Theirs), 1)
+	panes = min(max(avail/2, 1), tallest)
+	return panes, max(avail-panes, 1)
+}
+
+func (m model) View() string {
+	width := m.width
+	if width <= 0 {
+		width = ui.DefaultWidth
+	}
+	hunk := m.file.Hunks[m.current]
+	paneRows, resultRows := m.rows()
+
+	var b strings.Builder
+	header := titleStyle.Render(m.file.Path) + mutedStyle.Render(fmt.Sprintf("  hunk %d/%d · %s · %d unresolved",
+		m.current+1, len(m.file.Hunks), hunk.Choice, m.file.Unresolved()))
+	b.WriteString(ansi.Truncate(header, width, "…") + "\n")
+
+	paneWidth := max((width-2*1)/3, 6)
+	panes := []string{
+		renderPane("Base", hunk.Base, hunk.Choice == Base, paneWidth, paneRows),
+		renderPane("Ours", hunk.Ours, hunk.Choice == Ours || hunk.Choice == OursThenTheirs || hunk.Choice == TheirsThenOurs, paneWidth, paneRows),
+		renderPane("Theirs", hunk.Theirs, hunk.Choice == Theirs || hunk.Choice == OursThenTheirs || hunk.Choice == TheirsThenOurs, paneWidth, paneRows),
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, panes[0], " ", panes[1], " ", panes[2]) + "\n")
+
+	if m.editing {
+		b.WriteString(box("Result (editing)", true, width).Render(m.editor.View()) + "\n")
+		b.WriteString(mutedStyle.Render(ansi.Truncate("ctrl+s keep edit · esc cancel", width, "…")))
+		return b.String()
+	}
+
+	b.WriteString(box("Result", hunk.Choice != Unresolved, width).Height(resultRows).Render(m.renderResult(width-2, resultRows)) + "\n")
+	if m.note != "" {
+		b.WriteString(noteStyle.Render(ansi.Truncate(m.note, width, "…")))
+	} else {
+		b.WriteString(mutedStyle.Render(ansi.Truncate("←/→ hunk · o ours · t theirs · b both · B both (theirs first) · a base · e edit · u undo · w write · s skip · q quit", width, "…")))
+	}
+	return b.String()
+}
+
+// renderResult shows the current hunk as it will be written, with the agreed
+// text on either side of it for context.
+func (m model) renderResult(width, rows int) string {
+	hunk := m.file.Hunks[m.current]
+	var before, after []string
+	for i, c := range m.file.Chunks {
+		if c.Hunk != hunk {
+			continue
+		}
+		if i > 0 {
+			before = lastLines(m.file.Chunks[i-1], contextLines)
+		}
+		if i+1 < len(m.file.Chunks) {
+			after = firstLines(m.file.Chunks[i+1], contextLines)
+		}
+		break
+	}
+
+	style := pickedStyle
+	if hunk.Choice == Unresolved {
+		style = conflictStyle
+	}
+	var lines []string
+	for _, l := range before {
+		lines = append(lines, mutedStyle.Render(displayLine(l, width)))
+	}
+	for _, l := range hunk.Lines() {
+		lines = append(lines, style.Render(displayLine(l, width)))
+	}
+	for _, l := range after {
+		lines = append(lines, mutedStyle.Render(displayLine(l, width)))
+	}
+	return strings.Join(clip(lines, rows), "\n")
+}
+
+// renderPane draws one side of the hunk in a titled box.
+func renderPane(title string, lines []string, picked bool, width, rows int) string {
+	inner := max(width-2, 1)
+	var shown []string
+	for _, l := range lines {
+		shown = append(shown, displayLine(l, inner))
+	}
+	if len(lines) == 0 {
+		shown = []string{mutedStyle.Render("(empty)")}
+	}
+	return box(title, picked, width).Height(rows).Render(strings.Join(clip(shown, rows), "\n"))
+}
+
+// box is a bordered pane of the given outer width, with its title on the
+// top border. A picked pane is drawn in the accent color.
+func box(title string, picked bool, width int) lipgloss.Style {
+	border := lipgloss.RoundedBorder()
+	if ui.Plain() {
+		border = lipgloss.ASCIIBorder()
+	}
+	color := lipgloss.Color("245")
+	if picked {
+		color = lipgloss.Color("42")
+	}
+	label := " " + title + " "
+	if picked {
+		label = " " + title + " " + ui.Icon("✓")
+	}
+	if fill := max(width-2-ui.StringWidth(label), 0); fill > 0 {
+		border.Top = label + strings.Repeat(border.Top, fill)
+	}
+	return lipgloss.NewStyle().Border(border).BorderForeground(color).Width(max(width-2, 1))
+}
+
+// clip keeps at most rows lines, replacing the overflow with a count.
+func clip(lines []string, rows int) []string {
+	if len(lines) <= rows {
+		return lines
+	}
+	kept := append([]string{}, lines[:max(rows-1, 0)]...)
+	return append(kept, mutedStyle.Render(fmt.Sprintf("… %d more lines", len(lines)-len(kept))))
+}
+
+// displayLine prepares a line of the file for a pane: no line ending, tabs
+// expanded, cut to width.
+func displayLine(l string, width int) string {
+	l = strings.TrimRight(l, "\r\n")
+	l = strings.ReplaceAll(l, "\t", "    ")
+	return ansi.Truncate(l, width, "…")
+}
+
+func lastLines(c Chunk, n int) []string {
+	if c.Hunk != nil {
+		return nil
+	}
+	return c.Common[max(len(c.Common)-n, 0):]
+}
+
+func firstLines(c Chunk, n int) []string {
+	if c.Hunk != nil {
+		return nil
+	}
+	return c.Common[:min(n, len(c.Common))]
+}
+
+// splitLines turns edited text back into lines that each end in a newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	} else {
+		lines[last] += "\n"
+	}
+	return lines
+}
+
+// Run shows the merge tool for f full screen on w until the user writes,
+// skips or quits. Choices are recorded on f's hunks; f.Result() is the file
+// to write when the outcome is Written.
+func Run(w io.Writer, in io.Reader, f *File) (Outcome, error) {
+	if len(f.Hunks) == 0 {
+		return Written, nil
+	}
+	p := tea.NewProgram(newModel(f, 0, 0),
+		tea.WithOutput(w), tea.WithInput(in), tea.WithAltScreen(), tea.WithoutSignalHandler())
+	final, err := p.Run()
+	if err != nil {
+		return Quit, err
+	}
+	return final.(model).outcome, nil
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"strings"
+)
+
+// welcomeText introduces bgit to someone running it for the first time.
+const welcomeText = "bgit is a Git wrapper with readable, colorized output. It stages and " +
+	"commits like git does, and when you commit without -m it asks an AI " +
+	"provider to describe the staged changes for you. A few questions set it up; " +
+	"everything can be changed later with 'bgit config'."
+
+// CheatEntry is one line of the cheat-sheet: a command and what it does.
+type CheatEntry struct {
+	Command string
+	Summary string
+}
+
+// RenderWelcome renders the greeting shown before first-run setup.
+func RenderWelcome(width int) string {
+	return headerStyle.Render(Icon("👋")+"Welcome to bgit") + "\n\n" +
+		HangingIndent("", welcomeText, width) + "\n\n"
+}
+
+// RenderCheatSheet lists commands beside what they do. Summaries line up in
+// a column when there is room, and otherwise go under their command.
+func RenderCheatSheet(entries []CheatEntry, width int) string {
+	col := 0
+	for _, e := range entries {
+		col = max(col, StringWidth(e.Command))
+	}
+	stacked := col+4 >= width/2
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render("Cheat sheet") + "\n")
+	for _, e := range entries {
+		command := hashStyle.Render(e.Command)
+		if stacked {
+			b.WriteString("  " + command + "\n")
+			b.WriteString(HangingIndent("    ", e.Summary, width) + "\n")
+			continue
+		}
+		pad := strings.Repeat(" ", col-StringWidth(e.Command)+2)
+		b.WriteString(HangingIndent("  "+command+pad, e.Summary, width) + "\n")
+	}
+	return b.String()
+}
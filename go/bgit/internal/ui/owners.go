@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OwnerArea is a group of staged files that the same CODEOWNERS rule gives
+// owners to. The files no rule owns make up an area without owners.
+type OwnerArea struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+	Files   []string `json:"files"`
+}
+
+// RenderOwners lists the owners whose review the staged changes need, area
+// by area, with the pattern that makes them owners and the files it covers.
+func RenderOwners(areas []OwnerArea, width int) string {
+	if len(areas) == 0 {
+		return ""
+	}
+	count := fmt.Sprintf(" (%d areas)", len(areas))
+	if len(areas) == 1 {
+		count = " (1 area)"
+	}
+	var b strings.Builder
+	b.WriteString(headerStyle.Render("Code owners") + mutedStyle.Render(count) + "\n")
+	for _, a := range areas {
+		files := fmt.Sprintf("%d files", len(a.Files))
+		if len(a.Files) == 1 {
+			files = TruncateMiddle(a.Files[0], width-4)
+		}
+		if len(a.Owners) == 0 {
+			b.WriteString(HangingIndent("  "+Bullet()+" ", mutedStyle.Render("no owner · "+files), width) + "\n")
+			continue
+		}
+		b.WriteString(HangingIndent("  "+Bullet()+" ", strings.Join(a.Owners, " ")+mutedStyle.Render(" · "+a.Pattern+" · "+files), width) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,232 @@
+// Package pager is a full-screen reader for long output made of sections,
+// such as a series of commits with their patches. Sections can be stepped
+// through one at a time, and a section can be opened to show more about it
+// (the whole commit, say) before returning to where the reader was.
+package pager
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/endalk200/bgit/internal/ui"
+)
+
+var (
+	barStyle    = lipgloss.NewStyle().Reverse(true)
+	cursorStyle = lipgloss.NewStyle().Reverse(true).Bold(true)
+	mutedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	noteStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+)
+
+// Fallback size, used until the terminal reports its own.
+const (
+	fallbackWidth  = 80
+	fallbackHeight = 24
+)
+
+// Options configure a pager.
+type Options struct {
+	// Title is shown in the status bar.
+	Title string
+	// Noun names a section in the status bar ("commit 3/12").
+	Noun string
+	// Open returns the text shown when the reader presses enter on section
+	// i. Nil disables opening.
+	Open func(i int) (string, error)
+	// Compact leaves out the blank line between sections, for sections of
+	// a line or two such as the commits of a graph.
+	Compact bool
+}
+
+// page is one level of the pager: the sections first, then whatever was
+// opened from them.
+type page struct {
+	title    string
+	sections []string
+	compact  bool
+	starts   []int // first line of each section
+	cursor   int   // the section n, N and enter act on
+	view     viewport.Model
+}
+
+type model struct {
+	opts   Options
+	pages  []page
+	width  int
+	height int
+	note   string
+}
+
+func newModel(sections []string, opts Options, width, height int) model {
+	if width == 0 || height == 0 {
+		width, height = fallbackWidth, fallbackHeight
+	}
+	m := model{opts: opts, width: width, height: height}
+	m.push(opts.Title, sections, opts.Compact)
+	return m
+}
+
+// push opens a new page on top of the current one.
+func (m *model) push(title string, sections []string, compact bool) {
+	p := page{title: title, sections: sections, compact: compact, view: viewport.New(m.width, m.height-1)}
+	line := 0
+	for i, s := range sections {
+		if i > 0 && !compact {
+			line++ // blank line between sections
+		}
+		p.starts = append(p.starts, line)
+		line += strings.Count(strings.TrimRight(s, "\n"), "\n") + 1
+	}
+	p.render()
+	m.pages = append(m.pages, p)
+}
+
+func (m *model) top() *page { return &m.pages[len(m.pages)-1] }
+
+// render lays the sections out one after the other, with the first line of
+// the current one highlighted when there is more than one to choose from.
+func (p *page) render() {
+	var b strings.Builder
+	for i, s := range p.sections {
+		if i > 0 && !p.compact {
+			b.WriteString("\n")
+		}
+		s = strings.TrimRight(s, "\n")
+		if i == p.cursor && len(p.sections) > 1 {
+			first, rest, _ := strings.Cut(s, "\n")
+			s = cursorStyle.Render(ansi.Strip(first)) + "\n" + rest
+		}
+		b.WriteString(s + "\n")
+	}
+	p.view.SetContent(strings.TrimRight(b.String(), "\n"))
+}
+
+// moveTo makes section i current and scrolls it to the top of the screen,
+// as far as the content allows.
+func (p *page) moveTo(i int) {
+	if i < 0 || i >= len(p.sections) {
+		return
+	}
+	p.cursor = i
+	p.render()
+	p.view.SetYOffset(p.starts[i])
+}
+
+// follow makes the section at the top of the screen current after a scroll.
+func (p *page) follow() {
+	cur := 0
+	for i, s := range p.starts {
+		if s <= p.view.YOffset {
+			cur = i
+		}
+	}
+	if cur != p.cursor && !(p.view.AtBottom() && cur < p.cursor) {
+		p.cursor = cur
+		p.render()
+	}
+}
+
+func (m model) Init() tea.Cmd { return nil }
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		for i := range m.pages {
+			m.pages[i].view.Width = msg.Width
+			m.pages[i].view.Height = msg.Height - 1
+		}
+		return m, nil
+	case tea.KeyMsg:
+		return m.updateKey(msg)
+	}
+	return m, nil
+}
+
+func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.note = ""
+	p := m.top()
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace", "left", "h":
+		if len(m.pages) == 1 {
+			if msg.String() == "esc" {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		m.pages = m.pages[:len(m.pages)-1]
+	case "n", "tab":
+		p.moveTo(p.cursor + 1)
+	case "N", "p", "shift+tab":
+		p.moveTo(p.cursor - 1)
+	case "g", "home":
+		p.moveTo(0)
+	case "G", "end":
+		p.moveTo(len(p.sections) - 1)
+		p.view.GotoBottom()
+	case "enter", "right", "l":
+		if m.opts.Open == nil || len(m.pages) > 1 || len(p.sections) == 0 {
+			return m, nil
+		}
+		content, err := m.opts.Open(p.cursor)
+		if err != nil {
+			m.note = err.Error()
+			return m, nil
+		}
+		m.push(fmt.Sprintf("%s %d", m.opts.Noun, p.cursor+1), []string{content}, false)
+	default:
+		var cmd tea.Cmd
+		p.view, cmd = p.view.Update(msg)
+		p.follow()
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m model) View() string {
+	p := m.pages[len(m.pages)-1]
+	return p.view.View() + "\n" + m.statusBar()
+}
+
+// statusBar shows where the reader is and the keys that apply.
+func (m model) statusBar() string {
+	p := m.pages[len(m.pages)-1]
+	left := " " + p.title
+	if len(m.pages) == 1 && m.opts.Noun != "" {
+		left += fmt.Sprintf("  %s %d/%d", m.opts.Noun, p.cursor+1, len(p.sections))
+	}
+	left += fmt.Sprintf("  %d%%", int(p.view.ScrollPercent()*100))
+
+	keys := "n/N next/prev  enter open  q quit"
+	switch {
+	case len(m.pages) > 1:
+		keys = "esc back  q quit"
+	case m.opts.Open == nil:
+		keys = "n/N next/prev  q quit"
+	}
+	if m.note != "" {
+		keys = noteStyle.Render(m.note)
+	}
+
+	gap := m.width - ui.StringWidth(left) - ui.StringWidth(keys) - 1
+	if gap < 1 {
+		return barStyle.Render(ui.TruncateMiddle(left, max(m.width, 1)))
+	}
+	return barStyle.Render(left+strings.Repeat(" ", gap)) + " " + mutedStyle.Render(keys)
+}
+
+// Run shows sections full screen on w until the reader quits.
+func Run(w io.Writer, in io.Reader, sections []string, opts Options) error {
+	p := tea.NewProgram(newModel(sections, opts, 0, 0),
+		tea.WithOutput(w), tea.WithInput(in), tea.WithAltScreen(), tea.WithoutSignalHandler())
+	_, err := p.Run()
+	return err
+}
@@ -0,0 +1,75 @@
+package pager
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/endalk200/bgit/internal/ui/uitest"
+)
+
+// sections are three commits of different lengths; the second is long
+// enough that the screen scrolls.
+func sections() []string {
+	var out []string
+	for i, n := range []int{3, 12, 2} {
+		var b strings.Builder
+		fmt.Fprintf(&b, "commit %d\n", i+1)
+		for l := 1; l <= n; l++ {
+			fmt.Fprintf(&b, "    line %d of commit %d\n", l, i+1)
+		}
+		out = append(out, b.String())
+	}
+	return out
+}
+
+func TestSteppingAndOpening(t *testing.T) {
+	opened := -1
+	var m tea.Model = newModel(sections(), Options{
+		Title: "log main.go",
+		Noun:  "commit",
+		Open: func(i int) (string, error) {
+			opened = i
+			return fmt.Sprintf("all of commit %d\n", i+1), nil
+		},
+	}, 60, 10)
+	uitest.AssertGolden(t, "first", m.View())
+
+	m = uitest.Press(m, "n")
+	uitest.AssertGolden(t, "second", m.View())
+
+	m = uitest.Press(m, "enter")
+	if opened != 1 {
+		t.Fatalf("enter opened section %d, want 1", opened)
+	}
+	uitest.AssertGolden(t, "opened", m.View())
+
+	m = uitest.Press(m, "esc")
+	if pages := m.(model).pages; len(pages) != 1 || pages[0].cursor != 1 {
+		t.Errorf("esc left %d pages with cursor %d, want the list back at section 1", len(pages), pages[0].cursor)
+	}
+}
+
+func TestOpenError(t *testing.T) {
+	var m tea.Model = newModel(sections(), Options{
+		Noun: "commit",
+		Open: func(int) (string, error) { return "", errors.New("object not found") },
+	}, 60, 10)
+	m = uitest.Press(m, "enter")
+	if pages := len(m.(model).pages); pages != 1 {
+		t.Errorf("%d pages after a failed open, want 1", pages)
+	}
+	if !strings.Contains(uitest.StripANSI(m.View()), "object not found") {
+		t.Errorf("the error is not shown in the status bar")
+	}
+}
+
+func TestCompactSections(t *testing.T) {
+	lines := []string{"* c3 third\n", "* c2 second\n|\\\n", "* c1 first\n"}
+	var m tea.Model = newModel(lines, Options{Title: "log", Noun: "commit", Compact: true}, 40, 6)
+	m = uitest.Press(m, "n")
+	m = uitest.Press(m, "n")
+	uitest.AssertGolden(t, "compact", m.View())
+}
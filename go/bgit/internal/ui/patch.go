@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"path"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RenderPatch colors a unified diff the way git does: file headers in bold,
+// hunk headers blue, added lines green and removed lines red. The code on
+// each line is highlighted for the language of its file, when it is one
+// the highlighter knows.
+func RenderPatch(patch string) string {
+	var b strings.Builder
+	inHeader := false
+	var lexer chroma.Lexer
+	for _, line := range strings.SplitAfter(patch, "\n") {
+		text := strings.TrimSuffix(line, "\n")
+		nl := line[len(text):]
+		switch {
+		case text == "":
+		case strings.HasPrefix(text, "diff --git "):
+			inHeader, lexer = true, nil
+			text = headerStyle.Render(text)
+		case strings.HasPrefix(text, "@@"):
+			inHeader = false
+			text = hunkStyle.Render(text)
+		case inHeader:
+			// The new name wins over the old, unless the file was deleted.
+			if name, ok := strings.CutPrefix(text, "--- a/"); ok {
+				lexer = lexerFor(name)
+			} else if name, ok := strings.CutPrefix(text, "+++ b/"); ok {
+				lexer = lexerFor(name)
+			}
+			text = headerStyle.Render(text)
+		case strings.HasPrefix(text, "+"):
+			text = insertStyle.Render("+") + highlight(lexer, text[1:], insertStyle)
+		case strings.HasPrefix(text, "-"):
+			text = deleteStyle.Render("-") + highlight(lexer, text[1:], deleteStyle)
+		case strings.HasPrefix(text, " "):
+			text = " " + highlight(lexer, text[1:], lipgloss.NewStyle())
+		}
+		b.WriteString(text + nl)
+	}
+	return b.String()
+}
+
+// SplitPatch cuts a unified diff into the patches of each file.
+func SplitPatch(patch string) []string {
+	var files []string
+	for _, line := range strings.SplitAfter(patch, "\n") {
+		if strings.HasPrefix(line, "diff --git ") || len(files) == 0 {
+			files = append(files, "")
+		}
+		files[len(files)-1] += line
+	}
+	if len(files) == 1 && files[0] == "" {
+		return nil
+	}
+	return files
+}
+
+// lexerFor finds the highlighter for a file by its name, or nil.
+func lexerFor(name string) chroma.Lexer {
+	lexer := lexers.Match(path.Base(name))
+	if lexer == nil {
+		return nil
+	}
+	return chroma.Coalesce(lexer)
+}
+
+// highlight colors the tokens of one line of code, leaving text the lexer
+// has no color for in base. Each line is highlighted on its own, so a
+// construct spanning lines, like a block comment, is only colored where
+// the line shows what it is. Tabs are kept, so the patch still applies.
+func highlight(lexer chroma.Lexer, code string, base lipgloss.Style) string {
+	base = base.TabWidth(lipgloss.NoTabConversion)
+	if lexer == nil || code == "" {
+		return base.Render(code)
+	}
+	tokens, err := lexer.Tokenise(nil, code)
+	if err != nil {
+		return base.Render(code)
+	}
+	var b strings.Builder
+	for _, tok := range tokens.Tokens() {
+		value := strings.ReplaceAll(tok.Value, "\n", "") // lexers may end the line
+		if value != "" {
+			b.WriteString(tokenStyle(tok.Type, base).TabWidth(lipgloss.NoTabConversion).Render(value))
+		}
+	}
+	return b.String()
+}
+
+// tokenStyle is the color of a kind of token.
+func tokenStyle(t chroma.TokenType, base lipgloss.Style) lipgloss.Style {
+	switch {
+	case t.InCategory(chroma.Keyword):
+		return keywordStyle
+	case t.InCategory(chroma.Comment):
+		return commentStyle
+	case t.InSubCategory(chroma.LiteralString):
+		return stringStyle
+	case t.InSubCategory(chroma.LiteralNumber):
+		return numberStyle
+	case t == chroma.NameFunction, t == chroma.NameBuiltin:
+		return functionStyle
+	}
+	return base
+}
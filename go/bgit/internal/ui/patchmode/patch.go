@@ -0,0 +1,314 @@
+// Package patchmode is bgit's interactive hunk stager, the counterpart of
+// git add -p. The unstaged diff of each file is split into its hunks; a
+// full-screen Bubble Tea view walks through them, letting the user stage,
+// skip or split each one, and the hunks chosen are turned back into a
+// patch to apply to the index.
+package patchmode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/endalk200/bgit/internal/ui"
+)
+
+// Choice is what has been decided for a hunk.
+type Choice int
+
+const (
+	Undecided Choice = iota
+	Stage
+	Skip
+)
+
+func (c Choice) String() string {
+	return [...]string{"undecided", "stage", "skip"}[c]
+}
+
+// Hunk is one change to decide on: a hunk of the diff, or a part of one the
+// user split. Lines keep their ' ', '-', '+' or '\' prefix and their line
+// ending.
+type Hunk struct {
+	OldStart, NewStart int
+	Lines              []string
+	Choice             Choice
+
+	// The hunk as diffed that this one is, or is a part of, and where in
+	// its lines this one lies.
+	section  *section
+	from, to int
+}
+
+// section is a hunk as the diff has it. Each of its changed lines belongs
+// to the Hunk it is decided with; splitting a hunk hands them on to its
+// parts.
+type section struct {
+	oldStart, newStart int
+	heading            string
+	lines              []string
+	owners             []*Hunk // per line, nil for context
+}
+
+// Counts returns how many lines the hunk spans before and after it
+// applies.
+func (h *Hunk) Counts() (old, new int) {
+	return counts(h.Lines)
+}
+
+// Header is the hunk's @@ line, without the line ending.
+func (h *Hunk) Header() string {
+	old, new := h.Counts()
+	header := fmt.Sprintf("@@ -%s +%s @@", hunkRange(h.OldStart, old), hunkRange(h.NewStart, new))
+	if h.from == 0 && h.section.heading != "" {
+		header += " " + h.section.heading
+	}
+	return header
+}
+
+// File is the unstaged diff of one file.
+type File struct {
+	Path string
+	// Header is the diff --git line and those after it up to the first hunk.
+	Header []string
+	// Hunks are the changes to decide on, in order. Files with none, binary
+	// files and changes of mode alone, cannot be staged hunk by hunk.
+	Hunks    []*Hunk
+	sections []*section
+}
+
+// ErrMalformed is returned for a diff that cannot be read back.
+var ErrMalformed = errors.New("malformed diff")
+
+// Parse reads a unified diff of any number of files, as git diff prints it.
+func Parse(patch string) ([]*File, error) {
+	var files []*File
+	for _, text := range ui.SplitPatch(patch) {
+		f, err := parseFile(text)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+func parseFile(text string) (*File, error) {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "diff --git ") {
+		return nil, fmt.Errorf("%w: expected a diff --git line", ErrMalformed)
+	}
+	f := &File{Path: pathOf(lines[0])}
+	var sec *section
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "@@ "):
+			s, err := parseRange(line)
+			if err != nil {
+				return nil, err
+			}
+			sec = s
+			f.sections = append(f.sections, sec)
+		case sec == nil:
+			f.Header = append(f.Header, line)
+		case line != "" && strings.ContainsRune(" -+\\", rune(line[0])):
+			sec.lines = append(sec.lines, line)
+		default:
+			return nil, fmt.Errorf("%w: %s: unexpected line %q", ErrMalformed, f.Path, strings.TrimSuffix(line, "\n"))
+		}
+	}
+	for _, sec := range f.sections {
+		h := &Hunk{OldStart: sec.oldStart, NewStart: sec.newStart, Lines: sec.lines, section: sec, to: len(sec.lines)}
+		sec.owners = make([]*Hunk, len(sec.lines))
+		for i, l := range sec.lines {
+			if l[0] == '-' || l[0] == '+' {
+				sec.owners[i] = h
+			}
+		}
+		f.Hunks = append(f.Hunks, h)
+	}
+	return f, nil
+}
+
+// pathOf takes the file's name from its diff --git line, the new name of a
+// renamed file.
+func pathOf(line string) string {
+	line = strings.TrimSuffix(line, "\n")
+	if i := strings.LastIndex(line, " b/"); i >= 0 {
+		return line[i+3:]
+	}
+	return strings.TrimPrefix(line, "diff --git ")
+}
+
+// parseRange reads a hunk's @@ -a,b +c,d @@ heading line.
+func parseRange(line string) (*section, error) {
+	line = strings.TrimSuffix(line, "\n")
+	fields := strings.SplitN(line, " ", 5)
+	if len(fields) < 4 || fields[3] != "@@" || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return nil, fmt.Errorf("%w: bad hunk header %q", ErrMalformed, line)
+	}
+	oldStart, err1 := rangeStart(fields[1][1:])
+	newStart, err2 := rangeStart(fields[2][1:])
+	if err1 != nil || err2 != nil {
+		return nil, fmt.Errorf("%w: bad hunk header %q", ErrMalformed, line)
+	}
+	sec := &section{oldStart: oldStart, newStart: newStart}
+	if len(fields) == 5 {
+		sec.heading = fields[4]
+	}
+	return sec, nil
+}
+
+func rangeStart(r string) (int, error) {
+	start, _, _ := strings.Cut(r, ",")
+	return strconv.Atoi(start)
+}
+
+// hunkRange writes one side of an @@ line, leaving out a count of one as
+// git does.
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// counts tallies the lines of a hunk on each side of it.
+func counts(lines []string) (old, new int) {
+	for _, l := range lines {
+		switch l[0] {
+		case ' ':
+			old++
+			new++
+		case '-':
+			old++
+		case '+':
+			new++
+		}
+	}
+	return old, new
+}
+
+// Split cuts the hunk at i into one hunk per run of changed lines, each
+// with the unchanged lines around it, as git add -p's split does. It
+// reports false when there is only one run, so nothing to split. The parts
+// start undecided.
+func (f *File) Split(i int) bool {
+	h := f.Hunks[i]
+	sec := h.section
+	type run struct{ start, end int }
+	var runs []run
+	for j := h.from; j < h.to; {
+		if !changed(sec.lines[j]) {
+			j++
+			continue
+		}
+		r := run{start: j}
+		for j < h.to && (changed(sec.lines[j]) || sec.lines[j][0] == '\\') {
+			j++
+		}
+		r.end = j
+		runs = append(runs, r)
+	}
+	if len(runs) < 2 {
+		return false
+	}
+
+	// The unchanged lines between two runs go with both.
+	parts := make([]*Hunk, len(runs))
+	for k, r := range runs {
+		from, to := h.from, h.to
+		if k > 0 {
+			from = runs[k-1].end
+		}
+		if k+1 < len(runs) {
+			to = runs[k+1].start
+		}
+		old, new := counts(sec.lines[h.from:from])
+		part := &Hunk{OldStart: h.OldStart + old, NewStart: h.NewStart + new, Lines: sec.lines[from:to], section: sec, from: from, to: to}
+		for j := r.start; j < r.end; j++ {
+			if sec.owners[j] == h {
+				sec.owners[j] = part
+			}
+		}
+		parts[k] = part
+	}
+	f.Hunks = append(f.Hunks[:i], append(parts, f.Hunks[i+1:]...)...)
+	return true
+}
+
+// changed reports whether a hunk line removes or adds a line.
+func changed(line string) bool {
+	return line[0] == '-' || line[0] == '+'
+}
+
+// Undecided counts the hunks still waiting for a decision.
+func (f *File) Undecided() int {
+	n := 0
+	for _, h := range f.Hunks {
+		if h.Choice == Undecided {
+			n++
+		}
+	}
+	return n
+}
+
+// Patch is the patch of the hunks chosen to stage, to apply to the index,
+// or "" when there are none. Each hunk as diffed is rewritten without the
+// changes not chosen: a line not removed stays as context and a line not
+// added is left out, so parts split from one hunk apply together.
+func (f *File) Patch() string {
+	var b strings.Builder
+	shift := 0 // lines added less lines removed by the hunks written so far
+	for _, sec := range f.sections {
+		var lines []string
+		staged, dropped := false, false
+		for i, l := range sec.lines {
+			owner := sec.owners[i]
+			keep := owner == nil || owner.Choice == Stage
+			switch {
+			case l[0] == '\\':
+				if dropped {
+					continue // it told of the line left out
+				}
+			case keep:
+				staged = staged || owner != nil
+			case l[0] == '-':
+				l = " " + l[1:]
+			case l[0] == '+':
+				dropped = true
+				continue
+			}
+			dropped = false
+			lines = append(lines, l)
+		}
+		if !staged {
+			continue
+		}
+		old, new := counts(lines)
+		newStart := sec.oldStart + shift
+		if new == 0 {
+			newStart = sec.oldStart - 1 + shift // the line the hunk leaves off after
+		}
+		if old == 0 {
+			newStart = sec.oldStart + 1 + shift
+		}
+		header := fmt.Sprintf("@@ -%s +%s @@", hunkRange(sec.oldStart, old), hunkRange(max(newStart, 0), new))
+		if sec.heading != "" {
+			header += " " + sec.heading
+		}
+		b.WriteString(header + "\n")
+		for _, l := range lines {
+			b.WriteString(l)
+		}
+		shift += new - old
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return strings.Join(f.Header, "") + b.String()
+}
@@ -0,0 +1,250 @@
+package patchmode
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/endalk200/bgit/internal/ui/uitest"
+	"github.com/muesli/termenv"
+)
+
+func TestMain(m *testing.M) {
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	os.Exit(m.Run())
+}
+
+const mainHeader = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+`
+
+const notesHeader = `diff --git a/notes.txt b/notes.txt
+index 3333333..4444444 100644
+--- a/notes.txt
++++ b/notes.txt
+`
+
+// diff is git diff output for three files: two changes a few lines apart in
+// one hunk, a file that ends without a newline, and a binary file.
+const diff = mainHeader + `@@ -1,7 +1,7 @@ package main
+ package main
+ import "fmt"
+-const greeting = "hi"
++const greeting = "hello"
+ func main() {
+ 	x := 1
+-	println(greeting)
++	fmt.Println(greeting)
+ }
+` + notesHeader + `@@ -1,3 +1,3 @@
+-one
++uno
+ two
+-three
+\ No newline at end of file
++tres
+\ No newline at end of file
+diff --git a/logo.png b/logo.png
+index 5555555..6666666 100644
+Binary files a/logo.png and b/logo.png differ
+`
+
+func parse(t *testing.T) []*File {
+	t.Helper()
+	files, err := Parse(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("%d files, want 3", len(files))
+	}
+	return files
+}
+
+func TestParse(t *testing.T) {
+	files := parse(t)
+	for i, want := range []struct {
+		path  string
+		hunks int
+	}{{"main.go", 1}, {"notes.txt", 1}, {"logo.png", 0}} {
+		if f := files[i]; f.Path != want.path || len(f.Hunks) != want.hunks {
+			t.Errorf("file %d is %s with %d hunks, want %s with %d", i, f.Path, len(f.Hunks), want.path, want.hunks)
+		}
+	}
+	h := files[0].Hunks[0]
+	if got, want := h.Header(), "@@ -1,7 +1,7 @@ package main"; got != want {
+		t.Errorf("Header() = %q, want %q", got, want)
+	}
+	if got := strings.Join(files[0].Header, ""); got != mainHeader {
+		t.Errorf("file header = %q, want %q", got, mainHeader)
+	}
+	if files[0].Undecided() != 1 {
+		t.Errorf("%d hunks undecided, want 1", files[0].Undecided())
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	for name, text := range map[string]string{
+		"bad range":  mainHeader + "@@ -x +1 @@\n+a\n",
+		"stray line": mainHeader + "@@ -1 +1 @@\n-a\n+b\n?\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse(text); !errors.Is(err, ErrMalformed) {
+				t.Errorf("err = %v, want ErrMalformed", err)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	files := parse(t)
+	f := files[0]
+	if !f.Split(0) {
+		t.Fatal("Split(0) = false, want the hunk split in two")
+	}
+	if len(f.Hunks) != 2 {
+		t.Fatalf("%d hunks, want 2", len(f.Hunks))
+	}
+	// The unchanged lines between the changes go with both parts.
+	for i, want := range []string{"@@ -1,5 +1,5 @@ package main", "@@ -4,4 +4,4 @@"} {
+		if got := f.Hunks[i].Header(); got != want {
+			t.Errorf("hunk %d Header() = %q, want %q", i, got, want)
+		}
+	}
+	if f.Split(1) {
+		t.Error("Split(1) = true, want a single change left unsplit")
+	}
+
+	// The line telling of a missing newline stays with its change.
+	notes := files[1]
+	if !notes.Split(0) {
+		t.Fatal("Split(0) of notes.txt = false, want the hunk split in two")
+	}
+	if got := notes.Hunks[1].Lines; len(got) != 5 || got[4] != "\\ No newline at end of file\n" {
+		t.Errorf("second part of notes.txt = %q, want it to end with the no-newline line", got)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		split   bool
+		choices []Choice
+		file    int
+		want    string
+	}{
+		{"all", false, []Choice{Stage}, 0, diff[:strings.Index(diff, notesHeader)]},
+		{"none", false, []Choice{Skip}, 0, ""},
+		{"undecided", false, []Choice{Undecided}, 0, ""},
+		{"first part", true, []Choice{Stage, Skip}, 0, mainHeader + `@@ -1,7 +1,7 @@ package main
+ package main
+ import "fmt"
+-const greeting = "hi"
++const greeting = "hello"
+ func main() {
+ 	x := 1
+ 	println(greeting)
+ }
+`},
+		{"second part", true, []Choice{Skip, Stage}, 0, mainHeader + `@@ -1,7 +1,7 @@ package main
+ package main
+ import "fmt"
+ const greeting = "hi"
+ func main() {
+ 	x := 1
+-	println(greeting)
++	fmt.Println(greeting)
+ }
+`},
+		{"last line kept", true, []Choice{Stage, Skip}, 1, notesHeader + `@@ -1,3 +1,3 @@
+-one
++uno
+ two
+ three
+\ No newline at end of file
+`},
+		{"last line changed", true, []Choice{Skip, Stage}, 1, notesHeader + `@@ -1,3 +1,3 @@
+ one
+ two
+-three
+\ No newline at end of file
++tres
+\ No newline at end of file
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := parse(t)[tt.file]
+			if tt.split {
+				f.Split(0)
+			}
+			for i, c := range tt.choices {
+				f.Hunks[i].Choice = c
+			}
+			if got := f.Patch(); got != tt.want {
+				t.Errorf("Patch() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPatchAddedFile checks the new side of a hunk that adds a file, whose
+// old side starts at line 0.
+func TestPatchAddedFile(t *testing.T) {
+	added := "diff --git a/new.txt b/new.txt\nnew file mode 100644\nindex 0000000..7898192\n--- /dev/null\n+++ b/new.txt\n@@ -0,0 +1,2 @@\n+a\n+b\n"
+	files, err := Parse(added)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files[0].Hunks[0].Choice = Stage
+	if got := files[0].Patch(); got != added {
+		t.Errorf("Patch() =\n%s\nwant\n%s", got, added)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	files := parse(t)
+	var m tea.Model = newModel(files[:2], 80, 20)
+	m, _ = m.Update(uitest.Key("s")) // main.go splits in two
+	m, _ = m.Update(uitest.Key("y"))
+	m, _ = m.Update(uitest.Key("n"))
+	if got := m.(model); got.file != 1 || got.hunk != 0 {
+		t.Fatalf("at file %d hunk %d, want the hunk of notes.txt", got.file, got.hunk)
+	}
+	m, cmd := m.Update(uitest.Key("a"))
+	if cmd == nil || m.(model).outcome != Done {
+		t.Fatal("deciding the last hunk did not finish with Done")
+	}
+	for i, want := range []Choice{Stage, Skip} {
+		if got := files[0].Hunks[i].Choice; got != want {
+			t.Errorf("main.go hunk %d is %v, want %v", i, got, want)
+		}
+	}
+	if got := files[1].Hunks[0].Choice; got != Stage {
+		t.Errorf("notes.txt hunk is %v, want stage", got)
+	}
+
+	m, _ = newModel(parse(t)[:1], 80, 20).Update(uitest.Key("ctrl+c"))
+	if m.(model).outcome != Aborted {
+		t.Error("ctrl+c did not abort")
+	}
+}
+
+func TestViewGolden(t *testing.T) {
+	for _, w := range []int{80, 120} {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			var m tea.Model = newModel(parse(t)[:2], w, 20)
+			uitest.AssertGolden(t, fmt.Sprintf("hunk_%d", w), m.View())
+
+			m, _ = m.Update(uitest.Key("s"))
+			m, _ = m.Update(uitest.Key("y"))
+			uitest.AssertGolden(t, fmt.Sprintf("split_%d", w), m.View())
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package patchmode
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/endalk200/bgit/internal/ui"
+)
+
+// Outcome is how the user left the hunk stager.
+type Outcome int
+
+const (
+	// Done means the hunks chosen so far should be staged: every hunk was
+	// decided, or the user quit early.
+	Done Outcome = iota
+	// Aborted stages nothing.
+	Aborted
+)
+
+var (
+	titleStyle = lipgloss.NewStyle().Bold(true)
+	mutedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	stageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	skipStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	noteStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+)
+
+// fallbackHeight is used until the terminal reports its size.
+const fallbackHeight = 24
+
+// model is the hunk stager. Decisions are recorded on the hunks of files
+// directly.
+type model struct {
+	files  []*File
+	file   int // the file and hunk shown
+	hunk   int
+	scroll int // first line of the hunk shown, when it is taller than the screen
+	width  int
+	height int
+	note   string // one-off feedback, cleared by the next key
+
+	outcome Outcome
+}
+
+func newModel(files []*File, width, height int) model {
+	return model{files: files, width: width, height: height, outcome: Aborted}
+}
+
+func (m model) Init() tea.Cmd { return nil }
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.scroll = min(m.scroll, m.maxScroll())
+	case tea.KeyMsg:
+		return m.updateKey(msg)
+	}
+	return m, nil
+}
+
+// updateKey handles a key press.
+func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.note = ""
+	f := m.files[m.file]
+	hunk := f.Hunks[m.hunk]
+
+	switch msg.String() {
+	case "ctrl+c":
+		m.outcome = Aborted
+		return m, tea.Quit
+	case "q":
+		m.outcome = Done
+		return m, tea.Quit
+	case "y":
+		hunk.Choice = Stage
+		return m.advance()
+	case "n":
+		hunk.Choice = Skip
+		return m.advance()
+	case "a", "d":
+		choice := Stage
+		if msg.String() == "d" {
+			choice = Skip
+		}
+		hunk.Choice = choice
+		for _, h := range f.Hunks[m.hunk+1:] {
+			if h.Choice == Undecided {
+				h.Choice = choice
+			}
+		}
+		return m.advance()
+	case "s":
+		if hunk.Choice != Undecided {
+			m.note = "only an undecided hunk can be split; u undoes the decision"
+		} else if before := len(f.Hunks); f.Split(m.hunk) {
+			m.note = fmt.Sprintf("split into %d hunks", len(f.Hunks)-before+1)
+			m.scroll = 0
+		} else {
+			m.note = "this hunk has a single change and cannot be split"
+		}
+	case "u":
+		hunk.Choice = Undecided
+	case "right", "l", "tab":
+		m.move(1)
+	case "left", "h", "shift+tab":
+		m.move(-1)
+	case "down", "j":
+		m.scroll = min(m.scroll+1, m.maxScroll())
+	case "up", "k":
+		m.scroll = max(m.scroll-1, 0)
+	case "pgdown", " ":
+		m.scroll = min(m.scroll+m.bodyRows(), m.maxScroll())
+	case "pgup":
+		m.scroll = max(m.scroll-m.bodyRows(), 0)
+	}
+	return m, nil
+}
+
+// move steps by delta hunks, across files, wrapping around at either end.
+func (m *model) move(delta int) {
+	var all [][2]int
+	at := 0
+	for fi, f := range m.files {
+		for hi := range f.Hunks {
+			if fi == m.file && hi == m.hunk {
+				at = len(all)
+			}
+			all = append(all, [2]int{fi, hi})
+		}
+	}
+	next := all[(at+delta+len(all))%len(all)]
+	m.file, m.hunk, m.scroll = next[0], next[1], 0
+}
+
+// advance moves on to the next undecided hunk, in this file or the ones
+// after it and then from the start, and finishes once none is left.
+func (m model) advance() (tea.Model, tea.Cmd) {
+	total := 0
+	for _, f := range m.files {
+		total += len(f.Hunks)
+	}
+	for i := 0; i < total; i++ {
+		m.move(1)
+		if m.files[m.file].Hunks[m.hunk].Choice == Undecided {
+			return m, nil
+		}
+	}
+	m.outcome = Done
+	return m, tea.Quit
+}
+
+// bodyRows is how many lines of the hunk fit between the header and the
+// help line.
+func (m model) bodyRows() int {
+	height := m.height
+	if height <= 0 {
+		height = fallbackHeight
+	}
+	return max(height-3, 1)
+}
+
+// maxScroll is the furthest the hunk can be scrolled.
+func (m model) maxScroll() int {
+	return max(len(m.files[m.file].Hunks[m.hunk].Lines)+1-m.bodyRows(), 0)
+}
+
+func (m model) View() string {
+	width := m.width
+	if width <= 0 {
+		width = ui.DefaultWidth
+	}
+	f := m.files[m.file]
+	hunk := f.Hunks[m.hunk]
+
+	staged, undecided, total := 0, 0, 0
+	for _, f := range m.files {
+		for _, h := range f.Hunks {
+			total++
+			switch h.Choice {
+			case Stage:
+				staged++
+			case Undecided:
+				undecided++
+			}
+		}
+	}
+
+	var b strings.Builder
+	choice := mutedStyle.Render(hunk.Choice.String())
+	switch hunk.Choice {
+	case Stage:
+		choice = stageStyle.Render(ui.Icon("✓") + "stage")
+	case Skip:
+		choice = skipStyle.Render("skip")
+	}
+	header := titleStyle.Render(f.Path) + mutedStyle.Render(fmt.Sprintf("  hunk %d/%d · ", m.hunk+1, len(f.Hunks))) + choice +
+		mutedStyle.Render(fmt.Sprintf(" · %d of %d to stage, %d undecided", staged, total, undecided))
+	b.WriteString(ansi.Truncate(header, width, "…") + "\n\n")
+
+	rows := m.bodyRows()
+	lines := renderHunk(f, hunk)
+	shown := lines[min(m.scroll, len(lines)):]
+	if len(shown) > rows {
+		more := len(shown) - rows + 1
+		shown = append(shown[:rows-1:rows-1], mutedStyle.Render(fmt.Sprintf("… %d more lines (↓ to scroll)", more)))
+	}
+	for _, l := range shown {
+		b.WriteString(ansi.Truncate(l, width, "…") + "\n")
+	}
+	for i := len(shown); i < rows; i++ {
+		b.WriteString("\n")
+	}
+
+	if m.note != "" {
+		b.WriteString(noteStyle.Render(ansi.Truncate(m.note, width, "…")))
+	} else {
+		b.WriteString(mutedStyle.Render(ansi.Truncate("y stage · n skip · s split · a stage rest of file · d skip rest of file · u undo · ←/→ hunk · ↑/↓ scroll · q stage chosen and quit · ctrl+c abort", width, "…")))
+	}
+	return b.String()
+}
+
+// renderHunk colors the hunk as a patch of its file, so its code is
+// highlighted for the file's language, and returns its lines.
+func renderHunk(f *File, h *Hunk) []string {
+	text := strings.Join(f.Header, "") + h.Header() + "\n" + strings.Join(h.Lines, "")
+	text = strings.ReplaceAll(text, "\t", "    ")
+	lines := strings.Split(strings.TrimSuffix(ui.RenderPatch(text), "\n"), "\n")
+	return lines[len(f.Header):]
+}
+
+// Run shows the hunks of files full screen on w until every one is decided
+// or the user leaves. Decisions are recorded on the files' hunks; when the
+// outcome is Done, each file's Patch is what to stage. Files without hunks
+// are left out.
+func Run(w io.Writer, in io.Reader, files []*File) (Outcome, error) {
+	var shown []*File
+	for _, f := range files {
+		if len(f.Hunks) > 0 {
+			shown = append(shown, f)
+		}
+	}
+	if len(shown) == 0 {
+		return Done, nil
+	}
+	p := tea.NewProgram(newModel(shown, 0, 0),
+		tea.WithOutput(w), tea.WithInput(in), tea.WithAltScreen(), tea.WithoutSignalHandler())
+	final, err := p.Run()
+	if err != nil {
+		return Aborted, err
+	}
+	return final.(model).outcome, nil
+}
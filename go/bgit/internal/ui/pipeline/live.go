@@ -0,0 +1,130 @@
+package pipeline
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type stageStartedMsg struct{ index int }
+
+type stageFinishedMsg struct {
+	index  int
+	result result
+}
+
+type stageProgressMsg struct{ detail string }
+
+type pipelineDoneMsg struct{ total time.Duration }
+
+// model is the live status board. Stages run in a separate goroutine and
+// report through messages; the model only draws.
+type model struct {
+	stages   []Stage
+	results  []result
+	started  []time.Time
+	begin    time.Time
+	total    time.Duration
+	finished bool
+	spinner  spinner.Model
+	cancel   context.CancelFunc
+}
+
+func (m model) Init() tea.Cmd { return m.spinner.Tick }
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// The terminal is in raw mode, so Ctrl-C arrives as a key rather
+		// than SIGINT. Cancel the work; the runner reports back when the
+		// current stage returns.
+		if msg.String() == "ctrl+c" {
+			m.cancel()
+		}
+	case stageStartedMsg:
+		m.results[msg.index].status = Running
+		m.started[msg.index] = time.Now()
+	case stageProgressMsg:
+		for i := range m.results {
+			if m.results[i].status == Running {
+				m.results[i].detail = msg.detail
+			}
+		}
+	case stageFinishedMsg:
+		m.results[msg.index] = msg.result
+	case pipelineDoneMsg:
+		m.finished = true
+		m.total = msg.total
+		return m, tea.Quit
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m model) View() string {
+	var b strings.Builder
+	for i, st := range m.stages {
+		b.WriteString(renderRow(st.Name, m.results[i], time.Since(m.started[i]), m.spinner.View()))
+		b.WriteString("\n")
+	}
+	total := m.total
+	if !m.finished {
+		total = time.Since(m.begin)
+	}
+	b.WriteString(renderTotal(total))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func runLive(ctx context.Context, w io.Writer, in io.Reader, stages []Stage) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	m := model{
+		stages:  stages,
+		results: make([]result, len(stages)),
+		started: make([]time.Time, len(stages)),
+		begin:   time.Now(),
+		spinner: spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(runningStyle)),
+		cancel:  cancel,
+	}
+
+	p := tea.NewProgram(m, tea.WithOutput(w), tea.WithInput(in), tea.WithoutSignalHandler())
+	ctx = context.WithValue(ctx, suspendKey{}, func(fn func() error) error {
+		if err := p.ReleaseTerminal(); err != nil {
+			return err
+		}
+		defer p.RestoreTerminal()
+		return fn()
+	})
+	ctx = context.WithValue(ctx, progressKey{}, func(detail string) { p.Send(stageProgressMsg{detail: detail}) })
+
+	errc := make(chan error, 1)
+	go func() {
+		var total time.Duration
+		err := runStages(ctx, stages,
+			func(i int) { p.Send(stageStartedMsg{index: i}) },
+			func(i int, r result) { p.Send(stageFinishedMsg{index: i, result: r}) },
+			func(t time.Duration) { total = t },
+		)
+		if err != nil {
+			total = time.Since(m.begin)
+		}
+		p.Send(pipelineDoneMsg{total: total})
+		errc <- err
+	}()
+
+	if _, err := p.Run(); err != nil {
+		cancel()
+		<-errc
+		return err
+	}
+	return <-errc
+}
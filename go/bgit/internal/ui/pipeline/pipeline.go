@@ -0,0 +1,234 @@
+// Package pipeline runs a fixed sequence of named stages and shows their
+// progress. On a terminal it draws a live Bubble Tea view with a spinner on
+// the running stage and a running total; elsewhere it falls back to one line
+// per finished stage so CI logs stay readable.
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/endalk200/bgit/internal/ui"
+)
+
+// Status is the lifecycle state of a stage.
+type Status int
+
+const (
+	Pending Status = iota
+	Running
+	Done
+	Skipped
+	Failed
+)
+
+// Stage is one step of a pipeline. Run returns a short detail shown next to
+// the stage name (e.g. "3 files") or an error that stops the pipeline.
+type Stage struct {
+	Name string
+	Run  func(ctx context.Context) (detail string, err error)
+	// KeepGoing lets the stages after this one run when it fails, for
+	// steps independent of each other; the pipeline still fails with its
+	// error once they are done.
+	KeepGoing bool
+}
+
+// SkipError marks a stage as intentionally not run. It does not stop the
+// pipeline.
+type SkipError struct {
+	Reason string
+}
+
+func (e SkipError) Error() string { return "skipped: " + e.Reason }
+
+// Skip returns an error that marks the current stage as skipped.
+func Skip(reason string) error { return SkipError{Reason: reason} }
+
+// suspendKey carries the live board's terminal hand-over in a stage's
+// context.
+type suspendKey struct{}
+
+// Suspend runs fn with the terminal to itself, for a stage that has to ask
+// the user something. The live board stops drawing while fn runs and picks up
+// again below whatever fn printed; in the other modes fn simply runs.
+func Suspend(ctx context.Context, fn func() error) error {
+	if suspend, ok := ctx.Value(suspendKey{}).(func(func() error) error); ok {
+		return suspend(fn)
+	}
+	return fn()
+}
+
+// progressKey carries the display's progress report in a stage's context.
+type progressKey struct{}
+
+// Progress replaces the detail shown next to the running stage, for a stage
+// that waits on something and can say how far it got. The live board shows
+// the latest detail; line mode prints each new one so long waits show in
+// logs too.
+func Progress(ctx context.Context, detail string) {
+	if report, ok := ctx.Value(progressKey{}).(func(string)); ok {
+		report(detail)
+	}
+}
+
+// Mode selects how progress is displayed.
+type Mode int
+
+const (
+	// Live redraws a status board in place. Requires a terminal.
+	Live Mode = iota
+	// Lines prints one line per finished stage.
+	Lines
+	// Silent shows nothing; used by --quiet.
+	Silent
+)
+
+// result is the outcome of a single stage.
+type result struct {
+	status  Status
+	detail  string
+	elapsed time.Duration
+	err     error
+}
+
+// Run executes stages in order, rendering progress to w in the given mode.
+// It stops at the first failing stage, unless that stage keeps going, and
+// returns the first error.
+func Run(ctx context.Context, w io.Writer, in io.Reader, mode Mode, stages []Stage) error {
+	switch mode {
+	case Live:
+		return runLive(ctx, w, in, stages)
+	case Lines:
+		current, began, last := 0, time.Now(), ""
+		ctx = context.WithValue(ctx, progressKey{}, func(detail string) {
+			if detail != last {
+				last = detail
+				fmt.Fprintln(w, renderRow(stages[current].Name, result{status: Running, detail: detail}, time.Since(began), "…"))
+			}
+		})
+		return runStages(ctx, stages, func(i int) { current, began, last = i, time.Now(), "" }, func(i int, r result) {
+			fmt.Fprintln(w, renderRow(stages[i].Name, r, 0, ""))
+		}, func(total time.Duration) {
+			fmt.Fprintln(w, renderTotal(total))
+		})
+	default:
+		return runStages(ctx, stages, nil, nil, nil)
+	}
+}
+
+// runStages is the display-independent core: it runs every stage, timing
+// each one and notifying the callbacks as stages start and finish.
+func runStages(ctx context.Context, stages []Stage, started func(i int), finished func(i int, r result), done func(total time.Duration)) error {
+	begin := time.Now()
+	var failed error // the first error of a stage that kept going
+	for i, st := range stages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if started != nil {
+			started(i)
+		}
+
+		t0 := time.Now()
+		detail, err := st.Run(ctx)
+		r := result{status: Done, detail: detail, elapsed: time.Since(t0)}
+
+		var skip SkipError
+		switch {
+		case errors.As(err, &skip):
+			r.status, r.detail = Skipped, skip.Reason
+		case err != nil:
+			r.status, r.err = Failed, err
+		}
+
+		if finished != nil {
+			finished(i, r)
+		}
+		if r.status == Failed {
+			if !st.KeepGoing {
+				return err
+			}
+			if failed == nil {
+				failed = err
+			}
+		}
+	}
+	if done != nil {
+		done(time.Since(begin))
+	}
+	return failed
+}
+
+var (
+	doneStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	failStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	runningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	mutedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+)
+
+// nameWidth aligns stage details into a column.
+const nameWidth = 24
+
+// symbol is the status marker for a row; spin is the current spinner frame.
+func symbol(s Status, spin string) string {
+	if ui.Plain() {
+		return map[Status]string{
+			Pending: "[  ]", Running: "[..]", Done: "[ok]", Skipped: "[--]", Failed: "[!!]",
+		}[s]
+	}
+	switch s {
+	case Running:
+		return runningStyle.Render(spin)
+	case Done:
+		return doneStyle.Render("✓")
+	case Skipped:
+		return mutedStyle.Render("–")
+	case Failed:
+		return failStyle.Render("✗")
+	default:
+		return mutedStyle.Render("○")
+	}
+}
+
+// renderRow formats one stage. running is the live elapsed time of a
+// running stage; finished stages use their recorded duration.
+func renderRow(name string, r result, running time.Duration, spin string) string {
+	row := "  " + symbol(r.status, spin) + " " + name
+	if pad := nameWidth - ui.StringWidth(name); pad > 0 {
+		row += strings.Repeat(" ", pad)
+	}
+
+	detail := r.detail
+	if r.status == Failed && r.err != nil {
+		detail = r.err.Error()
+	}
+	if detail != "" {
+		row += " " + mutedStyle.Render(detail)
+	}
+
+	switch r.status {
+	case Done, Failed:
+		row += " " + mutedStyle.Render(formatDuration(r.elapsed))
+	case Running:
+		row += " " + mutedStyle.Render(formatDuration(running))
+	}
+	return row
+}
+
+func renderTotal(total time.Duration) string {
+	return mutedStyle.Render("  Total " + formatDuration(total))
+}
+
+// formatDuration keeps durations short: milliseconds under a second, then
+// tenths of a second.
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
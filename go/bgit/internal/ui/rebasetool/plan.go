@@ -0,0 +1,87 @@
+// Package rebasetool is the plan editor of 'bgit rebase -i': the commits to
+// replay, oldest first, each with what to do with it. Commits are reordered,
+// squashed or fixed up into the one above, reworded or dropped, and the plan
+// is only carried out once the user runs it.
+package rebasetool
+
+import (
+	"errors"
+	"strings"
+)
+
+// Action is what the rebase does with a commit.
+type Action string
+
+const (
+	// Pick replays the commit as it is.
+	Pick Action = "pick"
+	// Reword replays the commit with a new message.
+	Reword Action = "reword"
+	// Squash melds the commit into the one before it, combining their
+	// messages.
+	Squash Action = "squash"
+	// Fixup melds the commit into the one before it, dropping its message.
+	Fixup Action = "fixup"
+	// Drop leaves the commit out.
+	Drop Action = "drop"
+)
+
+// Commit is one step of the plan.
+type Commit struct {
+	Hash string
+	// Message is the commit's message, or its new one when reworded.
+	Message string
+	Action  Action
+}
+
+// Subject is the first line of the message.
+func (c Commit) Subject() string {
+	return strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0]
+}
+
+// ErrNothingToMeld is returned for a plan that squashes or fixes up a commit
+// with no kept commit before it to go into.
+var ErrNothingToMeld = errors.New("the first commit kept has nothing before it to squash or fix up into")
+
+// Check reports whether plan can run.
+func Check(plan []Commit) error {
+	for _, c := range plan {
+		switch c.Action {
+		case Drop:
+			continue
+		case Squash, Fixup:
+			return ErrNothingToMeld
+		}
+		return nil
+	}
+	return nil
+}
+
+// Changed reports whether plan does anything but replay the commits of
+// original as they are.
+func Changed(original, plan []Commit) bool {
+	if len(original) != len(plan) {
+		return true
+	}
+	for i, c := range plan {
+		if c.Hash != original[i].Hash || c.Action != Pick {
+			return true
+		}
+	}
+	return false
+}
+
+// describe says what the rebase does with c, for the line under the list.
+func describe(c Commit) string {
+	switch c.Action {
+	case Reword:
+		return "replayed with the new message"
+	case Squash:
+		return "melded into the commit above, their messages combined"
+	case Fixup:
+		return "melded into the commit above, its message dropped"
+	case Drop:
+		return "left out"
+	}
+	return "replayed as it is"
+}
@@ -0,0 +1,152 @@
+package rebasetool
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/endalk200/bgit/internal/ui/uitest"
+	"github.com/muesli/termenv"
+)
+
+func TestMain(m *testing.M) {
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	os.Exit(m.Run())
+}
+
+func commits() []Commit {
+	return []Commit{
+		{Hash: "1a2b3c4d5e6f", Message: "feat(auth): add login form", Action: Pick},
+		{Hash: "2b3c4d5e6f7a", Message: "wip", Action: Pick},
+		{Hash: "3c4d5e6f7a8b", Message: "fix typo in login form", Action: Pick},
+		{Hash: "4d5e6f7a8b9c", Message: "debug logging", Action: Pick},
+	}
+}
+
+func actions(plan []Commit) string {
+	var s []string
+	for _, c := range plan {
+		s = append(s, string(c.Action)+" "+c.Hash[:1])
+	}
+	return strings.Join(s, ", ")
+}
+
+func TestCheck(t *testing.T) {
+	plan := commits()
+	if err := Check(plan); err != nil {
+		t.Errorf("Check(all picked) = %v", err)
+	}
+	plan[0].Action, plan[1].Action = Drop, Fixup
+	if err := Check(plan); !errors.Is(err, ErrNothingToMeld) {
+		t.Errorf("Check(fixup first kept) = %v, want ErrNothingToMeld", err)
+	}
+	plan[1].Action = Reword
+	plan[2].Action = Squash
+	if err := Check(plan); err != nil {
+		t.Errorf("Check(squash after reword) = %v", err)
+	}
+}
+
+func TestChanged(t *testing.T) {
+	plan := commits()
+	if Changed(commits(), plan) {
+		t.Errorf("an untouched plan counts as changed")
+	}
+	plan[0], plan[1] = plan[1], plan[0]
+	if !Changed(commits(), plan) {
+		t.Errorf("a reordered plan counts as unchanged")
+	}
+}
+
+func TestEditPlan(t *testing.T) {
+	var m tea.Model = newModel(commits(), Options{}, 80, 12)
+
+	// Move the typo fix up under the commit it fixes and fix it up, squash
+	// the wip commit and drop the debug logging.
+	m = uitest.Press(m, "down", "down", "K", "f", "down", "s", "down", "d")
+	if got, want := actions(m.(model).plan), "pick 1, fixup 3, squash 2, drop 4"; got != want {
+		t.Errorf("plan = %s, want %s", got, want)
+	}
+
+	// Reword the first commit.
+	m = uitest.Press(m, "g", "r", "ctrl+u", "feat(auth): add a login form", "ctrl+s")
+	first := m.(model).plan[0]
+	if first.Action != Reword || first.Message != "feat(auth): add a login form" {
+		t.Errorf("reworded commit = %+v", first)
+	}
+	uitest.AssertGolden(t, "edited", m.View())
+
+	m = uitest.Press(m, "enter")
+	if !m.(model).run {
+		t.Errorf("enter did not run the plan")
+	}
+}
+
+func TestRewordUnchangedKeepsPick(t *testing.T) {
+	var m tea.Model = newModel(commits(), Options{}, 80, 12)
+	m = uitest.Press(m, "down", "r", "ctrl+s")
+	if c := m.(model).plan[1]; c.Action != Pick || c.Message != "wip" {
+		t.Errorf("commit reworded to its own message = %+v, want it picked", c)
+	}
+}
+
+func TestRunRefusesNothingToMeld(t *testing.T) {
+	var m tea.Model = newModel(commits(), Options{}, 80, 12)
+	m = uitest.Press(m, "s", "enter")
+	if m.(model).run {
+		t.Fatalf("a plan squashing the first commit ran")
+	}
+	if !strings.Contains(uitest.StripANSI(m.View()), "nothing before it to squash") {
+		t.Errorf("no word of why the plan cannot run")
+	}
+}
+
+func TestQuitWithChangedPlan(t *testing.T) {
+	var m tea.Model = newModel(commits(), Options{}, 80, 12)
+	m = uitest.Press(m, "d")
+	m, cmd := m.Update(uitest.Key("q"))
+	if cmd != nil {
+		t.Fatalf("q gave up a changed plan without asking")
+	}
+	if _, cmd = m.Update(uitest.Key("q")); cmd == nil {
+		t.Errorf("a second q did not quit")
+	}
+	if m.(model).run {
+		t.Errorf("quitting runs the plan")
+	}
+}
+
+func TestRegenerate(t *testing.T) {
+	var asked string
+	opts := Options{Regenerate: func(hash string) (string, error) {
+		asked = hash
+		return "chore: remove the wip marker\n", nil
+	}}
+	var m tea.Model = newModel(commits(), opts, 80, 12)
+	m = uitest.Press(m, "down", "r")
+	m, cmd := m.Update(uitest.Key("ctrl+g"))
+	if cmd == nil {
+		t.Fatalf("ctrl+g asked for no message")
+	}
+	m, _ = m.Update(cmd())
+	if asked != "2b3c4d5e6f7a" {
+		t.Errorf("asked for a message for %q, want the commit being reworded", asked)
+	}
+	m = uitest.Press(m, "ctrl+s")
+	if c := m.(model).plan[1]; c.Action != Reword || c.Message != "chore: remove the wip marker" {
+		t.Errorf("commit after regenerating = %+v", c)
+	}
+}
+
+func TestViewGolden(t *testing.T) {
+	for _, w := range []int{40, 80, 120} {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			m := newModel(commits(), Options{Branch: "feature/login", Onto: "main"}, w, 12)
+			uitest.AssertGolden(t, fmt.Sprintf("plan_%d", w), m.View())
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package rebasetool
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var (
+	titleStyle  = lipgloss.NewStyle().Bold(true)
+	cursorStyle = lipgloss.NewStyle().Reverse(true)
+	mutedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	hashStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	noteStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+	actionStyles = map[Action]lipgloss.Style{
+		Pick:   lipgloss.NewStyle().Foreground(lipgloss.Color("42")),
+		Reword: lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+		Squash: lipgloss.NewStyle().Foreground(lipgloss.Color("39")),
+		Fixup:  lipgloss.NewStyle().Foreground(lipgloss.Color("39")),
+		Drop:   lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
+	}
+)
+
+// Fallback size, used until the terminal reports its own.
+const (
+	fallbackWidth  = 80
+	fallbackHeight = 24
+)
+
+// actionWidth fits the longest action name.
+const actionWidth = 6
+
+// Options configure the editor.
+type Options struct {
+	// Branch is rebased onto Onto; both are only shown.
+	Branch string
+	Onto   string
+	// Regenerate writes a new message for the commit with hash, for
+	// rewording; nil leaves messages to be typed.
+	Regenerate func(hash string) (string, error)
+}
+
+// generatedMsg carries the message Regenerate wrote for a commit.
+type generatedMsg struct {
+	hash    string
+	message string
+	err     error
+}
+
+type model struct {
+	opts     Options
+	plan     []Commit
+	original []Commit
+	cursor   int
+	offset   int // first commit shown
+
+	editing    bool
+	editor     textarea.Model
+	generating bool
+
+	note        string // one-off feedback, cleared by the next key
+	confirmQuit bool
+	run         bool
+	width       int
+	height      int
+}
+
+func newModel(plan []Commit, opts Options, width, height int) model {
+	editor := textarea.New()
+	editor.ShowLineNumbers = false
+	editor.MaxHeight = 0
+	editor.MaxWidth = 0
+	editor.CharLimit = 0
+	return model{
+		opts:     opts,
+		plan:     append([]Commit(nil), plan...),
+		original: plan,
+		editor:   editor,
+		width:    width,
+		height:   height,
+	}
+}
+
+func (m model) Init() tea.Cmd { return nil }
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.resizeEditor()
+		m.scroll()
+		return m, nil
+	case generatedMsg:
+		m.generating = false
+		switch {
+		case msg.err != nil:
+			m.note = "no message: " + msg.err.Error()
+		case m.editing && m.plan[m.cursor].Hash == msg.hash:
+			m.editor.SetValue(strings.TrimSpace(msg.message))
+			m.note = "message generated; ctrl+s keeps it"
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if m.editing {
+			return m.updateEditor(msg)
+		}
+		return m.updateKey(msg)
+	}
+	if m.editing {
+		var cmd tea.Cmd
+		m.editor, cmd = m.editor.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// updateKey handles a key while moving through the plan.
+func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	quitting := m.confirmQuit
+	m.note, m.confirmQuit = "", false
+
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		if Changed(m.original, m.plan) && !quitting {
+			m.note = "the plan is not run: enter runs it, q again gives it up"
+			m.confirmQuit = true
+			return m, nil
+		}
+		return m, tea.Quit
+	case "enter":
+		if err := Check(m.plan); err != nil {
+			m.note = err.Error()
+			return m, nil
+		}
+		m.run = true
+		return m, tea.Quit
+	case "down", "j":
+		m.cursor = min(m.cursor+1, len(m.plan)-1)
+	case "up", "k":
+		m.cursor = max(m.cursor-1, 0)
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = len(m.plan) - 1
+	case "shift+down", "J":
+		if m.cursor+1 < len(m.plan) {
+			m.plan[m.cursor], m.plan[m.cursor+1] = m.plan[m.cursor+1], m.plan[m.cursor]
+			m.cursor++
+		}
+	case "shift+up", "K":
+		if m.cursor > 0 {
+			m.plan[m.cursor], m.plan[m.cursor-1] = m.plan[m.cursor-1], m.plan[m.cursor]
+			m.cursor--
+		}
+	case "p":
+		m.plan[m.cursor].Action = Pick
+		m.plan[m.cursor].Message = m.originalMessage(m.plan[m.cursor].Hash)
+	case "s":
+		m.meld(Squash)
+	case "f":
+		m.meld(Fixup)
+	case "d":
+		m.plan[m.cursor].Action = Drop
+	case "r":
+		m.editing = true
+		m.editor.SetValue(strings.TrimSpace(m.plan[m.cursor].Message))
+		m.resizeEditor()
+		return m, m.editor.Focus()
+	}
+	m.scroll()
+	return m, nil
+}
+
+// meld sets the current commit to be squashed or fixed up into the one
+// before it, keeping the message it had.
+func (m *model) meld(action Action) {
+	c := &m.plan[m.cursor]
+	c.Action = action
+	c.Message = m.originalMessage(c.Hash)
+	if err := Check(m.plan); err != nil {
+		m.note = err.Error()
+	}
+}
+
+// updateEditor handles a key while a message is being reworded.
+func (m model) updateEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.note = ""
+	switch msg.String() {
+	case "esc":
+		m.editing = false
+		m.editor.Blur()
+		return m, nil
+	case "ctrl+s":
+		m.editing = false
+		m.editor.Blur()
+		message := strings.TrimSpace(m.editor.Value())
+		c := &m.plan[m.cursor]
+		switch {
+		case message == "":
+			m.note = "an empty message is not kept"
+		case message == strings.TrimSpace(m.originalMessage(c.Hash)):
+			c.Action, c.Message = Pick, m.originalMessage(c.Hash)
+		default:
+			c.Action, c.Message = Reword, message
+		}
+		return m, nil
+	case "ctrl+g":
+		switch {
+		case m.opts.Regenerate == nil:
+			m.note = "no AI provider to write a message"
+			return m, nil
+		case m.generating:
+			return m, nil
+		}
+		m.generating = true
+		m.note = "writing a message…"
+		hash, regenerate := m.plan[m.cursor].Hash, m.opts.Regenerate
+		return m, func() tea.Msg {
+			message, err := regenerate(hash)
+			return generatedMsg{hash: hash, message: message, err: err}
+		}
+	}
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	return m, cmd
+}
+
+func (m model) originalMessage(hash string) string {
+	for _, c := range m.original {
+		if c.Hash == hash {
+			return c.Message
+		}
+	}
+	return ""
+}
+
+func (m model) size() (width, height int) {
+	width, height = m.width, m.height
+	if width <= 0 {
+		width = fallbackWidth
+	}
+	if height <= 0 {
+		height = fallbackHeight
+	}
+	return width, height
+}
+
+// listRows is the room for the plan between the header and the footer.
+func (m model) listRows() int {
+	_, height := m.size()
+	return max(height-5, 1)
+}
+
+func (m *model) resizeEditor() {
+	width, height := m.size()
+	m.editor.SetWidth(max(width-2, 1))
+	m.editor.SetHeight(max(height-5, 1))
+}
+
+// scroll keeps the cursor on screen.
+func (m *model) scroll() {
+	rows := m.listRows()
+	switch {
+	case m.cursor < m.offset:
+		m.offset = m.cursor
+	case m.cursor >= m.offset+rows:
+		m.offset = m.cursor - rows + 1
+	}
+}
+
+func (m model) View() string {
+	width, _ := m.size()
+	var b strings.Builder
+
+	header := titleStyle.Render("bgit rebase -i")
+	if m.opts.Branch != "" {
+		header += mutedStyle.Render("  " + m.opts.Branch + " onto " + m.opts.Onto)
+	}
+	header += mutedStyle.Render(fmt.Sprintf(" · %s, oldest first", plural(len(m.plan), "commit")))
+	b.WriteString(ansi.Truncate(header, width, "…") + "\n\n")
+
+	if m.editing {
+		c := m.plan[m.cursor]
+		b.WriteString(ansi.Truncate("Message of "+hashStyle.Render(shortHash(c.Hash)), width, "…") + "\n")
+		b.WriteString(m.editor.View() + "\n\n")
+		keys := "ctrl+s keep · ctrl+g generate · esc cancel"
+		if m.opts.Regenerate == nil {
+			keys = "ctrl+s keep · esc cancel"
+		}
+		b.WriteString(m.footerKeys(keys, width))
+		return b.String()
+	}
+
+	rows := m.listRows()
+	end := min(m.offset+rows, len(m.plan))
+	for i := m.offset; i < end; i++ {
+		b.WriteString(m.row(i, width) + "\n")
+	}
+	b.WriteString(strings.Repeat("\n", rows-(end-m.offset)+1))
+
+	b.WriteString(ansi.Truncate(mutedStyle.Render(describe(m.plan[m.cursor])), width, "…") + "\n")
+	b.WriteString(m.footerKeys("J/K move · p pick · r reword · s squash · f fixup · d drop · enter run · q quit", width))
+	return b.String()
+}
+
+// footerKeys is the keys that apply, or the note when there is one.
+func (m model) footerKeys(keys string, width int) string {
+	if m.note != "" {
+		return noteStyle.Render(ansi.Truncate(m.note, width, "…"))
+	}
+	return mutedStyle.Render(ansi.Truncate(keys, width, "…"))
+}
+
+// row renders one commit: its action, hash and subject, the subject of a
+// reworded commit as it will be.
+func (m model) row(i, width int) string {
+	c := m.plan[i]
+	action := string(c.Action)
+	action = actionStyles[c.Action].Render(action + strings.Repeat(" ", actionWidth-len(action)))
+	subject := c.Subject()
+	switch c.Action {
+	case Drop:
+		subject = mutedStyle.Render(subject)
+	case Reword:
+		subject = actionStyles[Reword].Render(subject)
+	}
+	line := action + "  " + hashStyle.Render(shortHash(c.Hash)) + " " + subject
+	if i == m.cursor {
+		return ansi.Truncate(cursorStyle.Render("›")+" "+line, width, "…")
+	}
+	return ansi.Truncate("  "+line, width, "…")
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// Run shows the editor full screen on w until the user runs the plan or
+// gives it up, and returns the plan as edited and whether to run it.
+func Run(w io.Writer, in io.Reader, plan []Commit, opts Options) ([]Commit, bool, error) {
+	if len(plan) == 0 {
+		return nil, false, nil
+	}
+	p := tea.NewProgram(newModel(plan, opts, 0, 0),
+		tea.WithOutput(w), tea.WithInput(in), tea.WithAltScreen(), tea.WithoutSignalHandler())
+	final, err := p.Run()
+	if err != nil {
+		return nil, false, err
+	}
+	m := final.(model)
+	return m.plan, m.run, nil
+}
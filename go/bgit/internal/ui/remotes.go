@@ -0,0 +1,48 @@
+package ui
+
+import "strings"
+
+// RemoteEntry is one configured remote.
+type RemoteEntry struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Mirror is set for a remote bgit push keeps in step with the others.
+	Mirror bool `json:"mirror"`
+	// SSHKey is the key bgit signs in to the remote with, when it has one
+	// of its own.
+	SSHKey string `json:"ssh_key,omitempty"`
+}
+
+// RenderRemoteList lists remotes with their URL, marking the mirrors, and
+// under each remote with a key of its own that key.
+func RenderRemoteList(entries []RemoteEntry, width int) string {
+	if len(entries) == 0 {
+		return "No remotes yet; add one with 'git remote add'.\n"
+	}
+	nameWidth := 0
+	for _, e := range entries {
+		nameWidth = max(nameWidth, StringWidth(e.Name))
+	}
+	nameWidth = min(nameWidth, max(width/3, 10))
+
+	var b strings.Builder
+	for _, e := range entries {
+		name := TruncateMiddle(e.Name, nameWidth)
+		line := headerStyle.Render(name) + strings.Repeat(" ", nameWidth-StringWidth(name)) + "  "
+		room := width - nameWidth - 2
+		if e.Mirror {
+			room -= len("  mirror")
+		}
+		if room >= 10 {
+			line += mutedStyle.Render(TruncateMiddle(e.URL, room))
+		}
+		if e.Mirror {
+			line += "  " + okStyle.Render("mirror")
+		}
+		b.WriteString(line + "\n")
+		if e.SSHKey != "" {
+			b.WriteString(strings.Repeat(" ", nameWidth+2) + mutedStyle.Render(TruncateMiddle("key "+e.SSHKey, max(width-nameWidth-2, 10))) + "\n")
+		}
+	}
+	return b.String()
+}
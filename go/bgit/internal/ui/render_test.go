@@ -0,0 +1,765 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/endalk200/bgit/internal/ui/uitest"
+	"github.com/muesli/termenv"
+)
+
+// widths are the terminal widths every rendered view is snapshotted at.
+var widths = []int{40, 80, 120}
+
+func TestMain(m *testing.M) {
+	// Render with full color so the snapshots prove ANSI is normalized away.
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	os.Exit(m.Run())
+}
+
+func TestRenderStatusGolden(t *testing.T) {
+	cases := map[string]StatusView{
+		"clean": {Branch: "main"},
+		"mixed": {
+			Branch:    "feature/status-rendering",
+			Staged:    []string{"cmd/status.go", "internal/ui/status.go"},
+			Added:     []string{"internal/ui/status.go"},
+			Modified:  []string{"README.md"},
+			Deleted:   []string{"old/legacy_file.go"},
+			Untracked: []string{"docs/a/very/deeply/nested/directory/structure/with/a/long/file_name.md"},
+		},
+		"unicode": {
+			Branch:    "機能/絵文字",
+			Staged:    []string{"docs/日本語/はじめに.md", "assets/🚀/launch-🎉.png"},
+			Modified:  []string{"café/résumé.md"},
+			Untracked: []string{"notes/👩‍💻/これはとても長いファイル名のテストです.txt"},
+		},
+		"changelists": {
+			Branch:    "main",
+			Staged:    []string{"cmd/status.go"},
+			Modified:  []string{"cmd/status.go", "internal/ui/status.go", "README.md"},
+			Untracked: []string{"docs/changelists.md"},
+			Changelists: []Changelist{
+				{Name: "ui-fixes", Files: []ChangelistFile{{Path: "internal/ui/status.go", State: "modified"}, {Path: "docs/changelists.md", State: "untracked"}}},
+				{Name: "empty", Files: []ChangelistFile{}},
+				{Name: "docs", Files: []ChangelistFile{{Path: "README.md", State: "modified"}}},
+			},
+		},
+		"tracking": {
+			Branch:   "feature/status-rendering",
+			Upstream: "origin/feature/status-rendering",
+			Ahead:    2,
+			Behind:   5,
+			Modified: []string{"README.md"},
+		},
+		"clean_tracking": {Branch: "main", Upstream: "origin/main"},
+		"gone":           {Branch: "old-work", Upstream: "origin/old-work", UpstreamGone: true, Staged: []string{"cmd/status.go"}},
+		"owners": {
+			Branch: "main",
+			Staged: []string{"internal/billing/invoice.go", "internal/billing/tax.go", "db/migrations/0042_add_billing_accounts.sql", "README.md"},
+			Owners: []OwnerArea{
+				{Pattern: "/internal/billing/", Owners: []string{"@acme/payments", "@lena"}, Files: []string{"internal/billing/invoice.go", "internal/billing/tax.go"}},
+				{Pattern: "*.sql", Owners: []string{"@acme/database-reliability-engineering"}, Files: []string{"db/migrations/0042_add_billing_accounts.sql"}},
+				{Owners: []string{}, Files: []string{"README.md"}},
+			},
+		},
+		"stashes": {
+			Branch:   "main",
+			Modified: []string{"README.md"},
+			Stashes: []StashEntry{
+				{Ref: "stash@{0}", Message: "On main: half-done parser"},
+				{Ref: "stash@{1}", Message: "WIP on feature/status-rendering: 3fd3808 Render status with lipgloss and keep columns aligned"},
+			},
+		},
+		"empty_dirs": {
+			Branch:    "main",
+			Modified:  []string{"README.md"},
+			EmptyDirs: []string{"logs", "assets/uploads/a/very/deeply/nested/directory/structure/for/user/content"},
+			Stashes:   []StashEntry{{Ref: "stash@{0}", Message: "On main: half-done parser"}},
+		},
+		"clean_empty_dirs": {Branch: "main", EmptyDirs: []string{"tmp/cache"}},
+		"clean_stashes":    {Branch: "main", Stashes: []StashEntry{{Ref: "stash@{0}", Message: "On main: half-done parser"}}},
+	}
+
+	for name, view := range cases {
+		for _, w := range widths {
+			t.Run(fmt.Sprintf("%s/%d", name, w), func(t *testing.T) {
+				uitest.AssertGolden(t, fmt.Sprintf("status_%s_%d", name, w), RenderStatus(view, w))
+			})
+		}
+	}
+}
+
+func TestRenderCommitSummaryGolden(t *testing.T) {
+	when := time.Date(2025, time.March, 14, 9, 26, 53, 0, time.UTC)
+	author := Person{Name: "Ada Lovelace", Email: "ada@example.com"}
+
+	cases := map[string]CommitView{
+		"simple": {
+			Hash:      "3fd3808a1b2c3d4e5f60718293a4b5c6d7e8f901",
+			Author:    author,
+			Committer: author,
+			When:      when,
+			Message:   "feat(ui): render status with lipgloss",
+		},
+		"long_message_committer": {
+			Hash:      "0123456789abcdef0123456789abcdef01234567",
+			Author:    author,
+			Committer: Person{Name: "CI Bot", Email: "ci@example.com"},
+			When:      when,
+			Message:   "refactor(cmd): route all command output through the ui package so that rendering can be snapshot tested across widths",
+		},
+		"stats": {
+			Hash:      "89abcdef0123456789abcdef0123456789abcdef",
+			Author:    author,
+			Committer: author,
+			When:      when,
+			Message:   "feat(ui): show a diffstat after committing",
+			Stats:     diffStats,
+		},
+	}
+
+	for name, view := range cases {
+		for _, w := range widths {
+			t.Run(fmt.Sprintf("%s/%d", name, w), func(t *testing.T) {
+				uitest.AssertGolden(t, fmt.Sprintf("commit_%s_%d", name, w), RenderCommitSummary(view, w))
+			})
+		}
+	}
+}
+
+func TestRenderCommitNotesGolden(t *testing.T) {
+	view := CommitView{
+		Hash:     "89abcdef0123456789abcdef0123456789abcdef",
+		Author:   Person{Name: "Ada Lovelace", Email: "ada@example.com"},
+		When:     time.Date(2025, time.March, 14, 9, 26, 53, 0, time.UTC),
+		Message:  "feat(cmd): record the build environment\n\nAttach it as a git note.",
+		Notes:    "os: linux/amd64\ngo: go1.24.1\ngit: 2.43.0\ndirty-submodules: none\n",
+		NotesRef: "bgit",
+	}
+	uitest.AssertGolden(t, "commit_notes_80", RenderCommit(view, 80))
+}
+
+func TestNormalizeStripsANSI(t *testing.T) {
+	styled := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000")).Bold(true).Render("hello")
+	if styled == "hello" {
+		t.Fatal("expected styled output to contain escape sequences")
+	}
+	if got := uitest.Normalize(styled + "   \r\n"); got != "hello\n" {
+		t.Errorf("Normalize() = %q, want %q", got, "hello\n")
+	}
+}
+
+func TestRenderPlainGolden(t *testing.T) {
+	SetPlain(true)
+	defer SetPlain(false)
+
+	author := Person{Name: "Ada Lovelace", Email: "ada@example.com"}
+	commit := CommitView{
+		Hash:      "0123456789abcdef0123456789abcdef01234567",
+		Author:    author,
+		Committer: Person{Name: "CI Bot", Email: "ci@example.com"},
+		When:      time.Date(2025, time.March, 14, 9, 26, 53, 0, time.UTC),
+		Message:   "chore: plain output for CI logs",
+	}
+	status := StatusView{
+		Branch:    "main",
+		Staged:    []string{"cmd/root.go"},
+		Untracked: []string{"notes.txt"},
+	}
+
+	uitest.AssertGolden(t, "plain_commit_80", RenderCommitSummary(commit, 80))
+	uitest.AssertGolden(t, "plain_status_80", RenderStatus(status, 80))
+}
+
+// diffStats covers a large change next to small ones, a deletion-only file,
+// a rename and a binary file.
+var diffStats = []FileStat{
+	{Path: "internal/ui/diffstat.go", Insertions: 412, Deletions: 3},
+	{Path: "cmd/commit.go", Insertions: 4, Deletions: 2},
+	{Path: "docs/a/very/deeply/nested/directory/structure/with/a/long/file_name.md", Deletions: 17},
+	{Path: "old.go => new.go", Insertions: 1, Deletions: 1},
+	{Path: "assets/logo.png", Binary: true},
+}
+
+func TestRenderDiffStatGolden(t *testing.T) {
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("diffstat_%d", w), RenderDiffStat(diffStats, w))
+		})
+	}
+}
+
+func TestRenderDiffStatGenerated(t *testing.T) {
+	stats := append([]FileStat{
+		{Path: "go.sum", Insertions: 120, Deletions: 40, Generated: true},
+		{Path: "vendor/x/y.go", Insertions: 900, Generated: true},
+	}, diffStats...)
+	uitest.AssertGolden(t, "diffstat_generated_80", RenderDiffStat(stats, 80))
+
+	onlyGenerated := RenderDiffStat(stats[:1], 80)
+	if !strings.Contains(onlyGenerated, "1 generated file not shown (+120 -40)") {
+		t.Errorf("RenderDiffStat(generated only) = %q, want the hidden-files note", onlyGenerated)
+	}
+}
+
+func TestDiffStatSummary(t *testing.T) {
+	tests := []struct {
+		stats []FileStat
+		want  string
+	}{
+		{[]FileStat{{Path: "a", Insertions: 1}}, "1 file changed, 1 insertion(+)"},
+		{[]FileStat{{Path: "a", Deletions: 2}, {Path: "b", Deletions: 1}}, "2 files changed, 3 deletions(-)"},
+		{[]FileStat{{Path: "a", Insertions: 2, Deletions: 1}}, "1 file changed, 2 insertions(+), 1 deletion(-)"},
+		{[]FileStat{{Path: "a.png", Binary: true}}, "1 file changed, 0 insertions(+), 0 deletions(-)"},
+	}
+	for _, tt := range tests {
+		if got := DiffStatSummary(tt.stats); got != tt.want {
+			t.Errorf("DiffStatSummary(%v) = %q, want %q", tt.stats, got, tt.want)
+		}
+	}
+}
+
+func TestRenderNumstat(t *testing.T) {
+	want := "412\t3\tinternal/ui/diffstat.go\n4\t2\tcmd/commit.go\n" +
+		"0\t17\tdocs/a/very/deeply/nested/directory/structure/with/a/long/file_name.md\n" +
+		"1\t1\told.go => new.go\n-\t-\tassets/logo.png\n"
+	if got := RenderNumstat(diffStats); got != want {
+		t.Errorf("RenderNumstat() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderSpellingGolden(t *testing.T) {
+	message := "fix: recieve the the Github config\n\nIt occured on every run."
+	issues := []SpellingIssue{
+		{Offset: 5, Word: "recieve", Suggestion: "receive", Kind: "misspelling"},
+		{Offset: 16, Word: " the", Kind: "repeated word"},
+		{Offset: 21, Word: "Github", Suggestion: "GitHub", Kind: "terminology"},
+		{Offset: 39, Word: "occured", Suggestion: "occurred", Kind: "misspelling"},
+	}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("spelling_%d", w), RenderSpelling(message, issues, w))
+		})
+	}
+}
+
+func TestRenderTaskResultsGolden(t *testing.T) {
+	var output []string
+	for i := 1; i <= 24; i++ {
+		output = append(output, fmt.Sprintf("internal/ui/tasks.go:%d: line %d is wrong in a way that takes a long explanation to describe", i, i))
+	}
+	results := []TaskResult{
+		{Name: "fmt", Summary: "passed in 0.2s"},
+		{Name: "lint", Summary: "exit status 1 after 3.4s", Failed: true, Output: strings.Join(output, "\n")},
+		{Name: "test", Summary: "timed out after 2m0s", Failed: true},
+	}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("tasks_%d", w), RenderTaskResults(results, w))
+		})
+	}
+}
+
+func TestRenderBranchTableGolden(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC)
+	table := BranchTable{
+		Base:   "main",
+		Now:    now,
+		ShowPR: true,
+		Rows: []BranchRow{
+			{Name: "feature/branch-comparison-matrix", Current: true, Ahead: 3, Behind: 1, When: now.Add(-2 * time.Hour), Author: "Ada Lovelace", PR: "#42"},
+			{Name: "fix/typo", Ahead: 0, Behind: 12, When: now.Add(-20 * 24 * time.Hour), Author: "Grace Hopper"},
+			{Name: "main", When: now.Add(-30 * time.Minute), Author: "Ada Lovelace"},
+			{Name: "spike/old-idea", Ahead: 7, Behind: 240, When: now.Add(-400 * 24 * time.Hour), Author: "Charles Babbage", PR: "#7 (draft)"},
+		},
+	}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("branches_%d", w), RenderBranchTable(table, w))
+		})
+	}
+
+	table.ShowPR, table.PRNote = false, "Pull requests not shown: origin is not hosted on GitHub"
+	uitest.AssertGolden(t, "branches_no_pr_80", RenderBranchTable(table, 80))
+}
+
+func TestRenderBranchListGolden(t *testing.T) {
+	rows := []BranchListRow{
+		{Name: "feature/a-very-long-branch-name-for-truncation", Hash: "0123456789abcdef0123456789abcdef01234567", Subject: "Truncate long branch names in the middle", Upstream: "origin/feature/a-very-long-branch-name-for-truncation", Ahead: 2, Behind: 5},
+		{Name: "fix/42-add-fails", Hash: "89abcdef0123456789abcdef0123456789abcdef", Subject: "Handle deleted files in add", Upstream: "origin/fix/42-add-fails", Gone: true},
+		{Name: "main", Current: true, Hash: "3fd3808a1b2c3d4e5f60718293a4b5c6d7e8f901", Subject: "Merge branch 'feature/b'", Upstream: "origin/main", Ahead: 1},
+		{Name: "scratch", Hash: "fedcba9876543210fedcba9876543210fedcba98", Subject: "Try things out"},
+	}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("branch_list_%d", w), RenderBranchList(rows, w))
+		})
+	}
+}
+
+func TestRenderCIGolden(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC)
+	head := "3fd3808a1b2c3d4e5f60718293a4b5c6d7e8f901"
+	view := CIView{
+		Repo:   "github.com/endalk200/bgit",
+		Branch: "feature/ci-status",
+		Head:   head,
+		Now:    now,
+		Runs: []RunRow{
+			{ID: 8123456701, Workflow: "CI", Event: "push", State: "success", SHA: head, Attempt: 1, When: now.Add(-2 * time.Hour)},
+			{ID: 8123456702, Workflow: "Lint and static analysis", Event: "pull_request", State: "failure", SHA: head, Attempt: 2, When: now.Add(-90 * time.Minute)},
+			{ID: 8123456703, Workflow: "Release", Event: "push", State: "in_progress", SHA: head, Attempt: 1, When: now.Add(-3 * time.Minute)},
+			{ID: 8123456600, Workflow: "Docs", Event: "push", State: "cancelled", SHA: "0123456789abcdef0123456789abcdef01234567", Attempt: 1, When: now.Add(-3 * 24 * time.Hour)},
+		},
+		Failed: []JobRow{
+			{ID: 22334455, RunID: 8123456702, Workflow: "Lint and static analysis", Name: "golangci-lint", State: "failure", Step: "Run golangci-lint"},
+			{ID: 22334456, RunID: 8123456702, Workflow: "Lint and static analysis", Name: "vet", State: "timed_out"},
+		},
+	}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("ci_%d", w), RenderCI(view, w))
+		})
+	}
+
+	uitest.AssertGolden(t, "ci_empty_80", RenderCI(CIView{Repo: view.Repo, Branch: "main", Head: head, Now: now}, 80))
+}
+
+func TestRenderIssuesGolden(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC)
+	issues := []IssueRow{
+		{Number: 42, Title: "add fails when a staged file was deleted from the working tree", State: "open", Labels: []string{"bug", "good first issue"}, Assignees: []string{"ada"}, Comments: 3, When: now.Add(-2 * time.Hour)},
+		{Number: 108, Title: "Support GitLab merge requests", State: "open", Labels: []string{"enhancement"}, When: now.Add(-5 * 24 * time.Hour)},
+		{Number: 7, Title: "Document the config file", State: "closed", Labels: []string{"documentation"}, Assignees: []string{"grace", "charles"}, When: now.Add(-60 * 24 * time.Hour)},
+	}
+	list := IssueList{Repo: "github.com/endalk200/bgit", State: "all", Issues: issues, Now: now}
+	view := IssueView{
+		IssueRow: issues[0],
+		Body:     "Steps to reproduce:\r\n\r\n1. `rm tracked.go`\r\n2. `bgit add tracked.go`\r\n\r\nThe add reports the file as not found instead of staging the deletion, which leaves the index out of date.",
+		Branch:   "fix/42-add-fails-when-a-staged-file-was-deleted",
+		Created:  now.Add(-3 * 24 * time.Hour),
+		Now:      now,
+	}
+	view.Author = "grace"
+	view.URL = "https://github.com/endalk200/bgit/issues/42"
+
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("issues_%d", w), RenderIssueList(list, w))
+			uitest.AssertGolden(t, fmt.Sprintf("issue_view_%d", w), RenderIssue(view, w))
+		})
+	}
+	uitest.AssertGolden(t, "issues_empty_80", RenderIssueList(IssueList{Repo: list.Repo, State: "open", Now: now}, 80))
+}
+
+func TestRenderLogLine(t *testing.T) {
+	for line, want := range map[string]struct {
+		text  string
+		group bool
+	}{
+		"2025-03-14T09:00:00.1234567Z ##[group]Run go test ./...":  {"▸ Run go test ./...", true},
+		"2025-03-14T09:00:01.0000000Z ok  \tgithub.com/x/y\t0.01s": {"ok  \tgithub.com/x/y\t0.01s", false},
+		"2025-03-14T09:00:02.0000000Z ##[endgroup]":                {"", false},
+		"##[error]Process completed with exit code 1.":             {"error: Process completed with exit code 1.", false},
+		"no timestamp\r": {"no timestamp", false},
+	} {
+		text, group := RenderLogLine(line)
+		if text = ansi.Strip(text); text != want.text || group != want.group {
+			t.Errorf("RenderLogLine(%q) = %q, %v; want %q, %v", line, text, group, want.text, want.group)
+		}
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC)
+	for ago, want := range map[time.Duration]string{
+		10 * time.Second:     "just now",
+		time.Minute:          "1 minute ago",
+		5 * time.Hour:        "5 hours ago",
+		3 * 24 * time.Hour:   "3 days ago",
+		21 * 24 * time.Hour:  "3 weeks ago",
+		100 * 24 * time.Hour: "3 months ago",
+		800 * 24 * time.Hour: "2 years ago",
+	} {
+		if got := RelativeTime(now.Add(-ago), now); got != want {
+			t.Errorf("RelativeTime(now - %v) = %q, want %q", ago, got, want)
+		}
+	}
+}
+
+func TestRenderOnboardingGolden(t *testing.T) {
+	entries := []CheatEntry{
+		{Command: "bgit status", Summary: "See what is staged, modified and untracked"},
+		{Command: "bgit add --all", Summary: "Stage every change"},
+		{Command: "bgit commit", Summary: "Commit with a message written from the staged diff"},
+		{Command: "bgit config init", Summary: "Run the configuration questions again"},
+	}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("onboarding_%d", w), RenderWelcome(w)+RenderCheatSheet(entries, w))
+		})
+	}
+}
+
+// activityFixture is a span of ten months with a few busy weeks, and the
+// commits of its busiest day.
+func activityFixture() (ActivityView, time.Time, []DayCommit) {
+	from := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.October, 31, 0, 0, 0, 0, time.UTC)
+	counts := map[string]int{}
+	for i, n := range []int{1, 3, 2, 7, 1, 1, 4, 2, 5, 1} {
+		day := from.AddDate(0, 0, 30*i+i%3)
+		counts[day.Format(DayLayout)] = n
+		counts[day.AddDate(0, 0, 1).Format(DayLayout)] = n / 2
+	}
+	busiest := from.AddDate(0, 0, 90)
+	view := ActivityView{From: from, To: to, Counts: counts, Author: "alice", Selected: busiest}
+	commits := []DayCommit{
+		{Hash: "3fd3808a1b2c3d4e5f60718293a4b5c6d7e8f901", Subject: "Add the activity heatmap", Author: "Alice Example", When: busiest.Add(9*time.Hour + 12*time.Minute)},
+		{Hash: "0123456789abcdef0123456789abcdef01234567", Subject: "Shade each day by how busy it was compared with the busiest one on the map", Author: "Alice Example", When: busiest.Add(14*time.Hour + 3*time.Minute)},
+	}
+	return view, busiest, commits
+}
+
+func TestRenderActivityGolden(t *testing.T) {
+	view, day, commits := activityFixture()
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("activity_%d", w), RenderActivity(view, w))
+			uitest.AssertGolden(t, fmt.Sprintf("activity_day_%d", w), RenderDayCommits(day, commits, w))
+		})
+	}
+
+	empty := ActivityView{From: view.From, To: view.To, Counts: map[string]int{}}
+	uitest.AssertGolden(t, "activity_empty_80", RenderActivity(empty, 80))
+}
+
+// graphFixture is two branches merged into main, the second with a long
+// subject, above a second root joined in by an unrelated-history merge.
+func graphFixture() GraphView {
+	now := time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC)
+	commit := func(hash, subject string, ago time.Duration, parents ...string) GraphCommit {
+		return GraphCommit{Hash: hash, Subject: subject, Author: "Alice Example", When: now.Add(-ago), Parents: parents}
+	}
+	return GraphView{Now: now, Commits: []GraphCommit{
+		commit("mergeb1", "Merge branch 'feature/b'", time.Hour, "main003", "branchb"),
+		commit("main003", "Tidy the README", 2*time.Hour, "mergea1"),
+		commit("mergea1", "Merge branch 'feature/a'", 3*time.Hour, "main002", "brancha"),
+		commit("main002", "Document the config file", 5*time.Hour, "main001"),
+		commit("branchb", "Draw the commit graph with a column per branch and colour each line", 6*time.Hour, "main001"),
+		commit("brancha", "Add log --oneline", 7*time.Hour, "main001"),
+		commit("main001", "Merge the imported history", 30*time.Hour, "root001", "import1"),
+		commit("root001", "Initial commit", 48*time.Hour),
+		commit("import1", "Import the old tool", 72*time.Hour),
+	}}
+}
+
+func TestRenderGraphGolden(t *testing.T) {
+	view := graphFixture()
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("graph_%d", w), strings.Join(RenderGraph(view, w), ""))
+		})
+	}
+
+	view.Oneline = true
+	uitest.AssertGolden(t, "graph_oneline_80", strings.Join(RenderGraph(view, 80), ""))
+}
+
+func TestRenderGraphExportGolden(t *testing.T) {
+	view := graphFixture()
+	uitest.AssertGolden(t, "graph_svg", RenderGraphSVG(view))
+	uitest.AssertGolden(t, "graph_html", RenderGraphHTML(view, "History of main"))
+	uitest.AssertGolden(t, "graph_dot", RenderGraphDOT(view))
+
+	// Only the parents shown get an edge; the text is escaped for each
+	// format.
+	view.Commits = []GraphCommit{{Hash: "tip0001", Parents: []string{"gone001"}, Subject: `Quote "<b>" & more`}}
+	view.Oneline = true
+	if svg := RenderGraphSVG(view); !strings.Contains(svg, "Quote &#34;&lt;b&gt;&#34; &amp; more") {
+		t.Errorf("SVG subject not escaped:\n%s", svg)
+	}
+	if dot := RenderGraphDOT(view); strings.Contains(dot, "->") || !strings.Contains(dot, `Quote \"<b>\" & more`) {
+		t.Errorf("DOT has an edge to a commit not shown, or an unescaped label:\n%s", dot)
+	}
+}
+
+func TestRenderExplanationGolden(t *testing.T) {
+	view := ExplainView{
+		Subject:  "3fd3808 Draw the commit graph in log when no path is given",
+		Provider: "OpenAI",
+		Text: `## Purpose
+The log command now draws the history as a graph when it is run without a
+path, the way git log --graph does.
+
+## Notable parts
+- RenderGraph keeps one column per line of history and joins them above
+  the commit they meet at:
+
+` + "```go" + `
+# not a heading inside the fence
+lanes = joinLanes(&b, lanes, next)
+` + "```" + `
+
+## Risks
+Very wide histories are truncated at the terminal width.`,
+		Note: "Part of the diff was left out to fit the ai.* limits of the config.",
+	}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("explanation_%d", w), RenderExplanation(view, w))
+		})
+	}
+}
+
+func TestRenderRiskGolden(t *testing.T) {
+	view := RiskView{Score: 78, Level: "high", Factors: []RiskFactor{
+		{Name: "Large change", Points: 28, Detail: "402 lines in 3 files"},
+		{Name: "No tests", Points: 20, Detail: "2 source files changed without a test"},
+		{Name: "Migrations or config", Points: 20, Detail: "db/migrations/0042_add_billing_accounts.sql, deploy/docker-compose.prod.yml"},
+		{Name: "Bug-prone files", Points: 10, Detail: "internal/billing/invoice.go (3 fixes)"},
+		{Name: "AI review", Points: -6, Detail: "rated 66: the migration only adds a nullable column"},
+	}}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("risk_%d", w), RenderRisk(view, w))
+		})
+	}
+
+	uitest.AssertGolden(t, "risk_none_80", RenderRisk(RiskView{Score: 0, Level: "low"}, 80))
+}
+
+func TestRenderTestPlanGolden(t *testing.T) {
+	view := TestPlanView{
+		Suites: []TestSuite{
+			{Name: "./go/bgit/internal/ui", Tests: []string{"go/bgit/internal/ui/status_test.go", "go/bgit/internal/ui/render_test.go", "go/bgit/internal/ui/width_test.go"}, Sources: []string{"go/bgit/internal/ui/status.go"}},
+			{Name: "web/src/", Tests: []string{"web/test/checkout.test.ts"}, Sources: []string{"web/src/checkout.ts", "web/src/cart.ts"}},
+		},
+		Untested: []string{"go/bgit/cmd/status.go"},
+		Commands: []TestCommand{
+			{Run: "go test ./internal/ui", Dir: "go/bgit"},
+			{Run: "npx vitest run web/test/checkout.test.ts", Dir: "."},
+		},
+	}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("testplan_%d", w), RenderTestPlan(view, w))
+		})
+	}
+
+	uitest.AssertGolden(t, "testplan_none_80", RenderTestPlan(TestPlanView{Untested: []string{"cmd/status.go"}}, 80))
+}
+
+func TestRenderStashListGolden(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC)
+	entries := []StashEntry{
+		{Ref: "stash@{0}", Branch: "main", Message: "On main: half-done parser", When: now.Add(-5 * time.Minute)},
+		{Ref: "stash@{1}", Branch: "feature/status-rendering", Message: "WIP on feature/status-rendering: 3fd3808 Render status with lipgloss and keep columns aligned", When: now.Add(-3 * 24 * time.Hour)},
+	}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("stash_list_%d", w), RenderStashList(entries, now, w))
+		})
+	}
+
+	uitest.AssertGolden(t, "stash_list_empty_80", RenderStashList(nil, now, 80))
+}
+
+const samplePatch = `diff --git a/main.go b/main.go
+index d6e0156..8976f2d 100644
+--- a/main.go
++++ b/main.go
+@@ -1,4 +1,5 @@
+ package main
+ 
++// main greets.
+ func main() {
+-	println("hi")
++	println("hello", 42)
+diff --git a/notes.txt b/notes.txt
+deleted file mode 100644
+index 286c5f5..0000000
+--- a/notes.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+`
+
+func TestRenderPatchKeepsText(t *testing.T) {
+	// Highlighting only adds color: the patch must still apply, tabs and all.
+	if got := ansi.Strip(RenderPatch(samplePatch)); got != samplePatch {
+		t.Errorf("RenderPatch() without color =\n%s\nwant\n%s", got, samplePatch)
+	}
+}
+
+func TestSplitPatch(t *testing.T) {
+	files := SplitPatch(samplePatch)
+	if len(files) != 2 {
+		t.Fatalf("SplitPatch() = %d files, want 2", len(files))
+	}
+	if !strings.HasPrefix(files[1], "diff --git a/notes.txt") || strings.Join(files, "") != samplePatch {
+		t.Errorf("SplitPatch() = %q, want the patch cut before each file", files)
+	}
+	if files := SplitPatch(""); files != nil {
+		t.Errorf("SplitPatch(\"\") = %q, want nil", files)
+	}
+}
+
+func TestRenderTagListGolden(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC)
+	entries := []TagEntry{
+		{Name: "before-refactor", Commit: "f54376a19114f70642eb1e9c48008dfa2a0dc8c6", When: now.Add(-40 * 24 * time.Hour)},
+		{Name: "v1.3.0", Commit: "20ef47b809fea7c9478586b0a01625c37d2b9138", Annotated: true, Message: "Release v1.3.0\n\n### Features\n\n- parse faster", When: now.Add(-3 * 24 * time.Hour)},
+		{Name: "v1.4.0-rc.1", Commit: "32f7d25dbebeb83b9cac52f7d15744ff60cc835d", Annotated: true, Message: "First release candidate with the new parser and the rewritten status screen", When: now.Add(-2 * time.Hour)},
+	}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("tag_list_%d", w), RenderTagList(entries, now, w))
+		})
+	}
+
+	uitest.AssertGolden(t, "tag_list_empty_80", RenderTagList(nil, now, 80))
+}
+
+func TestRenderRemoteListGolden(t *testing.T) {
+	entries := []RemoteEntry{
+		{Name: "origin", URL: "git@github.com:endalk200/bgit.git"},
+		{Name: "backup", URL: "https://git.example.com/mirrors/endalk200/bgit-with-a-rather-long-path.git", Mirror: true},
+		{Name: "work", URL: "git@git.example.com:team/bgit.git", SSHKey: "~/.ssh/id_ed25519_work"},
+	}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("remote_list_%d", w), RenderRemoteList(entries, w))
+		})
+	}
+
+	uitest.AssertGolden(t, "remote_list_empty_80", RenderRemoteList(nil, 80))
+}
+
+func TestRenderResetPlanGolden(t *testing.T) {
+	plan := ResetPlan{
+		Mode:      "hard",
+		Branch:    "feature/login",
+		From:      "9c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d",
+		To:        "20ef47b809fea7c9478586b0a01625c37d2b9138",
+		ToSubject: "Release v1.3.0",
+		Leaving: []ResetCommit{
+			{Hash: "9c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d", Subject: "Remember the last user who signed in on this device"},
+			{Hash: "32f7d25dbebeb83b9cac52f7d15744ff60cc835d", Subject: "Add the login form"},
+		},
+		Joining: []ResetCommit{
+			{Hash: "20ef47b809fea7c9478586b0a01625c37d2b9138", Subject: "Release v1.3.0"},
+		},
+		Lost: []string{"internal/auth/session.go", "web/login.html"},
+	}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("reset_plan_%d", w), RenderResetPlan(plan, w))
+		})
+	}
+
+	for _, mode := range []string{"soft", "mixed"} {
+		p := plan
+		p.Mode, p.Joining, p.Lost = mode, nil, nil
+		uitest.AssertGolden(t, "reset_plan_"+mode+"_80", RenderResetPlan(p, 80))
+	}
+	unstage := ResetPlan{Mode: "mixed", Branch: "main", From: plan.To, To: plan.To, ToSubject: plan.ToSubject}
+	uitest.AssertGolden(t, "reset_plan_unstage_80", RenderResetPlan(unstage, 80))
+}
+
+func TestRenderCherryPickGolden(t *testing.T) {
+	result := CherryPickResult{
+		Branch: "release/1.3",
+		Commits: []CherryPickCommit{
+			{Hash: "32f7d25dbebeb83b9cac52f7d15744ff60cc835d", Subject: "Add the login form", Picked: "8d0c4b1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c"},
+			{Hash: "9c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d", Subject: "Remember the last user who signed in on this device"},
+			{Hash: "20ef47b809fea7c9478586b0a01625c37d2b9138", Subject: "Release v1.3.0"},
+		},
+		Applied:   1,
+		Conflicts: []string{"internal/auth/session.go", "web/login.html"},
+	}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("cherry_pick_%d", w), RenderCherryPick(result, w))
+		})
+	}
+
+	done := result
+	done.Commits = append([]CherryPickCommit(nil), result.Commits...)
+	done.Commits[1].Picked, done.Commits[2].Picked = "e41f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f", "5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e1f2a3b4c"
+	done.Applied, done.Conflicts = 3, nil
+	uitest.AssertGolden(t, "cherry_pick_done_80", RenderCherryPick(done, 80))
+
+	staged := CherryPickResult{Commits: []CherryPickCommit{
+		{Hash: result.Commits[1].Hash, Subject: result.Commits[1].Subject},
+		{Hash: result.Commits[2].Hash, Subject: result.Commits[2].Subject},
+	}, Applied: 2, NoCommit: true}
+	uitest.AssertGolden(t, "cherry_pick_no_commit_80", RenderCherryPick(staged, 80))
+}
+
+func TestRenderMergeSummaryGolden(t *testing.T) {
+	summary := MergeSummary{
+		Rev:    "feature/login",
+		Branch: "main",
+		Kind:   "three-way",
+		Base:   MergeCommit{Hash: "20ef47b809fea7c9478586b0a01625c37d2b9138", Subject: "Release v1.3.0"},
+		Commit: "8d0c4b1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c",
+		Incoming: []MergeCommit{
+			{Hash: "9c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d", Subject: "Remember the last user who signed in on this device"},
+			{Hash: "32f7d25dbebeb83b9cac52f7d15744ff60cc835d", Subject: "Add the login form"},
+		},
+	}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("merge_summary_%d", w), RenderMergeSummary(summary, w))
+		})
+	}
+
+	conflicted := summary
+	conflicted.Commit, conflicted.Conflicts = "", []string{"internal/auth/session.go", "web/login.html"}
+	uitest.AssertGolden(t, "merge_summary_conflicts_80", RenderMergeSummary(conflicted, 80))
+
+	staged := summary
+	staged.Commit, staged.NoCommit = "", true
+	uitest.AssertGolden(t, "merge_summary_no_commit_80", RenderMergeSummary(staged, 80))
+
+	ff := summary
+	ff.Kind, ff.Commit = "fast-forward", summary.Incoming[0].Hash
+	ff.Incoming = nil
+	for i := range 12 {
+		ff.Incoming = append(ff.Incoming, MergeCommit{Hash: fmt.Sprintf("%07x", 0xa1b2c30+i) + strings.Repeat("0", 33), Subject: fmt.Sprintf("Step %d of the login flow", 12-i)})
+	}
+	uitest.AssertGolden(t, "merge_summary_fast_forward_80", RenderMergeSummary(ff, 80))
+
+	upToDate := MergeSummary{Rev: "feature/login", Branch: "main", Kind: "up-to-date"}
+	uitest.AssertGolden(t, "merge_summary_up_to_date_80", RenderMergeSummary(upToDate, 80))
+}
+
+func TestRenderBlameGolden(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC)
+	line := func(hash, author string, ago time.Duration, text string) BlameLine {
+		return BlameLine{Hash: hash + strings.Repeat("0", 33), Author: author, When: now.Add(-ago), Text: text}
+	}
+	day := 24 * time.Hour
+	view := BlameView{Path: "internal/auth/session.go", Rev: "HEAD", Now: now, Lines: []BlameLine{
+		line("1a2b3c4", "Alice Example", 400*day, "package auth"),
+		line("1a2b3c4", "Alice Example", 400*day, ""),
+		line("5d6e7f8", "Bartholomew Longname-Smith", 90*day, "// Session is a signed-in user on one device, kept until it expires or is revoked."),
+		line("1a2b3c4", "Alice Example", 400*day, "type Session struct {"),
+		line("9a8b7c6", "Chen Wei", 3*time.Hour, "\tUserID    string"),
+		line("9a8b7c6", "Chen Wei", 3*time.Hour, "\tDevice    string // the name the user gave it"),
+		line("0f1e2d3", "Dana", 20*day, "\tExpiresAt time.Time"),
+		line("1a2b3c4", "Alice Example", 400*day, "}"),
+	}}
+	for _, w := range widths {
+		t.Run(fmt.Sprint(w), func(t *testing.T) {
+			uitest.AssertGolden(t, fmt.Sprintf("blame_%d", w), strings.Join(RenderBlame(view, w), ""))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"strings"
+)
+
+// ResetCommit is a commit a reset takes off the branch or puts on it.
+type ResetCommit struct {
+	Hash    string
+	Subject string
+}
+
+// ResetPlan is what 'bgit reset' is about to do: move Branch (HEAD when
+// detached) from From to To in Mode, soft, mixed or hard, with the commits
+// the branch loses and gains and the files whose uncommitted changes a
+// hard reset throws away.
+type ResetPlan struct {
+	Mode      string
+	Branch    string
+	From      string
+	To        string
+	ToSubject string
+	Leaving   []ResetCommit
+	Joining   []ResetCommit
+	Lost      []string
+}
+
+// resetEffects says what p does to the staging area and the working tree.
+func resetEffects(p ResetPlan) (staging, worktree string) {
+	carried := ""
+	if len(p.Leaving) > 0 {
+		carried = "what the commits leaving " + p.Branch + " changed"
+	}
+	switch p.Mode {
+	case "soft":
+		staging, worktree = "kept as it is", "kept as it is"
+		if carried != "" {
+			staging += "; " + carried + " shows as staged"
+		}
+	case "mixed":
+		staging, worktree = "matches the new commit; staged changes become unstaged", "kept as it is"
+		if carried != "" {
+			staging = "matches the new commit; staged changes, and " + carried + ", become unstaged"
+		}
+	default:
+		staging = "matches the new commit"
+		worktree = "tracked files match the new commit, uncommitted changes to them are lost; untracked files stay"
+	}
+	return staging, worktree
+}
+
+// RenderResetPlan explains p: where the branch goes, the commits it loses
+// and gains, and what becomes of the staging area and the working tree.
+func RenderResetPlan(p ResetPlan, width int) string {
+	var b strings.Builder
+	head := "Reset " + p.Branch + " to "
+	if p.From == p.To {
+		head = "Keep " + p.Branch + " at "
+	}
+	b.WriteString(headerStyle.Render(head) + hashStyle.Render(shortHash(p.To)) + " " + truncateEnd(p.ToSubject, max(width-StringWidth(head)-8, 10)) + mutedStyle.Render(" (--"+p.Mode+")") + "\n")
+
+	commits := func(title string, list []ResetCommit, style func(...string) string) {
+		if len(list) == 0 {
+			return
+		}
+		b.WriteString("\n" + headerStyle.Render(title) + mutedStyle.Render(" ("+pluralize(len(list), "commit", "commits")+")") + "\n")
+		for _, c := range list {
+			b.WriteString("  " + style(Bullet()) + " " + hashStyle.Render(shortHash(c.Hash)) + " " + truncateEnd(c.Subject, max(width-12, 10)) + "\n")
+		}
+	}
+	commits("Leaving "+p.Branch, p.Leaving, deletedStyle.Render)
+	commits("Joining "+p.Branch, p.Joining, stagedStyle.Render)
+
+	staging, worktree := resetEffects(p)
+	b.WriteString("\n")
+	b.WriteString(HangingIndent("  "+mutedStyle.Render("Staging area  "), staging, width) + "\n")
+	b.WriteString(HangingIndent("  "+mutedStyle.Render("Working tree  "), worktree, width) + "\n")
+
+	if len(p.Lost) > 0 {
+		b.WriteString("\n" + RenderSection("Changes lost", p.Lost, deletedStyle, width))
+	}
+	return b.String()
+}
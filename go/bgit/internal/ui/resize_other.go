@@ -0,0 +1,13 @@
+//go:build !unix
+
+package ui
+
+import (
+	"context"
+	"os"
+)
+
+// notifyResize never fires: there is no resize signal on this platform.
+func notifyResize(ctx context.Context) <-chan os.Signal {
+	return make(chan os.Signal)
+}
@@ -0,0 +1,21 @@
+//go:build unix
+
+package ui
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyResize delivers a value for every SIGWINCH until ctx is done.
+func notifyResize(ctx context.Context) <-chan os.Signal {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGWINCH)
+	go func() {
+		<-ctx.Done()
+		signal.Stop(sig)
+	}()
+	return sig
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RiskFactor is one reason a change is risky and the points it adds.
+type RiskFactor struct {
+	Name   string
+	Points int
+	Detail string
+}
+
+// RiskView is the risk score of the changes about to be committed.
+type RiskView struct {
+	Score int
+	// Level is low, medium or high, by the thresholds in the config.
+	Level   string
+	Factors []RiskFactor
+}
+
+// minRiskDetail is the narrowest the column of factor details gets.
+const minRiskDetail = 30
+
+// RenderRisk shows the score and level of a change, then each factor with
+// the points it adds.
+func RenderRisk(v RiskView, width int) string {
+	var b strings.Builder
+	style := stagedStyle
+	switch v.Level {
+	case "medium":
+		style = modifiedStyle
+	case "high":
+		style = deletedStyle
+	}
+	b.WriteString(headerStyle.Render(fmt.Sprintf("Commit risk %d/100", v.Score)) + mutedStyle.Render(" · ") + style.Render(v.Level) + "\n")
+	if len(v.Factors) == 0 {
+		b.WriteString("  " + mutedStyle.Render("nothing stands out") + "\n")
+		return b.String()
+	}
+
+	nameWidth := 0
+	for _, f := range v.Factors {
+		nameWidth = max(nameWidth, StringWidth(f.Name))
+	}
+	for _, f := range v.Factors {
+		points := fmt.Sprintf("%+4d", f.Points)
+		name := f.Name + strings.Repeat(" ", nameWidth-StringWidth(f.Name))
+		if width-nameWidth-10 < minRiskDetail {
+			// Too narrow for a column of details: each goes under its factor.
+			b.WriteString("  " + points + "  " + f.Name + "\n")
+			b.WriteString(HangingIndent("        ", mutedStyle.Render(f.Detail), width) + "\n")
+			continue
+		}
+		b.WriteString(HangingIndent("  "+points+"  "+name+"  ", mutedStyle.Render(f.Detail), width) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,483 @@
+// Package settings is a full-screen editor for bgit's configuration: every
+// setting, grouped by section, with the value in effect and the file it
+// comes from. Values are checked as they are typed, and edits are only
+// written, to the file chosen, when the user saves.
+package settings
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/endalk200/bgit/internal/ui"
+)
+
+var (
+	titleStyle   = lipgloss.NewStyle().Bold(true)
+	sectionStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))
+	cursorStyle  = lipgloss.NewStyle().Reverse(true)
+	mutedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	pendingStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	errorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	noteStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+)
+
+// Fallback size, used until the terminal reports its own.
+const (
+	fallbackWidth  = 80
+	fallbackHeight = 24
+)
+
+// Field is one setting as the editor shows it.
+type Field struct {
+	Key     string
+	Section string
+	Help    string
+	// Value is the value in effect, as it is edited.
+	Value string
+	// Origin is where Value comes from: a layer name, "extends" or
+	// "default".
+	Origin string
+	// Bool fields are toggled rather than typed.
+	Bool bool
+	// Choices, when set, are the only values; enter steps through them.
+	Choices []string
+	// ReadOnly fields are only edited in the file itself.
+	ReadOnly bool
+	// SecretEnv marks a Value that names the environment variable holding
+	// a secret. The secret is shown next to it, masked.
+	SecretEnv bool
+}
+
+// Layer is a config file changes can be saved to.
+type Layer struct {
+	Name string
+	Path string
+}
+
+// Change is an edit to save.
+type Change struct {
+	Key   string
+	Layer string
+	Value string
+	// Unset removes the key from the layer instead, so the value from a
+	// lower one applies again.
+	Unset bool
+}
+
+// Options configure the editor.
+type Options struct {
+	// Layers are the files changes can be saved to; the first is the
+	// target until the user picks another.
+	Layers []Layer
+	// Validate checks a value as typed; the error is shown under it.
+	Validate func(key, value string) error
+	// Save writes changes and returns the fields as they now stand.
+	Save func([]Change) ([]Field, error)
+	// Getenv looks up the variables named by SecretEnv fields; nil means
+	// os.Getenv.
+	Getenv func(string) string
+}
+
+type model struct {
+	opts    Options
+	fields  []Field
+	cursor  int
+	offset  int // first list line shown
+	layer   int // index into opts.Layers of the target
+	pending []Change
+	saved   []Change
+
+	editing bool
+	input   textinput.Model
+	invalid string // why the value being typed is not valid
+
+	note        string // one-off feedback, cleared by the next key
+	confirmQuit bool
+	width       int
+	height      int
+}
+
+func newModel(fields []Field, opts Options, width, height int) model {
+	if opts.Getenv == nil {
+		opts.Getenv = os.Getenv
+	}
+	input := textinput.New()
+	input.Prompt = ""
+	return model{opts: opts, fields: fields, input: input, width: width, height: height}
+}
+
+func (m model) Init() tea.Cmd { return nil }
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.scroll()
+		return m, nil
+	case tea.KeyMsg:
+		if m.editing {
+			return m.updateInput(msg)
+		}
+		return m.updateKey(msg)
+	}
+	if m.editing {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// updateKey handles a key while moving through the list.
+func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	quitting := m.confirmQuit
+	m.note, m.confirmQuit = "", false
+	field := m.fields[m.cursor]
+
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		if len(m.pending) > 0 && !quitting {
+			m.note = fmt.Sprintf("%s not saved: w saves, q again discards", plural(len(m.pending), "change"))
+			m.confirmQuit = true
+			return m, nil
+		}
+		return m, tea.Quit
+	case "down", "j":
+		m.cursor = min(m.cursor+1, len(m.fields)-1)
+	case "up", "k":
+		m.cursor = max(m.cursor-1, 0)
+	case "pgdown", "ctrl+d":
+		m.cursor = min(m.cursor+m.listRows()/2, len(m.fields)-1)
+	case "pgup", "ctrl+u":
+		m.cursor = max(m.cursor-m.listRows()/2, 0)
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = len(m.fields) - 1
+	case "tab":
+		if len(m.opts.Layers) > 1 {
+			m.layer = (m.layer + 1) % len(m.opts.Layers)
+		}
+	case "enter", "e", " ":
+		switch {
+		case field.ReadOnly:
+			m.note = fmt.Sprintf("%s is edited in %s", field.Key, m.target().Path)
+		case field.Bool:
+			m.edit(field.Key, fmt.Sprint(m.value(field) != "true"))
+		case len(field.Choices) > 0:
+			m.edit(field.Key, nextChoice(field.Choices, m.value(field)))
+		default:
+			m.editing = true
+			m.invalid = ""
+			m.input.SetValue(m.value(field))
+			m.input.CursorEnd()
+			return m, m.input.Focus()
+		}
+	case "x", "delete":
+		if field.Origin != m.target().Name {
+			m.note = fmt.Sprintf("%s is not set in the %s file", field.Key, m.target().Name)
+			break
+		}
+		m.drop(field.Key)
+		m.pending = append(m.pending, Change{Key: field.Key, Layer: m.target().Name, Unset: true})
+	case "u":
+		m.drop(field.Key)
+	case "w", "ctrl+s":
+		m.save()
+	}
+	m.scroll()
+	return m, nil
+}
+
+// updateInput handles a key while a value is being typed.
+func (m model) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	field := m.fields[m.cursor]
+	switch msg.String() {
+	case "esc":
+		m.editing = false
+		m.input.Blur()
+		return m, nil
+	case "enter":
+		if m.invalid != "" {
+			return m, nil
+		}
+		m.editing = false
+		m.input.Blur()
+		if value := m.input.Value(); value != m.value(field) {
+			m.edit(field.Key, value)
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	m.invalid = ""
+	if m.opts.Validate != nil {
+		if err := m.opts.Validate(field.Key, m.input.Value()); err != nil {
+			m.invalid = err.Error()
+		}
+	}
+	return m, cmd
+}
+
+// edit records value for key, to be saved to the target layer.
+func (m *model) edit(key, value string) {
+	m.drop(key)
+	m.pending = append(m.pending, Change{Key: key, Layer: m.target().Name, Value: value})
+}
+
+// drop forgets the pending change to key, if any.
+func (m *model) drop(key string) {
+	for i, c := range m.pending {
+		if c.Key == key {
+			m.pending = append(m.pending[:i], m.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// change returns the pending change to key, if any.
+func (m model) change(key string) (Change, bool) {
+	for _, c := range m.pending {
+		if c.Key == key {
+			return c, true
+		}
+	}
+	return Change{}, false
+}
+
+// value is the field's value with its pending edit applied.
+func (m model) value(f Field) string {
+	if c, ok := m.change(f.Key); ok && !c.Unset {
+		return c.Value
+	}
+	return f.Value
+}
+
+func (m *model) save() {
+	if len(m.pending) == 0 {
+		m.note = "nothing to save"
+		return
+	}
+	fields, err := m.opts.Save(m.pending)
+	if err != nil {
+		m.note = "not saved: " + err.Error()
+		return
+	}
+	m.note = fmt.Sprintf("saved %s", plural(len(m.pending), "change"))
+	m.saved = append(m.saved, m.pending...)
+	m.pending = nil
+	m.fields = fields
+	m.cursor = min(m.cursor, len(fields)-1)
+}
+
+func (m model) target() Layer {
+	if len(m.opts.Layers) == 0 {
+		return Layer{}
+	}
+	return m.opts.Layers[m.layer]
+}
+
+func (m model) size() (width, height int) {
+	width, height = m.width, m.height
+	if width <= 0 {
+		width = fallbackWidth
+	}
+	if height <= 0 {
+		height = fallbackHeight
+	}
+	return width, height
+}
+
+// listRows is the room for the list between the header and the footer.
+func (m model) listRows() int {
+	_, height := m.size()
+	return max(height-5, 1)
+}
+
+// lines lays the list out: a heading before each section, then its
+// fields. It returns the line of each field too.
+func (m model) lines(width int) (lines []string, at []int) {
+	keyWidth := 0
+	for _, f := range m.fields {
+		keyWidth = max(keyWidth, ui.StringWidth(f.Key))
+	}
+	keyWidth = min(keyWidth, width/2)
+
+	section := ""
+	for i, f := range m.fields {
+		if f.Section != section {
+			if section != "" {
+				lines = append(lines, "")
+			}
+			section = f.Section
+			lines = append(lines, sectionStyle.Render(section))
+		}
+		at = append(at, len(lines))
+		lines = append(lines, m.row(i, f, keyWidth, width))
+	}
+	return lines, at
+}
+
+// originWidth fits "extends", "default" and the layer names.
+const originWidth = 9
+
+// row renders one field: its key, its value (with the pending edit and the
+// masked secret) and where the value comes from.
+func (m model) row(i int, f Field, keyWidth, width int) string {
+	origin := f.Origin
+	value := m.value(f)
+	valueStyle := lipgloss.NewStyle()
+	if c, ok := m.change(f.Key); ok {
+		valueStyle = pendingStyle
+		origin = "→ " + c.Layer
+		if c.Unset {
+			value = "(unset)"
+		}
+	}
+	if value == "" {
+		value = mutedStyle.Render("(empty)")
+	} else {
+		value = valueStyle.Render(value)
+	}
+	if name := m.value(f); f.SecretEnv && name != "" {
+		value += "  " + mutedStyle.Render(m.secret(name))
+	}
+
+	key := ansi.Truncate(f.Key, keyWidth, "…")
+	key += strings.Repeat(" ", keyWidth-ui.StringWidth(key))
+	room := max(width-4-keyWidth-2-originWidth, 1)
+	value = ansi.Truncate(value, room, "…")
+	value += strings.Repeat(" ", max(room-ui.StringWidth(value), 0))
+	line := "  " + key + "  " + value + " " + mutedStyle.Render(fmt.Sprintf("%*s", originWidth, origin))
+	if i == m.cursor {
+		return cursorStyle.Render("›") + " " + ansi.Truncate(line[2:], width-2, "…")
+	}
+	return ansi.Truncate(line, width, "…")
+}
+
+// secret shows whether the variable named is set, and its value masked.
+func (m model) secret(name string) string {
+	value := m.opts.Getenv(name)
+	if value == "" {
+		return "(not set)"
+	}
+	return "(" + Mask(value) + ")"
+}
+
+// Mask hides a secret, keeping just enough of it to tell two apart: a
+// prefix such as "sk-" and the last four characters of long values.
+func Mask(secret string) string {
+	r := []rune(secret)
+	if len(r) < 12 {
+		return strings.Repeat("•", 8)
+	}
+	return string(r[:3]) + strings.Repeat("•", 8) + string(r[len(r)-4:])
+}
+
+// scroll keeps the cursor's line on screen.
+func (m *model) scroll() {
+	width, _ := m.size()
+	_, at := m.lines(width)
+	if len(at) == 0 {
+		return
+	}
+	line, rows := at[m.cursor], m.listRows()
+	// Keep the section heading in view above the first field.
+	if m.cursor == 0 || m.fields[m.cursor].Section != m.fields[m.cursor-1].Section {
+		line--
+	}
+	switch {
+	case line < m.offset:
+		m.offset = max(line, 0)
+	case at[m.cursor] >= m.offset+rows:
+		m.offset = at[m.cursor] - rows + 1
+	}
+}
+
+func (m model) View() string {
+	width, _ := m.size()
+	var b strings.Builder
+
+	header := titleStyle.Render("bgit settings")
+	if t := m.target(); t.Name != "" {
+		header += mutedStyle.Render(fmt.Sprintf("  saving to %s (%s)", t.Name, t.Path))
+		if len(m.opts.Layers) > 1 {
+			header += mutedStyle.Render(" · tab switches")
+		}
+	}
+	b.WriteString(ansi.Truncate(header, width, "…") + "\n\n")
+
+	lines, _ := m.lines(width)
+	rows := m.listRows()
+	end := min(m.offset+rows, len(lines))
+	shown := lines[min(m.offset, end):end]
+	b.WriteString(strings.Join(shown, "\n"))
+	b.WriteString(strings.Repeat("\n", rows-len(shown)+1))
+
+	b.WriteString(m.footer(width))
+	return b.String()
+}
+
+// footer explains the current field, or shows the value being typed, above
+// the keys that apply.
+func (m model) footer(width int) string {
+	field := m.fields[m.cursor]
+	var detail, keys string
+	switch {
+	case m.editing:
+		m.input.Width = max(width-ui.StringWidth(field.Key)-3, 1)
+		detail = field.Key + ": " + m.input.View()
+		keys = "enter keep · esc cancel"
+		if m.invalid != "" {
+			keys = errorStyle.Render(m.invalid)
+		}
+	default:
+		detail = mutedStyle.Render(field.Help)
+		keys = "↑/↓ move · enter edit · x unset · u undo · w save · q quit"
+		if len(m.opts.Layers) > 1 {
+			keys = "↑/↓ move · enter edit · tab file · x unset · u undo · w save · q quit"
+		}
+		keys = mutedStyle.Render(keys)
+		if m.note != "" {
+			keys = noteStyle.Render(m.note)
+		}
+	}
+	return ansi.Truncate(detail, width, "…") + "\n" + ansi.Truncate(keys, width, "…")
+}
+
+func nextChoice(choices []string, current string) string {
+	for i, c := range choices {
+		if c == current {
+			return choices[(i+1)%len(choices)]
+		}
+	}
+	return choices[0]
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// Run shows the editor full screen on w until the user quits, and returns
+// the changes saved on the way.
+func Run(w io.Writer, in io.Reader, fields []Field, opts Options) ([]Change, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	p := tea.NewProgram(newModel(fields, opts, 0, 0),
+		tea.WithOutput(w), tea.WithInput(in), tea.WithAltScreen(), tea.WithoutSignalHandler())
+	final, err := p.Run()
+	if err != nil {
+		return nil, err
+	}
+	return final.(model).saved, nil
+}
@@ -0,0 +1,120 @@
+package settings
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/endalk200/bgit/internal/ui/uitest"
+)
+
+func fields() []Field {
+	return []Field{
+		{Key: "ai_provider.name", Section: "AI provider", Help: "Provider that writes commit messages",
+			Value: "OpenAI", Origin: "global", Choices: []string{"OpenAI", "OpenRouter", "Anthropic"}},
+		{Key: "ai_provider.env_name", Section: "AI provider", Help: "Environment variable holding the provider's API key",
+			Value: "OPENAI_API_KEY", Origin: "global", SecretEnv: true},
+		{Key: "ai.max_diff_bytes", Section: "AI diff limits", Help: "Largest diff sent to the provider",
+			Value: "60000", Origin: "default"},
+		{Key: "spell.enabled", Section: "Spell-check", Help: "Spell-check commit messages before committing",
+			Value: "true", Origin: "repo", Bool: true},
+		{Key: "message.post_process", Section: "Commit messages", Help: "Steps applied to generated messages",
+			Value: "2 entries", Origin: "extends", ReadOnly: true},
+	}
+}
+
+func options(saved *[]Change) Options {
+	return Options{
+		Layers: []Layer{{Name: "global", Path: "~/.bgit.yaml"}, {Name: "repo", Path: "./.bgit.yaml"}},
+		Validate: func(key, value string) error {
+			if key == "ai.max_diff_bytes" && strings.HasPrefix(value, "-") {
+				return errors.New("cannot be negative")
+			}
+			return nil
+		},
+		Save: func(changes []Change) ([]Field, error) {
+			*saved = append(*saved, changes...)
+			return fields(), nil
+		},
+		Getenv: func(name string) string {
+			if name == "OPENAI_API_KEY" {
+				return "sk-proj-0123456789abcdef"
+			}
+			return ""
+		},
+	}
+}
+
+func TestEditAndSave(t *testing.T) {
+	var saved []Change
+	var m tea.Model = newModel(fields(), options(&saved), 80, 20)
+	uitest.AssertGolden(t, "list", m.View())
+
+	// Step the provider, then switch to the repo file and turn spell-check off.
+	m = uitest.Press(m, "enter", "tab", "down", "down", "down", "enter")
+	uitest.AssertGolden(t, "pending", m.View())
+
+	m = uitest.Press(m, "w")
+	want := []Change{
+		{Key: "ai_provider.name", Layer: "global", Value: "OpenRouter"},
+		{Key: "spell.enabled", Layer: "repo", Value: "false"},
+	}
+	if len(saved) != len(want) {
+		t.Fatalf("saved %v, want %v", saved, want)
+	}
+	for i := range want {
+		if saved[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, saved[i], want[i])
+		}
+	}
+	if got := m.(model).saved; len(got) != 2 {
+		t.Errorf("the editor reports %d saved changes, want 2", len(got))
+	}
+}
+
+func TestInlineValidation(t *testing.T) {
+	var saved []Change
+	var m tea.Model = newModel(fields(), options(&saved), 80, 20)
+	m = uitest.Press(m, "down", "down", "enter", "backspace", "backspace", "backspace", "backspace", "backspace", "-", "1")
+	uitest.AssertGolden(t, "invalid", m.View())
+
+	m = uitest.Press(m, "enter")
+	if !m.(model).editing {
+		t.Fatalf("enter accepted an invalid value")
+	}
+	m = uitest.Press(m, "esc")
+	if len(m.(model).pending) != 0 {
+		t.Errorf("esc kept the edit: %+v", m.(model).pending)
+	}
+}
+
+func TestQuitWithPendingChanges(t *testing.T) {
+	var saved []Change
+	var m tea.Model = newModel(fields(), options(&saved), 80, 20)
+	m = uitest.Press(m, "enter")
+	m, cmd := m.Update(uitest.Key("q"))
+	if cmd != nil {
+		t.Fatalf("q quit with a change not saved")
+	}
+	if !strings.Contains(uitest.StripANSI(m.View()), "1 change not saved") {
+		t.Errorf("no warning about the unsaved change")
+	}
+	if _, cmd = m.Update(uitest.Key("q")); cmd == nil {
+		t.Errorf("a second q did not quit")
+	}
+	if len(saved) != 0 {
+		t.Errorf("quitting saved %v", saved)
+	}
+}
+
+func TestMask(t *testing.T) {
+	for _, tt := range []struct{ secret, want string }{
+		{"sk-proj-0123456789abcdef", "sk-••••••••cdef"},
+		{"short", "••••••••"},
+	} {
+		if got := Mask(tt.secret); got != tt.want {
+			t.Errorf("Mask(%q) = %q, want %q", tt.secret, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"strings"
+)
+
+// SpellingIssue is a word flagged in a commit message. Offset is its byte
+// offset in the message; an empty Suggestion means the word is to be deleted.
+type SpellingIssue struct {
+	Offset     int
+	Word       string
+	Suggestion string
+	Kind       string
+}
+
+// RenderSpelling shows message with the flagged words highlighted, followed
+// by the suggested correction for each. Issues must be in message order.
+func RenderSpelling(message string, issues []SpellingIssue, width int) string {
+	var marked strings.Builder
+	at := 0
+	for _, is := range issues {
+		marked.WriteString(message[at:is.Offset])
+		marked.WriteString(typoStyle.Render(is.Word))
+		at = is.Offset + len(is.Word)
+	}
+	marked.WriteString(message[at:])
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(Icon("🔤")+"Possible typos in the commit message") + "\n\n")
+	for _, line := range strings.Split(strings.TrimRight(marked.String(), "\n"), "\n") {
+		b.WriteString(strings.TrimRight("    "+line, " ") + "\n")
+	}
+	b.WriteString("\n")
+
+	for _, is := range issues {
+		word, fix := strings.TrimSpace(is.Word), is.Suggestion
+		if fix == "" {
+			fix = "(remove)"
+		}
+		line := word + " → " + fix
+		if Plain() {
+			line = word + " -> " + fix
+		}
+		b.WriteString(HangingIndent("  "+Bullet()+" ", line+mutedStyle.Render(" ("+is.Kind+")"), width) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StashEntry is one entry of the stash, newest first.
+type StashEntry struct {
+	Ref     string    `json:"ref"`
+	Hash    string    `json:"hash"`
+	Branch  string    `json:"branch,omitempty"`
+	Message string    `json:"message"`
+	When    time.Time `json:"saved_at"`
+}
+
+// RenderStashList lists the stash with how long ago each entry was saved.
+func RenderStashList(entries []StashEntry, now time.Time, width int) string {
+	if len(entries) == 0 {
+		return "The stash is empty\n"
+	}
+	refWidth, ageWidth := 0, 0
+	ages := make([]string, len(entries))
+	for i, e := range entries {
+		ages[i] = RelativeTime(e.When, now)
+		refWidth = max(refWidth, StringWidth(e.Ref))
+		ageWidth = max(ageWidth, StringWidth(ages[i]))
+	}
+	var b strings.Builder
+	for i, e := range entries {
+		ref := e.Ref + strings.Repeat(" ", refWidth-StringWidth(e.Ref))
+		age := ages[i] + strings.Repeat(" ", ageWidth-StringWidth(ages[i]))
+		message := truncateEnd(e.Message, max(width-refWidth-ageWidth-4, 10))
+		b.WriteString(headerStyle.Render(ref) + "  " + mutedStyle.Render(age) + "  " + message + "\n")
+	}
+	return b.String()
+}
+
+// RenderStashes is the stash section of the status screen: each entry
+// with its message, shortened to fit width.
+func RenderStashes(entries []StashEntry, width int) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(headerStyle.Render("Stashes") + mutedStyle.Render(fmt.Sprintf(" (%d)", len(entries))) + "\n")
+	for _, e := range entries {
+		prefix := e.Ref + " · "
+		b.WriteString("  " + Bullet() + " " + mutedStyle.Render(prefix) + truncateEnd(e.Message, max(width-4-StringWidth(prefix), 10)) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StatusView is everything the status screen displays.
+type StatusView struct {
+	Branch string `json:"branch"`
+	// Upstream is the branch Branch tracks, like origin/main. Ahead and
+	// Behind count the commits between them as of the last fetch;
+	// UpstreamGone is set when the upstream no longer exists.
+	Upstream     string   `json:"upstream,omitempty"`
+	Ahead        int      `json:"ahead"`
+	Behind       int      `json:"behind"`
+	UpstreamGone bool     `json:"upstream_gone,omitempty"`
+	Staged       []string `json:"staged"`
+	Added        []string `json:"added"`
+	Modified     []string `json:"modified"`
+	Deleted      []string `json:"deleted"`
+	Renamed      []string `json:"renamed"`
+	Untracked    []string `json:"untracked"`
+	// Intent lists new files recorded with add -N, content not yet staged.
+	Intent []string `json:"intent_to_add"`
+	// Changelists hold the changed files put in a changelist; the screen
+	// lists them under it rather than in the sections above.
+	Changelists []Changelist `json:"changelists,omitempty"`
+	// Owners groups the staged files by their code owners, when the
+	// repository has a CODEOWNERS file.
+	Owners []OwnerArea `json:"owners,omitempty"`
+	// EmptyDirs are directories with nothing git would track in them,
+	// which 'bgit keep' makes it track.
+	EmptyDirs []string `json:"empty_dirs,omitempty"`
+	// Stashes are the entries of the stash, newest first.
+	Stashes []StashEntry `json:"stashes,omitempty"`
+}
+
+// Changelist is a named group of files, committed together with 'bgit
+// commit --changelist'.
+type Changelist struct {
+	Name  string           `json:"name"`
+	Files []ChangelistFile `json:"files"`
+}
+
+// ChangelistFile is a file in a changelist and how it has changed.
+type ChangelistFile struct {
+	Path  string `json:"path"`
+	State string `json:"state"`
+}
+
+// Clean reports whether there is nothing to show besides the branch.
+func (v StatusView) Clean() bool {
+	return len(v.Staged)+len(v.Added)+len(v.Modified)+len(v.Deleted)+len(v.Renamed)+len(v.Untracked)+len(v.Intent) == 0
+}
+
+// RenderSection renders a titled list with bullet points. Paths wider than
+// width are shortened with an ellipsis in the middle so every entry stays on
+// one line.
+func RenderSection(title string, items []string, titleStyle lipgloss.Style, width int) string {
+	if len(items) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(title))
+	b.WriteString(mutedStyle.Render(fmt.Sprintf(" (%d)", len(items))))
+	b.WriteString("\n")
+	for _, it := range items {
+		b.WriteString("  " + Bullet() + " ")
+		b.WriteString(TruncateMiddle(it, width-4))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// RenderStatus renders the full status screen. On wide terminals the index
+// and worktree sections sit side by side; narrower ones stack them. Files in
+// a changelist are listed under it, below the sections, followed by the
+// empty directories, and the stash comes last.
+func RenderStatus(v StatusView, width int) string {
+	if v.Clean() {
+		clean := "Working tree clean\n"
+		if v.Upstream != "" {
+			clean = statusHeading(v, width) + "\n\n" + clean
+		}
+		if dirs := RenderEmptyDirs(v.EmptyDirs, width); dirs != "" {
+			clean += "\n" + dirs
+		}
+		if stashes := RenderStashes(v.Stashes, width); stashes != "" {
+			clean += "\n" + stashes
+		}
+		return clean
+	}
+
+	inList := map[string]bool{}
+	for _, cl := range v.Changelists {
+		for _, f := range cl.Files {
+			inList[f.Path] = true
+		}
+	}
+	rest := func(items []string) []string {
+		var kept []string
+		for _, it := range items {
+			if !inList[it] {
+				kept = append(kept, it)
+			}
+		}
+		return kept
+	}
+	staged, added := rest(v.Staged), rest(v.Added)
+	modified, deleted, renamed := rest(v.Modified), rest(v.Deleted), rest(v.Renamed)
+	intent, untracked := rest(v.Intent), rest(v.Untracked)
+
+	var index, worktree strings.Builder
+	colWidth := ColumnWidth(width, 2)
+	if len(staged)+len(added) == 0 || len(modified)+len(deleted)+len(renamed)+len(untracked)+len(intent) == 0 {
+		colWidth = width // only one column will be shown
+	}
+
+	index.WriteString(RenderSection("Staged (index)", staged, stagedStyle, colWidth))
+	index.WriteString(RenderSection("Added (staged new files)", added, stagedStyle, colWidth))
+	worktree.WriteString(RenderSection("Modified (worktree)", modified, modifiedStyle, colWidth))
+	worktree.WriteString(RenderSection("Deleted", deleted, deletedStyle, colWidth))
+	worktree.WriteString(RenderSection("Renamed", renamed, modifiedStyle, colWidth))
+	worktree.WriteString(RenderSection("Intent to add", intent, stagedStyle, colWidth))
+	worktree.WriteString(RenderSection("Untracked", untracked, untrackedStyle, colWidth))
+
+	var b strings.Builder
+	b.WriteString(statusHeading(v, width) + "\n\n")
+	b.WriteString(Columns(width, index.String(), worktree.String()))
+	if lists := RenderChangelists(v.Changelists, width); lists != "" {
+		if index.Len()+worktree.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(lists)
+	}
+	if owners := RenderOwners(v.Owners, width); owners != "" {
+		b.WriteString("\n" + owners)
+	}
+	if dirs := RenderEmptyDirs(v.EmptyDirs, width); dirs != "" {
+		b.WriteString("\n" + dirs)
+	}
+	if stashes := RenderStashes(v.Stashes, width); stashes != "" {
+		b.WriteString("\n" + stashes)
+	}
+	return b.String()
+}
+
+// RenderEmptyDirs lists the directories git leaves out for having nothing
+// in it to track, with how to keep them.
+func RenderEmptyDirs(dirs []string, width int) string {
+	if len(dirs) == 0 {
+		return ""
+	}
+	items := make([]string, 0, len(dirs))
+	for _, d := range dirs {
+		items = append(items, d+"/")
+	}
+	return RenderSection("Empty directories", items, untrackedStyle, width) +
+		"  " + mutedStyle.Render("Track them with 'bgit keep'") + "\n"
+}
+
+// statusHeading names the branch and how it stands against its upstream,
+// which moves to a line of its own when both do not fit in width.
+func statusHeading(v StatusView, width int) string {
+	heading := "On branch " + headerStyle.Render(v.Branch)
+	tracking := renderTracking(v)
+	switch {
+	case tracking == "":
+		return heading
+	case StringWidth(heading+" · "+tracking) > width:
+		return heading + "\n  " + tracking
+	}
+	return heading + mutedStyle.Render(" · ") + tracking
+}
+
+// renderTracking is how the branch stands against its upstream.
+func renderTracking(v StatusView) string {
+	switch {
+	case v.Upstream == "":
+		return ""
+	case v.UpstreamGone:
+		return deletedStyle.Render(v.Upstream + " is gone")
+	case v.Ahead == 0 && v.Behind == 0:
+		return mutedStyle.Render("up to date with " + v.Upstream)
+	}
+	var parts []string
+	if v.Ahead > 0 {
+		parts = append(parts, stagedStyle.Render(fmt.Sprintf("%d ahead", v.Ahead)))
+	}
+	if v.Behind > 0 {
+		parts = append(parts, modifiedStyle.Render(fmt.Sprintf("%d behind", v.Behind)))
+	}
+	return strings.Join(parts, mutedStyle.Render(", ")) + mutedStyle.Render(" "+v.Upstream)
+}
+
+// RenderChangelists lists each changelist with its files and how they have
+// changed. Empty changelists are left out.
+func RenderChangelists(lists []Changelist, width int) string {
+	var b strings.Builder
+	for _, cl := range lists {
+		if len(cl.Files) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(headerStyle.Render("Changelist "+cl.Name) + mutedStyle.Render(fmt.Sprintf(" (%d)", len(cl.Files))) + "\n")
+		for _, f := range cl.Files {
+			state := " (" + f.State + ")"
+			b.WriteString("  " + Bullet() + " " + TruncateMiddle(f.Path, max(width-4-StringWidth(state), 10)) + mutedStyle.Render(state) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// RenderConflictMarkers lists the "path:line: marker" locations that blocked
+// a commit.
+func RenderConflictMarkers(items []string, width int) string {
+	return RenderSection("Conflict markers in staged files", items, deletedStyle, width)
+}
+
+// RenderSkippedPaths lists the "path (reason)" entries an add could not
+// stage.
+func RenderSkippedPaths(items []string, width int) string {
+	return RenderSection("Not staged", items, modifiedStyle, width)
+}
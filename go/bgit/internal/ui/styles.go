@@ -0,0 +1,34 @@
+// Package ui holds the lipgloss rendering layer for bgit. Commands gather
+// data from the services and hand plain view structs to the Render*
+// functions here, which keeps formatting in one place and makes every piece
+// of output snapshot-testable.
+package ui
+
+import "github.com/charmbracelet/lipgloss"
+
+// DefaultWidth is used when the terminal width is unknown (pipes, CI).
+const DefaultWidth = 80
+
+var (
+	headerStyle = lipgloss.NewStyle().Bold(true)
+	mutedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	hashStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	okStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Bold(true)
+
+	stagedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Bold(true)
+	modifiedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true)
+	deletedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
+	untrackedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Bold(true)
+
+	insertStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	deleteStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	hunkStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+
+	keywordStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
+	commentStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Italic(true)
+	stringStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("180"))
+	numberStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("141"))
+	functionStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("75"))
+
+	typoStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Underline(true)
+)
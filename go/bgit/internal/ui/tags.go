@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"strings"
+	"time"
+)
+
+// TagEntry is one tag and the commit it points at.
+type TagEntry struct {
+	Name      string `json:"name"`
+	Commit    string `json:"commit"`
+	Annotated bool   `json:"annotated"`
+	// Message is an annotated tag's message; lightweight tags have none.
+	Message string    `json:"message,omitempty"`
+	When    time.Time `json:"date"`
+}
+
+// RenderTagList lists tags with their commit, how long ago each was made,
+// and the first line of an annotated tag's message.
+func RenderTagList(entries []TagEntry, now time.Time, width int) string {
+	if len(entries) == 0 {
+		return "No tags yet.\n"
+	}
+	nameWidth, ageWidth := 0, 0
+	ages := make([]string, len(entries))
+	for i, e := range entries {
+		ages[i] = RelativeTime(e.When, now)
+		nameWidth = max(nameWidth, StringWidth(e.Name))
+		ageWidth = max(ageWidth, StringWidth(ages[i]))
+	}
+	nameWidth = min(nameWidth, max(width/3, 10))
+
+	var b strings.Builder
+	for i, e := range entries {
+		name := TruncateMiddle(e.Name, nameWidth)
+		short := e.Commit
+		if len(short) > 7 {
+			short = short[:7]
+		}
+		line := headerStyle.Render(name) + strings.Repeat(" ", nameWidth-StringWidth(name)) +
+			"  " + hashStyle.Render(short) + "  " + mutedStyle.Render(ages[i])
+		pad := strings.Repeat(" ", ageWidth-StringWidth(ages[i])) + "  "
+		room := width - nameWidth - len(short) - ageWidth - 6
+		switch {
+		case room < 10:
+		case e.Annotated:
+			subject, _, _ := strings.Cut(strings.TrimSpace(e.Message), "\n")
+			line += pad + truncateEnd(subject, room)
+		default:
+			line += pad + mutedStyle.Render("lightweight")
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
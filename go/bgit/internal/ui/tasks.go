@@ -0,0 +1,55 @@
+package ui
+
+import "strings"
+
+// TaskResult is how one pre-commit task ended.
+type TaskResult struct {
+	Name string
+	// Summary is e.g. "exit status 1 after 2.3s" or "timed out after 2m0s".
+	Summary string
+	Failed  bool
+	// Output is what a failed task printed.
+	Output string
+}
+
+// taskOutputLines is how much of a failed task's output is shown: the end,
+// where the error usually is.
+const taskOutputLines = 20
+
+// RenderTaskResults lists the tasks that ran before a commit, each with how
+// it ended, followed for failed ones by the last lines of their output.
+func RenderTaskResults(results []TaskResult, width int) string {
+	return renderResults("Pre-commit tasks failed", results, width)
+}
+
+// RenderTestResults lists the test commands 'bgit commit --verify-tests'
+// ran, in the form of RenderTaskResults.
+func RenderTestResults(results []TaskResult, width int) string {
+	return renderResults("Tests failed", results, width)
+}
+
+func renderResults(title string, results []TaskResult, width int) string {
+	var b strings.Builder
+	b.WriteString(deletedStyle.Render(title) + "\n")
+	for _, r := range results {
+		state := "success"
+		if r.Failed {
+			state = "failure"
+		}
+		mark, style := stateMark(state), stateStyle(state)
+		b.WriteString(HangingIndent("  "+style.Render(mark)+" "+r.Name+"  ", mutedStyle.Render(r.Summary), width) + "\n")
+		if !r.Failed || strings.TrimSpace(r.Output) == "" {
+			continue
+		}
+
+		lines := strings.Split(strings.TrimRight(r.Output, "\n"), "\n")
+		if len(lines) > taskOutputLines {
+			skipped := len(lines) - taskOutputLines
+			lines = append([]string{mutedStyle.Render(pluralize(skipped, "line", "lines") + " before this")}, lines[skipped:]...)
+		}
+		for _, line := range lines {
+			b.WriteString(HangingIndent("      ", line, width) + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"context"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// TerminalWidth reports the column count of the terminal behind w. It falls
+// back to $COLUMNS and then DefaultWidth when w is not a terminal, so piped
+// output stays deterministic. Wrapping writers that implement
+// Unwrap() io.Writer are looked through.
+func TerminalWidth(w io.Writer) int {
+	if f, ok := underlyingFile(w); ok && term.IsTerminal(f.Fd()) {
+		if width, _, err := term.GetSize(f.Fd()); err == nil && width > 0 {
+			return width
+		}
+	}
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return cols
+	}
+	return DefaultWidth
+}
+
+// WatchWidth sends the new width of the terminal behind w every time it is
+// resized, until ctx is cancelled. Long-running views use it to re-layout.
+// On platforms without SIGWINCH the channel only closes on cancellation.
+func WatchWidth(ctx context.Context, w io.Writer) <-chan int {
+	widths := make(chan int, 1)
+	resized := notifyResize(ctx)
+
+	go func() {
+		defer close(widths)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-resized:
+				if !ok {
+					return
+				}
+				select {
+				case widths <- TerminalWidth(w):
+				default: // a newer width will follow; never block the watcher
+				}
+			}
+		}
+	}()
+
+	return widths
+}
+
+// IsTerminal reports whether w (or the stream it wraps) is a terminal.
+func IsTerminal(w io.Writer) bool {
+	_, ok := TerminalFile(w)
+	return ok
+}
+
+// IsInteractive reports whether the user can be asked a question: both in
+// and out must be terminals.
+func IsInteractive(in io.Reader, out io.Writer) bool {
+	f, ok := in.(*os.File)
+	return ok && term.IsTerminal(f.Fd()) && IsTerminal(out)
+}
+
+// TerminalFile returns the terminal behind w, looking through wrapping
+// writers, or false when w does not lead to a terminal. Interactive views
+// draw straight to it, bypassing any buffering.
+func TerminalFile(w io.Writer) (*os.File, bool) {
+	f, ok := underlyingFile(w)
+	if !ok || !term.IsTerminal(f.Fd()) {
+		return nil, false
+	}
+	return f, true
+}
+
+// underlyingFile follows Unwrap() io.Writer links down to an *os.File.
+func underlyingFile(w io.Writer) (*os.File, bool) {
+	for {
+		switch v := w.(type) {
+		case *os.File:
+			return v, true
+		case interface{ Unwrap() io.Writer }:
+			w = v.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
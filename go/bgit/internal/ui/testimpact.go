@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"path"
+	"strings"
+)
+
+// TestSuite is a group of tests that staged files affect: a Go package, or
+// the tests a mapping in the config finds.
+type TestSuite struct {
+	Name    string   `json:"name"`
+	Tests   []string `json:"tests"`
+	Sources []string `json:"sources"`
+}
+
+// TestCommand is a command that runs some of the suites, in Dir.
+type TestCommand struct {
+	Run string `json:"run"`
+	Dir string `json:"dir"`
+}
+
+// TestPlanView is what 'bgit tests' shows: the suites to run, the source
+// files no test was found for, and the commands that run the suites.
+type TestPlanView struct {
+	Suites   []TestSuite   `json:"suites"`
+	Untested []string      `json:"untested"`
+	Commands []TestCommand `json:"commands"`
+}
+
+// RenderTestPlan lists each suite with its test files and the staged files
+// that call for it, then the files without tests and the commands to run.
+func RenderTestPlan(v TestPlanView, width int) string {
+	var b strings.Builder
+	if len(v.Suites) == 0 {
+		b.WriteString(mutedStyle.Render("No tests found for the staged files") + "\n")
+	} else {
+		b.WriteString(headerStyle.Render("Tests to run") + mutedStyle.Render(" ("+pluralize(len(v.Suites), "suite", "suites")+")") + "\n")
+	}
+	for _, s := range v.Suites {
+		b.WriteString("  " + Bullet() + " " + TruncateMiddle(s.Name, width-4) + "\n")
+		b.WriteString(HangingIndent("    ", strings.Join(baseNames(s.Tests), ", "), width) + "\n")
+		b.WriteString(HangingIndent("    ", mutedStyle.Render("for "+strings.Join(baseNames(s.Sources), ", ")), width) + "\n")
+	}
+	if len(v.Untested) > 0 {
+		b.WriteString("\n" + RenderSection("No tests found for", v.Untested, modifiedStyle, width))
+	}
+	if len(v.Commands) > 0 {
+		b.WriteString("\n" + headerStyle.Render("Run with") + "\n")
+		for _, c := range v.Commands {
+			where := ""
+			if c.Dir != "." && c.Dir != "" {
+				where = mutedStyle.Render("  in " + c.Dir)
+			}
+			b.WriteString(HangingIndent("  $ ", c.Run+where, width) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// baseNames drops the directories from paths.
+func baseNames(paths []string) []string {
+	names := make([]string, 0, len(paths))
+	for _, p := range paths {
+		names = append(names, path.Base(p))
+	}
+	return names
+}
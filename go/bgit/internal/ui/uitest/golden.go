@@ -0,0 +1,66 @@
+// Package uitest provides snapshot ("golden file") and key press helpers for
+// testing the ui rendering layer. Rendered output is normalized before
+// comparison so snapshots do not depend on the color profile of the machine
+// running the tests.
+//
+// Regenerate snapshots after an intentional rendering change with:
+//
+//	go test ./internal/ui/... -update
+package uitest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// ansiPattern matches CSI and OSC escape sequences.
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+
+// StripANSI removes terminal escape sequences from s.
+func StripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// Normalize strips escape sequences and trailing whitespace on every line,
+// and unifies line endings, leaving only what a reader would see.
+func Normalize(s string) string {
+	s = strings.ReplaceAll(StripANSI(s), "\r\n", "\n")
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// AssertGolden compares the normalized output with testdata/<name>.golden,
+// rewriting the file instead when -update is set.
+func AssertGolden(t *testing.T, name string, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	got = Normalize(got)
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("creating testdata dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output does not match %s (run with -update to accept)\n--- want ---\n%s\n--- got ---\n%s", path, want, got)
+	}
+}
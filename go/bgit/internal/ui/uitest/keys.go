@@ -0,0 +1,38 @@
+package uitest
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// keyTypes maps the names the ui key bindings use to the key they stand
+// for. Any other name is typed as runes.
+var keyTypes = map[string]tea.KeyType{
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEsc,
+	"tab":       tea.KeyTab,
+	"backspace": tea.KeyBackspace,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"ctrl+c":    tea.KeyCtrlC,
+	"ctrl+g":    tea.KeyCtrlG,
+	"ctrl+s":    tea.KeyCtrlS,
+	"ctrl+u":    tea.KeyCtrlU,
+}
+
+// Key returns the message for pressing the key named name, such as
+// "enter", "ctrl+s" or "q".
+func Key(name string) tea.KeyMsg {
+	if t, ok := keyTypes[name]; ok {
+		return tea.KeyMsg{Type: t}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}
+}
+
+// Press sends m the keys named in order, dropping the commands they
+// return, and gives back the model that results.
+func Press(m tea.Model, keys ...string) tea.Model {
+	for _, k := range keys {
+		m, _ = m.Update(Key(k))
+	}
+	return m
+}
@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rivo/uniseg"
+)
+
+// StringWidth returns the number of terminal cells s occupies. It ignores
+// escape sequences and measures grapheme clusters rather than bytes or
+// runes, so CJK characters and most emoji count as two cells and combining
+// marks count as none. All width math in this package goes through it.
+func StringWidth(s string) int {
+	return ansi.StringWidth(s)
+}
+
+// graphemes splits s into user-perceived characters with their cell widths.
+// Truncation works on these clusters so it never separates a base letter
+// from its accent or breaks an emoji sequence in half.
+func graphemes(s string) (clusters []string, widths []int) {
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		clusters = append(clusters, g.Str())
+		widths = append(widths, g.Width())
+	}
+	return clusters, widths
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"ascii", "status.go", 9},
+		{"cjk", "日本語.txt", 10},
+		{"emoji", "🚀launch.md", 11},
+		{"combining accent", "cafe\u0301.md", 7},
+		{"zwj sequence", "👩‍💻.go", 5},
+		{"variation selector", "✉️", 2},
+		{"ansi ignored", "\x1b[1mbold\x1b[0m", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StringWidth(tt.in); got != tt.want {
+				t.Errorf("StringWidth(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateMiddleWideCharacters(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		width int
+		want  string
+	}{
+		// A wide character that would straddle the limit is dropped, not split.
+		{"cjk", "文档/日本語のファイル名.md", 12, "文档/…名.md"},
+		{"combining kept with base", "re\u0301sume\u0301-re\u0301sume\u0301", 7, "re\u0301s…ume\u0301"},
+		{"emoji sequence kept whole", "👩‍💻👩‍💻👩‍💻👩‍💻", 5, "👩‍💻…👩‍💻"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateMiddle(tt.in, tt.width)
+			if got != tt.want {
+				t.Errorf("TruncateMiddle(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+			}
+			if w := StringWidth(got); w > tt.width {
+				t.Errorf("result %q is %d cells wide, limit %d", got, w, tt.width)
+			}
+		})
+	}
+}
+
+func TestRenderSectionAlignsWideNames(t *testing.T) {
+	items := []string{"readme.md", "日本語.md", "🚀.md", "café.md"}
+	out := RenderSection("Untracked", items, untrackedStyle, 40)
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n")[1:] {
+		if !strings.HasPrefix(line, "  • ") {
+			t.Errorf("bullet misaligned: %q", line)
+		}
+	}
+}
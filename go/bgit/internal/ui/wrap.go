@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// HangingIndent renders prefix followed by text, word-wrapping text so no
+// line exceeds width and aligning continuation lines under the first
+// character of text. Unlike lipgloss' Width, it never pads lines with
+// trailing spaces.
+func HangingIndent(prefix, text string, width int) string {
+	indent := StringWidth(prefix)
+	avail := max(width-indent, 1)
+
+	lines := strings.Split(ansi.Wrap(text, avail, ""), "\n")
+	pad := strings.Repeat(" ", indent)
+	for i := range lines {
+		if i == 0 {
+			lines[i] = prefix + lines[i]
+		} else {
+			lines[i] = pad + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
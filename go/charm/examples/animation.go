@@ -0,0 +1,379 @@
+package examples
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/harmonica"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/endalk200/charm.poc/theme"
+)
+
+// All animations here follow the same pattern: a frame tick re-arms itself
+// every 1/fps seconds, and each frame advances the physics (harmonica) by
+// exactly that much time. Nothing is animated with sleeps or goroutines.
+//
+// In accessible mode motion is reduced: values jump to where the physics
+// would settle instead of easing there, and the bouncing ball is drawn as
+// one still picture of its path.
+const fps = 60
+
+// frameMsg is delivered once per animation frame
+type frameMsg time.Time
+
+func animationFrame() tea.Cmd {
+	return tea.Tick(time.Second/fps, func(t time.Time) tea.Msg {
+		return frameMsg(t)
+	})
+}
+
+var animationHelp = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+// ==============================================================================
+// EASY EXAMPLES - A spring chasing a target
+// ==============================================================================
+
+// springProgressModel draws a bar whose fill springs towards the real
+// progress instead of jumping to it
+type springProgressModel struct {
+	spring   harmonica.Spring
+	target   float64 // the real progress, 0..1, which moves in jumps
+	fill     float64 // what is drawn; chases target
+	velocity float64
+	steps    int
+	settled  int // consecutive frames at rest after finishing
+}
+
+func newSpringProgressModel() springProgressModel {
+	// Angular frequency sets the speed, the damping ratio the bounce:
+	// below 1 overshoots a little, 1 stops exactly, above 1 creeps in
+	return springProgressModel{spring: harmonica.NewSpring(harmonica.FPS(fps), 6.0, 0.6)}
+}
+
+// progressStepMsg simulates a chunk of work finishing
+type progressStepMsg struct{}
+
+func progressStep() tea.Cmd {
+	return tea.Tick(700*time.Millisecond, func(time.Time) tea.Msg { return progressStepMsg{} })
+}
+
+func (m springProgressModel) Init() tea.Cmd {
+	return tea.Batch(animationFrame(), progressStep())
+}
+
+func (m springProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "q" || msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+	case progressStepMsg:
+		// Work progresses in uneven jumps, like real downloads do
+		m.steps++
+		m.target = math.Min(1, m.target+[]float64{0.1, 0.25, 0.05, 0.3, 0.15, 0.35}[m.steps%6])
+		if m.target < 1 {
+			return m, progressStep()
+		}
+
+	case frameMsg:
+		if theme.Accessible() {
+			m.fill, m.velocity = m.target, 0
+		} else {
+			m.fill, m.velocity = m.spring.Update(m.fill, m.velocity, m.target)
+		}
+
+		// Stop once the bar has reached 100% and come to rest
+		if m.target == 1 && math.Abs(m.fill-1) < 0.001 && math.Abs(m.velocity) < 0.01 {
+			m.settled++
+			if m.settled > fps/4 {
+				return m, tea.Quit
+			}
+		}
+		return m, animationFrame()
+	}
+	return m, nil
+}
+
+func (m springProgressModel) View() string {
+	const width = 50
+	// The spring may overshoot slightly; never draw past the ends
+	shown := math.Max(0, math.Min(1, m.fill))
+	filled := int(math.Round(shown * width))
+
+	bar := lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Render(strings.Repeat(theme.Symbol("█", "#"), filled)) +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("238")).Render(strings.Repeat(theme.Symbol("░", "-"), width-filled))
+
+	// The marker shows where the real progress is; the bar catches up
+	marker := strings.Repeat(" ", int(m.target*width)) + theme.Symbol("▲", "^") + " actual"
+
+	return fmt.Sprintf("\n%s %3.0f%%\n%s\n\n%s\n", bar, shown*100, marker,
+		animationHelp.Render("the bar springs towards the real progress • q quit"))
+}
+
+// SpringProgressExample demonstrates a progress bar animated with a spring
+// Concept: harmonica.Spring and a fixed-rate frame tick
+func SpringProgressExample() {
+	fmt.Println("\n=== EASY: Spring-Animated Progress Bar ===")
+
+	if _, err := tea.NewProgram(newSpringProgressModel()).Run(); err != nil {
+		fmt.Println("Error:", err)
+	}
+}
+
+// ==============================================================================
+// MEDIUM EXAMPLES - Projectile physics
+// ==============================================================================
+
+const (
+	boxWidth  = 50
+	boxHeight = 12
+)
+
+// bounceModel drops a ball into a box. Between bounces it is a
+// harmonica.Projectile under gravity; at each wall a new projectile is
+// launched from the contact point with the velocity reflected and damped.
+type bounceModel struct {
+	ball    *harmonica.Projectile
+	bounces int
+	frames  int
+}
+
+// gravity is in cells per second squared. Terminal cells are about twice
+// as tall as wide, so y (rows) counts double compared to x (columns).
+var gravity = harmonica.Vector{Y: 40}
+
+func newBounceModel() bounceModel {
+	return bounceModel{
+		ball: harmonica.NewProjectile(harmonica.FPS(fps),
+			harmonica.Point{X: 2, Y: 0},
+			harmonica.Vector{X: 22, Y: 0},
+			gravity),
+	}
+}
+
+func (m bounceModel) Init() tea.Cmd { return animationFrame() }
+
+func (m bounceModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "q" || msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+	case frameMsg:
+		if m.step() {
+			return m, tea.Quit
+		}
+		return m, animationFrame()
+	}
+	return m, nil
+}
+
+// step advances the ball by one frame and reports whether it is done
+func (m *bounceModel) step() bool {
+	m.frames++
+	pos := m.ball.Update()
+	vel := m.ball.Velocity()
+
+	// Reflect off the floor and the side walls, losing some energy
+	hit := false
+	if pos.Y >= boxHeight-1 && vel.Y > 0 {
+		pos.Y, vel.Y, hit = boxHeight-1, -vel.Y*0.8, true
+	}
+	if (pos.X <= 0 && vel.X < 0) || (pos.X >= boxWidth-1 && vel.X > 0) {
+		pos.X, vel.X, hit = math.Max(0, math.Min(boxWidth-1, pos.X)), -vel.X*0.9, true
+	}
+	if hit {
+		m.bounces++
+		m.ball = harmonica.NewProjectile(harmonica.FPS(fps), pos, vel, gravity)
+	}
+
+	// Stop when the ball has nearly come to rest, or after 10 seconds
+	return (math.Abs(vel.Y) < 2 && pos.Y >= boxHeight-1.5) || m.frames > 10*fps
+}
+
+// cell is the box cell the ball is drawn in
+func (m bounceModel) cell() [2]int {
+	pos := m.ball.Position()
+	return [2]int{
+		int(math.Round(math.Max(0, math.Min(boxWidth-1, pos.X)))),
+		int(math.Round(math.Max(0, math.Min(boxHeight-1, pos.Y)))),
+	}
+}
+
+// drawBox draws the box with marks at the given {x, y} cells
+func drawBox(marks map[[2]int]string) string {
+	rows := make([]string, boxHeight)
+	for y := range rows {
+		var b strings.Builder
+		for x := range boxWidth {
+			if mark, ok := marks[[2]int{x, y}]; ok {
+				b.WriteString(mark)
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		rows[y] = b.String()
+	}
+	return lipgloss.NewStyle().Border(theme.Border(lipgloss.RoundedBorder())).BorderForeground(lipgloss.Color("63")).
+		Foreground(lipgloss.Color("212")).Render(strings.Join(rows, "\n"))
+}
+
+func (m bounceModel) View() string {
+	box := drawBox(map[[2]int]string{m.cell(): "●"})
+	return "\n" + box + "\n" + animationHelp.Render(fmt.Sprintf("bounces: %d • q quit", m.bounces)) + "\n"
+}
+
+// bounceTrace runs the whole simulation up front and draws every cell the
+// ball passed through in one still picture
+func bounceTrace() string {
+	m := newBounceModel()
+	marks := map[[2]int]string{}
+	for !m.step() {
+		marks[m.cell()] = "."
+	}
+	marks[m.cell()] = "o"
+	return drawBox(marks) + "\n" +
+		fmt.Sprintf("The ball's path: %d bounces in %.1fs, coming to rest at the o.", m.bounces, float64(m.frames)/fps)
+}
+
+// BouncingBallExample demonstrates projectile motion with bounces
+// Concept: harmonica.Projectile, gravity and re-launching on collision
+func BouncingBallExample() {
+	fmt.Println("\n=== MEDIUM: Bouncing Ball ===")
+
+	if theme.Accessible() {
+		fmt.Println(bounceTrace())
+		return
+	}
+
+	if _, err := tea.NewProgram(newBounceModel()).Run(); err != nil {
+		fmt.Println("Error:", err)
+	}
+}
+
+// ==============================================================================
+// HARD EXAMPLES - Animating layout
+// ==============================================================================
+
+// splitModel animates the split between two panes. The split ratio is a
+// spring position, so every layout change eases in instead of snapping.
+type splitModel struct {
+	spring   harmonica.Spring
+	ratio    float64 // current share of the left pane, 0..1
+	velocity float64
+	targets  []float64 // layouts to cycle through
+	current  int
+}
+
+// splitToggleMsg moves to the next layout
+type splitToggleMsg struct{}
+
+func splitToggle() tea.Cmd {
+	return tea.Tick(1500*time.Millisecond, func(time.Time) tea.Msg { return splitToggleMsg{} })
+}
+
+func newSplitModel() splitModel {
+	return splitModel{
+		// Critically damped: fast, and no overshoot (which would look like
+		// the panes wobbling)
+		spring:  harmonica.NewSpring(harmonica.FPS(fps), 8.0, 1.0),
+		ratio:   0.5,
+		targets: []float64{0.5, 0.75, 0.25, 0.9, 0.5},
+	}
+}
+
+func (m splitModel) Init() tea.Cmd { return tea.Batch(animationFrame(), splitToggle()) }
+
+func (m splitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case " ":
+			// Jump ahead manually; the spring takes care of the motion
+			m.current = (m.current + 1) % len(m.targets)
+		}
+
+	case splitToggleMsg:
+		m.current++
+		if m.current >= len(m.targets) {
+			return m, tea.Quit
+		}
+		return m, splitToggle()
+
+	case frameMsg:
+		target := m.targets[min(m.current, len(m.targets)-1)]
+		if theme.Accessible() {
+			m.ratio, m.velocity = target, 0
+		} else {
+			m.ratio, m.velocity = m.spring.Update(m.ratio, m.velocity, target)
+		}
+		return m, animationFrame()
+	}
+	return m, nil
+}
+
+func (m splitModel) View() string {
+	const total, height = 60, 6
+
+	// Each pane has a 2-column border; keep both at least 4 columns wide
+	left := int(math.Round(m.ratio * total))
+	left = max(6, min(total-6, left))
+	right := total - left
+
+	pane := func(title string, w int, color string) string {
+		return lipgloss.NewStyle().
+			Border(theme.Border(lipgloss.RoundedBorder())).
+			BorderForeground(lipgloss.Color(color)).
+			Width(w - 2).Height(height).
+			Render(lipgloss.NewStyle().MaxWidth(w - 2).Render(title))
+	}
+
+	return "\n" + lipgloss.JoinHorizontal(lipgloss.Top,
+		pane(fmt.Sprintf("Files %d%%", int(m.ratio*100)), left, "63"),
+		pane("Preview", right, "205"),
+	) + "\n" + animationHelp.Render("layout changes every 1.5s • space next • q quit") + "\n"
+}
+
+// SmoothPaneResizeExample demonstrates animating a layout change
+// Concept: Driving layout values (not just visuals) with a spring
+func SmoothPaneResizeExample() {
+	fmt.Println("\n=== HARD: Smooth Pane Resizing ===")
+
+	if _, err := tea.NewProgram(newSplitModel()).Run(); err != nil {
+		fmt.Println("Error:", err)
+	}
+}
+
+// Register the animation examples with the launcher registry
+func init() {
+	Register(
+		Example{Name: "SpringProgressExample", Package: "Animation", Difficulty: Easy, Description: "Progress bar that springs towards the real value", Run: SpringProgressExample},
+		Example{Name: "BouncingBallExample", Package: "Animation", Difficulty: Medium, Description: "Projectile physics with bounces", Run: BouncingBallExample},
+		Example{Name: "SmoothPaneResizeExample", Package: "Animation", Difficulty: Hard, Description: "Two panes whose split eases between layouts", Run: SmoothPaneResizeExample},
+	)
+}
+
+// RunAllAnimationExamples executes all animation examples
+func RunAllAnimationExamples() {
+	fmt.Println("\n" + strings.Repeat("=", 70))
+	fmt.Println("ANIMATION EXAMPLES - Springs, Physics and Frame Ticks")
+	fmt.Println(strings.Repeat("=", 70))
+
+	// Easy examples
+	SpringProgressExample()
+
+	// Medium examples
+	BouncingBallExample()
+
+	// Hard examples
+	SmoothPaneResizeExample()
+
+	fmt.Println("\n" + strings.Repeat("=", 70))
+}
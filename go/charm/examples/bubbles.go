@@ -0,0 +1,375 @@
+package examples
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/filepicker"
+	"github.com/charmbracelet/bubbles/paginator"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/endalk200/charm.poc/theme"
+)
+
+// ==============================================================================
+// MEDIUM EXAMPLES - A live gallery of the bubbles components
+// ==============================================================================
+
+// galleryTab is one page of the gallery: the component name and the
+// minimal code needed to use it, shown under the live component
+type galleryTab struct {
+	name    string
+	snippet string
+}
+
+var galleryTabs = []galleryTab{
+	{"Spinner", `s := spinner.New()
+s.Spinner = spinner.Dot
+
+// Init: start the animation
+return s.Tick
+
+// Update: forward spinner.TickMsg
+s, cmd = s.Update(msg)
+
+// View
+s.View() + " Loading..."`},
+	{"Progress", `p := progress.New(progress.WithDefaultGradient())
+
+// Update: animate towards a new value...
+cmd := p.SetPercent(0.75)
+
+// ...and forward the animation frames
+case progress.FrameMsg:
+    m, cmd := p.Update(msg)
+    p = m.(progress.Model)
+
+// View
+p.View()`},
+	{"Text Input", `ti := textinput.New()
+ti.Placeholder = "Your name"
+ti.CharLimit = 32
+ti.Focus()
+
+// Init: make the cursor blink
+return textinput.Blink
+
+// Update / View
+ti, cmd = ti.Update(msg)
+ti.View()
+ti.Value() // the current text`},
+	{"Table", `t := table.New(
+    table.WithColumns([]table.Column{
+        {Title: "Package", Width: 12},
+        {Title: "Purpose", Width: 30},
+    }),
+    table.WithRows([]table.Row{
+        {"bubbletea", "The TUI framework"},
+    }),
+    table.WithFocused(true),
+    table.WithHeight(6),
+)
+
+t, cmd = t.Update(msg) // ↑/↓ move the cursor
+t.SelectedRow()`},
+	{"Paginator", `p := paginator.New()
+p.Type = paginator.Dots
+p.PerPage = 5
+p.SetTotalPages(len(items))
+
+// Update: ←/→ change page
+p, cmd = p.Update(msg)
+
+// View: slice the current page
+start, end := p.GetSliceBounds(len(items))
+items[start:end]
+p.View()`},
+	{"File Picker", `fp := filepicker.New()
+fp.CurrentDirectory, _ = os.Getwd()
+fp.AllowedTypes = []string{".go", ".md"}
+
+// Init: read the directory
+return fp.Init()
+
+// Update: forward every message, then check
+fp, cmd = fp.Update(msg)
+if ok, path := fp.DidSelectFile(msg); ok {
+    selected = path
+}`},
+}
+
+// progressTickMsg advances the demo progress bar
+type progressTickMsg time.Time
+
+// galleryModel embeds one instance of every showcased component and
+// routes key presses only to the one on the active tab
+type galleryModel struct {
+	active int
+	width  int
+
+	spinner     spinner.Model
+	spinnerKind int
+	progress    progress.Model
+	percent     float64
+	input       textinput.Model
+	table       table.Model
+	paginator   paginator.Model
+	pageItems   []string
+	picker      filepicker.Model
+	picked      string
+}
+
+var spinnerKinds = []struct {
+	name    string
+	spinner spinner.Spinner
+}{
+	{"Dot", spinner.Dot},
+	{"Line", spinner.Line},
+	{"MiniDot", spinner.MiniDot},
+	{"Jump", spinner.Jump},
+	{"Pulse", spinner.Pulse},
+	{"Points", spinner.Points},
+	{"Globe", spinner.Globe},
+	{"Moon", spinner.Moon},
+	{"Monkey", spinner.Monkey},
+}
+
+func newGalleryModel() galleryModel {
+	s := spinner.New()
+	s.Spinner = spinnerKinds[0].spinner
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+
+	ti := textinput.New()
+	ti.Placeholder = "Your name"
+	ti.CharLimit = 32
+	ti.Width = 30
+	ti.Focus()
+
+	t := table.New(
+		table.WithColumns([]table.Column{
+			{Title: "Package", Width: 12},
+			{Title: "Purpose", Width: 34},
+		}),
+		table.WithRows([]table.Row{
+			{"bubbletea", "The Elm-style TUI framework"},
+			{"bubbles", "Reusable components (this gallery)"},
+			{"lipgloss", "Styles and layout"},
+			{"huh", "Forms and prompts"},
+			{"log", "Structured, colorful logging"},
+			{"glamour", "Markdown rendering"},
+			{"harmonica", "Spring animations"},
+			{"wish", "SSH apps"},
+		}),
+		table.WithFocused(true),
+		table.WithHeight(6),
+	)
+
+	var items []string
+	for i := 1; i <= 23; i++ {
+		items = append(items, fmt.Sprintf("Item %02d", i))
+	}
+	p := paginator.New()
+	p.Type = paginator.Dots
+	p.PerPage = 5
+	// The dots differ only in color, which is lost without it; give them
+	// different shapes then
+	active, inactive := "•", "•"
+	if theme.NoColor() || theme.Accessible() {
+		active, inactive = theme.Symbol("●", "*"), theme.Symbol("○", ".")
+	}
+	p.ActiveDot = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Render(active)
+	p.InactiveDot = lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(inactive)
+	p.SetTotalPages(len(items))
+
+	fp := filepicker.New()
+	fp.CurrentDirectory, _ = os.Getwd()
+	fp.AllowedTypes = []string{".go", ".md", ".mod"}
+	fp.SetHeight(8)
+
+	bar := progress.New(progress.WithDefaultGradient(), progress.WithWidth(40))
+	if theme.Accessible() {
+		bar = progress.New(progress.WithWidth(40), progress.WithFillCharacters('#', '-'))
+	}
+
+	return galleryModel{
+		spinner:   s,
+		progress:  bar,
+		input:     ti,
+		table:     t,
+		paginator: p,
+		pageItems: items,
+		picker:    fp,
+	}
+}
+
+func progressTick() tea.Cmd {
+	return tea.Tick(400*time.Millisecond, func(t time.Time) tea.Msg {
+		return progressTickMsg(t)
+	})
+}
+
+// Init starts everything that animates or loads in the background at once,
+// so switching tabs never shows a component in its zero state
+func (m galleryModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, progressTick(), textinput.Blink, m.picker.Init())
+}
+
+func (m galleryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.progress.Width = min(60, max(10, msg.Width-8))
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m, tea.Quit
+		case "tab":
+			m.active = (m.active + 1) % len(galleryTabs)
+			return m, nil
+		case "shift+tab":
+			m.active = (m.active + len(galleryTabs) - 1) % len(galleryTabs)
+			return m, nil
+		}
+		return m.updateActive(msg)
+
+	case progressTickMsg:
+		m.percent += 0.07
+		if m.percent > 1 {
+			m.percent = 0
+		}
+		return m, tea.Batch(m.progress.SetPercent(m.percent), progressTick())
+
+	case progress.FrameMsg:
+		pm, cmd := m.progress.Update(msg)
+		m.progress = pm.(progress.Model)
+		return m, cmd
+	}
+
+	// Everything else (spinner ticks, cursor blinks, directory listings)
+	// is addressed to a specific component by ID, so it is safe to forward
+	// to all of them regardless of the active tab
+	var cmds [3]tea.Cmd
+	m.spinner, cmds[0] = m.spinner.Update(msg)
+	m.input, cmds[1] = m.input.Update(msg)
+	m.picker, cmds[2] = m.picker.Update(msg)
+	return m, tea.Batch(cmds[:]...)
+}
+
+// updateActive sends a key press to the component on the current tab
+func (m galleryModel) updateActive(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch galleryTabs[m.active].name {
+	case "Spinner":
+		if msg.String() == " " {
+			m.spinnerKind = (m.spinnerKind + 1) % len(spinnerKinds)
+			m.spinner.Spinner = spinnerKinds[m.spinnerKind].spinner
+		}
+	case "Text Input":
+		m.input, cmd = m.input.Update(msg)
+	case "Table":
+		m.table, cmd = m.table.Update(msg)
+	case "Paginator":
+		m.paginator, cmd = m.paginator.Update(msg)
+	case "File Picker":
+		m.picker, cmd = m.picker.Update(msg)
+		if ok, path := m.picker.DidSelectFile(msg); ok {
+			m.picked = path
+		}
+	}
+	return m, cmd
+}
+
+var (
+	activeTabStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("230")).Background(lipgloss.Color("63")).Padding(0, 1)
+	inactiveTabStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Padding(0, 1)
+	demoBoxStyle     = lipgloss.NewStyle().BorderForeground(lipgloss.Color("63")).Padding(1, 2)
+	snippetStyle     = lipgloss.NewStyle().BorderForeground(lipgloss.Color("240")).Foreground(lipgloss.Color("250")).Padding(0, 1)
+	galleryHelp      = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+// demo renders the live component for the active tab plus a one-line hint
+func (m galleryModel) demo() (body, hint string) {
+	switch galleryTabs[m.active].name {
+	case "Spinner":
+		return fmt.Sprintf("%s Loading... (%s)", m.spinner.View(), spinnerKinds[m.spinnerKind].name),
+			"space next spinner style"
+	case "Progress":
+		return m.progress.View(), "animates on its own"
+	case "Text Input":
+		body = m.input.View()
+		if v := strings.TrimSpace(m.input.Value()); v != "" {
+			body += "\n\nHello, " + v + "!"
+		}
+		return body, "type to edit"
+	case "Table":
+		row := m.table.SelectedRow()
+		return m.table.View() + "\n\nSelected: " + row[0], "↑/↓ move"
+	case "Paginator":
+		start, end := m.paginator.GetSliceBounds(len(m.pageItems))
+		return strings.Join(m.pageItems[start:end], "\n") + "\n\n" + m.paginator.View(), "←/→ change page"
+	case "File Picker":
+		body = m.picker.View()
+		if m.picked != "" {
+			body += "\nSelected: " + m.picked
+		}
+		return body, "↑/↓ move • enter open/select • backspace up"
+	}
+	return "", ""
+}
+
+func (m galleryModel) View() string {
+	var tabs []string
+	for i, t := range galleryTabs {
+		style, name := inactiveTabStyle, t.name
+		if i == m.active {
+			// Without colors the active tab is only bold; mark it in text too
+			style, name = activeTabStyle, theme.Symbol(name, "["+name+"]")
+		}
+		tabs = append(tabs, style.Render(name))
+	}
+
+	body, hint := m.demo()
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, tabs...),
+		demoBoxStyle.Border(theme.Border(lipgloss.RoundedBorder())).Render(body),
+		snippetStyle.Border(theme.Border(lipgloss.NormalBorder())).Render(galleryTabs[m.active].snippet),
+		galleryHelp.Render("tab/shift+tab switch component • "+hint+" • esc quit"),
+	)
+}
+
+// BubblesGalleryExample demonstrates the bubbles components side by side
+// Concept: Embedding several components and routing messages between them
+func BubblesGalleryExample() {
+	fmt.Println("\n=== MEDIUM: Bubbles Component Gallery ===")
+
+	if _, err := tea.NewProgram(newGalleryModel(), tea.WithAltScreen()).Run(); err != nil {
+		fmt.Println("Error:", err)
+	}
+}
+
+// Register the bubbles examples with the launcher registry
+func init() {
+	Register(
+		Example{Name: "BubblesGalleryExample", Package: "Bubbles", Difficulty: Medium, Description: "Spinner, progress, text input, table, paginator and file picker with source", Run: BubblesGalleryExample, Interactive: true},
+	)
+}
+
+// RunAllBubblesExamples executes all bubbles examples
+func RunAllBubblesExamples() {
+	fmt.Println("\n" + strings.Repeat("=", 70))
+	fmt.Println("BUBBLES EXAMPLES - Reusable Bubble Tea Components")
+	fmt.Println(strings.Repeat("=", 70))
+
+	// Medium examples
+	BubblesGalleryExample()
+
+	fmt.Println("\n" + strings.Repeat("=", 70))
+}
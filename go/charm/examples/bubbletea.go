@@ -0,0 +1,400 @@
+package examples
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/endalk200/charm.poc/theme"
+)
+
+// ==============================================================================
+// EASY EXAMPLES - The Elm architecture: Model, Update, View
+// ==============================================================================
+
+// counterModel holds all the state of the counter program
+// In Bubble Tea the model is a plain value; Update returns a new copy
+type counterModel struct {
+	count int
+}
+
+// Init runs once at startup and may return a command (nil = nothing to do)
+func (m counterModel) Init() tea.Cmd { return nil }
+
+// Update receives every event (key presses, window resizes, timers...)
+// and returns the next model plus an optional command to run
+func (m counterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "+", "up", "k":
+			m.count++
+		case "-", "down", "j":
+			m.count--
+		case "r":
+			m.count = 0
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// View renders the model as a string; Bubble Tea redraws it after each Update
+func (m counterModel) View() string {
+	countStyle := lipgloss.NewStyle().
+		Bold(true).
+		Padding(0, 2).
+		Border(theme.Border(lipgloss.RoundedBorder())).
+		BorderForeground(lipgloss.Color("63"))
+
+	switch {
+	case m.count > 0:
+		countStyle = countStyle.Foreground(lipgloss.Color("46")) // Green
+	case m.count < 0:
+		countStyle = countStyle.Foreground(lipgloss.Color("196")) // Red
+	}
+
+	help := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).
+		Render("+/↑ increment • -/↓ decrement • r reset • q quit")
+
+	return "\n" + countStyle.Render(fmt.Sprintf("Count: %d", m.count)) + "\n\n" + help + "\n"
+}
+
+// CounterExample demonstrates the smallest useful Bubble Tea program
+// Concept: The Model / Update / View loop and handling key messages
+func CounterExample() {
+	fmt.Println("\n=== EASY: Counter (Model, Update, View) ===")
+
+	// NewProgram takes the initial model; Run blocks until tea.Quit
+	final, err := tea.NewProgram(counterModel{}).Run()
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Run returns the final model, so the program's state is available after it exits
+	fmt.Printf("Final count: %d\n", final.(counterModel).count)
+}
+
+// ==============================================================================
+// MEDIUM EXAMPLES - Composing bubbles components
+// ==============================================================================
+
+// topic is an item in the list; it must implement list.Item (FilterValue)
+// and list.DefaultItem (Title, Description) to use the default delegate
+type topic struct {
+	title, summary, body string
+}
+
+func (t topic) Title() string       { return t.title }
+func (t topic) Description() string { return t.summary }
+func (t topic) FilterValue() string { return t.title }
+
+// browserModel composes two components: a list on the left and a
+// viewport (scrollable text area) on the right showing the selected topic
+type browserModel struct {
+	list     list.Model
+	viewport viewport.Model
+	focusOnV bool // which pane receives key presses
+	ready    bool
+}
+
+// paneStyle frames a pane; the focused one has a brighter border, and a
+// double one when there are no colors to tell them apart
+func paneStyle(focused bool) lipgloss.Style {
+	border, color := lipgloss.RoundedBorder(), lipgloss.Color("240")
+	if focused {
+		color = lipgloss.Color("205")
+		if theme.NoColor() || theme.Accessible() {
+			border = lipgloss.DoubleBorder()
+		}
+	}
+	return lipgloss.NewStyle().Border(theme.Border(border)).BorderForeground(color)
+}
+
+func newBrowserModel() browserModel {
+	topics := []list.Item{
+		topic{"Model", "Your application state", "The model is any Go value holding the state of your program.\n\nBecause Update returns a new model instead of mutating a shared one, state changes are explicit and easy to follow. Keep it a plain struct: counters, cursors, loaded data, and the sub-models of any components you embed."},
+		topic{"Update", "Reacting to messages", "Update is called with every message: key presses, mouse events, window size changes, results of commands.\n\nIt returns the next model and optionally a tea.Cmd, a function that performs I/O and returns another message. This is how timers, HTTP calls and file reads fit into the loop without blocking the UI."},
+		topic{"View", "Rendering state", "View turns the model into a string. It should be a pure function of the model: no I/O, no mutation.\n\nBubble Tea diffs the output and redraws only what changed, so it is fine to rebuild the whole screen every time."},
+		topic{"Commands", "Side effects", "A tea.Cmd is func() tea.Msg. Bubble Tea runs it in a goroutine and feeds the returned message back into Update.\n\ntea.Batch runs several commands concurrently; tea.Sequence runs them one after another. tea.Tick and tea.Every schedule messages in the future."},
+		topic{"Components", "Reusable bubbles", "The bubbles package provides ready-made components: list, viewport, textinput, spinner, progress, table, paginator and more.\n\nEach is itself a model with an Update and a View. You embed them in your model and forward messages to them, exactly as this example does with a list and a viewport."},
+	}
+
+	l := list.New(topics, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Bubble Tea Concepts"
+	l.SetShowHelp(false)
+
+	return browserModel{list: l}
+}
+
+func (m browserModel) Init() tea.Cmd { return nil }
+
+// syncViewport copies the selected topic into the viewport
+func (m *browserModel) syncViewport() {
+	if t, ok := m.list.SelectedItem().(topic); ok {
+		m.viewport.SetContent(lipgloss.NewStyle().Width(m.viewport.Width).Render(t.body))
+		m.viewport.GotoTop()
+	}
+}
+
+func (m browserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Split the window: 40% list, 60% viewport (minus borders)
+		listWidth := msg.Width * 2 / 5
+		height := msg.Height - 4
+		m.list.SetSize(listWidth-2, height)
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width-listWidth-4, height)
+			m.ready = true
+		} else {
+			m.viewport.Width = msg.Width - listWidth - 4
+			m.viewport.Height = height
+		}
+		m.syncViewport()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "tab":
+			m.focusOnV = !m.focusOnV
+			return m, nil
+		}
+	}
+
+	// Forward the message only to the focused component
+	var cmd tea.Cmd
+	if m.focusOnV {
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+
+	before := m.list.Index()
+	m.list, cmd = m.list.Update(msg)
+	if m.list.Index() != before {
+		m.syncViewport()
+	}
+	return m, cmd
+}
+
+func (m browserModel) View() string {
+	if !m.ready {
+		return "Loading..."
+	}
+
+	listStyle, viewStyle := paneStyle(!m.focusOnV), paneStyle(m.focusOnV)
+
+	panes := lipgloss.JoinHorizontal(lipgloss.Top,
+		listStyle.Render(m.list.View()),
+		viewStyle.Render(m.viewport.View()),
+	)
+	help := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).
+		Render(fmt.Sprintf("tab switch pane • ↑/↓ move/scroll • q quit • %3.f%%", m.viewport.ScrollPercent()*100))
+
+	return panes + "\n" + help
+}
+
+// ListViewportExample demonstrates composing a list and a viewport
+// Concept: Embedding components, routing messages by focus, resizing
+func ListViewportExample() {
+	fmt.Println("\n=== MEDIUM: List + Viewport Composition ===")
+
+	// WithAltScreen uses the full terminal and restores it on exit
+	if _, err := tea.NewProgram(newBrowserModel(), tea.WithAltScreen()).Run(); err != nil {
+		fmt.Println("Error:", err)
+	}
+}
+
+// ==============================================================================
+// HARD EXAMPLES - Timers, concurrency and multi-pane layouts
+// ==============================================================================
+
+// tickMsg is delivered by tea.Tick on every dashboard refresh
+type tickMsg time.Time
+
+// tick schedules the next refresh; returning it again from Update keeps
+// the timer going, which is the idiomatic way to build a render loop
+func tick() tea.Cmd {
+	return tea.Tick(500*time.Millisecond, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// dashboardModel simulates a live monitoring dashboard with four panes
+type dashboardModel struct {
+	width, height int
+	focused       int // index of the focused pane
+	paused        bool
+	cpu           []float64 // recent CPU samples for the sparkline
+	memory        float64
+	requests      int
+	events        []string
+	started       time.Time
+	now           time.Time
+}
+
+const dashboardPanes = 4
+
+func newDashboardModel() dashboardModel {
+	return dashboardModel{started: time.Now(), now: time.Now(), memory: 40}
+}
+
+func (m dashboardModel) Init() tea.Cmd { return tick() }
+
+func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "tab":
+			m.focused = (m.focused + 1) % dashboardPanes
+		case "shift+tab":
+			m.focused = (m.focused + dashboardPanes - 1) % dashboardPanes
+		case " ":
+			m.paused = !m.paused
+		}
+
+	case tickMsg:
+		m.now = time.Time(msg)
+		if !m.paused {
+			m.sample()
+		}
+		return m, tick()
+	}
+	return m, nil
+}
+
+// sample generates the next round of fake metrics
+func (m *dashboardModel) sample() {
+	m.cpu = append(m.cpu, 20+rand.Float64()*60)
+	if len(m.cpu) > 40 {
+		m.cpu = m.cpu[1:]
+	}
+
+	m.memory = max(10, min(95, m.memory+rand.Float64()*6-3))
+	m.requests += rand.Intn(25)
+
+	if rand.Intn(3) == 0 {
+		kinds := []string{"INFO user signed in", "INFO cache refreshed", "WARN slow query", "ERROR upstream timeout", "INFO job finished"}
+		m.events = append(m.events, m.now.Format("15:04:05")+" "+kinds[rand.Intn(len(kinds))])
+		if len(m.events) > 50 {
+			m.events = m.events[1:]
+		}
+	}
+}
+
+// sparkline draws samples (0-100) with block characters
+func sparkline(samples []float64, width int) string {
+	blocks := []rune(theme.Symbol("▁▂▃▄▅▆▇█", "_.-=+*#@"))
+	if len(samples) > width {
+		samples = samples[len(samples)-width:]
+	}
+	var b strings.Builder
+	for _, s := range samples {
+		b.WriteRune(blocks[min(int(s/100*float64(len(blocks))), len(blocks)-1)])
+	}
+	return b.String()
+}
+
+// gauge draws a horizontal bar filled to percent
+func gauge(percent float64, width int) string {
+	filled := int(percent / 100 * float64(width))
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("86")).Render(strings.Repeat(theme.Symbol("█", "#"), filled)) +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("238")).Render(strings.Repeat(theme.Symbol("░", "-"), width-filled))
+}
+
+func (m dashboardModel) pane(i int, title, body string, w, h int) string {
+	style := paneStyle(i == m.focused)
+	header := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).Render(title)
+	return style.Width(w).Height(h).Render(header + "\n" + body)
+}
+
+func (m dashboardModel) View() string {
+	if m.width == 0 {
+		return "Starting dashboard..."
+	}
+
+	// Two columns, two rows; each pane subtracts 2 for its border
+	paneW := m.width/2 - 2
+	paneH := (m.height-2)/2 - 2
+	if paneW < 10 || paneH < 3 {
+		return "Terminal too small - please enlarge the window"
+	}
+
+	current := 0.0
+	if len(m.cpu) > 0 {
+		current = m.cpu[len(m.cpu)-1]
+	}
+	cpu := m.pane(0, "CPU", fmt.Sprintf("%5.1f%%\n%s", current, sparkline(m.cpu, paneW)), paneW, paneH)
+	mem := m.pane(1, "Memory", fmt.Sprintf("%5.1f%%\n%s", m.memory, gauge(m.memory, paneW)), paneW, paneH)
+	reqs := m.pane(2, "Requests", fmt.Sprintf("total: %d\nuptime: %s", m.requests, m.now.Sub(m.started).Round(time.Second)), paneW, paneH)
+
+	// Show only as many events as fit, newest last
+	events := m.events
+	if len(events) > paneH-1 {
+		events = events[len(events)-(paneH-1):]
+	}
+	logPane := m.pane(3, "Events", strings.Join(events, "\n"), paneW, paneH)
+
+	status := "live"
+	if m.paused {
+		status = "paused"
+	}
+	help := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).
+		Render(fmt.Sprintf("%s • tab focus • space pause • q quit", status))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, cpu, mem),
+		lipgloss.JoinHorizontal(lipgloss.Top, reqs, logPane),
+		help,
+	)
+}
+
+// DashboardExample demonstrates a live multi-pane dashboard
+// Concept: tea.Tick render loops, focus handling and responsive layout
+func DashboardExample() {
+	fmt.Println("\n=== HARD: Live Multi-Pane Dashboard ===")
+
+	if _, err := tea.NewProgram(newDashboardModel(), tea.WithAltScreen()).Run(); err != nil {
+		fmt.Println("Error:", err)
+	}
+}
+
+// Register the bubbletea examples with the launcher registry
+func init() {
+	Register(
+		Example{Name: "CounterExample", Package: "Bubble Tea", Difficulty: Easy, Description: "The Model / Update / View loop with a counter", Run: CounterExample, Interactive: true},
+		Example{Name: "ListViewportExample", Package: "Bubble Tea", Difficulty: Medium, Description: "Composing a list and a scrollable viewport", Run: ListViewportExample, Interactive: true},
+		Example{Name: "DashboardExample", Package: "Bubble Tea", Difficulty: Hard, Description: "Live multi-pane dashboard driven by ticks", Run: DashboardExample, Interactive: true},
+	)
+}
+
+// RunAllBubbleTeaExamples executes all bubbletea examples
+func RunAllBubbleTeaExamples() {
+	fmt.Println("\n" + strings.Repeat("=", 70))
+	fmt.Println("BUBBLE TEA EXAMPLES - Interactive Terminal Applications")
+	fmt.Println(strings.Repeat("=", 70))
+
+	// Easy examples
+	CounterExample()
+
+	// Medium examples
+	ListViewportExample()
+
+	// Hard examples
+	DashboardExample()
+
+	fmt.Println("\n" + strings.Repeat("=", 70))
+}
@@ -0,0 +1,123 @@
+package examples
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/huh"
+	"github.com/endalk200/charm.poc/theme"
+)
+
+// ==============================================================================
+// DEMO MODE - Forms that fill themselves in
+// ==============================================================================
+
+// demoMode makes runForm answer forms from a script instead of the keyboard
+var demoMode bool
+
+// SetDemoMode turns autoplay on or off for the form examples. With it on,
+// "Run All" works unattended and the forms still render exactly as they do
+// interactively, so it doubles as a recording of each example.
+func SetDemoMode(on bool) { demoMode = on }
+
+// DemoMode reports whether autoplay is on
+func DemoMode() bool { return demoMode }
+
+// Keystrokes as the terminal sends them; Bubble Tea parses them back into
+// the same tea.KeyMsg values a real key press produces
+const (
+	keyEnter  = "\r"
+	keyDown   = "\x1b[B"
+	keyCtrlC  = "\x03"
+	keyToggle = "x" // multi-select toggle
+)
+
+// typed types text into an input or text field and presses Enter
+func typed(text string) []string {
+	var keys []string
+	for _, r := range text {
+		keys = append(keys, string(r))
+	}
+	return append(keys, keyEnter)
+}
+
+// confirm answers a confirm field; y and n also move to the next field
+func confirm(yes bool) []string {
+	if yes {
+		return []string{"y"}
+	}
+	return []string{"n"}
+}
+
+// choose moves the cursor of a select field down to option n and picks it
+func choose(n int) []string {
+	return append(slices.Repeat([]string{keyDown}, n), keyEnter)
+}
+
+// check toggles the given options (in ascending order) of a multi-select
+// field and submits it
+func check(options ...int) []string {
+	var keys []string
+	at := 0
+	for _, n := range options {
+		keys = append(keys, slices.Repeat([]string{keyDown}, n-at)...)
+		keys = append(keys, keyToggle)
+		at = n
+	}
+	return append(keys, keyEnter)
+}
+
+// runForm runs a form styled with the shared theme. In demo mode the
+// answers come from script, one field per step (see typed, confirm, choose
+// and check), fed to the form as key presses. In accessible mode the form
+// asks its questions as plain numbered prompts, one line at a time, which
+// screen readers can follow; the scripts are written for the visual form,
+// so demo mode keeps using it.
+func runForm(form *huh.Form, script ...[]string) error {
+	form = form.WithTheme(theme.Huh())
+	if !demoMode {
+		return form.WithAccessible(theme.Accessible()).Run()
+	}
+	return form.WithInput(&autoplay{keys: slices.Concat(script...)}).Run()
+}
+
+// autoplay is an io.Reader that "types" one key per Read with a short pause,
+// slow enough to watch and fast enough not to bore
+type autoplay struct {
+	keys    []string
+	aborted bool
+}
+
+const (
+	typingDelay = 40 * time.Millisecond
+	stepDelay   = 350 * time.Millisecond
+	abortDelay  = 2 * time.Second
+)
+
+func (a *autoplay) Read(p []byte) (int, error) {
+	if len(a.keys) == 0 {
+		// A script shorter than its form would leave the form waiting
+		// forever; abort it instead, like a user pressing Ctrl+C. Wait
+		// first: the last key submits asynchronously, and a form that
+		// did finish stops reading before the pause is over.
+		if a.aborted {
+			return 0, io.EOF
+		}
+		a.aborted = true
+		time.Sleep(abortDelay)
+		return copy(p, keyCtrlC), nil
+	}
+
+	key := a.keys[0]
+	a.keys = a.keys[1:]
+
+	if utf8.RuneCountInString(key) == 1 && !strings.ContainsAny(key, "\r\x1b") {
+		time.Sleep(typingDelay)
+	} else {
+		time.Sleep(stepDelay)
+	}
+	return copy(p, key), nil
+}
@@ -0,0 +1,373 @@
+package examples
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/endalk200/charm.poc/theme"
+)
+
+// ==============================================================================
+// EASY EXAMPLES - Rendering markdown in the terminal
+// ==============================================================================
+
+// SimpleMarkdownExample demonstrates rendering markdown with one call
+// Concept: glamour.Render turns a markdown string into styled terminal output
+func SimpleMarkdownExample() {
+	fmt.Println("\n=== EASY: Rendering Markdown ===")
+
+	md := `# Hello, Glamour
+
+Glamour renders **markdown** in the terminal: *emphasis*, ` + "`inline code`" + `,
+[links](https://github.com/charmbracelet/glamour) and lists:
+
+- Headings
+- Block quotes
+- Tables and code blocks
+`
+
+	// The second argument picks a built-in style by name; theme.Markdown
+	// swaps in a plain one when colors or box characters are off
+	out, err := glamour.Render(md, theme.Markdown(styles.DarkStyle))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Print(out)
+}
+
+// AutoStyleExample demonstrates picking a style from the terminal
+// Concept: WithAutoStyle chooses dark or light from the terminal background
+func AutoStyleExample() {
+	fmt.Println("\n=== EASY: Automatic Style ===")
+
+	// NewTermRenderer builds a reusable renderer from options
+	style := glamour.WithAutoStyle() // dark or light background; "notty" when piped
+	if theme.NoColor() || theme.Accessible() {
+		style = glamour.WithStandardStyle(theme.Markdown(styles.DarkStyle))
+	}
+	r, err := glamour.NewTermRenderer(
+		style,
+		glamour.WithEmoji(), // turn :sparkles: into ✨
+	)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	out, err := r.Render("> :sparkles: This quote uses the style that suits your terminal.\n")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Print(out)
+}
+
+// ==============================================================================
+// MEDIUM EXAMPLES - Styles, wrapping and code
+// ==============================================================================
+
+// StylesComparisonExample demonstrates the built-in styles side by side
+// Concept: WithStandardStyle selects one of glamour's bundled styles
+func StylesComparisonExample() {
+	fmt.Println("\n=== MEDIUM: Built-in Styles ===")
+
+	md := "## Release notes\n\n- **Faster** startup\n- Fixed `--help` output\n"
+
+	names := []string{
+		styles.DarkStyle, styles.LightStyle, styles.DraculaStyle,
+		styles.TokyoNightStyle, styles.PinkStyle, styles.AsciiStyle,
+	}
+	if plain := theme.Markdown(styles.DarkStyle); plain != styles.DarkStyle {
+		// The styles differ mostly in color; without it, show the one that applies
+		fmt.Println("(colors are off, so only the plain style is shown)")
+		names = []string{plain}
+	}
+
+	for _, name := range names {
+		r, err := glamour.NewTermRenderer(
+			glamour.WithStandardStyle(name),
+			glamour.WithWordWrap(60),
+		)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		out, err := r.Render(md)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		fmt.Printf("--- style: %s ---", name)
+		fmt.Print(out)
+	}
+}
+
+// WordWrapExample demonstrates wrapping paragraphs to a width
+// Concept: WithWordWrap sets the width text reflows to (0 disables wrapping)
+func WordWrapExample() {
+	fmt.Println("\n=== MEDIUM: Word Wrap ===")
+
+	md := "Glamour reflows paragraphs to the width you give it, so the same " +
+		"document reads well in a narrow split pane and on a wide monitor. " +
+		"Code blocks and tables are never reflowed.\n"
+
+	for _, width := range []int{30, 60} {
+		r, err := glamour.NewTermRenderer(
+			glamour.WithStandardStyle(theme.Markdown(styles.DarkStyle)),
+			glamour.WithWordWrap(width),
+		)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		out, err := r.Render(md)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+
+		// Frame the output to make the width visible
+		frame := lipgloss.NewStyle().Border(theme.Border(lipgloss.NormalBorder())).BorderForeground(lipgloss.Color("240"))
+		fmt.Printf("Width %d:\n%s\n", width, frame.Render(strings.Trim(out, "\n")))
+	}
+}
+
+// CodeBlockExample demonstrates syntax-highlighted code blocks
+// Concept: The fence language selects the highlighter (chroma) lexer
+func CodeBlockExample() {
+	fmt.Println("\n=== MEDIUM: Code Blocks ===")
+
+	fence := "```"
+	md := "Go:\n\n" + fence + `go
+func greet(name string) string {
+	return fmt.Sprintf("Hello, %s!", name) // say hi
+}
+` + fence + "\n\nJSON:\n\n" + fence + `json
+{"name": "glamour", "stars": 2500, "tags": ["markdown", "cli"]}
+` + fence + "\n\nShell:\n\n" + fence + `bash
+go run . --run CodeBlockExample | less -R
+` + fence + "\n"
+
+	out, err := glamour.Render(md, styles.DraculaStyle)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Print(out)
+}
+
+// ==============================================================================
+// HARD EXAMPLES - Custom styles and scrolling documents
+// ==============================================================================
+
+// customStyle is a glamour style written as JSON. Every element (document,
+// headings, lists, code...) has its own block; elements left out are
+// rendered unstyled.
+const customStyle = `{
+	"document": {"margin": 2, "color": "252"},
+	"heading":  {"bold": true, "color": "39", "block_suffix": "\n"},
+	"h1":       {"prefix": " ", "suffix": " ", "color": "230", "background_color": "63"},
+	"h2":       {"prefix": "▌ "},
+	"paragraph":{"block_suffix": "\n"},
+	"strong":   {"bold": true, "color": "205"},
+	"emph":     {"italic": true, "color": "183"},
+	"code":     {"prefix": " ", "suffix": " ", "color": "203", "background_color": "236"},
+	"item":     {"block_prefix": "➜ "},
+	"link":     {"color": "39", "underline": true}
+}`
+
+// CustomStyleJSONExample demonstrates a style defined in JSON
+// Concept: WithStylesFromJSONBytes loads a full style from JSON
+func CustomStyleJSONExample() {
+	fmt.Println("\n=== HARD: Custom Style from JSON ===")
+
+	// Glamour renders in full color unless told otherwise; follow lipgloss,
+	// which knows whether colors are off. The custom prefixes are box
+	// characters, so accessible mode falls back to the ASCII style.
+	style := glamour.WithStylesFromJSONBytes([]byte(customStyle))
+	if theme.Accessible() {
+		style = glamour.WithStandardStyle(styles.AsciiStyle)
+	}
+	r, err := glamour.NewTermRenderer(
+		style,
+		glamour.WithColorProfile(lipgloss.ColorProfile()),
+		glamour.WithWordWrap(70),
+	)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	md := `# Custom Theme
+
+## Why JSON?
+
+Styles are **data**, so they can live in a file and be shared
+(` + "`glamour.WithStylePath(\"theme.json\")`" + `) or *tweaked* without
+recompiling.
+
+- Headings get a banner
+- Lists use arrows
+- Inline ` + "`code`" + ` gets a background
+`
+	out, err := r.Render(md)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Print(out)
+}
+
+// pagerModel shows rendered markdown in a scrollable viewport, re-rendering
+// it whenever the window width changes so paragraphs always fit
+type pagerModel struct {
+	markdown string
+	viewport viewport.Model
+	ready    bool
+	err      error
+}
+
+var pagerDocument = `# The Glamour Pager
+
+This document is rendered with **glamour** and displayed in a **bubbles viewport**.
+Resize the terminal: the text is re-rendered to the new width.
+
+## How it works
+
+1. On every ` + "`tea.WindowSizeMsg`" + `, build a renderer with
+   ` + "`glamour.WithWordWrap(width)`" + `
+2. Render the markdown and hand the result to ` + "`viewport.SetContent`" + `
+3. Forward key presses to the viewport so it scrolls
+
+## Code
+
+` + "```go" + `
+r, _ := glamour.NewTermRenderer(
+    glamour.WithStandardStyle("dark"),
+    glamour.WithWordWrap(msg.Width),
+)
+out, _ := r.Render(markdown)
+m.viewport.SetContent(out)
+` + "```" + `
+
+## A table
+
+| Key        | Action           |
+|------------|------------------|
+| ↑/↓, j/k   | scroll a line    |
+| pgup/pgdn  | scroll a page    |
+| q          | quit             |
+
+> Glamour also powers [Glow](https://github.com/charmbracelet/glow), a
+> full markdown reader for the terminal.
+
+## Filler
+
+` + strings.Repeat("Keep scrolling: long documents are where a pager earns its keep. ", 12) + `
+
+*The end.*
+`
+
+func (m pagerModel) Init() tea.Cmd { return nil }
+
+func (m pagerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "q" || msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+	case tea.WindowSizeMsg:
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width, msg.Height-1)
+			m.ready = true
+		} else {
+			m.viewport.Width, m.viewport.Height = msg.Width, msg.Height-1
+		}
+
+		r, err := glamour.NewTermRenderer(
+			glamour.WithStandardStyle(theme.Markdown(styles.DarkStyle)),
+			glamour.WithWordWrap(msg.Width-4),
+		)
+		if err != nil {
+			m.err = err
+			return m, tea.Quit
+		}
+		out, err := r.Render(m.markdown)
+		if err != nil {
+			m.err = err
+			return m, tea.Quit
+		}
+		m.viewport.SetContent(out)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m pagerModel) View() string {
+	if !m.ready {
+		return "Rendering..."
+	}
+	status := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).
+		Render(fmt.Sprintf("↑/↓ scroll • q quit • %3.f%%", m.viewport.ScrollPercent()*100))
+	return m.viewport.View() + "\n" + status
+}
+
+// MarkdownPagerExample demonstrates a scrollable markdown document
+// Concept: Combining glamour with a viewport and re-rendering on resize
+func MarkdownPagerExample() {
+	fmt.Println("\n=== HARD: Markdown Pager ===")
+
+	final, err := tea.NewProgram(pagerModel{markdown: pagerDocument}, tea.WithAltScreen()).Run()
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	if err := final.(pagerModel).err; err != nil {
+		fmt.Println("Error:", err)
+	}
+}
+
+// Register the glamour examples with the launcher registry
+func init() {
+	Register(
+		Example{Name: "SimpleMarkdownExample", Package: "Glamour", Difficulty: Easy, Description: "Render markdown with a built-in style", Run: SimpleMarkdownExample},
+		Example{Name: "AutoStyleExample", Package: "Glamour", Difficulty: Easy, Description: "Pick dark or light from the terminal background", Run: AutoStyleExample},
+		Example{Name: "StylesComparisonExample", Package: "Glamour", Difficulty: Medium, Description: "The same markdown in every built-in style", Run: StylesComparisonExample},
+		Example{Name: "WordWrapExample", Package: "Glamour", Difficulty: Medium, Description: "Reflowing paragraphs to different widths", Run: WordWrapExample},
+		Example{Name: "CodeBlockExample", Package: "Glamour", Difficulty: Medium, Description: "Syntax-highlighted fenced code blocks", Run: CodeBlockExample},
+		Example{Name: "CustomStyleJSONExample", Package: "Glamour", Difficulty: Hard, Description: "A custom style written in JSON", Run: CustomStyleJSONExample},
+		Example{Name: "MarkdownPagerExample", Package: "Glamour", Difficulty: Hard, Description: "Scrollable markdown that re-renders on resize", Run: MarkdownPagerExample, Interactive: true},
+	)
+}
+
+// RunAllGlamourExamples executes all glamour examples
+func RunAllGlamourExamples() {
+	fmt.Println("\n" + strings.Repeat("=", 70))
+	fmt.Println("GLAMOUR EXAMPLES - Markdown Rendering in the Terminal")
+	fmt.Println(strings.Repeat("=", 70))
+
+	// Easy examples
+	SimpleMarkdownExample()
+	AutoStyleExample()
+
+	// Medium examples
+	StylesComparisonExample()
+	WordWrapExample()
+	CodeBlockExample()
+
+	// Hard examples
+	CustomStyleJSONExample()
+	MarkdownPagerExample()
+
+	fmt.Println("\n" + strings.Repeat("=", 70))
+}
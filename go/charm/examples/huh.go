@@ -1,10 +1,17 @@
 package examples
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/huh"
+	"github.com/endalk200/charm.poc/theme"
 )
 
 // ==============================================================================
@@ -31,8 +38,9 @@ func SimpleInputExample() {
 		),
 	)
 
-	// Run() displays the form and waits for user input
-	err := form.Run()
+	// Run() displays the form and waits for user input; runForm calls it,
+	// or answers the form itself in demo mode (see demo.go)
+	err := runForm(form, typed("Gopher"))
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
@@ -58,16 +66,16 @@ func SimpleConfirmExample() {
 		),
 	)
 
-	err := form.Run()
+	err := runForm(form, confirm(true))
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
 	}
 
 	if confirmed {
-		fmt.Println("✓ Proceeding with the action...")
+		fmt.Println(theme.Symbol("✓", "[ok]") + " Proceeding with the action...")
 	} else {
-		fmt.Println("✗ Action cancelled.")
+		fmt.Println(theme.Symbol("✗", "[x]") + " Action cancelled.")
 	}
 }
 
@@ -95,7 +103,7 @@ func SimpleSelectExample() {
 		),
 	)
 
-	err := form.Run()
+	err := runForm(form, choose(2))
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
@@ -145,7 +153,12 @@ func MultiFieldFormExample() {
 		),
 	)
 
-	err := form.Run()
+	err := runForm(form,
+		typed("gopher"),
+		typed("gopher@example.com"),
+		typed("13"),
+		confirm(true),
+	)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
@@ -198,13 +211,13 @@ func ValidationExample() {
 		),
 	)
 
-	err := form.Run()
+	err := runForm(form, typed("gopher@example.com"), typed("correct-horse"))
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
 	}
 
-	fmt.Println("✓ Validation successful!")
+	fmt.Println(theme.Symbol("✓", "[ok]") + " Validation successful!")
 	fmt.Printf("Email: %s\n", email)
 	fmt.Printf("Password: %s\n", strings.Repeat("*", len(password)))
 }
@@ -236,7 +249,7 @@ func MultiSelectExample() {
 		),
 	)
 
-	err := form.Run()
+	err := runForm(form, check(0, 5))
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
@@ -267,7 +280,7 @@ func TextAreaExample() {
 		),
 	)
 
-	err := form.Run()
+	err := runForm(form, typed("Clear examples, more please!"))
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
@@ -293,7 +306,7 @@ func MultiPageFormExample() {
 		email     string
 
 		// Page 2: Preferences
-		theme         string
+		themeName     string
 		notifications bool
 
 		// Page 3: Confirmation
@@ -352,7 +365,7 @@ func MultiPageFormExample() {
 					huh.NewOption("Dark", "dark"),
 					huh.NewOption("Auto", "auto"),
 				).
-				Value(&theme),
+				Value(&themeName),
 
 			huh.NewConfirm().
 				Title("Enable email notifications").
@@ -369,45 +382,53 @@ func MultiPageFormExample() {
 				Title("Submit your information?").
 				Description(fmt.Sprintf(
 					"Name: %s %s\nEmail: %s\nTheme: %s\nNotifications: %v",
-					firstName, lastName, email, theme, notifications,
+					firstName, lastName, email, themeName, notifications,
 				)).
 				Value(&confirmSubmit),
 		),
 	)
 
-	err := form.Run()
+	err := runForm(form,
+		typed("Ada"),
+		typed("Lovelace"),
+		typed("ada@example.com"),
+		choose(1),
+		confirm(true),
+		confirm(true),
+	)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
 	}
 
 	if confirmSubmit {
-		fmt.Println("\n✓ Registration completed successfully!")
+		fmt.Println("\n" + theme.Symbol("✓", "[ok]") + " Registration completed successfully!")
 		fmt.Printf("Name: %s %s\n", firstName, lastName)
 		fmt.Printf("Email: %s\n", email)
-		fmt.Printf("Theme: %s\n", theme)
+		fmt.Printf("Theme: %s\n", themeName)
 		fmt.Printf("Notifications: %v\n", notifications)
 	} else {
-		fmt.Println("\n✗ Registration cancelled.")
+		fmt.Println("\n" + theme.Symbol("✗", "[x]") + " Registration cancelled.")
 	}
 }
 
 // DynamicFormExample demonstrates conditional form fields
-// Concept: Showing/hiding fields based on user input
+// Concept: Fields whose title and options are computed from earlier answers
 func DynamicFormExample() {
 	fmt.Println("\n=== HARD: Dynamic Form (Conditional Fields) ===")
 
 	var (
-		userType      string
-		companyName   string
-		jobTitle      string
-		studentID     string
-		university    string
-		freelanceRate string
+		userType     string
+		organization string
+		role         string
+		rate         string
 	)
 
-	// First, ask user type
-	typeForm := huh.NewForm(
+	// Everything lives in ONE form. Instead of asking the user type and then
+	// building a second form, the later fields are bound to &userType: the
+	// *Func variants are re-evaluated whenever a bound value changes, so the
+	// fields below update live as the cursor moves over the first select.
+	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewSelect[string]().
 				Title("What type of user are you?").
@@ -417,95 +438,146 @@ func DynamicFormExample() {
 					huh.NewOption("Freelancer", "freelancer"),
 				).
 				Value(&userType),
+
+			// TitleFunc and PlaceholderFunc: the same input asks a different question
+			huh.NewInput().
+				TitleFunc(func() string {
+					switch userType {
+					case "student":
+						return "University"
+					case "freelancer":
+						return "Business name"
+					}
+					return "Company name"
+				}, &userType).
+				PlaceholderFunc(func() string {
+					switch userType {
+					case "student":
+						return "Go University"
+					case "freelancer":
+						return "Pixel & Co"
+					}
+					return "Acme Inc."
+				}, &userType).
+				Value(&organization),
+
+			// OptionsFunc: the choices themselves depend on the first answer
+			huh.NewSelect[string]().
+				TitleFunc(func() string {
+					if userType == "student" {
+						return "Degree"
+					}
+					return "Role"
+				}, &userType).
+				OptionsFunc(func() []huh.Option[string] {
+					switch userType {
+					case "student":
+						return huh.NewOptions("Bachelor's", "Master's", "PhD")
+					case "freelancer":
+						return huh.NewOptions("Design", "Development", "Writing")
+					}
+					return huh.NewOptions("Engineer", "Manager", "Designer")
+				}, &userType).
+				Value(&role),
 		),
+
+		// WithHideFunc skips a whole group; it is asked only of freelancers
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Hourly Rate (USD)").
+				Placeholder("50").
+				Value(&rate).
+				Validate(func(s string) error {
+					if s == "" {
+						return fmt.Errorf("rate is required")
+					}
+					return nil
+				}),
+		).WithHideFunc(func() bool { return userType != "freelancer" }),
 	)
 
-	err := typeForm.Run()
+	err := runForm(form, choose(2), typed("Pixel & Co"), choose(1), typed("75"))
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
 	}
 
-	// Create conditional form based on user type
-	var detailsForm *huh.Form
+	// Display results based on user type
+	fmt.Println("\n--- User Profile ---")
+	fmt.Printf("Type: %s\n", userType)
 
 	switch userType {
 	case "corporate":
-		detailsForm = huh.NewForm(
-			huh.NewGroup(
-				huh.NewNote().
-					Title("Corporate Information"),
-
-				huh.NewInput().
-					Title("Company Name").
-					Value(&companyName),
-
-				huh.NewInput().
-					Title("Job Title").
-					Value(&jobTitle),
-			),
-		)
-
+		fmt.Printf("Company: %s\n", organization)
+		fmt.Printf("Role: %s\n", role)
 	case "student":
-		detailsForm = huh.NewForm(
-			huh.NewGroup(
-				huh.NewNote().
-					Title("Student Information"),
-
-				huh.NewInput().
-					Title("University").
-					Value(&university),
+		fmt.Printf("University: %s\n", organization)
+		fmt.Printf("Degree: %s\n", role)
+	case "freelancer":
+		fmt.Printf("Business: %s\n", organization)
+		fmt.Printf("Specialty: %s\n", role)
+		fmt.Printf("Hourly Rate: $%s\n", rate)
+	}
+}
 
-				huh.NewInput().
-					Title("Student ID").
-					Value(&studentID),
-			),
-		)
+// workflowProfile is what ComplexWorkflowExample remembers between runs.
+// The password is deliberately not part of it.
+type workflowProfile struct {
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	Bio         string    `json:"bio"`
+	Interests   []string  `json:"interests"`
+	Privacy     string    `json:"privacy"`
+	Theme       string    `json:"theme"`
+	Language    string    `json:"language"`
+	SavedAt     time.Time `json:"saved_at"`
+}
 
-	case "freelancer":
-		detailsForm = huh.NewForm(
-			huh.NewGroup(
-				huh.NewNote().
-					Title("Freelancer Information"),
+// profilePath is $CHARM_EXAMPLES_PROFILE, or profile.json in the user
+// config directory
+func profilePath() (string, error) {
+	return configPath("CHARM_EXAMPLES_PROFILE", "profile.json")
+}
 
-				huh.NewInput().
-					Title("Hourly Rate (USD)").
-					Placeholder("50").
-					Value(&freelanceRate).
-					Validate(func(s string) error {
-						if s == "" {
-							return fmt.Errorf("rate is required")
-						}
-						return nil
-					}),
-			),
-		)
+// loadWorkflowProfile returns the saved profile, or nil if there is none yet
+func loadWorkflowProfile() (*workflowProfile, error) {
+	path, err := profilePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
 	}
-
-	err = detailsForm.Run()
 	if err != nil {
-		fmt.Println("Error:", err)
-		return
+		return nil, err
 	}
+	var p workflowProfile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return &p, nil
+}
 
-	// Display results based on user type
-	fmt.Println("\n--- User Profile ---")
-	fmt.Printf("Type: %s\n", userType)
-
-	switch userType {
-	case "corporate":
-		fmt.Printf("Company: %s\n", companyName)
-		fmt.Printf("Job Title: %s\n", jobTitle)
-	case "student":
-		fmt.Printf("University: %s\n", university)
-		fmt.Printf("Student ID: %s\n", studentID)
-	case "freelancer":
-		fmt.Printf("Hourly Rate: $%s\n", freelanceRate)
+// saveWorkflowProfile writes the profile and returns where it went
+func saveWorkflowProfile(p workflowProfile) (string, error) {
+	path, err := profilePath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
 	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, append(data, '\n'), 0o644)
 }
 
 // ComplexWorkflowExample demonstrates a complete application workflow
-// Concept: Building a full user workflow with multiple forms and logic
+// Concept: Building a full user workflow with multiple forms, logic and
+// answers that persist between runs
 func ComplexWorkflowExample() {
 	fmt.Println("\n=== HARD: Complete Application Workflow ===")
 
@@ -522,16 +594,38 @@ func ComplexWorkflowExample() {
 
 		// Settings
 		privacyLevel string
-		theme        string
+		themeName    string
 		language     string
 	)
 
+	// Prefill: huh fields start from whatever their Value pointer holds, so
+	// loading saved answers into the variables is all it takes. Demo mode
+	// skips this, since its scripts type into empty fields.
+	var saved *workflowProfile
+	if !demoMode {
+		var err error
+		if saved, err = loadWorkflowProfile(); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+	}
+
+	welcome := huh.NewNote().
+		Title("Welcome! 👋").
+		Description("Let's get you set up")
+	if saved != nil {
+		existingUser = true
+		username, displayName, bio, interests = saved.Username, saved.DisplayName, saved.Bio, saved.Interests
+		privacyLevel, themeName, language = saved.Privacy, saved.Theme, saved.Language
+		welcome = huh.NewNote().
+			Title(fmt.Sprintf("Welcome back, %s! 👋", saved.DisplayName)).
+			Description(fmt.Sprintf("Your answers from %s are filled in.\nChange anything, or just press Enter.", saved.SavedAt.Format("Jan 2 15:04")))
+	}
+
 	// Step 1: Authentication
 	authForm := huh.NewForm(
 		huh.NewGroup(
-			huh.NewNote().
-				Title("Welcome! 👋").
-				Description("Let's get you set up"),
+			welcome,
 
 			huh.NewConfirm().
 				Title("Do you have an existing account?").
@@ -539,7 +633,7 @@ func ComplexWorkflowExample() {
 		),
 	)
 
-	err := authForm.Run()
+	err := runForm(authForm, confirm(false))
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
@@ -571,21 +665,25 @@ func ComplexWorkflowExample() {
 		),
 	)
 
-	err = credentialsForm.Run()
+	err = runForm(credentialsForm, typed("gopher"), typed("correct-horse"))
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
 	}
 
-	if existingUser {
-		fmt.Println("✓ Logged in successfully!")
+	if existingUser && saved == nil {
+		fmt.Println(theme.Symbol("✓", "[ok]") + " Logged in successfully!")
 	} else {
-		// Step 3: Profile Setup (only for new users)
+		// Step 3: Profile Setup (new users, or reviewing a saved profile)
+		title, description := "Complete Your Profile", "Tell us a bit about yourself"
+		if saved != nil {
+			title, description = "Review Your Profile", "Saved last time; edit what changed"
+		}
 		profileForm := huh.NewForm(
 			huh.NewGroup(
 				huh.NewNote().
-					Title("Complete Your Profile").
-					Description("Tell us a bit about yourself"),
+					Title(title).
+					Description(description),
 
 				huh.NewInput().
 					Title("Display Name").
@@ -612,7 +710,11 @@ func ComplexWorkflowExample() {
 			),
 		)
 
-		err = profileForm.Run()
+		err = runForm(profileForm,
+			typed("Gopher"),
+			typed("Learning Charm one example at a time"),
+			check(0, 3),
+		)
 		if err != nil {
 			fmt.Println("Error:", err)
 			return
@@ -642,7 +744,7 @@ func ComplexWorkflowExample() {
 					huh.NewOption("Dark", "dark"),
 					huh.NewOption("System", "system"),
 				).
-				Value(&theme),
+				Value(&themeName),
 
 			huh.NewSelect[string]().
 				Title("Language").
@@ -657,7 +759,7 @@ func ComplexWorkflowExample() {
 		),
 	)
 
-	err = settingsForm.Run()
+	err = runForm(settingsForm, choose(1), choose(1), choose(0))
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
@@ -665,11 +767,11 @@ func ComplexWorkflowExample() {
 
 	// Display final summary
 	fmt.Println("\n" + strings.Repeat("=", 60))
-	fmt.Println("✓ Setup Complete!")
+	fmt.Println(theme.Symbol("✓", "[ok]") + " Setup Complete!")
 	fmt.Println(strings.Repeat("=", 60))
 	fmt.Printf("\nUsername: %s\n", username)
 
-	if !existingUser {
+	if !existingUser || saved != nil {
 		fmt.Printf("Display Name: %s\n", displayName)
 		fmt.Printf("Bio: %s\n", bio)
 		fmt.Printf("Interests: %v\n", interests)
@@ -677,9 +779,43 @@ func ComplexWorkflowExample() {
 
 	fmt.Printf("\nSettings:\n")
 	fmt.Printf("  Privacy: %s\n", privacyLevel)
-	fmt.Printf("  Theme: %s\n", theme)
+	fmt.Printf("  Theme: %s\n", themeName)
 	fmt.Printf("  Language: %s\n", language)
 	fmt.Println(strings.Repeat("=", 60))
+
+	// Step 5: Persist the answers so the next run starts from them
+	if demoMode {
+		return
+	}
+	remember := true
+	err = runForm(huh.NewForm(huh.NewGroup(
+		huh.NewConfirm().
+			Title("Remember these answers for next time?").
+			Description("Saved without your password.").
+			Value(&remember),
+	)))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	if !remember {
+		return
+	}
+	path, err := saveWorkflowProfile(workflowProfile{
+		Username:    username,
+		DisplayName: displayName,
+		Bio:         bio,
+		Interests:   interests,
+		Privacy:     privacyLevel,
+		Theme:       themeName,
+		Language:    language,
+		SavedAt:     time.Now(),
+	})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println(theme.Symbol("✓", "[ok]")+" Saved to", path)
 }
 
 // FormWithInlineHelpExample demonstrates help text and documentation
@@ -739,18 +875,40 @@ func FormWithInlineHelpExample() {
 		),
 	)
 
-	err := form.Run()
+	err := runForm(form,
+		typed("sk_live_0123456789abcdefghij"),
+		typed("https://api.example.com"),
+		typed("30"),
+		typed("3"),
+	)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
 	}
 
-	fmt.Println("\n✓ API Configuration saved!")
+	fmt.Println("\n" + theme.Symbol("✓", "[ok]") + " API Configuration saved!")
 	fmt.Printf("Endpoint: %s\n", endpoint)
 	fmt.Printf("Timeout: %s seconds\n", timeout)
 	fmt.Printf("Max Retries: %s\n", retries)
 }
 
+// Register the huh examples with the launcher registry
+func init() {
+	Register(
+		Example{Name: "SimpleInputExample", Package: "Huh", Difficulty: Easy, Description: "Basic text input field", Run: SimpleInputExample},
+		Example{Name: "SimpleConfirmExample", Package: "Huh", Difficulty: Easy, Description: "Yes/no confirmation dialog", Run: SimpleConfirmExample},
+		Example{Name: "SimpleSelectExample", Package: "Huh", Difficulty: Easy, Description: "Single-choice selection menu", Run: SimpleSelectExample},
+		Example{Name: "MultiFieldFormExample", Package: "Huh", Difficulty: Medium, Description: "Forms with multiple input fields", Run: MultiFieldFormExample},
+		Example{Name: "ValidationExample", Package: "Huh", Difficulty: Medium, Description: "Input validation with custom rules", Run: ValidationExample},
+		Example{Name: "MultiSelectExample", Package: "Huh", Difficulty: Medium, Description: "Selecting multiple options from a list", Run: MultiSelectExample},
+		Example{Name: "TextAreaExample", Package: "Huh", Difficulty: Medium, Description: "Multi-line text input", Run: TextAreaExample},
+		Example{Name: "MultiPageFormExample", Package: "Huh", Difficulty: Hard, Description: "Multi-step wizard-like forms", Run: MultiPageFormExample},
+		Example{Name: "DynamicFormExample", Package: "Huh", Difficulty: Hard, Description: "Fields whose title and options follow earlier answers, in one form", Run: DynamicFormExample},
+		Example{Name: "ComplexWorkflowExample", Package: "Huh", Difficulty: Hard, Description: "Complete application workflow whose answers are saved and prefilled next run", Run: ComplexWorkflowExample},
+		Example{Name: "FormWithInlineHelpExample", Package: "Huh", Difficulty: Hard, Description: "Forms with contextual help text", Run: FormWithInlineHelpExample},
+	)
+}
+
 // RunAllHuhExamples executes all huh examples
 func RunAllHuhExamples() {
 	fmt.Println("\n" + strings.Repeat("=", 70))
@@ -3,8 +3,14 @@ package examples
 import (
 	"fmt"
 	"strings"
+	"testing"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/endalk200/charm.poc/layout"
+	"github.com/endalk200/charm.poc/theme"
+	"github.com/muesli/termenv"
 )
 
 // ==============================================================================
@@ -16,20 +22,23 @@ import (
 func SimpleLipglossExample() {
 	fmt.Println("\n=== EASY: Simple Text Styling ===")
 
+	p := theme.Current()
+
 	// Create a style with a single property - foreground color
-	// Colors can be specified using ANSI codes (0-255) or hex values
+	// The colors come from the shared theme (see BasicColorsExample for
+	// writing them by hand), so they follow the palette picked in the launcher
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("205")) // Pink color
+		Foreground(p.Primary)
 
 	// Render applies the style to the text
-	fmt.Println(style.Render("Hello, Lipgloss! This text is pink."))
+	fmt.Println(style.Render("Hello, Lipgloss! This text uses the primary color."))
 
 	// You can also chain multiple properties
 	boldStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("46")) // Green
+		Foreground(p.Success)
 
-	fmt.Println(boldStyle.Render("This is bold and green!"))
+	fmt.Println(boldStyle.Render("This is bold and uses the success color!"))
 }
 
 // BasicColorsExample shows how to work with different color formats
@@ -52,6 +61,14 @@ func BasicColorsExample() {
 		Foreground(lipgloss.Color("0")).  // Black text
 		Background(lipgloss.Color("226")) // Yellow background
 	fmt.Println(backgroundStyle.Render("Black text on yellow background"))
+
+	// Method 4: Adaptive colors pick a value based on the terminal background.
+	// Every color in the shared theme is one; toggle the background with b in
+	// the launcher to see both halves.
+	adaptiveStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.AdaptiveColor{Light: "21", Dark: "117"}) // Dark blue / light blue
+	fmt.Println(adaptiveStyle.Render("Adaptive Color (Light: 21, Dark: 117)"))
+	fmt.Printf("Background detected as dark: %v\n", lipgloss.HasDarkBackground())
 }
 
 // SimpleBordersExample introduces basic border styling
@@ -59,22 +76,24 @@ func BasicColorsExample() {
 func SimpleBordersExample() {
 	fmt.Println("\n=== EASY: Basic Borders ===")
 
+	p := theme.Current()
+
 	// NormalBorder is a simple single-line border
 	normalBorder := lipgloss.NewStyle().
-		Border(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("63")) // Purple
+		Border(theme.Border(lipgloss.NormalBorder())).
+		BorderForeground(p.Secondary)
 	fmt.Println(normalBorder.Render("Normal Border"))
 
 	// RoundedBorder has rounded corners
 	roundedBorder := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("86")) // Cyan
+		Border(theme.Border(lipgloss.RoundedBorder())).
+		BorderForeground(p.Info)
 	fmt.Println(roundedBorder.Render("Rounded Border"))
 
 	// DoubleBorder uses double-line characters
 	doubleBorder := lipgloss.NewStyle().
-		Border(lipgloss.DoubleBorder()).
-		BorderForeground(lipgloss.Color("214")) // Orange
+		Border(theme.Border(lipgloss.DoubleBorder())).
+		BorderForeground(p.Warning)
 	fmt.Println(doubleBorder.Render("Double Border"))
 }
 
@@ -87,31 +106,33 @@ func SimpleBordersExample() {
 func PaddingAndMarginsExample() {
 	fmt.Println("\n=== MEDIUM: Padding and Margins ===")
 
+	p := theme.Current()
+
 	// Padding adds space INSIDE the border
 	// Syntax: Padding(top, right, bottom, left) or Padding(vertical, horizontal)
 	withPadding := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("205")).
+		Border(theme.Border(lipgloss.RoundedBorder())).
+		BorderForeground(p.Primary).
 		Padding(1, 3). // 1 line vertical, 3 spaces horizontal
-		Foreground(lipgloss.Color("219"))
+		Foreground(p.Primary)
 	fmt.Println(withPadding.Render("Text with padding"))
 
 	// Margin adds space OUTSIDE the border
 	withMargin := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("86")).
+		Border(theme.Border(lipgloss.RoundedBorder())).
+		BorderForeground(p.Info).
 		Margin(1, 0). // 1 line vertical margin, 0 horizontal
-		Foreground(lipgloss.Color("123"))
+		Foreground(p.Info)
 	fmt.Println(withMargin.Render("Text with margin"))
 
 	// Combining both padding and margin
 	combined := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("214")).
+		Border(theme.Border(lipgloss.RoundedBorder())).
+		BorderForeground(p.Warning).
 		Padding(0, 2).
 		Margin(1, 4).
-		Background(lipgloss.Color("235")).
-		Foreground(lipgloss.Color("228"))
+		Background(p.Surface).
+		Foreground(p.Highlight)
 	fmt.Println(combined.Render("Padding + Margin + Background"))
 }
 
@@ -120,11 +141,13 @@ func PaddingAndMarginsExample() {
 func AlignmentExample() {
 	fmt.Println("\n=== MEDIUM: Text Alignment ===")
 
+	p := theme.Current()
+
 	// Width sets the fixed width for the styled element
 	baseStyle := lipgloss.NewStyle().
 		Width(50).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("69"))
+		Border(theme.Border(lipgloss.RoundedBorder())).
+		BorderForeground(p.Secondary)
 
 	// Left alignment (default)
 	leftAlign := baseStyle.Copy().Align(lipgloss.Left)
@@ -144,22 +167,24 @@ func AlignmentExample() {
 func JoinExample() {
 	fmt.Println("\n=== MEDIUM: Joining Elements ===")
 
+	p := theme.Current()
+
 	// Create individual styled blocks
 	block1 := lipgloss.NewStyle().
-		Background(lipgloss.Color("205")).
-		Foreground(lipgloss.Color("0")).
+		Background(p.Primary).
+		Foreground(p.OnColor).
 		Padding(1, 2).
 		Render("Block 1")
 
 	block2 := lipgloss.NewStyle().
-		Background(lipgloss.Color("86")).
-		Foreground(lipgloss.Color("0")).
+		Background(p.Info).
+		Foreground(p.OnColor).
 		Padding(1, 2).
 		Render("Block 2")
 
 	block3 := lipgloss.NewStyle().
-		Background(lipgloss.Color("214")).
-		Foreground(lipgloss.Color("0")).
+		Background(p.Warning).
+		Foreground(p.OnColor).
 		Padding(1, 2).
 		Render("Block 3")
 
@@ -178,29 +203,32 @@ func JoinExample() {
 func StyleInheritanceExample() {
 	fmt.Println("\n=== MEDIUM: Style Inheritance ===")
 
+	p := theme.Current()
+
 	// Create a base style
 	baseStyle := lipgloss.NewStyle().
 		Padding(1, 2).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("63"))
+		Border(theme.Border(lipgloss.RoundedBorder())).
+		BorderForeground(p.Secondary)
 
 	// Copy() creates a new style with all properties of the base
 	// Then you can modify specific properties
 	successStyle := baseStyle.Copy().
-		Foreground(lipgloss.Color("46")). // Green
-		BorderForeground(lipgloss.Color("46"))
+		Foreground(p.Success).
+		BorderForeground(p.Success)
 
 	warningStyle := baseStyle.Copy().
-		Foreground(lipgloss.Color("226")). // Yellow
-		BorderForeground(lipgloss.Color("226"))
+		Foreground(p.Warning).
+		BorderForeground(p.Warning)
 
 	errorStyle := baseStyle.Copy().
-		Foreground(lipgloss.Color("196")). // Red
-		BorderForeground(lipgloss.Color("196"))
+		Foreground(p.Error).
+		BorderForeground(p.Error)
 
-	fmt.Println(successStyle.Render("✓ Success message"))
-	fmt.Println(warningStyle.Render("⚠ Warning message"))
-	fmt.Println(errorStyle.Render("✗ Error message"))
+	// The icons say what the colors say, so nothing is lost without them
+	fmt.Println(successStyle.Render(theme.Symbol("✓", "[ok]") + " Success message"))
+	fmt.Println(warningStyle.Render(theme.Symbol("⚠", "[!]") + " Warning message"))
+	fmt.Println(errorStyle.Render(theme.Symbol("✗", "[x]") + " Error message"))
 }
 
 // ==============================================================================
@@ -212,19 +240,21 @@ func StyleInheritanceExample() {
 func ComplexLayoutExample() {
 	fmt.Println("\n=== HARD: Complex Dashboard Layout ===")
 
+	p := theme.Current()
+
 	// Header style
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("230")). // Light yellow
-		Background(lipgloss.Color("63")).  // Purple
+		Foreground(p.OnColor).
+		Background(p.Secondary).
 		Padding(0, 1).
 		Width(70).
 		Align(lipgloss.Center)
 
 	// Create individual panels
 	panelStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("241")).
+		Border(theme.Border(lipgloss.RoundedBorder())).
+		BorderForeground(p.Muted).
 		Padding(1).
 		Width(32).
 		Height(8)
@@ -232,44 +262,44 @@ func ComplexLayoutExample() {
 	// Stats panel
 	statsContent := lipgloss.JoinVertical(
 		lipgloss.Left,
-		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).Render("📊 Statistics"),
+		lipgloss.NewStyle().Bold(true).Foreground(p.Primary).Render(theme.Symbol("📊 ", "")+"Statistics"),
 		"",
-		lipgloss.NewStyle().Foreground(lipgloss.Color("46")).Render("Users: 1,234"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("86")).Render("Active: 456"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render("Revenue: $12.3k"),
+		lipgloss.NewStyle().Foreground(p.Success).Render("Users: 1,234"),
+		lipgloss.NewStyle().Foreground(p.Info).Render("Active: 456"),
+		lipgloss.NewStyle().Foreground(p.Warning).Render("Revenue: $12.3k"),
 	)
 	statsPanel := panelStyle.Copy().Render(statsContent)
 
 	// Activity panel
 	activityContent := lipgloss.JoinVertical(
 		lipgloss.Left,
-		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86")).Render("🔔 Recent Activity"),
+		lipgloss.NewStyle().Bold(true).Foreground(p.Info).Render(theme.Symbol("🔔 ", "")+"Recent Activity"),
 		"",
-		"• User logged in",
-		"• File uploaded",
-		"• Task completed",
+		theme.Symbol("•", "-")+" User logged in",
+		theme.Symbol("•", "-")+" File uploaded",
+		theme.Symbol("•", "-")+" Task completed",
 	)
 	activityPanel := panelStyle.Copy().Render(activityContent)
 
 	// Status panel
 	statusContent := lipgloss.JoinVertical(
 		lipgloss.Left,
-		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("226")).Render("⚡ System Status"),
+		lipgloss.NewStyle().Bold(true).Foreground(p.Highlight).Render(theme.Symbol("⚡ ", "")+"System Status"),
 		"",
-		lipgloss.NewStyle().Foreground(lipgloss.Color("46")).Render("✓ API: Online"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("46")).Render("✓ DB: Connected"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("226")).Render("⚠ Cache: Slow"),
+		lipgloss.NewStyle().Foreground(p.Success).Render(theme.Symbol("✓", "[ok]")+" API: Online"),
+		lipgloss.NewStyle().Foreground(p.Success).Render(theme.Symbol("✓", "[ok]")+" DB: Connected"),
+		lipgloss.NewStyle().Foreground(p.Warning).Render(theme.Symbol("⚠", "[!]")+" Cache: Slow"),
 	)
 	statusPanel := panelStyle.Copy().Render(statusContent)
 
 	// Alerts panel
 	alertsContent := lipgloss.JoinVertical(
 		lipgloss.Left,
-		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214")).Render("⚠️  Alerts"),
+		lipgloss.NewStyle().Bold(true).Foreground(p.Warning).Render(theme.Symbol("⚠️  ", "")+"Alerts"),
 		"",
-		lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("• 3 Failed logins"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render("• Disk 75% full"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color("226")).Render("• Update available"),
+		lipgloss.NewStyle().Foreground(p.Error).Render(theme.Symbol("•", "[x]")+" 3 Failed logins"),
+		lipgloss.NewStyle().Foreground(p.Warning).Render(theme.Symbol("•", "[!]")+" Disk 75% full"),
+		lipgloss.NewStyle().Foreground(p.Highlight).Render(theme.Symbol("•", "[i]")+" Update available"),
 	)
 	alertsPanel := panelStyle.Copy().Render(alertsContent)
 
@@ -280,7 +310,7 @@ func ComplexLayoutExample() {
 	// Combine everything
 	dashboard := lipgloss.JoinVertical(
 		lipgloss.Left,
-		headerStyle.Render("🚀 Application Dashboard"),
+		headerStyle.Render(theme.Symbol("🚀 ", "")+"Application Dashboard"),
 		"",
 		topRow,
 		"",
@@ -295,8 +325,17 @@ func ComplexLayoutExample() {
 func ProgressBarExample() {
 	fmt.Println("\n=== HARD: Progress Bars ===")
 
+	p := theme.Current()
+
+	// The bar is drawn with background colors, which vanish without color
+	// support; draw it with characters then
+	fillChar, emptyChar := " ", " "
+	if theme.NoColor() || theme.Accessible() {
+		fillChar, emptyChar = theme.Symbol("█", "#"), theme.Symbol("░", "-")
+	}
+
 	// Create a progress bar function
-	renderProgressBar := func(label string, percent int, color lipgloss.Color) string {
+	renderProgressBar := func(label string, percent int, color lipgloss.TerminalColor) string {
 		// Calculate filled and empty portions
 		totalWidth := 40
 		filledWidth := totalWidth * percent / 100
@@ -305,12 +344,12 @@ func ProgressBarExample() {
 		// Create filled portion
 		filled := lipgloss.NewStyle().
 			Background(color).
-			Render(strings.Repeat(" ", filledWidth))
+			Render(strings.Repeat(fillChar, filledWidth))
 
 		// Create empty portion
 		empty := lipgloss.NewStyle().
-			Background(lipgloss.Color("236")).
-			Render(strings.Repeat(" ", emptyWidth))
+			Background(p.Surface).
+			Render(strings.Repeat(emptyChar, emptyWidth))
 
 		// Create percentage label
 		percentLabel := lipgloss.NewStyle().
@@ -323,7 +362,7 @@ func ProgressBarExample() {
 		// Create label
 		labelStyle := lipgloss.NewStyle().
 			Width(12).
-			Foreground(lipgloss.Color("250")).
+			Foreground(p.Subtle).
 			Render(label)
 
 		// Combine everything
@@ -332,10 +371,10 @@ func ProgressBarExample() {
 	}
 
 	// Display multiple progress bars
-	fmt.Println(renderProgressBar("CPU", 67, lipgloss.Color("46")))
-	fmt.Println(renderProgressBar("Memory", 82, lipgloss.Color("214")))
-	fmt.Println(renderProgressBar("Disk", 45, lipgloss.Color("86")))
-	fmt.Println(renderProgressBar("Network", 91, lipgloss.Color("196")))
+	fmt.Println(renderProgressBar("CPU", 67, p.Success))
+	fmt.Println(renderProgressBar("Memory", 82, p.Warning))
+	fmt.Println(renderProgressBar("Disk", 45, p.Info))
+	fmt.Println(renderProgressBar("Network", 91, p.Error))
 }
 
 // TableExample creates a formatted table with styling
@@ -343,6 +382,8 @@ func ProgressBarExample() {
 func TableExample() {
 	fmt.Println("\n=== HARD: Styled Table ===")
 
+	p := theme.Current()
+
 	// Define table data
 	headers := []string{"Name", "Role", "Status", "Score"}
 	rows := [][]string{
@@ -355,20 +396,20 @@ func TableExample() {
 	// Header style
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("0")).
-		Background(lipgloss.Color("63")).
+		Foreground(p.OnColor).
+		Background(p.Secondary).
 		Padding(0, 1).
 		Width(18).
 		Align(lipgloss.Center)
 
 	// Cell styles
 	cellStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252")).
+		Foreground(p.Text).
 		Padding(0, 1).
 		Width(18)
 
 	alternateStyle := cellStyle.Copy().
-		Background(lipgloss.Color("235"))
+		Background(p.Surface)
 
 	// Render headers
 	var headerRow []string
@@ -397,36 +438,38 @@ func TableExample() {
 func AdaptiveLayoutExample() {
 	fmt.Println("\n=== HARD: Adaptive Layout ===")
 
+	p := theme.Current()
+
 	// Function to create a notification card based on type
 	createNotification := func(notifType, title, message string) string {
 		var (
 			icon        string
-			color       lipgloss.Color
-			borderColor lipgloss.Color
+			color       lipgloss.TerminalColor
+			borderColor lipgloss.TerminalColor
 		)
 
 		// Adapt style based on notification type
 		switch notifType {
 		case "success":
-			icon = "✓"
-			color = lipgloss.Color("46")
-			borderColor = lipgloss.Color("46")
+			icon = theme.Symbol("✓", "[ok]")
+			color = p.Success
+			borderColor = p.Success
 		case "warning":
-			icon = "⚠"
-			color = lipgloss.Color("226")
-			borderColor = lipgloss.Color("226")
+			icon = theme.Symbol("⚠", "[!]")
+			color = p.Warning
+			borderColor = p.Warning
 		case "error":
-			icon = "✗"
-			color = lipgloss.Color("196")
-			borderColor = lipgloss.Color("196")
+			icon = theme.Symbol("✗", "[x]")
+			color = p.Error
+			borderColor = p.Error
 		case "info":
-			icon = "ℹ"
-			color = lipgloss.Color("86")
-			borderColor = lipgloss.Color("86")
+			icon = theme.Symbol("ℹ", "[i]")
+			color = p.Info
+			borderColor = p.Info
 		default:
-			icon = "•"
-			color = lipgloss.Color("252")
-			borderColor = lipgloss.Color("241")
+			icon = theme.Symbol("•", "-")
+			color = p.Text
+			borderColor = p.Muted
 		}
 
 		// Create icon style
@@ -443,12 +486,12 @@ func AdaptiveLayoutExample() {
 
 		// Create message style
 		messageStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("250")).
+			Foreground(p.Subtle).
 			Width(50)
 
 		// Create border style
 		boxStyle := lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
+			Border(theme.Border(lipgloss.RoundedBorder())).
 			BorderForeground(borderColor).
 			Padding(1, 2)
 
@@ -473,6 +516,283 @@ func AdaptiveLayoutExample() {
 	fmt.Println(createNotification("info", "Information", "New updates are available for download."))
 }
 
+// splitLayoutModel lays out an editor-like screen with the layout package
+// and simply renders it again at the new size on every resize
+type splitLayoutModel struct {
+	width, height int
+	sidebar       int // percent of the width given to the file tree
+}
+
+func (m splitLayoutModel) Init() tea.Cmd { return nil }
+
+func (m splitLayoutModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "[":
+			m.sidebar = max(10, m.sidebar-5)
+		case "]":
+			m.sidebar = min(60, m.sidebar+5)
+		}
+	}
+	return m, nil
+}
+
+func (m splitLayoutModel) View() string {
+	if m.width == 0 {
+		return ""
+	}
+	p := theme.Current()
+
+	// panel draws a bordered box filling exactly the size it is given, and
+	// shows that size so resizing the terminal visibly re-flows the layout
+	panel := func(title, rule string, color lipgloss.TerminalColor, lines ...string) func(w, h int) string {
+		return func(w, h int) string {
+			head := lipgloss.NewStyle().Bold(true).Foreground(color).Render(title) +
+				lipgloss.NewStyle().Foreground(p.Muted).Render(fmt.Sprintf("  %d×%d  %s", w, h, rule))
+			return lipgloss.NewStyle().
+				Border(theme.Border(lipgloss.RoundedBorder())).
+				BorderForeground(color).
+				Width(max(0, w-2)).Height(max(0, h-2)).
+				MaxHeight(h).
+				Render(head + "\n" + strings.Join(lines, "\n"))
+		}
+	}
+
+	root := layout.Rows(
+		layout.Columns(
+			layout.Leaf(panel("Files", fmt.Sprintf("%d%%, min 18", m.sidebar), p.Secondary,
+				"▾ examples/", "   lipgloss.go", "   huh.go", "▾ layout/", "   layout.go", "  main.go",
+			)).Percent(m.sidebar).Min(18),
+			layout.Rows(
+				layout.Columns(
+					layout.Leaf(panel("Editor", "rest", p.Primary,
+						"func (p Pane) Render(w, h int) string {",
+						"    sizes := Sizes(total, p.children)",
+						"    ...",
+						"}",
+					)),
+					layout.Leaf(panel("Outline", "25%, min 16", p.Info,
+						"Leaf", "Columns", "Rows", "Sizes", "Fit",
+					)).Percent(25).Min(16),
+				),
+				layout.Leaf(panel("Terminal", "30%, min 5", p.Success,
+					"$ go run . --run SplitLayoutExample",
+				)).Percent(30).Min(5),
+			),
+		),
+		layout.Leaf(func(w, h int) string {
+			return lipgloss.NewStyle().Foreground(p.Muted).
+				Render(" resize the terminal • [/] sidebar width • panes below their minimum hide from the right • q quit")
+		}).Fixed(1),
+	)
+	return root.Render(m.width, m.height)
+}
+
+// SplitLayoutExample demonstrates a reusable flexbox-style layout helper
+// Concept: Percent-based nested splits with minimum sizes, re-rendered on resize
+func SplitLayoutExample() {
+	fmt.Println("\n=== HARD: Split-Pane Layout ===")
+
+	if _, err := tea.NewProgram(splitLayoutModel{sidebar: 25}, tea.WithAltScreen()).Run(); err != nil {
+		fmt.Println("Error:", err)
+	}
+}
+
+// benchRows is the data every rendering strategy below draws: a table big
+// enough that the cost of each approach shows
+func benchRows() [][]string {
+	services := []string{"api", "auth", "billing", "search", "worker"}
+	statuses := []string{"healthy", "degraded", "down"}
+	rows := make([][]string, 200)
+	for i := range rows {
+		rows[i] = []string{
+			fmt.Sprintf("%s-%03d", services[i%len(services)], i),
+			statuses[i%7%len(statuses)],
+			fmt.Sprintf("%d ms", 20+i*37%480),
+			fmt.Sprintf("%.1f%%", float64(i*13%1000)/10),
+			fmt.Sprintf("v1.%d.%d", i%9, i%4),
+		}
+	}
+	return rows
+}
+
+var benchHeaders = []string{"Service", "Status", "Latency", "CPU", "Version"}
+
+// renderNaive builds a fresh style for every cell and grows the result with
+// +=, which copies everything rendered so far on each append
+func renderNaive(p theme.Palette, rows [][]string) string {
+	out := ""
+	for _, h := range benchHeaders {
+		out += lipgloss.NewStyle().Bold(true).Foreground(p.OnColor).Background(p.Secondary).Width(14).Padding(0, 1).Render(h)
+	}
+	out += "\n"
+	for i, row := range rows {
+		for _, cell := range row {
+			style := lipgloss.NewStyle().Foreground(p.Text).Width(14).Padding(0, 1)
+			if i%2 == 1 {
+				style = style.Background(p.Surface)
+			}
+			out += style.Render(cell)
+		}
+		out += "\n"
+	}
+	return out
+}
+
+// renderBuilder still builds a style per cell, but appends to a
+// strings.Builder, so the output is only copied when the buffer grows
+func renderBuilder(p theme.Palette, rows [][]string) string {
+	var b strings.Builder
+	for _, h := range benchHeaders {
+		b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(p.OnColor).Background(p.Secondary).Width(14).Padding(0, 1).Render(h))
+	}
+	b.WriteByte('\n')
+	for i, row := range rows {
+		for _, cell := range row {
+			style := lipgloss.NewStyle().Foreground(p.Text).Width(14).Padding(0, 1)
+			if i%2 == 1 {
+				style = style.Background(p.Surface)
+			}
+			b.WriteString(style.Render(cell))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// renderPrecomputed defines each style once, outside the loops, and sizes
+// the builder up front from a first rendered row
+func renderPrecomputed(p theme.Palette, rows [][]string) string {
+	var (
+		header = lipgloss.NewStyle().Bold(true).Foreground(p.OnColor).Background(p.Secondary).Width(14).Padding(0, 1)
+		cell   = lipgloss.NewStyle().Foreground(p.Text).Width(14).Padding(0, 1)
+		alt    = cell.Background(p.Surface)
+	)
+
+	var b strings.Builder
+	for _, h := range benchHeaders {
+		b.WriteString(header.Render(h))
+	}
+	b.WriteByte('\n')
+	for i, row := range rows {
+		style := cell
+		if i%2 == 1 {
+			style = alt
+		}
+		for _, c := range row {
+			if i == 0 {
+				// One rendered cell tells roughly how big the whole table is
+				b.Grow(len(style.Render(c)) * len(row) * len(rows))
+			}
+			b.WriteString(style.Render(c))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// RenderingBenchmarkExample demonstrates what rendering choices cost
+// Concept: Measuring time and allocations of three ways to draw a big table
+func RenderingBenchmarkExample() {
+	fmt.Println("\n=== HARD: Rendering Performance ===")
+
+	p := theme.Current()
+	rows := benchRows()
+	strategies := []struct {
+		name   string
+		render func(theme.Palette, [][]string) string
+	}{
+		{"naive += and per-cell styles", renderNaive},
+		{"strings.Builder", renderBuilder},
+		{"pre-computed styles + Grow", renderPrecomputed},
+	}
+
+	// A benchmark is only meaningful if every strategy draws the same thing
+	want := renderNaive(p, rows)
+	for _, s := range strategies[1:] {
+		if s.render(p, rows) != want {
+			fmt.Println("Error:", s.name, "renders a different table")
+			return
+		}
+	}
+	// Without colors there are no escape codes to produce, so piped output
+	// benchmarks faster than a real terminal does
+	profile := map[termenv.Profile]string{
+		termenv.TrueColor: "true color", termenv.ANSI256: "256 colors",
+		termenv.ANSI: "16 colors", termenv.Ascii: "no color",
+	}[lipgloss.ColorProfile()]
+	fmt.Printf("Rendering a %d×%d table (%d KB of output, %s)\n",
+		len(rows)+1, len(benchHeaders), len(want)/1024, profile)
+	fmt.Println("Each strategy runs for about a second...")
+
+	// testing.Benchmark is the engine behind `go test -bench`, usable from
+	// any program: it picks b.N so the run lasts long enough to time reliably
+	results := make([]testing.BenchmarkResult, len(strategies))
+	for i, s := range strategies {
+		results[i] = testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				s.render(p, rows)
+			}
+		})
+	}
+
+	var (
+		nameStyle = lipgloss.NewStyle().Foreground(p.Text).Width(30)
+		numStyle  = lipgloss.NewStyle().Foreground(p.Subtle).Width(12).Align(lipgloss.Right)
+		headStyle = lipgloss.NewStyle().Bold(true).Foreground(p.Secondary)
+		barStyle  = lipgloss.NewStyle().Foreground(p.Primary)
+		fastest   = results[0].NsPerOp()
+	)
+	for _, r := range results {
+		fastest = min(fastest, r.NsPerOp())
+	}
+
+	fmt.Println()
+	fmt.Println(headStyle.Render(nameStyle.Render("Strategy") + numStyle.Render("time/op") +
+		numStyle.Render("allocs/op") + numStyle.Render("bytes/op") + "  relative"))
+	for i, r := range results {
+		relative := float64(r.NsPerOp()) / float64(max(1, fastest))
+		fmt.Println(nameStyle.Render(strategies[i].name) +
+			numStyle.Render(time.Duration(r.NsPerOp()).Round(time.Microsecond).String()) +
+			numStyle.Render(fmt.Sprint(r.AllocsPerOp())) +
+			numStyle.Render(fmt.Sprintf("%.1f MB", float64(r.AllocedBytesPerOp())/(1<<20))) +
+			"  " + barStyle.Render(strings.Repeat(theme.Symbol("█", "#"), min(30, int(relative*5)))) +
+			fmt.Sprintf(" %.2fx", relative))
+	}
+
+	fmt.Println("\nTakeaways:")
+	fmt.Println("  • += copies the whole string on every append: cost grows with the square of the output")
+	fmt.Println("  • Style values are cheap to copy but not free; build them once, outside hot loops")
+	fmt.Println("  • Render itself (wrapping, padding, escape codes) dominates once the waste is gone;")
+	fmt.Println("    in a Bubble Tea View, render only what changed and cache the rest")
+}
+
+// Register the lipgloss examples with the launcher registry
+func init() {
+	Register(
+		Example{Name: "SimpleLipglossExample", Package: "Lipgloss", Difficulty: Easy, Description: "Basic text styling with colors", Run: SimpleLipglossExample},
+		Example{Name: "BasicColorsExample", Package: "Lipgloss", Difficulty: Easy, Description: "Different color formats (ANSI, Hex, Named)", Run: BasicColorsExample},
+		Example{Name: "SimpleBordersExample", Package: "Lipgloss", Difficulty: Easy, Description: "Adding borders to text", Run: SimpleBordersExample},
+		Example{Name: "PaddingAndMarginsExample", Package: "Lipgloss", Difficulty: Medium, Description: "Spacing control inside and outside borders", Run: PaddingAndMarginsExample},
+		Example{Name: "AlignmentExample", Package: "Lipgloss", Difficulty: Medium, Description: "Text alignment (left, center, right)", Run: AlignmentExample},
+		Example{Name: "JoinExample", Package: "Lipgloss", Difficulty: Medium, Description: "Combining styled elements horizontally and vertically", Run: JoinExample},
+		Example{Name: "StyleInheritanceExample", Package: "Lipgloss", Difficulty: Medium, Description: "Reusing and extending base styles", Run: StyleInheritanceExample},
+		Example{Name: "ComplexLayoutExample", Package: "Lipgloss", Difficulty: Hard, Description: "Multi-panel dashboard layout", Run: ComplexLayoutExample},
+		Example{Name: "ProgressBarExample", Package: "Lipgloss", Difficulty: Hard, Description: "Creating visual progress indicators", Run: ProgressBarExample},
+		Example{Name: "TableExample", Package: "Lipgloss", Difficulty: Hard, Description: "Formatted tables with styling", Run: TableExample},
+		Example{Name: "AdaptiveLayoutExample", Package: "Lipgloss", Difficulty: Hard, Description: "Responsive-like notification cards", Run: AdaptiveLayoutExample},
+		Example{Name: "RenderingBenchmarkExample", Package: "Lipgloss", Difficulty: Hard, Description: "Time and allocations of naive, Builder and pre-computed style rendering", Run: RenderingBenchmarkExample},
+		Example{Name: "SplitLayoutExample", Package: "Lipgloss", Difficulty: Hard, Description: "Nested percent-based split panes with minimum sizes that follow resizes", Run: SplitLayoutExample, Interactive: true},
+	)
+}
+
 // RunAllLipglossExamples executes all lipgloss examples
 func RunAllLipglossExamples() {
 	fmt.Println("\n" + strings.Repeat("=", 70))
@@ -495,6 +815,8 @@ func RunAllLipglossExamples() {
 	ProgressBarExample()
 	TableExample()
 	AdaptiveLayoutExample()
+	SplitLayoutExample()
+	RenderingBenchmarkExample()
 
 	fmt.Println("\n" + strings.Repeat("=", 70))
 }
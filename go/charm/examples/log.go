@@ -1,12 +1,25 @@
 package examples
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
+	"github.com/endalk200/charm.poc/theme"
 )
 
 // ==============================================================================
@@ -183,11 +196,11 @@ func LoggerOptionsExample() {
 
 	// Create logger with custom options
 	logger := log.NewWithOptions(os.Stderr, log.Options{
-		ReportCaller:    true,           // Include file and line number
-		ReportTimestamp: true,           // Include timestamp
-		TimeFormat:      time.TimeOnly,  // Custom time format
-		Level:           log.DebugLevel, // Set initial log level
-		Prefix:          "MyApp 🚀",      // Add a prefix to all messages
+		ReportCaller:    true,                             // Include file and line number
+		ReportTimestamp: true,                             // Include timestamp
+		TimeFormat:      time.TimeOnly,                    // Custom time format
+		Level:           log.DebugLevel,                   // Set initial log level
+		Prefix:          theme.Symbol("MyApp 🚀", "MyApp"), // Add a prefix to all messages
 	})
 
 	logger.Debug("Debug message with caller info")
@@ -503,6 +516,873 @@ func DistributedTracingExample() {
 		"trace_id", traceID)
 }
 
+// ==============================================================================
+// PRODUCTION PATTERNS - One logger, several sinks
+// ==============================================================================
+
+// A real service rarely logs to one place. Here a single *slog.Logger fans
+// every record out to three sinks, each with its own format and level:
+//
+//   - console: human-readable text on stderr, warnings and errors only
+//   - file:    JSON lines in a size-rotated file, everything down to debug
+//   - ring:    the most recent entries in memory, at a level that can be
+//              changed while running, shown in a live tail view
+//
+// charm's *log.Logger implements slog.Handler, so it can be one of the sinks.
+
+// fanoutHandler is a slog.Handler that passes each record to every handler
+// that accepts its level
+type fanoutHandler []slog.Handler
+
+func (f fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			// Clone so one handler consuming the attributes cannot affect the next
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanoutHandler, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (f fanoutHandler) WithGroup(name string) slog.Handler {
+	out := make(fanoutHandler, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
+
+// rotatingFile is an io.Writer that starts a new file once the current one
+// would grow past maxBytes, keeping the last few as app.log.1, app.log.2...
+// The logger writes each entry with a single Write, so lines are never split.
+type rotatingFile struct {
+	mu        sync.Mutex
+	path      string
+	maxBytes  int64
+	backups   int
+	file      *os.File
+	size      int64
+	rotations int
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file, r.size = f, info.Size()
+	return nil
+}
+
+// rotate shifts app.log.N to app.log.N+1 (dropping the oldest), moves the
+// current file to app.log.1 and opens a fresh one
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	for i := r.backups - 1; i >= 1; i-- {
+		from := fmt.Sprintf("%s.%d", r.path, i)
+		if _, err := os.Stat(from); err == nil {
+			if err := os.Rename(from, fmt.Sprintf("%s.%d", r.path, i+1)); err != nil {
+				return err
+			}
+		}
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return err
+	}
+	r.rotations++
+	return r.open()
+}
+
+// stats reports the current file size and how many rotations happened
+func (r *rotatingFile) stats() (size int64, rotations int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.size, r.rotations
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Close()
+}
+
+// heldWriter forwards writes, except while held: then it keeps them until
+// release. The console sink goes through one so it cannot draw over the
+// tail view while that owns the screen.
+type heldWriter struct {
+	mu   sync.Mutex
+	w    io.Writer
+	held bool
+	buf  bytes.Buffer
+}
+
+func (h *heldWriter) Write(p []byte) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.held {
+		return h.buf.Write(p)
+	}
+	return h.w.Write(p)
+}
+
+func (h *heldWriter) hold() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.held = true
+}
+
+func (h *heldWriter) release() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.held = false
+	_, err := h.buf.WriteTo(h.w)
+	return err
+}
+
+// logEntry is one record kept by the ring buffer
+type logEntry struct {
+	time    time.Time
+	level   slog.Level
+	message string
+	attrs   string      // pre-rendered as key=value pairs
+	fields  [][2]string // the same pairs, for filtering
+}
+
+// ringBuffer keeps the last len(entries) records; when full, each new one
+// overwrites the oldest. Memory use is fixed however much is logged.
+type ringBuffer struct {
+	mu      sync.Mutex
+	entries []logEntry
+	next    int // slot the next entry goes into
+	total   int // entries stored since the start
+}
+
+func newRingBuffer(capacity int) *ringBuffer {
+	return &ringBuffer{entries: make([]logEntry, capacity)}
+}
+
+func (b *ringBuffer) add(e logEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries[b.next] = e
+	b.next = (b.next + 1) % len(b.entries)
+	b.total++
+}
+
+// last returns up to n of the newest entries, oldest first, and the number
+// currently held
+func (b *ringBuffer) last(n int) (entries []logEntry, held int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	held = min(b.total, len(b.entries))
+	n = min(n, held)
+	for i := n; i > 0; i-- {
+		entries = append(entries, b.entries[(b.next-i+len(b.entries))%len(b.entries)])
+	}
+	return entries, held
+}
+
+// ringHandler is the slog.Handler writing into a ringBuffer. Its level is a
+// slog.LevelVar, which can be changed safely while other goroutines log.
+type ringHandler struct {
+	buf    *ringBuffer
+	level  *slog.LevelVar
+	attrs  string
+	fields [][2]string
+	prefix string // group names, as "group."
+}
+
+func (h ringHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h ringHandler) Handle(_ context.Context, r slog.Record) error {
+	var sb strings.Builder
+	sb.WriteString(h.attrs)
+	fields := slices.Clone(h.fields)
+	r.Attrs(func(a slog.Attr) bool {
+		fmt.Fprintf(&sb, " %s%s=%v", h.prefix, a.Key, a.Value)
+		fields = append(fields, [2]string{h.prefix + a.Key, a.Value.String()})
+		return true
+	})
+	h.buf.add(logEntry{time: r.Time, level: r.Level, message: r.Message, attrs: sb.String(), fields: fields})
+	return nil
+}
+
+func (h ringHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	// Clip so handlers derived from the same parent never share an array
+	h.fields = slices.Clip(h.fields)
+	for _, a := range attrs {
+		h.attrs += fmt.Sprintf(" %s%s=%v", h.prefix, a.Key, a.Value)
+		h.fields = append(h.fields, [2]string{h.prefix + a.Key, a.Value.String()})
+	}
+	return h
+}
+
+func (h ringHandler) WithGroup(name string) slog.Handler {
+	h.prefix += name + "."
+	return h
+}
+
+// simulateTraffic logs like a busy web service until ctx is cancelled
+func simulateTraffic(ctx context.Context, logger *slog.Logger) {
+	paths := []string{"/api/users", "/api/orders", "/api/search", "/healthz"}
+	rng := rand.New(rand.NewPCG(1, 2))
+	ticker := time.NewTicker(120 * time.Millisecond)
+	defer ticker.Stop()
+
+	for i := 1; ; i++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		req := logger.With("request_id", fmt.Sprintf("req-%04d", i))
+		path := paths[rng.IntN(len(paths))]
+		latency := time.Duration(rng.IntN(400)) * time.Millisecond
+		switch {
+		case i%40 == 0:
+			req.Error("upstream timeout", "path", path, "upstream", "payments", "after", 2*time.Second)
+		case path == "/healthz":
+			req.Debug("health check", "status", 200)
+		case latency > 320*time.Millisecond:
+			req.Warn("slow request", "path", path, "latency", latency)
+		default:
+			req.Info("request served", "method", "GET", "path", path, "status", 200, "latency", latency)
+		}
+	}
+}
+
+// tailModel shows the newest ring buffer entries, refreshed a few times a
+// second. Keys 1-4 change the ring's level on the fly.
+type tailModel struct {
+	ring   *ringBuffer
+	level  *slog.LevelVar
+	file   *rotatingFile
+	paused bool
+	shown  []logEntry
+	held   int
+	height int
+}
+
+type tailTickMsg struct{}
+
+func tailTick() tea.Cmd {
+	return tea.Tick(250*time.Millisecond, func(time.Time) tea.Msg { return tailTickMsg{} })
+}
+
+func (m tailModel) Init() tea.Cmd { return tailTick() }
+
+func (m tailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case " ":
+			m.paused = !m.paused
+		case "1":
+			m.level.Set(slog.LevelDebug)
+		case "2":
+			m.level.Set(slog.LevelInfo)
+		case "3":
+			m.level.Set(slog.LevelWarn)
+		case "4":
+			m.level.Set(slog.LevelError)
+		}
+
+	case tailTickMsg:
+		if !m.paused {
+			// Header and footer take four lines
+			m.shown, m.held = m.ring.last(max(1, m.height-4))
+		}
+		return m, tailTick()
+	}
+	return m, nil
+}
+
+func (m tailModel) View() string {
+	p := theme.Current()
+	levels := map[slog.Level]lipgloss.Style{
+		slog.LevelDebug: lipgloss.NewStyle().Foreground(p.Muted),
+		slog.LevelInfo:  lipgloss.NewStyle().Foreground(p.Info),
+		slog.LevelWarn:  lipgloss.NewStyle().Foreground(p.Warning),
+		slog.LevelError: lipgloss.NewStyle().Foreground(p.Error).Bold(true),
+	}
+	subtle := lipgloss.NewStyle().Foreground(p.Muted)
+
+	size, rotations := m.file.stats()
+	state := ""
+	if m.paused {
+		state = lipgloss.NewStyle().Foreground(p.Warning).Render("  PAUSED")
+	}
+	header := lipgloss.NewStyle().Bold(true).Foreground(p.Primary).Render("ring buffer tail") +
+		subtle.Render(fmt.Sprintf("  %d/%d entries · capturing %s and above · %s %.1f KB, rotated %d×",
+			m.held, len(m.ring.entries), m.level.Level(), filepath.Base(m.file.path), float64(size)/1024, rotations)) + state
+
+	var lines []string
+	for _, e := range m.shown {
+		lines = append(lines, subtle.Render(e.time.Format("15:04:05.000"))+" "+
+			levels[e.level].Width(6).Render(e.level.String())+
+			e.message+subtle.Render(e.attrs))
+	}
+
+	help := subtle.Render("1 debug • 2 info • 3 warn • 4 error • space pause • q quit")
+	return header + "\n\n" + strings.Join(lines, "\n") + "\n\n" + help
+}
+
+// MultiSinkLoggingExample demonstrates one logger feeding several sinks
+// Concept: slog fan-out to a console, a rotating JSON file and a ring buffer
+func MultiSinkLoggingExample() {
+	fmt.Println("\n=== HARD: Multi-Sink Logging ===")
+
+	dir := filepath.Join(os.TempDir(), "charm-examples-logs")
+	if err := os.RemoveAll(dir); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// Sink 1: people. Text with colors, only what needs attention.
+	console := &heldWriter{w: os.Stderr}
+	consoleLogger := log.NewWithOptions(console, log.Options{
+		Level:           log.WarnLevel,
+		ReportTimestamp: true,
+		TimeFormat:      time.TimeOnly,
+	})
+	// heldWriter is not a terminal, so pass on the color support of the real one
+	consoleLogger.SetColorProfile(lipgloss.ColorProfile())
+
+	// Sink 2: machines. One JSON object per line, everything, size-rotated.
+	file := &rotatingFile{path: filepath.Join(dir, "app.log"), maxBytes: 8 << 10, backups: 3}
+	defer file.Close()
+	fileLogger := log.NewWithOptions(file, log.Options{
+		Level:           log.DebugLevel,
+		ReportTimestamp: true,
+		Formatter:       log.JSONFormatter,
+	})
+
+	// Sink 3: the recent past, in memory, at an adjustable level
+	ring := newRingBuffer(200)
+	ringLevel := new(slog.LevelVar) // Info by default
+
+	logger := slog.New(fanoutHandler{
+		consoleLogger,
+		fileLogger,
+		ringHandler{buf: ring, level: ringLevel},
+	}).With("service", "checkout")
+
+	logger.Info("service starting", "version", "1.4.2", "port", 8080)
+	logger.Warn("config value missing, using default", "key", "cache.ttl", "default", 5*time.Minute)
+
+	// Serve "traffic" while the tail view is open. The console sink holds
+	// its output meanwhile and prints it once the screen is back.
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		simulateTraffic(ctx, logger)
+		close(done)
+	}()
+
+	console.hold()
+	_, err := tea.NewProgram(tailModel{ring: ring, level: ringLevel, file: file}, tea.WithAltScreen()).Run()
+	cancel()
+	<-done
+	logger.Info("service stopped")
+	if err := console.release(); err != nil {
+		fmt.Println("Error:", err)
+	}
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	// What ended up where
+	files, _ := filepath.Glob(filepath.Join(dir, "app.log*"))
+	fmt.Println("\nSinks after the run:")
+	fmt.Println("  console  warnings and errors (above), held while the tail view was open")
+	fmt.Printf("  file     %d file(s) in %s, JSON lines, debug and above:\n", len(files), dir)
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil {
+			fmt.Printf("             %-10s %5.1f KB\n", filepath.Base(f), float64(info.Size())/1024)
+		}
+	}
+	_, held := ring.last(0)
+	fmt.Printf("  ring     %d entries kept in memory at level %s\n", held, ringLevel.Level())
+
+	if data, err := os.ReadFile(file.path); err == nil {
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		fmt.Println("\nLast line of app.log:")
+		fmt.Println(lines[len(lines)-1])
+	}
+}
+
+// ==============================================================================
+// PLAYGROUND - Practice reading structured logs
+// ==============================================================================
+
+// playgroundServices are the parts of the simulated application; each one
+// logs with its own service field
+var playgroundServices = []string{"api", "auth", "payments", "search", "db"}
+
+// playgroundFaults are the errors an injected incident can cause
+var playgroundFaults = []string{"connection refused", "deadline exceeded", "too many open files", "certificate expired", "disk full"}
+
+// faultInjector holds the incident injected into the simulated application,
+// if any. The TUI starts incidents and the simulator reads them, from
+// different goroutines.
+type faultInjector struct {
+	mu      sync.Mutex
+	service string
+	err     string
+	until   time.Time
+}
+
+func (f *faultInjector) inject(service, err string, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.service, f.err, f.until = service, err, time.Now().Add(d)
+}
+
+func (f *faultInjector) clear() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.until = time.Time{}
+}
+
+// active returns the failing service and its error while an incident lasts
+func (f *faultInjector) active() (service, err string, ok bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.service, f.err, time.Now().Before(f.until)
+}
+
+// simulateApp logs like a small application made of several services: mostly
+// routine requests, some background noise of warnings and unrelated errors,
+// and, during an incident, a stream of errors from the failing service that
+// the services calling it report as warnings
+func simulateApp(ctx context.Context, logger *slog.Logger, faults *faultInjector) {
+	routes := map[string][]string{
+		"api":      {"/v1/orders", "/v1/users", "/v1/cart"},
+		"auth":     {"/login", "/token/refresh"},
+		"payments": {"/charge", "/refund"},
+		"search":   {"/query", "/suggest"},
+		"db":       {"SELECT orders", "UPDATE carts", "SELECT users"},
+	}
+	rng := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 7))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Duration(60+rng.IntN(240)) * time.Millisecond):
+		}
+
+		service := playgroundServices[rng.IntN(len(playgroundServices))]
+		if bad, fault, ok := faults.active(); ok && rng.IntN(3) == 0 {
+			if service == bad || rng.IntN(2) == 0 {
+				route := routes[bad][rng.IntN(len(routes[bad]))]
+				logger.With("service", bad, "trace_id", fmt.Sprintf("%08x", rng.Uint32())).
+					Error("request failed", "route", route, "err", fault, "attempt", 1+rng.IntN(3))
+			} else {
+				// Callers only see that their upstream is failing
+				logger.With("service", service, "trace_id", fmt.Sprintf("%08x", rng.Uint32())).
+					Warn("upstream call failed, retrying", "upstream", bad, "latency_ms", 1000+rng.IntN(2000))
+			}
+			continue
+		}
+
+		req := logger.With("service", service, "trace_id", fmt.Sprintf("%08x", rng.Uint32()))
+		route := routes[service][rng.IntN(len(routes[service]))]
+		switch r := rng.IntN(100); {
+		case r < 3:
+			// Noise: errors happen all the time and are not all incidents
+			req.Error("request failed", "route", route, "err", []string{"context canceled", "invalid input"}[rng.IntN(2)], "status", []int{499, 400}[rng.IntN(2)])
+		case r < 12:
+			req.Warn("slow request", "route", route, "latency_ms", 300+rng.IntN(900))
+		case r < 25:
+			req.Debug("cache lookup", "route", route, "hit", rng.IntN(2) == 0)
+		default:
+			req.Info("request served", "route", route, "status", 200, "latency_ms", 5+rng.IntN(120))
+		}
+	}
+}
+
+// logFilter is a parsed filter line. Every term must match: key=value looks
+// for value in that field (level=warn compares the level), a bare word looks
+// in the message and all field values. Matching ignores case.
+type logFilter []string
+
+func parseLogFilter(s string) logFilter { return strings.Fields(strings.ToLower(s)) }
+
+func (f logFilter) matches(e logEntry) bool {
+	for _, term := range f {
+		if key, value, ok := strings.Cut(term, "="); ok {
+			if key == "level" {
+				if !strings.HasPrefix(strings.ToLower(e.level.String()), value) {
+					return false
+				}
+				continue
+			}
+			if !slices.ContainsFunc(e.fields, func(kv [2]string) bool {
+				return strings.ToLower(kv[0]) == key && strings.Contains(strings.ToLower(kv[1]), value)
+			}) {
+				return false
+			}
+			continue
+		}
+		if !strings.Contains(strings.ToLower(e.message), term) && !slices.ContainsFunc(e.fields, func(kv [2]string) bool {
+			return strings.Contains(strings.ToLower(kv[1]), term)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// highlights reports whether the filter picked out this field, so the view
+// can mark why an entry matched
+func (f logFilter) highlights(key, value string) bool {
+	key, value = strings.ToLower(key), strings.ToLower(value)
+	for _, term := range f {
+		k, v, ok := strings.Cut(term, "=")
+		if ok && k == key && strings.Contains(value, v) || !ok && strings.Contains(value, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// playgroundModel is a log viewer for the simulated application with
+// filtering by level and field, and incidents to find
+type playgroundModel struct {
+	ring   *ringBuffer
+	faults *faultInjector
+	rng    *rand.Rand
+
+	minLevel slog.Level
+	filter   logFilter
+	input    textinput.Model
+	asking   string // what the input is for: "filter", "answer" or ""
+	paused   bool
+	shown    []logEntry
+	matched  int
+
+	incident         string // the failing service while an incident is unsolved
+	incidentErr      string
+	started          time.Time
+	injected, solved int
+	status           string
+
+	width, height int
+}
+
+func newPlaygroundModel(ring *ringBuffer, faults *faultInjector) playgroundModel {
+	input := textinput.New()
+	input.CharLimit = 80
+	input.Width = 40
+	return playgroundModel{
+		ring:     ring,
+		faults:   faults,
+		rng:      rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 11)),
+		minLevel: slog.LevelInfo,
+		input:    input,
+		status:   "Press i to inject an incident, then find the failing service.",
+	}
+}
+
+func (m playgroundModel) Init() tea.Cmd { return tailTick() }
+
+func (m playgroundModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	p := theme.Current()
+	good := lipgloss.NewStyle().Foreground(p.Success)
+	bad := lipgloss.NewStyle().Foreground(p.Error)
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+
+	case tea.KeyMsg:
+		// While typing, keys go to the input
+		if m.asking != "" {
+			switch msg.String() {
+			case "esc":
+				m.asking = ""
+				m.input.Blur()
+				return m, nil
+			case "enter":
+				value := strings.TrimSpace(m.input.Value())
+				if m.asking == "filter" {
+					m.filter = parseLogFilter(value)
+				} else {
+					m = m.answer(value, good, bad)
+				}
+				m.asking = ""
+				m.input.Blur()
+				m.refresh()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
+
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case " ":
+			m.paused = !m.paused
+		case "1", "2", "3", "4":
+			m.minLevel = []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError}[msg.String()[0]-'1']
+		case "/":
+			m.asking = "filter"
+			m.input.Prompt = "filter: "
+			m.input.Placeholder = "service=db level=error timeout"
+			m.input.SetValue(strings.Join(m.filter, " "))
+			m.input.CursorEnd()
+			return m, m.input.Focus()
+		case "c":
+			m.filter = nil
+		case "i":
+			if m.incident != "" {
+				m.status = "An incident is already in progress."
+				break
+			}
+			m.incident = playgroundServices[m.rng.IntN(len(playgroundServices))]
+			m.incidentErr = playgroundFaults[m.rng.IntN(len(playgroundFaults))]
+			m.started = time.Now()
+			m.injected++
+			m.faults.inject(m.incident, m.incidentErr, 45*time.Second)
+			m.status = lipgloss.NewStyle().Foreground(p.Warning).Bold(true).Render("Incident! One service is failing.") +
+				" Find it, then press a to answer."
+		case "a":
+			if m.incident == "" {
+				m.status = "No incident to answer; press i to start one."
+				break
+			}
+			m.asking = "answer"
+			m.input.Prompt = "failing service: "
+			m.input.Placeholder = strings.Join(playgroundServices, ", ")
+			m.input.SetValue("")
+			return m, m.input.Focus()
+		}
+		m.refresh()
+
+	case tailTickMsg:
+		// An incident nobody solved ends on its own; reveal it
+		if _, _, ok := m.faults.active(); m.incident != "" && !ok {
+			m.status = fmt.Sprintf("The incident is over: %s was failing with %q.", m.incident, m.incidentErr)
+			m.incident = ""
+		}
+		if !m.paused {
+			m.refresh()
+		}
+		return m, tailTick()
+	}
+	return m, nil
+}
+
+// answer checks a guess for the failing service. A right answer ends the
+// incident, as fixing the service would.
+func (m playgroundModel) answer(service string, good, bad lipgloss.Style) playgroundModel {
+	if !strings.EqualFold(service, m.incident) {
+		m.status = bad.Render(theme.Symbol("✗", "[x]")+" Not "+service+".") +
+			" Hint: errors come from the failing service, warnings from its callers; compare the err fields."
+		return m
+	}
+	m.solved++
+	m.faults.clear()
+	m.status = good.Render(fmt.Sprintf("%s Right: %s was failing with %q, found in %s.",
+		theme.Symbol("✓", "[ok]"), m.incident, m.incidentErr, time.Since(m.started).Round(time.Second)))
+	m.incident = ""
+	return m
+}
+
+// refresh picks the newest entries that pass the level and the filter
+func (m *playgroundModel) refresh() {
+	all, _ := m.ring.last(len(m.ring.entries))
+	var matched []logEntry
+	for _, e := range all {
+		if e.level >= m.minLevel && m.filter.matches(e) {
+			matched = append(matched, e)
+		}
+	}
+	m.matched = len(matched)
+	// Header, status, help and the input take seven lines
+	m.shown = matched[max(0, len(matched)-max(1, m.height-7)):]
+}
+
+func (m playgroundModel) View() string {
+	p := theme.Current()
+	subtle := lipgloss.NewStyle().Foreground(p.Muted)
+	levels := map[slog.Level]lipgloss.Style{
+		slog.LevelDebug: lipgloss.NewStyle().Foreground(p.Muted),
+		slog.LevelInfo:  lipgloss.NewStyle().Foreground(p.Info),
+		slog.LevelWarn:  lipgloss.NewStyle().Foreground(p.Warning).Bold(true),
+		slog.LevelError: lipgloss.NewStyle().Foreground(p.OnColor).Background(p.Error).Bold(true),
+	}
+	// Each service keeps its color, so a burst from one of them stands out
+	services := map[string]lipgloss.Style{}
+	for i, c := range []lipgloss.AdaptiveColor{p.Primary, p.Secondary, p.Info, p.Highlight, p.Success} {
+		services[playgroundServices[i]] = lipgloss.NewStyle().Foreground(c)
+	}
+	value := lipgloss.NewStyle().Foreground(p.Text)
+	errValue := lipgloss.NewStyle().Foreground(p.Error)
+	marked := lipgloss.NewStyle().Underline(true).Bold(true)
+
+	filter := "none"
+	if len(m.filter) > 0 {
+		filter = strings.Join(m.filter, " ")
+	}
+	state := ""
+	if m.paused {
+		state = lipgloss.NewStyle().Foreground(p.Warning).Render("  PAUSED")
+	}
+	header := lipgloss.NewStyle().Bold(true).Foreground(p.Primary).Render("log playground") +
+		subtle.Render(fmt.Sprintf("  %d matching · level %s and above · filter: %s · solved %d/%d",
+			m.matched, m.minLevel, filter, m.solved, m.injected)) + state
+
+	var lines []string
+	for _, e := range m.shown {
+		var b strings.Builder
+		b.WriteString(subtle.Render(e.time.Format("15:04:05.000")) + " ")
+		b.WriteString(levels[e.level].Width(6).Align(lipgloss.Center).Render(e.level.String()) + " ")
+		b.WriteString(value.Render(e.message))
+		for _, kv := range e.fields {
+			style := value
+			switch {
+			case kv[0] == "service" || kv[0] == "upstream":
+				if s, ok := services[kv[1]]; ok {
+					style = s
+				}
+			case kv[0] == "err":
+				style = errValue
+			}
+			if m.filter.highlights(kv[0], kv[1]) {
+				style = style.Inherit(marked)
+			}
+			b.WriteString(" " + subtle.Render(kv[0]+"=") + style.Render(kv[1]))
+		}
+		lines = append(lines, lipgloss.NewStyle().MaxWidth(max(20, m.width)).Render(b.String()))
+	}
+
+	bottom := subtle.Render("1-4 level • / filter • c clear • i inject incident • a answer • space pause • q quit")
+	if m.asking != "" {
+		bottom = m.input.View() + subtle.Render("  (enter apply • esc cancel)")
+	}
+	return header + "\n\n" + strings.Join(lines, "\n") + "\n\n" + m.status + "\n" + bottom
+}
+
+// LogPlaygroundExample demonstrates reading structured logs interactively
+// Concept: Fields make logs searchable; filter by level and key=value to find a fault
+func LogPlaygroundExample() {
+	fmt.Println("\n=== HARD: Log Reading Playground ===")
+
+	// Everything is kept; what is shown is up to the filters in the viewer
+	ring := newRingBuffer(500)
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelDebug)
+	logger := slog.New(ringHandler{buf: ring, level: level})
+
+	faults := &faultInjector{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		simulateApp(ctx, logger, faults)
+		close(done)
+	}()
+
+	final, err := tea.NewProgram(newPlaygroundModel(ring, faults), tea.WithAltScreen()).Run()
+	cancel()
+	<-done
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	m := final.(playgroundModel)
+	_, held := ring.last(0)
+	fmt.Printf("Incidents solved: %d of %d (%d entries in the buffer at the end)\n", m.solved, m.injected, held)
+	fmt.Println("\nReading structured logs:")
+	fmt.Println("  • Filter on fields (service=db, level=error) instead of scanning text")
+	fmt.Println("  • Errors point at where a fault is; warnings often at who suffers from it")
+	fmt.Println("  • Not every error is the incident: look for the one that repeats")
+}
+
+// Register the log examples with the launcher registry
+func init() {
+	Register(
+		Example{Name: "SimpleLogExample", Package: "Log", Difficulty: Easy, Description: "Basic log levels (Debug, Info, Warn, Error)", Run: SimpleLogExample},
+		Example{Name: "LogWithFieldsExample", Package: "Log", Difficulty: Easy, Description: "Structured logging with key-value pairs", Run: LogWithFieldsExample},
+		Example{Name: "LogFormattingExample", Package: "Log", Difficulty: Easy, Description: "Customizing log output format", Run: LogFormattingExample},
+		Example{Name: "LogLevelsExample", Package: "Log", Difficulty: Medium, Description: "Controlling log level filtering", Run: LogLevelsExample},
+		Example{Name: "SubLoggerExample", Package: "Log", Difficulty: Medium, Description: "Creating contextual child loggers", Run: SubLoggerExample},
+		Example{Name: "StructuredDataExample", Package: "Log", Difficulty: Medium, Description: "Logging complex data structures", Run: StructuredDataExample},
+		Example{Name: "LoggerOptionsExample", Package: "Log", Difficulty: Medium, Description: "Various logger configuration options", Run: LoggerOptionsExample},
+		Example{Name: "ApplicationLoggerExample", Package: "Log", Difficulty: Hard, Description: "Production-ready logging setup", Run: ApplicationLoggerExample},
+		Example{Name: "PerformanceLoggingExample", Package: "Log", Difficulty: Hard, Description: "Measuring and logging execution time", Run: PerformanceLoggingExample},
+		Example{Name: "ErrorTrackingExample", Package: "Log", Difficulty: Hard, Description: "Comprehensive error tracking with context", Run: ErrorTrackingExample},
+		Example{Name: "AuditLogExample", Package: "Log", Difficulty: Hard, Description: "Creating audit trails for compliance", Run: AuditLogExample},
+		Example{Name: "DistributedTracingExample", Package: "Log", Difficulty: Hard, Description: "Logging with trace IDs for distributed systems", Run: DistributedTracingExample},
+		Example{Name: "MultiSinkLoggingExample", Package: "Log", Difficulty: Hard, Description: "Console, rotating JSON file and an in-memory ring buffer with a live tail", Run: MultiSinkLoggingExample, Interactive: true},
+		Example{Name: "LogPlaygroundExample", Package: "Log", Difficulty: Hard, Description: "Live viewer for a simulated app: filter by level and field, find injected incidents", Run: LogPlaygroundExample, Interactive: true},
+	)
+}
+
 // RunAllLogExamples executes all log examples
 func RunAllLogExamples() {
 	fmt.Println("\n" + strings.Repeat("=", 70))
@@ -526,6 +1406,8 @@ func RunAllLogExamples() {
 	ErrorTrackingExample()
 	AuditLogExample()
 	DistributedTracingExample()
+	MultiSinkLoggingExample()
+	LogPlaygroundExample()
 
 	fmt.Println("\n" + strings.Repeat("=", 70))
 }
@@ -0,0 +1,177 @@
+package examples
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/endalk200/charm.poc/theme"
+)
+
+// ==============================================================================
+// PROGRESS - Quiz scores saved between runs
+// ==============================================================================
+
+// masteryStreak is how many correct answers in a row mark a concept as
+// mastered; one lucky guess is not enough, and a wrong answer resets it
+const masteryStreak = 2
+
+// conceptScore is the saved record for one concept
+type conceptScore struct {
+	Correct  int       `json:"correct"`
+	Attempts int       `json:"attempts"`
+	Streak   int       `json:"streak"`
+	Last     time.Time `json:"last"`
+}
+
+func (s *conceptScore) mastered() bool { return s != nil && s.Streak >= masteryStreak }
+
+// quizProgress is the whole progress file
+type quizProgress struct {
+	Concepts map[string]*conceptScore `json:"concepts"`
+	path     string
+}
+
+// configPath is where the examples keep a state file: the path in the
+// environment variable env if set, otherwise name in the user config
+// directory (~/.config/charm.poc on Linux)
+func configPath(env, name string) (string, error) {
+	if p := os.Getenv(env); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "charm.poc", name), nil
+}
+
+// loadProgress reads the progress file; a missing file is an empty record
+func loadProgress() (*quizProgress, error) {
+	path, err := configPath("CHARM_EXAMPLES_PROGRESS", "progress.json")
+	if err != nil {
+		return nil, err
+	}
+	p := &quizProgress{Concepts: map[string]*conceptScore{}, path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if p.Concepts == nil {
+		p.Concepts = map[string]*conceptScore{}
+	}
+	return p, nil
+}
+
+func (p *quizProgress) record(concept string, correct bool) {
+	s := p.Concepts[concept]
+	if s == nil {
+		s = &conceptScore{}
+		p.Concepts[concept] = s
+	}
+	s.Attempts++
+	s.Last = time.Now()
+	if correct {
+		s.Correct++
+		s.Streak++
+	} else {
+		s.Streak = 0
+	}
+}
+
+func (p *quizProgress) save() error {
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p.path, append(data, '\n'), 0o644)
+}
+
+// ProgressReport renders every quiz concept grouped by package, with its
+// score and whether it is mastered, below an overall progress bar
+func ProgressReport() (string, error) {
+	saved, err := loadProgress()
+	if err != nil {
+		return "", err
+	}
+
+	p := theme.Current()
+	var (
+		title    = lipgloss.NewStyle().Bold(true).Foreground(p.Primary)
+		heading  = lipgloss.NewStyle().Bold(true).Foreground(p.Secondary)
+		name     = lipgloss.NewStyle().Foreground(p.Text).Width(24)
+		score    = lipgloss.NewStyle().Foreground(p.Subtle).Width(14)
+		hint     = lipgloss.NewStyle().Foreground(p.Muted)
+		mastered = lipgloss.NewStyle().Foreground(p.Success).Render(theme.Symbol("✓", "[ok]") + " mastered")
+		learning = lipgloss.NewStyle().Foreground(p.Warning).Render(theme.Symbol("~", "[~]") + " learning")
+		untried  = lipgloss.NewStyle().Foreground(p.Muted).Render(theme.Symbol("·", "[ ]") + " not tried")
+	)
+
+	var (
+		lines       []string
+		total, done int
+	)
+	for _, pkg := range Packages() {
+		// A concept can be quizzed after several examples; list it once,
+		// pointing at the first example that teaches it
+		var rows []string
+		seen := map[string]bool{}
+		for _, e := range ByPackage(pkg) {
+			for _, q := range quizzes[e.Name] {
+				if seen[q.Concept] {
+					continue
+				}
+				seen[q.Concept] = true
+				total++
+
+				s := saved.Concepts[q.Concept]
+				status := untried
+				switch {
+				case s.mastered():
+					status = mastered
+					done++
+				case s != nil:
+					status = learning
+				}
+				result := "-"
+				if s != nil {
+					result = fmt.Sprintf("%d/%d correct", s.Correct, s.Attempts)
+				}
+				rows = append(rows, "  "+name.Render(q.Concept)+score.Render(result)+status+hint.Render("  "+e.Name))
+			}
+		}
+		if len(rows) > 0 {
+			lines = append(lines, "", heading.Render(pkg))
+			lines = append(lines, rows...)
+		}
+	}
+
+	const barWidth = 30
+	filled := 0
+	if total > 0 {
+		filled = barWidth * done / total
+	}
+	bar := lipgloss.NewStyle().Foreground(p.Success).Render(strings.Repeat(theme.Symbol("█", "#"), filled)) +
+		lipgloss.NewStyle().Foreground(p.Muted).Render(strings.Repeat(theme.Symbol("░", "-"), barWidth-filled))
+
+	header := title.Render("Learning Progress") + "\n" +
+		fmt.Sprintf("%s %d/%d concepts mastered", bar, done, total) + "\n" +
+		hint.Render(fmt.Sprintf("A concept is mastered after %d correct answers in a row. Scores: %s", masteryStreak, saved.path))
+	return header + "\n" + strings.Join(lines, "\n") + "\n", nil
+}
@@ -0,0 +1,359 @@
+package examples
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/endalk200/charm.poc/theme"
+)
+
+// ==============================================================================
+// QUIZ MODE - Checking what an example taught
+// ==============================================================================
+
+// Question is one multiple-choice question about the concept an example
+// demonstrates. Answer is the index of the correct option.
+type Question struct {
+	Concept string
+	Prompt  string
+	Options []string
+	Answer  int
+	Explain string // shown after answering, right or wrong
+}
+
+// quizzes maps an example name to the questions asked after it runs.
+// Examples without an entry simply have no quiz.
+var quizzes = map[string][]Question{
+	// Lipgloss
+	"SimpleLipglossExample": {{
+		Concept: "Styles and Render",
+		Prompt:  "What does style.Render(text) return?",
+		Options: []string{
+			"Nothing; it prints the text directly",
+			"The text wrapped in the escape sequences for the style",
+			"A new Style with the text attached",
+		},
+		Answer:  1,
+		Explain: "Render only builds a string. Printing it is up to you, which is why styled text can be joined, measured and nested.",
+	}},
+	"BasicColorsExample": {{
+		Concept: "Color formats",
+		Prompt:  "Which of these will NOT produce a color?",
+		Options: []string{
+			`lipgloss.Color("205")`,
+			`lipgloss.Color("#FF5F87")`,
+			`lipgloss.AdaptiveColor{Light: "162", Dark: "205"}`,
+			`lipgloss.Color("pink")`,
+		},
+		Answer:  3,
+		Explain: "Colors are ANSI numbers or hex values; names are not parsed. AdaptiveColor picks one of two values from the terminal background.",
+	}},
+	"SimpleBordersExample": {{
+		Concept: "Borders",
+		Prompt:  "Which method sets the color of a border?",
+		Options: []string{"Foreground", "BorderForeground", "BorderStyle", "Background"},
+		Answer:  1,
+		Explain: "Foreground colors the text inside; BorderForeground (and BorderBackground) color the border itself.",
+	}},
+	"PaddingAndMarginsExample": {
+		{
+			Concept: "Padding vs margin",
+			Prompt:  "A style has a border, Padding(1, 3) and Margin(1, 0). Where does the padding go?",
+			Options: []string{
+				"Outside the border: 1 line above and below, 3 columns left and right",
+				"Inside the border: 1 line above and below, 3 columns left and right",
+				"Inside the border: 1 column left, 3 columns right",
+			},
+			Answer:  1,
+			Explain: "Padding is space inside the border and margin is space outside it. With two values the first is vertical and the second horizontal, as in CSS.",
+		},
+		{
+			Concept: "Padding vs margin",
+			Prompt:  "Which area does Background(...) fill?",
+			Options: []string{
+				"The text only",
+				"The text and the padding",
+				"The text, the padding and the margin",
+			},
+			Answer:  1,
+			Explain: "The background covers the content and its padding; margins stay transparent (use MarginBackground to color them).",
+		},
+	},
+	"AlignmentExample": {{
+		Concept: "Alignment",
+		Prompt:  "Align(lipgloss.Center) on a single line of text has no visible effect unless...",
+		Options: []string{
+			"the style also has a Width wider than the text",
+			"the style also has a border",
+			"the terminal supports true color",
+		},
+		Answer:  0,
+		Explain: "Alignment positions text within the available width. A single line with no Width is already exactly as wide as itself.",
+	}},
+	"JoinExample": {{
+		Concept: "Joining blocks",
+		Prompt:  "In lipgloss.JoinHorizontal(lipgloss.Top, a, b), what is lipgloss.Top for?",
+		Options: []string{
+			"It puts a above b",
+			"It lines up blocks of different heights along their top edges",
+			"It adds a border on top of the joined result",
+		},
+		Answer:  1,
+		Explain: "Joined blocks are padded to the same height; the position says where the shorter ones sit. JoinVertical takes Left, Center or Right instead.",
+	}},
+	"StyleInheritanceExample": {{
+		Concept: "Style inheritance",
+		Prompt:  "base is a Style. After warn := base.Foreground(p.Warning), what is base's foreground?",
+		Options: []string{
+			"Unchanged: styles are values, so warn is a modified copy",
+			"Warning: both variables point at the same style",
+			"It is reset to the terminal default",
+		},
+		Answer:  0,
+		Explain: "Every setter returns a new Style, so a base style can be shared and extended without surprises.",
+	}},
+
+	// Log
+	"SimpleLogExample": {{
+		Concept: "Log levels",
+		Prompt:  "A logger created with log.New(os.Stderr) receives Debug, Info and Warn calls. What is printed?",
+		Options: []string{"All three", "Info and Warn", "Only Warn"},
+		Answer:  1,
+		Explain: "The default level is Info, so Debug messages are dropped until you call SetLevel(log.DebugLevel).",
+	}},
+	"LogLevelsExample": {{
+		Concept: "Level filtering",
+		Prompt:  "After logger.SetLevel(log.WarnLevel), which calls still print?",
+		Options: []string{
+			"Only Warn",
+			"Warn, Error and Fatal",
+			"Debug, Info and Warn",
+		},
+		Answer:  1,
+		Explain: "The level is a minimum: messages at that level or above print, everything below is filtered out.",
+	}},
+	"LogWithFieldsExample": {{
+		Concept: "Structured fields",
+		Prompt:  `In log.Info("saved", "id", 42, "user", "ada"), what are the arguments after the message?`,
+		Options: []string{
+			"Values for fmt-style placeholders in the message",
+			"Alternating keys and values, printed as id=42 user=ada",
+			"Tags used only to filter messages",
+		},
+		Answer:  1,
+		Explain: "Key/value pairs keep data out of the message text, so it stays greppable and machine-readable. Use Infof for placeholders.",
+	}},
+	"LogFormattingExample": {{
+		Concept: "Time formats",
+		Prompt:  `In logger.SetTimeFormat("15:04:05"), what is "15:04:05"?`,
+		Options: []string{
+			"Go's reference time (Mon Jan 2 15:04:05 2006) written the way timestamps should look",
+			"A fixed time printed on every line",
+			"strftime codes, like %H:%M:%S",
+		},
+		Answer:  0,
+		Explain: "Go layouts are examples of the reference time, so time.Kitchen (\"3:04PM\") and any other time package layout work too.",
+	}},
+	"SubLoggerExample": {{
+		Concept: "Sub-loggers",
+		Prompt:  `What does logger.With("request_id", id) return?`,
+		Options: []string{
+			"Nothing; it adds the field to logger from now on",
+			"A new logger that adds the field to every message; logger itself is unchanged",
+			"A copy of the last message with the field added",
+		},
+		Answer:  1,
+		Explain: "Child loggers carry context (a request, a component) without threading it through every call, and without leaking it into the parent.",
+	}},
+	"MultiSinkLoggingExample": {{
+		Concept: "Log sinks",
+		Prompt:  "Why does the ring buffer sink keep its level in a slog.LevelVar?",
+		Options: []string{
+			"So the level can be changed while other goroutines are logging",
+			"Because slog handlers cannot store a plain slog.Level",
+			"So that every sink shares the same level",
+		},
+		Answer:  0,
+		Explain: "Each sink filters on its own level. A LevelVar is safe to change at runtime, so the tail view can turn on debug output without a restart.",
+	}},
+	"LogPlaygroundExample": {{
+		Concept: "Reading logs",
+		Prompt:  "During an incident, service=api logs \"upstream call failed\" warnings with upstream=db. Where is the fault most likely?",
+		Options: []string{
+			"In db, the upstream that api is calling",
+			"In api, since it is the one logging",
+			"Nowhere: warnings are not failures",
+		},
+		Answer:  0,
+		Explain: "Callers report their dependency's failure. The upstream field points at db; its own errors (filter service=db level=error) carry the err that explains it.",
+	}},
+
+	// Huh
+	"SimpleInputExample": {{
+		Concept: "Binding values",
+		Prompt:  "How does a huh field hand its answer back to your code?",
+		Options: []string{
+			"form.Run() returns it",
+			"Value(&v) writes it into the variable you pass",
+			"It is sent as a tea.Msg",
+		},
+		Answer:  1,
+		Explain: "Fields are bound to your variables with Value(&v); after Run returns without an error they hold the answers.",
+	}},
+	"ValidationExample": {{
+		Concept: "Validation",
+		Prompt:  "A field's Validate function returns an error. What happens?",
+		Options: []string{
+			"form.Run() returns that error",
+			"The error is shown and the field keeps focus until the input is valid",
+			"The value is cleared and the form moves on",
+		},
+		Answer:  1,
+		Explain: "Validation is interactive: the message appears under the field and the user fixes the input in place.",
+	}},
+	"MultiPageFormExample": {{
+		Concept: "Groups",
+		Prompt:  "What is a huh.NewGroup?",
+		Options: []string{
+			"A page of fields; the form moves to the next group when one is completed",
+			"A set of radio buttons",
+			"A way to validate several fields together",
+		},
+		Answer:  0,
+		Explain: "A form is a sequence of groups and each group is shown as one page, which is how wizards are built.",
+	}},
+	"DynamicFormExample": {{
+		Concept: "Conditional fields",
+		Prompt:  "A select's options should depend on an earlier field in the same form. What does the dynamic form use?",
+		Options: []string{
+			"Two forms: the second is built after the first one returns",
+			"OptionsFunc(fn, &earlier), which re-runs fn whenever the bound value changes",
+			"A Validate function that rejects options that do not apply",
+		},
+		Answer:  1,
+		Explain: "The *Func variants (TitleFunc, OptionsFunc, PlaceholderFunc...) are bound to other values and recomputed live. WithHideFunc skips whole groups the same way.",
+	}},
+
+	// Bubble Tea
+	"CounterExample": {{
+		Concept: "The Elm architecture",
+		Prompt:  "Where does a Bubble Tea program change its state?",
+		Options: []string{
+			"In View, while drawing",
+			"In Update, which returns the new model",
+			"In a goroutine that edits the model directly",
+		},
+		Answer:  1,
+		Explain: "Messages go to Update, which returns the next model; View only renders it. Keeping changes in one place is what makes the loop predictable.",
+	}},
+	"DashboardExample": {{
+		Concept: "Commands",
+		Prompt:  "How does the dashboard refresh itself every 500ms?",
+		Options: []string{
+			"A goroutine sleeps and updates the model",
+			"Update returns a tea.Tick command, which sends a message later",
+			"View is called on a timer and fetches new data",
+		},
+		Answer:  1,
+		Explain: "Anything asynchronous is a tea.Cmd: it runs outside the loop and reports back with a message, which Update handles like a key press.",
+	}},
+
+	// Glamour
+	"WordWrapExample": {{
+		Concept: "Word wrap",
+		Prompt:  "What does glamour.WithWordWrap(0) do?",
+		Options: []string{"Wraps at the terminal width", "Disables wrapping", "Wraps every word onto its own line"},
+		Answer:  1,
+		Explain: "Zero turns reflowing off; pass the viewport width (as the pager does on every resize) to fit text to the screen.",
+	}},
+
+	// Animation
+	"SpringProgressExample": {{
+		Concept: "Springs",
+		Prompt:  "What does a damping ratio below 1 do to a harmonica spring?",
+		Options: []string{
+			"It overshoots the target a little before settling",
+			"It stops exactly at the target, as fast as possible",
+			"It never reaches the target",
+		},
+		Answer:  0,
+		Explain: "Below 1 is under-damped (bouncy), exactly 1 is critically damped (no overshoot), above 1 creeps in slowly.",
+	}},
+}
+
+// quizMode asks the questions for an example after it runs
+var quizMode bool
+
+// SetQuizMode turns quizzes after each example on or off
+func SetQuizMode(on bool) { quizMode = on }
+
+// QuizMode reports whether quizzes are on
+func QuizMode() bool { return quizMode }
+
+// HasQuiz reports whether the named example has questions
+func HasQuiz(name string) bool {
+	e, ok := Find(name)
+	return ok && len(quizzes[e.Name]) > 0
+}
+
+// RunQuiz asks the questions for the named example one at a time, explains
+// each answer and records the results. In demo mode the right answers are
+// picked automatically and nothing is recorded.
+func RunQuiz(name string) error {
+	e, ok := Find(name)
+	if !ok || len(quizzes[e.Name]) == 0 {
+		return nil
+	}
+	questions := quizzes[e.Name]
+
+	p := theme.Current()
+	var (
+		right   = lipgloss.NewStyle().Foreground(p.Success).Bold(true)
+		wrong   = lipgloss.NewStyle().Foreground(p.Error).Bold(true)
+		explain = lipgloss.NewStyle().Foreground(p.Subtle).PaddingLeft(2).Width(72)
+	)
+
+	saved, err := loadProgress()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== QUIZ: %s ===\n", e.Name)
+	score := 0
+	for i, q := range questions {
+		options := make([]huh.Option[int], len(q.Options))
+		for j, text := range q.Options {
+			options[j] = huh.NewOption(text, j)
+		}
+
+		picked := -1
+		form := huh.NewForm(huh.NewGroup(
+			huh.NewSelect[int]().
+				Title(fmt.Sprintf("%d/%d · %s", i+1, len(questions), q.Concept)).
+				Description(q.Prompt).
+				Options(options...).
+				Value(&picked),
+		))
+		if err := runForm(form, choose(q.Answer)); err != nil {
+			return err
+		}
+
+		correct := picked == q.Answer
+		if correct {
+			score++
+			fmt.Println(right.Render(theme.Symbol("✓", "[ok]") + " Correct"))
+		} else {
+			fmt.Println(wrong.Render(theme.Symbol("✗", "[x]")+" Not quite: ") + q.Options[q.Answer])
+		}
+		fmt.Println(explain.Render(q.Explain))
+		saved.record(q.Concept, correct)
+	}
+
+	fmt.Printf("\nScore: %d/%d\n", score, len(questions))
+	if demoMode {
+		fmt.Println("(demo mode: answers were scripted, so the score was not saved)")
+		return nil
+	}
+	return saved.save()
+}
@@ -0,0 +1,100 @@
+package examples
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ==============================================================================
+// REGISTRY - Metadata for every example so launchers don't hardcode them
+// ==============================================================================
+
+// Difficulty groups examples the same way the source files do
+type Difficulty string
+
+const (
+	Easy   Difficulty = "Easy"
+	Medium Difficulty = "Medium"
+	Hard   Difficulty = "Hard"
+)
+
+// Example describes a single runnable example function
+type Example struct {
+	Name        string // the function name, e.g. "TableExample"
+	Package     string // the charm package it demonstrates, e.g. "Lipgloss"
+	Difficulty  Difficulty
+	Description string
+	Run         func()
+
+	// Interactive examples are Bubble Tea programs that run until the user
+	// quits them, so they cannot run unattended (see --export)
+	Interactive bool
+}
+
+// registry holds examples in registration order, which is the order they
+// appear in their source file (easy → hard)
+var registry []Example
+
+// Register adds examples to the registry. Each example file calls it from
+// init(), so adding a new file is enough to make its examples show up in
+// the launcher and the CLI flags.
+func Register(examples ...Example) {
+	registry = append(registry, examples...)
+}
+
+// All returns every registered example
+func All() []Example {
+	return slices.Clone(registry)
+}
+
+// Find looks an example up by name, ignoring case
+func Find(name string) (Example, bool) {
+	for _, e := range registry {
+		if strings.EqualFold(e.Name, name) {
+			return e, true
+		}
+	}
+	return Example{}, false
+}
+
+// ByPackage returns the examples for one package, ignoring case
+func ByPackage(pkg string) []Example {
+	var out []Example
+	for _, e := range registry {
+		if strings.EqualFold(e.Package, pkg) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Packages lists the package names in the order they were first registered
+func Packages() []string {
+	var pkgs []string
+	for _, e := range registry {
+		if !slices.Contains(pkgs, e.Package) {
+			pkgs = append(pkgs, e.Package)
+		}
+	}
+	return pkgs
+}
+
+// RunPackage runs every example of a package in order under a banner
+func RunPackage(pkg string) error {
+	list := ByPackage(pkg)
+	if len(list) == 0 {
+		return fmt.Errorf("unknown package %q (available: %s)", pkg, strings.Join(Packages(), ", "))
+	}
+
+	fmt.Println("\n" + strings.Repeat("=", 70))
+	fmt.Println(strings.ToUpper(list[0].Package) + " EXAMPLES")
+	fmt.Println(strings.Repeat("=", 70))
+
+	for _, e := range list {
+		e.Run()
+	}
+
+	fmt.Println("\n" + strings.Repeat("=", 70))
+	return nil
+}
@@ -0,0 +1,85 @@
+package examples
+
+import (
+	"embed"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"sync"
+)
+
+// ==============================================================================
+// SOURCE - The code behind each example, for reading next to its output
+// ==============================================================================
+
+// The example files are embedded so the binary can show its own source
+// no matter where it is run from
+//
+//go:embed *.go
+var sourceFiles embed.FS
+
+// Snippet is the source of one top-level function, doc comment included
+type Snippet struct {
+	File string // e.g. "lipgloss.go"
+	Line int    // line of the first line of Code in File
+	Code string
+}
+
+var (
+	snippetsOnce sync.Once
+	snippets     map[string]Snippet
+	snippetsErr  error
+)
+
+// Source returns the source of the named top-level function. Example names
+// in the registry are function names, so Source(e.Name) shows the code of e.
+func Source(name string) (Snippet, error) {
+	snippetsOnce.Do(func() { snippets, snippetsErr = parseSources() })
+	if snippetsErr != nil {
+		return Snippet{}, snippetsErr
+	}
+
+	s, ok := snippets[name]
+	if !ok {
+		return Snippet{}, fmt.Errorf("no source found for %s", name)
+	}
+	return s, nil
+}
+
+// parseSources indexes every top-level function of the embedded files.
+// Methods are skipped: their names are not unique across files.
+func parseSources() (map[string]Snippet, error) {
+	paths, err := fs.Glob(sourceFiles, "*.go")
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]Snippet)
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		src, err := sourceFiles.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil {
+				continue
+			}
+			start := fn.Pos()
+			if fn.Doc != nil {
+				start = fn.Doc.Pos()
+			}
+			from, to := fset.Position(start), fset.Position(fn.End())
+			out[fn.Name.Name] = Snippet{File: path, Line: from.Line, Code: string(src[from.Offset:to.Offset])}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/endalk200/charm.poc/examples"
+	"github.com/endalk200/charm.poc/theme"
+	"github.com/muesli/termenv"
+)
+
+// ==============================================================================
+// EXPORT - Writing example output to files
+// ==============================================================================
+
+// exportExamples runs each example with its output captured and writes two
+// files per example into dir: <Name>.ansi with the escape sequences kept
+// (view it with `cat`) and <Name>.txt with them stripped (diff it).
+// Forms are answered by demo mode; examples that need a keyboard are skipped.
+func exportExamples(dir string, list []examples.Example) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	// Output goes to a pipe, which would normally turn colors off. Force
+	// them: full color for lipgloss styles, and CLICOLOR_FORCE for renderers
+	// that examples create themselves (loggers), which then use basic ANSI.
+	// With colors turned off on purpose, leave them off.
+	if !theme.NoColor() {
+		lipgloss.SetColorProfile(termenv.TrueColor)
+		if err := os.Setenv("CLICOLOR_FORCE", "1"); err != nil {
+			return err
+		}
+	}
+	examples.SetDemoMode(true)
+
+	for _, e := range list {
+		if e.Interactive {
+			fmt.Fprintf(os.Stderr, "skipped  %s (interactive)\n", e.Name)
+			continue
+		}
+
+		out, err := captureOutput(e.Run)
+		if err != nil {
+			return fmt.Errorf("%s: %w", e.Name, err)
+		}
+
+		base := filepath.Join(dir, e.Name)
+		if err := os.WriteFile(base+".ansi", out, 0o644); err != nil {
+			return err
+		}
+		if err := os.WriteFile(base+".txt", []byte(ansi.Strip(string(out))), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "exported %s\n", base+".{ansi,txt}")
+	}
+	return nil
+}
+
+// captureOutput runs fn with stdout and stderr redirected into one pipe, so
+// the capture interleaves them the way a terminal would show them
+func captureOutput(fn func()) ([]byte, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	copied := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(&buf, r)
+		copied <- err
+	}()
+
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = w, w
+	// The default logger binds to os.Stderr when first used, so point it at
+	// the pipe explicitly and back afterwards
+	log.SetOutput(w)
+	defer func() {
+		os.Stdout, os.Stderr = stdout, stderr
+		log.SetOutput(stderr)
+	}()
+
+	fn()
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := <-copied; err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), r.Close()
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	"github.com/endalk200/charm.poc/examples"
+	"github.com/endalk200/charm.poc/theme"
+)
+
+// ==============================================================================
+// MENU ITEMS - One entry per runnable example
+// ==============================================================================
+
+// menuItem is a single runnable entry in the launcher list: either one
+// registered example or a "run the whole package" shortcut.
+// It implements list.DefaultItem so the default delegate can render it.
+type menuItem struct {
+	name        string
+	pkg         string
+	difficulty  string
+	description string
+	source      string // function to show in the source pane, if any
+	run         func()
+}
+
+func (i menuItem) Title() string { return i.name }
+
+// Description shows the difficulty and package as a tag before the summary
+func (i menuItem) Description() string {
+	return fmt.Sprintf("[%s · %s] %s", i.difficulty, i.pkg, i.description)
+}
+
+// FilterValue makes the package and difficulty searchable, not just the name,
+// so typing "hard" or "huh" narrows the list as well
+func (i menuItem) FilterValue() string {
+	return i.name + " " + i.pkg + " " + i.difficulty + " " + i.description
+}
+
+// menuItems lists every registered example followed by one
+// "All <package> Examples" entry per package
+func menuItems() []list.Item {
+	var items []list.Item
+	for _, e := range examples.All() {
+		items = append(items, menuItem{e.Name, e.Package, string(e.Difficulty), e.Description, e.Name, e.Run})
+	}
+
+	for _, pkg := range examples.Packages() {
+		items = append(items, menuItem{
+			name:        "All " + pkg + " Examples",
+			pkg:         pkg,
+			difficulty:  "All",
+			description: "Run every " + strings.ToLower(pkg) + " example in order",
+			run:         func() { _ = examples.RunPackage(pkg) },
+		})
+	}
+	return items
+}
+
+// ==============================================================================
+// RUNNING EXAMPLES - Handing the terminal over and coming back
+// ==============================================================================
+
+// exampleExec adapts an example function to tea.ExecCommand.
+// tea.Exec releases the terminal (leaving the alt screen and raw mode) before
+// calling Run and restores it afterwards, so examples can print freely and
+// even start their own Bubble Tea programs (every huh form does).
+type exampleExec struct {
+	run        func()
+	name       string // the example being run, empty for package runs and screens
+	stdin      io.Reader
+	stdout     io.Writer
+	showSource bool // the user asked to see the source after the run
+}
+
+func (e *exampleExec) Run() error {
+	// The default logger detects its colors once, when first used; bring it
+	// in line with the colors setting, which may have changed since. This
+	// queries the terminal, so it must happen here and not while the menu
+	// is reading the keyboard.
+	log.SetColorProfile(lipgloss.ColorProfile())
+	e.run()
+
+	if e.name != "" && examples.QuizMode() {
+		if err := examples.RunQuiz(e.name); err != nil {
+			fmt.Fprintln(e.stdout, "Quiz:", err)
+		}
+	}
+
+	// Let the user read the output before the menu redraws over it
+	prompt := "\nPress Enter to return to the menu..."
+	if e.name != "" {
+		prompt = "\nPress Enter to return to the menu (s + Enter to read its source)..."
+	}
+	fmt.Fprint(e.stdout, prompt)
+	answer, err := bufio.NewReader(e.stdin).ReadString('\n')
+	e.showSource = strings.TrimSpace(answer) == "s"
+	return err
+}
+
+// runExample hands the terminal to e and reports back when it is done
+func runExample(e *exampleExec) tea.Cmd {
+	return tea.Exec(e, func(err error) tea.Msg {
+		return exampleFinishedMsg{err: err, showSource: e.showSource}
+	})
+}
+
+// showProgress prints the quiz progress screen
+func showProgress() {
+	report, err := examples.ProgressReport()
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Print("\n" + report)
+}
+
+func (e *exampleExec) SetStdin(r io.Reader)  { e.stdin = r }
+func (e *exampleExec) SetStdout(w io.Writer) { e.stdout = w }
+func (e *exampleExec) SetStderr(io.Writer)   {}
+
+type exampleFinishedMsg struct {
+	err        error
+	showSource bool
+}
+
+// ==============================================================================
+// LAUNCHER MODEL
+// ==============================================================================
+
+var (
+	appStyle = lipgloss.NewStyle().Padding(1, 2)
+
+	sourcePaneStyle        = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	focusedSourcePaneStyle = sourcePaneStyle
+)
+
+// launcher is the example menu. Pressing s splits the screen and shows the
+// source of the selected example next to the list; tab moves the keyboard
+// focus between the list and the source so the code can be scrolled.
+type launcher struct {
+	list   list.Model
+	source viewport.Model
+
+	width, height int
+	showSource    bool
+	focusSource   bool
+	shownSource   string // function currently loaded into the viewport
+}
+
+func newLauncher() launcher {
+	l := list.New(menuItems(), list.NewDefaultDelegate(), 0, 0)
+	l.Title = "CHARM PACKAGE EXAMPLES - Learning & Experimentation"
+	l.SetStatusBarItemName("example", "examples")
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "run")),
+			key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "source")),
+			key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "demo mode")),
+			key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "theme")),
+			key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "background")),
+			key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "colors")),
+			key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "accessible")),
+			key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "quiz mode")),
+			key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "progress")),
+			key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "record")),
+		}
+	}
+
+	m := launcher{list: l, source: viewport.New(0, 0)}
+	m.applyTheme()
+	return m
+}
+
+// applyTheme restyles the launcher from the current palette and the
+// accessibility settings. Examples read them when they run, so only the
+// launcher's own styles need redoing.
+func (m *launcher) applyTheme() {
+	p := theme.Current()
+
+	d := list.NewDefaultDelegate()
+	selected := theme.Border(lipgloss.NormalBorder())
+	d.Styles.SelectedTitle = d.Styles.SelectedTitle.BorderStyle(selected).Foreground(p.Primary).BorderForeground(p.Primary)
+	d.Styles.SelectedDesc = d.Styles.SelectedDesc.BorderStyle(selected).Foreground(p.Secondary).BorderForeground(p.Primary)
+	d.Styles.NormalTitle = d.Styles.NormalTitle.Foreground(p.Text)
+	d.Styles.NormalDesc = d.Styles.NormalDesc.Foreground(p.Muted)
+	m.list.SetDelegate(d)
+
+	m.list.Styles.Title = m.list.Styles.Title.Foreground(p.OnColor).Background(p.Secondary)
+
+	// The focused pane stands out by color; without colors, by its border
+	focused := lipgloss.RoundedBorder()
+	if theme.NoColor() || theme.Accessible() {
+		focused = lipgloss.DoubleBorder()
+	}
+	sourcePaneStyle = sourcePaneStyle.BorderStyle(theme.Border(lipgloss.RoundedBorder())).BorderForeground(p.Muted)
+	focusedSourcePaneStyle = sourcePaneStyle.BorderStyle(theme.Border(focused)).BorderForeground(p.Primary)
+}
+
+func (m launcher) Init() tea.Cmd { return nil }
+
+// resize lays out the list alone, or the list and the source side by side
+func (m *launcher) resize() {
+	h, v := appStyle.GetFrameSize()
+	width, height := m.width-h, m.height-v
+	if !m.showSource {
+		m.list.SetSize(width, height)
+		return
+	}
+
+	listWidth := width * 2 / 5
+	fh, fv := sourcePaneStyle.GetFrameSize()
+	m.list.SetSize(listWidth, height)
+	m.source.Width = width - listWidth - fh
+	m.source.Height = height - fv
+}
+
+// syncSource loads the selected example into the source pane if it changed
+func (m *launcher) syncSource() {
+	it, ok := m.list.SelectedItem().(menuItem)
+	if !ok || it.source == m.shownSource && m.shownSource != "" {
+		return
+	}
+	m.shownSource = it.source
+	if it.source == "" {
+		m.source.SetContent(commentStyle.Render("// " + it.name + " runs several examples;\n// select one to read its source"))
+	} else {
+		m.source.SetContent(sourceFor(it.source))
+	}
+	m.source.GotoTop()
+}
+
+func (m launcher) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.resize()
+
+	case tea.KeyMsg:
+		// While the filter input has focus, keys belong to it
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case "enter":
+			if it, ok := m.list.SelectedItem().(menuItem); ok {
+				return m, runExample(&exampleExec{run: it.run, name: it.source})
+			}
+		case "s":
+			m.showSource = !m.showSource
+			m.focusSource = false
+			m.resize()
+			m.syncSource()
+			return m, nil
+		case "d":
+			examples.SetDemoMode(!examples.DemoMode())
+			status := "demo mode off: forms wait for your input"
+			if examples.DemoMode() {
+				status = "demo mode on: forms fill themselves in"
+			}
+			return m, m.list.NewStatusMessage(status)
+		case "t":
+			p := theme.Next()
+			m.applyTheme()
+			return m, m.list.NewStatusMessage("theme: " + p.Name)
+		case "b":
+			bg := theme.NextBackground()
+			m.applyTheme()
+			return m, m.list.NewStatusMessage(fmt.Sprintf("background: %s (dark colors: %v)", bg, lipgloss.HasDarkBackground()))
+		case "c":
+			theme.SetNoColor(!theme.NoColor())
+			m.applyTheme()
+			status := "colors on"
+			if theme.NoColor() {
+				status = "colors off, as with NO_COLOR"
+			}
+			return m, m.list.NewStatusMessage(status)
+		case "a":
+			theme.SetAccessible(!theme.Accessible())
+			m.applyTheme()
+			status := "accessible mode off"
+			if theme.Accessible() {
+				status = "accessible mode on"
+			}
+			return m, m.list.NewStatusMessage(status)
+		case "e":
+			examples.SetQuizMode(!examples.QuizMode())
+			status := "quiz mode off"
+			if examples.QuizMode() {
+				status = "quiz mode on: questions after each example"
+			}
+			return m, m.list.NewStatusMessage(status)
+		case "p":
+			return m, runExample(&exampleExec{run: showProgress})
+		case "r":
+			it, ok := m.list.SelectedItem().(menuItem)
+			e, found := examples.Find(it.source)
+			if !ok || !found {
+				return m, m.list.NewStatusMessage("select a single example to record")
+			}
+			return m, runExample(&exampleExec{run: func() {
+				if err := recordExample(recordingsDir(), e); err != nil {
+					fmt.Println("Error:", err)
+				}
+			}})
+		case "tab":
+			if m.showSource {
+				m.focusSource = !m.focusSource
+				return m, nil
+			}
+		}
+		if m.focusSource {
+			if msg.String() == "q" || msg.String() == "esc" {
+				m.focusSource = false
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.source, cmd = m.source.Update(msg)
+			return m, cmd
+		}
+
+	case exampleFinishedMsg:
+		if msg.showSource && !m.showSource {
+			m.showSource = true
+			m.resize()
+			m.syncSource()
+		}
+		if msg.err != nil {
+			return m, m.list.NewStatusMessage("example failed: " + msg.err.Error())
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	if m.showSource {
+		m.syncSource()
+	}
+	return m, cmd
+}
+
+func (m launcher) View() string {
+	if !m.showSource {
+		return appStyle.Render(m.list.View())
+	}
+
+	style := sourcePaneStyle
+	if m.focusSource {
+		style = focusedSourcePaneStyle
+	}
+	return appStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top,
+		m.list.View(),
+		style.Render(m.source.View()),
+	))
+}
+
+// runLauncher shows the menu until the user quits
+func runLauncher() error {
+	_, err := tea.NewProgram(newLauncher(), tea.WithAltScreen(), tea.WithOutput(os.Stdout)).Run()
+	return err
+}
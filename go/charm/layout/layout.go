@@ -0,0 +1,213 @@
+// Package layout splits a terminal area into panes, flexbox style: each
+// pane takes a percentage of its parent (or an equal share of what is
+// left), never shrinks below its minimum size, and splits can be nested to
+// any depth.
+//
+// A layout is built once and rendered at whatever size the terminal has,
+// so a Bubble Tea program only needs to call Render with the size from the
+// latest tea.WindowSizeMsg:
+//
+//	root := layout.Columns(
+//		layout.Leaf(sidebar).Percent(25).Min(20),
+//		layout.Rows(
+//			layout.Leaf(editor),
+//			layout.Leaf(terminal).Percent(30).Min(5),
+//		),
+//	)
+//	view := root.Render(width, height)
+package layout
+
+import (
+	"github.com/charmbracelet/lipgloss"
+)
+
+// direction is how a split arranges its children
+type direction int
+
+const (
+	leaf    direction = iota
+	columns           // side by side, sharing the width
+	rows              // stacked, sharing the height
+)
+
+// Pane is a region of the screen: a leaf that draws content, or a split
+// that divides its area between child panes
+type Pane struct {
+	fixed    int // exact size, for bars and headers; overrides percent
+	percent  int // share of the parent, 0 for an equal share of the remainder
+	min      int // minimum cells along the parent's split direction
+	dir      direction
+	children []Pane
+	render   func(width, height int) string
+}
+
+// Leaf is a pane drawn by render, which is given the exact size the pane
+// ended up with. Output that does not fit is cut off; output that is too
+// small is padded.
+func Leaf(render func(width, height int) string) Pane {
+	return Pane{render: render}
+}
+
+// Columns places panes side by side
+func Columns(panes ...Pane) Pane {
+	return Pane{dir: columns, children: panes}
+}
+
+// Rows stacks panes from top to bottom
+func Rows(panes ...Pane) Pane {
+	return Pane{dir: rows, children: panes}
+}
+
+// Percent sets the share of the parent the pane asks for. Panes without one
+// split whatever the others leave equally.
+func (p Pane) Percent(n int) Pane {
+	p.percent = n
+	return p
+}
+
+// Fixed gives the pane exactly n cells (columns in Columns, rows in Rows),
+// for status bars, headers and other panes that should not grow
+func (p Pane) Fixed(n int) Pane {
+	p.fixed = n
+	return p
+}
+
+// Min sets the size (columns in Columns, rows in Rows) the pane never goes
+// below while there is room. When even the minimums do not fit, the last
+// panes are hidden first.
+func (p Pane) Min(n int) Pane {
+	p.min = n
+	return p
+}
+
+// Render draws the pane at exactly width x height cells
+func (p Pane) Render(width, height int) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	if p.dir == leaf {
+		return Fit(p.render(width, height), width, height)
+	}
+
+	total := width
+	if p.dir == rows {
+		total = height
+	}
+	sizes := Sizes(total, p.children)
+
+	var parts []string
+	for i, child := range p.children {
+		if sizes[i] == 0 {
+			continue
+		}
+		if p.dir == columns {
+			parts = append(parts, child.Render(sizes[i], height))
+		} else {
+			parts = append(parts, child.Render(width, sizes[i]))
+		}
+	}
+	if p.dir == columns {
+		return lipgloss.JoinHorizontal(lipgloss.Top, parts...)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// Sizes divides total cells between panes. Fixed sizes and percentages are
+// applied first and the rest is shared equally; then any pane under its minimum is raised
+// to it, taking the cells from panes that have room to spare. The sizes
+// always add up to total.
+func Sizes(total int, panes []Pane) []int {
+	sizes := make([]int, len(panes))
+	if total <= 0 || len(panes) == 0 {
+		return sizes
+	}
+
+	// Hide panes from the end until the minimums fit
+	visible := len(panes)
+	for visible > 1 && sumMin(panes[:visible]) > total {
+		visible--
+	}
+	shown := panes[:visible]
+
+	// Ask: fixed sizes and percentages first, then equal shares of what is
+	// left
+	used, flexible := 0, 0
+	for i, p := range shown {
+		switch {
+		case p.fixed > 0:
+			sizes[i] = p.fixed
+		case p.percent > 0:
+			sizes[i] = total * p.percent / 100
+		default:
+			flexible++
+			continue
+		}
+		used += sizes[i]
+	}
+	if flexible > 0 {
+		share := max(0, total-used) / flexible
+		for i, p := range shown {
+			if p.fixed == 0 && p.percent == 0 {
+				sizes[i] = share
+			}
+		}
+	}
+
+	// Enforce minimums, taking the cells from the largest panes that can
+	// give them up
+	for i, p := range shown {
+		for sizes[i] < p.min {
+			donor := -1
+			for j, q := range shown {
+				if j != i && sizes[j] > q.min && (donor < 0 || sizes[j] > sizes[donor]) {
+					donor = j
+				}
+			}
+			if donor < 0 {
+				break
+			}
+			sizes[donor]--
+			sizes[i]++
+		}
+	}
+
+	// Rounding leaves a few cells over, which go to the last visible pane
+	// that is not fixed; percentages adding up to more than 100 are trimmed
+	// from the largest
+	sum := 0
+	for _, s := range sizes {
+		sum += s
+	}
+	for ; sum > total; sum-- {
+		largest := 0
+		for j := range shown {
+			if sizes[j] > sizes[largest] {
+				largest = j
+			}
+		}
+		sizes[largest]--
+	}
+	last := visible - 1
+	for last > 0 && shown[last].fixed > 0 {
+		last--
+	}
+	sizes[last] += total - sum
+	return sizes
+}
+
+func sumMin(panes []Pane) int {
+	n := 0
+	for _, p := range panes {
+		n += max(p.min, p.fixed, 1)
+	}
+	return n
+}
+
+// Fit pads or cuts s to exactly width x height cells, so panes line up
+// whatever their content is
+func Fit(s string, width, height int) string {
+	return lipgloss.NewStyle().
+		Width(width).Height(height).
+		MaxWidth(width).MaxHeight(height).
+		Render(s)
+}
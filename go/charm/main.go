@@ -1,51 +1,136 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
 	"os"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/endalk200/charm.poc/examples"
+	"github.com/endalk200/charm.poc/theme"
 )
 
 func main() {
-	reader := bufio.NewReader(os.Stdin)
-
-	for {
-		fmt.Println("\n" + strings.Repeat("=", 70))
-		fmt.Println("CHARM PACKAGE EXAMPLES - Learning & Experimentation")
-		fmt.Println(strings.Repeat("=", 70))
-		fmt.Println("\nSelect which package examples to run:")
-		fmt.Println("1. Lipgloss - Terminal UI Styling")
-		fmt.Println("2. Log - Structured Logging")
-		fmt.Println("3. Huh - Interactive Forms")
-		fmt.Println("4. All Examples")
-		fmt.Println("5. Exit")
-		fmt.Print("\nEnter your choice (1-5): ")
-
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(input)
-
-		switch input {
-		case "1":
-			examples.RunAllLipglossExamples()
-		case "2":
-			examples.RunAllLogExamples()
-		case "3":
-			examples.RunAllHuhExamples()
-		case "4":
-			examples.RunAllLipglossExamples()
-			examples.RunAllLogExamples()
-			examples.RunAllHuhExamples()
-		case "5":
-			fmt.Println("\nGoodbye! 👋")
-			return
-		default:
-			fmt.Println("\n❌ Invalid choice. Please enter a number between 1 and 5.")
-		}
-
-		fmt.Print("\nPress Enter to continue...")
-		reader.ReadString('\n')
+	var (
+		listFlag = flag.Bool("list", false, "list available examples and exit")
+		runFlag  = flag.String("run", "", "run a single example by name, e.g. TableExample")
+		pkgFlag  = flag.String("package", "", "with --list, only list this package; alone, run all of its examples")
+		demoFlag = flag.Bool("demo", false, "answer form examples automatically from a script (autoplay)")
+		export   = flag.String("export", "", "run the examples selected by --run/--package (default all) and write their output to `dir`")
+		record   = flag.String("record", "", "with --run, record the run as an asciinema cast and a VHS tape in `dir`")
+		themeArg = flag.String("theme", "", "color palette: "+strings.Join(theme.Names(), ", "))
+		quizFlag = flag.Bool("quiz", false, "with --run, ask a few questions about the example afterwards")
+		progress = flag.Bool("progress", false, "show which quiz concepts are mastered and exit")
+		noColor  = flag.Bool("no-color", false, "render without colors, as when NO_COLOR is set")
+		access   = flag.Bool("accessible", false, "screen reader friendly forms, ASCII borders and icons, no animation")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [--list] [--run NAME] [--package PKG] [--demo] [--quiz] [--progress] [--export DIR] [--record DIR] [--theme NAME] [--no-color] [--accessible]\n\n", os.Args[0])
+		fmt.Fprintln(flag.CommandLine.Output(), "Without flags, opens the interactive launcher.")
+		fmt.Fprintln(flag.CommandLine.Output())
+		flag.PrintDefaults()
 	}
+	flag.Parse()
+
+	examples.SetDemoMode(*demoFlag)
+	examples.SetQuizMode(*quizFlag)
+	theme.SetAccessible(*access)
+	if *noColor || theme.NoColor() {
+		theme.SetNoColor(true)
+	}
+	if *themeArg != "" {
+		if err := theme.Set(*themeArg); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := run(*listFlag, *progress, *runFlag, *pkgFlag, *export, *record); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// run dispatches between the non-interactive modes and the launcher
+func run(list, progress bool, name, pkg, export, record string) error {
+	switch {
+	case list:
+		return listExamples(pkg)
+	case progress:
+		report, err := examples.ProgressReport()
+		if err != nil {
+			return err
+		}
+		fmt.Print(report)
+		return nil
+	case export != "":
+		selected, err := selectExamples(name, pkg)
+		if err != nil {
+			return err
+		}
+		return exportExamples(export, selected)
+	case record != "":
+		e, ok := examples.Find(name)
+		if !ok {
+			return fmt.Errorf("--record needs an example to record: --run NAME (see --list)")
+		}
+		return recordExample(record, e)
+	case name != "":
+		e, ok := examples.Find(name)
+		if !ok {
+			return fmt.Errorf("unknown example %q (see --list)", name)
+		}
+		e.Run()
+		if examples.QuizMode() {
+			return examples.RunQuiz(e.Name)
+		}
+		return nil
+	case pkg != "":
+		return examples.RunPackage(pkg)
+	}
+
+	if err := runLauncher(); err != nil {
+		return err
+	}
+	fmt.Println("\nGoodbye! 👋")
+	return nil
+}
+
+// selectExamples resolves --run and --package to a list of examples;
+// with neither, every example is selected
+func selectExamples(name, pkg string) ([]examples.Example, error) {
+	switch {
+	case name != "":
+		e, ok := examples.Find(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown example %q (see --list)", name)
+		}
+		return []examples.Example{e}, nil
+	case pkg != "":
+		list := examples.ByPackage(pkg)
+		if len(list) == 0 {
+			return nil, fmt.Errorf("unknown package %q (available: %s)", pkg, strings.Join(examples.Packages(), ", "))
+		}
+		return list, nil
+	}
+	return examples.All(), nil
+}
+
+// listExamples prints a table of examples, optionally for one package only
+func listExamples(pkg string) error {
+	list := examples.All()
+	if pkg != "" {
+		list = examples.ByPackage(pkg)
+		if len(list) == 0 {
+			return fmt.Errorf("unknown package %q (available: %s)", pkg, strings.Join(examples.Packages(), ", "))
+		}
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tPACKAGE\tDIFFICULTY\tDESCRIPTION")
+	for _, e := range list {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.Name, e.Package, e.Difficulty, e.Description)
+	}
+	return tw.Flush()
 }
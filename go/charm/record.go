@@ -0,0 +1,344 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/creack/pty"
+	"github.com/endalk200/charm.poc/examples"
+	"github.com/endalk200/charm.poc/theme"
+	"github.com/muesli/cancelreader"
+	"golang.org/x/term"
+)
+
+// ==============================================================================
+// RECORD - Example runs as asciinema casts, VHS tapes and GIFs
+// ==============================================================================
+
+// recordingsDir is where the launcher saves recordings:
+// $CHARM_EXAMPLES_RECORDINGS, or ./recordings
+func recordingsDir() string {
+	if dir := os.Getenv("CHARM_EXAMPLES_RECORDINGS"); dir != "" {
+		return dir
+	}
+	return "recordings"
+}
+
+// recordExample runs one example in a pseudo-terminal the size of the real
+// one, passing the keyboard through and showing its output as usual, and
+// writes the session into dir:
+//
+//   - <Name>.cast, the output with its timing in asciinema's v2 format
+//     (`asciinema play` it, or upload it)
+//   - <Name>.tape, a VHS script that runs the example again and presses the
+//     same keys at the same moments
+//
+// If agg or vhs is installed, either one is then used to render <Name>.gif.
+func recordExample(dir string, e examples.Example) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	// The example runs as a separate process (this program with --run) so it
+	// gets a terminal of its own, and Bubble Tea programs inside it see a
+	// real terminal size
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := recordArgs(e.Name)
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width, height = 100, 30
+	}
+	cmd := exec.Command(exe, args...)
+	ptmx, err := pty.StartWithSize(cmd, &pty.Winsize{Cols: uint16(width), Rows: uint16(height)})
+	if err != nil {
+		return fmt.Errorf("starting %s: %w", e.Name, err)
+	}
+	defer ptmx.Close()
+
+	// Keys go to the example one at a time, as it would get them directly
+	if state, err := term.MakeRaw(int(os.Stdin.Fd())); err == nil {
+		defer term.Restore(int(os.Stdin.Fd()), state)
+	}
+
+	rec := &recording{start: time.Now(), width: width, height: height}
+
+	// The keyboard is read through a cancelable reader: a plain Read would
+	// still be waiting when the example ends, and swallow the next key
+	stdin, err := cancelreader.NewReader(os.Stdin)
+	if err != nil {
+		return err
+	}
+	defer stdin.Close()
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := stdin.Read(buf)
+			if n > 0 {
+				rec.input(buf[:n])
+				ptmx.Write(buf[:n])
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// Reading the terminal fails once the example exits; that is the end of
+	// the recording, not an error
+	io.Copy(io.MultiWriter(os.Stdout, rec), ptmx)
+	rec.length = time.Since(rec.start)
+	stdin.Cancel()
+	runErr := cmd.Wait()
+
+	base := filepath.Join(dir, e.Name)
+	if err := rec.writeCast(base+".cast", e.Name); err != nil {
+		return err
+	}
+	if err := rec.writeTape(base+".tape", base+".gif", args); err != nil {
+		return err
+	}
+	fmt.Printf("\r\nRecorded %s in %s.{cast,tape}\r\n", e.Name, base)
+
+	switch gif, err := renderGIF(base); {
+	case errors.Is(err, errNoRenderer):
+		fmt.Print("Install agg or vhs to render a GIF from it\r\n")
+	case err != nil:
+		fmt.Printf("Rendering the GIF failed: %v\r\n", err)
+	default:
+		fmt.Printf("Rendered %s\r\n", gif)
+	}
+	if runErr != nil {
+		return fmt.Errorf("%s: %w", e.Name, runErr)
+	}
+	return nil
+}
+
+// recordArgs is the command line that runs the example the way the launcher
+// is set up right now: same demo mode, theme and rendering settings
+func recordArgs(name string) []string {
+	args := []string{"--run", name, "--theme", theme.Current().Name}
+	if examples.DemoMode() {
+		args = append(args, "--demo")
+	}
+	if examples.QuizMode() {
+		args = append(args, "--quiz")
+	}
+	if theme.NoColor() {
+		args = append(args, "--no-color")
+	}
+	if theme.Accessible() {
+		args = append(args, "--accessible")
+	}
+	return args
+}
+
+// recording collects output and keyboard events with the time they happened
+type recording struct {
+	mu            sync.Mutex
+	start         time.Time
+	width, height int
+	length        time.Duration
+	output        [][2]any // asciinema events: seconds, data
+	keys          []keyEvent
+	pending       []byte // an incomplete UTF-8 character at the end of the last write
+}
+
+type keyEvent struct {
+	at   time.Duration
+	data string
+}
+
+// Write records a chunk of output. JSON strings must be valid UTF-8, so a
+// character split across two writes is held back until it is complete.
+func (r *recording) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data := append(r.pending, p...)
+	cut := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	r.pending = append([]byte(nil), data[cut:]...)
+	if cut > 0 {
+		r.output = append(r.output, [2]any{time.Since(r.start).Seconds(), string(data[:cut])})
+	}
+	return len(p), nil
+}
+
+func (r *recording) input(p []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = append(r.keys, keyEvent{at: time.Since(r.start), data: string(p)})
+}
+
+// writeCast writes the asciinema v2 file: a JSON header line, then one
+// [time, "o", data] line per chunk of output
+func (r *recording) writeCast(path, title string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	header := map[string]any{
+		"version":   2,
+		"width":     r.width,
+		"height":    r.height,
+		"timestamp": r.start.Unix(),
+		"title":     title,
+		"env":       map[string]string{"TERM": os.Getenv("TERM"), "SHELL": os.Getenv("SHELL")},
+	}
+	if err := enc.Encode(header); err != nil {
+		f.Close()
+		return err
+	}
+	for _, ev := range r.output {
+		if err := enc.Encode([]any{ev[0], "o", ev[1]}); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// writeTape writes a VHS script that replays the session: it types the
+// command that runs the example, then presses the recorded keys with the
+// recorded pauses between them
+func (r *recording) writeTape(path, gif string, args []string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Replays a recorded run of the example. Render it from go/charm with:\n#   vhs %s\n\n", path)
+	fmt.Fprintf(&b, "Output %s\n", gif)
+	// VHS sizes the window in pixels; at font size 16 a cell is about
+	// 10x20 pixels
+	fmt.Fprintf(&b, "Set FontSize 16\nSet Width %d\nSet Height %d\nSet TypingSpeed 40ms\n\n", r.width*10+40, r.height*20+40)
+	fmt.Fprintf(&b, "Type %s\nEnter\n", tapeString("go run . "+strings.Join(args, " ")))
+
+	var last time.Duration
+	for _, k := range r.keys {
+		cmds := tapeKeys(k.data)
+		if len(cmds) == 0 {
+			continue
+		}
+		if pause := (k.at - last).Round(100 * time.Millisecond); pause > 0 {
+			fmt.Fprintf(&b, "Sleep %dms\n", pause.Milliseconds())
+		}
+		last = k.at
+		for _, c := range cmds {
+			b.WriteString(c + "\n")
+		}
+	}
+	// Keep filming until the example ended, plus a moment to read the end
+	end := r.length - last + time.Second
+	fmt.Fprintf(&b, "Sleep %dms\n", end.Round(100*time.Millisecond).Milliseconds())
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// tapeNames maps the escape sequences of special keys to VHS key commands
+var tapeNames = map[string]string{
+	"\r": "Enter", "\n": "Enter", "\t": "Tab", " ": "Space", "\x7f": "Backspace", "\x1b": "Escape",
+	"\x1b[A": "Up", "\x1b[B": "Down", "\x1b[C": "Right", "\x1b[D": "Left",
+	"\x1bOA": "Up", "\x1bOB": "Down", "\x1bOC": "Right", "\x1bOD": "Left",
+	"\x1b[Z": "Shift+Tab", "\x1b[5~": "PageUp", "\x1b[6~": "PageDown",
+}
+
+// tapeKeys turns raw keyboard input into VHS commands. Runs of ordinary
+// characters become one Type; escape sequences that are not keys (the
+// terminal answering a color or cursor query) are dropped.
+func tapeKeys(data string) []string {
+	var (
+		cmds []string
+		text strings.Builder
+	)
+	flush := func() {
+		if text.Len() > 0 {
+			cmds = append(cmds, "Type "+tapeString(text.String()))
+			text.Reset()
+		}
+	}
+
+	for len(data) > 0 {
+		seq := data[:1]
+		switch {
+		case strings.HasPrefix(data, "\x1b]"):
+			// Operating system command, ended by BEL or ESC \
+			end := strings.IndexByte(data, '\a')
+			if st := strings.Index(data, "\x1b\\"); st >= 0 && (end < 0 || st < end) {
+				end = st + 1
+			}
+			if end < 0 {
+				end = len(data) - 1
+			}
+			seq = data[:end+1]
+		case strings.HasPrefix(data, "\x1b[") || strings.HasPrefix(data, "\x1bO"):
+			// Control sequence: parameters, then a final byte from @ to ~
+			i := 2
+			for i < len(data) && (data[i] < 0x40 || data[i] > 0x7e) {
+				i++
+			}
+			seq = data[:min(i+1, len(data))]
+		default:
+			_, size := utf8.DecodeRuneInString(data)
+			seq = data[:size]
+		}
+		data = data[len(seq):]
+
+		switch name, ok := tapeNames[seq]; {
+		case ok:
+			flush()
+			cmds = append(cmds, name)
+		case len(seq) == 1 && seq[0] < 0x20:
+			flush()
+			cmds = append(cmds, "Ctrl+"+string(rune('A'+seq[0]-1)))
+		case seq[0] != 0x1b:
+			text.WriteString(seq)
+		}
+	}
+	flush()
+	return cmds
+}
+
+// tapeString quotes s for a tape, with backticks when it contains quotes
+func tapeString(s string) string {
+	if strings.Contains(s, `"`) {
+		return "`" + s + "`"
+	}
+	return `"` + s + `"`
+}
+
+var errNoRenderer = errors.New("neither agg nor vhs is installed")
+
+// renderGIF turns a recording into base.gif. agg draws the cast exactly as
+// recorded; vhs runs the tape, which starts the example again, so it is
+// slower and only used without agg.
+func renderGIF(base string) (string, error) {
+	gif := base + ".gif"
+	var cmd *exec.Cmd
+	if agg, err := exec.LookPath("agg"); err == nil {
+		cmd = exec.Command(agg, base+".cast", gif)
+	} else if vhs, err := exec.LookPath("vhs"); err == nil {
+		cmd = exec.Command(vhs, base+".tape")
+	} else {
+		return "", errNoRenderer
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("%w\n%s", err, out)
+	}
+	return gif, nil
+}
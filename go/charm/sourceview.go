@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/endalk200/charm.poc/examples"
+)
+
+// ==============================================================================
+// SOURCE VIEW - Syntax-highlighted code of the selected example
+// ==============================================================================
+
+var (
+	keywordStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	stringStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+	numberStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("215"))
+	commentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Italic(true)
+	callStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("75"))
+	lineNoStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
+)
+
+// tokenStyle picks the style for a token; ok is false for plain tokens
+// (operators, punctuation and ordinary identifiers)
+func tokenStyle(tok token.Token, next token.Token) (lipgloss.Style, bool) {
+	switch {
+	case tok.IsKeyword():
+		return keywordStyle, true
+	case tok == token.STRING || tok == token.CHAR:
+		return stringStyle, true
+	case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+		return numberStyle, true
+	case tok == token.COMMENT:
+		return commentStyle, true
+	case tok == token.IDENT && next == token.LPAREN:
+		return callStyle, true
+	}
+	return lipgloss.Style{}, false
+}
+
+// highlightGo colors Go source with go/scanner. Text between tokens
+// (whitespace) is copied verbatim, so the layout of the source is kept.
+func highlightGo(src string) string {
+	type span struct {
+		tok      token.Token
+		from, to int
+	}
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, []byte(src), nil, scanner.ScanComments)
+
+	var spans []span
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		// Automatically inserted semicolons have no text of their own
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+		n := len(lit)
+		if n == 0 {
+			n = len(tok.String())
+		}
+		from := file.Offset(pos)
+		spans = append(spans, span{tok, from, min(from+n, len(src))})
+	}
+
+	var b strings.Builder
+	last := 0
+	for i, sp := range spans {
+		next := token.ILLEGAL
+		if i+1 < len(spans) {
+			next = spans[i+1].tok
+		}
+		b.WriteString(src[last:sp.from])
+		text := src[sp.from:sp.to]
+		if style, ok := tokenStyle(sp.tok, next); ok {
+			// Style line by line: rendering a multi-line string would pad
+			// every line to the width of the longest one
+			lines := strings.Split(text, "\n")
+			for j, line := range lines {
+				lines[j] = style.Render(line)
+			}
+			text = strings.Join(lines, "\n")
+		}
+		b.WriteString(text)
+		last = sp.to
+	}
+	b.WriteString(src[last:])
+	return b.String()
+}
+
+// renderSnippet highlights a snippet and numbers its lines as they are
+// numbered in the original file
+func renderSnippet(s examples.Snippet) string {
+	lines := strings.Split(highlightGo(s.Code), "\n")
+	width := len(fmt.Sprint(s.Line + len(lines)))
+
+	var b strings.Builder
+	b.WriteString(commentStyle.Render("// examples/"+s.File) + "\n\n")
+	for i, line := range lines {
+		b.WriteString(lineNoStyle.Render(fmt.Sprintf("%*d ", width, s.Line+i)) + line + "\n")
+	}
+	return b.String()
+}
+
+// sourceFor returns the rendered source of an example, or a short note when
+// the menu entry has no single function behind it
+func sourceFor(name string) string {
+	s, err := examples.Source(name)
+	if err != nil {
+		return commentStyle.Render("// " + err.Error())
+	}
+	return renderSnippet(s)
+}
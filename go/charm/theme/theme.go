@@ -0,0 +1,309 @@
+// Package theme holds the color palettes shared by every example and the
+// launcher, so the whole collection can be re-themed in one place.
+//
+// Every color is a lipgloss.AdaptiveColor: it has one value for light
+// terminal backgrounds and one for dark ones, and lipgloss picks between
+// them when a style is rendered. SetBackground overrides that detection,
+// which is how the launcher shows both variants without changing terminals.
+//
+// The package also holds the two settings for restricted terminals, NoColor
+// and Accessible, which every example checks to render a plainer version of
+// itself.
+package theme
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// ==============================================================================
+// PALETTES
+// ==============================================================================
+
+// Palette names colors by role rather than by hue, so examples say
+// "this is an error" and each palette decides what an error looks like
+type Palette struct {
+	Name string
+
+	Primary   lipgloss.AdaptiveColor // titles, focused elements
+	Secondary lipgloss.AdaptiveColor // borders, headers, secondary accents
+
+	Success   lipgloss.AdaptiveColor
+	Warning   lipgloss.AdaptiveColor
+	Error     lipgloss.AdaptiveColor
+	Info      lipgloss.AdaptiveColor
+	Highlight lipgloss.AdaptiveColor // attention without alarm
+
+	Text    lipgloss.AdaptiveColor // body text
+	Subtle  lipgloss.AdaptiveColor // secondary text
+	Muted   lipgloss.AdaptiveColor // hints, dividers, inactive borders
+	Surface lipgloss.AdaptiveColor // panel and row backgrounds
+	OnColor lipgloss.AdaptiveColor // text drawn on a colored background
+}
+
+// Charm is the default palette, matching the colors the examples were
+// written with on dark terminals
+var Charm = Palette{
+	Name:      "Charm",
+	Primary:   lipgloss.AdaptiveColor{Light: "162", Dark: "205"},
+	Secondary: lipgloss.AdaptiveColor{Light: "57", Dark: "63"},
+	Success:   lipgloss.AdaptiveColor{Light: "28", Dark: "46"},
+	Warning:   lipgloss.AdaptiveColor{Light: "166", Dark: "214"},
+	Error:     lipgloss.AdaptiveColor{Light: "160", Dark: "196"},
+	Info:      lipgloss.AdaptiveColor{Light: "30", Dark: "86"},
+	Highlight: lipgloss.AdaptiveColor{Light: "136", Dark: "226"},
+	Text:      lipgloss.AdaptiveColor{Light: "235", Dark: "252"},
+	Subtle:    lipgloss.AdaptiveColor{Light: "240", Dark: "250"},
+	Muted:     lipgloss.AdaptiveColor{Light: "247", Dark: "241"},
+	Surface:   lipgloss.AdaptiveColor{Light: "254", Dark: "235"},
+	OnColor:   lipgloss.AdaptiveColor{Light: "231", Dark: "0"},
+}
+
+// Ocean is a calm blue and teal palette
+var Ocean = Palette{
+	Name:      "Ocean",
+	Primary:   lipgloss.AdaptiveColor{Light: "25", Dark: "39"},
+	Secondary: lipgloss.AdaptiveColor{Light: "30", Dark: "37"},
+	Success:   lipgloss.AdaptiveColor{Light: "29", Dark: "79"},
+	Warning:   lipgloss.AdaptiveColor{Light: "130", Dark: "179"},
+	Error:     lipgloss.AdaptiveColor{Light: "124", Dark: "203"},
+	Info:      lipgloss.AdaptiveColor{Light: "31", Dark: "117"},
+	Highlight: lipgloss.AdaptiveColor{Light: "61", Dark: "147"},
+	Text:      lipgloss.AdaptiveColor{Light: "236", Dark: "254"},
+	Subtle:    lipgloss.AdaptiveColor{Light: "241", Dark: "152"},
+	Muted:     lipgloss.AdaptiveColor{Light: "248", Dark: "66"},
+	Surface:   lipgloss.AdaptiveColor{Light: "195", Dark: "17"},
+	OnColor:   lipgloss.AdaptiveColor{Light: "231", Dark: "16"},
+}
+
+// Mono uses only grays and relies on bold and borders for emphasis, which
+// is a good check that no example carries meaning in color alone
+var Mono = Palette{
+	Name:      "Mono",
+	Primary:   lipgloss.AdaptiveColor{Light: "232", Dark: "255"},
+	Secondary: lipgloss.AdaptiveColor{Light: "238", Dark: "250"},
+	Success:   lipgloss.AdaptiveColor{Light: "234", Dark: "253"},
+	Warning:   lipgloss.AdaptiveColor{Light: "236", Dark: "251"},
+	Error:     lipgloss.AdaptiveColor{Light: "232", Dark: "255"},
+	Info:      lipgloss.AdaptiveColor{Light: "238", Dark: "249"},
+	Highlight: lipgloss.AdaptiveColor{Light: "234", Dark: "253"},
+	Text:      lipgloss.AdaptiveColor{Light: "235", Dark: "252"},
+	Subtle:    lipgloss.AdaptiveColor{Light: "241", Dark: "248"},
+	Muted:     lipgloss.AdaptiveColor{Light: "247", Dark: "242"},
+	Surface:   lipgloss.AdaptiveColor{Light: "254", Dark: "236"},
+	OnColor:   lipgloss.AdaptiveColor{Light: "231", Dark: "232"},
+}
+
+var (
+	palettes = []Palette{Charm, Ocean, Mono}
+	current  = 0
+)
+
+// Current returns the active palette. Examples call it when they build
+// their styles, so a theme change applies to the next example run.
+func Current() Palette { return palettes[current] }
+
+// Names lists the available palettes
+func Names() []string {
+	names := make([]string, len(palettes))
+	for i, p := range palettes {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// Set activates a palette by name, ignoring case
+func Set(name string) error {
+	for i, p := range palettes {
+		if strings.EqualFold(p.Name, name) {
+			current = i
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(Names(), ", "))
+}
+
+// Next activates the palette after the current one and returns it
+func Next() Palette {
+	current = (current + 1) % len(palettes)
+	return Current()
+}
+
+// ==============================================================================
+// BACKGROUND - Which half of each AdaptiveColor is used
+// ==============================================================================
+
+// Background selects the Light or Dark value of every AdaptiveColor
+type Background int
+
+const (
+	Auto  Background = iota // detect from the terminal (the default)
+	Dark                    // force the Dark values
+	Light                   // force the Light values
+)
+
+func (b Background) String() string {
+	return [...]string{"auto", "dark", "light"}[b]
+}
+
+var (
+	background Background
+	detected   *bool // the terminal's own answer, remembered before overriding it
+)
+
+// CurrentBackground returns the active background mode
+func CurrentBackground() Background { return background }
+
+// SetBackground forces the light or dark variant of every color, or goes
+// back to what the terminal reports
+func SetBackground(b Background) {
+	if detected == nil {
+		dark := lipgloss.HasDarkBackground()
+		detected = &dark
+	}
+
+	background = b
+	switch b {
+	case Dark:
+		lipgloss.SetHasDarkBackground(true)
+	case Light:
+		lipgloss.SetHasDarkBackground(false)
+	default:
+		lipgloss.SetHasDarkBackground(*detected)
+	}
+}
+
+// NextBackground cycles auto → dark → light and returns the new mode
+func NextBackground() Background {
+	SetBackground((background + 1) % 3)
+	return background
+}
+
+// ==============================================================================
+// ACCESSIBILITY - Rendering for restricted terminals
+// ==============================================================================
+
+var (
+	// noColor starts from the NO_COLOR convention (https://no-color.org)
+	noColor    = os.Getenv("NO_COLOR") != ""
+	accessible bool
+)
+
+// NoColor reports whether colors are off. Styles still apply bold,
+// underline and layout; only the colors are dropped.
+func NoColor() bool { return noColor }
+
+// SetNoColor turns colors off or back on. Lipgloss styles follow the color
+// profile set here; NO_COLOR is set as well, so loggers and other renderers
+// that examples create themselves make the same choice.
+func SetNoColor(on bool) {
+	noColor = on
+	if on {
+		os.Setenv("NO_COLOR", "1")
+		lipgloss.SetColorProfile(termenv.Ascii)
+		return
+	}
+	os.Unsetenv("NO_COLOR")
+	lipgloss.SetColorProfile(termenv.NewOutput(os.Stdout).EnvColorProfile())
+}
+
+// Accessible reports whether examples should render for screen readers and
+// reduced motion: forms ask plain line-by-line questions, borders and icons
+// use ASCII, and animations jump straight to where they would settle
+func Accessible() bool { return accessible }
+
+// SetAccessible turns accessible rendering on or off
+func SetAccessible(on bool) { accessible = on }
+
+// strongASCIIBorder stands in for the heavy borders (double, thick), so
+// emphasis survives the switch to ASCII
+var strongASCIIBorder = lipgloss.Border{
+	Top: "=", Bottom: "=", Left: "|", Right: "|",
+	TopLeft: "#", TopRight: "#", BottomLeft: "#", BottomRight: "#",
+}
+
+// Border returns b, or a plain ASCII border in accessible mode; box-drawing
+// characters are read out one by one by screen readers
+func Border(b lipgloss.Border) lipgloss.Border {
+	switch {
+	case !accessible:
+		return b
+	case b == lipgloss.DoubleBorder() || b == lipgloss.ThickBorder():
+		return strongASCIIBorder
+	}
+	return lipgloss.ASCIIBorder()
+}
+
+// Symbol returns fancy, or plain in accessible mode. Use it for icons and
+// bar characters that carry meaning, with a plain version that says the
+// same thing in words or ASCII, e.g. Symbol("✓", "[ok]").
+func Symbol(fancy, plain string) string {
+	if accessible {
+		return plain
+	}
+	return fancy
+}
+
+// Markdown returns the glamour style to render with instead of style: the
+// ASCII style in accessible mode and the colorless one with NoColor
+func Markdown(style string) string {
+	switch {
+	case accessible:
+		return styles.AsciiStyle
+	case noColor:
+		return styles.NoTTYStyle
+	}
+	return style
+}
+
+// ==============================================================================
+// HUH - The palette as a form theme
+// ==============================================================================
+
+// Huh builds a huh form theme from the current palette, following the
+// structure of huh's own ThemeCharm
+func Huh() *huh.Theme {
+	p := Current()
+	t := huh.ThemeBase()
+
+	t.Focused.Base = t.Focused.Base.BorderForeground(p.Muted)
+	t.Focused.Card = t.Focused.Base
+	t.Focused.Title = t.Focused.Title.Foreground(p.Secondary).Bold(true)
+	t.Focused.NoteTitle = t.Focused.NoteTitle.Foreground(p.Secondary).Bold(true).MarginBottom(1)
+	t.Focused.Directory = t.Focused.Directory.Foreground(p.Secondary)
+	t.Focused.Description = t.Focused.Description.Foreground(p.Muted)
+	t.Focused.ErrorIndicator = t.Focused.ErrorIndicator.Foreground(p.Error)
+	t.Focused.ErrorMessage = t.Focused.ErrorMessage.Foreground(p.Error)
+	t.Focused.SelectSelector = t.Focused.SelectSelector.Foreground(p.Primary)
+	t.Focused.NextIndicator = t.Focused.NextIndicator.Foreground(p.Primary)
+	t.Focused.PrevIndicator = t.Focused.PrevIndicator.Foreground(p.Primary)
+	t.Focused.Option = t.Focused.Option.Foreground(p.Text)
+	t.Focused.MultiSelectSelector = t.Focused.MultiSelectSelector.Foreground(p.Primary)
+	t.Focused.SelectedOption = t.Focused.SelectedOption.Foreground(p.Success)
+	t.Focused.SelectedPrefix = lipgloss.NewStyle().Foreground(p.Success).SetString(Symbol("✓ ", "[x] "))
+	t.Focused.UnselectedPrefix = lipgloss.NewStyle().Foreground(p.Muted).SetString(Symbol("• ", "[ ] "))
+	t.Focused.UnselectedOption = t.Focused.UnselectedOption.Foreground(p.Text)
+	t.Focused.FocusedButton = t.Focused.FocusedButton.Foreground(p.OnColor).Background(p.Primary)
+	t.Focused.Next = t.Focused.FocusedButton
+	t.Focused.BlurredButton = t.Focused.BlurredButton.Foreground(p.Text).Background(p.Surface)
+
+	t.Focused.TextInput.Cursor = t.Focused.TextInput.Cursor.Foreground(p.Success)
+	t.Focused.TextInput.Placeholder = t.Focused.TextInput.Placeholder.Foreground(p.Muted)
+	t.Focused.TextInput.Prompt = t.Focused.TextInput.Prompt.Foreground(p.Primary)
+
+	t.Blurred = t.Focused
+	t.Blurred.Base = t.Focused.Base.BorderStyle(lipgloss.HiddenBorder())
+	t.Blurred.Card = t.Blurred.Base
+	t.Blurred.NextIndicator = lipgloss.NewStyle()
+	t.Blurred.PrevIndicator = lipgloss.NewStyle()
+
+	t.Group.Title = t.Focused.Title
+	t.Group.Description = t.Focused.Description
+	return t
+}
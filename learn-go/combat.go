@@ -0,0 +1,178 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Monster struct {
+	Name      string
+	HP, MaxHP int
+	Attack    int
+	Defense   int
+	Poison    int    // world ticks of poison every hit leaves
+	Loot      []Item // dropped when it dies
+}
+
+// bestiary has every kind of monster at full health
+var bestiary = map[string]Monster{
+	"Rat":      {Name: "Rat", HP: 8, MaxHP: 8, Attack: 3},
+	"Goblin":   {Name: "Goblin", HP: 20, MaxHP: 20, Attack: 7, Defense: 1, Loot: []Item{{Name: "Potion", Type: "Consumable", Quantity: 1, Weight: 0.5, MaxStack: 5, Value: 8}}},
+	"Skeleton": {Name: "Skeleton", HP: 30, MaxHP: 30, Attack: 9, Defense: 3, Loot: []Item{{Name: "Bone Ring", Type: "Trinket", Quantity: 1, Weight: 0.1, MaxStack: 1, Value: 40}}},
+	"Spider":   {Name: "Spider", HP: 12, MaxHP: 12, Attack: 4, Poison: 5},
+}
+
+// NewMonster is a fresh monster of the named kind, or nil if there is none
+func NewMonster(name string) *Monster {
+	m, ok := bestiary[name]
+	if !ok {
+		return nil
+	}
+	return &m
+}
+
+// Damage is what an attack does against a defense: the difference, but
+// never less than 1, so every hit counts
+func Damage(attack, defense int) int {
+	return max(1, attack-defense)
+}
+
+// Effect is what using an item does in a fight
+type Effect struct {
+	Message string
+	Damage  int // dealt to the monster, before its defense
+	Guard   int // extra defense against the monster's next attack
+}
+
+// CombatUsable is a Usable that does something different in a fight:
+// weapons attack instead of being readied, armor blocks instead of being put on
+type CombatUsable interface {
+	Usable
+	InCombat(p *Player) (Effect, error)
+}
+
+// fistDamage is what the player does without a weapon
+const fistDamage = 2
+
+// Outcome is how a fight is going
+type Outcome int
+
+const (
+	Ongoing Outcome = iota
+	Won
+	Lost
+)
+
+// Combat is a fight between the player and one monster, in rounds: the
+// player does something, then the monster, if it is still alive, hits back
+type Combat struct {
+	Player  *Player
+	Monster *Monster
+	Rounds  int
+}
+
+func NewCombat(p *Player, m *Monster) *Combat {
+	return &Combat{Player: p, Monster: m}
+}
+
+func (c *Combat) Outcome() Outcome {
+	switch {
+	case c.Player.HP <= 0:
+		return Lost
+	case c.Monster.HP <= 0:
+		return Won
+	}
+	return Ongoing
+}
+
+var errFightOver = errors.New("the fight is over")
+
+// Attack fights a round with the readied weapon, or with bare hands
+func (c *Combat) Attack() error {
+	if c.Outcome() != Ongoing {
+		return errFightOver
+	}
+	weapon := c.Player.Equipment[WeaponSlot]
+	if weapon == "" {
+		c.Player.Events.Publish(ItemUsed{Message: "You punch the " + c.Monster.Name})
+		c.round(Effect{Damage: c.Player.Stats().Attack})
+		return nil
+	}
+	return c.Round(weapon)
+}
+
+// Round fights one round with the item: weapons and armor have their combat
+// effect, anything else is used as usual (a potion still heals) while the
+// monster attacks. What happens is published on the player's event bus.
+func (c *Combat) Round(itemName string) error {
+	if c.Outcome() != Ongoing {
+		return errFightOver
+	}
+	if c.Player.Count(itemName) == 0 {
+		return fmt.Errorf("%w: you have no %s", ErrNotCarried, itemName)
+	}
+
+	// Other items are used as usual, which publishes ItemUsed
+	if u, ok := usables[itemName].(CombatUsable); ok {
+		effect, err := u.InCombat(c.Player)
+		if err != nil {
+			return err
+		}
+		c.Player.Events.Publish(ItemUsed{Item: itemName, Message: effect.Message})
+		c.round(effect)
+		return nil
+	}
+	if _, err := c.Player.UseItem(itemName); err != nil {
+		return err
+	}
+	c.round(Effect{})
+	return nil
+}
+
+func (c *Combat) round(effect Effect) {
+	c.Rounds++
+	m, p := c.Monster, c.Player
+
+	if effect.Damage > 0 {
+		dealt := Damage(effect.Damage, m.Defense)
+		m.HP = max(0, m.HP-dealt)
+		p.Events.Publish(MonsterDamaged{Monster: m.Name, Amount: dealt, HP: m.HP, Max: m.MaxHP})
+		if m.HP == 0 {
+			p.Events.Publish(MonsterKilled{Monster: m.Name})
+			return
+		}
+	}
+
+	taken := Damage(m.Attack, p.Stats().Defense+effect.Guard)
+	p.HP = max(0, p.HP-taken)
+	p.Events.Publish(PlayerDamaged{By: m.Name, Amount: taken, HP: p.HP})
+	if p.HP == 0 {
+		p.Events.Publish(PlayerKilled{By: m.Name})
+		return
+	}
+	if m.Poison > p.Poisoned {
+		p.Poisoned = m.Poison
+		p.Events.Publish(PlayerPoisoned{By: m.Name, Ticks: m.Poison})
+	}
+}
+
+// InCombat attacks with the weapon, readying it first, and uses up one of
+// its ammunition. The rest of the equipment adds to its damage.
+func (w Weapon) InCombat(p *Player) (Effect, error) {
+	if _, err := w.Use(p); err != nil {
+		return Effect{}, err
+	}
+	damage := p.Stats().Attack
+	if w.Ammo != "" {
+		p.removeItem(w.Ammo, 1)
+		return Effect{Message: fmt.Sprintf("You shoot an %s with the %s", w.Ammo, w.Name), Damage: damage}, nil
+	}
+	return Effect{Message: "You swing the " + w.Name, Damage: damage}, nil
+}
+
+// InCombat puts the armor on and blocks with it: the next hit meets twice
+// its defense
+func (a Armor) InCombat(p *Player) (Effect, error) {
+	p.Equipment[ArmorSlot] = a.Name
+	return Effect{Message: "You brace behind the " + a.Name, Guard: a.Defense}, nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestPlayer(items ...Item) *Player {
+	return &Player{Name: "Tester", Inventory: items, Capacity: 50, HP: 50, MaxHP: 100}
+}
+
+var (
+	sword  = Item{Name: "Sword", Type: "Weapon", Quantity: 1, Weight: 3, MaxStack: 1, Value: 15}
+	bow    = Item{Name: "Bow", Type: "Ranged", Quantity: 1, Weight: 1.5, MaxStack: 1, Value: 18}
+	arrows = Item{Name: "Arrow", Type: "Ammunition", Quantity: 2, Weight: 0.1, MaxStack: 20, Value: 1}
+	shield = Item{Name: "Shield", Type: "Armor", Quantity: 1, Weight: 5, MaxStack: 1, Value: 12}
+	potion = Item{Name: "Potion", Type: "Consumable", Quantity: 1, Weight: 0.5, MaxStack: 5, Value: 8}
+)
+
+func TestDamage(t *testing.T) {
+	tests := []struct {
+		name            string
+		attack, defense int
+		want            int
+	}{
+		{"no defense", 8, 0, 8},
+		{"defense takes off", 8, 3, 5},
+		{"defense equal to attack", 5, 5, 1},
+		{"defense above attack", 2, 9, 1},
+		{"no attack", 0, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Damage(tt.attack, tt.defense); got != tt.want {
+				t.Errorf("Damage(%d, %d) = %d, want %d", tt.attack, tt.defense, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		name        string
+		items       []Item
+		wear        bool // put the shield on before the fight
+		use         string
+		monsterHP   int // after the round
+		playerHP    int
+		arrowsAfter int
+	}{
+		// Goblin: 20 HP, attack 7, defense 1
+		{name: "sword", items: []Item{sword}, use: "Sword", monsterHP: 13, playerHP: 43},
+		{name: "bow uses an arrow", items: []Item{bow, arrows}, use: "Bow", monsterHP: 15, playerHP: 43, arrowsAfter: 1},
+		{name: "shield blocks", items: []Item{shield}, use: "Shield", monsterHP: 20, playerHP: 49},
+		{name: "worn shield blocks twice", items: []Item{sword, shield}, wear: true, use: "Shield", monsterHP: 20, playerHP: 49},
+		{name: "worn shield while attacking", items: []Item{sword, shield}, wear: true, use: "Sword", monsterHP: 13, playerHP: 46},
+		{name: "potion heals before the hit", items: []Item{potion}, use: "Potion", monsterHP: 20, playerHP: 68},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPlayer(tt.items...)
+			if tt.wear {
+				if _, err := p.UseItem("Shield"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c := NewCombat(p, NewMonster("Goblin"))
+
+			if err := c.Round(tt.use); err != nil {
+				t.Fatalf("Round(%q): %v", tt.use, err)
+			}
+			if c.Monster.HP != tt.monsterHP {
+				t.Errorf("monster HP = %d, want %d", c.Monster.HP, tt.monsterHP)
+			}
+			if p.HP != tt.playerHP {
+				t.Errorf("player HP = %d, want %d", p.HP, tt.playerHP)
+			}
+			if got := p.Count("Arrow"); got != tt.arrowsAfter {
+				t.Errorf("arrows = %d, want %d", got, tt.arrowsAfter)
+			}
+		})
+	}
+}
+
+func TestRoundErrors(t *testing.T) {
+	p := newTestPlayer(bow)
+	c := NewCombat(p, NewMonster("Rat"))
+
+	if err := c.Round("Sword"); !errors.Is(err, ErrNotCarried) {
+		t.Errorf("Round with an item not carried: err = %v, want ErrNotCarried", err)
+	}
+	if err := c.Round("Bow"); err == nil {
+		t.Error("Round with a bow and no arrows: want an error")
+	}
+	if c.Rounds != 0 || p.HP != 50 {
+		t.Errorf("a failed round was fought: %d rounds, %d HP", c.Rounds, p.HP)
+	}
+}
+
+func TestAttackWithoutWeapon(t *testing.T) {
+	c := NewCombat(newTestPlayer(), NewMonster("Rat"))
+	if err := c.Attack(); err != nil {
+		t.Fatal(err)
+	}
+	if want := 8 - fistDamage; c.Monster.HP != want {
+		t.Errorf("rat HP = %d, want %d", c.Monster.HP, want)
+	}
+}
+
+func TestOutcome(t *testing.T) {
+	t.Run("win", func(t *testing.T) {
+		p := newTestPlayer(sword)
+		c := NewCombat(p, NewMonster("Rat"))
+		if err := c.Round("Sword"); err != nil {
+			t.Fatal(err)
+		}
+		if c.Outcome() != Won {
+			t.Fatalf("outcome = %v, want Won", c.Outcome())
+		}
+		if p.HP != 50 {
+			t.Errorf("a dead rat hit back: player HP = %d", p.HP)
+		}
+		if err := c.Attack(); err == nil {
+			t.Error("attacking after the fight: want an error")
+		}
+	})
+
+	t.Run("lose", func(t *testing.T) {
+		p := newTestPlayer()
+		p.HP = 5
+		c := NewCombat(p, NewMonster("Skeleton"))
+		if err := c.Attack(); err != nil {
+			t.Fatal(err)
+		}
+		if c.Outcome() != Lost || p.HP != 0 {
+			t.Errorf("outcome = %v with %d HP, want Lost with 0", c.Outcome(), p.HP)
+		}
+		if err := c.Attack(); err == nil {
+			t.Error("attacking after dying: want an error")
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Ingredient is an item a recipe uses up, and how many of it
+type Ingredient struct {
+	Item     string `yaml:"item"`
+	Quantity int    `yaml:"quantity"`
+}
+
+// Recipe turns its inputs into its output
+type Recipe struct {
+	Name   string       `yaml:"name"`
+	Inputs []Ingredient `yaml:"inputs"`
+	Output Item         `yaml:"output"`
+}
+
+//go:embed recipes.yaml
+var recipesYAML []byte
+
+// recipes is the recipe book, read from recipes.yaml when the program starts.
+// The file is built into the program, so an error in it is a bug.
+var recipes = mustLoadRecipes(recipesYAML)
+
+func mustLoadRecipes(data []byte) []Recipe {
+	r, err := LoadRecipes(data)
+	if err != nil {
+		panic(fmt.Sprintf("recipes.yaml: %v", err))
+	}
+	return r
+}
+
+// LoadRecipes reads a recipe book and checks every recipe makes sense
+func LoadRecipes(data []byte) ([]Recipe, error) {
+	var book struct {
+		Recipes []Recipe `yaml:"recipes"`
+	}
+	if err := yaml.Unmarshal(data, &book); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, r := range book.Recipes {
+		switch {
+		case r.Name == "":
+			return nil, errors.New("a recipe has no name")
+		case seen[strings.ToLower(r.Name)]:
+			return nil, fmt.Errorf("two recipes are called %s", r.Name)
+		case len(r.Inputs) == 0:
+			return nil, fmt.Errorf("%s has no inputs", r.Name)
+		case r.Output.Name == "" || r.Output.Quantity < 1:
+			return nil, fmt.Errorf("%s has no output", r.Name)
+		}
+		for _, in := range r.Inputs {
+			if in.Item == "" || in.Quantity < 1 {
+				return nil, fmt.Errorf("%s has an input without an item or quantity", r.Name)
+			}
+		}
+		seen[strings.ToLower(r.Name)] = true
+	}
+	return book.Recipes, nil
+}
+
+// FindRecipe looks a recipe up by name, in any case
+func FindRecipe(name string) (Recipe, bool) {
+	i := slices.IndexFunc(recipes, func(r Recipe) bool { return strings.EqualFold(r.Name, name) })
+	if i < 0 {
+		return Recipe{}, false
+	}
+	return recipes[i], true
+}
+
+// ErrMissingIngredients is returned when crafting without everything the
+// recipe needs
+var ErrMissingIngredients = errors.New("missing ingredients")
+
+// Missing lists what the player lacks to craft r: "1 Torch, 3 more Arrows".
+// It is empty when the player has everything.
+func (p *Player) Missing(r Recipe) []string {
+	var missing []string
+	for _, in := range r.Inputs {
+		have := p.Count(in.Item)
+		if have >= in.Quantity {
+			continue
+		}
+		more := ""
+		if have > 0 {
+			more = "more "
+		}
+		missing = append(missing, fmt.Sprintf("%d %s%s", in.Quantity-have, more, plural(in.Item, in.Quantity-have)))
+	}
+	return missing
+}
+
+// Craft uses up the recipe's inputs and adds its output to the inventory.
+// It either does all of that or nothing: when an ingredient is missing, or
+// the output does not fit, the inventory is left as it was.
+func (p *Player) Craft(r Recipe) error {
+	if missing := p.Missing(r); len(missing) > 0 {
+		return fmt.Errorf("%w for the %s: %s", ErrMissingIngredients, r.Name, strings.Join(missing, ", "))
+	}
+
+	// Work on the real inventory, but keep what is needed to put it back
+	inventory, equipment := slices.Clone(p.Inventory), p.Equipment
+	for _, in := range r.Inputs {
+		p.removeItem(in.Item, in.Quantity)
+	}
+	if err := p.addItem(r.Output); err != nil {
+		p.Inventory, p.Equipment = inventory, equipment
+		return err
+	}
+	p.Events.Publish(ItemCrafted{Recipe: r.Name, Item: r.Output})
+	return nil
+}
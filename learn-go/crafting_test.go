@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRecipeBook(t *testing.T) {
+	// The embedded book loaded when the program started; everything it
+	// makes must do something when used
+	if len(recipes) == 0 {
+		t.Fatal("no recipes in recipes.yaml")
+	}
+	for _, r := range recipes {
+		if _, ok := usables[r.Output.Name]; !ok {
+			t.Errorf("%s makes a %s that can't be used", r.Name, r.Output.Name)
+		}
+	}
+}
+
+func TestLoadRecipesErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"not yaml", "recipes: [", "yaml"},
+		{"no name", "recipes:\n  - inputs: [{item: A, quantity: 1}]\n    output: {name: B, quantity: 1}", "no name"},
+		{"duplicate", "recipes:\n  - {name: X, inputs: [{item: A, quantity: 1}], output: {name: B, quantity: 1}}\n  - {name: x, inputs: [{item: A, quantity: 1}], output: {name: B, quantity: 1}}", "two recipes"},
+		{"no inputs", "recipes:\n  - {name: X, output: {name: B, quantity: 1}}", "no inputs"},
+		{"no output", "recipes:\n  - {name: X, inputs: [{item: A, quantity: 1}]}", "no output"},
+		{"zero quantity", "recipes:\n  - {name: X, inputs: [{item: A, quantity: 0}], output: {name: B, quantity: 1}}", "without an item or quantity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadRecipes([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCraft(t *testing.T) {
+	greaterPotion, _ := FindRecipe("greater potion")
+	flamingSword, _ := FindRecipe("Flaming Sword")
+	twoPotions := potion
+	twoPotions.Quantity = 2
+
+	tests := []struct {
+		name     string
+		items    []Item
+		capacity float64
+		recipe   Recipe
+		want     []string // inventory afterwards
+		wantErr  error
+		missing  string
+	}{
+		{
+			name: "uses up the inputs", items: []Item{sword, twoPotions}, recipe: greaterPotion,
+			want: []string{"Sword", "Greater Potion"},
+		},
+		{
+			name: "several inputs", items: []Item{sword, torch, bow}, recipe: flamingSword,
+			want: []string{"Bow", "Flaming Sword"},
+		},
+		{
+			name: "missing one", items: []Item{potion}, recipe: greaterPotion,
+			want: []string{"Potion"}, wantErr: ErrMissingIngredients, missing: "1 more Potion",
+		},
+		{
+			name: "missing all", items: nil, recipe: flamingSword,
+			wantErr: ErrMissingIngredients, missing: "1 Sword, 1 Torch",
+		},
+		{
+			// Sword and torch weigh 4 kg, the flaming sword 3.5: it fits
+			// even in a full pack, because the inputs are gone first
+			name: "output fits once the inputs are gone", items: []Item{sword, torch}, capacity: 4, recipe: flamingSword,
+			want: []string{"Flaming Sword"},
+		},
+		{
+			name: "output too heavy", items: []Item{twoPotions}, capacity: 1, recipe: Recipe{
+				Name:   "Anvil",
+				Inputs: []Ingredient{{Item: "Potion", Quantity: 1}},
+				Output: Item{Name: "Anvil", Quantity: 1, Weight: 50, MaxStack: 1},
+			},
+			want: []string{"Potion"}, wantErr: ErrTooHeavy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPlayer(slices.Clone(tt.items)...)
+			if tt.capacity > 0 {
+				p.Capacity = tt.capacity
+			}
+			before := slices.Clone(p.Inventory)
+
+			err := p.Craft(tt.recipe)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !slices.Equal(p.Inventory, before) {
+					t.Errorf("a failed craft changed the inventory to %v", names(p.Inventory))
+				}
+				if !strings.Contains(err.Error(), tt.missing) {
+					t.Errorf("err = %v, want it to list %q", err, tt.missing)
+				}
+				return
+			}
+			if got := names(p.Inventory); !slices.Equal(got, tt.want) {
+				t.Errorf("inventory = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCraftKeepsReadiedItems(t *testing.T) {
+	p := newTestPlayer(sword, shield, torch)
+	p.UseItem("Sword")
+	p.UseItem("Shield")
+	p.Capacity = 10
+
+	heavy := Recipe{
+		Name:   "Heavy Sword",
+		Inputs: []Ingredient{{Item: "Sword", Quantity: 1}},
+		Output: Item{Name: "Heavy Sword", Quantity: 1, Weight: 20, MaxStack: 1},
+	}
+	if err := p.Craft(heavy); !errors.Is(err, ErrTooHeavy) {
+		t.Fatalf("err = %v, want ErrTooHeavy", err)
+	}
+	if p.Equipment[WeaponSlot] != "Sword" {
+		t.Errorf("weapon = %q after a failed craft, want the Sword still readied", p.Equipment[WeaponSlot])
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// LootEntry is one line of a loot table: an item, how likely it is against
+// the others, and how many of it drop
+type LootEntry struct {
+	Item     Item
+	Weight   int
+	Min, Max int // quantity, both included
+}
+
+// LootTable is what can drop, each entry as likely as its weight
+type LootTable []LootEntry
+
+// floorLoot is what lies around in generated rooms
+var floorLoot = LootTable{
+	{Item: Item{Name: "Arrow", Type: "Ammunition", Weight: 0.1, MaxStack: 20, Value: 1}, Weight: 30, Min: 3, Max: 10},
+	{Item: Item{Name: "Potion", Type: "Consumable", Weight: 0.5, MaxStack: 5, Value: 8}, Weight: 25, Min: 1, Max: 2},
+	{Item: Item{Name: "Torch", Type: "Tool", Weight: 1, MaxStack: 1, Value: 2}, Weight: 15, Min: 1, Max: 1},
+	{Item: Item{Name: "Axe", Type: "Weapon", Weight: 4, MaxStack: 1, Value: 14}, Weight: 8, Min: 1, Max: 1},
+	{Item: Item{Name: "Shield", Type: "Armor", Weight: 5, MaxStack: 1, Value: 12}, Weight: 6, Min: 1, Max: 1},
+	{Item: Item{Name: "Lucky Charm", Type: "Trinket", Weight: 0.1, MaxStack: 1, Value: 30}, Weight: 3, Min: 1, Max: 1},
+	{Item: Item{Name: "Greater Potion", Type: "Consumable", Weight: 0.5, MaxStack: 5, Value: 25}, Weight: 2, Min: 1, Max: 1},
+}
+
+// Roll picks one entry by weight and how many of it drop
+func (t LootTable) Roll(rng *rand.Rand) Item {
+	total := 0
+	for _, e := range t {
+		total += e.Weight
+	}
+	n := rng.IntN(total)
+	for _, e := range t {
+		if n -= e.Weight; n < 0 {
+			item := e.Item
+			item.Quantity = e.Min + rng.IntN(e.Max-e.Min+1)
+			return item
+		}
+	}
+	panic("unreachable: the weights add up to total")
+}
+
+// encounter is a monster that can be met from some depth down
+type encounter struct {
+	Monster  string
+	MinDepth int
+}
+
+var encounters = []encounter{
+	{"Rat", 1},
+	{"Spider", 1},
+	{"Goblin", 2},
+	{"Skeleton", 3},
+}
+
+var (
+	roomAdjectives = []string{"Damp", "Collapsed", "Flooded", "Dusty", "Narrow", "Forgotten"}
+	roomPlaces     = []struct{ Name, Description string }{
+		{"Cellar", "Broken barrels line the walls."},
+		{"Crypt", "Stone coffins lie open, and empty."},
+		{"Tunnel", "The ceiling is low and the air is stale."},
+		{"Cave", "Water has carved the walls smooth."},
+		{"Vault", "Iron doors hang from their hinges."},
+	}
+)
+
+// Difficulty sets how a room gets more dangerous, and richer, with depth
+type Difficulty struct {
+	MonsterChance  float64 // of a monster in a room at depth 1, up to 0.9
+	ChancePerLevel float64 // added to MonsterChance for every level down
+	ScalePerLevel  float64 // extra monster HP and attack for every level down: 0.25 is +25%
+	LootRolls      int     // items on the floor at depth 1; one more every two levels
+}
+
+// Normal is the difficulty the game is played at
+var Normal = Difficulty{MonsterChance: 0.5, ChancePerLevel: 0.1, ScalePerLevel: 0.25, LootRolls: 2}
+
+// Generator makes rooms and loot from a seeded random source: the same seed
+// and difficulty always make the same dungeon, which is what lets the tests
+// check randomized code exactly
+type Generator struct {
+	rng        *rand.Rand
+	Seed       uint64
+	Difficulty Difficulty
+}
+
+func NewGenerator(seed uint64, d Difficulty) *Generator {
+	return &Generator{rng: rand.New(rand.NewPCG(seed, seed)), Seed: seed, Difficulty: d}
+}
+
+// Room makes the room at depth (1 is the first one down)
+func (g *Generator) Room(depth int) *Room {
+	depth = max(1, depth)
+	place := roomPlaces[g.rng.IntN(len(roomPlaces))]
+	room := &Room{
+		Name:        fmt.Sprintf("%s %s", roomAdjectives[g.rng.IntN(len(roomAdjectives))], place.Name),
+		Description: place.Description,
+		Depth:       depth,
+	}
+	for range g.Difficulty.LootRolls + (depth-1)/2 {
+		room.Floor = addToPile(room.Floor, floorLoot.Roll(g.rng))
+	}
+	chance := min(0.9, g.Difficulty.MonsterChance+g.Difficulty.ChancePerLevel*float64(depth-1))
+	if g.rng.Float64() < chance {
+		room.Monster = g.Monster(depth)
+	}
+	return room
+}
+
+// Monster picks one of the monsters met at depth, made stronger the deeper
+// it is
+func (g *Generator) Monster(depth int) *Monster {
+	var names []string
+	for _, e := range encounters {
+		if depth >= e.MinDepth {
+			names = append(names, e.Monster)
+		}
+	}
+	m := NewMonster(names[g.rng.IntN(len(names))])
+	return scaleMonster(m, depth, g.Difficulty.ScalePerLevel)
+}
+
+// scaleMonster makes m stronger for every level below the first: HP and
+// attack grow by perLevel each time, rounded down
+func scaleMonster(m *Monster, depth int, perLevel float64) *Monster {
+	factor := 1 + perLevel*float64(max(0, depth-1))
+	m.MaxHP = int(float64(m.MaxHP) * factor)
+	m.HP = m.MaxHP
+	m.Attack = int(float64(m.Attack) * factor)
+	return m
+}
+
+// addToPile adds item to the pile of the same ones, or starts a new pile
+func addToPile(pile []Item, item Item) []Item {
+	if i := indexOf(pile, item.Name); i >= 0 {
+		pile[i].Quantity += item.Quantity
+		return pile
+	}
+	return append(pile, item)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// dungeon describes the first n rooms a generator makes, monsters and all,
+// so two dungeons can be compared
+func dungeon(g *Generator, n int) []string {
+	var rooms []string
+	for depth := 1; depth <= n; depth++ {
+		r := g.Room(depth)
+		monster := "no monster"
+		if r.Monster != nil {
+			monster = fmt.Sprintf("%s %d HP", r.Monster.Name, r.Monster.MaxHP)
+		}
+		rooms = append(rooms, fmt.Sprintf("%s: %v, %s", r.Name, stacks(r.Floor), monster))
+	}
+	return rooms
+}
+
+func TestSameSeedSameDungeon(t *testing.T) {
+	a := dungeon(NewGenerator(42, Normal), 10)
+	b := dungeon(NewGenerator(42, Normal), 10)
+	if !slices.Equal(a, b) {
+		t.Errorf("two dungeons from seed 42 differ:\n%v\n%v", a, b)
+	}
+	if c := dungeon(NewGenerator(43, Normal), 10); slices.Equal(a, c) {
+		t.Errorf("seeds 42 and 43 made the same dungeon: %v", a)
+	}
+}
+
+func TestLootRoll(t *testing.T) {
+	table := LootTable{
+		{Item: Item{Name: "Arrow"}, Weight: 3, Min: 2, Max: 5},
+		{Item: Item{Name: "Potion"}, Weight: 1, Min: 1, Max: 1},
+		{Item: Item{Name: "Crown"}, Weight: 0, Min: 1, Max: 1},
+	}
+	rng := rand.New(rand.NewPCG(1, 1))
+
+	counts := map[string]int{}
+	for range 4000 {
+		item := table.Roll(rng)
+		counts[item.Name]++
+		e := table[slices.IndexFunc(table, func(e LootEntry) bool { return e.Item.Name == item.Name })]
+		if item.Quantity < e.Min || item.Quantity > e.Max {
+			t.Fatalf("rolled %d %s, want %d to %d", item.Quantity, item.Name, e.Min, e.Max)
+		}
+	}
+	if counts["Crown"] != 0 {
+		t.Errorf("an entry of weight 0 dropped %d times", counts["Crown"])
+	}
+	// 3 to 1, give or take: the seed is fixed, so this never flakes
+	if ratio := float64(counts["Arrow"]) / float64(counts["Potion"]); ratio < 2.5 || ratio > 3.5 {
+		t.Errorf("arrows to potions = %.2f (%v), want about 3", ratio, counts)
+	}
+}
+
+func TestScaleMonster(t *testing.T) {
+	tests := []struct {
+		name       string
+		depth      int
+		perLevel   float64
+		hp, attack int // the goblin's: 20 and 7 to start with
+	}{
+		{"first level", 1, 0.25, 20, 7},
+		{"second level", 2, 0.25, 25, 8},
+		{"fifth level", 5, 0.25, 40, 14},
+		{"no scaling", 5, 0, 20, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := scaleMonster(NewMonster("Goblin"), tt.depth, tt.perLevel)
+			if m.MaxHP != tt.hp || m.HP != tt.hp || m.Attack != tt.attack {
+				t.Errorf("%d/%d HP, attack %d; want %d HP, attack %d", m.HP, m.MaxHP, m.Attack, tt.hp, tt.attack)
+			}
+		})
+	}
+}
+
+func TestDifficulty(t *testing.T) {
+	t.Run("no monsters", func(t *testing.T) {
+		g := NewGenerator(1, Difficulty{LootRolls: 1})
+		for range 50 {
+			if r := g.Room(1); r.Monster != nil {
+				t.Fatalf("a %s at monster chance 0", r.Monster.Name)
+			}
+		}
+	})
+
+	t.Run("monsters by depth", func(t *testing.T) {
+		g := NewGenerator(1, Difficulty{MonsterChance: 1})
+		for range 50 {
+			if m := g.Monster(1); m.Name != "Rat" && m.Name != "Spider" {
+				t.Fatalf("a %s on the first level", m.Name)
+			}
+		}
+		seen := map[string]bool{}
+		for range 200 {
+			seen[g.Monster(3).Name] = true
+		}
+		if len(seen) != len(encounters) {
+			t.Errorf("met %v on the third level, want every kind", seen)
+		}
+	})
+
+	t.Run("more loot further down", func(t *testing.T) {
+		g := NewGenerator(1, Difficulty{LootRolls: 2})
+		for _, depth := range []int{1, 5} {
+			r := g.Room(depth)
+			want := 2 + (depth-1)/2
+			n := 0
+			for _, item := range r.Floor {
+				n += item.Quantity
+			}
+			if len(r.Floor) > want || n < want {
+				t.Errorf("depth %d: %v on the floor, want %d rolls", depth, stacks(r.Floor), want)
+			}
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Slot is where an equipped item goes: the player holds one weapon, wears
+// one armor and one trinket
+type Slot int
+
+const (
+	WeaponSlot Slot = iota
+	ArmorSlot
+	TrinketSlot
+	numSlots
+)
+
+// Slots are all the slots, in the order they are shown
+var Slots = []Slot{WeaponSlot, ArmorSlot, TrinketSlot}
+
+func (s Slot) String() string {
+	switch s {
+	case WeaponSlot:
+		return "weapon"
+	case ArmorSlot:
+		return "armor"
+	case TrinketSlot:
+		return "trinket"
+	}
+	return fmt.Sprintf("Slot(%d)", int(s))
+}
+
+// ParseSlot is the slot called name, in any case
+func ParseSlot(name string) (Slot, bool) {
+	for _, s := range Slots {
+		if strings.EqualFold(s.String(), name) {
+			return s, true
+		}
+	}
+	return 0, false
+}
+
+// Stats are what the player fights with: the damage of an attack, and what
+// is taken off every hit
+type Stats struct {
+	Attack  int
+	Defense int
+}
+
+// Equippable is a Usable that is used by equipping it. It goes in one slot
+// only, and adds its bonus to the player's stats while it is there.
+type Equippable interface {
+	Usable
+	Slot() Slot
+	Bonus() Stats
+}
+
+// SlotOf is the slot the item called name goes in, if it can be equipped
+func SlotOf(itemName string) (Slot, bool) {
+	e, ok := usables[itemName].(Equippable)
+	if !ok {
+		return 0, false
+	}
+	return e.Slot(), true
+}
+
+// ErrWrongSlot is returned when equipping an item in a slot it does not go
+// in, or one that can't be equipped at all
+var ErrWrongSlot = errors.New("wrong slot")
+
+// Equip puts the item in the slot, in place of whatever was there, and
+// describes what happened
+func (p *Player) Equip(slot Slot, itemName string) (string, error) {
+	if p.Count(itemName) == 0 {
+		return "", fmt.Errorf("%w: you have no %s", ErrNotCarried, itemName)
+	}
+	e, ok := usables[itemName].(Equippable)
+	if !ok {
+		return "", fmt.Errorf("%w: the %s can't be equipped", ErrWrongSlot, itemName)
+	}
+	if e.Slot() != slot {
+		return "", fmt.Errorf("%w: the %s goes in the %s slot, not the %s slot", ErrWrongSlot, itemName, e.Slot(), slot)
+	}
+	msg, err := e.Use(p)
+	if err != nil {
+		return "", err
+	}
+	p.Events.Publish(ItemEquipped{Item: itemName, Slot: slot, Message: msg})
+	return msg, nil
+}
+
+// Unequip empties the slot, keeping the item in the inventory
+func (p *Player) Unequip(slot Slot) (string, error) {
+	itemName := p.Equipment[slot]
+	if itemName == "" {
+		return "", fmt.Errorf("you have nothing in your %s slot", slot)
+	}
+	p.Equipment[slot] = ""
+	msg := "You put away the " + itemName
+	p.Events.Publish(ItemUnequipped{Item: itemName, Slot: slot, Message: msg})
+	return msg, nil
+}
+
+// Equipped is what is in the slot, if anything
+func (p *Player) Equipped(slot Slot) (Equippable, bool) {
+	e, ok := usables[p.Equipment[slot]].(Equippable)
+	return e, ok
+}
+
+// Stats adds up the bonuses of everything equipped. Without a weapon the
+// player attacks with bare hands.
+func (p *Player) Stats() Stats {
+	var s Stats
+	if _, ok := p.Equipped(WeaponSlot); !ok {
+		s.Attack = fistDamage
+	}
+	for _, slot := range Slots {
+		if e, ok := p.Equipped(slot); ok {
+			s.Attack += e.Bonus().Attack
+			s.Defense += e.Bonus().Defense
+		}
+	}
+	return s
+}
+
+func (w Weapon) Slot() Slot   { return WeaponSlot }
+func (w Weapon) Bonus() Stats { return Stats{Attack: w.Damage} }
+
+func (a Armor) Slot() Slot   { return ArmorSlot }
+func (a Armor) Bonus() Stats { return Stats{Defense: a.Defense} }
+
+func (t Trinket) Slot() Slot   { return TrinketSlot }
+func (t Trinket) Bonus() Stats { return Stats{Attack: t.Attack, Defense: t.Defense} }
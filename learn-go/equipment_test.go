@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+var charm = Item{Name: "Lucky Charm", Type: "Trinket", Quantity: 1, Weight: 0.1, MaxStack: 1, Value: 30}
+
+func TestEquip(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []Item
+		slot    Slot
+		equip   string
+		want    [numSlots]string
+		wantErr error
+	}{
+		{name: "weapon", items: []Item{sword}, slot: WeaponSlot, equip: "Sword", want: [numSlots]string{WeaponSlot: "Sword"}},
+		{name: "armor", items: []Item{shield}, slot: ArmorSlot, equip: "Shield", want: [numSlots]string{ArmorSlot: "Shield"}},
+		{name: "trinket", items: []Item{charm}, slot: TrinketSlot, equip: "Lucky Charm", want: [numSlots]string{TrinketSlot: "Lucky Charm"}},
+		{name: "not carried", slot: WeaponSlot, equip: "Sword", wantErr: ErrNotCarried},
+		{name: "weapon as armor", items: []Item{sword}, slot: ArmorSlot, equip: "Sword", wantErr: ErrWrongSlot},
+		{name: "armor as a trinket", items: []Item{shield}, slot: TrinketSlot, equip: "Shield", wantErr: ErrWrongSlot},
+		{name: "trinket as a weapon", items: []Item{charm}, slot: WeaponSlot, equip: "Lucky Charm", wantErr: ErrWrongSlot},
+		{name: "not equippable", items: []Item{potion}, slot: WeaponSlot, equip: "Potion", wantErr: ErrWrongSlot},
+		{name: "unknown item", items: []Item{torch}, slot: WeaponSlot, equip: "Torch", wantErr: ErrWrongSlot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewBus()
+			got := record(bus)
+			p := newTestPlayer(tt.items...)
+			p.Events = bus
+
+			_, err := p.Equip(tt.slot, tt.equip)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if p.Equipment != tt.want {
+				t.Errorf("equipment = %q, want %q", p.Equipment, tt.want)
+			}
+			var want []string
+			if err == nil {
+				want = []string{"main.ItemEquipped"}
+			}
+			if !slices.Equal(*got, want) {
+				t.Errorf("published %v, want %v", *got, want)
+			}
+		})
+	}
+}
+
+func TestEquipReplaces(t *testing.T) {
+	p := newTestPlayer(sword, axe)
+	p.Equip(WeaponSlot, "Sword")
+	if _, err := p.Equip(WeaponSlot, "Axe"); err != nil {
+		t.Fatal(err)
+	}
+	if p.Equipment[WeaponSlot] != "Axe" || p.Count("Sword") != 1 {
+		t.Errorf("weapon = %q with %d swords, want the Axe and the Sword still carried", p.Equipment[WeaponSlot], p.Count("Sword"))
+	}
+}
+
+func TestUseEquips(t *testing.T) {
+	bus := NewBus()
+	got := record(bus)
+	p := newTestPlayer(shield)
+	p.Events = bus
+
+	if _, err := p.UseItem("Shield"); err != nil {
+		t.Fatal(err)
+	}
+	if p.Equipment[ArmorSlot] != "Shield" {
+		t.Errorf("armor = %q, want the Shield", p.Equipment[ArmorSlot])
+	}
+	if want := []string{"main.ItemEquipped"}; !slices.Equal(*got, want) {
+		t.Errorf("published %v, want %v", *got, want)
+	}
+}
+
+func TestUnequip(t *testing.T) {
+	p := newTestPlayer(sword)
+	if _, err := p.Unequip(WeaponSlot); err == nil {
+		t.Error("unequipping an empty slot: want an error")
+	}
+	p.Equip(WeaponSlot, "Sword")
+	if _, err := p.Unequip(WeaponSlot); err != nil {
+		t.Fatal(err)
+	}
+	if p.Equipment[WeaponSlot] != "" || p.Count("Sword") != 1 {
+		t.Errorf("weapon = %q with %d swords, want none equipped and the Sword carried", p.Equipment[WeaponSlot], p.Count("Sword"))
+	}
+}
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name  string
+		equip []string
+		want  Stats
+	}{
+		{name: "nothing", want: Stats{Attack: fistDamage}},
+		{name: "weapon replaces fists", equip: []string{"Sword"}, want: Stats{Attack: 8}},
+		{name: "armor", equip: []string{"Shield"}, want: Stats{Attack: fistDamage, Defense: 3}},
+		{name: "trinket with fists", equip: []string{"Lucky Charm"}, want: Stats{Attack: fistDamage + 1, Defense: 1}},
+		{name: "everything", equip: []string{"Sword", "Shield", "Lucky Charm"}, want: Stats{Attack: 9, Defense: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPlayer(sword, shield, charm)
+			for _, name := range tt.equip {
+				if _, err := p.UseItem(name); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := p.Stats(); got != tt.want {
+				t.Errorf("stats = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsInCombat(t *testing.T) {
+	// Goblin: 20 HP, attack 7, defense 1
+	p := newTestPlayer(sword, shield, charm)
+	p.UseItem("Shield")
+	p.UseItem("Lucky Charm")
+	c := NewCombat(p, NewMonster("Goblin"))
+
+	if err := c.Round("Sword"); err != nil {
+		t.Fatal(err)
+	}
+	if want := 20 - (8 + 1 - 1); c.Monster.HP != want {
+		t.Errorf("goblin HP = %d, want %d", c.Monster.HP, want)
+	}
+	if want := 50 - (7 - 4); p.HP != want {
+		t.Errorf("player HP = %d, want %d", p.HP, want)
+	}
+}
+
+func TestParseSlot(t *testing.T) {
+	for _, slot := range Slots {
+		if got, ok := ParseSlot(slot.String()); !ok || got != slot {
+			t.Errorf("ParseSlot(%q) = %v, %v", slot.String(), got, ok)
+		}
+	}
+	if got, ok := ParseSlot("Armor"); !ok || got != ArmorSlot {
+		t.Errorf("ParseSlot is case sensitive")
+	}
+	if _, ok := ParseSlot("helmet"); ok {
+		t.Error("ParseSlot(\"helmet\") found a slot")
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Event is something that happened in the game. The game logic publishes
+// events and never prints anything; whoever wants to know subscribes.
+type Event any
+
+type (
+	ItemPickedUp struct{ Item Item }
+	ItemDropped  struct{ Item Item }
+	ItemCrafted  struct {
+		Recipe string
+		Item   Item
+	}
+	ItemUsed struct {
+		Item    string // empty for bare hands
+		Message string
+	}
+	ItemBought struct {
+		Item  Item
+		Price int // for all of them
+	}
+	ItemSold struct {
+		Item  Item
+		Price int
+	}
+	ItemEquipped struct {
+		Item    string
+		Slot    Slot
+		Message string
+	}
+	ItemUnequipped struct {
+		Item    string
+		Slot    Slot
+		Message string
+	}
+	PlayerDamaged struct {
+		By     string
+		Amount int
+		HP     int // left after the hit
+	}
+	PlayerKilled struct{ By string }
+	PlayerHealed struct {
+		Amount int
+		HP     int // after healing
+	}
+	PlayerPoisoned struct {
+		By    string
+		Ticks int
+	}
+	MonsterSpawned struct{ Monster string }
+	MonsterDamaged struct {
+		Monster string
+		Amount  int
+		HP, Max int
+	}
+	MonsterKilled       struct{ Monster string }
+	AchievementUnlocked struct{ Name, Description string }
+	QuestCompleted      struct {
+		Quest  string
+		Reward int // gold
+	}
+)
+
+// Bus passes every published event to every subscriber. A nil *Bus is
+// valid and drops everything, so game logic works without one.
+type Bus struct {
+	mu         sync.Mutex
+	next       int
+	subs       []subscription
+	queue      []Event
+	delivering bool
+}
+
+type subscription struct {
+	id     int
+	handle func(Event)
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe calls handle with every event from now on, in the publisher's
+// goroutine, until the returned function is called
+func (b *Bus) Subscribe(handle func(Event)) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.next
+	b.next++
+	b.subs = append(b.subs, subscription{id: id, handle: handle})
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.subs = slices.DeleteFunc(b.subs, func(s subscription) bool { return s.id == id })
+	}
+}
+
+// Channel subscribes a channel instead of a function, for a goroutine that
+// waits for events. Publishing blocks while the channel is full, so size
+// the buffer for the most events one action publishes.
+func (b *Bus) Channel(size int) (<-chan Event, func()) {
+	ch := make(chan Event, size)
+	return ch, b.Subscribe(func(e Event) { ch <- e })
+}
+
+// Publish hands e to the subscribers in the order they subscribed.
+//
+// Events are delivered one at a time, in the order they were published: an
+// event published by a subscriber, or by another goroutine meanwhile, is
+// queued until the current one has reached everybody, and then delivered
+// by the goroutine already delivering. So handlers never run at the same
+// time, and every subscriber sees the events in the same order.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.queue = append(b.queue, e)
+	if b.delivering {
+		b.mu.Unlock()
+		return
+	}
+	b.delivering = true
+	for len(b.queue) > 0 {
+		next := b.queue[0]
+		b.queue = b.queue[1:]
+		// The handlers run without the lock, on a copy of the subscribers:
+		// one that publishes or unsubscribes would otherwise wait for itself
+		subs := slices.Clone(b.subs)
+		b.mu.Unlock()
+		for _, s := range subs {
+			s.handle(next)
+		}
+		b.mu.Lock()
+	}
+	b.delivering = false
+	b.mu.Unlock()
+}
+
+// ==============================================================================
+// SUBSCRIBERS
+// ==============================================================================
+
+// EventLogger writes every event to w with the time it happened, one per line
+func EventLogger(w io.Writer) func(Event) {
+	return func(e Event) {
+		fmt.Fprintf(w, "%s %T %+v\n", time.Now().Format(time.TimeOnly), e, e)
+	}
+}
+
+// Achievements keeps score of what the player does and unlocks
+// achievements, which it announces on the bus
+type Achievements struct {
+	bus      *Bus
+	Unlocked []AchievementUnlocked
+	pickedUp int
+	potions  int
+}
+
+func NewAchievements(bus *Bus) *Achievements {
+	a := &Achievements{bus: bus}
+	bus.Subscribe(a.handle)
+	return a
+}
+
+func (a *Achievements) handle(e Event) {
+	switch e := e.(type) {
+	case ItemPickedUp:
+		if a.pickedUp += e.Item.Quantity; a.pickedUp >= 10 {
+			a.unlock("Packrat", "Pick up 10 items")
+		}
+	case ItemUsed:
+		if e.Item == "Potion" {
+			if a.potions++; a.potions >= 3 {
+				a.unlock("Thirsty", "Drink 3 potions")
+			}
+		}
+	case ItemCrafted:
+		a.unlock("Tinkerer", "Craft an item")
+	case MonsterKilled:
+		a.unlock("First Blood", "Defeat a monster")
+	case PlayerDamaged:
+		if e.HP > 0 && e.HP < 10 {
+			a.unlock("Close Call", "Survive a hit with less than 10 HP left")
+		}
+	}
+}
+
+func (a *Achievements) unlock(name, description string) {
+	for _, u := range a.Unlocked {
+		if u.Name == name {
+			return
+		}
+	}
+	u := AchievementUnlocked{Name: name, Description: description}
+	a.Unlocked = append(a.Unlocked, u)
+	a.bus.Publish(u)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// record subscribes to bus and collects the type of every event
+func record(bus *Bus) *[]string {
+	var got []string
+	bus.Subscribe(func(e Event) { got = append(got, fmt.Sprintf("%T", e)) })
+	return &got
+}
+
+func TestBusOrder(t *testing.T) {
+	bus := NewBus()
+
+	// The first subscriber publishes while handling; the second must still
+	// see the events in the order they happened
+	bus.Subscribe(func(e Event) {
+		if _, ok := e.(MonsterKilled); ok {
+			bus.Publish(AchievementUnlocked{Name: "First Blood"})
+		}
+	})
+	got := record(bus)
+
+	bus.Publish(MonsterDamaged{})
+	bus.Publish(MonsterKilled{})
+	want := []string{"main.MonsterDamaged", "main.MonsterKilled", "main.AchievementUnlocked"}
+	if !slices.Equal(*got, want) {
+		t.Errorf("got %v, want %v", *got, want)
+	}
+}
+
+func TestBusUnsubscribe(t *testing.T) {
+	bus := NewBus()
+	got := record(bus)
+	n := 0
+	unsubscribe := bus.Subscribe(func(Event) { n++ })
+
+	bus.Publish(ItemUsed{})
+	unsubscribe()
+	bus.Publish(ItemUsed{})
+	if n != 1 || len(*got) != 2 {
+		t.Errorf("unsubscribed handler ran %d times, the other %d; want 1 and 2", n, len(*got))
+	}
+
+	var none *Bus
+	none.Publish(ItemUsed{}) // must not panic
+}
+
+func TestBusChannel(t *testing.T) {
+	bus := NewBus()
+	events, unsubscribe := bus.Channel(2)
+	bus.Publish(ItemPickedUp{Item: sword})
+	bus.Publish(ItemDropped{Item: sword})
+	unsubscribe()
+	bus.Publish(ItemUsed{})
+
+	if e := <-events; e != (ItemPickedUp{Item: sword}) {
+		t.Errorf("first event = %v", e)
+	}
+	if e := <-events; e != (ItemDropped{Item: sword}) {
+		t.Errorf("second event = %v", e)
+	}
+	if len(events) != 0 {
+		t.Error("got an event after unsubscribing")
+	}
+}
+
+func TestPlayerEvents(t *testing.T) {
+	bus := NewBus()
+	got := record(bus)
+	p := newTestPlayer(sword)
+	p.Events = bus
+
+	p.PickUpItem(potion)
+	p.UseItem("Potion")
+	p.DropItem("Sword", 1)
+	p.DropItem("Sword", 1) // nothing left to drop, nothing published
+	c := NewCombat(p, NewMonster("Rat"))
+	c.Attack()
+
+	want := []string{
+		"main.ItemPickedUp", "main.ItemUsed", "main.ItemDropped",
+		"main.ItemUsed", "main.MonsterDamaged", "main.PlayerDamaged",
+	}
+	if !slices.Equal(*got, want) {
+		t.Errorf("got %v, want %v", *got, want)
+	}
+}
+
+func TestAchievements(t *testing.T) {
+	bus := NewBus()
+	a := NewAchievements(bus)
+	got := record(bus)
+
+	for range 3 {
+		bus.Publish(ItemUsed{Item: "Potion"})
+	}
+	bus.Publish(PlayerDamaged{HP: 5})
+	bus.Publish(PlayerDamaged{HP: 3}) // already unlocked
+	bus.Publish(PlayerDamaged{HP: 0}) // dead is not a close call
+
+	var names []string
+	for _, u := range a.Unlocked {
+		names = append(names, u.Name)
+	}
+	if want := []string{"Thirsty", "Close Call"}; !slices.Equal(names, want) {
+		t.Errorf("unlocked %v, want %v", names, want)
+	}
+	if n := slices.Index(*got, "main.AchievementUnlocked"); n != 3 {
+		t.Errorf("first achievement published after %d events, want 3: %v", n, *got)
+	}
+}
@@ -0,0 +1,931 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Room is where the player stands, with the items lying on its floor
+type Room struct {
+	Name        string
+	Description string
+	Floor       []Item
+	Monster     *Monster // nil once it is dead
+	Shop        *Shop    // someone to trade with, if any
+	Depth       int      // how far down: 0 is where the game starts
+}
+
+// logKind decides how a line of the message log is drawn
+type logKind int
+
+const (
+	logInfo    logKind = iota
+	logCommand         // what the player typed
+	logError
+	logAchievement
+)
+
+type logLine struct {
+	kind logKind
+	text string
+}
+
+// game is the Bubble Tea model: the world, the command prompt and
+// everything that has happened so far
+type game struct {
+	player        *Player
+	room          *Room
+	dungeon       *Generator // makes the rooms further down
+	input         textinput.Model
+	log           []logLine
+	events        <-chan Event
+	world         <-chan WorldEvent
+	achievements  *Achievements
+	quests        *QuestLog
+	fight         *Combat // the fight going on, if any
+	shop          *huh.Form
+	trade         *trade // what the shop menu is filling in
+	showQuests    bool   // the quest log instead of the message log, until the next command
+	dead          bool
+	width, height int
+}
+
+// newGame sets up the game around player; what happens is logged from the
+// events published on player.Events. The world's events are applied as they
+// arrive from world.
+func newGame(player *Player, room *Room, dungeon *Generator, achievements *Achievements, quests *QuestLog, world <-chan WorldEvent) game {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "pick up axe, use sword, drop 2 arrows, look, help"
+	input.ShowSuggestions = true
+	input.Focus()
+
+	g := game{player: player, room: room, dungeon: dungeon, input: input, achievements: achievements, quests: quests, world: world, width: 80, height: 24}
+	g.events, _ = player.Events.Channel(64)
+	g.say(logInfo, fmt.Sprintf("You wake up in the %s. Type help to see what you can do.", room.Name))
+	g.say(logInfo, fmt.Sprintf("The dungeon below has seed %d; go run . -seed %d plays it again.", dungeon.Seed, dungeon.Seed))
+	g.look()
+	g.input.SetSuggestions(g.suggestions())
+	return g
+}
+
+func (g game) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, waitForEvent(g.events), waitForWorld(g.world))
+}
+
+// eventMsg brings an event from the bus into the Bubble Tea loop
+type eventMsg struct{ Event }
+
+// waitForEvent waits for the next event in a goroutine of its own; Update
+// starts it again after every event, so the game keeps listening
+func waitForEvent(events <-chan Event) tea.Cmd {
+	return func() tea.Msg {
+		return eventMsg{<-events}
+	}
+}
+
+type worldMsg struct{ WorldEvent }
+
+// waitForWorld is waitForEvent for the world clock, which stops sending
+// when the world shuts down
+func waitForWorld(world <-chan WorldEvent) tea.Cmd {
+	return func() tea.Msg {
+		e, ok := <-world
+		if !ok {
+			return nil
+		}
+		return worldMsg{e}
+	}
+}
+
+func (g game) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case eventMsg:
+		g.handleEvent(msg.Event)
+		return g, waitForEvent(g.events)
+
+	case worldMsg:
+		// World events are applied here, in the Bubble Tea loop, so the
+		// player and the room are only ever changed from one goroutine
+		w := World{Player: g.player, Room: g.room}
+		w.Apply(msg.WorldEvent)
+		g.input.SetSuggestions(g.suggestions())
+		return g, waitForWorld(g.world)
+
+	case tea.WindowSizeMsg:
+		g.width, g.height = msg.Width, msg.Height
+		g.input.Width = max(10, msg.Width-4)
+		if g.shop != nil {
+			g.shop = g.shop.WithWidth(g.logWidth() - 4)
+		}
+		return g, nil
+	}
+
+	// While the shop menu is open it gets the keys, and the messages the
+	// form sends itself
+	if g.shop != nil {
+		if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "ctrl+c" {
+			return g, tea.Quit
+		}
+		return g.updateShop(msg)
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return g, tea.Quit
+		case "enter":
+			line := strings.TrimSpace(g.input.Value())
+			g.input.Reset()
+			if line == "" || g.dead {
+				return g, nil
+			}
+			if quit := g.run(line); quit {
+				return g, tea.Quit
+			}
+			if g.shop != nil {
+				return g, g.shop.Init()
+			}
+			// Tab completes whatever makes sense right now
+			g.input.SetSuggestions(g.suggestions())
+			return g, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	g.input, cmd = g.input.Update(msg)
+	return g, cmd
+}
+
+// ==============================================================================
+// COMMANDS
+// ==============================================================================
+
+// verbAliases maps the other ways of saying a command to its name
+var verbAliases = map[string]string{
+	"pickup": "take", "get": "take", "grab": "take",
+	"i": "inventory", "inv": "inventory",
+	"l": "look",
+	"q": "quit", "exit": "quit",
+	"fight": "attack", "hit": "attack", "kill": "attack",
+	"wield": "equip", "wear": "equip", "remove": "unequip",
+	"buy": "shop", "sell": "shop", "trade": "shop",
+	"quest": "quests", "journal": "quests", "j": "quests",
+	"down": "descend", "explore": "descend",
+}
+
+// parseQuantity splits an optional leading number, or "all", off an item
+// name: "3 arrows" is 3, "arrows". Without one the quantity is 0.
+func parseQuantity(arg string) (int, string) {
+	first, rest, _ := strings.Cut(arg, " ")
+	if first == "all" {
+		return All, rest
+	}
+	if n, err := strconv.Atoi(first); err == nil && n > 0 {
+		return n, rest
+	}
+	return 0, arg
+}
+
+// parseCommand splits a typed line into a verb and the rest, so
+// "Pick up the Axe" becomes "take", "axe"
+func parseCommand(line string) (verb, arg string) {
+	words := strings.Fields(strings.ToLower(line))
+	verb, rest := words[0], words[1:]
+	if verb == "pick" && len(rest) > 0 && rest[0] == "up" {
+		verb, rest = "take", rest[1:]
+	}
+	if alias, ok := verbAliases[verb]; ok {
+		verb = alias
+	}
+	if len(rest) > 0 && rest[0] == "the" {
+		rest = rest[1:]
+	}
+	return verb, strings.Join(rest, " ")
+}
+
+// run carries out one typed command and logs what happened. It reports
+// whether the player asked to quit.
+func (g *game) run(line string) bool {
+	g.say(logCommand, "> "+line)
+	g.showQuests = false
+
+	verb, arg := parseCommand(line)
+	switch verb {
+	case "take":
+		g.take(arg)
+	case "drop":
+		g.drop(arg)
+	case "use":
+		g.use(arg)
+	case "equip":
+		g.equip(arg)
+	case "unequip":
+		g.unequip(arg)
+	case "attack":
+		g.attack()
+	case "craft":
+		g.craft(arg)
+	case "recipes":
+		g.listRecipes()
+	case "shop":
+		g.openShop()
+	case "quests":
+		g.showQuests = true
+	case "descend":
+		g.descend()
+	case "look":
+		g.look()
+	case "inventory":
+		g.inventory(arg)
+	case "help":
+		g.say(logInfo, "Commands: pick up [n|all] <item>, use <item>, equip <item>, unequip <slot or item>, drop [n|all] <item>, attack, craft <recipe>, recipes, shop, quests, descend, look, inventory [type or name] [by name|weight|value], help, quit. Tab completes a command.")
+	case "quit":
+		return true
+	default:
+		g.say(logError, fmt.Sprintf("You don't know how to %q. Type help to see what you can do.", verb))
+	}
+	return false
+}
+
+func (g *game) take(arg string) {
+	quantity, name := parseQuantity(arg)
+	if g.fight != nil {
+		g.say(logError, "Not now, the "+g.fight.Monster.Name+" is attacking you!")
+		return
+	}
+	if name == "" {
+		g.say(logError, "Pick up what?")
+		return
+	}
+	i := findItem(g.room.Floor, name)
+	if i < 0 {
+		g.say(logError, fmt.Sprintf("There is no %s here", name))
+		return
+	}
+
+	// Without a number the whole pile is picked up
+	pile := &g.room.Floor[i]
+	item := *pile
+	if quantity > 0 {
+		item.Quantity = min(quantity, pile.Quantity)
+	}
+	if err := g.player.PickUpItem(item); err != nil {
+		g.say(logError, "You can't pick that up: "+err.Error())
+		return
+	}
+	pile.Quantity -= item.Quantity
+	if pile.Quantity == 0 {
+		g.room.Floor = slices.Delete(g.room.Floor, i, i+1)
+	}
+}
+
+func (g *game) drop(arg string) {
+	quantity, name := parseQuantity(arg)
+	if g.fight != nil {
+		g.say(logError, "Not now, the "+g.fight.Monster.Name+" is attacking you!")
+		return
+	}
+	if name == "" {
+		g.say(logError, "Drop what?")
+		return
+	}
+	i := findItem(g.player.Inventory, name)
+	if i < 0 {
+		g.say(logError, fmt.Sprintf("You are not carrying a %s", name))
+		return
+	}
+
+	// Without a number one is dropped
+	if quantity == 0 {
+		quantity = 1
+	}
+	item, err := g.player.DropItem(g.player.Inventory[i].Name, quantity)
+	if err != nil {
+		g.say(logError, "You can't: "+err.Error())
+		return
+	}
+	g.putOnFloor(item)
+}
+
+func (g *game) use(name string) {
+	if name == "" {
+		g.say(logError, "Use what?")
+		return
+	}
+	i := findItem(g.player.Inventory, name)
+	if i < 0 {
+		g.say(logError, fmt.Sprintf("You are not carrying a %s", name))
+		return
+	}
+	itemName := g.player.Inventory[i].Name
+
+	// In a fight every item used is a round
+	if g.fight != nil {
+		g.fightRound(g.fight.Round(itemName))
+		return
+	}
+	if _, err := g.player.UseItem(itemName); err != nil {
+		g.say(logError, "You can't: "+err.Error())
+	}
+}
+
+func (g *game) equip(name string) {
+	if g.fight != nil {
+		g.say(logError, "Not now, the "+g.fight.Monster.Name+" is attacking you!")
+		return
+	}
+	if name == "" {
+		g.say(logError, "Equip what?")
+		return
+	}
+	i := findItem(g.player.Inventory, name)
+	if i < 0 {
+		g.say(logError, fmt.Sprintf("You are not carrying a %s", name))
+		return
+	}
+	itemName := g.player.Inventory[i].Name
+	slot, ok := SlotOf(itemName)
+	if !ok {
+		g.say(logError, fmt.Sprintf("The %s can't be equipped", itemName))
+		return
+	}
+	if _, err := g.player.Equip(slot, itemName); err != nil {
+		g.say(logError, "You can't: "+err.Error())
+	}
+}
+
+// unequip empties a slot, named either by itself or by what is in it:
+// "unequip armor", "unequip shield"
+func (g *game) unequip(arg string) {
+	if g.fight != nil {
+		g.say(logError, "Not now, the "+g.fight.Monster.Name+" is attacking you!")
+		return
+	}
+	slot, ok := ParseSlot(arg)
+	if !ok {
+		i := slices.IndexFunc(Slots, func(s Slot) bool {
+			return g.player.Equipment[s] != "" && strings.EqualFold(g.player.Equipment[s], arg)
+		})
+		if i < 0 {
+			g.say(logError, fmt.Sprintf("You have no %s equipped. Unequip weapon, armor or trinket.", arg))
+			return
+		}
+		slot = Slots[i]
+	}
+	if _, err := g.player.Unequip(slot); err != nil {
+		g.say(logError, "You can't: "+err.Error())
+	}
+}
+
+func (g *game) craft(name string) {
+	if g.fight != nil {
+		g.say(logError, "Not now, the "+g.fight.Monster.Name+" is attacking you!")
+		return
+	}
+	if name == "" {
+		g.say(logError, "Craft what? Type recipes to see what you can make.")
+		return
+	}
+	r, ok := FindRecipe(name)
+	if !ok {
+		g.say(logError, fmt.Sprintf("You don't know how to make a %s. Type recipes to see what you can make.", name))
+		return
+	}
+	if err := g.player.Craft(r); err != nil {
+		g.say(logError, "You can't: "+err.Error())
+	}
+}
+
+// listRecipes logs every recipe with its ingredients and what is missing
+func (g *game) listRecipes() {
+	for _, r := range recipes {
+		inputs := make([]string, len(r.Inputs))
+		for i, in := range r.Inputs {
+			inputs[i] = fmt.Sprintf("%d %s", in.Quantity, plural(in.Item, in.Quantity))
+		}
+		line := fmt.Sprintf("%s: %s", r.Name, strings.Join(inputs, " + "))
+		if missing := g.player.Missing(r); len(missing) > 0 {
+			line += " (missing " + strings.Join(missing, ", ") + ")"
+		} else {
+			line += " (ready)"
+		}
+		g.say(logInfo, line)
+	}
+}
+
+// attack starts a fight with the monster in the room, or goes on with it
+func (g *game) attack() {
+	if g.fight == nil {
+		if g.room.Monster == nil {
+			g.say(logError, "There is nothing here to fight")
+			return
+		}
+		g.fight = NewCombat(g.player, g.room.Monster)
+		g.say(logInfo, fmt.Sprintf("You attack the %s!", g.room.Monster.Name))
+	}
+	g.fightRound(g.fight.Attack())
+}
+
+func (g *game) fightRound(err error) {
+	if err != nil {
+		g.say(logError, "You can't: "+err.Error())
+	}
+}
+
+// handleEvent logs what happened, and ends the fight once someone won
+func (g *game) handleEvent(e Event) {
+	switch e := e.(type) {
+	case ItemPickedUp:
+		g.say(logInfo, "You pick up "+describe(e.Item))
+	case ItemDropped:
+		g.say(logInfo, "You drop "+describe(e.Item))
+	case ItemUsed:
+		g.say(logInfo, e.Message)
+	case ItemEquipped:
+		g.say(logInfo, e.Message)
+	case ItemUnequipped:
+		g.say(logInfo, e.Message)
+	case ItemCrafted:
+		g.say(logInfo, "You craft "+describe(e.Item))
+	case ItemBought:
+		g.say(logInfo, fmt.Sprintf("You buy %s for %d gold", describe(e.Item), e.Price))
+	case ItemSold:
+		g.say(logInfo, fmt.Sprintf("You sell %s for %d gold", describe(e.Item), e.Price))
+	case MonsterDamaged:
+		g.say(logInfo, fmt.Sprintf("The %s takes %d damage (%d/%d HP)", e.Monster, e.Amount, e.HP, e.Max))
+	case PlayerDamaged:
+		if e.By == "poison" {
+			g.say(logError, fmt.Sprintf("The poison burns you for %d damage (%d/%d HP)", e.Amount, e.HP, g.player.MaxHP))
+		} else {
+			g.say(logInfo, fmt.Sprintf("The %s hits you for %d damage (%d/%d HP)", e.By, e.Amount, e.HP, g.player.MaxHP))
+		}
+	case PlayerPoisoned:
+		g.say(logError, fmt.Sprintf("The %s's bite poisons you", e.By))
+	case MonsterSpawned:
+		g.say(logError, fmt.Sprintf("A %s crawls out of the dark!", e.Monster))
+	case AchievementUnlocked:
+		g.say(logAchievement, fmt.Sprintf("Achievement unlocked: %s (%s)", e.Name, e.Description))
+	case QuestCompleted:
+		g.say(logAchievement, fmt.Sprintf("Quest complete: %s. You are paid %d gold.", e.Quest, e.Reward))
+
+	case MonsterKilled:
+		g.say(logInfo, fmt.Sprintf("The %s dies. You win!", e.Monster))
+		if m := g.room.Monster; m != nil {
+			for _, item := range m.Loot {
+				g.putOnFloor(item)
+				g.say(logInfo, fmt.Sprintf("The %s drops %s", m.Name, describe(item)))
+			}
+		}
+		g.room.Monster, g.fight = nil, nil
+	case PlayerKilled:
+		g.say(logError, fmt.Sprintf("You were killed by the %s. Game over, press esc to quit.", e.By))
+		g.dead, g.shop = true, nil
+	}
+}
+
+// inventory lists what the player carries, narrowed down to a type or the
+// start of a name and sorted: "inventory weapons by weight"
+func (g *game) inventory(arg string) {
+	filter, by, _ := strings.Cut(arg, "by ")
+	filter = strings.TrimSpace(filter)
+
+	items := g.player.Inventory
+	if filter != "" {
+		singular := strings.TrimSuffix(filter, "s")
+		items = g.player.Query(Or(OfType(filter), OfType(singular), NamePrefix(filter)))
+	}
+	switch strings.TrimSpace(by) {
+	case "":
+	case "name":
+		items = SortBy(items, ItemName)
+	case "weight":
+		items = SortBy(items, ItemWeight)
+	case "value":
+		items = SortBy(items, ItemValue)
+	default:
+		g.say(logError, fmt.Sprintf("You can sort by name, weight or value, not %q", by))
+		return
+	}
+
+	if filter == "" {
+		g.say(logInfo, "You are carrying: "+itemList(items))
+	} else {
+		g.say(logInfo, fmt.Sprintf("You are carrying (%s): %s", filter, itemList(items)))
+	}
+}
+
+// putOnFloor adds the item to the pile of the same ones on the floor, or
+// starts a new pile
+func (g *game) putOnFloor(item Item) {
+	g.room.Floor = addToPile(g.room.Floor, item)
+}
+
+// descend leaves the room for a new one, one level further down: more
+// dangerous, and with more lying around
+func (g *game) descend() {
+	switch {
+	case g.fight != nil:
+		g.say(logError, "Not now, the "+g.fight.Monster.Name+" is attacking you!")
+		return
+	case g.room.Monster != nil:
+		g.say(logError, fmt.Sprintf("The %s blocks the way down", g.room.Monster.Name))
+		return
+	}
+	g.room = g.dungeon.Room(g.room.Depth + 1)
+	g.say(logInfo, fmt.Sprintf("You climb down into the %s (depth %d).", g.room.Name, g.room.Depth))
+	g.look()
+}
+
+func (g *game) look() {
+	text := g.room.Description
+	if m := g.room.Monster; m != nil {
+		text += fmt.Sprintf(" A %s is watching you.", m.Name)
+	}
+	if shop := g.room.Shop; shop != nil {
+		text += fmt.Sprintf(" A %s offers to trade (shop).", strings.ToLower(shop.Name))
+	}
+	g.say(logInfo, text+" On the floor: "+itemList(g.room.Floor)+".")
+}
+
+func (g *game) say(kind logKind, text string) {
+	g.log = append(g.log, logLine{kind: kind, text: text})
+}
+
+// suggestions are the complete commands that would do something right now
+func (g *game) suggestions() []string {
+	s := []string{"look", "inventory", "quests", "help", "quit"}
+	if g.room.Monster != nil {
+		s = append(s, "attack")
+	} else {
+		s = append(s, "descend")
+	}
+	if g.room.Shop != nil {
+		s = append(s, "shop")
+	}
+	s = append(s, "recipes")
+	for _, r := range recipes {
+		s = append(s, "craft "+strings.ToLower(r.Name))
+	}
+	for _, item := range g.room.Floor {
+		s = append(s, "pick up "+strings.ToLower(item.Name))
+	}
+	for _, item := range g.player.Inventory {
+		s = append(s, "use "+strings.ToLower(item.Name), "drop "+strings.ToLower(item.Name))
+		if _, ok := SlotOf(item.Name); ok {
+			s = append(s, "equip "+strings.ToLower(item.Name))
+		}
+	}
+	for _, slot := range Slots {
+		if g.player.Equipment[slot] != "" {
+			s = append(s, "unequip "+slot.String())
+		}
+	}
+	slices.Sort(s)
+	return slices.Compact(s)
+}
+
+// findItem is the index of the item called name, in any case and singular
+// or plural, or -1
+func findItem(items []Item, name string) int {
+	return slices.IndexFunc(items, func(item Item) bool {
+		return strings.EqualFold(item.Name, name) || strings.EqualFold(item.Name+"s", name)
+	})
+}
+
+// describe names a stack: "the Axe", "3 Arrows"
+func describe(item Item) string {
+	if item.Quantity == 1 {
+		return "the " + item.Name
+	}
+	return fmt.Sprintf("%d %s", item.Quantity, plural(item.Name, item.Quantity))
+}
+
+func itemList(items []Item) string {
+	if len(items) == 0 {
+		return "nothing"
+	}
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+		if item.Quantity > 1 {
+			names[i] = fmt.Sprintf("%s x%d", item.Name, item.Quantity)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// ==============================================================================
+// VIEW
+// ==============================================================================
+
+const sidebarWidth = 30
+
+var (
+	titleStyle       = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#7D56F4")).Padding(0, 1)
+	headingStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4"))
+	mutedStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	errorStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
+	achievementStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFD700"))
+	commandStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575"))
+	panelStyle       = lipgloss.NewStyle().
+				Border(lipgloss.RoundedBorder()).
+				BorderForeground(lipgloss.Color("63")).
+				Padding(0, 1)
+)
+
+// ==============================================================================
+// SHOP MENU
+// ==============================================================================
+
+// trade is what the shop menu asks for: buy or sell, what and how many
+type trade struct {
+	action   string // "buy", "sell" or "leave"
+	item     string
+	quantity string
+}
+
+// openShop opens the shop menu, a huh form run inside the game instead of
+// on its own: the game passes it messages while it is open (see Update)
+func (g *game) openShop() {
+	shop := g.room.Shop
+	switch {
+	case shop == nil:
+		g.say(logError, "There is nobody here to trade with")
+		return
+	case g.fight != nil:
+		g.say(logError, "Not now, the "+g.fight.Monster.Name+" is attacking you!")
+		return
+	}
+
+	t := &trade{quantity: "1"}
+	var buy, sell []huh.Option[string]
+	for _, item := range shop.Stock {
+		price, _ := shop.Price(item.Name)
+		buy = append(buy, huh.NewOption(fmt.Sprintf("%-14s x%-3d %4d gold", item.Name, item.Quantity, price), item.Name))
+	}
+	for _, item := range g.player.Inventory {
+		offer := shop.Offer(item)
+		if offer == 0 || slices.ContainsFunc(sell, func(o huh.Option[string]) bool { return o.Value == item.Name }) {
+			continue
+		}
+		sell = append(sell, huh.NewOption(fmt.Sprintf("%-14s x%-3d %4d gold", item.Name, g.player.Count(item.Name), offer), item.Name))
+	}
+	actions := []huh.Option[string]{}
+	if len(buy) > 0 {
+		actions = append(actions, huh.NewOption("Buy", "buy"))
+	}
+	if len(sell) > 0 {
+		actions = append(actions, huh.NewOption("Sell", "sell"))
+	}
+	actions = append(actions, huh.NewOption("Leave", "leave"))
+
+	// esc leaves the shop instead of the game
+	keys := huh.NewDefaultKeyMap()
+	keys.Quit = key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "leave"))
+
+	g.trade = t
+	g.shop = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(fmt.Sprintf("The %s greets you. You have %d gold.", shop.Name, g.player.Gold)).
+				Options(actions...).
+				Value(&t.action),
+		),
+		huh.NewGroup(
+			huh.NewSelect[string]().Title("Buy what?").Options(buy...).Value(&t.item),
+		).WithHideFunc(func() bool { return t.action != "buy" }),
+		huh.NewGroup(
+			huh.NewSelect[string]().Title("Sell what?").Options(sell...).Value(&t.item),
+		).WithHideFunc(func() bool { return t.action != "sell" }),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("How many?").
+				Value(&t.quantity).
+				Validate(func(s string) error {
+					if n, err := strconv.Atoi(s); err != nil || n < 1 {
+						return fmt.Errorf("a number, 1 or more")
+					}
+					return nil
+				}),
+		).WithHideFunc(func() bool { return t.action == "leave" }),
+	).WithKeyMap(keys).WithWidth(g.logWidth() - 4).WithShowHelp(true)
+}
+
+// updateShop passes msg on to the shop menu, and makes the trade once the
+// menu is filled in
+func (g game) updateShop(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, cmd := g.shop.Update(msg)
+	g.shop = form.(*huh.Form)
+
+	switch {
+	case g.shop.State == huh.StateNormal:
+		return g, cmd
+	case g.shop.State == huh.StateCompleted && g.trade.action != "leave":
+		g.makeTrade()
+	default:
+		g.say(logInfo, "You leave the "+g.room.Shop.Name)
+	}
+	g.shop, g.trade = nil, nil
+	g.input.SetSuggestions(g.suggestions())
+	return g, nil
+}
+
+func (g *game) makeTrade() {
+	t, shop := g.trade, g.room.Shop
+	n, _ := strconv.Atoi(t.quantity)
+	var err error
+	switch t.action {
+	case "buy":
+		err = g.player.Buy(shop, t.item, n)
+	case "sell":
+		err = g.player.Sell(shop, t.item, n)
+	}
+	if err != nil {
+		g.say(logError, "You can't: "+err.Error())
+	}
+}
+
+func (g game) View() string {
+	header := titleStyle.Render("Inventory Adventure") + mutedStyle.Render(fmt.Sprintf("  %s, in the %s", g.player.Name, g.room.Name))
+	if g.room.Depth > 0 {
+		header += mutedStyle.Render(fmt.Sprintf(" (depth %d)", g.room.Depth))
+	}
+	if n := len(g.achievements.Unlocked); n > 0 {
+		header += achievementStyle.Render(fmt.Sprintf("  ★ %d", n))
+	}
+	header += mutedStyle.Render(fmt.Sprintf("  quests %d/%d", g.quests.Completed(), len(g.quests.Quests)))
+	footer := g.input.View() + "\n" + mutedStyle.Render("enter runs a command • tab completes • esc quits")
+	bodyHeight := max(6, g.height-lipgloss.Height(header)-lipgloss.Height(footer))
+
+	inventory := g.inventoryPanel()
+	room := panel("Room", g.roomContent(), sidebarWidth, max(4, bodyHeight-lipgloss.Height(inventory)))
+	sidebar := lipgloss.JoinVertical(lipgloss.Left, inventory, room)
+	messages := panel("Log", g.logContent(g.logWidth(), bodyHeight), g.logWidth(), bodyHeight)
+	switch {
+	case g.shop != nil:
+		messages = panel(g.room.Shop.Name, g.shop.View(), g.logWidth(), bodyHeight)
+	case g.showQuests:
+		messages = panel("Quests", g.questLogContent(g.logWidth()), g.logWidth(), bodyHeight)
+	}
+
+	body := lipgloss.JoinHorizontal(lipgloss.Top, sidebar, messages)
+	return lipgloss.JoinVertical(lipgloss.Left, header, body, footer)
+}
+
+func (g game) inventoryPanel() string {
+	var lines []string
+	for _, item := range g.player.Inventory {
+		count := ""
+		if item.Quantity > 1 {
+			count = fmt.Sprintf("x%d", item.Quantity)
+		}
+		name := item.Name
+		if len(name) > 14 {
+			name = name[:13] + "…"
+		}
+		lines = append(lines, fmt.Sprintf("%-14s %-4s %s", name, count, mutedStyle.Render(fmt.Sprintf("%4.1fkg", item.TotalWeight()))))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, mutedStyle.Render("empty"))
+	}
+
+	load := g.player.Load()
+	loadStyle := mutedStyle
+	if load > g.player.Capacity*0.8 {
+		loadStyle = errorStyle
+	}
+	lines = append(lines, "", loadStyle.Render(fmt.Sprintf("Load %.1f / %.1f kg", load, g.player.Capacity)))
+	lines = append(lines, achievementStyle.UnsetBold().Render(fmt.Sprintf("Gold %d", g.player.Gold)))
+
+	hpStyle := commandStyle
+	if g.player.HP < g.player.MaxHP/3 {
+		hpStyle = errorStyle
+	}
+	poisoned := ""
+	if g.player.Poisoned > 0 {
+		poisoned = " poisoned"
+	}
+	lines = append(lines, hpStyle.Render(fmt.Sprintf("HP   %d / %d", g.player.HP, g.player.MaxHP)+poisoned))
+	lines = append(lines, statsLines(g.player)...)
+	return panel("Inventory", strings.Join(lines, "\n"), sidebarWidth, len(lines)+3)
+}
+
+// statsLines sums up the player's stats and what is in each slot:
+//
+//	Attack 9   Defense 4
+//	Weapon  Sword
+//	Armor   Shield
+//	Trinket Lucky Charm
+func statsLines(p *Player) []string {
+	stats := p.Stats()
+	lines := []string{fmt.Sprintf("Attack %-3d Defense %d", stats.Attack, stats.Defense)}
+	for _, slot := range Slots {
+		name := p.Equipment[slot]
+		if name == "" {
+			name = "none"
+		}
+		label := strings.ToUpper(slot.String()[:1]) + slot.String()[1:]
+		lines = append(lines, fmt.Sprintf("%-8s", label)+mutedStyle.Render(name))
+	}
+	return lines
+}
+
+func (g game) roomContent() string {
+	inner := sidebarWidth - 4
+	content := lipgloss.NewStyle().Width(inner).Render(g.room.Description)
+	if m := g.room.Monster; m != nil {
+		content += "\n\n" + errorStyle.Render(fmt.Sprintf("%-10s HP %d / %d", m.Name, m.HP, m.MaxHP))
+	}
+	if g.room.Shop != nil {
+		content += "\n\n" + commandStyle.Render("A "+strings.ToLower(g.room.Shop.Name)+", to trade with")
+	}
+	content += "\n\n" + headingStyle.Render("On the floor")
+	for _, item := range g.room.Floor {
+		content += "\n" + item.Name
+		if item.Quantity > 1 {
+			content += fmt.Sprintf(" x%d", item.Quantity)
+		}
+	}
+	if len(g.room.Floor) == 0 {
+		content += "\n" + mutedStyle.Render("nothing")
+	}
+	return content
+}
+
+// questLogContent lists every quest with its objectives, done or not:
+//
+//	Goblin Trouble                          25 gold
+//	The peddler wants the goblin gone
+//	  [x] Defeat the Goblin (1/1)
+func (g game) questLogContent(width int) string {
+	wrap := lipgloss.NewStyle().Width(width - 4)
+	var blocks []string
+	for _, q := range g.quests.Quests {
+		name, reward := headingStyle.Render(q.Name), mutedStyle.Render(fmt.Sprintf("%d gold", q.Reward))
+		if q.Completed {
+			name, reward = achievementStyle.Render(q.Name), achievementStyle.Render("done")
+		}
+		gap := max(1, width-4-lipgloss.Width(name)-lipgloss.Width(reward))
+		lines := []string{name + strings.Repeat(" ", gap) + reward, wrap.Inherit(mutedStyle).Render(q.Description)}
+		for _, o := range q.Objectives {
+			if o.Done() {
+				lines = append(lines, commandStyle.Render("  [x] "+o.String()))
+			} else {
+				lines = append(lines, "  [ ] "+o.String())
+			}
+		}
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+	if len(blocks) == 0 {
+		return mutedStyle.Render("No quests")
+	}
+	return strings.Join(blocks, "\n\n") + "\n\n" + mutedStyle.Render("Type any command to go back to the log")
+}
+
+// logWidth is the width of the log panel, right of the sidebar
+func (g game) logWidth() int {
+	return max(20, g.width-sidebarWidth)
+}
+
+// logContent is the end of the message log: as many of the latest lines as
+// fit, wrapped to the panel
+func (g game) logContent(width, height int) string {
+	wrap := lipgloss.NewStyle().Width(width - 4)
+	var lines []string
+	for _, l := range g.log {
+		style := wrap
+		switch l.kind {
+		case logCommand:
+			style = wrap.Inherit(commandStyle)
+		case logError:
+			style = wrap.Inherit(errorStyle)
+		case logAchievement:
+			style = wrap.Inherit(achievementStyle)
+		}
+		lines = append(lines, strings.Split(style.Render(l.text), "\n")...)
+	}
+	// The border and the panel title take three lines
+	if fit := height - 3; len(lines) > fit {
+		lines = lines[len(lines)-max(0, fit):]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// panel draws content in a bordered box of exactly width x height cells,
+// under a title
+func panel(title, content string, width, height int) string {
+	return panelStyle.
+		Width(width - 2).Height(height - 2).
+		MaxWidth(width).MaxHeight(height).
+		Render(headingStyle.Render(title) + "\n" + content)
+}
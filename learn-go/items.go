@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Usable is anything the player can do something with. Each kind of item
+// is its own type with its own Use, so adding one never touches UseItem.
+type Usable interface {
+	Use(p *Player) (string, error)
+}
+
+// usables maps item names to what using them does
+var usables = map[string]Usable{}
+
+// RegisterUsable makes the items called name usable
+func RegisterUsable(name string, u Usable) {
+	usables[name] = u
+}
+
+func init() {
+	RegisterUsable("Sword", Weapon{Name: "Sword", Damage: 8})
+	RegisterUsable("Axe", Weapon{Name: "Axe", Damage: 10})
+	RegisterUsable("Bow", Weapon{Name: "Bow", Damage: 6, Ammo: "Arrow"})
+	RegisterUsable("Shield", Armor{Name: "Shield", Defense: 3})
+	RegisterUsable("Potion", Consumable{Name: "Potion", Verb: "drink", Heal: 25})
+	RegisterUsable("Lucky Charm", Trinket{Name: "Lucky Charm", Attack: 1, Defense: 1})
+	RegisterUsable("Bone Ring", Trinket{Name: "Bone Ring", Attack: 3})
+
+	// Crafted items, see recipes.yaml
+	RegisterUsable("Flaming Sword", Weapon{Name: "Flaming Sword", Damage: 13})
+	RegisterUsable("Spiked Shield", Armor{Name: "Spiked Shield", Defense: 5})
+	RegisterUsable("Greater Potion", Consumable{Name: "Greater Potion", Verb: "drink", Heal: 60})
+}
+
+// Weapon is readied when used, in the weapon slot, and is what the player
+// attacks with
+type Weapon struct {
+	Name   string
+	Damage int
+	Ammo   string // item used up by every attack, if any
+}
+
+func (w Weapon) Use(p *Player) (string, error) {
+	if w.Ammo != "" && p.Count(w.Ammo) == 0 {
+		return "", fmt.Errorf("the %s is no use without %ss", w.Name, w.Ammo)
+	}
+	p.Equipment[WeaponSlot] = w.Name
+	return fmt.Sprintf("You ready the %s (%d damage)", w.Name, w.Damage), nil
+}
+
+// Armor is worn when used, in the armor slot, and takes Defense off every
+// hit
+type Armor struct {
+	Name    string
+	Defense int
+}
+
+func (a Armor) Use(p *Player) (string, error) {
+	p.Equipment[ArmorSlot] = a.Name
+	return fmt.Sprintf("You put on the %s (+%d defense)", a.Name, a.Defense), nil
+}
+
+// Trinket is worn when used, in the trinket slot, and adds a little to both
+// attack and defense
+type Trinket struct {
+	Name    string
+	Attack  int
+	Defense int
+}
+
+func (t Trinket) Use(p *Player) (string, error) {
+	p.Equipment[TrinketSlot] = t.Name
+	return fmt.Sprintf("You put on the %s (+%d attack, +%d defense)", t.Name, t.Attack, t.Defense), nil
+}
+
+// Consumable heals the player and is used up, one at a time
+type Consumable struct {
+	Name string
+	Verb string // what the player does with it: drink, eat
+	Heal int
+}
+
+func (c Consumable) Use(p *Player) (string, error) {
+	if p.HP >= p.MaxHP {
+		return "", fmt.Errorf("you are already at full health, the %s would be wasted", c.Name)
+	}
+	healed := min(c.Heal, p.MaxHP-p.HP)
+	p.HP += healed
+	p.removeItem(c.Name, 1)
+	return fmt.Sprintf("You %s the %s and recover %d HP", c.Verb, c.Name, healed), nil
+}
@@ -1,83 +1,113 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
-	"slices"
-)
-
-type Item struct {
-	Name string
-	Type string
-}
+	"os"
+	"path/filepath"
+	"time"
 
-type Player struct {
-	Name      string
-	Inventory []Item
-}
+	tea "github.com/charmbracelet/bubbletea"
+)
 
-// Modifies the player's inventory
-func (p *Player) PickUpItem(item Item) {
-	p.Inventory = append(p.Inventory, Item{
-		Name: item.Name,
-		Type: item.Type,
-	})
+var worldConfig = WorldConfig{
+	Tick:         time.Second,
+	PoisonDamage: 2,
+	RegenEvery:   5,
+	RegenAmount:  3,
+	SpawnEvery:   40,
+	Spawns:       []string{"Spider", "Rat", "Skeleton"},
 }
 
-// Removes the item from the player's inventory
-func (p *Player) DropItem(itemName string) {
-	// p.Inventory = slices.DeleteFunc(p.Inventory, func(item Item) bool {
-	// 	return item.Name == itemName
-	// })
+func main() {
+	// The same seed makes the same dungeon below the cellar
+	seed := flag.Uint64("seed", uint64(time.Now().UnixNano()), "seed of the dungeon")
+	flag.Parse()
 
-	for i, item := range p.Inventory {
-		if item.Name == itemName {
-			p.Inventory = append(p.Inventory[:i], p.Inventory[i+1:]...)
-		}
+	// Everything that happens is published on the bus: the game logs it on
+	// screen, the event log writes it to a file and achievements count it
+	events := NewBus()
+	logFile, err := os.Create(filepath.Join(os.TempDir(), "learn-go-events.log"))
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
 	}
-}
-
-// Uses the item in the player's inventory
-func (p *Player) UseItem(itemName string) {
-	exists := slices.ContainsFunc(p.Inventory, func(item Item) bool {
-		return item.Name == itemName
-	})
+	defer logFile.Close()
+	events.Subscribe(EventLogger(logFile))
+	achievements := NewAchievements(events)
 
-	if exists {
-		if itemName == "Sword" {
-			fmt.Println("You swing the sword at the monster")
-		} else if itemName == "Shield" {
-			fmt.Println("You block the monster's attack")
-		} else if itemName == "Bow" {
-			fmt.Println("You fire an arrow at the monster")
-		} else if itemName == "Potion" {
-			// Potien is one time use
-			fmt.Println("You drink the potion and feel refreshed")
-			p.Inventory = slices.DeleteFunc(p.Inventory, func(item Item) bool {
-				return item.Name == itemName
-			})
-		}
-	} else {
-		fmt.Println("Item not found")
-	}
-}
-
-func main() {
-	player := Player{
+	player := &Player{
 		Name: "John Doe",
 		Inventory: []Item{
-			{Name: "Sword", Type: "Weapon"},
-			{Name: "Shield", Type: "Armor"},
-			{Name: "Bow", Type: "Ranged"},
-			{Name: "Potion", Type: "Consumable"},
+			{Name: "Sword", Type: "Weapon", Quantity: 1, Weight: 3, MaxStack: 1, Value: 15},
+			{Name: "Shield", Type: "Armor", Quantity: 1, Weight: 5, MaxStack: 1, Value: 12},
+			{Name: "Bow", Type: "Ranged", Quantity: 1, Weight: 1.5, MaxStack: 1, Value: 18},
+			{Name: "Potion", Type: "Consumable", Quantity: 2, Weight: 0.5, MaxStack: 5, Value: 8},
+		},
+		Capacity: 15,
+		HP:       70,
+		MaxHP:    100,
+		Gold:     20,
+		Events:   events,
+	}
+	room := &Room{
+		Name:        "Damp Cellar",
+		Description: "Water drips from the ceiling. Something growls in the dark corner.",
+		Floor: []Item{
+			{Name: "Axe", Type: "Weapon", Quantity: 1, Weight: 4, MaxStack: 1, Value: 14},
+			{Name: "Torch", Type: "Tool", Quantity: 1, Weight: 1, MaxStack: 1, Value: 2},
+			{Name: "Lucky Charm", Type: "Trinket", Quantity: 1, Weight: 0.1, MaxStack: 1, Value: 30},
+			{Name: "Arrow", Type: "Ammunition", Quantity: 12, Weight: 0.1, MaxStack: 20, Value: 1},
+			{Name: "Potion", Type: "Consumable", Quantity: 4, Weight: 0.5, MaxStack: 5, Value: 8},
+		},
+		Monster: NewMonster("Goblin"),
+		Shop: &Shop{
+			Name: "Peddler",
+			Stock: []Item{
+				{Name: "Potion", Type: "Consumable", Quantity: 5, Weight: 0.5, MaxStack: 5, Value: 8},
+				{Name: "Arrow", Type: "Ammunition", Quantity: 30, Weight: 0.1, MaxStack: 20, Value: 1},
+				{Name: "Bone Ring", Type: "Trinket", Quantity: 1, Weight: 0.1, MaxStack: 1, Value: 40},
+			},
+			Prices: map[string]int{"Potion": 12, "Arrow": 2, "Bone Ring": 60},
 		},
 	}
 
-	player.PickUpItem(Item{Name: "Axe", Type: "Weapon"})
+	quests := NewQuestLog(player,
+		&Quest{
+			Name:        "Goblin Trouble",
+			Description: "The peddler won't sleep with a goblin in the cellar.",
+			Objectives:  []Objective{&Defeat{Monster: "Goblin", Count: 1}},
+			Reward:      25,
+		},
+		&Quest{
+			Name:        "Fletcher",
+			Description: "Nights in the cellar are long. Gather arrows for them.",
+			Objectives:  []Objective{&Collect{Item: "Arrow", Quantity: 20}},
+			Reward:      10,
+		},
+		&Quest{
+			Name:        "Pest Control",
+			Description: "Something crawls out of the dark now and then. Deal with it.",
+			Objectives:  []Objective{&Defeat{Monster: "Spider", Count: 1}, &Defeat{Monster: "Rat", Count: 1}},
+			Reward:      30,
+		},
+	)
 
-	player.UseItem("Sword")
+	// The world clock runs in its own goroutine until the game ends
+	ctx, stop := context.WithCancel(context.Background())
+	world := make(chan WorldEvent)
+	stopped := make(chan struct{})
+	go func() {
+		RunWorld(ctx, worldConfig, world)
+		close(stopped)
+	}()
 
-	player.DropItem("Sword")
-	player.UseItem("Sword")
-	player.UseItem("Potion")
-	player.UseItem("Bow")
+	_, err = tea.NewProgram(newGame(player, room, NewGenerator(*seed, Normal), achievements, quests, world), tea.WithAltScreen()).Run()
+	stop()
+	<-stopped
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
 }
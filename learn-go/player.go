@@ -0,0 +1,190 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+type Item struct {
+	Name     string  `yaml:"name"`
+	Type     string  `yaml:"type"`
+	Quantity int     `yaml:"quantity"`  // how many are in this stack
+	Weight   float64 `yaml:"weight"`    // of one, in kg
+	MaxStack int     `yaml:"max_stack"` // the most that fit in one inventory slot
+	Value    int     `yaml:"value"`     // of one, in gold
+}
+
+// plural is the name of n of the item: "Arrow", "Arrows"
+func plural(name string, n int) string {
+	if n == 1 {
+		return name
+	}
+	return name + "s"
+}
+
+// TotalWeight is the weight of the whole stack
+func (i Item) TotalWeight() float64 {
+	return float64(i.Quantity) * i.Weight
+}
+
+// TotalValue is what the whole stack is worth
+func (i Item) TotalValue() int {
+	return i.Quantity * i.Value
+}
+
+type Player struct {
+	Name      string
+	Inventory []Item
+	Capacity  float64 // the most the player can carry, in kg
+	HP, MaxHP int
+	Gold      int
+	Equipment [numSlots]string // the name of the item in each slot, if any
+	Poisoned  int              // world ticks of poison left
+	Events    *Bus             // where everything the player does is published
+}
+
+// Load is the weight of everything the player carries
+func (p *Player) Load() float64 {
+	load := 0.0
+	for _, item := range p.Inventory {
+		load += item.TotalWeight()
+	}
+	return load
+}
+
+// Count is how many of the item the player carries, over all stacks
+func (p *Player) Count(itemName string) int {
+	n := 0
+	for _, item := range p.Inventory {
+		if item.Name == itemName {
+			n += item.Quantity
+		}
+	}
+	return n
+}
+
+// ErrNotCarried is returned for items the player does not have
+var ErrNotCarried = errors.New("not carried")
+
+// ErrTooHeavy is returned when picking something up would go over the
+// player's carry capacity
+var ErrTooHeavy = errors.New("too heavy")
+
+// Adds the item to the player's inventory, topping up stacks of the same
+// item before starting new ones. Nothing is picked up if it all does not fit.
+func (p *Player) PickUpItem(item Item) error {
+	if item.Quantity < 0 {
+		return fmt.Errorf("cannot pick up %d %s", item.Quantity, item.Name)
+	}
+	item.Quantity = max(item.Quantity, 1)
+	if err := p.addItem(item); err != nil {
+		return err
+	}
+	p.Events.Publish(ItemPickedUp{Item: item})
+	return nil
+}
+
+// addItem puts items in the inventory without picking them up, for the ones
+// that come from somewhere else
+func (p *Player) addItem(item Item) error {
+	item.MaxStack = max(item.MaxStack, 1)
+
+	if load := p.Load(); load+item.TotalWeight() > p.Capacity {
+		return fmt.Errorf("%w: %d x %s is %.1f kg, and you can only carry %.1f kg more",
+			ErrTooHeavy, item.Quantity, item.Name, item.TotalWeight(), max(0, p.Capacity-load))
+	}
+
+	for i := range p.Inventory {
+		stack := &p.Inventory[i]
+		if stack.Name != item.Name || stack.Quantity >= stack.MaxStack {
+			continue
+		}
+		n := min(item.Quantity, stack.MaxStack-stack.Quantity)
+		stack.Quantity += n
+		item.Quantity -= n
+	}
+	for item.Quantity > 0 {
+		stack := item
+		stack.Quantity = min(item.Quantity, item.MaxStack)
+		p.Inventory = append(p.Inventory, stack)
+		item.Quantity -= stack.Quantity
+	}
+	return nil
+}
+
+// All is the quantity that drops every one of an item
+const All = math.MaxInt
+
+// Removes up to quantity of the item from the player's inventory (1 for
+// one, All for every one of them), emptying the last stacks first, and
+// returns what was removed. Dropping something the player does not carry
+// is an ErrNotCarried.
+func (p *Player) DropItem(itemName string, quantity int) (Item, error) {
+	if quantity < 1 {
+		return Item{}, fmt.Errorf("cannot drop %d %s", quantity, itemName)
+	}
+	if p.Count(itemName) == 0 {
+		return Item{}, fmt.Errorf("%w: you have no %s", ErrNotCarried, itemName)
+	}
+	dropped := p.removeItem(itemName, quantity)
+	p.Events.Publish(ItemDropped{Item: dropped})
+	return dropped, nil
+}
+
+// removeItem takes items out of the inventory without dropping them, for
+// the ones that are used up
+func (p *Player) removeItem(itemName string, quantity int) Item {
+	dropped := Item{Name: itemName}
+
+	// Going backwards, removing a stack does not move the ones still to visit
+	for i := len(p.Inventory) - 1; i >= 0 && dropped.Quantity < quantity; i-- {
+		stack := &p.Inventory[i]
+		if stack.Name != itemName {
+			continue
+		}
+		if dropped.Quantity == 0 {
+			dropped = *stack
+			dropped.Quantity = 0
+		}
+		n := min(stack.Quantity, quantity-dropped.Quantity)
+		stack.Quantity -= n
+		dropped.Quantity += n
+		if stack.Quantity == 0 {
+			p.Inventory = slices.Delete(p.Inventory, i, i+1)
+		}
+	}
+
+	// Whatever the player no longer has can't stay equipped
+	if p.Count(itemName) == 0 {
+		for _, slot := range Slots {
+			if p.Equipment[slot] == itemName {
+				p.Equipment[slot] = ""
+			}
+		}
+	}
+	return dropped
+}
+
+// Uses the item in the player's inventory and describes what happened.
+// What using it does is up to the Usable registered for its name; items
+// that can be equipped are, in their own slot.
+func (p *Player) UseItem(itemName string) (string, error) {
+	if p.Count(itemName) == 0 {
+		return "", fmt.Errorf("%w: you have no %s", ErrNotCarried, itemName)
+	}
+	u, ok := usables[itemName]
+	if !ok {
+		return "", fmt.Errorf("you can't think of a way to use the %s", itemName)
+	}
+	if e, ok := u.(Equippable); ok {
+		return p.Equip(e.Slot(), itemName)
+	}
+	msg, err := u.Use(p)
+	if err != nil {
+		return "", err
+	}
+	p.Events.Publish(ItemUsed{Item: itemName, Message: msg})
+	return msg, nil
+}
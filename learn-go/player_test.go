@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// stacks lists the inventory as "Name xQuantity", so stack sizes show up in
+// test failures
+func stacks(items []Item) []string {
+	var s []string
+	for _, item := range items {
+		s = append(s, fmt.Sprintf("%s x%d", item.Name, item.Quantity))
+	}
+	return s
+}
+
+// withQuantity is item with a different stack size
+func withQuantity(item Item, n int) Item {
+	item.Quantity = n
+	return item
+}
+
+func TestDropItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []Item
+		drop     string
+		quantity int
+		dropped  int
+		want     []string
+		wantErr  error
+	}{
+		{name: "empty inventory", drop: "Sword", quantity: 1, wantErr: ErrNotCarried},
+		{name: "not carried", items: []Item{sword, bow}, drop: "Axe", quantity: 1, want: []string{"Sword x1", "Bow x1"}, wantErr: ErrNotCarried},
+		{name: "name is exact", items: []Item{sword}, drop: "sword", quantity: 1, want: []string{"Sword x1"}, wantErr: ErrNotCarried},
+
+		// Boundaries: the item first, last, in the middle and alone
+		{name: "first", items: []Item{sword, bow, torch}, drop: "Sword", quantity: 1, dropped: 1, want: []string{"Bow x1", "Torch x1"}},
+		{name: "last", items: []Item{sword, bow, torch}, drop: "Torch", quantity: 1, dropped: 1, want: []string{"Sword x1", "Bow x1"}},
+		{name: "middle", items: []Item{sword, bow, torch}, drop: "Bow", quantity: 1, dropped: 1, want: []string{"Sword x1", "Torch x1"}},
+		{name: "only item", items: []Item{sword}, drop: "Sword", quantity: 1, dropped: 1},
+
+		// Duplicates: ranging over the slice while deleting from it used to
+		// skip the entry after each one removed
+		{name: "one of two next to each other", items: []Item{sword, sword, bow}, drop: "Sword", quantity: 1, dropped: 1, want: []string{"Sword x1", "Bow x1"}},
+		{name: "all of two next to each other", items: []Item{sword, sword, bow}, drop: "Sword", quantity: All, dropped: 2, want: []string{"Bow x1"}},
+		{name: "all of three in a row at the end", items: []Item{bow, sword, sword, sword}, drop: "Sword", quantity: All, dropped: 3, want: []string{"Bow x1"}},
+		{name: "all of two apart", items: []Item{sword, bow, sword}, drop: "Sword", quantity: All, dropped: 2, want: []string{"Bow x1"}},
+
+		// Stacks: partial drops take from the last stack first
+		{name: "part of a stack", items: []Item{withQuantity(arrows, 10)}, drop: "Arrow", quantity: 3, dropped: 3, want: []string{"Arrow x7"}},
+		{name: "exactly a stack", items: []Item{withQuantity(potion, 5), sword, withQuantity(potion, 2)}, drop: "Potion", quantity: 2, dropped: 2, want: []string{"Potion x5", "Sword x1"}},
+		{name: "across stacks", items: []Item{withQuantity(potion, 5), sword, withQuantity(potion, 2)}, drop: "Potion", quantity: 3, dropped: 3, want: []string{"Potion x4", "Sword x1"}},
+		{name: "all stacks", items: []Item{withQuantity(potion, 5), sword, withQuantity(potion, 2)}, drop: "Potion", quantity: All, dropped: 7, want: []string{"Sword x1"}},
+		{name: "more than carried", items: []Item{withQuantity(arrows, 4)}, drop: "Arrow", quantity: 10, dropped: 4},
+
+		{name: "zero", items: []Item{sword}, drop: "Sword", quantity: 0, want: []string{"Sword x1"}, wantErr: errAny},
+		{name: "negative", items: []Item{sword}, drop: "Sword", quantity: -1, want: []string{"Sword x1"}, wantErr: errAny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPlayer(slices.Clone(tt.items)...)
+
+			got, err := p.DropItem(tt.drop, tt.quantity)
+			switch {
+			case tt.wantErr == errAny && err == nil:
+				t.Fatal("want an error")
+			case tt.wantErr != errAny && !errors.Is(err, tt.wantErr):
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (got.Name != tt.drop || got.Quantity != tt.dropped) {
+				t.Errorf("dropped %d %s, want %d %s", got.Quantity, got.Name, tt.dropped, tt.drop)
+			}
+			if inv := stacks(p.Inventory); !slices.Equal(inv, tt.want) {
+				t.Errorf("inventory = %v, want %v", inv, tt.want)
+			}
+		})
+	}
+}
+
+// errAny stands for "some error" in test tables
+var errAny = errors.New("any error")
+
+func TestDropReadiedItem(t *testing.T) {
+	p := newTestPlayer(sword, sword, shield)
+	p.UseItem("Sword")
+	p.UseItem("Shield")
+
+	p.DropItem("Sword", 1)
+	if p.Equipment[WeaponSlot] != "Sword" {
+		t.Error("dropping one of two swords put the sword away")
+	}
+	p.DropItem("Sword", 1)
+	p.DropItem("Shield", All)
+	if p.Equipment != [numSlots]string{} {
+		t.Errorf("equipment = %q after dropping all of it", p.Equipment)
+	}
+}
+
+func TestPickUpItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []Item
+		capacity float64
+		pick     Item
+		want     []string
+		wantErr  error
+	}{
+		{name: "into an empty inventory", pick: sword, want: []string{"Sword x1"}},
+		{name: "no quantity is one", pick: withQuantity(torch, 0), want: []string{"Torch x1"}},
+		{name: "tops up a stack", items: []Item{withQuantity(arrows, 15)}, pick: withQuantity(arrows, 3), want: []string{"Arrow x18"}},
+		{name: "overflows into a new stack", items: []Item{withQuantity(arrows, 15)}, pick: withQuantity(arrows, 10), want: []string{"Arrow x20", "Arrow x5"}},
+		{name: "more than a stack", pick: withQuantity(potion, 12), want: []string{"Potion x5", "Potion x5", "Potion x2"}},
+		{name: "tops up the first stack with room", items: []Item{withQuantity(potion, 5), sword, withQuantity(potion, 3)}, pick: withQuantity(potion, 1), want: []string{"Potion x5", "Sword x1", "Potion x4"}},
+		{name: "unstackable", items: []Item{sword}, pick: sword, want: []string{"Sword x1", "Sword x1"}},
+		{name: "exactly full", items: []Item{shield}, capacity: 8, pick: sword, want: []string{"Shield x1", "Sword x1"}},
+		{name: "too heavy", items: []Item{shield}, capacity: 7, pick: sword, want: []string{"Shield x1"}, wantErr: ErrTooHeavy},
+		{name: "negative", pick: withQuantity(arrows, -2), wantErr: errAny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPlayer(slices.Clone(tt.items)...)
+			if tt.capacity > 0 {
+				p.Capacity = tt.capacity
+			}
+
+			err := p.PickUpItem(tt.pick)
+			switch {
+			case tt.wantErr == errAny && err == nil:
+				t.Fatal("want an error")
+			case tt.wantErr != errAny && !errors.Is(err, tt.wantErr):
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if inv := stacks(p.Inventory); !slices.Equal(inv, tt.want) {
+				t.Errorf("inventory = %v, want %v", inv, tt.want)
+			}
+		})
+	}
+}
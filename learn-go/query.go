@@ -0,0 +1,87 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Predicate reports whether a value is wanted. Predicates are combined
+// with And, Or and Not, so queries are built from small pieces.
+type Predicate[T any] func(T) bool
+
+// And wants values every predicate wants; with none, it wants everything
+func And[T any](preds ...Predicate[T]) Predicate[T] {
+	return func(v T) bool {
+		for _, pred := range preds {
+			if !pred(v) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or wants values any of the predicates wants
+func Or[T any](preds ...Predicate[T]) Predicate[T] {
+	return func(v T) bool {
+		return slices.ContainsFunc(preds, func(pred Predicate[T]) bool { return pred(v) })
+	}
+}
+
+// Not wants the values pred does not
+func Not[T any](pred Predicate[T]) Predicate[T] {
+	return func(v T) bool { return !pred(v) }
+}
+
+// Filter is the values keep wants, in their order. The values are copied,
+// so changing the result leaves the original alone.
+func Filter[T any](values []T, keep Predicate[T]) []T {
+	var kept []T
+	for _, v := range values {
+		if keep(v) {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// SortBy is a sorted copy of values, ordered by key from smallest to
+// largest. Values with equal keys keep their order.
+func SortBy[T any, K cmp.Ordered](values []T, key func(T) K) []T {
+	sorted := slices.Clone(values)
+	slices.SortStableFunc(sorted, func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	})
+	return sorted
+}
+
+// OfType wants items of the type, in any case
+func OfType(itemType string) Predicate[Item] {
+	return func(item Item) bool { return strings.EqualFold(item.Type, itemType) }
+}
+
+// NamePrefix wants items whose name starts with prefix, in any case
+func NamePrefix(prefix string) Predicate[Item] {
+	return func(item Item) bool {
+		return strings.HasPrefix(strings.ToLower(item.Name), strings.ToLower(prefix))
+	}
+}
+
+// IsUsable wants items that do something when used
+func IsUsable(item Item) bool {
+	_, ok := usables[item.Name]
+	return ok
+}
+
+// Sort keys for items
+var (
+	ItemName   = func(item Item) string { return item.Name }
+	ItemWeight = func(item Item) float64 { return item.TotalWeight() }
+	ItemValue  = func(item Item) int { return item.TotalValue() }
+)
+
+// Query is the stacks in the player's inventory that match every predicate
+func (p *Player) Query(preds ...Predicate[Item]) []Item {
+	return Filter(p.Inventory, And(preds...))
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// names lists the item names, to compare results at a glance
+func names(items []Item) []string {
+	var n []string
+	for _, item := range items {
+		n = append(n, item.Name)
+	}
+	return n
+}
+
+var (
+	axe   = Item{Name: "Axe", Type: "Weapon", Quantity: 1, Weight: 4, MaxStack: 1, Value: 14}
+	torch = Item{Name: "Torch", Type: "Tool", Quantity: 1, Weight: 1, MaxStack: 1, Value: 2}
+
+	// backpack weighs: Sword 3, Arrow 0.2, Shield 5, Potion 0.5, Axe 4, Bow 1.5, Torch 1
+	// and is worth: Sword 15, Arrow 2, Shield 12, Potion 8, Axe 14, Bow 18, Torch 2
+	backpack = []Item{sword, arrows, shield, potion, axe, bow, torch}
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		keep Predicate[Item]
+		want []string
+	}{
+		{"by type", OfType("Weapon"), []string{"Sword", "Axe"}},
+		{"type in any case", OfType("armor"), []string{"Shield"}},
+		{"unknown type", OfType("Food"), nil},
+		{"name prefix", NamePrefix("s"), []string{"Sword", "Shield"}},
+		{"whole name", NamePrefix("Bow"), []string{"Bow"}},
+		{"empty prefix", NamePrefix(""), names(backpack)},
+		{"usable", IsUsable, []string{"Sword", "Shield", "Potion", "Axe", "Bow"}},
+		{"and", And(OfType("Weapon"), NamePrefix("a")), []string{"Axe"}},
+		{"and of nothing", And[Item](), names(backpack)},
+		{"or", Or(OfType("Armor"), OfType("Ranged")), []string{"Shield", "Bow"}},
+		{"not", Not(IsUsable), []string{"Arrow", "Torch"}},
+		{"custom", func(item Item) bool { return item.Weight >= 4 }, []string{"Shield", "Axe"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := names(Filter(backpack, tt.keep))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	tests := []struct {
+		name string
+		sort func([]Item) []Item
+		want []string
+	}{
+		{"name", func(items []Item) []Item { return SortBy(items, ItemName) }, []string{"Arrow", "Axe", "Bow", "Potion", "Shield", "Sword", "Torch"}},
+		{"weight", func(items []Item) []Item { return SortBy(items, ItemWeight) }, []string{"Arrow", "Potion", "Torch", "Bow", "Sword", "Axe", "Shield"}},
+		// Arrow and Torch are worth the same and stay in backpack order
+		{"value", func(items []Item) []Item { return SortBy(items, ItemValue) }, []string{"Arrow", "Torch", "Potion", "Shield", "Axe", "Sword", "Bow"}},
+		{"custom key", func(items []Item) []Item { return SortBy(items, func(item Item) int { return len(item.Name) }) }, []string{"Axe", "Bow", "Sword", "Arrow", "Torch", "Shield", "Potion"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := names(backpack)
+			got := names(tt.sort(backpack))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(names(backpack), before) {
+				t.Error("sorting changed the original")
+			}
+		})
+	}
+}
+
+// The helpers are generic, so they work on anything, not only items
+func TestGenericHelpers(t *testing.T) {
+	even := Predicate[int](func(n int) bool { return n%2 == 0 })
+	if got := Filter([]int{5, 2, 8, 3, 4}, even); !slices.Equal(got, []int{2, 8, 4}) {
+		t.Errorf("Filter even = %v", got)
+	}
+	if got := SortBy([]string{"ccc", "a", "bb"}, func(s string) int { return len(s) }); !slices.Equal(got, []string{"a", "bb", "ccc"}) {
+		t.Errorf("SortBy length = %v", got)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	p := newTestPlayer(backpack...)
+	tests := []struct {
+		name  string
+		preds []Predicate[Item]
+		want  []string
+	}{
+		{"everything", nil, names(backpack)},
+		{"one predicate", []Predicate[Item]{OfType("Consumable")}, []string{"Potion"}},
+		{"all must match", []Predicate[Item]{IsUsable, NamePrefix("s")}, []string{"Sword", "Shield"}},
+		{"nothing matches", []Predicate[Item]{OfType("Weapon"), OfType("Tool")}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(p.Query(tt.preds...)); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
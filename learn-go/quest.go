@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Objective is one thing a quest asks for. It follows its own progress from
+// the events on the bus, so nothing in the game has to know about quests.
+type Objective interface {
+	Progress(e Event)
+	Done() bool
+	String() string // what to do, and how far along it is
+}
+
+// Collect asks for Quantity of an item, picked up, bought or crafted from
+// the moment the quest starts
+type Collect struct {
+	Item     string
+	Quantity int
+	Have     int
+}
+
+func (c *Collect) Progress(e Event) {
+	var got Item
+	switch e := e.(type) {
+	case ItemPickedUp:
+		got = e.Item
+	case ItemBought:
+		got = e.Item
+	case ItemCrafted:
+		got = e.Item
+	}
+	if got.Name == c.Item {
+		c.Have = min(c.Quantity, c.Have+got.Quantity)
+	}
+}
+
+func (c *Collect) Done() bool { return c.Have >= c.Quantity }
+
+func (c *Collect) String() string {
+	return fmt.Sprintf("Collect %d %s (%d/%d)", c.Quantity, plural(c.Item, c.Quantity), c.Have, c.Quantity)
+}
+
+// Defeat asks for Count monsters of a kind to be killed
+type Defeat struct {
+	Monster string
+	Count   int
+	Killed  int
+}
+
+func (d *Defeat) Progress(e Event) {
+	if e, ok := e.(MonsterKilled); ok && e.Monster == d.Monster {
+		d.Killed = min(d.Count, d.Killed+1)
+	}
+}
+
+func (d *Defeat) Done() bool { return d.Killed >= d.Count }
+
+func (d *Defeat) String() string {
+	if d.Count == 1 {
+		return fmt.Sprintf("Defeat the %s (%d/1)", d.Monster, d.Killed)
+	}
+	return fmt.Sprintf("Defeat %d %s (%d/%d)", d.Count, plural(d.Monster, d.Count), d.Killed, d.Count)
+}
+
+// Quest is a set of objectives, rewarded with gold once all of them are done,
+// in any order
+type Quest struct {
+	Name        string
+	Description string
+	Objectives  []Objective
+	Reward      int // gold
+	Completed   bool
+}
+
+// Done reports whether every objective is
+func (q *Quest) Done() bool {
+	for _, o := range q.Objectives {
+		if !o.Done() {
+			return false
+		}
+	}
+	return true
+}
+
+// QuestLog follows the player's quests. It is one more subscriber on the
+// player's event bus: every event moves the objectives along, and a quest
+// whose objectives are all done pays its reward and publishes QuestCompleted.
+type QuestLog struct {
+	player *Player
+	Quests []*Quest
+}
+
+func NewQuestLog(p *Player, quests ...*Quest) *QuestLog {
+	l := &QuestLog{player: p, Quests: quests}
+	p.Events.Subscribe(l.handle)
+	return l
+}
+
+func (l *QuestLog) handle(e Event) {
+	for _, q := range l.Quests {
+		if q.Completed {
+			continue
+		}
+		for _, o := range q.Objectives {
+			o.Progress(e)
+		}
+		if q.Done() {
+			q.Completed = true
+			l.player.Gold += q.Reward
+			l.player.Events.Publish(QuestCompleted{Quest: q.Name, Reward: q.Reward})
+		}
+	}
+}
+
+// Completed is how many of the quests are done
+func (l *QuestLog) Completed() int {
+	n := 0
+	for _, q := range l.Quests {
+		if q.Completed {
+			n++
+		}
+	}
+	return n
+}
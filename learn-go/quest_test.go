@@ -0,0 +1,100 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestObjectives(t *testing.T) {
+	tests := []struct {
+		name      string
+		objective Objective
+		events    []Event
+		want      string
+		done      bool
+	}{
+		{
+			name: "collect counts what is picked up", objective: &Collect{Item: "Arrow", Quantity: 20},
+			events: []Event{ItemPickedUp{Item: withQuantity(arrows, 12)}},
+			want:   "Collect 20 Arrows (12/20)",
+		},
+		{
+			name: "collect counts bought and crafted items", objective: &Collect{Item: "Potion", Quantity: 3},
+			events: []Event{ItemBought{Item: withQuantity(potion, 2)}, ItemCrafted{Item: potion}},
+			want:   "Collect 3 Potions (3/3)", done: true,
+		},
+		{
+			name: "collect stops at the quantity", objective: &Collect{Item: "Arrow", Quantity: 5},
+			events: []Event{ItemPickedUp{Item: withQuantity(arrows, 12)}},
+			want:   "Collect 5 Arrows (5/5)", done: true,
+		},
+		{
+			name: "collect ignores other items and other events", objective: &Collect{Item: "Arrow", Quantity: 5},
+			events: []Event{ItemPickedUp{Item: sword}, ItemDropped{Item: arrows}, ItemSold{Item: arrows}},
+			want:   "Collect 5 Arrows (0/5)",
+		},
+		{
+			name: "defeat one", objective: &Defeat{Monster: "Goblin", Count: 1},
+			events: []Event{MonsterDamaged{Monster: "Goblin"}, MonsterKilled{Monster: "Goblin"}},
+			want:   "Defeat the Goblin (1/1)", done: true,
+		},
+		{
+			name: "defeat several", objective: &Defeat{Monster: "Rat", Count: 3},
+			events: []Event{MonsterKilled{Monster: "Rat"}, MonsterKilled{Monster: "Spider"}, MonsterKilled{Monster: "Rat"}},
+			want:   "Defeat 3 Rats (2/3)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, e := range tt.events {
+				tt.objective.Progress(e)
+			}
+			if got := tt.objective.String(); got != tt.want {
+				t.Errorf("objective = %q, want %q", got, tt.want)
+			}
+			if tt.objective.Done() != tt.done {
+				t.Errorf("done = %v, want %v", tt.objective.Done(), tt.done)
+			}
+		})
+	}
+}
+
+func TestQuestLog(t *testing.T) {
+	bus := NewBus()
+	p := newTestPlayer()
+	p.Events = bus
+	pests := &Quest{
+		Name:       "Pest Control",
+		Objectives: []Objective{&Defeat{Monster: "Spider", Count: 1}, &Defeat{Monster: "Rat", Count: 1}},
+		Reward:     30,
+	}
+	arrowQuest := &Quest{Name: "Fletcher", Objectives: []Objective{&Collect{Item: "Arrow", Quantity: 2}}, Reward: 10}
+	log := NewQuestLog(p, pests, arrowQuest)
+	var completed []string
+	bus.Subscribe(func(e Event) {
+		if e, ok := e.(QuestCompleted); ok {
+			completed = append(completed, e.Quest)
+		}
+	})
+
+	// The game publishes as usual; the quests follow along
+	bus.Publish(MonsterKilled{Monster: "Rat"})
+	if pests.Completed || p.Gold != 0 {
+		t.Fatal("a quest was completed with an objective left")
+	}
+	if err := p.PickUpItem(arrows); err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish(MonsterKilled{Monster: "Spider"})
+	bus.Publish(MonsterKilled{Monster: "Spider"})
+
+	if want := []string{"Fletcher", "Pest Control"}; !slices.Equal(completed, want) {
+		t.Errorf("completed %v, want %v", completed, want)
+	}
+	if p.Gold != 40 {
+		t.Errorf("gold = %d, want 40, every reward paid once", p.Gold)
+	}
+	if log.Completed() != 2 {
+		t.Errorf("%d quests completed, want 2", log.Completed())
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Shop sells what it has in stock at its own prices, and buys anything of
+// value for half of what it is worth
+type Shop struct {
+	Name   string
+	Stock  []Item
+	Prices map[string]int // what one of each item costs; without a price it is not for sale
+}
+
+// ErrNotEnoughGold is returned when buying more than the player can pay for
+var ErrNotEnoughGold = errors.New("not enough gold")
+
+// ErrOutOfStock is returned when buying more than the shop has
+var ErrOutOfStock = errors.New("out of stock")
+
+// Price is what one of the item costs at the shop
+func (s *Shop) Price(itemName string) (int, bool) {
+	price, ok := s.Prices[itemName]
+	return price, ok
+}
+
+// Offer is what the shop pays for one of the item: half its value, and
+// nothing for what is worthless
+func (s *Shop) Offer(item Item) int {
+	return item.Value / 2
+}
+
+// InStock is how many of the item the shop has left
+func (s *Shop) InStock(itemName string) int {
+	n := 0
+	for _, item := range s.Stock {
+		if item.Name == itemName {
+			n += item.Quantity
+		}
+	}
+	return n
+}
+
+// Buy pays for quantity of the item and puts them in the player's
+// inventory. Nothing changes hands if the shop does not have them, the
+// player can't pay or can't carry them.
+func (p *Player) Buy(s *Shop, itemName string, quantity int) error {
+	if quantity < 1 {
+		return fmt.Errorf("cannot buy %d %s", quantity, itemName)
+	}
+	price, ok := s.Price(itemName)
+	if !ok {
+		return fmt.Errorf("the %s does not sell %s", s.Name, plural(itemName, 2))
+	}
+	if have := s.InStock(itemName); have < quantity {
+		return fmt.Errorf("%w: the %s only has %d %s", ErrOutOfStock, s.Name, have, plural(itemName, have))
+	}
+	cost := price * quantity
+	if cost > p.Gold {
+		return fmt.Errorf("%w: %d %s cost %d gold, and you have %d",
+			ErrNotEnoughGold, quantity, plural(itemName, quantity), cost, p.Gold)
+	}
+
+	i := indexOf(s.Stock, itemName)
+	item := s.Stock[i]
+	item.Quantity = quantity
+	if err := p.addItem(item); err != nil {
+		return err
+	}
+	p.Gold -= cost
+	s.removeStock(itemName, quantity)
+	p.Events.Publish(ItemBought{Item: item, Price: cost})
+	return nil
+}
+
+// Sell gives the shop quantity of the item, for its offer. Selling what
+// the player does not carry is an ErrNotCarried.
+func (p *Player) Sell(s *Shop, itemName string, quantity int) error {
+	if quantity < 1 {
+		return fmt.Errorf("cannot sell %d %s", quantity, itemName)
+	}
+	if have := p.Count(itemName); have < quantity {
+		return fmt.Errorf("%w: you only have %d %s", ErrNotCarried, have, plural(itemName, have))
+	}
+	i := indexOf(p.Inventory, itemName)
+	offer := s.Offer(p.Inventory[i])
+	if offer == 0 {
+		return fmt.Errorf("the %s does not want your %s", s.Name, plural(itemName, 2))
+	}
+
+	item := p.removeItem(itemName, quantity)
+	p.Gold += offer * quantity
+	s.addStock(item)
+	p.Events.Publish(ItemSold{Item: item, Price: offer * quantity})
+	return nil
+}
+
+// indexOf is the index of the first stack of the item, or -1
+func indexOf(items []Item, itemName string) int {
+	return slices.IndexFunc(items, func(item Item) bool { return item.Name == itemName })
+}
+
+// addStock puts sold items on the shelves, with the others like them. The
+// shop sells them back at their full value.
+func (s *Shop) addStock(item Item) {
+	if s.Prices == nil {
+		s.Prices = map[string]int{}
+	}
+	if _, ok := s.Prices[item.Name]; !ok {
+		s.Prices[item.Name] = item.Value
+	}
+	if i := indexOf(s.Stock, item.Name); i >= 0 {
+		s.Stock[i].Quantity += item.Quantity
+		return
+	}
+	s.Stock = append(s.Stock, item)
+}
+
+// removeStock takes sold items off the shelves
+func (s *Shop) removeStock(itemName string, quantity int) {
+	for i := len(s.Stock) - 1; i >= 0 && quantity > 0; i-- {
+		if s.Stock[i].Name != itemName {
+			continue
+		}
+		n := min(s.Stock[i].Quantity, quantity)
+		s.Stock[i].Quantity -= n
+		quantity -= n
+		if s.Stock[i].Quantity == 0 {
+			s.Stock = slices.Delete(s.Stock, i, i+1)
+		}
+	}
+}
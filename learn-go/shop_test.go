@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func newTestShop() *Shop {
+	return &Shop{
+		Name:   "Peddler",
+		Stock:  []Item{withQuantity(potion, 3), withQuantity(arrows, 20)},
+		Prices: map[string]int{"Potion": 12, "Arrow": 2},
+	}
+}
+
+func TestBuy(t *testing.T) {
+	tests := []struct {
+		name      string
+		gold      int
+		capacity  float64
+		buy       string
+		quantity  int
+		wantGold  int
+		wantCount int // of the item, carried afterwards
+		wantStock []string
+		wantErr   error
+	}{
+		{name: "one", gold: 20, buy: "Potion", quantity: 1, wantGold: 8, wantCount: 1, wantStock: []string{"Potion x2", "Arrow x20"}},
+		{name: "the whole stock", gold: 40, buy: "Potion", quantity: 3, wantGold: 4, wantCount: 3, wantStock: []string{"Arrow x20"}},
+		{name: "exactly the money", gold: 24, buy: "Potion", quantity: 2, wantGold: 0, wantCount: 2, wantStock: []string{"Potion x1", "Arrow x20"}},
+		{name: "not enough gold", gold: 23, buy: "Potion", quantity: 2, wantGold: 23, wantStock: []string{"Potion x3", "Arrow x20"}, wantErr: ErrNotEnoughGold},
+		{name: "out of stock", gold: 100, buy: "Potion", quantity: 4, wantGold: 100, wantStock: []string{"Potion x3", "Arrow x20"}, wantErr: ErrOutOfStock},
+		{name: "too heavy", gold: 100, capacity: 1, buy: "Potion", quantity: 3, wantGold: 100, wantStock: []string{"Potion x3", "Arrow x20"}, wantErr: ErrTooHeavy},
+		{name: "not for sale", gold: 100, buy: "Sword", quantity: 1, wantGold: 100, wantStock: []string{"Potion x3", "Arrow x20"}, wantErr: errAny},
+		{name: "none", gold: 100, buy: "Potion", quantity: 0, wantGold: 100, wantStock: []string{"Potion x3", "Arrow x20"}, wantErr: errAny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shop := newTestShop()
+			p := newTestPlayer()
+			p.Gold = tt.gold
+			if tt.capacity > 0 {
+				p.Capacity = tt.capacity
+			}
+
+			err := p.Buy(shop, tt.buy, tt.quantity)
+			switch {
+			case tt.wantErr == errAny && err == nil:
+				t.Fatal("want an error")
+			case tt.wantErr != errAny && !errors.Is(err, tt.wantErr):
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if p.Gold != tt.wantGold || p.Count(tt.buy) != tt.wantCount {
+				t.Errorf("%d gold and %d %s, want %d gold and %d", p.Gold, p.Count(tt.buy), tt.buy, tt.wantGold, tt.wantCount)
+			}
+			if got := stacks(shop.Stock); !slices.Equal(got, tt.wantStock) {
+				t.Errorf("stock = %v, want %v", got, tt.wantStock)
+			}
+		})
+	}
+}
+
+func TestSell(t *testing.T) {
+	tests := []struct {
+		name      string
+		items     []Item
+		sell      string
+		quantity  int
+		wantGold  int
+		wantStock []string
+		wantErr   error
+	}{
+		// Sword: worth 15, sold for 7; potions worth 8, sold for 4
+		{name: "one", items: []Item{sword}, sell: "Sword", quantity: 1, wantGold: 7, wantStock: []string{"Potion x3", "Arrow x20", "Sword x1"}},
+		{name: "onto the stock", items: []Item{withQuantity(potion, 2)}, sell: "Potion", quantity: 2, wantGold: 8, wantStock: []string{"Potion x5", "Arrow x20"}},
+		{name: "not carried", items: []Item{sword}, sell: "Potion", quantity: 1, wantStock: []string{"Potion x3", "Arrow x20"}, wantErr: ErrNotCarried},
+		{name: "more than carried", items: []Item{withQuantity(potion, 2)}, sell: "Potion", quantity: 3, wantStock: []string{"Potion x3", "Arrow x20"}, wantErr: ErrNotCarried},
+		{name: "worthless", items: []Item{withQuantity(arrows, 5)}, sell: "Arrow", quantity: 5, wantStock: []string{"Potion x3", "Arrow x20"}, wantErr: errAny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shop := newTestShop()
+			p := newTestPlayer(slices.Clone(tt.items)...)
+
+			err := p.Sell(shop, tt.sell, tt.quantity)
+			switch {
+			case tt.wantErr == errAny && err == nil:
+				t.Fatal("want an error")
+			case tt.wantErr != errAny && !errors.Is(err, tt.wantErr):
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if p.Gold != tt.wantGold {
+				t.Errorf("gold = %d, want %d", p.Gold, tt.wantGold)
+			}
+			if got := stacks(shop.Stock); !slices.Equal(got, tt.wantStock) {
+				t.Errorf("stock = %v, want %v", got, tt.wantStock)
+			}
+		})
+	}
+}
+
+func TestSellThenBuyBack(t *testing.T) {
+	shop := newTestShop()
+	p := newTestPlayer(sword)
+	p.UseItem("Sword")
+
+	if err := p.Sell(shop, "Sword", 1); err != nil {
+		t.Fatal(err)
+	}
+	if p.Equipment[WeaponSlot] != "" {
+		t.Errorf("still wielding the sold %s", p.Equipment[WeaponSlot])
+	}
+	// The shop sells it back at its full value
+	if price, ok := shop.Price("Sword"); !ok || price != sword.Value {
+		t.Errorf("price of the sword = %d, %v; want %d", price, ok, sword.Value)
+	}
+	if err := p.Buy(shop, "Sword", 1); !errors.Is(err, ErrNotEnoughGold) {
+		t.Errorf("buying it back for less: err = %v, want ErrNotEnoughGold", err)
+	}
+}
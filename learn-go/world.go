@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// WorldConfig sets how fast the world moves and how often things happen in it
+type WorldConfig struct {
+	Tick         time.Duration // one beat of the world clock
+	PoisonDamage int           // every tick, while poisoned
+	RegenEvery   int           // ticks between two regenerations
+	RegenAmount  int
+	SpawnEvery   int      // ticks between two monsters, 0 for none
+	Spawns       []string // the monsters that come, in turn
+}
+
+// WorldEvent is something the world does on its own, sent by RunWorld
+type WorldEvent any
+
+type (
+	PoisonTick   struct{ Damage int }
+	RegenTick    struct{ Amount int }
+	MonsterSpawn struct{ Monster string }
+)
+
+// RunWorld is the world clock. It sends what happens on every tick to
+// events, until ctx is canceled; then it closes events and returns.
+//
+// It only decides when things happen and never touches the player or the
+// room: those belong to the goroutine reading events, which applies each
+// one with World.Apply. So nothing is shared and nothing needs a lock.
+func RunWorld(ctx context.Context, cfg WorldConfig, events chan<- WorldEvent) {
+	defer close(events)
+	ticker := time.NewTicker(cfg.Tick)
+	defer ticker.Stop()
+
+	for tick := 1; ; tick++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		due := []WorldEvent{PoisonTick{Damage: cfg.PoisonDamage}}
+		if cfg.RegenEvery > 0 && tick%cfg.RegenEvery == 0 {
+			due = append(due, RegenTick{Amount: cfg.RegenAmount})
+		}
+		if cfg.SpawnEvery > 0 && len(cfg.Spawns) > 0 && tick%cfg.SpawnEvery == 0 {
+			n := tick/cfg.SpawnEvery - 1
+			due = append(due, MonsterSpawn{Monster: cfg.Spawns[n%len(cfg.Spawns)]})
+		}
+
+		// Sending waits for the reader, but never past shutdown
+		for _, e := range due {
+			select {
+			case events <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// World is what the world's events act on
+type World struct {
+	Player *Player
+	Room   *Room
+}
+
+// Apply carries out one world event, publishing what it did on the
+// player's event bus. The dead are left alone.
+func (w *World) Apply(e WorldEvent) {
+	p := w.Player
+	if p.HP <= 0 {
+		return
+	}
+
+	switch e := e.(type) {
+	case PoisonTick:
+		if p.Poisoned == 0 {
+			return
+		}
+		p.Poisoned--
+		p.HP = max(0, p.HP-e.Damage)
+		p.Events.Publish(PlayerDamaged{By: "poison", Amount: e.Damage, HP: p.HP})
+		if p.HP == 0 {
+			p.Events.Publish(PlayerKilled{By: "poison"})
+		}
+
+	case RegenTick:
+		// Wounds only heal while nothing is around to attack
+		if w.Room.Monster != nil || p.HP >= p.MaxHP {
+			return
+		}
+		healed := min(e.Amount, p.MaxHP-p.HP)
+		p.HP += healed
+		p.Events.Publish(PlayerHealed{Amount: healed, HP: p.HP})
+
+	case MonsterSpawn:
+		if w.Room.Monster != nil {
+			return
+		}
+		if w.Room.Monster = NewMonster(e.Monster); w.Room.Monster != nil {
+			p.Events.Publish(MonsterSpawned{Monster: e.Monster})
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRunWorld(t *testing.T) {
+	cfg := WorldConfig{
+		Tick:         time.Millisecond,
+		PoisonDamage: 1,
+		RegenEvery:   2,
+		RegenAmount:  5,
+		SpawnEvery:   3,
+		Spawns:       []string{"Rat", "Spider"},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan WorldEvent)
+	go RunWorld(ctx, cfg, events)
+
+	var got []WorldEvent
+	for range 10 {
+		got = append(got, <-events)
+	}
+	poison, regen := PoisonTick{Damage: 1}, RegenTick{Amount: 5}
+	want := []WorldEvent{
+		poison,        // tick 1
+		poison, regen, // tick 2
+		poison, MonsterSpawn{Monster: "Rat"}, // tick 3
+		poison, regen, // tick 4
+		poison,        // tick 5
+		poison, regen, // tick 6, then the next spawn
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if e := <-events; e != (MonsterSpawn{Monster: "Spider"}) {
+		t.Errorf("the second spawn = %v, want the Spider", e)
+	}
+
+	// Canceling stops the clock even while it waits to send, and closes
+	// the channel
+	cancel()
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatal("the world did not stop after cancel")
+		}
+	}
+}
+
+func TestWorldApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		hp       int
+		poisoned int
+		monster  string // already in the room
+		event    WorldEvent
+		wantHP   int
+		wantPois int
+		wantMon  string
+		want     []string // published events
+	}{
+		{name: "poison hurts", hp: 50, poisoned: 2, event: PoisonTick{Damage: 3}, wantHP: 47, wantPois: 1, want: []string{"main.PlayerDamaged"}},
+		{name: "no poison", hp: 50, event: PoisonTick{Damage: 3}, wantHP: 50},
+		{name: "poison kills", hp: 2, poisoned: 4, event: PoisonTick{Damage: 3}, wantHP: 0, wantPois: 3, want: []string{"main.PlayerDamaged", "main.PlayerKilled"}},
+		{name: "regen", hp: 50, event: RegenTick{Amount: 5}, wantHP: 55, want: []string{"main.PlayerHealed"}},
+		{name: "regen up to max", hp: 98, event: RegenTick{Amount: 5}, wantHP: 100, want: []string{"main.PlayerHealed"}},
+		{name: "no regen at max", hp: 100, event: RegenTick{Amount: 5}, wantHP: 100},
+		{name: "no regen near a monster", hp: 50, monster: "Rat", event: RegenTick{Amount: 5}, wantHP: 50, wantMon: "Rat"},
+		{name: "spawn", hp: 50, event: MonsterSpawn{Monster: "Spider"}, wantHP: 50, wantMon: "Spider", want: []string{"main.MonsterSpawned"}},
+		{name: "one monster at a time", hp: 50, monster: "Rat", event: MonsterSpawn{Monster: "Spider"}, wantHP: 50, wantMon: "Rat"},
+		{name: "unknown monster", hp: 50, event: MonsterSpawn{Monster: "Dragon"}, wantHP: 50},
+		{name: "the dead stay dead", hp: 0, event: RegenTick{Amount: 5}, wantHP: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewBus()
+			got := record(bus)
+			p := newTestPlayer()
+			p.HP, p.Poisoned, p.Events = tt.hp, tt.poisoned, bus
+			room := &Room{Monster: NewMonster(tt.monster)}
+
+			w := World{Player: p, Room: room}
+			w.Apply(tt.event)
+
+			if p.HP != tt.wantHP || p.Poisoned != tt.wantPois {
+				t.Errorf("HP %d, poisoned %d; want %d and %d", p.HP, p.Poisoned, tt.wantHP, tt.wantPois)
+			}
+			monster := ""
+			if room.Monster != nil {
+				monster = room.Monster.Name
+			}
+			if monster != tt.wantMon {
+				t.Errorf("monster in the room = %q, want %q", monster, tt.wantMon)
+			}
+			if !slices.Equal(*got, tt.want) {
+				t.Errorf("published %v, want %v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoisonousMonster(t *testing.T) {
+	p := newTestPlayer()
+	c := NewCombat(p, NewMonster("Spider"))
+	if err := c.Attack(); err != nil {
+		t.Fatal(err)
+	}
+	if p.Poisoned != bestiary["Spider"].Poison {
+		t.Errorf("poisoned for %d ticks, want %d", p.Poisoned, bestiary["Spider"].Poison)
+	}
+}